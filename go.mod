@@ -1,6 +1,6 @@
 module github.com/maxence-charriere/go-app/v9
 
-go 1.15
+go 1.18
 
 require (
 	github.com/gomarkdown/markdown v0.0.0-20210408062403-ad838ccf8cdd
@@ -8,3 +8,9 @@ require (
 	github.com/stretchr/testify v1.6.1
 	golang.org/x/net v0.0.0-20210415231046-e915ea6b2b7d
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
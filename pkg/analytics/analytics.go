@@ -25,7 +25,7 @@ type Backend interface {
 // Identify links your users, and their actions, to a recognizable userID and
 // traits.
 func Identify(userID string, traits map[string]interface{}) {
-	if app.IsServer || userID == "" {
+	if app.IsServer || userID == "" || !consentGranted() {
 		return
 	}
 
@@ -38,7 +38,7 @@ func Identify(userID string, traits map[string]interface{}) {
 
 // Track record actions your users perform.
 func Track(event string, properties map[string]interface{}) {
-	if app.IsServer || event == "" {
+	if app.IsServer || event == "" || !consentGranted() {
 		return
 	}
 
@@ -55,7 +55,7 @@ func Track(event string, properties map[string]interface{}) {
 // The following properties are automatically set: path, referrer, search, title
 // and url.
 func Page(name string, properties map[string]interface{}) {
-	if app.IsServer {
+	if app.IsServer || !consentGranted() {
 		return
 	}
 
@@ -85,10 +85,42 @@ func Add(b Backend) {
 	backends = append(backends, b)
 }
 
+// RequireConsent sets the consent category that must be granted by the user
+// for analytics to be sent. eg:
+//  analytics.RequireConsent(app.ConsentAnalytics)
+//
+// Analytics are sent regardless of the user consent when no category is
+// required.
+func RequireConsent(category string) {
+	consentCategory = category
+}
+
+// AutoTrack registers app action handlers that record a page view after each
+// navigation and send the events recorded with app.Context.Track to the
+// backends.
+//
+// It must be called before app.RunWhenOnBrowser.
+func AutoTrack() {
+	app.Handle(app.NavAction, func(ctx app.Context, a app.Action) {
+		Page("", nil)
+	})
+
+	app.Handle(app.TrackAction, func(ctx app.Context, a app.Action) {
+		if e, ok := a.Value.(app.TrackEvent); ok {
+			Track(e.Name, e.Properties)
+		}
+	})
+}
+
 var (
-	backends []Backend
+	backends        []Backend
+	consentCategory string
 )
 
+func consentGranted() bool {
+	return consentCategory == "" || app.ConsentGranted(consentCategory)
+}
+
 func sanitizeValue(v interface{}) interface{} {
 	switch v := v.(type) {
 	case app.Value,
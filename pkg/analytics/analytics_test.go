@@ -1,6 +1,12 @@
 package analytics
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
 
 func TestAnalytics(t *testing.T) {
 	testingProps := func() map[string]interface{} {
@@ -23,6 +29,14 @@ func TestAnalytics(t *testing.T) {
 			name:    "google analytics",
 			backend: NewGoogleAnalytics(),
 		},
+		{
+			name:    "plausible",
+			backend: NewPlausible(),
+		},
+		{
+			name:    "http",
+			backend: NewHTTP(HTTPConfig{Endpoint: "/analytics"}),
+		},
 	}
 
 	for _, p := range providers {
@@ -55,3 +69,58 @@ func TestAnalytics(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPBackend(t *testing.T) {
+	var sent [][]HTTPEvent
+	var unloads []bool
+
+	b := NewHTTP(HTTPConfig{
+		Endpoint:      "/analytics",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	}).(*httpBackend)
+	b.send = func(endpoint string, body []byte, unload bool) {
+		require.Equal(t, "/analytics", endpoint)
+
+		var events []HTTPEvent
+		err := json.Unmarshal(body, &events)
+		require.NoError(t, err)
+		sent = append(sent, events)
+		unloads = append(unloads, unload)
+	}
+
+	t.Run("events are buffered", func(t *testing.T) {
+		b.Track("signup", map[string]interface{}{"plan": "pro"})
+		require.Empty(t, sent)
+		require.NotNil(t, b.timer)
+	})
+
+	t.Run("full batch is sent", func(t *testing.T) {
+		b.Page("Home", nil)
+		require.Len(t, sent, 1)
+		require.Len(t, sent[0], 2)
+		require.Equal(t, "track", sent[0][0].Type)
+		require.Equal(t, "signup", sent[0][0].Name)
+		require.Equal(t, "pro", sent[0][0].Properties["plan"])
+		require.Equal(t, "page", sent[0][1].Type)
+		require.False(t, unloads[0])
+		require.Nil(t, b.timer)
+	})
+
+	t.Run("remaining events are sent on unload", func(t *testing.T) {
+		b.Identify("Maxoo", nil)
+		b.mutex.Lock()
+		b.flush(true)
+		b.mutex.Unlock()
+		require.Len(t, sent, 2)
+		require.Equal(t, "identify", sent[1][0].Type)
+		require.True(t, unloads[1])
+	})
+
+	t.Run("empty buffer is not sent", func(t *testing.T) {
+		b.mutex.Lock()
+		b.flush(true)
+		b.mutex.Unlock()
+		require.Len(t, sent, 2)
+	})
+}
@@ -0,0 +1,181 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// HTTPConfig describes the configuration of a backend that sends analytics to
+// a custom HTTP endpoint.
+//
+// Events are sent in batches, as a JSON array of HTTPEvent, with a POST
+// request. Remaining events are sent with navigator.sendBeacon when the page
+// is hidden or unloaded.
+type HTTPConfig struct {
+	// The URL where events are sent.
+	Endpoint string
+
+	// The maximum number of events sent in a single request.
+	//
+	// Default: 20.
+	BatchSize int
+
+	// The maximum duration an event is buffered before being sent.
+	//
+	// Default: 5s.
+	FlushInterval time.Duration
+}
+
+// HTTPEvent represents an event sent by the HTTP backend.
+type HTTPEvent struct {
+	// The event type: "identify", "track" or "page".
+	Type string `json:"type"`
+
+	// The event name. It is the user ID for identify events.
+	Name string `json:"name"`
+
+	// The event properties.
+	Properties map[string]interface{} `json:"properties,omitempty"`
+
+	// The time when the event occurred.
+	Time time.Time `json:"time"`
+}
+
+// NewHTTP creates a backend that sends analytics to a custom HTTP endpoint.
+func NewHTTP(c HTTPConfig) Backend {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second * 5
+	}
+
+	b := &httpBackend{
+		HTTPConfig: c,
+		send:       sendHTTPEvents,
+	}
+	if !app.IsServer {
+		b.flushOnHide()
+	}
+	return b
+}
+
+type httpBackend struct {
+	HTTPConfig
+
+	mutex  sync.Mutex
+	events []HTTPEvent
+	timer  *time.Timer
+	send   func(endpoint string, body []byte, unload bool)
+}
+
+func (b *httpBackend) Identify(userID string, traits map[string]interface{}) {
+	b.push(HTTPEvent{
+		Type:       "identify",
+		Name:       userID,
+		Properties: traits,
+	})
+}
+
+func (b *httpBackend) Track(event string, properties map[string]interface{}) {
+	b.push(HTTPEvent{
+		Type:       "track",
+		Name:       event,
+		Properties: properties,
+	})
+}
+
+func (b *httpBackend) Page(name string, properties map[string]interface{}) {
+	b.push(HTTPEvent{
+		Type:       "page",
+		Name:       name,
+		Properties: properties,
+	})
+}
+
+func (b *httpBackend) push(e HTTPEvent) {
+	e.Time = time.Now().UTC()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.events = append(b.events, e)
+	if len(b.events) >= b.BatchSize {
+		b.flush(false)
+		return
+	}
+
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.FlushInterval, func() {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			b.flush(false)
+		})
+	}
+}
+
+func (b *httpBackend) flush(unload bool) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.events) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(b.events)
+	b.events = nil
+	if err != nil {
+		app.Log(errors.New("encoding analytics events failed").Wrap(err))
+		return
+	}
+	b.send(b.Endpoint, body, unload)
+}
+
+// flushOnHide sends the buffered events when the page is hidden or unloaded.
+// The JavaScript listeners call Go synchronously, which is required since
+// the page can be discarded as soon as they return.
+func (b *httpBackend) flushOnHide() {
+	flush := app.FuncOf(func(this app.Value, args []app.Value) interface{} {
+		if args[0].Get("type").String() == "visibilitychange" &&
+			!app.Window().Get("document").Get("hidden").Bool() {
+			return nil
+		}
+
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		b.flush(true)
+		return nil
+	})
+
+	app.Window().Get("document").Call("addEventListener", "visibilitychange", flush)
+	app.Window().Call("addEventListener", "pagehide", flush)
+}
+
+func sendHTTPEvents(endpoint string, body []byte, unload bool) {
+	beacon := app.Window().Get("navigator").Get("sendBeacon")
+	if beacon.Truthy() {
+		app.Window().Get("navigator").Call("sendBeacon", endpoint, string(body))
+		return
+	}
+	if unload {
+		return
+	}
+
+	go func() {
+		res, err := http.Post(endpoint, "application/json", bytes.NewReader(body))
+		if err != nil {
+			app.Log(errors.New("sending analytics events failed").
+				Tag("endpoint", endpoint).
+				Wrap(err))
+			return
+		}
+		res.Body.Close()
+	}()
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// PlausibleHeader returns the header to use in the app.Handler.RawHeader field
+// to initialize Plausible for the given domain.
+//
+// Automatic page views are disabled since they are recorded with Page.
+func PlausibleHeader(domain string) string {
+	return fmt.Sprintf(`<!-- Plausible Analytics -->
+	<script defer data-domain="%s" data-auto-pageviews="false" src="https://plausible.io/js/script.manual.js"></script>
+	<script>
+	  window.plausible = window.plausible || function() { (window.plausible.q = window.plausible.q || []).push(arguments) };
+	</script>`, domain)
+}
+
+// NewPlausible creates a backend that sends analytics to Plausible.
+//
+// Plausible does not identify users. Identify is a noop.
+func NewPlausible() Backend {
+	return plausible{}
+}
+
+type plausible struct{}
+
+func (p plausible) Identify(userID string, traits map[string]interface{}) {
+}
+
+func (p plausible) Track(event string, properties map[string]interface{}) {
+	p.plausible(event, map[string]interface{}{
+		"props": properties,
+	})
+}
+
+func (p plausible) Page(name string, properties map[string]interface{}) {
+	p.plausible("pageview", map[string]interface{}{
+		"u": properties["url"],
+	})
+}
+
+func (p plausible) plausible(args ...interface{}) {
+	plausible := app.Window().Get("plausible")
+	if !plausible.Truthy() {
+		return
+	}
+	app.Window().Call("plausible", args...)
+}
@@ -0,0 +1,285 @@
+package app
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	accessibilityAuditInterval = 2 * time.Second
+
+	// The minimum contrast ratios between a text and its background, as
+	// defined by the WCAG 2.1 AA success criterion 1.4.3.
+	minTextContrast      = 4.5
+	minLargeTextContrast = 3
+)
+
+// accessibilityIssue describes an accessibility problem found in a mounted
+// tree.
+type accessibilityIssue struct {
+	// The path of the faulty node, made of the component types and element
+	// tags that lead to it. Eg: "*main.page > form > input#email".
+	Path string
+
+	// The rule that is not respected.
+	Rule string
+
+	// A description of the problem.
+	Detail string
+}
+
+// textContrast returns the contrast ratio of the text of an element with its
+// background, and the minimum ratio required for its size. It returns false
+// when the contrast can't be computed.
+type textContrast func(n UI) (ratio, min float64, ok bool)
+
+// auditAccessibility reports the nodes of the given tree that have missing
+// alternative texts, unlabeled form controls, skipped heading levels or an
+// insufficient text contrast.
+func auditAccessibility(root UI, contrast textContrast) []accessibilityIssue {
+	a := accessibilityAuditor{
+		contrast: contrast,
+		labelFor: make(map[string]bool),
+	}
+	a.collectLabels(root)
+	a.audit(root, "", false)
+	return a.issues
+}
+
+type accessibilityAuditor struct {
+	contrast     textContrast
+	labelFor     map[string]bool
+	headingLevel int
+	issues       []accessibilityIssue
+}
+
+func (a *accessibilityAuditor) collectLabels(n UI) {
+	if n.Kind() == HTML && n.name() == "label" {
+		if id := n.attributes()["for"]; id != "" {
+			a.labelFor[id] = true
+		}
+	}
+	for _, c := range n.children() {
+		a.collectLabels(c)
+	}
+}
+
+func (a *accessibilityAuditor) audit(n UI, path string, inLabel bool) {
+	switch n.Kind() {
+	case Component:
+		path = joinNodePath(path, reflect.TypeOf(n).String())
+
+	case HTML:
+		path = joinNodePath(path, nodePathName(n))
+		a.auditElem(n, path, inLabel)
+		inLabel = inLabel || n.name() == "label"
+
+	default:
+		return
+	}
+
+	for _, c := range n.children() {
+		a.audit(c, path, inLabel)
+	}
+}
+
+func (a *accessibilityAuditor) auditElem(n UI, path string, inLabel bool) {
+	tag := n.name()
+	attrs := n.attributes()
+
+	switch tag {
+	case "img":
+		if _, ok := attrs["alt"]; !ok && attrs["role"] != "presentation" {
+			a.report(path, "image-alt", "image has no alt text")
+		}
+
+	case "input", "select", "textarea":
+		switch attrs["type"] {
+		case "hidden", "submit", "reset", "button":
+			return
+
+		case "image":
+			if attrs["alt"] == "" {
+				a.report(path, "image-alt", "image button has no alt text")
+			}
+			return
+		}
+
+		if !inLabel && !a.labelFor[attrs["id"]] &&
+			attrs["aria-label"] == "" &&
+			attrs["aria-labelledby"] == "" &&
+			attrs["title"] == "" {
+			a.report(path, "label", tag+" has no label")
+		}
+
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(tag[1] - '0')
+		if a.headingLevel != 0 && level > a.headingLevel+1 {
+			a.report(path, "heading-order", "heading level "+tag+" follows h"+strconv.Itoa(a.headingLevel))
+		}
+		a.headingLevel = level
+	}
+
+	if a.contrast == nil || !hasTextChild(n) {
+		return
+	}
+	if ratio, min, ok := a.contrast(n); ok && ratio < min {
+		a.report(path, "color-contrast", "text contrast ratio is "+
+			strconv.FormatFloat(ratio, 'f', 2, 64)+
+			" instead of at least "+
+			strconv.FormatFloat(min, 'f', 1, 64))
+	}
+}
+
+func (a *accessibilityAuditor) report(path, rule, detail string) {
+	a.issues = append(a.issues, accessibilityIssue{
+		Path:   path,
+		Rule:   rule,
+		Detail: detail,
+	})
+}
+
+func nodePathName(n UI) string {
+	if id := n.attributes()["id"]; id != "" {
+		return n.name() + "#" + id
+	}
+	return n.name()
+}
+
+func joinNodePath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + " > " + name
+}
+
+func hasTextChild(n UI) bool {
+	for _, c := range n.children() {
+		if t, ok := c.(*text); ok && strings.TrimSpace(t.value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// computedTextContrast returns the contrast ratio between the computed color
+// of a mounted element and the first opaque background color of its
+// ancestors.
+func computedTextContrast(n UI) (float64, float64, bool) {
+	if IsServer || !n.Mounted() {
+		return 0, 0, false
+	}
+
+	style := Window().Call("getComputedStyle", n.JSValue())
+	fg, ok := parseCSSColor(style.Get("color").String())
+	if !ok {
+		return 0, 0, false
+	}
+
+	bg := [4]float64{255, 255, 255, 1}
+	for el := n.JSValue(); el.Truthy(); el = el.Get("parentElement") {
+		c, ok := parseCSSColor(Window().Call("getComputedStyle", el).Get("backgroundColor").String())
+		if ok && c[3] > 0 {
+			bg = c
+			break
+		}
+	}
+
+	size, _ := strconv.ParseFloat(strings.TrimSuffix(style.Get("fontSize").String(), "px"), 64)
+	weight, _ := strconv.Atoi(style.Get("fontWeight").String())
+	min := float64(minTextContrast)
+	if size >= 24 || size >= 18.66 && weight >= 700 {
+		min = minLargeTextContrast
+	}
+	return contrastRatio(fg, bg), min, true
+}
+
+// parseCSSColor parses a color serialized as "rgb(r, g, b)" or
+// "rgba(r, g, b, a)", which is the format of computed styles.
+func parseCSSColor(s string) ([4]float64, bool) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexByte(s, '(')
+	if i < 0 || !strings.HasSuffix(s, ")") {
+		return [4]float64{}, false
+	}
+	switch s[:i] {
+	case "rgb", "rgba":
+	default:
+		return [4]float64{}, false
+	}
+
+	parts := strings.FieldsFunc(s[i+1:len(s)-1], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/'
+	})
+	if len(parts) != 3 && len(parts) != 4 {
+		return [4]float64{}, false
+	}
+
+	c := [4]float64{0, 0, 0, 1}
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return [4]float64{}, false
+		}
+		c[i] = v
+	}
+	return c, true
+}
+
+// contrastRatio returns the WCAG contrast ratio of a foreground color over an
+// opaque background color. A translucent foreground is blended with the
+// background.
+func contrastRatio(fg, bg [4]float64) float64 {
+	for i := 0; i < 3; i++ {
+		fg[i] = fg[i]*fg[3] + bg[i]*(1-fg[3])
+	}
+
+	l1 := relativeLuminance(fg)
+	l2 := relativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+func relativeLuminance(c [4]float64) float64 {
+	channel := func(v float64) float64 {
+		v /= 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c[0]) + 0.7152*channel(c[1]) + 0.0722*channel(c[2])
+}
+
+// auditAccessibility logs the accessibility issues of the mounted tree that
+// were not already reported. It is a no-op when the tree is not modified since
+// the previous audit.
+func (e *engine) auditAccessibility() {
+	if !e.isAccessibilityModified || e.Body == nil {
+		return
+	}
+	e.isAccessibilityModified = false
+
+	if e.accessibilityIssues == nil {
+		e.accessibilityIssues = make(map[accessibilityIssue]struct{})
+	}
+
+	for _, issue := range auditAccessibility(e.Body, computedTextContrast) {
+		if _, reported := e.accessibilityIssues[issue]; reported {
+			continue
+		}
+		e.accessibilityIssues[issue] = struct{}{}
+
+		Log(errors.New("accessibility issue").
+			Tag("rule", issue.Rule).
+			Tag("path", issue.Path).
+			Tag("detail", issue.Detail))
+	}
+}
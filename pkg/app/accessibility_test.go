@@ -0,0 +1,123 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type accessibilityTestCompo struct {
+	Compo
+}
+
+func (c *accessibilityTestCompo) Render() UI {
+	return Div().Body(
+		H1().Text("Sign up"),
+		Img().Src("/web/logo.png"),
+		Img().Src("/web/divider.png").Alt(""),
+		Form().Body(
+			Label().For("email").Text("Email"),
+			Input().ID("email").Type("email"),
+			Label().Body(
+				Text("Name"),
+				Input().Type("text"),
+			),
+			Input().Type("password"),
+			Input().Type("search").Aria("label", "Search"),
+			Input().Type("hidden"),
+			Input().Type("image").Src("/web/go.png"),
+			Textarea(),
+			H3().Text("Terms"),
+			P().Class("faded").Text("Read them"),
+		),
+		H2().Text("Help"),
+	)
+}
+
+func TestAuditAccessibility(t *testing.T) {
+	compo := &accessibilityTestCompo{}
+	d := NewServerTester(compo)
+	defer d.Close()
+
+	issues := auditAccessibility(compo, func(n UI) (float64, float64, bool) {
+		if n.attributes()["class"] == "faded" {
+			return 2.5, minTextContrast, true
+		}
+		return 0, 0, false
+	})
+
+	require.Equal(t, []accessibilityIssue{
+		{
+			Path:   "*app.accessibilityTestCompo > div > img",
+			Rule:   "image-alt",
+			Detail: "image has no alt text",
+		},
+		{
+			Path:   "*app.accessibilityTestCompo > div > form > input",
+			Rule:   "label",
+			Detail: "input has no label",
+		},
+		{
+			Path:   "*app.accessibilityTestCompo > div > form > input",
+			Rule:   "image-alt",
+			Detail: "image button has no alt text",
+		},
+		{
+			Path:   "*app.accessibilityTestCompo > div > form > textarea",
+			Rule:   "label",
+			Detail: "textarea has no label",
+		},
+		{
+			Path:   "*app.accessibilityTestCompo > div > form > h3",
+			Rule:   "heading-order",
+			Detail: "heading level h3 follows h1",
+		},
+		{
+			Path:   "*app.accessibilityTestCompo > div > form > p",
+			Rule:   "color-contrast",
+			Detail: "text contrast ratio is 2.50 instead of at least 4.5",
+		},
+	}, issues)
+}
+
+func TestParseCSSColor(t *testing.T) {
+	utests := []struct {
+		color    string
+		expected [4]float64
+		ok       bool
+	}{
+		{color: "rgb(255, 0, 10)", expected: [4]float64{255, 0, 10, 1}, ok: true},
+		{color: "rgba(0, 0, 0, 0.5)", expected: [4]float64{0, 0, 0, 0.5}, ok: true},
+		{color: "rgb(1 2 3 / 0)", expected: [4]float64{1, 2, 3, 0}, ok: true},
+		{color: "color(srgb 1 0 0)"},
+		{color: "rgb(1, 2)"},
+		{color: "rgb(a, b, c)"},
+		{color: "red"},
+		{color: ""},
+	}
+
+	for _, u := range utests {
+		t.Run(u.color, func(t *testing.T) {
+			c, ok := parseCSSColor(u.color)
+			require.Equal(t, u.ok, ok)
+			require.Equal(t, u.expected, c)
+		})
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	white := [4]float64{255, 255, 255, 1}
+	black := [4]float64{0, 0, 0, 1}
+
+	require.InDelta(t, 21, contrastRatio(black, white), 0.01)
+	require.InDelta(t, 21, contrastRatio(white, black), 0.01)
+	require.InDelta(t, 1, contrastRatio(white, white), 0.01)
+	require.InDelta(t, 4.54, contrastRatio([4]float64{118, 118, 118, 1}, white), 0.01)
+	require.InDelta(t, 1, contrastRatio([4]float64{0, 0, 0, 0}, white), 0.01)
+}
+
+func TestEngineAuditAccessibility(t *testing.T) {
+	e := engine{}
+	e.auditAccessibility()
+	require.Nil(t, e.accessibilityIssues)
+}
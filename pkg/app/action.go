@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -24,6 +26,13 @@ type ActionHandler func(Context, Action)
 
 // Handle registers the handler for the given action name. When an action
 // occurs, the handler is executed on its own goroutine.
+//
+// Action names are made of segments separated by dots. A handler can be
+// registered with a pattern where "*" matches exactly one segment and "#"
+// matches zero or more segments. eg:
+//  app.Handle("cart.*", h) // Handles "cart.add" and "cart.remove".
+//  app.Handle("user.#", h) // Handles "user", "user.login" and "user.profile.edit".
+//  app.Handle("#", h)      // Handles all actions.
 func Handle(actionName string, h ActionHandler) {
 	actionHandlers[actionName] = h
 }
@@ -32,18 +41,34 @@ var actionHandlers = make(map[string]ActionHandler)
 
 type actionHandler struct {
 	async    bool
+	durable  bool
 	source   UI
 	function ActionHandler
 }
 
 type actionManager struct {
-	once     sync.Once
-	mutex    sync.Mutex
-	handlers map[string]map[string]actionHandler
+	once      sync.Once
+	mutex     sync.Mutex
+	handlers  map[string]map[string]actionHandler
+	patterns  []string
+	matches   map[string][]string
+	durables  map[string][]string
+	mailboxes map[string]*mailbox
 }
 
 func (m *actionManager) init() {
 	m.handlers = make(map[string]map[string]actionHandler)
+	m.matches = make(map[string][]string)
+	m.durables = make(map[string][]string)
+	m.mailboxes = make(map[string]*mailbox)
+}
+
+func (m *actionManager) enableMailbox(actionName string, mb Mailbox) {
+	m.once.Do(m.init)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.mailboxes[actionName] = newMailbox(mb)
 }
 
 func (m *actionManager) post(a Action) {
@@ -51,23 +76,105 @@ func (m *actionManager) post(a Action) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	handlers := m.handlers[a.Name]
-	for key, h := range handlers {
-		source := h.source
-		if !source.Mounted() {
-			delete(handlers, key)
+	if m.deliver(a) == 0 {
+		if mb, isEnabled := m.mailboxes[a.Name]; isEnabled {
+			mb.push(a)
+		}
+	}
+}
+
+// deliver executes the mounted handlers registered for the given action and
+// returns the number of executed handlers.
+func (m *actionManager) deliver(a Action) int {
+	delivered := 0
+	for _, name := range m.matchingNames(a.Name) {
+		handlers := m.handlers[name]
+		for key, h := range handlers {
+			source := h.source
+			if !source.Mounted() {
+				if !h.durable {
+					delete(handlers, key)
+				}
+				continue
+			}
+
+			ctx := makeContext(source)
+			function := h.function
+
+			if h.async {
+				ctx.Async(func() { function(ctx, a) })
+			} else {
+				ctx.Dispatch(func(ctx Context) { function(ctx, a) })
+			}
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// flushMailboxes delivers the buffered actions which name matches the given
+// action name or pattern.
+func (m *actionManager) flushMailboxes(actionName string) {
+	for name, mb := range m.mailboxes {
+		if len(mb.actions) == 0 {
+			continue
+		}
+		if name != actionName && !(isActionPattern(actionName) && matchAction(actionName, name)) {
 			continue
 		}
 
-		ctx := makeContext(source)
-		function := h.function
+		actions := mb.pop()
+		for i, a := range actions {
+			if m.deliver(a) == 0 {
+				mb.actions = append(mb.actions, actions[i:]...)
+				break
+			}
+		}
+	}
+}
+
+// matchingNames returns the names of the handlers registered for the given
+// action name. When the action name is a pattern, it returns the registered
+// names that match the pattern. Results are cached until a name is registered
+// or removed.
+func (m *actionManager) matchingNames(actionName string) []string {
+	if names, isCached := m.matches[actionName]; isCached {
+		return names
+	}
 
-		if h.async {
-			ctx.Async(func() { function(ctx, a) })
-		} else {
-			ctx.Dispatch(func(ctx Context) { function(ctx, a) })
+	var names []string
+	if isActionPattern(actionName) {
+		for name := range m.handlers {
+			if !isActionPattern(name) && matchAction(actionName, name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	} else {
+		if _, isRegistered := m.handlers[actionName]; isRegistered {
+			names = append(names, actionName)
+		}
+		for _, p := range m.patterns {
+			if matchAction(p, actionName) {
+				names = append(names, p)
+			}
 		}
 	}
+
+	m.matches[actionName] = names
+	return names
+}
+
+func (m *actionManager) resetMatches() {
+	m.patterns = m.patterns[:0]
+	for name := range m.handlers {
+		if isActionPattern(name) {
+			m.patterns = append(m.patterns, name)
+		}
+	}
+	sort.Strings(m.patterns)
+
+	m.matches = make(map[string][]string, len(m.matches))
 }
 
 func (m *actionManager) handle(actionName string, async bool, source UI, h ActionHandler) {
@@ -79,6 +186,7 @@ func (m *actionManager) handle(actionName string, async bool, source UI, h Actio
 	if !isRegistered {
 		handlers = make(map[string]actionHandler)
 		m.handlers[actionName] = handlers
+		m.resetMatches()
 	}
 
 	key := fmt.Sprintf("/%T:%p/%p", source, source, h)
@@ -87,21 +195,123 @@ func (m *actionManager) handle(actionName string, async bool, source UI, h Actio
 		source:   source,
 		function: h,
 	}
+	m.flushMailboxes(actionName)
+}
+
+func (m *actionManager) handleDurable(actionName string, source Composer, h ActionHandler) {
+	m.once.Do(m.init)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	handlers, isRegistered := m.handlers[actionName]
+	if !isRegistered {
+		handlers = make(map[string]actionHandler)
+		m.handlers[actionName] = handlers
+		m.resetMatches()
+	}
+
+	key := durableHandlerKey(source)
+	if _, isRegistered := handlers[key]; !isRegistered {
+		m.durables[key] = append(m.durables[key], actionName)
+	}
+	handlers[key] = actionHandler{
+		durable:  true,
+		source:   source,
+		function: h,
+	}
+	m.flushMailboxes(actionName)
+}
+
+// bind binds the durable handlers registered by a component of the same type
+// as the given component and that are no longer mounted to the given
+// component.
+func (m *actionManager) bind(c Composer) {
+	m.once.Do(m.init)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := durableHandlerKey(c)
+	for _, actionName := range m.durables[key] {
+		handlers := m.handlers[actionName]
+		h, isRegistered := handlers[key]
+		if !isRegistered || h.source.Mounted() {
+			continue
+		}
+		h.source = c
+		handlers[key] = h
+		m.flushMailboxes(actionName)
+	}
+}
+
+func durableHandlerKey(c Composer) string {
+	return fmt.Sprintf("/durable/%T", c)
 }
 
 func (m *actionManager) closeUnusedHandlers() {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	removed := false
 	for actionName, handlers := range m.handlers {
 		for key, h := range handlers {
-			if !h.source.Mounted() {
+			if !h.durable && !h.source.Mounted() {
 				delete(handlers, key)
 			}
 		}
 
 		if len(handlers) == 0 {
 			delete(m.handlers, actionName)
+			removed = true
 		}
 	}
+
+	if removed {
+		m.resetMatches()
+	}
+}
+
+func isActionPattern(name string) bool {
+	for _, s := range strings.Split(name, ".") {
+		if s == "*" || s == "#" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAction reports whether the given action name matches the given
+// pattern.
+func matchAction(pattern, name string) bool {
+	return matchActionSegments(strings.Split(pattern, "."), strings.Split(name, "."))
+}
+
+func matchActionSegments(pattern, name []string) bool {
+	for len(pattern) != 0 {
+		switch pattern[0] {
+		case "#":
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchActionSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case "*":
+			if len(name) == 0 {
+				return false
+			}
+
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
 }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -90,3 +91,112 @@ func TestActionManagerCloseUnusedHandlers(t *testing.T) {
 	m.closeUnusedHandlers()
 	require.Empty(t, m.handlers)
 }
+
+func TestActionManagerHandlePattern(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	m := actionManager{}
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var calls []string
+	handle := func(name string) {
+		m.handle(name, false, h, func(ctx Context, a Action) {
+			calls = append(calls, name+"<"+a.Name)
+		})
+	}
+	handle("cart.add")
+	handle("cart.remove")
+	handle("cart.*")
+	handle("user.#")
+
+	post := func(name string) []string {
+		calls = nil
+		m.post(Action{Name: name})
+		e.Consume()
+		sort.Strings(calls)
+		return calls
+	}
+
+	require.Equal(t, []string{"cart.*<cart.add", "cart.add<cart.add"}, post("cart.add"))
+	require.Empty(t, post("cart.item.add"))
+	require.Equal(t, []string{"user.#<user"}, post("user"))
+	require.Equal(t, []string{"user.#<user.profile.edit"}, post("user.profile.edit"))
+	require.Equal(t, []string{"cart.add<cart.*", "cart.remove<cart.*"}, post("cart.*"))
+
+	handle("#")
+	require.Equal(t, []string{"#<user.login", "user.#<user.login"}, post("user.login"))
+}
+
+func TestMatchAction(t *testing.T) {
+	utests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{pattern: "cart", name: "cart", match: true},
+		{pattern: "cart", name: "user", match: false},
+		{pattern: "cart.*", name: "cart.add", match: true},
+		{pattern: "cart.*", name: "cart", match: false},
+		{pattern: "cart.*", name: "cart.item.add", match: false},
+		{pattern: "*.add", name: "cart.add", match: true},
+		{pattern: "user.#", name: "user", match: true},
+		{pattern: "user.#", name: "user.profile.edit", match: true},
+		{pattern: "user.#", name: "cart.add", match: false},
+		{pattern: "#.edit", name: "user.profile.edit", match: true},
+		{pattern: "#.edit", name: "user.profile", match: false},
+		{pattern: "user.#.edit", name: "user.edit", match: true},
+		{pattern: "#", name: "anything.at.all", match: true},
+	}
+
+	for _, u := range utests {
+		t.Run(u.pattern+" "+u.name, func(t *testing.T) {
+			require.Equal(t, u.match, matchAction(u.pattern, u.name))
+		})
+	}
+}
+
+func TestActionManagerHandleDurable(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var sources []UI
+	makeContext(h).HandleDurable("/test", func(ctx Context, a Action) {
+		sources = append(sources, ctx.Src())
+	})
+	require.Len(t, e.actions.handlers["/test"], 1)
+
+	e.Post(Action{Name: "/test"})
+	e.Wait()
+	e.Consume()
+	require.Equal(t, []UI{h}, sources)
+
+	e.Mount(Div())
+	e.Consume()
+	e.actions.closeUnusedHandlers()
+	require.Len(t, e.actions.handlers["/test"], 1)
+
+	e.Post(Action{Name: "/test"})
+	e.Wait()
+	e.Consume()
+	require.Len(t, sources, 1)
+
+	h2 := &hello{}
+	e.Mount(h2)
+	e.Consume()
+
+	e.Post(Action{Name: "/test"})
+	e.Wait()
+	e.Consume()
+	require.Len(t, sources, 2)
+	require.True(t, sources[1] == h2)
+}
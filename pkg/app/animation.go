@@ -0,0 +1,228 @@
+package app
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Keyframe describes the styles of an element at a given point of an
+// animation.
+type Keyframe struct {
+	// The position of the keyframe in the animation, ranging from 0 to 1.
+	// Keyframes are evenly spaced when all offsets are zero.
+	Offset float64
+
+	// The timing function used from this keyframe to the next one. eg:
+	// "ease-in".
+	Easing string
+
+	// The CSS properties of the keyframe. eg: {"opacity": "0",
+	// "transform": "translateX(-12px)"}.
+	Styles map[string]string
+}
+
+// AnimationOptions describes the timing and the callbacks of an animation.
+type AnimationOptions struct {
+	// The duration of an iteration.
+	Duration time.Duration
+
+	// The delay before the animation starts.
+	Delay time.Duration
+
+	// The timing function of the animation. eg: "ease-in-out".
+	//
+	// Default: "linear".
+	Easing string
+
+	// The number of times the animation is played. It is played indefinitely
+	// when negative.
+	//
+	// Default: 1.
+	Iterations float64
+
+	// Whether the animation runs forwards, backwards or alternates. eg:
+	// "alternate".
+	Direction string
+
+	// Whether the styles of the first and last keyframes are applied before
+	// and after the animation. eg: "forwards".
+	Fill string
+
+	// Whether the animation conveys information and is played even when the
+	// user prefers reduced motion. Other animations are completed immediately
+	// in that case.
+	Essential bool
+
+	// The function called on the UI goroutine when the animation finishes. It
+	// is called immediately when the element can't be animated.
+	OnFinish func(Context)
+
+	// The function called on the UI goroutine when the animation is
+	// canceled.
+	OnCancel func(Context)
+}
+
+// Animation represents an animation started with Context.Animate.
+type Animation struct {
+	value Value
+}
+
+// Play starts or resumes the animation.
+func (a Animation) Play() {
+	a.call("play")
+}
+
+// Pause pauses the animation.
+func (a Animation) Pause() {
+	a.call("pause")
+}
+
+// Reverse reverses the playback direction of the animation.
+func (a Animation) Reverse() {
+	a.call("reverse")
+}
+
+// Finish seeks the animation to its end.
+func (a Animation) Finish() {
+	a.call("finish")
+}
+
+// Cancel stops the animation and removes its effects.
+func (a Animation) Cancel() {
+	a.call("cancel")
+}
+
+func (a Animation) call(method string) {
+	if a.value != nil && a.value.Truthy() {
+		a.value.Call(method)
+	}
+}
+
+func animate(d Dispatcher, src UI, elem UI, keyframes []Keyframe, opts AnimationOptions) Animation {
+	dispatch := func(fn func(Context)) {
+		if fn == nil {
+			return
+		}
+		d.Dispatch(Dispatch{
+			Mode:     Update,
+			Source:   src,
+			Function: fn,
+		})
+	}
+
+	if IsServer || !elem.Mounted() || !elem.JSValue().Get("animate").Truthy() {
+		dispatch(opts.OnFinish)
+		return Animation{}
+	}
+
+	if !opts.Essential && prefersReducedMotion() {
+		opts = reducedMotionOptions(opts)
+	}
+
+	animation := elem.JSValue().Call("animate",
+		jsKeyframes(keyframes),
+		jsAnimationOptions(opts),
+	)
+
+	var onFinish, onCancel Func
+	release := func() {
+		onFinish.Release()
+		onCancel.Release()
+	}
+	onFinish = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		dispatch(opts.OnFinish)
+		return nil
+	})
+	onCancel = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		dispatch(opts.OnCancel)
+		return nil
+	})
+	animation.Set("onfinish", onFinish)
+	animation.Set("oncancel", onCancel)
+
+	return Animation{value: animation}
+}
+
+func jsKeyframes(keyframes []Keyframe) []interface{} {
+	hasOffsets := false
+	for _, k := range keyframes {
+		if k.Offset != 0 {
+			hasOffsets = true
+			break
+		}
+	}
+
+	frames := make([]interface{}, 0, len(keyframes))
+	for _, k := range keyframes {
+		frame := make(map[string]interface{}, len(k.Styles)+2)
+		for p, v := range k.Styles {
+			frame[jsStyleProperty(p)] = v
+		}
+		if hasOffsets {
+			frame["offset"] = k.Offset
+		}
+		if k.Easing != "" {
+			frame["easing"] = k.Easing
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func jsAnimationOptions(opts AnimationOptions) map[string]interface{} {
+	iterations := opts.Iterations
+	switch {
+	case iterations < 0:
+		iterations = math.Inf(1)
+
+	case iterations == 0:
+		iterations = 1
+	}
+
+	o := map[string]interface{}{
+		"duration":   float64(opts.Duration) / float64(time.Millisecond),
+		"delay":      float64(opts.Delay) / float64(time.Millisecond),
+		"iterations": iterations,
+	}
+	if opts.Easing != "" {
+		o["easing"] = opts.Easing
+	}
+	if opts.Direction != "" {
+		o["direction"] = opts.Direction
+	}
+	if opts.Fill != "" {
+		o["fill"] = opts.Fill
+	}
+	return o
+}
+
+// reducedMotionOptions returns the given options modified to complete the
+// animation immediately, without losing the styles applied by the fill mode.
+func reducedMotionOptions(opts AnimationOptions) AnimationOptions {
+	opts.Duration = 0
+	opts.Delay = 0
+	opts.Iterations = 1
+	return opts
+}
+
+// jsStyleProperty converts a CSS property name to its JavaScript keyframe
+// property name. eg: "background-color" becomes "backgroundColor".
+func jsStyleProperty(p string) string {
+	if strings.HasPrefix(p, "--") {
+		return p
+	}
+	if p == "float" {
+		return "cssFloat"
+	}
+
+	parts := strings.Split(p, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
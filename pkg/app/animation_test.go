@@ -0,0 +1,108 @@
+package app
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnimate(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	finished := false
+	a := animate(&e, h, h.root, []Keyframe{
+		{Styles: map[string]string{"opacity": "0"}},
+		{Styles: map[string]string{"opacity": "1"}},
+	}, AnimationOptions{
+		Duration: time.Millisecond * 200,
+		OnFinish: func(Context) { finished = true },
+	})
+	e.Consume()
+	require.True(t, finished)
+
+	a.Play()
+	a.Pause()
+	a.Reverse()
+	a.Finish()
+	a.Cancel()
+}
+
+func TestJSKeyframes(t *testing.T) {
+	t.Run("offsets are omitted when not set", func(t *testing.T) {
+		frames := jsKeyframes([]Keyframe{
+			{Styles: map[string]string{"background-color": "red"}},
+			{Styles: map[string]string{"background-color": "blue"}, Easing: "ease-in"},
+		})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"backgroundColor": "red"},
+			map[string]interface{}{"backgroundColor": "blue", "easing": "ease-in"},
+		}, frames)
+	})
+
+	t.Run("offsets are set when one is defined", func(t *testing.T) {
+		frames := jsKeyframes([]Keyframe{
+			{Styles: map[string]string{"opacity": "0"}},
+			{Offset: 0.3, Styles: map[string]string{"opacity": "1"}},
+		})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"opacity": "0", "offset": float64(0)},
+			map[string]interface{}{"opacity": "1", "offset": 0.3},
+		}, frames)
+	})
+}
+
+func TestJSAnimationOptions(t *testing.T) {
+	require.Equal(t, map[string]interface{}{
+		"duration":   float64(250),
+		"delay":      float64(0),
+		"iterations": float64(1),
+	}, jsAnimationOptions(AnimationOptions{
+		Duration: time.Millisecond * 250,
+	}))
+
+	require.Equal(t, map[string]interface{}{
+		"duration":   float64(100),
+		"delay":      float64(50),
+		"iterations": math.Inf(1),
+		"easing":     "ease-out",
+		"direction":  "alternate",
+		"fill":       "forwards",
+	}, jsAnimationOptions(AnimationOptions{
+		Duration:   time.Millisecond * 100,
+		Delay:      time.Millisecond * 50,
+		Easing:     "ease-out",
+		Iterations: -1,
+		Direction:  "alternate",
+		Fill:       "forwards",
+	}))
+}
+
+func TestJSStyleProperty(t *testing.T) {
+	require.Equal(t, "opacity", jsStyleProperty("opacity"))
+	require.Equal(t, "backgroundColor", jsStyleProperty("background-color"))
+	require.Equal(t, "borderTopLeftRadius", jsStyleProperty("border-top-left-radius"))
+	require.Equal(t, "cssFloat", jsStyleProperty("float"))
+	require.Equal(t, "--accent", jsStyleProperty("--accent"))
+}
+
+func TestReducedMotionOptions(t *testing.T) {
+	require.Equal(t, AnimationOptions{
+		Iterations: 1,
+		Easing:     "ease-out",
+		Fill:       "forwards",
+	}, reducedMotionOptions(AnimationOptions{
+		Duration:   time.Millisecond * 100,
+		Delay:      time.Millisecond * 50,
+		Iterations: -1,
+		Easing:     "ease-out",
+		Fill:       "forwards",
+	}))
+}
@@ -33,16 +33,27 @@ const (
 	IsServer = runtime.GOARCH != "wasm" || runtime.GOOS != "js"
 
 	orientationChangeDelay = time.Millisecond * 500
-	engineUpdateRate       = 120
 	resizeInterval         = time.Millisecond * 250
 )
 
 var (
+	engineUpdateRate   = 120
+	engineUpdateBudget = time.Millisecond * 8
+
 	rootPrefix         string
 	isInternalURL      func(string) bool
 	appUpdateAvailable bool
+	maintenanceMode    bool
+	isNavigatedOnce    bool
 	lastURLVisited     *url.URL
 	resizeTimer        *time.Timer
+
+	displayModes = []string{
+		"fullscreen",
+		"standalone",
+		"minimal-ui",
+		"window-controls-overlay",
+	}
 )
 
 // Getenv retrieves the value of the environment variable named by the key. It
@@ -72,6 +83,25 @@ func KeepBodyClean() (close func()) {
 	}
 }
 
+// SetUpdateRate sets the number of times per second the engine performs
+// component updates. It must be called before RunWhenOnBrowser.
+//
+// Default: 120.
+func SetUpdateRate(rate int) {
+	engineUpdateRate = rate
+}
+
+// SetUpdateBudget sets the maximum time the engine spends updating components
+// during a single update cycle. Once the budget is spent, the remaining
+// updates are performed in the next cycles, which gives the browser a chance
+// to handle user input in between. A budget of zero disables the limit. It
+// must be called before RunWhenOnBrowser.
+//
+// Default: 8ms.
+func SetUpdateBudget(d time.Duration) {
+	engineUpdateBudget = d
+}
+
 // Window returns the JavaScript "window" object.
 func Window() BrowserWindow {
 	return window
@@ -104,32 +134,59 @@ func RunWhenOnBrowser() {
 
 	defer func() {
 		err := recover()
+		reportPanic(err)
 		displayLoadError(err)
 		panic(err)
 	}()
 
 	rootPrefix = Getenv("GOAPP_ROOT_PREFIX")
+	loadAppLocales()
 	isInternalURL = internalURLChecker()
-	staticResourcesResolver := newClientStaticResourceResolver(Getenv("GOAPP_STATIC_RESOURCES_URL"))
+	staticResourcesResolver := newClientStaticResourceResolver(
+		Getenv("GOAPP_STATIC_RESOURCES_URL"),
+		clientResourceFingerprints(),
+	)
+	clientStaticResourceResolver = staticResourcesResolver
 
 	disp := engine{
 		UpdateRate:             engineUpdateRate,
+		UpdateBudget:           engineUpdateBudget,
 		LocalStorage:           newJSStorage("localStorage"),
 		SessionStorage:         newJSStorage("sessionStorage"),
 		ResolveStaticResources: staticResourcesResolver,
 		ActionHandlers:         actionHandlers,
+		ActionMailboxes:        actionMailboxes,
+		Tracer:                 clientTracer,
+		TraceContext:           ContextWithTraceParent(context.Background(), serverTraceParent()),
+		SuspendPolicy:          engineSuspendPolicy,
+		AuditSink:              clientAuditSink,
+		Locales:                appLocales,
+		TimeZone:               clientTimeZone(),
 	}
 	disp.Page = browserPage{dispatcher: &disp}
 	disp.Body = newClientBody(&disp)
 	disp.init()
 	defer disp.Close()
+	loadConsent(&disp)
+	disp.initCrashRecovery()
+	loadPageClaims(&disp)
+	loadPageLaunch()
+	featureFlags.start(&disp)
+	startMaintenanceEvents(&disp)
+	disp.Experiments = newClientExperimentAssignments(featureFlags.anonymousKey())
 
 	window.setBody(disp.Body)
+	disp.startE2EBridge()
+	disp.startGlobalErrorCapture()
 
 	onAchorClick := FuncOf(onAchorClick(&disp))
 	defer onAchorClick.Release()
 	Window().Set("onclick", onAchorClick)
 
+	onAnchorHover := FuncOf(onAnchorHover)
+	defer onAnchorHover.Release()
+	Window().Call("addEventListener", "mouseover", onAnchorHover)
+
 	onPopState := FuncOf(onPopState(&disp))
 	defer onPopState.Release()
 	Window().Set("onpopstate", onPopState)
@@ -142,16 +199,46 @@ func RunWhenOnBrowser() {
 	defer onAppInstallChange.Release()
 	Window().Set("goappOnAppInstallChange", onAppInstallChange)
 
+	closeAppInstalled := Window().AddEventListener("appinstalled", onAppInstalled(&disp))
+	defer closeAppInstalled()
+
+	closeDisplayModeChange := watchDisplayMode(&disp)
+	defer closeDisplayModeChange()
+
 	closeAppResize := Window().AddEventListener("resize", onResize)
 	defer closeAppResize()
 
 	closeAppOrientationChange := Window().AddEventListener("orientationchange", onAppOrientationChange)
 	defer closeAppOrientationChange()
 
+	closeVisibilityChange := Window().AddEventListener("visibilitychange", onVisibilityChange(&disp))
+	defer closeVisibilityChange()
+
 	performNavigate(&disp, Window().URL(), false)
+	disp.restoreUpdateSnapshot()
+	startLaunchQueue(&disp)
+	startBackgroundFetches(&disp)
 	disp.start(context.Background())
 }
 
+func serverTraceParent() string {
+	navigation := Window().
+		Get("performance").
+		Call("getEntriesByType", "navigation").
+		Index(0)
+	if !navigation.Truthy() || !navigation.Get("serverTiming").Truthy() {
+		return ""
+	}
+
+	serverTiming := navigation.Get("serverTiming")
+	for i := 0; i < serverTiming.Length(); i++ {
+		if entry := serverTiming.Index(i); entry.Get("name").String() == "traceparent" {
+			return entry.Get("description").String()
+		}
+	}
+	return ""
+}
+
 func displayLoadError(err interface{}) {
 	loadingLabel := Window().
 		Get("document").
@@ -162,7 +249,7 @@ func displayLoadError(err interface{}) {
 	loadingLabel.setInnerText(fmt.Sprint(err))
 }
 
-func newClientStaticResourceResolver(staticResourceURL string) func(string) string {
+func newClientStaticResourceResolver(staticResourceURL string, fingerprints map[string]string) func(string) string {
 	return func(path string) string {
 		if isRemoteLocation(path) || !isStaticResourcePath(path) {
 			return path
@@ -172,10 +259,16 @@ func newClientStaticResourceResolver(staticResourceURL string) func(string) stri
 		b.WriteString(staticResourceURL)
 		b.WriteByte('/')
 		b.WriteString(strings.TrimPrefix(path, "/"))
-		return b.String()
+		return fingerprintPath(fingerprints, path, b.String())
 	}
 }
 
+func clientResourceFingerprints() map[string]string {
+	var fingerprints map[string]string
+	json.Unmarshal([]byte(Getenv("GOAPP_RESOURCE_FINGERPRINTS")), &fingerprints)
+	return fingerprints
+}
+
 func internalURLChecker() func(string) bool {
 	var urls []string
 	json.Unmarshal([]byte(Getenv("GOAPP_INTERNAL_URLS")), &urls)
@@ -326,6 +419,13 @@ func navigateTo(d Dispatcher, u *url.URL, updateHistory bool) {
 		return
 	}
 
+	if maintenanceMode {
+		Log(errors.New("navigating to URL failed").
+			Tag("url", u).
+			Tag("reason", "server is under maintenance"))
+		return
+	}
+
 	performNavigate(d, u, updateHistory)
 }
 
@@ -338,6 +438,7 @@ func performNavigate(d Dispatcher, u *url.URL, updateHistory bool) {
 	if path == "" {
 		path = "/"
 	}
+	_, path = splitLocalePath(path, appLocales)
 	compo, ok := routes.createComponent(path)
 	if !ok {
 		compo = &notFound{}
@@ -347,23 +448,56 @@ func performNavigate(d Dispatcher, u *url.URL, updateHistory bool) {
 	if !ok {
 		return
 	}
-	disp.Mount(compo)
+	isRouteChange := isNavigatedOnce
+	from := lastURLVisited
+	isRestore := isNavigatedOnce && !updateHistory && disp.isPageKept(u)
+	changePage := func() {
+		applyRouteMeta(disp.currentPage(), path)
+		if isRestore {
+			disp.restorePage(from, u)
+		} else {
+			disp.mountPage(from, compo)
+		}
 
-	if updateHistory {
-		Window().addHistory(u)
-	} else {
-		lastURLVisited = u
+		if updateHistory {
+			Window().addHistory(u)
+		} else {
+			lastURLVisited = u
+		}
+
+		disp.Nav(u)
+		updateRouteLinks(u, path)
+		updateDocumentLocale(pageLocale(u, appLocales))
+		postNavAction(d, u)
+		if isFragmentNavigation(u) {
+			d.Dispatch(Dispatch{
+				Mode: Defer,
+				Function: func(ctx Context) {
+					Window().ScrollToID(u.Fragment)
+				},
+			})
+		} else if isRouteChange && !isRestore {
+			d.Dispatch(Dispatch{
+				Mode: Defer,
+				Function: func(ctx Context) {
+					focusRouteHeading()
+				},
+			})
+		}
 	}
 
-	disp.Nav(u)
-	if isFragmentNavigation(u) {
-		d.Dispatch(Dispatch{
-			Mode: Defer,
-			Function: func(ctx Context) {
-				Window().ScrollToID(u.Fragment)
-			},
-		})
+	if !isNavigatedOnce {
+		isNavigatedOnce = true
+		disp.loadRoute(u, path, changePage)
+		return
+	}
+	if isRestore {
+		startViewTransition(d, changePage)
+		return
 	}
+	disp.loadRoute(u, path, func() {
+		startViewTransition(d, changePage)
+	})
 }
 
 func isExternalNavigation(u *url.URL) bool {
@@ -397,6 +531,44 @@ func onAppInstallChange(d ClientDispatcher) func(this Value, args []Value) inter
 	}
 }
 
+func onAppInstalled(d ClientDispatcher) EventHandler {
+	return func(ctx Context, e Event) {
+		d.AppInstalled()
+	}
+}
+
+// displayMode returns the display mode of the app, as defined in the display
+// field of the manifest.
+func displayMode() string {
+	for _, m := range displayModes {
+		if Window().Call("matchMedia", "(display-mode: "+m+")").Get("matches").Bool() {
+			return m
+		}
+	}
+	return "browser"
+}
+
+// watchDisplayMode triggers OnDisplayModeChange when the display mode changes.
+func watchDisplayMode(d ClientDispatcher) func() {
+	onChange := FuncOf(func(this Value, args []Value) interface{} {
+		d.DisplayModeChange()
+		return nil
+	})
+
+	queries := make([]Value, len(displayModes))
+	for i, m := range displayModes {
+		queries[i] = Window().Call("matchMedia", "(display-mode: "+m+")")
+		queries[i].Call("addEventListener", "change", onChange)
+	}
+
+	return func() {
+		for _, q := range queries {
+			q.Call("removeEventListener", "change", onChange)
+		}
+		onChange.Release()
+	}
+}
+
 func onResize(ctx Context, e Event) {
 	if resizeTimer != nil {
 		resizeTimer.Stop()
@@ -10,6 +10,7 @@ func TestNewClientStaticResourceResolver(t *testing.T) {
 	utests := []struct {
 		scenario           string
 		staticResourcesURL string
+		fingerprints       map[string]string
 		path               string
 		expected           string
 	}{
@@ -68,11 +69,24 @@ func TestNewClientStaticResourceResolver(t *testing.T) {
 			path:               "https://storage.googleapis.com/go-app/web/hello.css",
 			expected:           "https://storage.googleapis.com/go-app/web/hello.css",
 		},
+		{
+			scenario:     "fingerprinted static resource",
+			fingerprints: map[string]string{"/web/hello.css": "42"},
+			path:         "web/hello.css",
+			expected:     "/web/hello.css?v=42",
+		},
+		{
+			scenario:           "fingerprinted static resource with remote root dir",
+			staticResourcesURL: "https://storage.googleapis.com/go-app",
+			fingerprints:       map[string]string{"/web/hello.css": "42"},
+			path:               "/web/hello.css",
+			expected:           "https://storage.googleapis.com/go-app/web/hello.css?v=42",
+		},
 	}
 
 	for _, u := range utests {
 		t.Run(u.scenario, func(t *testing.T) {
-			res := newClientStaticResourceResolver(u.staticResourcesURL)(u.path)
+			res := newClientStaticResourceResolver(u.staticResourcesURL, u.fingerprints)(u.path)
 			require.Equal(t, u.expected, res)
 		})
 	}
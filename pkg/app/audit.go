@@ -0,0 +1,113 @@
+package app
+
+import (
+	"time"
+)
+
+const (
+	// The kind of the audit events recorded when an annotated element is
+	// clicked.
+	AuditClick = "click"
+
+	// The kind of the audit events recorded when an annotated form is
+	// submitted.
+	AuditSubmit = "submit"
+
+	// The kind of the audit events recorded when the user navigates to a
+	// page.
+	AuditNavigation = "navigation"
+
+	// The kind of the audit events recorded with Context.Audit.
+	AuditAction = "action"
+
+	auditAttr = "data-audit"
+)
+
+var (
+	clientAuditSink AuditSink
+)
+
+// AuditEvent describes a user action recorded for auditing purposes.
+type AuditEvent struct {
+	// The time when the action occurred.
+	Time time.Time `json:"time"`
+
+	// The identifier of the user that performed the action. It is the subject
+	// of the claims set with Handler.Claims or Context.SetClaims.
+	UserID string `json:"userId,omitempty"`
+
+	// The kind of action: AuditClick, AuditSubmit, AuditNavigation or
+	// AuditAction.
+	Kind string `json:"kind"`
+
+	// The name of the action. It is the value of the audit data attribute for
+	// clicks and submissions, the page path for navigations, and the name
+	// given to Context.Audit for actions.
+	Name string `json:"name"`
+
+	// The path of the page where the action occurred.
+	Path string `json:"path"`
+}
+
+// AuditSink is the interface that describes a destination for audit events,
+// such as a remote endpoint or a compliance storage.
+type AuditSink interface {
+	// Records the given event. It is called on the UI goroutine and must not
+	// block.
+	Record(AuditEvent)
+}
+
+// AuditSinkFunc is a function that satisfies the AuditSink interface.
+type AuditSinkFunc func(AuditEvent)
+
+// Record calls f(e).
+func (f AuditSinkFunc) Record(e AuditEvent) {
+	f(e)
+}
+
+// SetAuditSink enables the audit log and sets the sink where audit events are
+// recorded. It must be called before RunWhenOnBrowser.
+//
+// Once enabled, navigations are recorded, as well as the clicks and the
+// submissions handled by the elements annotated with an audit data attribute.
+//
+// eg:
+//  app.SetAuditSink(app.AuditSinkFunc(func(e app.AuditEvent) {
+//      // Send the event to the audit endpoint.
+//  }))
+//
+//  app.Button().
+//      DataSet("audit", "delete-account").
+//      OnClick(b.onDeleteAccount)
+func SetAuditSink(s AuditSink) {
+	clientAuditSink = s
+}
+
+// audit records a user action in the audit sink, if any.
+func (e *engine) audit(kind, name string) {
+	if e.AuditSink == nil || e.RunsInServer {
+		return
+	}
+
+	e.AuditSink.Record(AuditEvent{
+		Time:   time.Now(),
+		UserID: e.claims().Subject,
+		Kind:   kind,
+		Name:   name,
+		Path:   e.Page.URL().Path,
+	})
+}
+
+// auditEvent records the given event when it is a click or a submission
+// handled by an element annotated with an audit data attribute.
+func auditEvent(src UI, ev Event) {
+	name, ok := src.attributes()[auditAttr]
+	if !ok {
+		return
+	}
+
+	switch kind := ev.Get("type").String(); kind {
+	case AuditClick, AuditSubmit:
+		src.dispatcher().audit(kind, name)
+	}
+}
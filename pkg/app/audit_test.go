@@ -0,0 +1,98 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type auditTestValue struct {
+	value
+	props map[string]string
+}
+
+func (v auditTestValue) Get(p string) Value {
+	return auditTestString{s: v.props[p]}
+}
+
+type auditTestString struct {
+	value
+	s string
+}
+
+func (s auditTestString) String() string {
+	return s.s
+}
+
+func newAuditTestEngine(n UI) (*engine, *[]AuditEvent) {
+	var events []AuditEvent
+	e := &engine{
+		AuditSink: AuditSinkFunc(func(ev AuditEvent) {
+			events = append(events, ev)
+		}),
+	}
+	e.init()
+	e.Mount(n)
+	e.Consume()
+	return e, &events
+}
+
+func TestAuditEvent(t *testing.T) {
+	button := Button().DataSet("audit", "delete-account")
+	div := Div()
+	e, events := newAuditTestEngine(Div().Body(button, div))
+	defer e.Close()
+	e.SetState(claimsState, Claims{Subject: "42"})
+	e.Consume()
+
+	click := Event{Value: auditTestValue{props: map[string]string{"type": "click"}}}
+	auditEvent(button, click)
+	auditEvent(div, click)
+	auditEvent(button, Event{Value: auditTestValue{props: map[string]string{"type": "mouseover"}}})
+
+	require.Len(t, *events, 1)
+	ev := (*events)[0]
+	require.Equal(t, AuditClick, ev.Kind)
+	require.Equal(t, "delete-account", ev.Name)
+	require.Equal(t, "42", ev.UserID)
+	require.Empty(t, ev.Path)
+	require.False(t, ev.Time.IsZero())
+}
+
+func TestAuditNavigation(t *testing.T) {
+	e, events := newAuditTestEngine(&hello{})
+	defer e.Close()
+
+	u, _ := url.Parse("https://test.go-app.dev/invoices")
+	e.Nav(u)
+	e.Consume()
+
+	require.Len(t, *events, 1)
+	require.Equal(t, AuditNavigation, (*events)[0].Kind)
+	require.Equal(t, "/invoices", (*events)[0].Name)
+}
+
+func TestContextAudit(t *testing.T) {
+	compo := &hello{}
+	e, events := newAuditTestEngine(compo)
+	defer e.Close()
+
+	makeContext(compo).Audit("export-invoices")
+	require.Len(t, *events, 1)
+	require.Equal(t, AuditAction, (*events)[0].Kind)
+	require.Equal(t, "export-invoices", (*events)[0].Name)
+	require.Empty(t, (*events)[0].UserID)
+}
+
+func TestAuditDisabled(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	require.NotPanics(t, func() {
+		makeContext(compo).Audit("export-invoices")
+	})
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	claimsState    = "/app/claims"
+	claimsScriptID = "app-claims"
+)
+
+// Claims describes the user for which the app is displayed and the roles
+// granted to them.
+type Claims struct {
+	// The identifier of the user. It is empty when the user is not signed in.
+	Subject string `json:"sub,omitempty"`
+
+	// The roles granted to the user.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// HasRole reports whether the given role is granted.
+func (c Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAuthorized reports whether one of the given roles is granted. When no role
+// is given, it reports whether the user is signed in.
+func (c Claims) IsAuthorized(roles ...string) bool {
+	if len(roles) == 0 {
+		return c.Subject != ""
+	}
+
+	for _, r := range roles {
+		if c.HasRole(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Claims) isZero() bool {
+	return c.Subject == "" && len(c.Roles) == 0
+}
+
+// IfAuthorized returns a UI element that displays the given element only when
+// one of the given roles is granted to the current user, as described by the
+// claims set with Handler.Claims and Context.SetClaims. When no role is given,
+// the element is displayed to signed in users.
+//
+// The element is not mounted when the user is not authorized, which means that
+// it is never prerendered nor rendered in the browser. It is mounted or
+// dismounted each time the claims change.
+//
+// eg:
+//  app.Div().Body(
+//      app.IfAuthorized([]string{"admin"},
+//          &adminPanel{},
+//      ),
+//  )
+func IfAuthorized(roles []string, ui UI) UI {
+	return &authorized{
+		Roles: roles,
+		Body:  ui,
+	}
+}
+
+type authorized struct {
+	Compo
+
+	Roles []string
+	Body  UI
+
+	claims Claims
+}
+
+func (a *authorized) OnMount(ctx Context) {
+	ctx.ObserveState(claimsState).Value(&a.claims)
+}
+
+func (a *authorized) Render() UI {
+	if a.Body == nil || !a.dispatcher().claims().IsAuthorized(a.Roles...) {
+		return Text("")
+	}
+	return a.Body
+}
+
+// requestClaims returns the claims of the given request.
+func (h *Handler) requestClaims(r *http.Request) Claims {
+	if h.Claims == nil {
+		return Claims{}
+	}
+	return h.Claims(r)
+}
+
+// renderPageClaims returns the script that passes the given claims to the app.
+func renderPageClaims(c Claims) string {
+	if c.isZero() {
+		return ""
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		Log(errors.New("encoding page claims failed").Wrap(err))
+		return ""
+	}
+	return `<script id="` + claimsScriptID + `" type="application/json">` + string(b) + `</script>`
+}
+
+// loadPageClaims sets the claims passed in the page by the server.
+func loadPageClaims(d Dispatcher) {
+	script := Window().GetElementByID(claimsScriptID)
+	if !script.Truthy() {
+		return
+	}
+
+	var c Claims
+	if err := json.Unmarshal([]byte(script.Get("textContent").String()), &c); err != nil {
+		Log(errors.New("decoding page claims failed").Wrap(err))
+		return
+	}
+	d.SetState(claimsState, c)
+}
+
+func (e *engine) claims() Claims {
+	if e.RunsInServer {
+		return e.Claims
+	}
+
+	var c Claims
+	e.GetState(claimsState, &c)
+	return c
+}
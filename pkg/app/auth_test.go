@@ -0,0 +1,124 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/authorized", &authTestPage{})
+}
+
+type authTestPage struct {
+	Compo
+}
+
+func (p *authTestPage) Render() UI {
+	return Div().
+		ID("auth-page").
+		Body(
+			IfAuthorized([]string{"admin"},
+				Div().ID("admin-panel"),
+			),
+		)
+}
+
+type authTestCompo struct {
+	Compo
+}
+
+func (c *authTestCompo) Render() UI {
+	return Div().ID("restricted")
+}
+
+func TestClaimsIsAuthorized(t *testing.T) {
+	c := Claims{
+		Subject: "42",
+		Roles:   []string{"editor", "admin"},
+	}
+	require.True(t, c.HasRole("admin"))
+	require.False(t, c.HasRole("owner"))
+	require.True(t, c.IsAuthorized())
+	require.True(t, c.IsAuthorized("owner", "editor"))
+	require.False(t, c.IsAuthorized("owner"))
+	require.False(t, Claims{}.IsAuthorized())
+}
+
+func TestIfAuthorized(t *testing.T) {
+	restricted := &authTestCompo{}
+	d := NewClientTester(Div().Body(
+		IfAuthorized([]string{"admin"}, restricted),
+	))
+	defer d.Close()
+	d.Consume()
+	require.False(t, restricted.Mounted())
+
+	t.Run("restricted element is mounted when authorized", func(t *testing.T) {
+		d.SetState(claimsState, Claims{Subject: "42", Roles: []string{"admin"}})
+		d.Consume()
+		require.True(t, restricted.Mounted())
+	})
+
+	t.Run("restricted element is dismounted when unauthorized", func(t *testing.T) {
+		d.SetState(claimsState, Claims{Subject: "42"})
+		d.Consume()
+		require.False(t, restricted.Mounted())
+	})
+}
+
+func TestContextClaims(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+	d.Consume()
+
+	ctx := makeContext(compo)
+	require.False(t, ctx.HasRole("admin"))
+
+	ctx.SetClaims(Claims{Subject: "42", Roles: []string{"admin"}})
+	d.Consume()
+	require.True(t, ctx.HasRole("admin"))
+	require.Equal(t, "42", ctx.Claims().Subject)
+}
+
+func TestHandlerServePageWithClaims(t *testing.T) {
+	var claims Claims
+	h := Handler{
+		Claims: func(r *http.Request) Claims {
+			return claims
+		},
+	}
+
+	serve := func() string {
+		r := httptest.NewRequest(http.MethodGet, "/authorized", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("restricted markup is not prerendered", func(t *testing.T) {
+		body := serve()
+		require.Contains(t, body, `id="auth-page"`)
+		require.NotContains(t, body, `id="admin-panel"`)
+		require.NotContains(t, body, `id="app-claims"`)
+	})
+
+	t.Run("restricted markup is prerendered when authorized", func(t *testing.T) {
+		claims = Claims{Subject: "42", Roles: []string{"admin"}}
+		body := serve()
+		require.Contains(t, body, `id="admin-panel"`)
+		require.Contains(t, body, `<script id="app-claims" type="application/json">{"sub":"42","roles":["admin"]}</script>`)
+	})
+
+	t.Run("pages prerendered with claims are not cached", func(t *testing.T) {
+		claims = Claims{}
+		body := serve()
+		require.NotContains(t, body, `id="admin-panel"`)
+	})
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The name of the action posted when a background fetch progresses or is
+	// done. The action value is the BackgroundFetchProgress of the fetch.
+	//
+	// The fetches that progressed or were completed while the app was closed
+	// are reported when the app is opened again.
+	BackgroundFetchAction = "/app/background-fetch"
+
+	backgroundFetchCacheName  = "goapp-background-fetch"
+	backgroundFetchMessage    = "goapp-background-fetch"
+	backgroundFetchRecordPath = "/app-background-fetch/"
+)
+
+// BackgroundFetchResult describes how a background fetch ended.
+type BackgroundFetchResult string
+
+const (
+	// The background fetch is in progress.
+	BackgroundFetchPending BackgroundFetchResult = ""
+
+	// All the files of the background fetch are downloaded.
+	BackgroundFetchSuccess BackgroundFetchResult = "success"
+
+	// The background fetch failed or was aborted.
+	BackgroundFetchFailure BackgroundFetchResult = "failure"
+)
+
+// BackgroundFetchOptions describes how files are downloaded with
+// Context.BackgroundFetch.
+type BackgroundFetchOptions struct {
+	// The title displayed by the browser while the files are downloaded.
+	Title string
+
+	// The paths or URLs of the icons displayed by the browser while the files
+	// are downloaded.
+	Icons []string
+
+	// The total size of the files in bytes. It is used by the browser to
+	// display the progress of the download.
+	DownloadTotal int64
+
+	// The function called on the UI goroutine when the background fetch is
+	// started. The error is not nil when the browser does not support
+	// background fetches or when the fetch could not be started.
+	OnStart func(Context, error)
+}
+
+// BackgroundFetchProgress describes the progress of a background fetch.
+type BackgroundFetchProgress struct {
+	// The background fetch id.
+	ID string
+
+	// The number of bytes downloaded.
+	Downloaded int64
+
+	// The total size of the files in bytes. It is 0 when it was not given
+	// when the fetch was started.
+	DownloadTotal int64
+
+	// How the background fetch ended.
+	Result BackgroundFetchResult
+
+	// The reason why the background fetch failed. eg: "aborted",
+	// "bad-status", "fetch-error", "quota-exceeded" or "download-total-exceeded".
+	FailureReason string
+}
+
+// Progress returns the progress of the background fetch, ranging from 0 to 1.
+// It is 0 when the total size of the files is unknown.
+func (p BackgroundFetchProgress) Progress() float64 {
+	if p.DownloadTotal <= 0 {
+		return 0
+	}
+	return float64(p.Downloaded) / float64(p.DownloadTotal)
+}
+
+// IsDone reports whether the background fetch is over.
+func (p BackgroundFetchProgress) IsDone() bool {
+	return p.Result != BackgroundFetchPending
+}
+
+func backgroundFetch(ctx Context, id string, urls []string, opts BackgroundFetchOptions) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	done := func(err error) {
+		if err != nil {
+			err = errors.New("starting background fetch failed").
+				Tag("id", id).
+				Wrap(err)
+		}
+		dispatchResult(ctx, opts.OnStart == nil, err, func(ctx Context) {
+			opts.OnStart(ctx, err)
+		})
+	}
+
+	withBackgroundFetchManager(func(manager Value) {
+		requests := make([]interface{}, len(urls))
+		for i, u := range urls {
+			requests[i] = u
+		}
+
+		icons := make([]interface{}, len(opts.Icons))
+		for i, src := range opts.Icons {
+			icons[i] = map[string]interface{}{"src": src}
+		}
+
+		options := map[string]interface{}{
+			"title": opts.Title,
+			"icons": icons,
+		}
+		if opts.DownloadTotal > 0 {
+			options["downloadTotal"] = float64(opts.DownloadTotal)
+		}
+
+		awaitPromise(manager.Call("fetch", id, requests, options), func(reg Value) {
+			watchBackgroundFetch(ctx.Dispatcher(), reg)
+			done(nil)
+		}, func(reason Value) {
+			done(jsReasonError(reason))
+		})
+	}, done)
+}
+
+func abortBackgroundFetch(ctx Context, id string) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	fail := func(err error) {
+		Log(errors.New("aborting background fetch failed").
+			Tag("id", id).
+			Wrap(err))
+	}
+
+	withBackgroundFetchManager(func(manager Value) {
+		awaitPromise(manager.Call("get", id), func(reg Value) {
+			if reg.Truthy() {
+				reg.Call("abort")
+			}
+		}, func(reason Value) {
+			fail(jsReasonError(reason))
+		})
+	}, fail)
+}
+
+// withBackgroundFetchManager calls onManager with the background fetch manager
+// of the active service worker registration.
+func withBackgroundFetchManager(onManager func(Value), onErr func(error)) {
+	withServiceWorkerRegistration(func(reg Value) {
+		manager := reg.Get("backgroundFetch")
+		if !manager.Truthy() {
+			onErr(errors.New("background fetch is not supported"))
+			return
+		}
+		onManager(manager)
+	}, onErr)
+}
+
+// withServiceWorkerRegistration calls onReady with the service worker
+// registration once it is active.
+func withServiceWorkerRegistration(onReady func(Value), onErr func(error)) {
+	serviceWorker := Window().Get("navigator").Get("serviceWorker")
+	if !serviceWorker.Truthy() {
+		onErr(errors.New("service workers are not supported"))
+		return
+	}
+
+	awaitPromise(serviceWorker.Get("ready"), onReady, func(reason Value) {
+		onErr(jsReasonError(reason))
+	})
+}
+
+// watchBackgroundFetch posts the progress of the given background fetch
+// registration until it is over. The end of the fetch is reported by the
+// service worker.
+func watchBackgroundFetch(d Dispatcher, reg Value) {
+	post := func() {
+		if p := makeBackgroundFetchProgress(reg); !p.IsDone() {
+			postBackgroundFetchProgress(d, p)
+		}
+	}
+
+	var onProgress Func
+	onProgress = FuncOf(func(this Value, args []Value) interface{} {
+		if reg.Get("result").String() != "" {
+			reg.Call("removeEventListener", "progress", onProgress)
+			onProgress.Release()
+			return nil
+		}
+		post()
+		return nil
+	})
+	reg.Call("addEventListener", "progress", onProgress)
+	post()
+}
+
+// startBackgroundFetches reports the background fetches that ended or
+// progressed while the app was closed, and the ones that end while it is
+// running.
+func startBackgroundFetches(d Dispatcher) {
+	serviceWorker := Window().Get("navigator").Get("serviceWorker")
+	if !serviceWorker.Truthy() {
+		return
+	}
+
+	serviceWorker.Call("addEventListener", "message", FuncOf(func(this Value, args []Value) interface{} {
+		data := promiseArg(args).Get("data")
+		if !data.Truthy() || jsOptionalString(data.Get("type")) != backgroundFetchMessage {
+			return nil
+		}
+
+		postBackgroundFetchProgress(d, makeBackgroundFetchProgress(data))
+		deleteBackgroundFetchRecord(data.Get("record").String())
+		return nil
+	}))
+
+	withBackgroundFetchManager(func(manager Value) {
+		awaitPromise(manager.Call("getIds"), func(ids Value) {
+			for i := 0; i < ids.Length(); i++ {
+				awaitPromise(manager.Call("get", ids.Index(i)), func(reg Value) {
+					if reg.Truthy() {
+						watchBackgroundFetch(d, reg)
+					}
+				}, logBackgroundFetchRestoreError)
+			}
+		}, logBackgroundFetchRestoreError)
+	}, func(error) {})
+
+	loadBackgroundFetchRecords(d)
+}
+
+// loadBackgroundFetchRecords posts the results of the background fetches that
+// ended while the app was closed. Results are recorded in the cache by the
+// service worker.
+func loadBackgroundFetchRecords(d Dispatcher) {
+	caches := Window().Get("caches")
+	if !caches.Truthy() {
+		return
+	}
+
+	awaitPromise(caches.Call("open", backgroundFetchCacheName), func(cache Value) {
+		awaitPromise(cache.Call("keys"), func(requests Value) {
+			for i := 0; i < requests.Length(); i++ {
+				req := requests.Index(i)
+				if !isBackgroundFetchRecord(req.Get("url").String()) {
+					continue
+				}
+
+				awaitPromise(cache.Call("match", req), func(res Value) {
+					awaitPromise(res.Call("json"), func(data Value) {
+						postBackgroundFetchProgress(d, makeBackgroundFetchProgress(data))
+						cache.Call("delete", req)
+					}, logBackgroundFetchRestoreError)
+				}, logBackgroundFetchRestoreError)
+			}
+		}, logBackgroundFetchRestoreError)
+	}, logBackgroundFetchRestoreError)
+}
+
+func deleteBackgroundFetchRecord(record string) {
+	caches := Window().Get("caches")
+	if !caches.Truthy() || !isBackgroundFetchRecord(record) {
+		return
+	}
+
+	awaitPromise(caches.Call("open", backgroundFetchCacheName), func(cache Value) {
+		cache.Call("delete", record)
+	}, logBackgroundFetchRestoreError)
+}
+
+func isBackgroundFetchRecord(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	return err == nil && strings.HasPrefix(u.Path, rootPrefix+backgroundFetchRecordPath)
+}
+
+func makeBackgroundFetchProgress(v Value) BackgroundFetchProgress {
+	return BackgroundFetchProgress{
+		ID:            v.Get("id").String(),
+		Downloaded:    int64(v.Get("downloaded").Float()),
+		DownloadTotal: int64(v.Get("downloadTotal").Float()),
+		Result:        BackgroundFetchResult(jsOptionalString(v.Get("result"))),
+		FailureReason: jsOptionalString(v.Get("failureReason")),
+	}
+}
+
+func postBackgroundFetchProgress(d Dispatcher, p BackgroundFetchProgress) {
+	d.Post(Action{
+		Name:  BackgroundFetchAction,
+		Value: p,
+	})
+}
+
+func logBackgroundFetchRestoreError(reason Value) {
+	Log(errors.New("restoring background fetches failed").Wrap(jsReasonError(reason)))
+}
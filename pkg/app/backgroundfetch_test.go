@@ -0,0 +1,37 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackgroundFetchOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	started := false
+	ctx := makeContext(h)
+	ctx.BackgroundFetch("videos", []string{"/web/video.mp4"}, BackgroundFetchOptions{
+		OnStart: func(Context, error) { started = true },
+	})
+	ctx.AbortBackgroundFetch("videos")
+	d.Consume()
+	require.False(t, started)
+}
+
+func TestBackgroundFetchProgress(t *testing.T) {
+	require.Zero(t, BackgroundFetchProgress{Downloaded: 42}.Progress())
+	require.Equal(t, 0.5, BackgroundFetchProgress{Downloaded: 21, DownloadTotal: 42}.Progress())
+
+	require.False(t, BackgroundFetchProgress{}.IsDone())
+	require.True(t, BackgroundFetchProgress{Result: BackgroundFetchSuccess}.IsDone())
+	require.True(t, BackgroundFetchProgress{Result: BackgroundFetchFailure}.IsDone())
+}
+
+func TestIsBackgroundFetchRecord(t *testing.T) {
+	require.True(t, isBackgroundFetchRecord("https://murlok.io/app-background-fetch/videos"))
+	require.False(t, isBackgroundFetchRecord("https://murlok.io/web/video.mp4"))
+	require.False(t, isBackgroundFetchRecord(""))
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"testing"
+)
+
+// BenchmarkRender measures the update of the given component in the server
+// engine. The component is mounted once, then each iteration calls mutate to
+// modify its fields and measures the render and the diff that result from
+// its update. Mutations are excluded from the timings and the allocations.
+//
+// In addition to the time and the allocations per operation, it reports the
+// number of components updated per operation and the number of nodes of the
+// mounted tree, which helps to tell a slower diff from a larger tree. Eg:
+//  func BenchmarkList(b *testing.B) {
+//      l := &list{}
+//      app.BenchmarkRender(b, l, func(i int) {
+//          l.Items = append(l.Items, strconv.Itoa(i))
+//      })
+//  }
+func BenchmarkRender(b *testing.B, compo Composer, mutate func(i int)) {
+	b.Helper()
+
+	d := NewServerTester(compo).(*engine)
+	defer d.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	updates := 0
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		if mutate != nil {
+			mutate(i)
+		}
+		d.updateCount = 0
+		b.StartTimer()
+
+		compo.Update()
+		d.Consume()
+
+		b.StopTimer()
+		updates += d.updateCount
+		b.StartTimer()
+	}
+
+	b.StopTimer()
+	b.ReportMetric(float64(updates)/float64(b.N), "updates/op")
+	b.ReportMetric(float64(countNodes(compo)), "nodes")
+}
+
+// BenchmarkMount measures the creation and the mount of the component
+// returned by newCompo in the server engine, as done when a page is
+// pre-rendered. Eg:
+//  func BenchmarkHome(b *testing.B) {
+//      app.BenchmarkMount(b, func() app.UI {
+//          return &home{}
+//      })
+//  }
+func BenchmarkMount(b *testing.B, newCompo func() UI) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	nodes := 0
+	for i := 0; i < b.N; i++ {
+		n := newCompo()
+		d := NewServerTester(n)
+
+		b.StopTimer()
+		nodes = countNodes(n)
+		d.Close()
+		b.StartTimer()
+	}
+
+	b.StopTimer()
+	b.ReportMetric(float64(nodes), "nodes")
+}
+
+// countNodes returns the number of nodes of the given tree.
+func countNodes(n UI) int {
+	count := 1
+	for _, c := range n.children() {
+		count += countNodes(c)
+	}
+	return count
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type benchmarkTestList struct {
+	Compo
+
+	Items []string
+}
+
+func (l *benchmarkTestList) Render() UI {
+	return Ul().Body(
+		Range(l.Items).Slice(func(i int) UI {
+			return Li().
+				Class("item").
+				Text(l.Items[i])
+		}),
+	)
+}
+
+func TestBenchmarkRender(t *testing.T) {
+	l := &benchmarkTestList{}
+	res := testing.Benchmark(func(b *testing.B) {
+		BenchmarkRender(b, l, func(i int) {
+			l.Items = []string{strconv.Itoa(i), "b", "c"}
+		})
+	})
+	require.NotZero(t, res.N)
+	require.Equal(t, float64(1), res.Extra["updates/op"])
+	require.Equal(t, float64(8), res.Extra["nodes"])
+}
+
+func TestBenchmarkMount(t *testing.T) {
+	res := testing.Benchmark(func(b *testing.B) {
+		BenchmarkMount(b, func() UI {
+			return &benchmarkTestList{Items: []string{"a", "b"}}
+		})
+	})
+	require.NotZero(t, res.N)
+	require.Equal(t, float64(6), res.Extra["nodes"])
+}
+
+func BenchmarkRenderListAppend(b *testing.B) {
+	l := &benchmarkTestList{}
+	BenchmarkRender(b, l, func(i int) {
+		if len(l.Items) >= 100 {
+			l.Items = l.Items[:0]
+		}
+		l.Items = append(l.Items, strconv.Itoa(i))
+	})
+}
+
+func BenchmarkRenderListText(b *testing.B) {
+	l := &benchmarkTestList{Items: make([]string, 100)}
+	BenchmarkRender(b, l, func(i int) {
+		l.Items[i%len(l.Items)] = strconv.Itoa(i)
+	})
+}
+
+func BenchmarkMountList(b *testing.B) {
+	items := make([]string, 100)
+	BenchmarkMount(b, func() UI {
+		return &benchmarkTestList{Items: items}
+	})
+}
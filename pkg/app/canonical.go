@@ -0,0 +1,137 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Alternate describes an alternate language variant of a page.
+type Alternate struct {
+	// The language of the variant. eg: "fr", "en-US" or "x-default".
+	HrefLang string
+
+	// The URL template of the variant. See RouteCanonical for the template
+	// syntax.
+	URL string
+}
+
+// RouteCanonical sets the canonical URL template of the pages served by the
+// route registered with the given path or regular expression pattern.
+//
+// In the template, "{path}" is replaced by the page path and "{name}" by the
+// value of the parameter "name" of the route path or of the named group "name"
+// of the route regular expression. Templates that start with a "/" are
+// relative to the page host.
+//
+// eg:
+//  app.RouteWithRegexp("^/blog/(?P<slug>[^/]+)$", &blogPost{})
+//  app.RouteCanonical("^/blog/(?P<slug>[^/]+)$", "https://go-app.dev/blog/{slug}")
+//
+// The canonical URL is emitted as a link tag during prerender and is updated
+// on navigation.
+func RouteCanonical(route, urlTemplate string) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.canonical = urlTemplate
+	})
+}
+
+// RouteAlternates sets the alternate language variants of the pages served by
+// the route registered with the given path or regular expression pattern.
+//
+// eg:
+//  app.RouteAlternates("/",
+//      app.Alternate{HrefLang: "en", URL: "https://go-app.dev{path}"},
+//      app.Alternate{HrefLang: "fr", URL: "https://go-app.dev/fr{path}"},
+//      app.Alternate{HrefLang: "x-default", URL: "https://go-app.dev{path}"},
+//  )
+//
+// Alternates are emitted as link tags during prerender and are updated on
+// navigation.
+func RouteAlternates(route string, alternates ...Alternate) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.alternates = alternates
+	})
+}
+
+type routeLink struct {
+	rel      string
+	hrefLang string
+	href     string
+}
+
+// routeLinks returns the canonical and alternate links of the page with the
+// given URL, which is served by the route with the given path.
+func routeLinks(u *url.URL, path string) []routeLink {
+	meta, groups := routes.meta(path)
+
+	var links []routeLink
+	if meta.canonical != "" {
+		links = append(links, routeLink{
+			rel:  "canonical",
+			href: expandRouteURL(meta.canonical, u, groups),
+		})
+	}
+	for _, a := range meta.alternates {
+		links = append(links, routeLink{
+			rel:      "alternate",
+			hrefLang: a.HrefLang,
+			href:     expandRouteURL(a.URL, u, groups),
+		})
+	}
+	return links
+}
+
+func expandRouteURL(template string, u *url.URL, groups map[string]string) string {
+	href := expandRouteTemplate(template, u.Path, groups)
+
+	if strings.HasPrefix(href, "/") && u.Host != "" {
+		scheme := u.Scheme
+		if scheme == "" {
+			scheme = "https"
+		}
+		href = scheme + "://" + u.Host + href
+	}
+	return href
+}
+
+func expandRouteTemplate(template, path string, groups map[string]string) string {
+	oldnew := []string{"{path}", path}
+	for name, v := range groups {
+		oldnew = append(oldnew, "{"+name+"}", v)
+	}
+	return strings.NewReplacer(oldnew...).Replace(template)
+}
+
+func renderRouteLinks(links []routeLink) []UI {
+	elems := make([]UI, 0, len(links))
+	for _, l := range links {
+		link := Link().
+			Rel(l.rel).
+			Href(l.href)
+		if l.hrefLang != "" {
+			link.HrefLang(l.hrefLang)
+		}
+		elems = append(elems, link)
+	}
+	return elems
+}
+
+func updateRouteLinks(u *url.URL, path string) {
+	doc := Window().Get("document")
+	head := doc.Get("head")
+
+	current := doc.Call("querySelectorAll", "link[rel='canonical'], link[rel='alternate'][hreflang]")
+	for i := current.Length() - 1; i >= 0; i-- {
+		head.Call("removeChild", current.Index(i))
+	}
+
+	for _, l := range routeLinks(u, path) {
+		link := doc.Call("createElement", "link")
+		link.setAttr("rel", l.rel)
+		link.setAttr("href", l.href)
+		if l.hrefLang != "" {
+			link.setAttr("hreflang", l.hrefLang)
+		}
+		head.Call("appendChild", link)
+	}
+}
@@ -0,0 +1,75 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/canonical-test", &routeCompo{})
+	RouteCanonical("/canonical-test", "/canonical-test/main")
+	RouteAlternates("/canonical-test",
+		Alternate{HrefLang: "en", URL: "https://go-app.dev{path}"},
+		Alternate{HrefLang: "fr", URL: "https://go-app.dev/fr{path}"},
+	)
+
+	RouteWithRegexp("^/canonical-test/blog/(?P<slug>[^/]+)$", &routeWithRegexpCompo{})
+	RouteCanonical("^/canonical-test/blog/(?P<slug>[^/]+)$", "https://go-app.dev/blog/{slug}")
+}
+
+func TestRouteLinks(t *testing.T) {
+	utests := []struct {
+		scenario string
+		url      string
+		expected []routeLink
+	}{
+		{
+			scenario: "route without links",
+			url:      "https://go-app.dev/",
+		},
+		{
+			scenario: "route with canonical and alternates",
+			url:      "https://go-app.dev/canonical-test",
+			expected: []routeLink{
+				{rel: "canonical", href: "https://go-app.dev/canonical-test/main"},
+				{rel: "alternate", hrefLang: "en", href: "https://go-app.dev/canonical-test"},
+				{rel: "alternate", hrefLang: "fr", href: "https://go-app.dev/fr/canonical-test"},
+			},
+		},
+		{
+			scenario: "regexp route with named group",
+			url:      "https://go-app.dev/canonical-test/blog/hello",
+			expected: []routeLink{
+				{rel: "canonical", href: "https://go-app.dev/blog/hello"},
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			pageURL, err := url.Parse(u.url)
+			require.NoError(t, err)
+			require.Equal(t, u.expected, routeLinks(pageURL, pageURL.Path))
+		})
+	}
+}
+
+func TestHandlerServePageRouteLinks(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/canonical-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Regexp(t, `<link (rel="canonical" href="http://example.com/canonical-test/main"|href="http://example.com/canonical-test/main" rel="canonical")>`, body)
+	require.Contains(t, body, `hreflang="fr"`)
+	require.Contains(t, body, `"https://go-app.dev/fr/canonical-test"`)
+}
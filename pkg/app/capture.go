@@ -0,0 +1,384 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	recorderDefaultName            = "recording"
+	recorderDefaultUploadTimeslice = time.Second * 5
+)
+
+// DisplayCaptureOptions describes how the screen is captured with
+// MediaCapture.Display.
+type DisplayCaptureOptions struct {
+	// Reports whether the audio of the captured surface is captured. It is
+	// ignored by browsers that can't capture audio.
+	Audio bool
+
+	// Reports whether the browser offers the current tab as the captured
+	// surface first.
+	PreferCurrentTab bool
+}
+
+// CameraCaptureOptions describes how the camera is captured with
+// MediaCapture.Camera.
+type CameraCaptureOptions struct {
+	// Reports whether the microphone is captured.
+	Audio bool
+
+	// The camera to use: "user" for the front camera or "environment" for
+	// the back camera. The browser picks one when empty.
+	FacingMode string
+
+	// The ideal width of the video in px. The browser picks one when zero.
+	Width int
+
+	// The ideal height of the video in px. The browser picks one when zero.
+	Height int
+}
+
+// MediaStream is a captured media stream. It is displayed by setting its
+// JavaScript value as the srcObject of a video element. It must be stopped
+// with Stop when no longer used.
+type MediaStream struct {
+	value Value
+}
+
+// JSValue returns the JavaScript MediaStream value.
+func (s MediaStream) JSValue() Value {
+	return s.value
+}
+
+// IsActive reports whether the stream has tracks that are still captured.
+func (s MediaStream) IsActive() bool {
+	return s.value != nil && s.value.Get("active").Bool()
+}
+
+// Stop stops the capture of the stream tracks.
+func (s MediaStream) Stop() {
+	if s.value == nil {
+		return
+	}
+
+	tracks := s.value.Call("getTracks")
+	for i := 0; i < tracks.Length(); i++ {
+		tracks.Index(i).Call("stop")
+	}
+}
+
+// RecorderOptions describes how a media stream is recorded with
+// MediaCapture.Record.
+type RecorderOptions struct {
+	// The content type of the recording. eg: "video/webm;codecs=vp9". The
+	// browser picks one when empty.
+	Type string
+
+	// The bit rate of the video in bits per second. The browser picks one
+	// when zero.
+	VideoBitsPerSecond int
+
+	// The bit rate of the audio in bits per second. The browser picks one
+	// when zero.
+	AudioBitsPerSecond int
+
+	// The duration of the recorded chunks. The recording is a single chunk
+	// when zero.
+	//
+	// Default: 5s when Upload is set.
+	Timeslice time.Duration
+
+	// The name of the recording. Uploaded chunks are named with the name,
+	// their number and the recording extension. eg: "recording-0.webm".
+	//
+	// Default: "recording".
+	Name string
+
+	// When set, each chunk is uploaded with Context.Upload as soon as it is
+	// recorded.
+	Upload *UploadOptions
+
+	// The function called on the UI goroutine when a chunk is recorded, with
+	// its JavaScript Blob value.
+	OnChunk func(Context, Value)
+
+	// The function called on the UI goroutine when the recording is stopped,
+	// with the JavaScript Blob value of the whole recording. The error is not
+	// nil when the recording could not be started or failed.
+	OnDone func(Context, Value, error)
+}
+
+// MediaRecorder records a media stream. It is stopped when the context it was
+// created with is done.
+type MediaRecorder struct {
+	value Value
+}
+
+// State returns the state of the recorder: "inactive", "recording" or
+// "paused".
+func (r *MediaRecorder) State() string {
+	if r.value == nil {
+		return "inactive"
+	}
+	return r.value.Get("state").String()
+}
+
+// Pause pauses the recording.
+func (r *MediaRecorder) Pause() {
+	if r.State() == "recording" {
+		r.value.Call("pause")
+	}
+}
+
+// Resume resumes a paused recording.
+func (r *MediaRecorder) Resume() {
+	if r.State() == "paused" {
+		r.value.Call("resume")
+	}
+}
+
+// Stop stops the recording. RecorderOptions.OnDone is then called with the
+// recording.
+func (r *MediaRecorder) Stop() {
+	if r.State() != "inactive" {
+		r.value.Call("stop")
+	}
+}
+
+// MediaCapture captures the screen and the camera with the Screen Capture and
+// Media Capture APIs, and records the captured streams with the MediaStream
+// Recording API.
+//
+// Operations are asynchronous. Their result is passed to the given function,
+// called on the UI goroutine. Operations do nothing on the server.
+type MediaCapture struct {
+	ctx Context
+}
+
+// IsDisplaySupported reports whether the browser can capture the screen.
+func (m MediaCapture) IsDisplaySupported() bool {
+	return !IsServer && Window().Get("navigator").Get("mediaDevices").Get("getDisplayMedia").Truthy()
+}
+
+// IsCameraSupported reports whether the browser can capture the camera.
+func (m MediaCapture) IsCameraSupported() bool {
+	return !IsServer && Window().Get("navigator").Get("mediaDevices").Get("getUserMedia").Truthy()
+}
+
+// IsRecordingSupported reports whether the browser can record media streams.
+func (m MediaCapture) IsRecordingSupported() bool {
+	return !IsServer && Window().Get("MediaRecorder").Truthy()
+}
+
+// Display asks the user to pick a screen, a window or a tab and captures it.
+// It must be called from a user gesture, like a click handler.
+func (m MediaCapture) Display(opts DisplayCaptureOptions, onDone func(Context, MediaStream, error)) {
+	constraints := map[string]interface{}{
+		"video": true,
+		"audio": opts.Audio,
+	}
+	if opts.PreferCurrentTab {
+		constraints["preferCurrentTab"] = true
+	}
+	m.capture("getDisplayMedia", constraints, onDone)
+}
+
+// Camera captures the camera, and the microphone when requested. The user is
+// asked for the permission the first time.
+func (m MediaCapture) Camera(opts CameraCaptureOptions, onDone func(Context, MediaStream, error)) {
+	video := map[string]interface{}{}
+	if opts.FacingMode != "" {
+		video["facingMode"] = opts.FacingMode
+	}
+	if opts.Width > 0 {
+		video["width"] = map[string]interface{}{"ideal": opts.Width}
+	}
+	if opts.Height > 0 {
+		video["height"] = map[string]interface{}{"ideal": opts.Height}
+	}
+
+	m.capture("getUserMedia", map[string]interface{}{
+		"video": video,
+		"audio": opts.Audio,
+	}, onDone)
+}
+
+func (m MediaCapture) capture(method string, constraints map[string]interface{}, onDone func(Context, MediaStream, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(stream MediaStream, err error) {
+		if err != nil {
+			err = errors.New("capturing media failed").
+				Tag("method", method).
+				Wrap(err)
+		}
+		dispatchResult(m.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, stream, err)
+		})
+	}
+
+	devices := Window().Get("navigator").Get("mediaDevices")
+	if !devices.Truthy() || !devices.Get(method).Truthy() {
+		done(MediaStream{}, errors.New("media capture is not supported"))
+		return
+	}
+
+	awaitPromise(devices.Call(method, constraints), func(stream Value) {
+		done(MediaStream{value: stream}, nil)
+	}, func(reason Value) {
+		done(MediaStream{}, jsReasonError(reason))
+	})
+}
+
+// Record starts recording the given stream. The recording is available once
+// the returned recorder is stopped or when the stream ends.
+func (m MediaCapture) Record(stream MediaStream, opts RecorderOptions) *MediaRecorder {
+	r := &MediaRecorder{}
+	if IsServer {
+		return r
+	}
+
+	if opts.Name == "" {
+		opts.Name = recorderDefaultName
+	}
+	if opts.Timeslice <= 0 && opts.Upload != nil {
+		opts.Timeslice = recorderDefaultUploadTimeslice
+	}
+
+	finished := make(chan struct{})
+	done := func(recording Value, err error) {
+		select {
+		case <-finished:
+			return
+		default:
+			close(finished)
+		}
+
+		if err != nil {
+			err = errors.New("recording media failed").
+				Tag("name", opts.Name).
+				Wrap(err)
+		}
+		dispatchResult(m.ctx, opts.OnDone == nil, err, func(ctx Context) {
+			opts.OnDone(ctx, recording, err)
+		})
+	}
+
+	recorder := Window().Get("MediaRecorder")
+	if !recorder.Truthy() {
+		done(nil, errors.New("media recording is not supported"))
+		return r
+	}
+	if stream.value == nil {
+		done(nil, errors.New("media stream is not captured"))
+		return r
+	}
+
+	options := map[string]interface{}{}
+	if opts.Type != "" {
+		if !recorder.Call("isTypeSupported", opts.Type).Bool() {
+			done(nil, errors.New("recording type is not supported").Tag("type", opts.Type))
+			return r
+		}
+		options["mimeType"] = opts.Type
+	}
+	if opts.VideoBitsPerSecond > 0 {
+		options["videoBitsPerSecond"] = opts.VideoBitsPerSecond
+	}
+	if opts.AudioBitsPerSecond > 0 {
+		options["audioBitsPerSecond"] = opts.AudioBitsPerSecond
+	}
+
+	r.value = recorder.New(stream.value, options)
+	startedAt := time.Now()
+	var chunks []interface{}
+
+	var onData, onStop, onError Func
+	release := func() {
+		onData.Release()
+		onStop.Release()
+		onError.Release()
+	}
+
+	onData = FuncOf(func(this Value, args []Value) interface{} {
+		chunk := promiseArg(args).Get("data")
+		if !chunk.Truthy() || chunk.Get("size").Int() == 0 {
+			return nil
+		}
+
+		if opts.Upload != nil {
+			name := opts.Name + "-" + strconv.Itoa(len(chunks)) + recordingExtension(r.value.Get("mimeType").String())
+			file := Window().Get("File").New([]interface{}{chunk}, name, map[string]interface{}{
+				"type":         chunk.Get("type").String(),
+				"lastModified": startedAt.UnixMilli(),
+			})
+			upload(m.ctx, file, *opts.Upload)
+		}
+		chunks = append(chunks, chunk)
+
+		if opts.OnChunk != nil {
+			m.ctx.Dispatch(func(ctx Context) {
+				opts.OnChunk(ctx, chunk)
+			})
+		}
+		return nil
+	})
+
+	onStop = FuncOf(func(this Value, args []Value) interface{} {
+		defer release()
+
+		recording := Window().Get("Blob").New(chunks, map[string]interface{}{
+			"type": r.value.Get("mimeType").String(),
+		})
+		done(recording, nil)
+		return nil
+	})
+
+	onError = FuncOf(func(this Value, args []Value) interface{} {
+		defer release()
+
+		done(nil, jsReasonError(promiseArg(args).Get("error")))
+		return nil
+	})
+
+	r.value.Call("addEventListener", "dataavailable", onData)
+	r.value.Call("addEventListener", "stop", onStop)
+	r.value.Call("addEventListener", "error", onError)
+
+	if opts.Timeslice > 0 {
+		r.value.Call("start", opts.Timeslice.Milliseconds())
+	} else {
+		r.value.Call("start")
+	}
+
+	go func() {
+		select {
+		case <-m.ctx.Done():
+			r.Stop()
+
+		case <-finished:
+		}
+	}()
+	return r
+}
+
+// recordingExtension returns the file extension of the given recording content
+// type. eg: ".webm" for "video/webm;codecs=vp9".
+func recordingExtension(contentType string) string {
+	_, subtype, ok := strings.Cut(contentType, "/")
+	if !ok {
+		return ""
+	}
+
+	subtype, _, _ = strings.Cut(subtype, ";")
+	if subtype = strings.TrimSpace(subtype); subtype == "" {
+		return ""
+	}
+	return "." + subtype
+}
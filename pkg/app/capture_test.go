@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaCaptureOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	onDone := func(Context, MediaStream, error) {
+		called = true
+	}
+
+	capture := makeContext(h).MediaCapture()
+	capture.Display(DisplayCaptureOptions{Audio: true}, onDone)
+	capture.Camera(CameraCaptureOptions{FacingMode: "user"}, onDone)
+
+	recorder := capture.Record(MediaStream{}, RecorderOptions{
+		OnDone: func(Context, Value, error) {
+			called = true
+		},
+	})
+	recorder.Pause()
+	recorder.Resume()
+	recorder.Stop()
+	d.Consume()
+
+	require.False(t, called)
+	require.Equal(t, "inactive", recorder.State())
+	require.False(t, capture.IsDisplaySupported())
+	require.False(t, capture.IsCameraSupported())
+	require.False(t, capture.IsRecordingSupported())
+	require.False(t, MediaStream{}.IsActive())
+	MediaStream{}.Stop()
+}
+
+func TestRecordingExtension(t *testing.T) {
+	utests := []struct {
+		contentType string
+		expected    string
+	}{
+		{contentType: "video/webm", expected: ".webm"},
+		{contentType: "video/webm;codecs=vp9", expected: ".webm"},
+		{contentType: "video/mp4; codecs=avc1", expected: ".mp4"},
+		{contentType: "audio/ogg", expected: ".ogg"},
+		{contentType: "", expected: ""},
+		{contentType: "video/", expected: ""},
+	}
+
+	for _, u := range utests {
+		t.Run(u.contentType, func(t *testing.T) {
+			require.Equal(t, u.expected, recordingExtension(u.contentType))
+		})
+	}
+}
@@ -125,6 +125,33 @@ type AppInstaller interface {
 	OnAppInstallChange(Context)
 }
 
+// AppInstalledHandler is the interface that describes a component that is
+// notified when the application has been installed from the browser.
+type AppInstalledHandler interface {
+	// The function called when the application has been installed. It is
+	// always called on the UI goroutine.
+	OnAppInstalled(Context)
+}
+
+// DisplayModeHandler is the interface that describes a component that is
+// notified when the display mode of the application changes, which happens
+// when the installed app is opened from the browser or displayed full screen.
+type DisplayModeHandler interface {
+	// The function called when the display mode changes. Use
+	// Context.DisplayMode to get the current display mode. It is always called
+	// on the UI goroutine.
+	OnDisplayModeChange(Context)
+}
+
+// AppLauncher is the interface that describes a component that is notified
+// when the installed application is launched from the operating system, with
+// a custom protocol or with files.
+type AppLauncher interface {
+	// The function called when the app is launched with the given params. It
+	// is always called on the UI goroutine.
+	OnAppLaunch(Context, LaunchParams)
+}
+
 // Resizer is the interface that describes a component that is notified when the
 // app has been resized or a parent component calls the ResizeContent() method.
 type Resizer interface {
@@ -267,6 +294,23 @@ func (c *Compo) children() []UI {
 }
 
 func (c *Compo) mount(d Dispatcher) error {
+	return c.mountRoot(d, func(root UI) error {
+		return mount(d, root)
+	})
+}
+
+func (c *Compo) hydrate(d Dispatcher, v Value) error {
+	return c.mountRoot(d, func(root UI) error {
+		// The parent is set before the root is hydrated in order to let
+		// outlets find their depth.
+		root.setParent(c.this)
+		return hydrate(d, root, v)
+	})
+}
+
+// mountRoot renders the component and mounts its root element with the given
+// function.
+func (c *Compo) mountRoot(d Dispatcher, mountRoot func(UI) error) error {
 	if c.Mounted() {
 		return errors.New("mounting component failed").
 			Tag("reason", "already mounted").
@@ -278,7 +322,7 @@ func (c *Compo) mount(d Dispatcher) error {
 	c.ctx, c.ctxCancel = context.WithCancel(context.Background())
 
 	root := c.render()
-	if err := mount(d, root); err != nil {
+	if err := mountRoot(root); err != nil {
 		return errors.New("mounting component failed").
 			Tag("name", c.name()).
 			Tag("kind", c.Kind()).
@@ -286,6 +330,8 @@ func (c *Compo) mount(d Dispatcher) error {
 	}
 	root.setParent(c.this)
 	c.root = root
+	d.bindHandlers(c.this)
+	c.updateHead()
 
 	if c.dispatcher().runsInServer() {
 		return nil
@@ -302,6 +348,9 @@ func (c *Compo) mount(d Dispatcher) error {
 func (c *Compo) dismount() {
 	dismount(c.root)
 	c.ctxCancel()
+	if _, ok := c.self().(HeadProvider); ok {
+		c.dispatcher().setHead(c.this, nil)
+	}
 
 	if dismounter, ok := c.this.(Dismounter); ok {
 		dismounter.OnDismount()
@@ -380,9 +429,16 @@ func (c *Compo) updateRoot() error {
 			Wrap(err)
 	}
 
+	c.updateHead()
 	return nil
 }
 
+func (c *Compo) updateHead() {
+	if p, ok := c.self().(HeadProvider); ok {
+		c.dispatcher().setHead(c.this, p.Head())
+	}
+}
+
 func (c *Compo) replaceRoot(n UI) error {
 	old := c.root
 	new := n
@@ -398,12 +454,9 @@ func (c *Compo) replaceRoot(n UI) error {
 			Wrap(err)
 	}
 
-	var parent UI
-	for {
-		parent = c.parent()
-		if parent == nil || parent.Kind() == HTML {
-			break
-		}
+	parent := c.parent()
+	for parent != nil && parent.Kind() != HTML {
+		parent = parent.parent()
 	}
 
 	if parent == nil {
@@ -466,6 +519,40 @@ func (c *Compo) onAppInstallChange() {
 	}
 }
 
+func (c *Compo) onVersionSkew() {
+	c.root.onVersionSkew()
+
+	if handler, ok := c.self().(VersionSkewHandler); ok {
+		c.dispatch(handler.OnVersionSkew)
+	}
+}
+
+func (c *Compo) onAppInstalled() {
+	c.root.onAppInstalled()
+
+	if handler, ok := c.self().(AppInstalledHandler); ok {
+		c.dispatch(handler.OnAppInstalled)
+	}
+}
+
+func (c *Compo) onDisplayModeChange() {
+	c.root.onDisplayModeChange()
+
+	if handler, ok := c.self().(DisplayModeHandler); ok {
+		c.dispatch(handler.OnDisplayModeChange)
+	}
+}
+
+func (c *Compo) onAppLaunch(p LaunchParams) {
+	c.root.onAppLaunch(p)
+
+	if launcher, ok := c.self().(AppLauncher); ok {
+		c.dispatch(func(ctx Context) {
+			launcher.OnAppLaunch(ctx, p)
+		})
+	}
+}
+
 func (c *Compo) onResize() {
 	defer c.root.onResize()
 
@@ -347,6 +347,69 @@ func TestNestedInComponentAppInstaller(t *testing.T) {
 	require.True(t, b.appInstalled)
 }
 
+func TestAppInstalledHandler(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	d.AppInstalled()
+	d.Consume()
+	require.True(t, h.appInstallCompleted)
+}
+
+func TestNestedInComponentAppInstalledHandler(t *testing.T) {
+	foo := &foo{Bar: "Bar"}
+	d := NewClientTester(foo)
+	defer d.Close()
+
+	d.AppInstalled()
+	d.Consume()
+	b := foo.children()[0].(*bar)
+	require.True(t, b.appInstallCompleted)
+}
+
+func TestDisplayModeHandler(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	d.DisplayModeChange()
+	d.Consume()
+	require.Equal(t, "browser", h.displayMode)
+}
+
+func TestNestedDisplayModeHandler(t *testing.T) {
+	h := &hello{}
+	div := Div().Body(h)
+	d := NewClientTester(div)
+	defer d.Close()
+
+	d.DisplayModeChange()
+	d.Consume()
+	require.Equal(t, "browser", h.displayMode)
+}
+
+func TestAppLauncher(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	d.AppLaunch(LaunchParams{URL: "web+myapp://orders/42"})
+	d.Consume()
+	require.Equal(t, "web+myapp://orders/42", h.launch.URL)
+}
+
+func TestNestedInComponentAppLauncher(t *testing.T) {
+	foo := &foo{Bar: "Bar"}
+	d := NewClientTester(foo)
+	defer d.Close()
+
+	d.AppLaunch(LaunchParams{URL: "web+myapp://orders/42"})
+	d.Consume()
+	b := foo.children()[0].(*bar)
+	require.Equal(t, "web+myapp://orders/42", b.launch.URL)
+}
+
 func TestResizer(t *testing.T) {
 	h := &hello{}
 	d := NewClientTester(h)
@@ -432,12 +495,15 @@ func TestUpdater(t *testing.T) {
 type hello struct {
 	Compo
 
-	Greeting     string
-	onNavURL     string
-	appUpdated   bool
-	appInstalled bool
-	appResized   bool
-	preRenderer  bool
+	Greeting            string
+	onNavURL            string
+	appUpdated          bool
+	appInstalled        bool
+	appInstallCompleted bool
+	displayMode         string
+	launch              LaunchParams
+	appResized          bool
+	preRenderer         bool
 }
 
 func (h *hello) OnMount(Context) {
@@ -455,6 +521,18 @@ func (h *hello) OnAppInstallChange(ctx Context) {
 	h.appInstalled = true
 }
 
+func (h *hello) OnAppInstalled(ctx Context) {
+	h.appInstallCompleted = true
+}
+
+func (h *hello) OnDisplayModeChange(ctx Context) {
+	h.displayMode = ctx.DisplayMode()
+}
+
+func (h *hello) OnAppLaunch(ctx Context, params LaunchParams) {
+	h.launch = params
+}
+
 func (h *hello) OnResize(ctx Context) {
 	h.appResized = true
 }
@@ -493,11 +571,13 @@ type bar struct {
 	Compo
 	Value string
 
-	onNavURL     string
-	appUpdated   bool
-	appInstalled bool
-	appRezized   bool
-	updated      bool
+	onNavURL            string
+	appUpdated          bool
+	appInstalled        bool
+	appInstallCompleted bool
+	launch              LaunchParams
+	appRezized          bool
+	updated             bool
 }
 
 func (b *bar) OnPreRender(ctx Context) {
@@ -516,6 +596,14 @@ func (b *bar) OnAppInstallChange(ctx Context) {
 	b.appInstalled = true
 }
 
+func (b *bar) OnAppInstalled(ctx Context) {
+	b.appInstallCompleted = true
+}
+
+func (b *bar) OnAppLaunch(ctx Context, params LaunchParams) {
+	b.launch = params
+}
+
 func (b *bar) OnResize(ctx Context) {
 	b.appRezized = true
 }
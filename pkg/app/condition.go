@@ -113,6 +113,12 @@ func (c condition) mount(Dispatcher) error {
 		Tag("kind", c.Kind())
 }
 
+func (c condition) hydrate(Dispatcher, Value) error {
+	return errors.New("condition is not hydratable").
+		Tag("name", c.name()).
+		Tag("kind", c.Kind())
+}
+
 func (c condition) dismount() {
 }
 
@@ -134,6 +140,18 @@ func (c condition) onAppInstallChange() {
 func (c condition) onResize() {
 }
 
+func (c condition) onVersionSkew() {
+}
+
+func (c condition) onAppInstalled() {
+}
+
+func (c condition) onDisplayModeChange() {
+}
+
+func (c condition) onAppLaunch(LaunchParams) {
+}
+
 func (c condition) preRender(Page) {
 }
 
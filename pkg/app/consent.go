@@ -0,0 +1,96 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// The name of the action posted when the user consent changes. The action
+	// value is the new Consent.
+	ConsentAction = "/app/consent"
+
+	// The consent category of the features required for the app to work. It
+	// is always granted.
+	ConsentNecessary = "necessary"
+
+	// The consent category of the features that remember user choices, such
+	// as language or theme.
+	ConsentPreferences = "preferences"
+
+	// The consent category of the features that measure how the app is used.
+	ConsentAnalytics = "analytics"
+
+	// The consent category of the features that track users for advertising.
+	ConsentMarketing = "marketing"
+
+	consentState = "/app/consent"
+)
+
+// Consent represents the consent choices made by the user.
+type Consent struct {
+	// The categories granted by the user.
+	Categories map[string]bool
+
+	// The time when the user made the choices.
+	UpdatedAt time.Time
+}
+
+// Decided reports whether the user made consent choices.
+func (c Consent) Decided() bool {
+	return !c.UpdatedAt.IsZero()
+}
+
+// Granted reports whether the given category is granted.
+func (c Consent) Granted(category string) bool {
+	return category == ConsentNecessary || c.Categories[category]
+}
+
+// ConsentGranted reports whether the given category is granted by the current
+// user consent. It is meant to gate features that are not executed in a
+// component context, such as analytics backends.
+//
+// It always returns false for categories other than ConsentNecessary when the
+// app runs on a server.
+func ConsentGranted(category string) bool {
+	consentMutex.RLock()
+	defer consentMutex.RUnlock()
+	return currentConsent.Granted(category)
+}
+
+var (
+	consentMutex   sync.RWMutex
+	currentConsent Consent
+)
+
+func loadConsent(d Dispatcher) Consent {
+	var c Consent
+	d.GetState(consentState, &c)
+
+	consentMutex.Lock()
+	currentConsent = c
+	consentMutex.Unlock()
+	return c
+}
+
+func storeConsent(d Dispatcher, categories []string) Consent {
+	c := Consent{
+		Categories: make(map[string]bool, len(categories)),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	for _, category := range categories {
+		c.Categories[category] = true
+	}
+
+	d.SetState(consentState, c, Persist, Broadcast)
+
+	consentMutex.Lock()
+	currentConsent = c
+	consentMutex.Unlock()
+
+	d.Post(Action{
+		Name:  ConsentAction,
+		Value: c,
+	})
+	return c
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsent(t *testing.T) {
+	c := Consent{}
+	require.False(t, c.Decided())
+	require.True(t, c.Granted(ConsentNecessary))
+	require.False(t, c.Granted(ConsentAnalytics))
+}
+
+func TestContextSetConsent(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var changed Consent
+	e.Handle(ConsentAction, h, func(ctx Context, a Action) {
+		changed = a.Value.(Consent)
+	})
+
+	ctx := makeContext(h)
+	ctx.SetConsent(ConsentAnalytics)
+	e.Wait()
+	e.Consume()
+	defer func() {
+		currentConsent = Consent{}
+	}()
+
+	consent := ctx.Consent()
+	require.True(t, consent.Decided())
+	require.True(t, consent.Granted(ConsentAnalytics))
+	require.False(t, consent.Granted(ConsentMarketing))
+	require.Equal(t, consent.Categories, changed.Categories)
+	require.True(t, ConsentGranted(ConsentAnalytics))
+	require.False(t, ConsentGranted(ConsentMarketing))
+}
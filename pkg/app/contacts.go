@@ -0,0 +1,206 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Contact describes a contact picked from the address book of the device.
+// Fields that were not requested or that the browser does not support are
+// empty.
+type Contact struct {
+	// The names of the contact.
+	Names []string
+
+	// The email addresses of the contact.
+	Emails []string
+
+	// The phone numbers of the contact.
+	Tels []string
+
+	// The postal addresses of the contact.
+	Addresses []ContactAddress
+
+	// The pictures of the contact, as JavaScript Blob values.
+	Icons []Value
+}
+
+// ContactAddress describes the postal address of a contact.
+type ContactAddress struct {
+	// The street address lines.
+	AddressLines []string
+
+	// The city or town.
+	City string
+
+	// The country code. eg: "FR".
+	Country string
+
+	// The dependent locality or sublocality, such as a neighborhood.
+	DependentLocality string
+
+	// The organization, firm, company or institution.
+	Organization string
+
+	// The phone number of the recipient.
+	Phone string
+
+	// The postal code or ZIP code.
+	PostalCode string
+
+	// The name of the recipient.
+	Recipient string
+
+	// The top level administrative subdivision, such as a state or a region.
+	Region string
+
+	// The sorting code used in some countries, such as France.
+	SortingCode string
+}
+
+// ContactPickerOptions describes how contacts are picked with Contacts.Select.
+type ContactPickerOptions struct {
+	// The contact properties to retrieve: "name", "email", "tel", "address"
+	// or "icon". The properties that are not supported by the browser are
+	// ignored.
+	//
+	// Default: "name", "email" and "tel".
+	Properties []string
+
+	// Reports whether the user can pick several contacts.
+	Multiple bool
+}
+
+// Contacts picks contacts from the address book of the device with the
+// Contact Picker API. It is mostly available on mobile browsers.
+//
+// Operations are asynchronous. Their result is passed to the given function,
+// called on the UI goroutine. Operations do nothing on the server.
+type Contacts struct {
+	ctx Context
+}
+
+// IsSupported reports whether the browser can pick contacts. It should be used
+// to display an alternative, such as a regular input, when contacts can't be
+// picked.
+func (c Contacts) IsSupported() bool {
+	return !IsServer && Window().Get("navigator").Get("contacts").Truthy()
+}
+
+// Select displays the contact picker. It must be called from a user gesture,
+// like a click handler. The returned contacts are empty when the user
+// dismissed the picker.
+func (c Contacts) Select(opts ContactPickerOptions, onDone func(Context, []Contact, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(contacts []Contact, err error) {
+		if err != nil {
+			err = errors.New("selecting contacts failed").Wrap(err)
+		}
+		dispatchResult(c.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, contacts, err)
+		})
+	}
+	fail := func(reason Value) {
+		done(nil, jsReasonError(reason))
+	}
+
+	contacts := Window().Get("navigator").Get("contacts")
+	if !contacts.Truthy() {
+		done(nil, errors.New("contact picker is not supported"))
+		return
+	}
+
+	properties := opts.Properties
+	if len(properties) == 0 {
+		properties = []string{"name", "email", "tel"}
+	}
+
+	awaitPromise(contacts.Call("getProperties"), func(supported Value) {
+		var props []interface{}
+		for _, p := range properties {
+			if jsArrayContains(supported, p) {
+				props = append(props, p)
+			}
+		}
+		if len(props) == 0 {
+			done(nil, errors.New("contact properties are not supported").
+				Tag("properties", properties))
+			return
+		}
+
+		awaitPromise(contacts.Call("select", props, map[string]interface{}{
+			"multiple": opts.Multiple,
+		}), func(selected Value) {
+			var contacts []Contact
+			if selected.Truthy() {
+				contacts = make([]Contact, selected.Length())
+				for i := range contacts {
+					contacts[i] = makeContact(selected.Index(i))
+				}
+			}
+			done(contacts, nil)
+		}, fail)
+	}, fail)
+}
+
+func makeContact(v Value) Contact {
+	c := Contact{
+		Names:  jsStrings(v.Get("name")),
+		Emails: jsStrings(v.Get("email")),
+		Tels:   jsStrings(v.Get("tel")),
+	}
+
+	if addresses := v.Get("address"); addresses.Truthy() {
+		c.Addresses = make([]ContactAddress, addresses.Length())
+		for i := range c.Addresses {
+			a := addresses.Index(i)
+			c.Addresses[i] = ContactAddress{
+				AddressLines:      jsStrings(a.Get("addressLine")),
+				City:              jsOptionalString(a.Get("city")),
+				Country:           jsOptionalString(a.Get("country")),
+				DependentLocality: jsOptionalString(a.Get("dependentLocality")),
+				Organization:      jsOptionalString(a.Get("organization")),
+				Phone:             jsOptionalString(a.Get("phone")),
+				PostalCode:        jsOptionalString(a.Get("postalCode")),
+				Recipient:         jsOptionalString(a.Get("recipient")),
+				Region:            jsOptionalString(a.Get("region")),
+				SortingCode:       jsOptionalString(a.Get("sortingCode")),
+			}
+		}
+	}
+
+	if icons := v.Get("icon"); icons.Truthy() {
+		c.Icons = make([]Value, icons.Length())
+		for i := range c.Icons {
+			c.Icons[i] = icons.Index(i)
+		}
+	}
+	return c
+}
+
+func jsStrings(v Value) []string {
+	if !v.Truthy() {
+		return nil
+	}
+
+	s := make([]string, v.Length())
+	for i := range s {
+		s[i] = v.Index(i).String()
+	}
+	return s
+}
+
+func jsArrayContains(v Value, s string) bool {
+	if !v.Truthy() {
+		return false
+	}
+
+	for i := 0; i < v.Length(); i++ {
+		if v.Index(i).String() == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactsOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	contacts := makeContext(h).Contacts()
+	contacts.Select(ContactPickerOptions{Multiple: true}, func(Context, []Contact, error) {
+		called = true
+	})
+	d.Consume()
+	require.False(t, called)
+	require.False(t, contacts.IsSupported())
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"encoding/json"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	contentIndexCacheName  = "goapp-content-index"
+	contentIndexRecordPath = "/app-content-index/"
+)
+
+// ContentIndexItem describes a page that is available offline and that is
+// listed in the offline content UI of the browser. ID, Title, Description and
+// URL are required.
+type ContentIndexItem struct {
+	// The item id.
+	ID string
+
+	// The item title.
+	Title string
+
+	// The item description.
+	Description string
+
+	// The path or URL of the page that displays the item. The page is stored
+	// in the service worker cache when the item is indexed.
+	URL string
+
+	// The category of the item. eg: "homepage", "article", "video" or
+	// "audio".
+	Category string
+
+	// The paths or URLs of the icons that represent the item.
+	Icons []string
+}
+
+func (i ContentIndexItem) toJS() map[string]interface{} {
+	icons := make([]interface{}, len(i.Icons))
+	for n, src := range i.Icons {
+		icons[n] = map[string]interface{}{"src": src}
+	}
+
+	return map[string]interface{}{
+		"id":          i.ID,
+		"title":       i.Title,
+		"description": i.Description,
+		"url":         i.URL,
+		"category":    i.Category,
+		"icons":       icons,
+	}
+}
+
+func indexContent(ctx Context, item ContentIndexItem, onDone func(Context, error)) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	done := func(err error) {
+		if err != nil {
+			err = errors.New("indexing content failed").
+				Tag("id", item.ID).
+				Tag("url", item.URL).
+				Wrap(err)
+		}
+		dispatchResult(ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, err)
+		})
+	}
+	fail := func(reason Value) {
+		done(jsReasonError(reason))
+	}
+
+	record, err := json.Marshal(contentIndexRecord{URL: item.URL})
+	if err != nil {
+		done(err)
+		return
+	}
+
+	withContentIndex(func(index, cache Value) {
+		awaitPromise(cache.Call("add", item.URL), func(Value) {
+			res := Window().Get("Response").New(string(record), map[string]interface{}{
+				"headers": map[string]interface{}{"Content-Type": "application/json"},
+			})
+
+			awaitPromise(cache.Call("put", contentIndexRecordURL(item.ID), res), func(Value) {
+				awaitPromise(index.Call("add", item.toJS()), func(Value) {
+					done(nil)
+				}, fail)
+			}, fail)
+		}, fail)
+	}, done)
+}
+
+func unindexContent(ctx Context, id string, onDone func(Context, error)) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	done := func(err error) {
+		if err != nil {
+			err = errors.New("unindexing content failed").
+				Tag("id", id).
+				Wrap(err)
+		}
+		dispatchResult(ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, err)
+		})
+	}
+	fail := func(reason Value) {
+		done(jsReasonError(reason))
+	}
+
+	withContentIndex(func(index, cache Value) {
+		awaitPromise(index.Call("delete", id), func(Value) {
+			record := contentIndexRecordURL(id)
+
+			awaitPromise(cache.Call("match", record), func(res Value) {
+				if !res.Truthy() {
+					done(nil)
+					return
+				}
+
+				awaitPromise(res.Call("json"), func(data Value) {
+					cache.Call("delete", data.Get("url"))
+					cache.Call("delete", record)
+					done(nil)
+				}, fail)
+			}, fail)
+		}, fail)
+	}, done)
+}
+
+func listIndexedContent(ctx Context, onDone func(Context, []ContentIndexItem, error)) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	done := func(items []ContentIndexItem, err error) {
+		if err != nil {
+			err = errors.New("listing indexed content failed").Wrap(err)
+		}
+		dispatchResult(ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, items, err)
+		})
+	}
+
+	withContentIndex(func(index, cache Value) {
+		awaitPromise(index.Call("getAll"), func(all Value) {
+			items := make([]ContentIndexItem, all.Length())
+			for i := range items {
+				items[i] = makeContentIndexItem(all.Index(i))
+			}
+			done(items, nil)
+		}, func(reason Value) {
+			done(nil, jsReasonError(reason))
+		})
+	}, func(err error) {
+		done(nil, err)
+	})
+}
+
+// withContentIndex calls fn with the content index of the active service
+// worker registration and the cache that stores the indexed pages.
+func withContentIndex(fn func(index, cache Value), onErr func(error)) {
+	withServiceWorkerRegistration(func(reg Value) {
+		index := reg.Get("index")
+		caches := Window().Get("caches")
+		if !index.Truthy() || !caches.Truthy() {
+			onErr(errors.New("content index is not supported"))
+			return
+		}
+
+		awaitPromise(caches.Call("open", contentIndexCacheName), func(cache Value) {
+			fn(index, cache)
+		}, func(reason Value) {
+			onErr(jsReasonError(reason))
+		})
+	}, onErr)
+}
+
+// contentIndexRecord is stored in the content index cache in order to remove
+// the page of an item when the item is deleted from the browser offline
+// content UI.
+type contentIndexRecord struct {
+	URL string `json:"url"`
+}
+
+// contentIndexRecordURL returns the URL of the record of the item with the
+// given id. It is encoded the same way as in the service worker.
+func contentIndexRecordURL(id string) string {
+	return rootPrefix + contentIndexRecordPath + Window().Call("encodeURIComponent", id).String()
+}
+
+func makeContentIndexItem(v Value) ContentIndexItem {
+	item := ContentIndexItem{
+		ID:          v.Get("id").String(),
+		Title:       jsOptionalString(v.Get("title")),
+		Description: jsOptionalString(v.Get("description")),
+		URL:         jsOptionalString(v.Get("url")),
+		Category:    jsOptionalString(v.Get("category")),
+	}
+
+	if icons := v.Get("icons"); icons.Truthy() {
+		for i := 0; i < icons.Length(); i++ {
+			item.Icons = append(item.Icons, icons.Index(i).Get("src").String())
+		}
+	}
+	return item
+}
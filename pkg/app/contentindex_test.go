@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentIndexOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	onDone := func(Context, error) { called = true }
+
+	ctx := makeContext(h)
+	ctx.IndexContent(ContentIndexItem{
+		ID:    "article-42",
+		Title: "Article 42",
+		URL:   "/articles/42",
+	}, onDone)
+	ctx.UnindexContent("article-42", onDone)
+	ctx.IndexedContent(func(Context, []ContentIndexItem, error) { called = true })
+	d.Consume()
+	require.False(t, called)
+}
+
+func TestContentIndexItemToJS(t *testing.T) {
+	item := ContentIndexItem{
+		ID:       "article-42",
+		Title:    "Article 42",
+		URL:      "/articles/42",
+		Category: "article",
+		Icons:    []string{"/web/icon.png"},
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"id":          "article-42",
+		"title":       "Article 42",
+		"description": "",
+		"url":         "/articles/42",
+		"category":    "article",
+		"icons": []interface{}{
+			map[string]interface{}{"src": "/web/icon.png"},
+		},
+	}, item.toJS())
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/url"
 	"strings"
 	"time"
@@ -31,9 +32,27 @@ type Context interface {
 	// to load the updated version.
 	AppUpdateAvailable() bool
 
+	// Returns the version of the app served by the server when it differs
+	// from the running one. It returns an empty string until a version skew
+	// is detected. Components are notified of version skews with
+	// OnVersionSkew. Eg:
+	//  func (c *banner) OnVersionSkew(ctx app.Context) {
+	//      c.newVersion = ctx.ServerVersion()
+	//  }
+	ServerVersion() string
+
 	// Reports whether the app is installable.
 	IsAppInstallable() bool
 
+	// Returns the display mode of the app: "browser" when displayed in a
+	// browser tab, or "standalone", "minimal-ui", "fullscreen" or
+	// "window-controls-overlay" when displayed as an installed app. It
+	// returns "browser" on the server. Eg:
+	//  func (h *header) OnDisplayModeChange(ctx app.Context) {
+	//      h.showInstallButton = ctx.DisplayMode() == "browser"
+	//  }
+	DisplayMode() string
+
 	// Shows the app install prompt if the app is installable.
 	ShowAppInstallPrompt()
 
@@ -48,12 +67,59 @@ type Context interface {
 	// context's nearest component to update its state.
 	Defer(fn func(Context))
 
+	// Executes the given function on the UI goroutine with the given priority
+	// and notifies the context's nearest component to update its state.
+	// Pending dispatches with a higher priority are executed first.
+	//
+	// Eg:
+	//  ctx.PriorityDispatch(app.LowPriority, func(ctx app.Context) {
+	//      c.items = append(c.items, items...)
+	//  })
+	PriorityDispatch(p DispatchPriority, fn func(Context))
+
+	// Executes the given function on the UI goroutine with the given priority
+	// after notifying the context's nearest component to update its state.
+	// Deferred functions with a higher priority are executed first.
+	PriorityDefer(p DispatchPriority, fn func(Context))
+
+	// Executes the given function on the UI goroutine once the browser has
+	// painted the frame that follows the context's nearest component update.
+	AfterPaint(fn func(Context))
+
+	// Executes the given function on the UI goroutine once the fonts of the
+	// page are loaded, which lets layout-sensitive components measure their
+	// elements with their final fonts. It does nothing on the server.
+	//
+	// Eg:
+	//  func (c *myCompo) OnMount(ctx app.Context) {
+	//      ctx.FontsReady(func(ctx app.Context) {
+	//          c.width = app.Window().GetElementByID("title").Get("offsetWidth").Int()
+	//      })
+	//  }
+	FontsReady(fn func(Context))
+
 	// Registers the handler for the given action name. When an action occurs,
 	// the handler is executed on the UI goroutine.
+	//
+	// The action name can be a pattern where "*" matches exactly one segment
+	// and "#" matches zero or more segments. eg: "cart.*", "user.#".
 	Handle(actionName string, h ActionHandler)
 
+	// Registers the handler for the given action name. Unlike Handle, the
+	// handler is bound to the type of the context's nearest component rather
+	// than to the component itself: it is kept when the component is
+	// dismounted and is bound again to the next mounted component of the same
+	// type, typically when a page is revisited.
+	//
+	// Since the handler outlives the component that registered it, it should
+	// access the component with Context.Src() rather than a captured
+	// receiver. Registering a durable handler of the same action name from a
+	// component of the same type replaces the previous one.
+	HandleDurable(actionName string, h ActionHandler)
+
 	// Creates an action with optional tags, to be handled with Context.Handle.
-	// Eg:
+	// When the name is a pattern, the action is handled by the handlers
+	// registered with a name that matches the pattern. Eg:
 	//  ctx.NewAction("myAction")
 	//  ctx.NewAction("myAction", app.T("purpose", "test"))
 	//  ctx.NewAction("myAction", app.Tags{
@@ -72,6 +138,44 @@ type Context interface {
 	//  })
 	NewActionWithValue(name string, v interface{}, tags ...Tagger)
 
+	// Records an event with optional properties by posting a TrackAction
+	// action. Tracked events are typically forwarded to analytics backends
+	// with a handler registered for TrackAction. Eg:
+	//  ctx.Track("signup", map[string]interface{}{"plan": "pro"})
+	Track(event string, properties map[string]interface{})
+
+	// Returns the consent choices made by the user.
+	Consent() Consent
+
+	// Grants the given consent categories and denies the others. The choices
+	// are persisted in local storage and a ConsentAction action is posted.
+	SetConsent(categories ...string)
+
+	// Loads the external script located at the given URL. The script is
+	// injected only once, subsequent calls report the result of the first
+	// load. A script that failed to load is injected again. Eg:
+	//  ctx.LoadScript("https://js.stripe.com/v3", app.ScriptOptions{
+	//      Async:  true,
+	//      OnLoad: func(ctx app.Context) { ... },
+	//  })
+	LoadScript(url string, opts ScriptOptions)
+
+	// Removes the external script located at the given URL. The script can
+	// be loaded again with Context.LoadScript.
+	RemoveScript(url string)
+
+	// Animates the given element with the Web Animations API. Completion
+	// callbacks are executed on the UI goroutine and update the context's
+	// nearest component. Eg:
+	//  ctx.Animate(c.panel, []app.Keyframe{
+	//      {Styles: map[string]string{"opacity": "0"}},
+	//      {Styles: map[string]string{"opacity": "1"}},
+	//  }, app.AnimationOptions{
+	//      Duration: time.Millisecond * 200,
+	//      OnFinish: func(ctx app.Context) { ... },
+	//  })
+	Animate(elem UI, keyframes []Keyframe, opts AnimationOptions) Animation
+
 	// Executes the given function on a new goroutine.
 	//
 	// The difference versus just launching a goroutine is that it ensures that
@@ -83,12 +187,31 @@ type Context interface {
 	// function.
 	After(d time.Duration, fn func(Context))
 
+	// Dispatches the given function at each interval of the given duration,
+	// until the context's UI element is dismounted. Calls are skipped while
+	// the engine is suspended because the page is hidden.
+	//
+	// Does not work when pre-rendering.
+	Every(d time.Duration, fn func(Context))
+
+	// Dispatches the given function when the browser is idle, in order to not
+	// compete with user interactions. The deadline indicates the time until
+	// which the idle period is estimated to last.
+	//
+	// It uses requestIdleCallback when available and falls back to a timer
+	// otherwise. Does not work when pre-rendering.
+	WhenIdle(fn func(ctx Context, deadline time.Time))
+
 	// Executes the given function and notifies the parent components to update
 	// their state. It should be used to launch component custom event handlers.
 	Emit(fn func())
 
 	// Reloads the WebAssembly app to the current page. It is like refreshing
 	// the browser page.
+	//
+	// When an app update is available, the scroll positions and the values of
+	// the form controls that have an ID are restored once the updated app is
+	// loaded.
 	Reload()
 
 	// Navigates to the given URL. This is a helper method that converts url to
@@ -98,6 +221,46 @@ type Context interface {
 	// Navigates to the given URL.
 	NavigateTo(u *url.URL)
 
+	// Redirects to the given URL with the given 3xx status code. When a page
+	// is pre-rendered, the server responds with the redirection instead of the
+	// page. In the browser, it navigates to the given URL and replaces the
+	// current history entry. Codes that are not 3xx are replaced by 302. Eg:
+	//  func (p *account) OnPreRender(ctx app.Context) {
+	//      if !ctx.Claims().IsAuthorized() {
+	//          ctx.Redirect("/login", http.StatusSeeOther)
+	//      }
+	//  }
+	Redirect(url string, code int)
+
+	// Stores the data loaded by the loader of the current route into the given
+	// receiver, or returns the error of the loader. The receiver is set to its
+	// zero value when the route does not have a loader. Eg:
+	//  func (p *article) OnMount(ctx app.Context) {
+	//      if err := ctx.RouteData(&p.article); err != nil {
+	//          p.err = err
+	//      }
+	//  }
+	RouteData(recv interface{}) error
+
+	// Stores the data loaded by the parent loader of the given route into the
+	// given receiver, or returns its error. See Loader.Parents.
+	ParentRouteData(route string, recv interface{}) error
+
+	// Returns the value of the given parameter of the current route pattern,
+	// or of the given named group of the current route regular expression.
+	// It is empty when the route does not have the parameter. Eg:
+	//  app.Route("/users/:id/posts/*rest", &userPosts{})
+	//
+	//  func (p *userPosts) OnNav(ctx app.Context) {
+	//      p.userID = ctx.RouteParam("id")
+	//      p.postPath = ctx.RouteParam("rest")
+	//  }
+	RouteParam(name string) string
+
+	// Navigates to the previous page in the browser history. It is like
+	// clicking the browser back button.
+	NavigateBack()
+
 	// Resolves the given path to make it point to the right location whether
 	// static resources are located on a local directory or a remote bucket.
 	ResolveStaticResource(string) string
@@ -113,6 +276,175 @@ type Context interface {
 	// Scrolls to the HTML element with the given id.
 	ScrollTo(id string)
 
+	// Makes the browser download the content read from the given reader as a
+	// file with the given name and content type. Eg:
+	//  var b bytes.Buffer
+	//  export.CSV(&b, table)
+	//  ctx.Download("orders.csv", export.CSVContentType, &b)
+	Download(filename, contentType string, r io.Reader) error
+
+	// Downloads the file at the given URL with the fetch API and saves it with
+	// the given name. The file is received as a stream, which allows to report
+	// the download progress and to write large files to disk without
+	// buffering them in memory. The download is aborted when the context's
+	// component is dismounted. Eg:
+	//  ctx.DownloadURL("/web/report.pdf", "report.pdf", app.DownloadOptions{
+	//      SaveToDisk: true,
+	//      OnProgress: func(ctx app.Context, p app.DownloadProgress) {
+	//          c.progress = p.Progress()
+	//      },
+	//      OnDone: func(ctx app.Context, err error) { ... },
+	//  })
+	DownloadURL(url, filename string, opts DownloadOptions)
+
+	// Downloads the files located at the given URLs with the service worker.
+	// Unlike DownloadURL, the download continues when the app is closed.
+	// Downloaded files are stored in the service worker cache and are served
+	// when they are requested, including offline.
+	//
+	// The progress of the fetch is reported with BackgroundFetchAction
+	// actions. The fetches that progressed or ended while the app was closed
+	// are reported when it is opened again.
+	// Eg:
+	//  ctx.BackgroundFetch("season-1", []string{
+	//      "/web/videos/episode-1.mp4",
+	//      "/web/videos/episode-2.mp4",
+	//  }, app.BackgroundFetchOptions{
+	//      Title:         "Season 1",
+	//      DownloadTotal: 1 << 30,
+	//      OnStart:       func(ctx app.Context, err error) { ... },
+	//  })
+	//
+	//  ctx.Handle(app.BackgroundFetchAction, func(ctx app.Context, a app.Action) {
+	//      p := a.Value.(app.BackgroundFetchProgress)
+	//      ...
+	//  })
+	BackgroundFetch(id string, urls []string, opts BackgroundFetchOptions)
+
+	// Aborts the background fetch with the given id.
+	AbortBackgroundFetch(id string)
+
+	// Stores the page of the given item in the service worker cache and lists
+	// it in the offline content UI of the browser. The page is removed from
+	// the cache when the user deletes the item from the browser UI.
+	// Eg:
+	//  ctx.IndexContent(app.ContentIndexItem{
+	//      ID:          "article-42",
+	//      Title:       "Go app",
+	//      Description: "Build progressive web apps with Go.",
+	//      URL:         "/articles/42",
+	//      Category:    "article",
+	//      Icons:       []string{"/web/icon.png"},
+	//  }, func(ctx app.Context, err error) { ... })
+	IndexContent(item ContentIndexItem, onDone func(Context, error))
+
+	// Removes the item with the given id from the offline content UI of the
+	// browser, and its page from the service worker cache.
+	UnindexContent(id string, onDone func(Context, error))
+
+	// Calls the given function with the items listed in the offline content UI
+	// of the browser.
+	IndexedContent(fn func(Context, []ContentIndexItem, error))
+
+	// Uploads the given JavaScript File in chunks. Chunks are uploaded in
+	// parallel and retried when they fail, and the upload is resumed when the
+	// browser comes back online or when the same file is uploaded again. The
+	// upload is aborted when the context's component is dismounted.
+	//
+	// Files are sent to the endpoint enabled with Handler.Uploads by default.
+	// Eg:
+	//  func (c *uploader) onChange(ctx app.Context, e app.Event) {
+	//      file := e.Get("target").Get("files").Index(0)
+	//      ctx.Upload(file, app.UploadOptions{
+	//          OnProgress: func(ctx app.Context, p app.UploadProgress) {
+	//              c.progress = p.Progress()
+	//          },
+	//          OnDone: func(ctx app.Context, id string, err error) { ... },
+	//      })
+	//  }
+	Upload(file Value, opts UploadOptions)
+
+	// Resizes, rotates and strips the metadata of the given JavaScript File
+	// image in a web worker, without blocking the UI thread. Eg:
+	//  ctx.ProcessImage(file, app.ImageOptions{
+	//      MaxWidth: 1920,
+	//      OnDone: func(ctx app.Context, img app.ProcessedFile, err error) {
+	//          ctx.Upload(img.File, app.UploadOptions{})
+	//      },
+	//  })
+	ProcessImage(file Value, opts ImageOptions)
+
+	// Renders a page of the given JavaScript File PDF as an image in a web
+	// worker, with pdf.js.
+	RenderPDFPage(file Value, opts PDFPageOptions)
+
+	// Tracks which of the given sections are visible and stores the ID of the
+	// active one in the given state. Tracking stops when the context's
+	// component is dismounted. Eg:
+	//  func (d *doc) OnMount(ctx app.Context) {
+	//      ctx.SpyScroll(app.ScrollSpy{
+	//          State:    "/doc/section",
+	//          Sections: []string{"intro", "install", "usage"},
+	//      })
+	//      ctx.ObserveState("/doc/section").Value(&d.activeSection)
+	//  }
+	SpyScroll(s ScrollSpy)
+
+	// Observes the window scroll position stored in ScrollState. The
+	// position is read at most once per animation frame and shared by all the
+	// observers, which avoids registering a scroll listener per component.
+	// Eg:
+	//  func (h *header) OnMount(ctx app.Context) {
+	//      ctx.ObserveScroll().Value(&h.scroll)
+	//  }
+	ObserveScroll() Observer
+
+	// Reports whether the user asked the system to minimize non-essential
+	// motion. Animations started with Animate are then completed
+	// immediately, unless they are essential. It is always false on the
+	// server.
+	PrefersReducedMotion() bool
+
+	// Observes whether the user prefers reduced motion, stored as a bool in
+	// ReducedMotionState. Eg:
+	//  func (c *carousel) OnMount(ctx app.Context) {
+	//      ctx.ObserveReducedMotion().Value(&c.reducedMotion)
+	//  }
+	ObserveReducedMotion() Observer
+
+	// Returns the contrast requested by the user with the prefers-contrast
+	// media feature: "more", "less", "custom" or "no-preference". It is
+	// always "no-preference" on the server.
+	PrefersContrast() string
+
+	// Observes the contrast requested by the user, stored as a string in
+	// ContrastState. Eg:
+	//  func (t *theme) OnMount(ctx app.Context) {
+	//      ctx.ObserveContrast().Value(&t.contrast)
+	//  }
+	ObserveContrast() Observer
+
+	// Returns an element ID that is derived from the position of the source
+	// in the tree of the page component. The same ID is generated when a page
+	// is pre-rendered on the server and when it is mounted on the client,
+	// which keeps relations such as label/for or aria-describedby valid
+	// across hydration. Eg:
+	//  func (f *field) OnPreRender(ctx app.Context) {
+	//      f.id = ctx.StableID("email")
+	//  }
+	//
+	//  func (f *field) OnMount(ctx app.Context) {
+	//      f.id = ctx.StableID("email")
+	//  }
+	//
+	//  func (f *field) Render() app.UI {
+	//      return app.Div().Body(
+	//          app.Label().For(f.id).Text("Email"),
+	//          app.Input().ID(f.id).Type("email"),
+	//      )
+	//  }
+	StableID(prefix string) string
+
 	// Returns a UUID that identifies the app on the current device.
 	DeviceID() string
 
@@ -122,6 +454,260 @@ type Context interface {
 	// Decrypts the given encrypted bytes and stores them in the given value.
 	Decrypt(crypted []byte, v interface{}) error
 
+	// Returns the cryptographic operations implemented by the browser with
+	// WebCrypto. Eg:
+	//  ctx.Crypto().GenerateKey(app.AESGCM, func(ctx app.Context, k app.CryptoKey, err error) {
+	//      ctx.Crypto().StoreKey("/notes/key", k, nil)
+	//  })
+	Crypto() WebCrypto
+
+	// Returns the passkey operations implemented by the browser with
+	// WebAuthn. Eg:
+	//  ctx.WebAuthn().Authenticate(app.WebAuthnAuthenticateOptions{
+	//      Challenge: c.challenge,
+	//      OnDone: func(ctx app.Context, a app.WebAuthnAssertion, err error) {
+	//          // Send the assertion to the server.
+	//      },
+	//  })
+	WebAuthn() WebAuthn
+
+	// Returns the password operations implemented by the browser with the
+	// Credential Management API. Eg:
+	//  ctx.Credentials().StorePassword(app.PasswordCredential{
+	//      ID:       c.username,
+	//      Password: c.password,
+	//  }, nil)
+	Credentials() Credentials
+
+	// Returns the contact picker implemented by the browser with the Contact
+	// Picker API. Eg:
+	//  func (c *invite) onPickContact(ctx app.Context, e app.Event) {
+	//      ctx.Contacts().Select(app.ContactPickerOptions{
+	//          Properties: []string{"name", "email"},
+	//      }, func(ctx app.Context, contacts []app.Contact, err error) {
+	//          ...
+	//      })
+	//  }
+	Contacts() Contacts
+
+	// Returns the idle detection API that reports whether the user is idle
+	// and whether the screen is locked. Eg:
+	//  func (p *presence) OnMount(ctx app.Context) {
+	//      ctx.Idle().Observe(time.Minute).Value(&p.idle)
+	//  }
+	Idle() IdleDetection
+
+	// Returns the media capture API that captures the screen and the camera,
+	// and records the captured streams. Eg:
+	//  func (r *recorder) onRecord(ctx app.Context, e app.Event) {
+	//      ctx.MediaCapture().Display(app.DisplayCaptureOptions{}, func(ctx app.Context, s app.MediaStream, err error) {
+	//          ...
+	//          r.recorder = ctx.MediaCapture().Record(s, app.RecorderOptions{
+	//              Upload: &app.UploadOptions{},
+	//          })
+	//      })
+	//  }
+	MediaCapture() MediaCapture
+
+	// Returns the native window of an app that is shipped as a desktop binary
+	// with RunDesktop. Eg:
+	//  if ctx.Desktop().IsDesktop() {
+	//      ctx.Desktop().SetTitle("Untitled - Editor")
+	//  }
+	Desktop() DesktopWindow
+
+	// Returns how the installed app was launched from the operating system,
+	// with a custom protocol set in Handler.ProtocolHandlers or with files
+	// set in Handler.FileHandlers. It is zero when the app was not launched
+	// this way or on the server. Eg:
+	//  for _, f := range ctx.LaunchParams().Files {
+	//      f.Read(ctx, func(ctx app.Context, data []byte, err error) {
+	//          // ...
+	//      })
+	//  }
+	LaunchParams() LaunchParams
+
+	// Returns the server session of the request being prerendered. It returns
+	// nil in the browser and when Handler.Sessions is not set. The methods of
+	// a nil session are no-ops. Eg:
+	//  func (p *profile) OnPreRender(ctx app.Context) {
+	//      ctx.Session().Get("user", &p.user)
+	//  }
+	Session() *Session
+
+	// Returns a read-only view of the HTTP request of the page being
+	// prerendered, with its headers, its cookies and the address of the
+	// client. It reports false in the browser. Pages that read the request are
+	// not cached. Eg:
+	//  func (p *home) OnPreRender(ctx app.Context) {
+	//      if r, ok := ctx.Request(); ok {
+	//          p.isMobile = strings.Contains(r.UserAgent(), "Mobile")
+	//      }
+	//  }
+	Request() (Request, bool)
+
+	// Returns the value of the environment variable named by the given key,
+	// as set in Handler.Env. It returns an empty string when the variable is
+	// not set. Eg:
+	//  apiURL := ctx.Env("API_URL")
+	Env(k string) string
+
+	// Reports whether the feature flag with the given name is on for the
+	// current user. Flags are configured with SetFeatureFlags. Eg:
+	//  if ctx.Flag("new-checkout") {
+	//      // ...
+	//  }
+	Flag(name string) bool
+
+	// Creates an observer that binds the value of the feature flag with the
+	// given name to a boolean. The component is updated each time the flag
+	// value changes. Eg:
+	//  func (c *checkout) OnMount(ctx app.Context) {
+	//      ctx.ObserveFlag("new-checkout").Value(&c.newCheckout)
+	//  }
+	ObserveFlag(name string) Observer
+
+	// Sets the key of the user that feature flags are evaluated for, which is
+	// typically the user ID once logged in. An anonymous key persisted in
+	// local storage is used when the key is empty.
+	SetFlagUser(key string)
+
+	// Returns the name of the variant of the A/B experiment with the given
+	// name that is assigned to the current user. Experiments are configured
+	// with SetFeatureFlags. It returns an empty string when the experiment
+	// does not exist.
+	//
+	// Variants are assigned during prerendering and kept in a cookie: the
+	// app always displays the variant of the prerendered page. Eg:
+	//  func (c *checkout) OnMount(ctx app.Context) {
+	//      c.variant = ctx.Experiment("checkout-button")
+	//  }
+	Experiment(name string) string
+
+	// Creates a mark with the given name in the browser performance
+	// timeline. It does nothing on the server. Eg:
+	//  ctx.PerfMark("search-start")
+	PerfMark(name string)
+
+	// Creates a measure with the given name between the given marks in the
+	// browser performance timeline, and returns its duration. The measure
+	// ends now when end is empty.
+	//
+	// Measures are recorded as spans by the tracer set with SetClientTracer
+	// and are sent to Handler.ClientReports. Mounts, navigations and the
+	// first update are measured automatically with PerfMeasureMount,
+	// PerfMeasureNav and PerfMeasureFirstUpdate. Eg:
+	//  ctx.PerfMark("search-start")
+	//  // ...
+	//  ctx.PerfMeasure("search", "search-start", "")
+	PerfMeasure(name, start, end string) time.Duration
+
+	// Returns the memory used by the app, including the Go heap and the size
+	// of the WebAssembly memory.
+	//
+	// When the app is served from a local host, a warning is logged once the
+	// WebAssembly memory approaches the limits of mobile browsers. Eg:
+	//  stats := ctx.MemoryStats()
+	//  app.Logf("heap: %v, wasm: %v", stats.HeapAlloc, stats.WasmMemory)
+	MemoryStats() MemoryStats
+
+	// Asynchronously measures the memory used by the page with
+	// performance.measureUserAgentSpecificMemory, which requires the page to
+	// be cross-origin isolated. The given function is called on the UI
+	// goroutine with the number of bytes, or with an error when the
+	// measurement is not available. It does nothing on the server. Eg:
+	//  ctx.MeasureMemory(func(ctx app.Context, bytes uint64, err error) {
+	//      // ...
+	//  })
+	MeasureMemory(onDone func(ctx Context, bytes uint64, err error))
+
+	// Reports whether states saved before the previous app instance crashed
+	// can be restored with RestoreCrashState.
+	HasCrashState() bool
+
+	// Restores the states set with the Snapshot option that were saved in
+	// session storage before the previous app instance crashed, and notifies
+	// their observers. It reports whether states were restored. Eg:
+	//  func (e *editor) OnMount(ctx app.Context) {
+	//      ctx.ObserveState("/draft").Value(&e.draft)
+	//      if ctx.HasCrashState() {
+	//          e.showRestorePrompt = true
+	//      }
+	//  }
+	//
+	//  func (e *editor) onRestore(ctx app.Context, ev app.Event) {
+	//      ctx.RestoreCrashState()
+	//  }
+	RestoreCrashState() bool
+
+	// Returns the claims of the current user. On the server, they are the
+	// claims returned by Handler.Claims for the request being prerendered.
+	Claims() Claims
+
+	// Reports whether the given role is granted to the current user.
+	HasRole(role string) bool
+
+	// Sets the claims of the current user, typically after a sign in or a sign
+	// out. Elements created with IfAuthorized are mounted or dismounted
+	// accordingly. It does nothing on the server. Eg:
+	//  ctx.SetClaims(app.Claims{
+	//      Subject: "42",
+	//      Roles:   []string{"admin"},
+	//  })
+	SetClaims(c Claims)
+
+	// Records the named user action in the sink set with SetAuditSink. It
+	// does nothing when the audit log is disabled or on the server. Eg:
+	//  ctx.Audit("export-invoices")
+	Audit(action string)
+
+	// Returns the locale of the current page, which is given by the locale
+	// prefix of its path, such as "de" for "/de/about". It returns the default
+	// locale when the path is not prefixed, or an empty string when
+	// Handler.Locales is not set.
+	Locale() string
+
+	// Persists the given locale and navigates to the current page in that
+	// locale. The persisted locale overrides the Accept-Language header when
+	// the root path is requested. It does nothing on the server or when the
+	// locale is not one of Handler.Locales. Eg:
+	//  ctx.SetLocale("de")
+	SetLocale(locale string)
+
+	// Returns the given route path prefixed by the locale of the current page.
+	// Eg:
+	//  a := app.A().Href(ctx.LocalizePath("/about"))
+	LocalizePath(path string) string
+
+	// Returns the translation of the given key for the locale of the current
+	// page, registered with AddTranslations. It falls back on the translation
+	// of the default locale, then on the key. The translation is formatted
+	// with the given values, as with fmt.Sprintf. Eg:
+	//  greeting := ctx.T("Hello, %s!", name)
+	T(key string, v ...interface{}) string
+
+	// Returns the given amount of money formatted for the locale of the
+	// current page with Intl.NumberFormat, eg: "12,34 €" for 12.34 EUR in
+	// "de". It returns the amount followed by its currency code on the
+	// server. Eg:
+	//  price := ctx.FormatMoney(app.Money{Amount: 1234, Currency: "EUR"})
+	FormatMoney(m Money) string
+
+	// Returns the given value formatted with the given measurement unit for
+	// the locale of the current page with Intl.NumberFormat, eg: "12.5 km/h".
+	// Units are named as the Intl.NumberFormat units, such as "kilometer",
+	// "kilometer-per-hour" or "gigabyte". It uses the unit symbol on the
+	// server. Eg:
+	//  speed := ctx.FormatUnit(12.5, "kilometer-per-hour")
+	FormatUnit(v float64, unit string) string
+
+	// Returns the time zone of the user. In the browser, it is the time zone
+	// detected with Intl.DateTimeFormat. During prerendering, it is the time
+	// zone echoed by the app when Handler.ClientTimeZone is enabled, or UTC.
+	// Eg:
+	//  date := post.CreatedAt.In(ctx.TimeZone()).Format("Jan 2, 15:04")
+	TimeZone() *time.Location
+
 	// Sets the state with the given value.
 	// Example:
 	//  ctx.SetState("/globalNumber", 42, Persistent)
@@ -178,6 +764,10 @@ func (ctx uiContext) AppUpdateAvailable() bool {
 	return ctx.appUpdateAvailable
 }
 
+func (ctx uiContext) ServerVersion() string {
+	return serverVersion
+}
+
 func (ctx uiContext) IsAppInstallable() bool {
 	if Window().Get("goappIsAppInstallable").Truthy() {
 		return Window().Call("goappIsAppInstallable").Bool()
@@ -185,6 +775,13 @@ func (ctx uiContext) IsAppInstallable() bool {
 	return false
 }
 
+func (ctx uiContext) DisplayMode() string {
+	if IsServer {
+		return "browser"
+	}
+	return displayMode()
+}
+
 func (ctx uiContext) IsAppInstalled() bool {
 	if Window().Get("goappIsAppInstalled").Truthy() {
 		return Window().Call("goappIsAppInstalled").Bool()
@@ -218,10 +815,57 @@ func (ctx uiContext) Defer(fn func(Context)) {
 	})
 }
 
+func (ctx uiContext) PriorityDispatch(p DispatchPriority, fn func(Context)) {
+	ctx.Dispatcher().Dispatch(Dispatch{
+		Mode:     Update,
+		Priority: p,
+		Source:   ctx.Src(),
+		Function: fn,
+	})
+}
+
+func (ctx uiContext) PriorityDefer(p DispatchPriority, fn func(Context)) {
+	ctx.Dispatcher().Dispatch(Dispatch{
+		Mode:     Defer,
+		Priority: p,
+		Source:   ctx.Src(),
+		Function: fn,
+	})
+}
+
+func (ctx uiContext) AfterPaint(fn func(Context)) {
+	ctx.Dispatcher().Dispatch(Dispatch{
+		Mode:     AfterPaint,
+		Source:   ctx.Src(),
+		Function: fn,
+	})
+}
+
+func (ctx uiContext) FontsReady(fn func(Context)) {
+	if IsServer || ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	fonts := Window().Get("document").Get("fonts")
+	if !fonts.Truthy() {
+		ctx.Dispatch(fn)
+		return
+	}
+
+	ready := func(Value) {
+		ctx.Dispatch(fn)
+	}
+	awaitPromise(fonts.Get("ready"), ready, ready)
+}
+
 func (ctx uiContext) Handle(actionName string, h ActionHandler) {
 	ctx.Dispatcher().Handle(actionName, ctx.Src(), h)
 }
 
+func (ctx uiContext) HandleDurable(actionName string, h ActionHandler) {
+	ctx.Dispatcher().handleDurable(actionName, ctx.Src(), h)
+}
+
 func (ctx uiContext) NewAction(name string, tags ...Tagger) {
 	ctx.NewActionWithValue(name, nil, tags...)
 }
@@ -245,6 +889,42 @@ func (ctx uiContext) NewActionWithValue(name string, v interface{}, tags ...Tagg
 	})
 }
 
+func (ctx uiContext) Track(event string, properties map[string]interface{}) {
+	ctx.Dispatcher().Post(Action{
+		Name: TrackAction,
+		Value: TrackEvent{
+			Name:       event,
+			Properties: properties,
+		},
+	})
+}
+
+func (ctx uiContext) Consent() Consent {
+	return loadConsent(ctx.Dispatcher())
+}
+
+func (ctx uiContext) SetConsent(categories ...string) {
+	storeConsent(ctx.Dispatcher(), categories)
+}
+
+func (ctx uiContext) LoadScript(url string, opts ScriptOptions) {
+	if IsServer {
+		return
+	}
+	scripts.load(ctx.Dispatcher(), ctx.Src(), url, opts)
+}
+
+func (ctx uiContext) RemoveScript(url string) {
+	if IsServer {
+		return
+	}
+	scripts.remove(url)
+}
+
+func (ctx uiContext) Animate(elem UI, keyframes []Keyframe, opts AnimationOptions) Animation {
+	return animate(ctx.Dispatcher(), ctx.Src(), elem, keyframes, opts)
+}
+
 func (ctx uiContext) Async(fn func()) {
 	ctx.Dispatcher().Async(fn)
 }
@@ -256,6 +936,41 @@ func (ctx uiContext) After(d time.Duration, fn func(Context)) {
 	})
 }
 
+func (ctx uiContext) Every(d time.Duration, fn func(Context)) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				if !ctx.Dispatcher().suspended() {
+					ctx.Dispatch(fn)
+				}
+			}
+		}
+	}()
+}
+
+func (ctx uiContext) WhenIdle(fn func(Context, time.Time)) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	requestIdle(func(deadline time.Time) {
+		ctx.Dispatch(func(ctx Context) {
+			fn(ctx, deadline)
+		})
+	})
+}
+
 func (ctx uiContext) Emit(fn func()) {
 	ctx.Dispatcher().Emit(ctx.Src(), fn)
 }
@@ -265,6 +980,9 @@ func (ctx uiContext) Reload() {
 		return
 	}
 	ctx.Defer(func(ctx Context) {
+		if ctx.AppUpdateAvailable() {
+			ctx.Dispatcher().saveUpdateSnapshot()
+		}
 		Window().Get("location").Call("reload")
 	})
 }
@@ -281,6 +999,37 @@ func (ctx uiContext) NavigateTo(u *url.URL) {
 	})
 }
 
+func (ctx uiContext) Redirect(url string, code int) {
+	if ctx.Dispatcher().runsInServer() {
+		ctx.Dispatcher().redirect(url, code)
+		return
+	}
+	ctx.Defer(func(ctx Context) {
+		ctx.Dispatcher().redirect(url, code)
+	})
+}
+
+func (ctx uiContext) RouteData(recv interface{}) error {
+	return ctx.Dispatcher().routeData(recv)
+}
+
+func (ctx uiContext) RouteParam(name string) string {
+	return ctx.Dispatcher().routeParam(name)
+}
+
+func (ctx uiContext) ParentRouteData(route string, recv interface{}) error {
+	return ctx.Dispatcher().parentRouteData(route, recv)
+}
+
+func (ctx uiContext) NavigateBack() {
+	if IsServer {
+		return
+	}
+	ctx.Defer(func(ctx Context) {
+		Window().Get("history").Call("back")
+	})
+}
+
 func (ctx uiContext) ResolveStaticResource(path string) string {
 	return ctx.Dispatcher().resolveStaticResource(path)
 }
@@ -299,6 +1048,86 @@ func (ctx uiContext) ScrollTo(id string) {
 	})
 }
 
+func (ctx uiContext) Download(filename, contentType string, r io.Reader) error {
+	return download(ctx, filename, contentType, r)
+}
+
+func (ctx uiContext) DownloadURL(url, filename string, opts DownloadOptions) {
+	downloadURL(ctx, url, filename, opts)
+}
+
+func (ctx uiContext) BackgroundFetch(id string, urls []string, opts BackgroundFetchOptions) {
+	backgroundFetch(ctx, id, urls, opts)
+}
+
+func (ctx uiContext) AbortBackgroundFetch(id string) {
+	abortBackgroundFetch(ctx, id)
+}
+
+func (ctx uiContext) IndexContent(item ContentIndexItem, onDone func(Context, error)) {
+	indexContent(ctx, item, onDone)
+}
+
+func (ctx uiContext) UnindexContent(id string, onDone func(Context, error)) {
+	unindexContent(ctx, id, onDone)
+}
+
+func (ctx uiContext) IndexedContent(fn func(Context, []ContentIndexItem, error)) {
+	listIndexedContent(ctx, fn)
+}
+
+func (ctx uiContext) Upload(file Value, opts UploadOptions) {
+	upload(ctx, file, opts)
+}
+
+func (ctx uiContext) ProcessImage(file Value, opts ImageOptions) {
+	processMedia(ctx, "image", file, imageWorkerOptions(opts), opts.OnDone)
+}
+
+func (ctx uiContext) RenderPDFPage(file Value, opts PDFPageOptions) {
+	if opts.PDFJS == "" {
+		if opts.OnDone != nil {
+			ctx.Dispatch(func(ctx Context) {
+				opts.OnDone(ctx, ProcessedFile{}, errors.New("rendering pdf page failed").
+					Tag("reason", "pdf.js url is not set"))
+			})
+		}
+		return
+	}
+	processMedia(ctx, "pdf-page", file, pdfPageWorkerOptions(opts), opts.OnDone)
+}
+
+func (ctx uiContext) SpyScroll(s ScrollSpy) {
+	spyScroll(ctx, s)
+}
+
+func (ctx uiContext) ObserveScroll() Observer {
+	scrolls.start(ctx.Dispatcher())
+	return ctx.ObserveState(ScrollState)
+}
+
+func (ctx uiContext) PrefersReducedMotion() bool {
+	return prefersReducedMotion()
+}
+
+func (ctx uiContext) ObserveReducedMotion() Observer {
+	preferences.start(ctx.Dispatcher())
+	return ctx.ObserveState(ReducedMotionState)
+}
+
+func (ctx uiContext) PrefersContrast() string {
+	return prefersContrast()
+}
+
+func (ctx uiContext) ObserveContrast() Observer {
+	preferences.start(ctx.Dispatcher())
+	return ctx.ObserveState(ContrastState)
+}
+
+func (ctx uiContext) StableID(prefix string) string {
+	return stableID(ctx.Src(), prefix)
+}
+
 func (ctx uiContext) DeviceID() string {
 	var id string
 	if err := ctx.LocalStorage().Get("/go-app/deviceID", &id); err != nil {
@@ -340,6 +1169,161 @@ func (ctx uiContext) Decrypt(crypted []byte, v interface{}) error {
 	return nil
 }
 
+func (ctx uiContext) Crypto() WebCrypto {
+	return WebCrypto{ctx: ctx}
+}
+
+func (ctx uiContext) WebAuthn() WebAuthn {
+	return WebAuthn{ctx: ctx}
+}
+
+func (ctx uiContext) Credentials() Credentials {
+	return Credentials{ctx: ctx}
+}
+
+func (ctx uiContext) Contacts() Contacts {
+	return Contacts{ctx: ctx}
+}
+
+func (ctx uiContext) Idle() IdleDetection {
+	return IdleDetection{ctx: ctx}
+}
+
+func (ctx uiContext) MediaCapture() MediaCapture {
+	return MediaCapture{ctx: ctx}
+}
+
+func (ctx uiContext) Desktop() DesktopWindow {
+	return DesktopWindow{ctx: ctx}
+}
+
+func (ctx uiContext) LaunchParams() LaunchParams {
+	return launchParams
+}
+
+func (ctx uiContext) Session() *Session {
+	return ctx.Dispatcher().serverSession()
+}
+
+func (ctx uiContext) Request() (Request, bool) {
+	return ctx.Dispatcher().request()
+}
+
+func (ctx uiContext) Env(k string) string {
+	return ctx.Dispatcher().getenv(k)
+}
+
+func (ctx uiContext) Flag(name string) bool {
+	return featureFlags.enabled(name)
+}
+
+func (ctx uiContext) ObserveFlag(name string) Observer {
+	return ctx.ObserveState(flagStatePrefix + name)
+}
+
+func (ctx uiContext) Experiment(name string) string {
+	return ctx.Dispatcher().experiments().variant(name)
+}
+
+func (ctx uiContext) PerfMark(name string) {
+	if !ctx.Dispatcher().runsInServer() {
+		perfMark(name)
+	}
+}
+
+func (ctx uiContext) PerfMeasure(name, start, end string) time.Duration {
+	return ctx.Dispatcher().perfMeasure(name, start, end)
+}
+
+func (ctx uiContext) MemoryStats() MemoryStats {
+	return readMemoryStats()
+}
+
+func (ctx uiContext) MeasureMemory(onDone func(ctx Context, bytes uint64, err error)) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+	measureUserAgentMemory(ctx, onDone)
+}
+
+func (ctx uiContext) HasCrashState() bool {
+	return ctx.Dispatcher().hasCrashState()
+}
+
+func (ctx uiContext) RestoreCrashState() bool {
+	return ctx.Dispatcher().restoreCrashState()
+}
+
+func (ctx uiContext) Claims() Claims {
+	return ctx.Dispatcher().claims()
+}
+
+func (ctx uiContext) HasRole(role string) bool {
+	return ctx.Claims().HasRole(role)
+}
+
+func (ctx uiContext) Audit(action string) {
+	ctx.Dispatcher().audit(AuditAction, action)
+}
+
+func (ctx uiContext) Locale() string {
+	return pageLocale(ctx.Page().URL(), ctx.Dispatcher().locales())
+}
+
+func (ctx uiContext) SetLocale(locale string) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+
+	locales := ctx.Dispatcher().locales()
+	if !stringsContains(locales, locale) {
+		Log(errors.New("setting locale failed").
+			Tag("locale", locale).
+			Tag("reason", "locale is not supported"))
+		return
+	}
+	persistLocale(locale)
+
+	u := *ctx.Page().URL()
+	_, path := splitLocalePath(strings.TrimPrefix(u.Path, rootPrefix), locales)
+	u.Path = rootPrefix + localizePath(path, locale)
+	ctx.NavigateTo(&u)
+}
+
+func (ctx uiContext) LocalizePath(path string) string {
+	return localizePath(path, ctx.Locale())
+}
+
+func (ctx uiContext) T(key string, v ...interface{}) string {
+	return translate(ctx.Locale(), ctx.Dispatcher().locales(), key, v...)
+}
+
+func (ctx uiContext) FormatMoney(m Money) string {
+	return formatMoney(ctx.Locale(), m)
+}
+
+func (ctx uiContext) FormatUnit(v float64, unit string) string {
+	return formatUnit(ctx.Locale(), v, unit)
+}
+
+func (ctx uiContext) TimeZone() *time.Location {
+	return ctx.Dispatcher().timeZone()
+}
+
+func (ctx uiContext) SetClaims(c Claims) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+	ctx.SetState(claimsState, c)
+}
+
+func (ctx uiContext) SetFlagUser(key string) {
+	if ctx.Dispatcher().runsInServer() {
+		return
+	}
+	featureFlags.setUser(key)
+}
+
 func (ctx uiContext) SetState(state string, v interface{}, opts ...StateOption) {
 	ctx.Dispatcher().SetState(state, v, opts...)
 }
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	crashSnapshotKey      = "/app/crash/snapshot"
+	crashDetectedKey      = "/app/crash/detected"
+	crashSnapshotInterval = 5 * time.Second
+)
+
+type crashSnapshot struct {
+	Time   time.Time
+	States map[string]json.RawMessage
+}
+
+// snapshot returns the JSON encoded values of the states set with the Snapshot
+// option.
+func (s *store) snapshot() map[string]json.RawMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	states := make(map[string]json.RawMessage)
+	for k, state := range s.states {
+		if !state.IsSnapshotted || state.isExpired(now) {
+			continue
+		}
+
+		switch {
+		case state.value != nil:
+			b, err := json.Marshal(state.value)
+			if err != nil {
+				Log(errors.New("snapshotting state failed").
+					Tag("state", k).
+					Wrap(err))
+				continue
+			}
+			states[k] = b
+
+		case state.raw != nil:
+			states[k] = state.raw
+		}
+	}
+	return states
+}
+
+// restore sets the given JSON encoded values and notifies the observers of the
+// related states.
+func (s *store) restore(states map[string]json.RawMessage) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for k, v := range states {
+		state := s.states[k]
+		state.value = nil
+		state.raw = v
+		state.IsSnapshotted = true
+		s.states[k] = state
+		s.notifyJSON(k, state, v)
+	}
+}
+
+// initCrashRecovery loads the last snapshot saved before the previous app
+// instance crashed. Crashes are detected in app.js.
+func (e *engine) initCrashRecovery() {
+	var crashed bool
+	e.sessionStorage().Get(crashDetectedKey, &crashed)
+	if !crashed {
+		return
+	}
+	e.sessionStorage().Del(crashDetectedKey)
+
+	var snapshot crashSnapshot
+	if err := e.sessionStorage().Get(crashSnapshotKey, &snapshot); err != nil {
+		Log(errors.New("loading crash snapshot failed").Wrap(err))
+		return
+	}
+	if len(snapshot.States) != 0 {
+		e.crashStates = snapshot.States
+	}
+}
+
+// saveCrashSnapshot saves the states set with the Snapshot option in session
+// storage when they changed since the last snapshot.
+func (e *engine) saveCrashSnapshot() {
+	states := e.states.snapshot()
+	if len(states) == 0 && e.lastCrashSnapshot == nil {
+		return
+	}
+
+	b, err := json.Marshal(states)
+	if err != nil {
+		Log(errors.New("encoding crash snapshot failed").Wrap(err))
+		return
+	}
+	if bytes.Equal(b, e.lastCrashSnapshot) {
+		return
+	}
+	e.lastCrashSnapshot = b
+
+	if err := e.sessionStorage().Set(crashSnapshotKey, crashSnapshot{
+		Time:   time.Now(),
+		States: states,
+	}); err != nil {
+		Log(errors.New("saving crash snapshot failed").Wrap(err))
+	}
+}
+
+func (e *engine) hasCrashState() bool {
+	return len(e.crashStates) != 0
+}
+
+func (e *engine) restoreCrashState() bool {
+	if len(e.crashStates) == 0 {
+		return false
+	}
+
+	e.states.restore(e.crashStates)
+	e.crashStates = nil
+	return true
+}
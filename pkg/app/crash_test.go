@@ -0,0 +1,122 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSnapshot(t *testing.T) {
+	d := NewClientTester(Div())
+	defer d.Close()
+
+	s := newStore(d)
+	defer s.Close()
+
+	s.Set("/snapshotted", 42, Snapshot)
+	s.Set("/ignored", 21)
+	s.Set("/expired", 84, Snapshot, ExpiresIn(-1))
+
+	states := s.snapshot()
+	require.Len(t, states, 1)
+	require.Equal(t, json.RawMessage("42"), states["/snapshotted"])
+}
+
+func TestStoreRestore(t *testing.T) {
+	foo := &foo{}
+	d := NewClientTester(foo)
+	defer d.Close()
+
+	s := newStore(d)
+	defer s.Close()
+
+	s.Observe("/draft", foo).Value(&foo.Bar)
+	s.restore(map[string]json.RawMessage{
+		"/draft": json.RawMessage(`"hello"`),
+	})
+	d.Consume()
+	require.Equal(t, "hello", foo.Bar)
+
+	var draft string
+	s.Get("/draft", &draft)
+	require.Equal(t, "hello", draft)
+	require.Equal(t, json.RawMessage(`"hello"`), s.snapshot()["/draft"])
+
+	s.Set("/draft", "bye")
+	s.Get("/draft", &draft)
+	require.Equal(t, "bye", draft)
+}
+
+func TestEngineCrashRecovery(t *testing.T) {
+	t.Run("states are restored after a crash", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		err := e.sessionStorage().Set(crashSnapshotKey, crashSnapshot{
+			States: map[string]json.RawMessage{"/draft": json.RawMessage(`"hello"`)},
+		})
+		require.NoError(t, err)
+		err = e.sessionStorage().Set(crashDetectedKey, true)
+		require.NoError(t, err)
+
+		e.initCrashRecovery()
+		require.True(t, e.hasCrashState())
+
+		var crashed bool
+		e.sessionStorage().Get(crashDetectedKey, &crashed)
+		require.False(t, crashed)
+
+		require.True(t, e.restoreCrashState())
+		require.False(t, e.hasCrashState())
+		require.False(t, e.restoreCrashState())
+
+		var draft string
+		e.GetState("/draft", &draft)
+		require.Equal(t, "hello", draft)
+	})
+
+	t.Run("states are not restored without crash", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		err := e.sessionStorage().Set(crashSnapshotKey, crashSnapshot{
+			States: map[string]json.RawMessage{"/draft": json.RawMessage(`"hello"`)},
+		})
+		require.NoError(t, err)
+
+		e.initCrashRecovery()
+		require.False(t, e.hasCrashState())
+		require.False(t, e.restoreCrashState())
+	})
+}
+
+func TestEngineSaveCrashSnapshot(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	e.saveCrashSnapshot()
+	require.Zero(t, e.sessionStorage().Len())
+
+	e.SetState("/draft", "hello", Snapshot)
+	e.saveCrashSnapshot()
+
+	var snapshot crashSnapshot
+	err := e.sessionStorage().Get(crashSnapshotKey, &snapshot)
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`"hello"`), snapshot.States["/draft"])
+	require.False(t, snapshot.Time.IsZero())
+
+	e.sessionStorage().Del(crashSnapshotKey)
+	e.saveCrashSnapshot()
+	require.Zero(t, e.sessionStorage().Len())
+
+	e.SetState("/draft", "bye", Snapshot)
+	e.saveCrashSnapshot()
+	err = e.sessionStorage().Get(crashSnapshotKey, &snapshot)
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`"bye"`), snapshot.States["/draft"])
+}
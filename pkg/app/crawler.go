@@ -0,0 +1,40 @@
+package app
+
+import (
+	"net/http"
+	"regexp"
+)
+
+const (
+	crawlerCacheKeyPrefix = "crawler:"
+)
+
+var (
+	// The user agents of the crawlers. It matches the ones for which app.js
+	// does not load the app.
+	crawlerUserAgentRegexp = regexp.MustCompile(`(?i)bot|googlebot|crawler|spider|robot|crawling`)
+)
+
+// isCrawlerRequest reports whether the given request is sent by a crawler that
+// is served fully rendered pages.
+func (h *Handler) isCrawlerRequest(r *http.Request) bool {
+	return h.CrawlerRendering && crawlerUserAgentRegexp.MatchString(r.UserAgent())
+}
+
+// setCrawlerHeaders tells caches that pages depend on the user agent when
+// crawlers are served fully rendered pages.
+func (h *Handler) setCrawlerHeaders(w http.ResponseWriter) {
+	if h.CrawlerRendering {
+		w.Header().Add("Vary", "User-Agent")
+	}
+}
+
+// preRenderCacheKey returns the path where the pre-rendered page served for
+// the given request is cached. Pages served to crawlers are cached apart from
+// the ones served to browsers.
+func (h *Handler) preRenderCacheKey(r *http.Request, path string) string {
+	if _, ok := h.proxyResources[path]; ok || !h.isCrawlerRequest(r) {
+		return path
+	}
+	return crawlerCacheKeyPrefix + path
+}
@@ -0,0 +1,77 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerCrawlerRendering(t *testing.T) {
+	serve := func(h *Handler, userAgent string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	const (
+		browser = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0"
+		crawler = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	)
+
+	t.Run("crawler is served a fully rendered page", func(t *testing.T) {
+		h := &Handler{CrawlerRendering: true}
+
+		w := serve(h, crawler)
+		body := w.Body.String()
+		require.Contains(t, body, `id="app-pre-render"`)
+		require.Contains(t, body, `id="pre-render-ok"`)
+		require.NotContains(t, body, "app-wasm-loader")
+		require.NotContains(t, body, `src="/app.js"`)
+		require.NotContains(t, body, `src="/wasm_exec.js"`)
+		require.Equal(t, "User-Agent", w.Header().Get("Vary"))
+	})
+
+	t.Run("crawler page is cached apart", func(t *testing.T) {
+		h := &Handler{CrawlerRendering: true}
+
+		serve(h, crawler)
+		item, ok := h.PreRenderCache.Get(context.Background(), "crawler:/")
+		require.True(t, ok)
+		require.NotContains(t, string(item.Body), "app-wasm-loader")
+		_, ok = h.PreRenderCache.Get(context.Background(), "/")
+		require.False(t, ok)
+
+		w := serve(h, browser)
+		require.Contains(t, w.Body.String(), "app-wasm-loader")
+		require.Contains(t, w.Body.String(), `src="/app.js"`)
+		require.Equal(t, "User-Agent", w.Header().Get("Vary"))
+
+		item, ok = h.PreRenderCache.Get(context.Background(), "/")
+		require.True(t, ok)
+		require.Contains(t, string(item.Body), "app-wasm-loader")
+
+		h.PreRenderCache.Set(context.Background(), PreRenderedItem{
+			Path:        "crawler:/",
+			Body:        []byte("cached crawler page"),
+			ContentType: "text/html",
+		})
+		w = serve(h, crawler)
+		require.Equal(t, "cached crawler page", w.Body.String())
+		require.Equal(t, "User-Agent", w.Header().Get("Vary"))
+	})
+
+	t.Run("crawler rendering is disabled by default", func(t *testing.T) {
+		w := serve(&Handler{}, crawler)
+		require.Contains(t, w.Body.String(), "app-wasm-loader")
+		require.Contains(t, w.Body.String(), `src="/app.js"`)
+		require.Empty(t, w.Header().Get("Vary"))
+	})
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// PasswordCredential describes a user name and password pair saved by the
+// browser.
+type PasswordCredential struct {
+	// The user name.
+	ID string
+
+	// The password.
+	Password string
+
+	// The name displayed by the browser account chooser.
+	Name string
+
+	// The URL of the picture displayed by the browser account chooser.
+	IconURL string
+}
+
+// IsZero reports whether the credential is empty.
+func (c PasswordCredential) IsZero() bool {
+	return c.ID == "" && c.Password == ""
+}
+
+// Credentials saves and retrieves passwords with the browser Credential
+// Management API. It lets the browser offer to save the password after a
+// successful login, and to sign the user in when the app is opened again.
+//
+// Login forms should also set the autocomplete attributes of their inputs in
+// order to be recognized by browsers and password managers. eg:
+//  app.Input().Name("username").AutoCompleteTokens("username")
+//  app.Input().Type("password").AutoCompleteTokens("current-password")
+//
+// Operations are asynchronous. Their result is passed to the given function,
+// called on the UI goroutine. Operations do nothing on the server.
+type Credentials struct {
+	ctx Context
+}
+
+// StorePassword asks the browser to save the given credential. It should be
+// called after the user successfully logged in.
+func (c Credentials) StorePassword(cred PasswordCredential, onDone func(Context, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(err error) {
+		if err != nil {
+			err = errors.New("storing password credential failed").
+				Tag("id", cred.ID).
+				Wrap(err)
+		}
+		dispatchResult(c.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, err)
+		})
+	}
+
+	credentials := Window().Get("navigator").Get("credentials")
+	constructor := Window().Get("PasswordCredential")
+	if !credentials.Truthy() || !constructor.Truthy() {
+		done(errors.New("password credentials are not supported"))
+		return
+	}
+
+	data := map[string]interface{}{
+		"id":       cred.ID,
+		"password": cred.Password,
+	}
+	if cred.Name != "" {
+		data["name"] = cred.Name
+	}
+	if cred.IconURL != "" {
+		data["iconURL"] = cred.IconURL
+	}
+
+	awaitPromise(credentials.Call("store", constructor.New(data)), func(Value) {
+		done(nil)
+	}, func(reason Value) {
+		done(jsReasonError(reason))
+	})
+}
+
+// GetPassword retrieves a password saved by the browser for the app.
+//
+// The mediation tells whether the user is asked to pick an account: "silent"
+// never asks, "optional" asks only when required and "required" always asks.
+// Default is "optional". The returned credential is zero when the user
+// dismissed the browser prompt or when no password is saved.
+func (c Credentials) GetPassword(mediation string, onDone func(Context, PasswordCredential, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(cred PasswordCredential, err error) {
+		if err != nil {
+			err = errors.New("getting password credential failed").Wrap(err)
+		}
+		dispatchResult(c.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, cred, err)
+		})
+	}
+
+	credentials := Window().Get("navigator").Get("credentials")
+	if !credentials.Truthy() || !Window().Get("PasswordCredential").Truthy() {
+		done(PasswordCredential{}, errors.New("password credentials are not supported"))
+		return
+	}
+
+	if mediation == "" {
+		mediation = "optional"
+	}
+
+	awaitPromise(credentials.Call("get", map[string]interface{}{
+		"password":  true,
+		"mediation": mediation,
+	}), func(cred Value) {
+		if !cred.Truthy() || cred.Get("type").String() != "password" {
+			done(PasswordCredential{}, nil)
+			return
+		}
+
+		done(PasswordCredential{
+			ID:       cred.Get("id").String(),
+			Password: cred.Get("password").String(),
+			Name:     jsOptionalString(cred.Get("name")),
+			IconURL:  jsOptionalString(cred.Get("iconURL")),
+		}, nil)
+	}, func(reason Value) {
+		done(PasswordCredential{}, jsReasonError(reason))
+	})
+}
+
+// PreventSilentAccess prevents the browser from signing the user in
+// automatically with GetPassword and a "silent" mediation. It should be called
+// when the user logs out.
+func (c Credentials) PreventSilentAccess() {
+	if IsServer {
+		return
+	}
+
+	if credentials := Window().Get("navigator").Get("credentials"); credentials.Truthy() {
+		credentials.Call("preventSilentAccess")
+	}
+}
+
+// IsOTPSupported reports whether the browser can read one-time codes sent by
+// SMS with the WebOTP API.
+func (c Credentials) IsOTPSupported() bool {
+	return !IsServer && Window().Get("OTPCredential").Truthy()
+}
+
+// GetOTP waits for an SMS that contains a one-time code for the app origin and
+// returns the code once the user allowed the browser to read it. The SMS last
+// line must be formatted as "@<host> #<code>". eg: "@example.com #123456".
+//
+// The request is aborted when the context's component is dismounted, and the
+// returned code is empty when the request is aborted or dismissed. The code
+// input should also have the "one-time-code" autocomplete token, which lets
+// browsers that don't support the API suggest the code. eg:
+//  app.Input().AutoCompleteTokens("one-time-code")
+func (c Credentials) GetOTP(onDone func(Context, string, error)) {
+	if IsServer {
+		return
+	}
+
+	finished := make(chan struct{})
+	done := func(code string, err error) {
+		close(finished)
+		if err != nil {
+			err = errors.New("getting one-time code failed").Wrap(err)
+		}
+		dispatchResult(c.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, code, err)
+		})
+	}
+
+	credentials := Window().Get("navigator").Get("credentials")
+	if !credentials.Truthy() || !Window().Get("OTPCredential").Truthy() {
+		done("", errors.New("web otp is not supported"))
+		return
+	}
+
+	controller := Window().Get("AbortController").New()
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			controller.Call("abort")
+
+		case <-finished:
+		}
+	}()
+
+	awaitPromise(credentials.Call("get", map[string]interface{}{
+		"otp":    map[string]interface{}{"transport": []interface{}{"sms"}},
+		"signal": controller.Get("signal"),
+	}), func(cred Value) {
+		if !cred.Truthy() {
+			done("", nil)
+			return
+		}
+		done(jsOptionalString(cred.Get("code")), nil)
+	}, func(reason Value) {
+		if reason.Truthy() && reason.Get("name").String() == "AbortError" {
+			done("", nil)
+			return
+		}
+		done("", jsReasonError(reason))
+	})
+}
+
+// dispatchResult calls the given function on the UI goroutine, or logs the
+// given error when there is no function to call.
+func dispatchResult(ctx Context, noHandler bool, err error, fn func(Context)) {
+	if noHandler {
+		if err != nil {
+			Log(err)
+		}
+		return
+	}
+	ctx.Dispatch(fn)
+}
+
+func jsOptionalString(v Value) string {
+	if !v.Truthy() {
+		return ""
+	}
+	return v.String()
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordCredentialIsZero(t *testing.T) {
+	require.True(t, PasswordCredential{}.IsZero())
+	require.True(t, PasswordCredential{Name: "Max"}.IsZero())
+	require.False(t, PasswordCredential{ID: "max"}.IsZero())
+}
+
+func TestCredentialsOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	creds := makeContext(h).Credentials()
+	creds.StorePassword(PasswordCredential{ID: "max", Password: "secret"}, func(Context, error) {
+		called = true
+	})
+	creds.GetPassword("", func(Context, PasswordCredential, error) {
+		called = true
+	})
+	creds.PreventSilentAccess()
+	creds.GetOTP(func(Context, string, error) {
+		called = true
+	})
+	d.Consume()
+	require.False(t, called)
+	require.False(t, creds.IsOTPSupported())
+}
+
+func TestInputAutoCompleteTokens(t *testing.T) {
+	input := Input().AutoCompleteTokens("section-login", "username", "webauthn").(*htmlInput)
+	require.Equal(t, "section-login username webauthn", input.attrs["autocomplete"])
+}
@@ -0,0 +1,310 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+var (
+	cssCommentRegexp        = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssSelectorIgnoreRegexp = regexp.MustCompile(`\([^()]*\)|\[[^\]]*\]|::?[a-zA-Z-]+`)
+	rawTagRegexp            = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9-]*)`)
+	rawAttrRegexp           = regexp.MustCompile(`\s(class|id)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// criticalStyles is the CSS of the stylesheets of a page, parsed in order to
+// extract the rules required to display a pre-rendered page.
+type criticalStyles struct {
+	rules       []criticalRule
+	stylesheets map[string]bool
+}
+
+type criticalRule struct {
+	css       string
+	prelude   string
+	selectors []criticalSelector
+	nested    []criticalRule
+}
+
+// criticalSelector describes the tags, ids and classes that a page must
+// contain for a selector to match one of its elements.
+type criticalSelector struct {
+	tags    []string
+	ids     []string
+	classes []string
+}
+
+func (h *Handler) initCriticalCSS() {
+	if !h.CriticalCSS {
+		return
+	}
+
+	styles := criticalStyles{
+		stylesheets: make(map[string]bool),
+	}
+
+	styles.add(h.resolvePackagePath("/app.css"), appCSS)
+
+	dir, isLocalDir := h.Resources.(localDir)
+	for _, path := range h.Styles {
+		if !isLocalDir || isRemoteLocation(path) || !isStaticResourcePath(path) {
+			continue
+		}
+
+		filename := filepath.Join(dir.dir, filepath.FromSlash(strings.TrimPrefix(path, "/")))
+		css, err := ioutil.ReadFile(filename)
+		if err != nil {
+			Log(errors.New("reading critical css stylesheet failed").
+				Tag("path", path).
+				Wrap(err))
+			continue
+		}
+		styles.add(h.resolveStaticPath(path), string(css))
+	}
+
+	h.criticalStyles = &styles
+}
+
+// add parses the given stylesheet CSS. The stylesheet located at the given
+// href is then loaded without blocking the display of pages.
+func (s *criticalStyles) add(href, css string) {
+	s.rules = append(s.rules, parseCriticalRules(css)...)
+	s.stylesheets[href] = true
+}
+
+// isDeferred reports whether the stylesheet located at the given href is
+// loaded after the page is displayed.
+func (s *criticalStyles) isDeferred(href string) bool {
+	return s != nil && s.stylesheets[href]
+}
+
+// render returns the CSS rules that match the elements of the given node.
+func (s *criticalStyles) render(n UI) string {
+	if s == nil {
+		return ""
+	}
+
+	u := newCriticalUsage()
+	u.collect(n)
+
+	var b strings.Builder
+	for _, r := range s.rules {
+		r.render(&b, u)
+	}
+	return b.String()
+}
+
+func parseCriticalRules(css string) []criticalRule {
+	css = cssCommentRegexp.ReplaceAllString(css, "")
+
+	var rules []criticalRule
+	for _, block := range splitCSSBlocks(css) {
+		open := strings.Index(block, "{")
+		prelude := block[:open]
+		prelude = strings.TrimSpace(prelude[strings.LastIndex(prelude, ";")+1:])
+		content := block[open+1 : len(block)-1]
+
+		switch {
+		case strings.HasPrefix(prelude, "@media"),
+			strings.HasPrefix(prelude, "@supports"):
+			rules = append(rules, criticalRule{
+				prelude: prelude,
+				nested:  parseCriticalRules(content),
+			})
+
+		case strings.HasPrefix(prelude, "@"):
+			rules = append(rules, criticalRule{
+				css: prelude + "{" + strings.TrimSpace(content) + "}",
+			})
+
+		default:
+			rule := criticalRule{
+				css: prelude + "{" + strings.TrimSpace(content) + "}",
+			}
+			for _, s := range strings.Split(prelude, ",") {
+				rule.selectors = append(rule.selectors, parseCriticalSelector(s))
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseCriticalSelector returns the tags, ids and classes required by the
+// given selector. Attribute selectors, pseudo-classes, pseudo-elements and
+// the compounds that can't be parsed are ignored, which means that they are
+// considered as matching.
+func parseCriticalSelector(s string) criticalSelector {
+	for {
+		ignored := cssSelectorIgnoreRegexp.ReplaceAllString(s, "")
+		if ignored == s {
+			break
+		}
+		s = ignored
+	}
+
+	var sel criticalSelector
+	compounds := strings.FieldsFunc(s, func(c rune) bool {
+		return c == '>' || c == '+' || c == '~' || isHTMLSpace(byte(c))
+	})
+
+	for _, c := range compounds {
+		m := emailSelectorRegexp.FindStringSubmatch(c)
+		if m == nil {
+			continue
+		}
+
+		if tag := strings.ToLower(m[1]); tag != "" && tag != "*" {
+			sel.tags = append(sel.tags, tag)
+		}
+
+		for _, part := range emailSelectorPartRegexp.FindAllString(m[2], -1) {
+			switch part[0] {
+			case '#':
+				sel.ids = append(sel.ids, part[1:])
+
+			case '.':
+				sel.classes = append(sel.classes, part[1:])
+			}
+		}
+	}
+	return sel
+}
+
+func (r criticalRule) render(b *strings.Builder, u criticalUsage) {
+	switch {
+	case r.prelude != "":
+		var nested strings.Builder
+		for _, n := range r.nested {
+			n.render(&nested, u)
+		}
+		if nested.Len() != 0 {
+			b.WriteString(r.prelude)
+			b.WriteByte('{')
+			b.WriteString(nested.String())
+			b.WriteByte('}')
+		}
+
+	case len(r.selectors) == 0:
+		b.WriteString(r.css)
+
+	default:
+		for _, s := range r.selectors {
+			if u.match(s) {
+				b.WriteString(r.css)
+				return
+			}
+		}
+	}
+}
+
+// criticalUsage is the set of tags, ids and classes used by a page.
+type criticalUsage struct {
+	tags    map[string]struct{}
+	ids     map[string]struct{}
+	classes map[string]struct{}
+}
+
+func newCriticalUsage() criticalUsage {
+	return criticalUsage{
+		tags:    map[string]struct{}{"html": {}},
+		ids:     make(map[string]struct{}),
+		classes: make(map[string]struct{}),
+	}
+}
+
+func (u criticalUsage) collect(n UI) {
+	switch n.Kind() {
+	case HTML:
+		u.tags[n.name()] = struct{}{}
+		u.add(n.attributes()["id"], n.attributes()["class"])
+
+	case RawHTML:
+		var b bytes.Buffer
+		n.html(&b)
+		u.collectRaw(b.String())
+		return
+	}
+
+	for _, c := range n.children() {
+		u.collect(c)
+	}
+}
+
+func (u criticalUsage) collectRaw(s string) {
+	for _, m := range rawTagRegexp.FindAllStringSubmatch(s, -1) {
+		u.tags[strings.ToLower(m[1])] = struct{}{}
+	}
+
+	for _, m := range rawAttrRegexp.FindAllStringSubmatch(s, -1) {
+		v := m[2] + m[3] + m[4]
+		if m[1] == "id" {
+			u.add(v, "")
+			continue
+		}
+		u.add("", v)
+	}
+}
+
+func (u criticalUsage) add(id, class string) {
+	if id != "" {
+		u.ids[id] = struct{}{}
+	}
+	for _, c := range strings.Fields(class) {
+		u.classes[c] = struct{}{}
+	}
+}
+
+func (u criticalUsage) match(s criticalSelector) bool {
+	for _, t := range s.tags {
+		if _, ok := u.tags[t]; !ok {
+			return false
+		}
+	}
+	for _, id := range s.ids {
+		if _, ok := u.ids[id]; !ok {
+			return false
+		}
+	}
+	for _, c := range s.classes {
+		if _, ok := u.classes[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// renderStylesheets returns the links to the given stylesheets. Stylesheets
+// whose critical rules are inlined are preloaded and applied once loaded.
+func renderStylesheets(styles *criticalStyles, hrefs ...string) []UI {
+	links := make([]UI, 0, len(hrefs))
+	for _, href := range hrefs {
+		if !styles.isDeferred(href) {
+			links = append(links, Link().
+				Type("text/css").
+				Rel("stylesheet").
+				Href(href))
+			continue
+		}
+
+		links = append(links,
+			Link().
+				Rel("preload").
+				As("style").
+				Href(href).
+				Attr("onload", "this.onload=null;this.rel='stylesheet'"),
+			NoScript().Body(
+				Link().
+					Type("text/css").
+					Rel("stylesheet").
+					Href(href),
+			),
+		)
+	}
+	return links
+}
@@ -0,0 +1,150 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCriticalSelector(t *testing.T) {
+	utests := []struct {
+		selector string
+		expected criticalSelector
+	}{
+		{
+			selector: "div",
+			expected: criticalSelector{tags: []string{"div"}},
+		},
+		{
+			selector: "a.button#main",
+			expected: criticalSelector{
+				tags:    []string{"a"},
+				ids:     []string{"main"},
+				classes: []string{"button"},
+			},
+		},
+		{
+			selector: " ul > li + li.item ~ span ",
+			expected: criticalSelector{
+				tags:    []string{"ul", "li", "li", "span"},
+				classes: []string{"item"},
+			},
+		},
+		{
+			selector: ".card:hover::before",
+			expected: criticalSelector{classes: []string{"card"}},
+		},
+		{
+			selector: `input[type="text"]:not(.hidden)`,
+			expected: criticalSelector{tags: []string{"input"}},
+		},
+		{
+			selector: ":root",
+			expected: criticalSelector{},
+		},
+		{
+			selector: "*",
+			expected: criticalSelector{},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.selector, func(t *testing.T) {
+			require.Equal(t, u.expected, parseCriticalSelector(u.selector))
+		})
+	}
+}
+
+func TestCriticalStylesRender(t *testing.T) {
+	styles := criticalStyles{stylesheets: make(map[string]bool)}
+	styles.add("/web/main.css", `
+		@import url("fonts.css");
+		/* Layout */
+		body { margin: 0; }
+		.card, .unused { padding: 12px; }
+		.unused { color: red; }
+		p.lead:first-child { font-size: 21px; }
+		#sidebar { width: 200px; }
+		@media (max-width: 480px) {
+			.card { padding: 6px; }
+			.unused { display: none; }
+		}
+		@media print {
+			.unused { display: none; }
+		}
+		@font-face { font-family: "Inter"; src: url(inter.woff2); }
+		.raw-item { color: blue; }
+	`)
+
+	tree := Body().Body(
+		Div().Class("card").Body(
+			P().Class("lead").Text("hello"),
+		),
+		Raw(`<ul><li class='raw-item'>x</li></ul>`),
+	)
+	css := styles.render(tree)
+
+	require.Equal(t, "body{margin: 0;}"+
+		".card, .unused{padding: 12px;}"+
+		"p.lead:first-child{font-size: 21px;}"+
+		"@media (max-width: 480px){.card{padding: 6px;}}"+
+		`@font-face{font-family: "Inter"; src: url(inter.woff2);}`+
+		".raw-item{color: blue;}", css)
+
+	require.True(t, styles.isDeferred("/web/main.css"))
+	require.False(t, styles.isDeferred("https://foo.com/test.css"))
+
+	var nilStyles *criticalStyles
+	require.Empty(t, nilStyles.render(tree))
+	require.False(t, nilStyles.isDeferred("/web/main.css"))
+}
+
+func TestHandlerCriticalCSS(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateFile(t, filepath.Join("web", "main.css"), "#pre-render-ok { color: red; } .missing { color: blue; }")
+
+	serve := func(h *Handler) string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("critical css is inlined", func(t *testing.T) {
+		body := serve(&Handler{
+			CriticalCSS: true,
+			Styles: []string{
+				"/web/main.css",
+				"https://foo.com/test.css",
+			},
+		})
+
+		require.Contains(t, body, "<style>")
+		require.Contains(t, body, "#pre-render-ok{color: red;}")
+		require.Contains(t, body, ".goapp-app-info{")
+		require.NotContains(t, body, ".missing")
+		require.NotContains(t, body, ".goapp-notfound-title")
+
+		require.Equal(t, 2, strings.Count(body, `rel="preload"`))
+		require.Equal(t, 2, strings.Count(body, `onload="this.onload=null;this.rel='stylesheet'"`))
+		require.Equal(t, 2, strings.Count(body, "<noscript>"))
+		require.Equal(t, 1, strings.Count(body, `href="https://foo.com/test.css"`))
+	})
+
+	t.Run("critical css is disabled by default", func(t *testing.T) {
+		body := serve(&Handler{
+			Styles: []string{"/web/main.css"},
+		})
+
+		require.NotContains(t, body, "<style>")
+		require.NotContains(t, body, `rel="preload"`)
+		require.Contains(t, body, `href="/web/main.css"`)
+	})
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns an event handler that calls the given handler once the
+// events stop firing for the given duration, with the last event. It is
+// typically used to search as the user types without a request for each
+// keystroke. Eg:
+//  app.Input().
+//      Type("search").
+//      OnInput(app.Debounce(300*time.Millisecond, s.onSearch))
+//
+// The handler is called on the UI goroutine, and pending calls are dropped
+// when the element that emitted the events is dismounted.
+func Debounce(d time.Duration, h EventHandler) EventHandler {
+	var mutex sync.Mutex
+	var timer *time.Timer
+
+	return func(ctx Context, e Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, func() {
+			callDelayedHandler(ctx, h, e)
+		})
+	}
+}
+
+// Throttle returns an event handler that calls the given handler at most once
+// per given duration. The first event is handled immediately, and the last
+// event that fires during the wait is handled when it ends. It is typically
+// used to handle scroll or resize events. Eg:
+//  app.Div().OnScroll(app.Throttle(100*time.Millisecond, c.onScroll))
+//
+// The handler is called on the UI goroutine, and pending calls are dropped
+// when the element that emitted the events is dismounted.
+func Throttle(d time.Duration, h EventHandler) EventHandler {
+	var mutex sync.Mutex
+	var waiting bool
+	var pending *Event
+	var pendingCtx Context
+
+	var wait func()
+	wait = func() {
+		waiting = true
+		time.AfterFunc(d, func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if pending == nil {
+				waiting = false
+				return
+			}
+
+			ctx, e := pendingCtx, *pending
+			pendingCtx, pending = nil, nil
+			callDelayedHandler(ctx, h, e)
+			wait()
+		})
+	}
+
+	return func(ctx Context, e Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if waiting {
+			pendingCtx, pending = ctx, &e
+			return
+		}
+
+		h(ctx, e)
+		wait()
+	}
+}
+
+// callDelayedHandler dispatches the call of the given handler on the UI
+// goroutine, unless the element that emitted the event is dismounted.
+func callDelayedHandler(ctx Context, h EventHandler, e Event) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	ctx.Dispatch(func(ctx Context) {
+		ctx.Emit(func() {
+			h(ctx, e)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type delayedHandlerCompo struct {
+	Compo
+
+	wrap   func(EventHandler) EventHandler
+	calls  int
+	values []string
+}
+
+func (c *delayedHandlerCompo) Render() UI {
+	return Input().OnInput(c.wrap(c.onInput))
+}
+
+func (c *delayedHandlerCompo) onInput(ctx Context, e Event) {
+	c.calls++
+	c.values = append(c.values, e.Get("value").String())
+}
+
+func (c *delayedHandlerCompo) emit(d ClientDispatcher, value string) {
+	input := c.root
+	h := input.eventHandlers()["input"]
+	d.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: input,
+		Function: func(ctx Context) {
+			h.value(ctx, Event{Value: newSimulatedEvent("input", map[string]interface{}{
+				"value": value,
+			})})
+		},
+	})
+	d.Consume()
+}
+
+func TestDebounce(t *testing.T) {
+	delay := 20 * time.Millisecond
+	wrap := func(h EventHandler) EventHandler {
+		return Debounce(delay, h)
+	}
+
+	t.Run("last event is handled", func(t *testing.T) {
+		compo := &delayedHandlerCompo{wrap: wrap}
+		d := NewClientTester(compo)
+		defer d.Close()
+
+		compo.emit(d, "a")
+		compo.emit(d, "ab")
+		compo.emit(d, "abc")
+		require.Zero(t, compo.calls)
+
+		time.Sleep(delay * 3)
+		d.Consume()
+		require.Equal(t, 1, compo.calls)
+		require.Equal(t, []string{"abc"}, compo.values)
+	})
+
+	t.Run("pending event is dropped on dismount", func(t *testing.T) {
+		compo := &delayedHandlerCompo{wrap: wrap}
+		d := NewClientTester(compo)
+		defer d.Close()
+
+		compo.emit(d, "a")
+		d.Mount(Div())
+		d.Consume()
+
+		time.Sleep(delay * 3)
+		d.Consume()
+		require.Zero(t, compo.calls)
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	delay := 20 * time.Millisecond
+	compo := &delayedHandlerCompo{wrap: func(h EventHandler) EventHandler {
+		return Throttle(delay, h)
+	}}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	compo.emit(d, "a")
+	compo.emit(d, "ab")
+	compo.emit(d, "abc")
+	require.Equal(t, 1, compo.calls)
+
+	time.Sleep(delay * 3 / 2)
+	d.Consume()
+	require.Equal(t, 2, compo.calls)
+	require.Equal(t, []string{"a", "abc"}, compo.values)
+
+	time.Sleep(delay * 2)
+	d.Consume()
+	compo.emit(d, "abcd")
+	require.Equal(t, 3, compo.calls)
+}
@@ -0,0 +1,65 @@
+package app
+
+// DelegatedEvent is an event handled by a delegated event handler.
+type DelegatedEvent struct {
+	Event
+
+	// The descendant of the container that matches the selector of the
+	// delegated event handler.
+	Target Value
+
+	// The key of the target, set with its data-key attribute.
+	Key string
+}
+
+// Data returns the value of the given data attribute of the target. The
+// attribute name is in camel case and without the "data-" prefix, as in the
+// dataset property of the target. Eg: "itemId" for "data-item-id".
+func (e DelegatedEvent) Data(k string) string {
+	if v := e.Target.Get("dataset").Get(k); v.Truthy() {
+		return v.String()
+	}
+	return ""
+}
+
+// Delegate returns an event handler to set on a container, which calls the
+// given handler when the event is emitted from a descendant that matches the
+// given CSS selector.
+//
+// Setting a single handler on the container of a large list instead of one
+// handler per row avoids creating a JavaScript callback for each row, which
+// reduces the memory usage and the mount time. Rows are identified with their
+// data-key attribute. Eg:
+//  app.Ul().
+//      OnClick(app.Delegate("li", l.onSelect)).
+//      Body(
+//          app.Range(l.items).Slice(func(i int) app.UI {
+//              return app.Li().
+//                  DataSet("key", l.items[i].ID).
+//                  Text(l.items[i].Name)
+//          }),
+//      )
+//
+//  func (l *list) onSelect(ctx app.Context, e app.DelegatedEvent) {
+//      l.selected = e.Key
+//  }
+func Delegate(selector string, h func(Context, DelegatedEvent)) EventHandler {
+	return func(ctx Context, e Event) {
+		source := e.Get("target")
+		if !source.Truthy() || !source.Get("closest").Truthy() {
+			return
+		}
+
+		target := source.Call("closest", selector)
+		if !target.Truthy() || !ctx.JSSrc().Call("contains", target).Bool() {
+			return
+		}
+
+		de := DelegatedEvent{
+			Event:  e,
+			Target: target,
+		}
+		de.Key = de.Data("key")
+		h(ctx, de)
+	}
+}
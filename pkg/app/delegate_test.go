@@ -0,0 +1,108 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type delegateCompo struct {
+	Compo
+
+	selected []string
+	names    []string
+}
+
+func (c *delegateCompo) Render() UI {
+	return Ul().
+		OnClick(Delegate("li", c.onSelect)).
+		Body(
+			Li().DataSet("key", "1").Text("one"),
+			Li().DataSet("key", "2").Text("two"),
+		)
+}
+
+func (c *delegateCompo) onSelect(ctx Context, e DelegatedEvent) {
+	c.selected = append(c.selected, e.Key)
+	c.names = append(c.names, e.Data("name"))
+}
+
+func TestDelegate(t *testing.T) {
+	row := newSimulatedEvent("", map[string]interface{}{
+		"dataset": map[string]interface{}{
+			"key":  "2",
+			"name": "two",
+		},
+	})
+
+	utests := []struct {
+		scenario string
+		target   interface{}
+		contains bool
+		expected []string
+	}{
+		{
+			scenario: "matching descendant is handled",
+			target: map[string]interface{}{
+				"closest": func(args ...interface{}) Value {
+					return row
+				},
+			},
+			contains: true,
+			expected: []string{"2"},
+		},
+		{
+			scenario: "descendant that does not match is ignored",
+			target: map[string]interface{}{
+				"closest": func(args ...interface{}) Value {
+					return newSimulatedEvent("", nil).Get("none")
+				},
+			},
+			contains: true,
+		},
+		{
+			scenario: "match outside of the container is ignored",
+			target: map[string]interface{}{
+				"closest": func(args ...interface{}) Value {
+					return row
+				},
+			},
+		},
+		{
+			scenario: "target without closest is ignored",
+			target:   map[string]interface{}{},
+			contains: true,
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			compo := &delegateCompo{}
+			d := NewClientTester(compo)
+			defer d.Close()
+
+			container := compo.root
+			contains := u.contains
+			setSimulatedProperty(container, "contains", func(args ...interface{}) Value {
+				return newSimulatedEvent("", map[string]interface{}{"ok": contains}).Get("ok")
+			})
+
+			h := container.eventHandlers()["click"]
+			d.Dispatch(Dispatch{
+				Mode:   Update,
+				Source: container,
+				Function: func(ctx Context) {
+					h.value(ctx, Event{Value: newSimulatedEvent("click", map[string]interface{}{
+						"target": newSimulatedEvent("", u.target.(map[string]interface{})),
+					})})
+				},
+			})
+			d.Consume()
+
+			require.Equal(t, u.expected, compo.selected)
+			if u.expected != nil {
+				require.Equal(t, []string{"two"}, compo.names)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	desktopSetTitleBinding = "goappDesktopSetTitle"
+	desktopSetSizeBinding  = "goappDesktopSetSize"
+	desktopOpenFileBinding = "goappDesktopOpenFile"
+	desktopSaveFileBinding = "goappDesktopSaveFile"
+
+	defaultDesktopAddr   = "127.0.0.1:0"
+	defaultDesktopWidth  = 1024
+	defaultDesktopHeight = 768
+	desktopShutdownDelay = 5 * time.Second
+)
+
+// Webview is the interface that describes a native window that displays a web
+// page. It is satisfied by a thin wrapper around github.com/webview/webview:
+//  type webviewWindow struct {
+//      webview.WebView
+//  }
+//
+//  func (w webviewWindow) SetSize(width, height int) {
+//      w.WebView.SetSize(width, height, webview.HintNone)
+//  }
+//
+//  func main() {
+//      // ...
+//      w := webview.New(false)
+//      err := app.RunDesktop(h, webviewWindow{WebView: w}, app.Desktop{})
+//      // ...
+//  }
+type Webview interface {
+	// Sets the title of the window.
+	SetTitle(title string)
+
+	// Sets the size of the window.
+	SetSize(width, height int)
+
+	// Navigates to the given URL.
+	Navigate(url string)
+
+	// Binds a Go function to a global JavaScript function with the given
+	// name. The JavaScript function returns a promise that is resolved with
+	// the JSON encoded results of the Go function, or rejected with its error.
+	Bind(name string, fn interface{}) error
+
+	// Executes the given function on the window goroutine.
+	Dispatch(fn func())
+
+	// Runs the window event loop until the window is closed.
+	Run()
+
+	// Releases the window resources.
+	Destroy()
+}
+
+// FileDialogs is the interface that describes the native file dialogs of a
+// desktop app. It can be implemented with packages such as
+// github.com/sqweek/dialog.
+type FileDialogs interface {
+	// Displays a dialog to pick a file to open and returns its path. Filters
+	// are file extensions such as ".png". It returns an empty path when the
+	// user cancelled the dialog.
+	OpenFile(title string, filters []string) (string, error)
+
+	// Displays a dialog to pick the path where a file is saved, with the given
+	// default file name. It returns an empty path when the user cancelled the
+	// dialog.
+	SaveFile(title, name string) (string, error)
+}
+
+// Desktop describes the window of an app that is shipped as a desktop binary
+// with RunDesktop.
+type Desktop struct {
+	// The address where the app is served.
+	//
+	// Default: "127.0.0.1:0", which picks a free port on the loopback
+	// interface.
+	Addr string
+
+	// The title of the window.
+	//
+	// Default: the Handler title or name.
+	Title string
+
+	// The width of the window.
+	//
+	// Default: 1024.
+	Width int
+
+	// The height of the window.
+	//
+	// Default: 768.
+	Height int
+
+	// The native file dialogs used by Context.Desktop().OpenFile and
+	// Context.Desktop().SaveFile. File dialogs are not supported when nil.
+	FileDialogs FileDialogs
+}
+
+// RunDesktop serves the given handler on localhost and displays it in the given
+// webview window. It blocks until the window is closed.
+//
+// The window title and size, as well as the file dialogs, can be controlled
+// from the app with Context.Desktop.
+func RunDesktop(h http.Handler, w Webview, d Desktop) error {
+	if d.Addr == "" {
+		d.Addr = defaultDesktopAddr
+	}
+	if d.Title == "" {
+		if appHandler, ok := h.(*Handler); ok {
+			d.Title = appHandler.Title
+			if d.Title == "" {
+				d.Title = appHandler.Name
+			}
+		}
+	}
+	if d.Width <= 0 {
+		d.Width = defaultDesktopWidth
+	}
+	if d.Height <= 0 {
+		d.Height = defaultDesktopHeight
+	}
+
+	l, err := net.Listen("tcp", d.Addr)
+	if err != nil {
+		return errors.New("listening desktop address failed").
+			Tag("addr", d.Addr).
+			Wrap(err)
+	}
+
+	server := &http.Server{Handler: h}
+	go server.Serve(l)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), desktopShutdownDelay)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+	defer w.Destroy()
+
+	if err := bindDesktop(w, d.FileDialogs); err != nil {
+		return err
+	}
+
+	w.SetTitle(d.Title)
+	w.SetSize(d.Width, d.Height)
+	w.Navigate("http://" + l.Addr().String() + "/")
+	w.Run()
+	return nil
+}
+
+type desktopFile struct {
+	Path string `json:"path"`
+	Data []byte `json:"data"`
+}
+
+func bindDesktop(w Webview, dialogs FileDialogs) error {
+	bindings := map[string]interface{}{
+		desktopSetTitleBinding: func(title string) {
+			w.Dispatch(func() {
+				w.SetTitle(title)
+			})
+		},
+		desktopSetSizeBinding: func(width, height int) {
+			w.Dispatch(func() {
+				w.SetSize(width, height)
+			})
+		},
+	}
+
+	if dialogs != nil {
+		bindings[desktopOpenFileBinding] = func(title string, filters []string) (desktopFile, error) {
+			path, err := dialogs.OpenFile(title, filters)
+			if err != nil || path == "" {
+				return desktopFile{}, err
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return desktopFile{}, err
+			}
+			return desktopFile{Path: path, Data: data}, nil
+		}
+
+		bindings[desktopSaveFileBinding] = func(title, name string, data []byte) (string, error) {
+			path, err := dialogs.SaveFile(title, name)
+			if err != nil || path == "" {
+				return "", err
+			}
+			return path, ioutil.WriteFile(path, data, 0644)
+		}
+	}
+
+	for name, fn := range bindings {
+		if err := w.Bind(name, fn); err != nil {
+			return errors.New("binding desktop function failed").
+				Tag("name", name).
+				Wrap(err)
+		}
+	}
+	return nil
+}
+
+// DesktopWindow controls the native window of an app that is shipped as a
+// desktop binary with RunDesktop.
+//
+// Window operations do nothing when the app is not displayed in a desktop
+// window. File dialog results are passed to the given functions, called on the
+// UI goroutine. Operations do nothing on the server.
+type DesktopWindow struct {
+	ctx Context
+}
+
+// IsDesktop reports whether the app is displayed in a desktop window.
+func (d DesktopWindow) IsDesktop() bool {
+	return !IsServer && Window().Get(desktopSetTitleBinding).Truthy()
+}
+
+// SetTitle sets the title of the window.
+func (d DesktopWindow) SetTitle(title string) {
+	if d.IsDesktop() {
+		Window().Call(desktopSetTitleBinding, title)
+	}
+}
+
+// SetSize sets the size of the window.
+func (d DesktopWindow) SetSize(width, height int) {
+	if d.IsDesktop() {
+		Window().Call(desktopSetSizeBinding, width, height)
+	}
+}
+
+// OpenFile displays a native dialog to pick a file and passes its path and
+// content to the given function. The path is empty when the user cancelled the
+// dialog. Filters are file extensions such as ".png".
+func (d DesktopWindow) OpenFile(title string, filters []string, onDone func(ctx Context, path string, data []byte, err error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(path string, data []byte, err error) {
+		if err != nil {
+			err = errors.New("opening file failed").Wrap(err)
+		}
+		dispatchResult(d.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, path, data, err)
+		})
+	}
+
+	if !d.isFileDialogSupported(desktopOpenFileBinding) {
+		done("", nil, errors.New("file dialogs are not supported"))
+		return
+	}
+
+	jsFilters := make([]interface{}, len(filters))
+	for i, f := range filters {
+		jsFilters[i] = f
+	}
+
+	awaitPromise(Window().Call(desktopOpenFileBinding, title, jsFilters), func(file Value) {
+		path := jsOptionalString(file.Get("path"))
+		data, err := base64.StdEncoding.DecodeString(jsOptionalString(file.Get("data")))
+		done(path, data, err)
+	}, func(reason Value) {
+		done("", nil, jsReasonError(reason))
+	})
+}
+
+// SaveFile displays a native dialog to pick where the given data is saved,
+// with the given default file name, and passes the path of the saved file to
+// the given function. The path is empty when the user cancelled the dialog.
+func (d DesktopWindow) SaveFile(title, name string, data []byte, onDone func(ctx Context, path string, err error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(path string, err error) {
+		if err != nil {
+			err = errors.New("saving file failed").
+				Tag("name", name).
+				Wrap(err)
+		}
+		dispatchResult(d.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, path, err)
+		})
+	}
+
+	if !d.isFileDialogSupported(desktopSaveFileBinding) {
+		done("", errors.New("file dialogs are not supported"))
+		return
+	}
+
+	awaitPromise(Window().Call(desktopSaveFileBinding, title, name, base64.StdEncoding.EncodeToString(data)), func(path Value) {
+		done(jsOptionalString(path), nil)
+	}, func(reason Value) {
+		done("", jsReasonError(reason))
+	})
+}
+
+func (d DesktopWindow) isFileDialogSupported(binding string) bool {
+	return d.IsDesktop() && Window().Get(binding).Truthy()
+}
@@ -0,0 +1,147 @@
+//go:build !wasm
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type desktopTestWebview struct {
+	title     string
+	width     int
+	height    int
+	url       string
+	status    int
+	bindings  map[string]interface{}
+	destroyed bool
+}
+
+func (w *desktopTestWebview) SetTitle(title string) {
+	w.title = title
+}
+
+func (w *desktopTestWebview) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+}
+
+func (w *desktopTestWebview) Navigate(url string) {
+	w.url = url
+}
+
+func (w *desktopTestWebview) Bind(name string, fn interface{}) error {
+	if w.bindings == nil {
+		w.bindings = make(map[string]interface{})
+	}
+	w.bindings[name] = fn
+	return nil
+}
+
+func (w *desktopTestWebview) Dispatch(fn func()) {
+	fn()
+}
+
+func (w *desktopTestWebview) Run() {
+	res, err := http.Get(w.url)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	w.status = res.StatusCode
+}
+
+func (w *desktopTestWebview) Destroy() {
+	w.destroyed = true
+}
+
+type desktopTestDialogs struct {
+	path string
+}
+
+func (d desktopTestDialogs) OpenFile(title string, filters []string) (string, error) {
+	return d.path, nil
+}
+
+func (d desktopTestDialogs) SaveFile(title, name string) (string, error) {
+	return d.path, nil
+}
+
+func TestRunDesktop(t *testing.T) {
+	w := &desktopTestWebview{}
+	err := RunDesktop(&Handler{Title: "Desktop test"}, w, Desktop{})
+	require.NoError(t, err)
+
+	require.Equal(t, "Desktop test", w.title)
+	require.Equal(t, defaultDesktopWidth, w.width)
+	require.Equal(t, defaultDesktopHeight, w.height)
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/$`, w.url)
+	require.Equal(t, http.StatusOK, w.status)
+	require.True(t, w.destroyed)
+	require.Contains(t, w.bindings, desktopSetTitleBinding)
+	require.NotContains(t, w.bindings, desktopOpenFileBinding)
+
+	w.bindings[desktopSetTitleBinding].(func(string))("Renamed")
+	require.Equal(t, "Renamed", w.title)
+
+	w.bindings[desktopSetSizeBinding].(func(int, int))(800, 600)
+	require.Equal(t, 800, w.width)
+	require.Equal(t, 600, w.height)
+}
+
+func TestDesktopFileDialogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-desktop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "report.txt")
+
+	w := &desktopTestWebview{}
+	err = RunDesktop(&Handler{}, w, Desktop{
+		FileDialogs: desktopTestDialogs{path: path},
+	})
+	require.NoError(t, err)
+
+	save := w.bindings[desktopSaveFileBinding].(func(string, string, []byte) (string, error))
+	savedPath, err := save("Save", "report.txt", []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, path, savedPath)
+
+	open := w.bindings[desktopOpenFileBinding].(func(string, []string) (desktopFile, error))
+	file, err := open("Open", []string{".txt"})
+	require.NoError(t, err)
+	require.Equal(t, path, file.Path)
+	require.Equal(t, "hello", string(file.Data))
+
+	t.Run("cancelled dialog", func(t *testing.T) {
+		w := &desktopTestWebview{}
+		err := RunDesktop(&Handler{}, w, Desktop{
+			FileDialogs: desktopTestDialogs{},
+		})
+		require.NoError(t, err)
+
+		open := w.bindings[desktopOpenFileBinding].(func(string, []string) (desktopFile, error))
+		file, err := open("Open", nil)
+		require.NoError(t, err)
+		require.Empty(t, file.Path)
+	})
+}
+
+func TestDesktopWindowOnServer(t *testing.T) {
+	compo := &hello{}
+	d := NewServerTester(compo)
+	defer d.Close()
+
+	desktop := makeContext(compo).Desktop()
+	require.False(t, desktop.IsDesktop())
+	require.NotPanics(t, func() {
+		desktop.SetTitle("title")
+		desktop.SetSize(800, 600)
+		desktop.OpenFile("Open", nil, nil)
+		desktop.SaveFile("Save", "name", nil, nil)
+	})
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"net/url"
+	"time"
 )
 
 const (
@@ -62,8 +63,33 @@ type Dispatcher interface {
 	localStorage() BrowserStorage
 	sessionStorage() BrowserStorage
 	runsInServer() bool
+	serverSession() *Session
+	request() (Request, bool)
+	getenv(string) string
+	experiments() *experimentAssignments
+	perfMeasure(name, start, end string) time.Duration
+	hasCrashState() bool
+	restoreCrashState() bool
+	saveUpdateSnapshot()
+	redirect(url string, code int)
+	loadRoute(u *url.URL, path string, done func())
+	routeData(recv interface{}) error
+	routeParam(name string) string
+	currentRoutePath() string
+	parentRouteData(route string, recv interface{}) error
+	mountPage(from *url.URL, n UI)
+	isPageKept(u *url.URL) bool
+	restorePage(from, u *url.URL)
+	claims() Claims
+	locales() []string
+	timeZone() *time.Location
+	audit(kind, name string)
 	resolveStaticResource(string) string
 	removeFromUpdates(Composer)
+	suspended() bool
+	handleDurable(actionName string, src UI, h ActionHandler)
+	bindHandlers(Composer)
+	setHead(Composer, []UI)
 }
 
 // ClientDispatcher is the interface that describes a dispatcher that emulates a
@@ -93,6 +119,18 @@ type ClientDispatcher interface {
 	// Triggers OnAppInstallChange from the root component.
 	AppInstallChange()
 
+	// Triggers OnVersionSkew from the root component.
+	VersionSkew()
+
+	// Triggers OnAppInstalled from the root component.
+	AppInstalled()
+
+	// Triggers OnDisplayModeChange from the root component.
+	DisplayModeChange()
+
+	// Triggers OnAppLaunch from the root component.
+	AppLaunch(LaunchParams)
+
 	// Triggers OnAppResize from the root component.
 	AppResize()
 }
@@ -100,7 +138,10 @@ type ClientDispatcher interface {
 // NewClientTester creates a testing dispatcher that simulates a
 // client environment. The given UI element is mounted upon creation.
 func NewClientTester(n UI) ClientDispatcher {
-	e := &engine{ActionHandlers: actionHandlers}
+	e := &engine{
+		ActionHandlers:  actionHandlers,
+		ActionMailboxes: actionMailboxes,
+	}
 	e.init()
 	e.Mount(n)
 	e.Consume()
@@ -141,6 +182,7 @@ func NewServerTester(n UI) ServerDispatcher {
 // Dispatch represents an operation executed on the UI goroutine.
 type Dispatch struct {
 	Mode     DispatchMode
+	Priority DispatchPriority
 	Source   UI
 	Function func(Context)
 }
@@ -156,11 +198,38 @@ const (
 
 	// A dispatch mode that schedules the dispatched operation to be executed
 	// after the current update frame.
+	//
+	// Deferred operations are executed by source depth, parents first.
+	// Operations with the same source are executed in the order they were
+	// dispatched.
 	Defer
 
 	// A dispatch mode where the dispatched operation is enqueued to be executed
 	// as soon as possible.
 	Next
+
+	// A dispatch mode that schedules the dispatched operation to be executed
+	// once the browser has painted the current update frame. It is typically
+	// used to focus or scroll elements that were just rendered.
+	AfterPaint
+)
+
+// DispatchPriority represents the lane where a dispatch is enqueued. Pending
+// dispatches are executed by priority, highest first, and dispatches with the
+// same priority are executed in the order they were dispatched.
+type DispatchPriority int
+
+const (
+	// The priority of dispatches that don't specify one.
+	NormalPriority DispatchPriority = iota
+
+	// A priority for latency-sensitive operations, such as the handling of
+	// user inputs. Event handlers are dispatched with this priority.
+	HighPriority
+
+	// A priority for bulk operations that can wait for the other ones, such
+	// as background state updates.
+	LowPriority
 )
 
 // MsgHandler represents a handler to listen to messages sent with Context.Post.
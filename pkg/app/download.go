@@ -0,0 +1,316 @@
+package app
+
+import (
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The delay before revoking the object URL of a downloaded file. Revoking
+	// it right after the click can abort the download in some browsers.
+	downloadRevokeDelay = time.Second * 30
+)
+
+// DownloadOptions describes how a file is downloaded with
+// Context.DownloadURL.
+type DownloadOptions struct {
+	// The HTTP headers sent with the request.
+	Header map[string]string
+
+	// Reports whether the file is written to disk as it is received, with the
+	// File System Access API. The user is prompted for the location of the
+	// file. Files are buffered in memory when the browser does not support
+	// the API.
+	SaveToDisk bool
+
+	// The function called on the UI goroutine when a chunk of the file is
+	// received.
+	OnProgress func(Context, DownloadProgress)
+
+	// The function called on the UI goroutine when the download is completed.
+	// The error is not nil when the download failed or was canceled.
+	OnDone func(Context, error)
+}
+
+// DownloadProgress describes the progress of a download.
+type DownloadProgress struct {
+	// The number of bytes received.
+	Loaded int64
+
+	// The size of the file in bytes. It is 0 when the server does not
+	// provide a Content-Length header.
+	Total int64
+}
+
+// Progress returns the progress of the download, ranging from 0 to 1. It is 0
+// when the size of the file is unknown.
+func (p DownloadProgress) Progress() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Loaded) / float64(p.Total)
+}
+
+func download(ctx Context, filename, contentType string, r io.Reader) error {
+	if IsServer {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.New("downloading file failed").
+			Tag("filename", filename).
+			Wrap(err)
+	}
+
+	data := Window().Get("Uint8Array").New(len(b))
+	copyBytesToJS(data, b)
+
+	blob := Window().Get("Blob").New([]interface{}{data}, map[string]interface{}{
+		"type": contentType,
+	})
+	saveBlob(ctx, filename, blob)
+	return nil
+}
+
+// saveBlob makes the browser download the given blob as a file with the given
+// name.
+func saveBlob(ctx Context, filename string, blob Value) {
+	url := Window().Get("URL").Call("createObjectURL", blob).String()
+
+	doc := Window().Get("document")
+	anchor := doc.Call("createElement", "a")
+	anchor.Set("href", url)
+	anchor.Set("download", filename)
+	anchor.Get("style").Set("display", "none")
+	doc.Get("body").Call("appendChild", anchor)
+	anchor.Call("click")
+	doc.Get("body").Call("removeChild", anchor)
+
+	ctx.After(downloadRevokeDelay, func(Context) {
+		Window().Get("URL").Call("revokeObjectURL", url)
+	})
+}
+
+// streamDownload fetches the file at the given URL chunk by chunk and either
+// writes the chunks to a file picked by the user or buffers them in a blob.
+// The request is aborted when the context is canceled.
+type streamDownload struct {
+	ctx      Context
+	url      string
+	filename string
+	opts     DownloadOptions
+
+	controller  Value
+	writable    Value
+	reader      Value
+	chunks      []interface{}
+	contentType string
+	progress    DownloadProgress
+	finished    chan struct{}
+}
+
+func downloadURL(ctx Context, url, filename string, opts DownloadOptions) {
+	if IsServer {
+		return
+	}
+
+	if !Window().Get("fetch").Truthy() || !Window().Get("AbortController").Truthy() {
+		ctx.Dispatch(func(ctx Context) {
+			if opts.OnDone != nil {
+				opts.OnDone(ctx, errors.New("downloading file failed").
+					Tag("url", url).
+					Tag("reason", "fetch is not supported"))
+			}
+		})
+		return
+	}
+
+	d := &streamDownload{
+		ctx:        ctx,
+		url:        url,
+		filename:   filename,
+		opts:       opts,
+		controller: Window().Get("AbortController").New(),
+		finished:   make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.controller.Call("abort")
+
+		case <-d.finished:
+		}
+	}()
+
+	if opts.SaveToDisk && Window().Get("showSaveFilePicker").Truthy() {
+		d.openFile()
+		return
+	}
+	d.fetch()
+}
+
+func (d *streamDownload) openFile() {
+	picker := Window().Call("showSaveFilePicker", map[string]interface{}{
+		"suggestedName": d.filename,
+	})
+	awaitPromise(picker, func(handle Value) {
+		awaitPromise(handle.Call("createWritable"), func(writable Value) {
+			d.writable = writable
+			d.fetch()
+		}, d.fail)
+	}, d.fail)
+}
+
+func (d *streamDownload) fetch() {
+	header := make(map[string]interface{}, len(d.opts.Header))
+	for k, v := range d.opts.Header {
+		header[k] = v
+	}
+
+	res := Window().Call("fetch", d.url, map[string]interface{}{
+		"headers": header,
+		"signal":  d.controller.Get("signal"),
+	})
+	awaitPromise(res, d.read, d.fail)
+}
+
+func (d *streamDownload) read(res Value) {
+	if !res.Get("ok").Bool() {
+		d.finish(errors.New("downloading file failed").
+			Tag("url", d.url).
+			Tag("status", res.Get("status").Int()))
+		return
+	}
+
+	d.contentType = res.Get("headers").Call("get", "Content-Type").String()
+	if length := res.Get("headers").Call("get", "Content-Length"); length.Truthy() {
+		d.progress.Total = int64(Window().Call("parseInt", length).Float())
+	}
+
+	body := res.Get("body")
+	if !body.Truthy() || !body.Get("getReader").Truthy() {
+		awaitPromise(res.Call("blob"), func(blob Value) {
+			d.progress.Loaded = int64(blob.Get("size").Float())
+			d.notifyProgress()
+			d.chunks = append(d.chunks, blob)
+			d.complete()
+		}, d.fail)
+		return
+	}
+
+	d.reader = body.Call("getReader")
+	d.readChunk()
+}
+
+func (d *streamDownload) readChunk() {
+	awaitPromise(d.reader.Call("read"), func(result Value) {
+		if result.Get("done").Bool() {
+			d.complete()
+			return
+		}
+
+		chunk := result.Get("value")
+		d.progress.Loaded += int64(chunk.Length())
+		d.notifyProgress()
+
+		if d.writable != nil {
+			awaitPromise(d.writable.Call("write", chunk), func(Value) {
+				d.readChunk()
+			}, d.fail)
+			return
+		}
+
+		d.chunks = append(d.chunks, chunk)
+		d.readChunk()
+	}, d.fail)
+}
+
+func (d *streamDownload) complete() {
+	if d.writable != nil {
+		awaitPromise(d.writable.Call("close"), func(Value) {
+			d.finish(nil)
+		}, d.fail)
+		return
+	}
+
+	blob := Window().Get("Blob").New(d.chunks, map[string]interface{}{
+		"type": d.contentType,
+	})
+	saveBlob(d.ctx, d.filename, blob)
+	d.chunks = nil
+	d.finish(nil)
+}
+
+func (d *streamDownload) fail(reason Value) {
+	if d.writable != nil {
+		d.writable.Call("abort")
+	}
+	d.chunks = nil
+
+	err := errors.New("downloading file failed").Tag("url", d.url)
+	if reason != nil && reason.Truthy() {
+		err = err.
+			Tag("name", reason.Get("name").String()).
+			Tag("reason", reason.Get("message").String())
+	}
+	d.finish(err)
+}
+
+func (d *streamDownload) notifyProgress() {
+	if d.opts.OnProgress == nil {
+		return
+	}
+
+	progress := d.progress
+	d.ctx.Dispatch(func(ctx Context) {
+		d.opts.OnProgress(ctx, progress)
+	})
+}
+
+func (d *streamDownload) finish(err error) {
+	close(d.finished)
+
+	if d.opts.OnDone == nil {
+		if err != nil {
+			Log(err)
+		}
+		return
+	}
+	d.ctx.Dispatch(func(ctx Context) {
+		d.opts.OnDone(ctx, err)
+	})
+}
+
+// awaitPromise calls onFulfilled or onRejected with the value the given
+// promise settles with.
+func awaitPromise(p Value, onFulfilled, onRejected func(Value)) {
+	var fulfilled, rejected Func
+	release := func() {
+		fulfilled.Release()
+		rejected.Release()
+	}
+
+	fulfilled = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		onFulfilled(promiseArg(args))
+		return nil
+	})
+	rejected = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		onRejected(promiseArg(args))
+		return nil
+	})
+	p.Call("then", fulfilled, rejected)
+}
+
+func promiseArg(args []Value) Value {
+	if len(args) == 0 {
+		return Undefined()
+	}
+	return args[0]
+}
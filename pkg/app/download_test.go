@@ -0,0 +1,35 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	err := makeContext(h).Download("hello.txt", "text/plain", strings.NewReader("hello"))
+	require.NoError(t, err)
+}
+
+func TestDownloadURLOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	done := false
+	makeContext(h).DownloadURL("/web/hello.txt", "hello.txt", DownloadOptions{
+		OnDone: func(Context, error) { done = true },
+	})
+	d.Consume()
+	require.False(t, done)
+}
+
+func TestDownloadProgress(t *testing.T) {
+	require.Zero(t, DownloadProgress{Loaded: 42}.Progress())
+	require.Equal(t, 0.5, DownloadProgress{Loaded: 21, Total: 42}.Progress())
+}
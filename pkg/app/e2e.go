@@ -0,0 +1,117 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The environment variable that enables the end-to-end testing bridge.
+	// It is set by the harness of the e2e package.
+	e2eEnv = "GOAPP_E2E"
+)
+
+// startE2EBridge exposes the goappE2E JavaScript object that lets end-to-end
+// tests inspect the app from the browser when GOAPP_E2E is set. Its functions
+// return promises that are resolved with JSON once the pending updates are
+// performed:
+//  - components(name): the mounted components with the given type name, such
+//    as "*main.hello" or "hello", encoded with their exported fields.
+//  - state(key): the value of the given state.
+//  - settle(): null.
+func (e *engine) startE2EBridge() {
+	if IsServer || Getenv(e2eEnv) == "" {
+		return
+	}
+
+	bridge := Window().Get("Object").New()
+	bridge.Set("components", e.e2eFunc(func(ctx Context, args []Value) (interface{}, error) {
+		return e2eComponents(ctx.Src(), args[0].String()), nil
+	}))
+	bridge.Set("state", e.e2eFunc(func(ctx Context, args []Value) (interface{}, error) {
+		return e.states.json(args[0].String())
+	}))
+	bridge.Set("settle", e.e2eFunc(func(ctx Context, args []Value) (interface{}, error) {
+		return nil, nil
+	}))
+	Window().Set("goappE2E", bridge)
+}
+
+// e2eFunc returns a JavaScript function that executes the given function on
+// the UI goroutine, once the pending updates are performed, and returns a
+// promise resolved with its JSON encoded result.
+func (e *engine) e2eFunc(fn func(Context, []Value) (interface{}, error)) Func {
+	return FuncOf(func(this Value, args []Value) interface{} {
+		var executor Func
+		executor = FuncOf(func(this Value, promise []Value) interface{} {
+			defer executor.Release()
+
+			resolve := promise[0]
+			reject := promise[1]
+			e.Dispatch(Dispatch{
+				Mode: Defer,
+				Function: func(ctx Context) {
+					v, err := fn(ctx, args)
+					if err != nil {
+						reject.Invoke(err.Error())
+						return
+					}
+
+					b, err := json.Marshal(v)
+					if err != nil {
+						reject.Invoke(errors.New("encoding e2e result failed").Wrap(err).Error())
+						return
+					}
+					resolve.Invoke(string(b))
+				},
+			})
+			return nil
+		})
+		return Window().Get("Promise").New(executor)
+	})
+}
+
+// e2eComponents returns the mounted components of the given tree whose type
+// is named with the given name.
+func e2eComponents(n UI, name string) []Composer {
+	var compos []Composer
+	if c, ok := n.(Composer); ok && isComponentNamed(c, name) {
+		compos = append(compos, c)
+	}
+	for _, c := range n.children() {
+		compos = append(compos, e2eComponents(c, name)...)
+	}
+	return compos
+}
+
+func isComponentNamed(c Composer, name string) bool {
+	t := reflect.TypeOf(c)
+	if t.String() == name {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name() == name
+}
+
+// json returns the JSON encoded value of the given state. It returns null when
+// the state is not set.
+func (s *store) json(key string) (json.RawMessage, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state := s.states[key]
+	switch {
+	case state.value != nil:
+		return json.Marshal(state.value)
+
+	case state.raw != nil:
+		return state.raw, nil
+
+	default:
+		return json.RawMessage("null"), nil
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2EComponents(t *testing.T) {
+	compo := &foo{Bar: "bar"}
+	d := NewServerTester(compo)
+	defer d.Close()
+
+	require.Len(t, e2eComponents(compo, "*app.foo"), 1)
+	require.Len(t, e2eComponents(compo, "bar"), 1)
+	require.Empty(t, e2eComponents(compo, "hello"))
+}
+
+func TestStoreJSON(t *testing.T) {
+	s := newStore(&engine{})
+	defer s.Close()
+
+	b, err := s.json("/missing")
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage("null"), b)
+
+	s.Set("/count", 42)
+	b, err = s.json("/count")
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage("42"), b)
+}
+
+func TestStartE2EBridge(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+	e.startE2EBridge()
+}
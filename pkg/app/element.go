@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/maxence-charriere/go-app/v9/pkg/errors"
 )
@@ -13,20 +14,32 @@ type elemWithChildren interface {
 	UI
 
 	replaceChildAt(idx int, new UI) error
+	swapChildAt(idx int, new UI) (UI, error)
+}
+
+type selfClosingElem interface {
+	UI
+
+	isSelfClosing() bool
 }
 
 type elem struct {
-	attrs       map[string]string
-	body        []UI
-	disp        Dispatcher
-	ctx         context.Context
-	ctxCancel   func()
-	events      map[string]eventHandler
-	jsvalue     Value
-	parentElem  UI
-	selfClosing bool
-	tag         string
-	this        UI
+	attrs        map[string]string
+	body         []UI
+	disp         Dispatcher
+	ctx          context.Context
+	ctxCancel    func()
+	events       map[string]eventHandler
+	jsvalue      Value
+	parentElem   UI
+	selfClosing  bool
+	srcsetWidths []int
+	tag          string
+	this         UI
+}
+
+func (e *elem) isSelfClosing() bool {
+	return e.selfClosing
 }
 
 func (e *elem) Kind() Kind {
@@ -37,6 +50,10 @@ func (e *elem) JSValue() Value {
 	return e.jsvalue
 }
 
+func (e *elem) setJSValue(v Value) {
+	e.jsvalue = v
+}
+
 func (e *elem) Mounted() bool {
 	return e.dispatcher() != nil &&
 		e.ctx != nil &&
@@ -127,6 +144,66 @@ func (e *elem) mount(d Dispatcher) error {
 	return nil
 }
 
+func (e *elem) hydrate(d Dispatcher, v Value) error {
+	if e.Mounted() {
+		return errors.New("hydrating ui element failed").
+			Tag("reason", "already mounted").
+			Tag("name", e.name()).
+			Tag("kind", e.Kind())
+	}
+
+	if v.Get("nodeType").Int() != elementNode ||
+		!strings.EqualFold(v.Get("tagName").String(), e.tag) {
+		return errHydrationMismatch(e, v)
+	}
+
+	e.disp = d
+	e.ctx, e.ctxCancel = context.WithCancel(context.Background())
+	e.jsvalue = v
+
+	for _, k := range jsAttrNames(v) {
+		if _, ok := e.attrs[k]; !ok {
+			v.delAttr(k)
+		}
+	}
+
+	for k, val := range e.attrs {
+		val = e.resolveURLAttr(k, val)
+		e.attrs[k] = val
+		if v.getAttr(k) != val {
+			e.setJsAttr(k, val)
+		}
+	}
+
+	for k, h := range e.events {
+		e.setJsEventHandler(k, h)
+	}
+
+	nodes := hydratableChildNodes(v)
+	for i, c := range e.children() {
+		c.setParent(e.self())
+
+		var err error
+		if i < len(nodes) {
+			err = hydrate(d, c, nodes[i])
+		} else if err = mount(d, c); err == nil {
+			v.appendChild(c)
+		}
+
+		if err != nil {
+			return errors.New("hydrating ui element failed").
+				Tag("name", e.name()).
+				Tag("kind", e.Kind()).
+				Wrap(err)
+		}
+	}
+
+	for i := len(e.children()); i < len(nodes); i++ {
+		v.removeChild(nodes[i])
+	}
+	return nil
+}
+
 func (e *elem) dismount() {
 	for _, c := range e.children() {
 		dismount(c)
@@ -255,6 +332,32 @@ func (e *elem) replaceChildAt(idx int, new UI) error {
 	return nil
 }
 
+// swapChildAt replaces the child at the given index with the given element
+// and returns the replaced child, which stays mounted. The given element is
+// mounted when it is not already.
+func (e *elem) swapChildAt(idx int, new UI) (UI, error) {
+	old := e.body[idx]
+
+	if !new.Mounted() {
+		if err := mount(e.dispatcher(), new); err != nil {
+			return nil, errors.New("swapping child failed").
+				Tag("name", e.name()).
+				Tag("kind", e.Kind()).
+				Tag("index", idx).
+				Tag("old-name", old.name()).
+				Tag("old-kind", old.Kind()).
+				Tag("new-name", new.name()).
+				Tag("new-kind", new.Kind()).
+				Wrap(err)
+		}
+	}
+
+	e.body[idx] = new
+	new.setParent(e.self())
+	e.JSValue().replaceChild(new, old)
+	return old, nil
+}
+
 func (e *elem) removeChildAt(idx int) error {
 	body := e.body
 	if idx < 0 || idx >= len(body) {
@@ -315,11 +418,25 @@ func (e *elem) setAttr(k string, v interface{}) {
 		s += toString(v)
 		e.attrs[k] = s
 
+	case "src":
+		e.attrs[k] = toString(v)
+		if len(e.srcsetWidths) != 0 {
+			e.attrs["srcset"] = ImageSrcSet(e.attrs[k], e.srcsetWidths...)
+		}
+
 	default:
 		e.attrs[k] = toString(v)
 	}
 }
 
+// setResponsiveSrcSet sets the srcset with the URLs of the image set by the
+// src attribute, resized to the given widths. The srcset is updated when the
+// src attribute is set afterward.
+func (e *elem) setResponsiveSrcSet(widths []int) {
+	e.srcsetWidths = widths
+	e.setAttr("srcset", ImageSrcSet(e.attrs["src"], widths...))
+}
+
 func (e *elem) resolveURLAttr(k, v string) string {
 	if !isURLAttrValue(k) {
 		return v
@@ -330,7 +447,7 @@ func (e *elem) resolveURLAttr(k, v string) string {
 func (e *elem) setJsAttr(k, v string) {
 	switch k {
 	case "value":
-		e.JSValue().Set("value", v)
+		setInputValue(e.JSValue(), v)
 
 	case "class":
 		e.JSValue().Set("className", v)
@@ -405,17 +522,26 @@ func (e *elem) setEventHandler(k string, h EventHandler, scope ...interface{}) {
 		e.events = make(map[string]eventHandler)
 	}
 
+	modifiers, scope := splitEventModifiers(k, scope)
 	e.events[k] = eventHandler{
-		event: k,
-		scope: toPath(scope...),
-		value: h,
+		event:     k,
+		scope:     toPath(scope...),
+		modifiers: modifiers,
+		value:     h,
 	}
 }
 
 func (e *elem) setJsEventHandler(k string, h eventHandler) {
-	jshandler := makeJsEventHandler(e.self(), h.value)
+	jshandler := makeJsEventHandler(e.self(), h.value, h.modifiers)
 	h.jsvalue = jshandler
 	e.events[k] = h
+
+	if h.modifiers.is(Passive) {
+		e.JSValue().Call("addEventListener", k, jshandler, map[string]interface{}{
+			"passive": true,
+		})
+		return
+	}
 	e.JSValue().addEventListener(k, jshandler)
 }
 
@@ -460,6 +586,30 @@ func (e *elem) onResize() {
 	}
 }
 
+func (e *elem) onVersionSkew() {
+	for _, c := range e.children() {
+		c.onVersionSkew()
+	}
+}
+
+func (e *elem) onAppInstalled() {
+	for _, c := range e.children() {
+		c.onAppInstalled()
+	}
+}
+
+func (e *elem) onDisplayModeChange() {
+	for _, c := range e.children() {
+		c.onDisplayModeChange()
+	}
+}
+
+func (e *elem) onAppLaunch(p LaunchParams) {
+	for _, c := range e.children() {
+		c.onAppLaunch(p)
+	}
+}
+
 func (e *elem) preRender(p Page) {
 	for _, c := range e.children() {
 		c.preRender(p)
@@ -0,0 +1,325 @@
+package app
+
+import (
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	emailSelectorRegexp     = regexp.MustCompile(`^(\*|[a-zA-Z][a-zA-Z0-9-]*)?((?:[.#][a-zA-Z0-9_-]+)*)$`)
+	emailSelectorPartRegexp = regexp.MustCompile(`[.#][^.#]+`)
+)
+
+// EmailOptions describes how a component tree is rendered as an email with
+// RenderEmail.
+type EmailOptions struct {
+	// The title of the email document.
+	Title string
+
+	// The CSS rules applied to the email. Rules with simple selectors, such
+	// as "p", ".button", "#header" or "a.button", are inlined into the style
+	// attribute of the matching elements. Other rules, such as media queries
+	// or rules with pseudo-classes and combinators, are kept in a style
+	// element for the clients that support it.
+	Styles string
+
+	// The URL used to resolve the relative URLs of links and images, which
+	// are not supported by email clients. Eg: "https://murlok.io".
+	BaseURL string
+}
+
+// RenderEmail returns email-safe HTML of the given UI element, which allows to
+// author transactional emails with the same components as the app.
+//
+// The element is mounted and prerendered as on the server, which means that
+// components can load their data in OnPreRender. The resulting tree is then
+// flattened:
+//  - CSS rules are inlined into style attributes.
+//  - Layout elements such as div, section, header or footer are replaced by
+//    presentation tables.
+//  - Scripts, frames, links to stylesheets and event handler attributes are
+//    removed.
+//  - Relative URLs are resolved with the base URL.
+//
+// Raw HTML elements are written as is.
+//
+// eg:
+//  body := app.RenderEmail(&welcomeEmail{Name: "Max"}, app.EmailOptions{
+//      Title:   "Welcome",
+//      Styles:  ".button { background-color: #2196f3; color: white; }",
+//      BaseURL: "https://murlok.io",
+//  })
+func RenderEmail(ui UI, opts EmailOptions) string {
+	disp := engine{
+		RunsInServer:   true,
+		ActionHandlers: actionHandlers,
+	}
+	disp.init()
+	defer disp.Close()
+
+	disp.Mount(ui)
+	disp.Consume()
+	disp.PreRender()
+	for disp.pendingDispatches() != 0 {
+		disp.Consume()
+		disp.Wait()
+	}
+
+	r := emailRenderer{
+		rules: parseEmailStyles(opts.Styles),
+	}
+	if opts.BaseURL != "" {
+		if u, err := url.Parse(opts.BaseURL); err == nil {
+			r.baseURL = u
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n")
+	b.WriteString(`<html><head><meta charset="UTF-8">`)
+	b.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">`)
+	if opts.Title != "" {
+		b.WriteString("<title>" + html.EscapeString(opts.Title) + "</title>")
+	}
+	if len(r.rules.remaining) != 0 {
+		b.WriteString("<style>" + strings.Join(r.rules.remaining, "\n") + "</style>")
+	}
+	b.WriteString(`</head><body style="margin:0;padding:0;">`)
+	for _, c := range disp.Body.children() {
+		r.write(&b, c)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+type emailRule struct {
+	tag          string
+	ids          []string
+	classes      []string
+	declarations string
+	specificity  int
+}
+
+func (r emailRule) match(tag string, attrs map[string]string) bool {
+	if r.tag != "" && r.tag != "*" && r.tag != tag {
+		return false
+	}
+
+	for _, id := range r.ids {
+		if attrs["id"] != id {
+			return false
+		}
+	}
+
+	classes := strings.Fields(attrs["class"])
+	for _, c := range r.classes {
+		if !stringsContains(classes, c) {
+			return false
+		}
+	}
+	return true
+}
+
+type emailStyles struct {
+	inlined   []emailRule
+	remaining []string
+}
+
+// parseEmailStyles splits the given CSS into the rules that can be inlined,
+// sorted by specificity, and the rules that can't.
+func parseEmailStyles(css string) emailStyles {
+	var styles emailStyles
+
+	for _, block := range splitCSSBlocks(css) {
+		open := strings.Index(block, "{")
+		selectors := strings.TrimSpace(block[:open])
+		declarations := strings.TrimSpace(block[open+1 : len(block)-1])
+
+		if strings.HasPrefix(selectors, "@") {
+			styles.remaining = append(styles.remaining, block)
+			continue
+		}
+		if declarations != "" && !strings.HasSuffix(declarations, ";") {
+			declarations += ";"
+		}
+
+		for _, s := range strings.Split(selectors, ",") {
+			s = strings.TrimSpace(s)
+			rule, ok := parseEmailSelector(s)
+			if !ok {
+				styles.remaining = append(styles.remaining, s+" { "+declarations+" }")
+				continue
+			}
+
+			rule.declarations = declarations
+			styles.inlined = append(styles.inlined, rule)
+		}
+	}
+
+	sort.SliceStable(styles.inlined, func(i, j int) bool {
+		return styles.inlined[i].specificity < styles.inlined[j].specificity
+	})
+	return styles
+}
+
+// splitCSSBlocks returns the top level blocks of the given CSS, including their
+// braces.
+func splitCSSBlocks(css string) []string {
+	var blocks []string
+	depth := 0
+	start := 0
+
+	for i, c := range css {
+		switch c {
+		case '{':
+			depth++
+
+		case '}':
+			if depth == 0 {
+				start = i + 1
+				continue
+			}
+
+			depth--
+			if depth == 0 {
+				blocks = append(blocks, strings.TrimSpace(css[start:i+1]))
+				start = i + 1
+			}
+		}
+	}
+	return blocks
+}
+
+func parseEmailSelector(s string) (emailRule, bool) {
+	m := emailSelectorRegexp.FindStringSubmatch(s)
+	if m == nil || s == "" {
+		return emailRule{}, false
+	}
+
+	rule := emailRule{tag: strings.ToLower(m[1])}
+	if rule.tag != "" && rule.tag != "*" {
+		rule.specificity = 1
+	}
+
+	for _, part := range emailSelectorPartRegexp.FindAllString(m[2], -1) {
+		switch part[0] {
+		case '#':
+			rule.ids = append(rule.ids, part[1:])
+			rule.specificity += 10000
+
+		case '.':
+			rule.classes = append(rule.classes, part[1:])
+			rule.specificity += 100
+		}
+	}
+	return rule, true
+}
+
+type emailRenderer struct {
+	rules   emailStyles
+	baseURL *url.URL
+}
+
+func (r *emailRenderer) write(w io.Writer, n UI) {
+	switch n.Kind() {
+	case HTML:
+		r.writeElem(w, n)
+
+	case SimpleText, RawHTML:
+		n.html(w)
+
+	default:
+		for _, c := range n.children() {
+			r.write(w, c)
+		}
+	}
+}
+
+func (r *emailRenderer) writeElem(w io.Writer, n UI) {
+	tag := n.name()
+	switch tag {
+	case "script", "noscript", "iframe", "object", "embed", "link", "template", "base":
+		return
+	}
+
+	attrs := r.attributes(tag, n.attributes())
+
+	switch tag {
+	case "div", "section", "article", "header", "footer", "main", "nav", "aside":
+		io.WriteString(w, `<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr>`)
+		r.writeTag(w, "td", attrs, n)
+		io.WriteString(w, "</tr></table>")
+
+	default:
+		r.writeTag(w, tag, attrs, n)
+	}
+}
+
+func (r *emailRenderer) writeTag(w io.Writer, tag string, attrs map[string]string, n UI) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	io.WriteString(w, "<"+tag)
+	for _, k := range keys {
+		io.WriteString(w, " "+k)
+		if v := attrs[k]; v != "" {
+			io.WriteString(w, `="`+html.EscapeString(v)+`"`)
+		}
+	}
+	io.WriteString(w, ">")
+
+	if e, ok := n.(selfClosingElem); ok && e.isSelfClosing() {
+		return
+	}
+
+	for _, c := range n.children() {
+		r.write(w, c)
+	}
+	io.WriteString(w, "</"+tag+">")
+}
+
+// attributes returns the email-safe attributes of an element, with the
+// matching CSS rules inlined.
+func (r *emailRenderer) attributes(tag string, attrs map[string]string) map[string]string {
+	res := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		if strings.HasPrefix(k, "on") {
+			continue
+		}
+		if isURLAttrValue(k) {
+			v = r.resolveURL(v)
+		}
+		res[k] = v
+	}
+
+	var style strings.Builder
+	for _, rule := range r.rules.inlined {
+		if rule.match(tag, attrs) {
+			style.WriteString(rule.declarations)
+		}
+	}
+	style.WriteString(attrs["style"])
+	if style.Len() != 0 {
+		res["style"] = style.String()
+	}
+	return res
+}
+
+func (r *emailRenderer) resolveURL(v string) string {
+	if r.baseURL == nil {
+		return v
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || u.IsAbs() || strings.HasPrefix(v, "#") {
+		return v
+	}
+	return r.baseURL.ResolveReference(u).String()
+}
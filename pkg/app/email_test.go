@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type emailTestCompo struct {
+	Compo
+
+	Name string
+}
+
+func (c *emailTestCompo) OnPreRender(ctx Context) {
+	c.Name = "Max"
+}
+
+func (c *emailTestCompo) Render() UI {
+	return Div().
+		ID("header").
+		Body(
+			H1().Text("Hello "+c.Name),
+			Img().Src("/web/logo.png"),
+			A().
+				Class("button").
+				Class("primary").
+				Href("/confirm").
+				Style("padding", "8px").
+				Text("Confirm"),
+			Script().Text("alert('hello')"),
+		)
+}
+
+func TestRenderEmail(t *testing.T) {
+	email := RenderEmail(&emailTestCompo{}, EmailOptions{
+		Title: "Welcome",
+		Styles: `
+			h1 { font-size: 24px }
+			.button { color: white; }
+			a.primary, #header { background-color: blue; }
+			a:hover { color: red; }
+			@media (max-width: 600px) { h1 { font-size: 18px; } }
+		`,
+		BaseURL: "https://murlok.io",
+	})
+
+	require.Contains(t, email, "<title>Welcome</title>")
+	require.Contains(t, email, `<h1 style="font-size: 24px;">Hello Max</h1>`)
+	require.Contains(t, email, `<img src="https://murlok.io/web/logo.png">`)
+	require.Contains(t, email, `<a class="button primary" href="https://murlok.io/confirm" style="color: white;background-color: blue;padding:8px;">Confirm</a>`)
+	require.Contains(t, email, `<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr><td id="header" style="background-color: blue;">`)
+	require.Contains(t, email, `a:hover { color: red; }`)
+	require.Contains(t, email, `@media (max-width: 600px) { h1 { font-size: 18px; } }`)
+	require.NotContains(t, email, "<div")
+	require.NotContains(t, email, "<script")
+	require.NotContains(t, email, "alert")
+}
+
+func TestParseEmailSelector(t *testing.T) {
+	utests := []struct {
+		selector    string
+		valid       bool
+		specificity int
+	}{
+		{selector: "p", valid: true, specificity: 1},
+		{selector: "*", valid: true, specificity: 0},
+		{selector: ".button", valid: true, specificity: 100},
+		{selector: "a.button.primary", valid: true, specificity: 201},
+		{selector: "#header.dark", valid: true, specificity: 10100},
+		{selector: "a:hover"},
+		{selector: "div p"},
+		{selector: "ul > li"},
+		{selector: ""},
+	}
+
+	for _, u := range utests {
+		t.Run(u.selector, func(t *testing.T) {
+			rule, ok := parseEmailSelector(u.selector)
+			require.Equal(t, u.valid, ok)
+			require.Equal(t, u.specificity, rule.specificity)
+		})
+	}
+}
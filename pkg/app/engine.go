@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"net/url"
+	"reflect"
 	"sort"
 	"sync"
 	"time"
@@ -20,6 +23,11 @@ type engine struct {
 	// The rate where component updates are performed (per seconds).
 	UpdateRate int
 
+	// The maximum duration of an update cycle. Components that are not updated
+	// within the budget are updated during the next cycles. There is no limit
+	// when zero.
+	UpdateBudget time.Duration
+
 	// The page.
 	Page Page
 
@@ -42,18 +50,87 @@ type engine struct {
 	// executed asynchronously.
 	ActionHandlers map[string]ActionHandler
 
+	// The mailboxes that buffer the actions posted while no handler is
+	// mounted.
+	ActionMailboxes map[string]Mailbox
+
+	// The tracer used to record dispatches and component updates. Tracing is
+	// disabled when nil.
+	Tracer Tracer
+
+	// The context used as parent for the spans recorded by the tracer.
+	TraceContext context.Context
+
+	// The policy that decides whether component updates are suspended while
+	// the page is hidden.
+	SuspendPolicy SuspendPolicy
+
+	// The server session of the request being prerendered.
+	Session *Session
+
+	// The HTTP request of the page being prerendered.
+	Request *http.Request
+
+	// The environment variables of the request being prerendered.
+	Env Environment
+
+	// The experiment variants assigned to the user.
+	Experiments *experimentAssignments
+
+	// The claims of the user for which the page is prerendered.
+	Claims Claims
+
+	// The sink where user actions are recorded. The audit log is disabled
+	// when nil.
+	AuditSink AuditSink
+
+	// The locales of the app. The first one is the default locale.
+	Locales []string
+
+	// The time zone of the user. It is UTC when nil.
+	TimeZone *time.Location
+
+	// The buffers reused from a previous engine. Dispatches whose source is
+	// not an element of the engine are ignored, which prevents operations that
+	// are dispatched after the previous engine is closed from being executed.
+	buffers *engineBuffers
+
 	initOnce  sync.Once
 	startOnce sync.Once
 	closeOnce sync.Once
 	wait      sync.WaitGroup
 
-	isMountedOnce bool
-	dispatches    chan Dispatch
-	updates       map[Composer]struct{}
-	updateQueue   []updateDescriptor
-	defers        []Dispatch
-	actions       actionManager
-	states        *store
+	isMountedOnce           bool
+	isUpdatedOnce           bool
+	isMemoryWarned          bool
+	isAccessibilityModified bool
+	updateCount             int
+	accessibilityIssues     map[accessibilityIssue]struct{}
+	dispatchSource          UI
+	pageRedirect            *pageRedirect
+	route                   routeData
+	routeParams             map[string]string
+	routePath               string
+	routeLoads              int
+	cancelRouteLoad         func()
+	displayedRoute          routeData
+	displayedRouteParams    map[string]string
+	displayedRoutePath      string
+	keptPages               keptPages
+	crashStates             map[string]json.RawMessage
+	lastCrashSnapshot       []byte
+	lastVersionCheck        time.Time
+	dispatches              chan Dispatch
+	highDispatches          chan Dispatch
+	lowDispatches           chan Dispatch
+	updates                 map[Composer]struct{}
+	updateQueue             []updateDescriptor
+	defers                  []Dispatch
+	actions                 actionManager
+	heads                   headManager
+	states                  *store
+	isSuspended             int32
+	isRequestUsed           int32
 }
 
 func (e *engine) Dispatch(d Dispatch) {
@@ -63,7 +140,65 @@ func (e *engine) Dispatch(d Dispatch) {
 	if d.Function == nil {
 		d.Function = func(Context) {}
 	}
-	e.dispatches <- d
+
+	switch d.Priority {
+	case HighPriority:
+		e.highDispatches <- d
+
+	case LowPriority:
+		e.lowDispatches <- d
+
+	default:
+		e.dispatches <- d
+	}
+}
+
+// nextDispatch returns the pending dispatch with the highest priority, if
+// any.
+func (e *engine) nextDispatch() (Dispatch, bool) {
+	select {
+	case d := <-e.highDispatches:
+		return d, true
+	default:
+	}
+
+	select {
+	case d := <-e.dispatches:
+		return d, true
+	default:
+	}
+
+	select {
+	case d := <-e.lowDispatches:
+		return d, true
+	default:
+		return Dispatch{}, false
+	}
+}
+
+// pendingDispatches returns the number of dispatches that are waiting to be
+// executed.
+func (e *engine) pendingDispatches() int {
+	return len(e.highDispatches) + len(e.dispatches) + len(e.lowDispatches)
+}
+
+// handleHigherDispatches executes the dispatches that are pending in the
+// lanes that have a higher priority than the given one.
+func (e *engine) handleHigherDispatches(p DispatchPriority) {
+	if p == HighPriority {
+		return
+	}
+
+	for n := len(e.highDispatches); n > 0; n-- {
+		e.handleDispatch(<-e.highDispatches)
+	}
+	if p != LowPriority {
+		return
+	}
+
+	for n := len(e.dispatches); n > 0; n-- {
+		e.handleDispatch(<-e.dispatches)
+	}
 }
 
 func (e *engine) Emit(src UI, fn func()) {
@@ -96,6 +231,23 @@ func (e *engine) Handle(actionName string, src UI, h ActionHandler) {
 	e.actions.handle(actionName, false, src, h)
 }
 
+func (e *engine) handleDurable(actionName string, src UI, h ActionHandler) {
+	c := nearestCompo(src)
+	if c == nil {
+		e.Handle(actionName, src, h)
+		return
+	}
+	e.actions.handleDurable(actionName, c, h)
+}
+
+func (e *engine) bindHandlers(c Composer) {
+	e.actions.bind(c)
+}
+
+func (e *engine) setHead(c Composer, elems []UI) {
+	e.heads.set(c, elems)
+}
+
 func (e *engine) SetState(state string, v interface{}, opts ...StateOption) {
 	e.states.Set(state, v, opts...)
 }
@@ -138,28 +290,27 @@ func (e *engine) Consume() {
 	for {
 		e.Wait()
 
-		select {
-		case d := <-e.dispatches:
+		if d, ok := e.nextDispatch(); ok {
 			e.handleDispatch(d)
+			continue
+		}
 
-		default:
-			e.updateComponents()
-			e.execDeferableEvents()
-			return
+		e.updateComponents()
+		if len(e.updateQueue) != 0 {
+			continue
 		}
+		e.execDeferableEvents()
+		return
 	}
 }
 
 func (e *engine) ConsumeNext() {
 	e.Wait()
 
-	select {
-	case d := <-e.dispatches:
+	if d, ok := e.nextDispatch(); ok {
 		e.handleDispatch(d)
 		e.updateComponents()
 		e.execDeferableEvents()
-
-	default:
 	}
 }
 
@@ -170,7 +321,11 @@ func (e *engine) Close() {
 
 		dismount(e.Body)
 		e.Body = nil
-		close(e.dispatches)
+		if e.buffers == nil {
+			close(e.highDispatches)
+			close(e.dispatches)
+			close(e.lowDispatches)
+		}
 
 		e.states.Close()
 	})
@@ -191,54 +346,72 @@ func (e *engine) Mount(n UI) {
 		Mode:   Update,
 		Source: e.Body,
 		Function: func(ctx Context) {
-			if !e.isMountedOnce {
-				if err := e.Body.(elemWithChildren).replaceChildAt(0, n); err != nil {
-					panic(errors.New("mounting ui element failed").
-						Tag("dispatches-count", len(e.dispatches)).
-						Tag("dispatches-capacity", cap(e.dispatches)).
-						Tag("updates-count", len(e.updates)).
-						Tag("updates-queue-len", len(e.updateQueue)).
-						Wrap(err))
-				}
-
-				e.isMountedOnce = true
-				return
-			}
+			defer e.perfSection(PerfMeasureMount)()
+			e.mount(n)
+		},
+	})
+}
 
-			err := update(e.Body.children()[0], n)
-			if err == nil {
-				return
-			}
-			if !isErrReplace(err) {
-				panic(errors.New("mounting ui element failed").
-					Tag("dispatches-count", len(e.dispatches)).
-					Tag("dispatches-capacity", cap(e.dispatches)).
-					Tag("updates-count", len(e.updates)).
-					Tag("updates-queue-len", len(e.updateQueue)).
-					Wrap(err))
-			}
+func (e *engine) mount(n UI) {
+	defer e.refreshOutlets()
 
+	if !e.isMountedOnce {
+		hydrated, err := e.hydratePreRender(n)
+		if err != nil {
+			panic(e.mountError(err))
+		}
+		if !hydrated {
 			if err := e.Body.(elemWithChildren).replaceChildAt(0, n); err != nil {
-				panic(errors.New("mounting ui element failed").
-					Tag("dispatches-count", len(e.dispatches)).
-					Tag("dispatches-capacity", cap(e.dispatches)).
-					Tag("updates-count", len(e.updates)).
-					Tag("updates-queue-len", len(e.updateQueue)).
-					Wrap(err))
+				panic(e.mountError(err))
 			}
-		},
-	})
+		}
+
+		e.isMountedOnce = true
+		return
+	}
+
+	err := update(e.Body.children()[0], n)
+	if err == nil {
+		return
+	}
+	if !isErrReplace(err) {
+		panic(e.mountError(err))
+	}
+
+	if err := e.Body.(elemWithChildren).replaceChildAt(0, n); err != nil {
+		panic(e.mountError(err))
+	}
+}
+
+// refreshOutlets displays the components of the child routes of the current
+// route in the outlets of the page.
+func (e *engine) refreshOutlets() {
+	if err := refreshOutlets(e.Body, e.routePath); err != nil {
+		panic(e.mountError(err))
+	}
+}
+
+func (e *engine) mountError(err error) error {
+	return errors.New("mounting ui element failed").
+		Tag("dispatches-count", e.pendingDispatches()).
+		Tag("dispatches-capacity", cap(e.dispatches)).
+		Tag("updates-count", len(e.updates)).
+		Tag("updates-queue-len", len(e.updateQueue)).
+		Wrap(err)
 }
 
 func (e *engine) Nav(u *url.URL) {
 	if p, ok := e.Page.(*requestPage); ok {
 		p.ReplaceURL(u)
 	}
+	e.checkVersion()
 
 	e.Dispatch(Dispatch{
 		Mode:   Update,
 		Source: e.Body,
 		Function: func(ctx Context) {
+			defer e.perfSection(PerfMeasureNav)()
+			e.audit(AuditNavigation, u.Path)
 			ctx.Src().onNav(u)
 		},
 	})
@@ -264,6 +437,46 @@ func (e *engine) AppInstallChange() {
 	})
 }
 
+func (e *engine) VersionSkew() {
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			ctx.Src().onVersionSkew()
+		},
+	})
+}
+
+func (e *engine) AppInstalled() {
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			ctx.Src().onAppInstalled()
+		},
+	})
+}
+
+func (e *engine) DisplayModeChange() {
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			ctx.Src().onDisplayModeChange()
+		},
+	})
+}
+
+func (e *engine) AppLaunch(p LaunchParams) {
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			ctx.Src().onAppLaunch(p)
+		},
+	})
+}
+
 func (e *engine) AppResize() {
 	e.Dispatch(Dispatch{
 		Mode:   Update,
@@ -276,11 +489,23 @@ func (e *engine) AppResize() {
 
 func (e *engine) init() {
 	e.initOnce.Do(func() {
-		e.dispatches = make(chan Dispatch, eventBufferSize)
-		e.updates = make(map[Composer]struct{})
-		e.updateQueue = make([]updateDescriptor, 0, updateBufferSize)
-		e.defers = make([]Dispatch, 0, deferBufferSize)
+		if e.buffers == nil {
+			e.dispatches = make(chan Dispatch, eventBufferSize)
+			e.highDispatches = make(chan Dispatch, eventBufferSize)
+			e.lowDispatches = make(chan Dispatch, eventBufferSize)
+			e.updates = make(map[Composer]struct{})
+			e.updateQueue = make([]updateDescriptor, 0, updateBufferSize)
+			e.defers = make([]Dispatch, 0, deferBufferSize)
+		} else {
+			e.dispatches = e.buffers.dispatches
+			e.highDispatches = e.buffers.highDispatches
+			e.lowDispatches = e.buffers.lowDispatches
+			e.updates = e.buffers.updates
+			e.updateQueue = e.buffers.updateQueue
+			e.defers = e.buffers.defers
+		}
 		e.states = newStore(e)
+		e.heads.browser = !e.RunsInServer && !IsServer
 
 		if e.UpdateRate <= 0 {
 			e.UpdateRate = 60
@@ -299,6 +524,10 @@ func (e *engine) init() {
 			e.SessionStorage = newMemoryStorage()
 		}
 
+		if e.TraceContext == nil {
+			e.TraceContext = context.Background()
+		}
+
 		if e.ResolveStaticResources == nil {
 			e.ResolveStaticResources = func(path string) string {
 				return path
@@ -313,6 +542,9 @@ func (e *engine) init() {
 			e.Body = body
 		}
 
+		for actionName, mailbox := range e.ActionMailboxes {
+			e.actions.enableMailbox(actionName, mailbox)
+		}
 		for actionName, handler := range e.ActionHandlers {
 			e.actions.handle(actionName, true, e.Body, handler)
 		}
@@ -330,24 +562,69 @@ func (e *engine) start(ctx context.Context) {
 		cleanup := time.NewTicker(time.Minute)
 		defer cleanup.Stop()
 
+		var memoryChecks <-chan time.Time
+		var accessibilityAudits <-chan time.Time
+		if !IsServer && !e.RunsInServer && isLocalHost(Window().URL()) {
+			memory := time.NewTicker(memoryCheckInterval)
+			defer memory.Stop()
+			memoryChecks = memory.C
+
+			accessibility := time.NewTicker(accessibilityAuditInterval)
+			defer accessibility.Stop()
+			accessibilityAudits = accessibility.C
+		}
+
+		var crashSnapshots <-chan time.Time
+		if !IsServer && !e.RunsInServer {
+			snapshots := time.NewTicker(crashSnapshotInterval)
+			defer snapshots.Stop()
+			crashSnapshots = snapshots.C
+		}
+
+		resetInterval := func() {
+			interval := updateInterval
+			if e.suspended() {
+				interval = time.Hour
+			}
+			if currentInterval != interval {
+				currentInterval = interval
+				updates.Reset(currentInterval)
+			}
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
+			case d := <-e.highDispatches:
+				e.handleDispatch(d)
+				resetInterval()
+
 			case d := <-e.dispatches:
-				if currentInterval != updateInterval {
-					currentInterval = updateInterval
-					updates.Reset(currentInterval)
-				}
+				e.handleHigherDispatches(NormalPriority)
+				e.handleDispatch(d)
+				resetInterval()
 
+			case d := <-e.lowDispatches:
+				e.handleHigherDispatches(LowPriority)
 				e.handleDispatch(d)
+				resetInterval()
 
 			case <-updates.C:
+				if e.suspended() {
+					currentInterval = time.Hour
+					updates.Reset(currentInterval)
+					continue
+				}
+
 				e.updateComponents()
+				if len(e.updateQueue) != 0 {
+					continue
+				}
 				e.execDeferableEvents()
 
-				if len(e.dispatches) == 0 {
+				if e.pendingDispatches() == 0 {
 					currentInterval = time.Hour
 					updates.Reset(currentInterval)
 				}
@@ -355,29 +632,61 @@ func (e *engine) start(ctx context.Context) {
 			case <-cleanup.C:
 				e.actions.closeUnusedHandlers()
 				e.states.Cleanup()
+
+			case <-memoryChecks:
+				e.checkMemory()
+
+			case <-accessibilityAudits:
+				e.auditAccessibility()
+
+			case <-crashSnapshots:
+				e.saveCrashSnapshot()
 			}
 		}
 	})
 }
 
 func (e *engine) handleDispatch(d Dispatch) {
+	if e.buffers != nil && (d.Source == nil || d.Source.dispatcher() != e) {
+		return
+	}
+
+	if e.Tracer != nil {
+		_, span := e.Tracer.StartSpan(e.TraceContext, "engine.dispatch")
+		span.SetAttribute("dispatch.mode", int(d.Mode))
+		span.SetAttribute("dispatch.source", reflect.TypeOf(d.Source).String())
+		defer span.End()
+	}
+	e.isAccessibilityModified = true
+
 	switch d.Mode {
 	case Next:
-		d.Function(makeContext(d.Source))
+		e.execDispatch(d)
 
 	case Update:
 		if d.Source.Mounted() {
-			d.Function(makeContext(d.Source))
+			e.execDispatch(d)
 			e.scheduleComponentUpdate(d.Source)
 		}
 
-	case Defer:
+	case Defer, AfterPaint:
 		if d.Source.Mounted() {
 			e.defers = append(e.defers, d)
 		}
 	}
 }
 
+// execDispatch calls the function of the given dispatch. Its source is
+// recorded during the call to attribute global errors.
+func (e *engine) execDispatch(d Dispatch) {
+	source := e.dispatchSource
+	e.dispatchSource = d.Source
+	defer func() {
+		e.dispatchSource = source
+	}()
+	d.Function(makeContext(d.Source))
+}
+
 func (e *engine) scheduleComponentUpdate(n UI) {
 	if !n.Mounted() {
 		return
@@ -404,8 +713,21 @@ func (e *engine) updateComponents() {
 		return
 	}
 
+	if e.Tracer != nil {
+		_, span := e.Tracer.StartSpan(e.TraceContext, "engine.update")
+		span.SetAttribute("update.count", len(e.updateQueue))
+		defer span.End()
+	}
+
+	if !e.isUpdatedOnce {
+		e.isUpdatedOnce = true
+		defer e.perfSection(PerfMeasureFirstUpdate)()
+	}
+
+	start := time.Now()
+
 	sortUpdateDescriptors(e.updateQueue)
-	for _, ud := range e.updateQueue {
+	for i, ud := range e.updateQueue {
 		compo := ud.compo
 		if !compo.Mounted() {
 			e.removeFromUpdates(compo)
@@ -420,6 +742,13 @@ func (e *engine) updateComponents() {
 			panic(err)
 		}
 		e.removeFromUpdates(compo)
+		e.updateCount++
+
+		if e.UpdateBudget > 0 && time.Since(start) >= e.UpdateBudget {
+			n := copy(e.updateQueue, e.updateQueue[i+1:])
+			e.updateQueue = e.updateQueue[:n]
+			return
+		}
 	}
 
 	e.updateQueue = e.updateQueue[:0]
@@ -430,12 +759,64 @@ func (e *engine) removeFromUpdates(c Composer) {
 }
 
 func (e *engine) execDeferableEvents() {
+	if len(e.defers) == 0 {
+		return
+	}
+
+	sortDispatches(e.defers)
+
+	var afterPaints []Dispatch
 	for _, d := range e.defers {
+		if d.Mode == AfterPaint {
+			afterPaints = append(afterPaints, d)
+			continue
+		}
+
 		if d.Source.Mounted() {
-			d.Function(makeContext(d.Source))
+			e.execDispatch(d)
 		}
 	}
 	e.defers = e.defers[:0]
+
+	if len(afterPaints) != 0 {
+		e.execAfterPaint(afterPaints)
+	}
+}
+
+func (e *engine) execAfterPaint(dispatches []Dispatch) {
+	exec := func(Context) {
+		for _, d := range dispatches {
+			if d.Source.Mounted() {
+				d.Function(makeContext(d.Source))
+			}
+		}
+	}
+
+	if !Window().Get("requestAnimationFrame").Truthy() {
+		exec(nil)
+		return
+	}
+
+	// The first animation frame callback is called before the paint of the
+	// current frame. The second one is called after it has been painted.
+	var first, second Func
+	second = FuncOf(func(this Value, args []Value) interface{} {
+		defer second.Release()
+
+		e.Dispatch(Dispatch{
+			Mode:     Next,
+			Source:   e.Body,
+			Function: exec,
+		})
+		return nil
+	})
+	first = FuncOf(func(this Value, args []Value) interface{} {
+		defer first.Release()
+
+		Window().Call("requestAnimationFrame", second)
+		return nil
+	})
+	Window().Call("requestAnimationFrame", first)
 }
 
 func (e *engine) currentPage() Page {
@@ -454,6 +835,32 @@ func (e *engine) runsInServer() bool {
 	return e.RunsInServer
 }
 
+func (e *engine) serverSession() *Session {
+	return e.Session
+}
+
+func (e *engine) experiments() *experimentAssignments {
+	return e.Experiments
+}
+
+func (e *engine) locales() []string {
+	return e.Locales
+}
+
+func (e *engine) timeZone() *time.Location {
+	if e.TimeZone == nil {
+		return time.UTC
+	}
+	return e.TimeZone
+}
+
+func (e *engine) getenv(k string) string {
+	if v, ok := e.Env[k]; ok {
+		return v
+	}
+	return Getenv(k)
+}
+
 func (e *engine) resolveStaticResource(path string) string {
 	return e.ResolveStaticResources(path)
 }
@@ -479,13 +886,42 @@ func nearestCompo(n UI) Composer {
 }
 
 func compoPriority(c Composer) int {
+	return nodeDepth(c)
+}
+
+func nodeDepth(n UI) int {
 	depth := 1
-	for parent := c.parent(); parent != nil; parent = parent.parent() {
+	for parent := n.parent(); parent != nil; parent = parent.parent() {
 		depth++
 	}
 	return depth
 }
 
+// sortDispatches sorts the given dispatches by priority, highest first, and
+// then by the depth of their source. Dispatches with the same priority and
+// depth keep their order.
+func sortDispatches(d []Dispatch) {
+	sort.SliceStable(d, func(a, b int) bool {
+		if pa, pb := priorityRank(d[a].Priority), priorityRank(d[b].Priority); pa != pb {
+			return pa < pb
+		}
+		return nodeDepth(d[a].Source) < nodeDepth(d[b].Source)
+	})
+}
+
+func priorityRank(p DispatchPriority) int {
+	switch p {
+	case HighPriority:
+		return 0
+
+	case LowPriority:
+		return 2
+
+	default:
+		return 1
+	}
+}
+
 type msgHandler struct {
 	src      UI
 	function MsgHandler
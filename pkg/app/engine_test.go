@@ -22,6 +22,8 @@ func TestEngineInit(t *testing.T) {
 	assert.NotNil(t, e.ResolveStaticResources)
 	assert.NotNil(t, e.Body)
 	assert.NotNil(t, e.dispatches)
+	assert.NotNil(t, e.highDispatches)
+	assert.NotNil(t, e.lowDispatches)
 	assert.NotNil(t, e.updates)
 	assert.NotNil(t, e.updateQueue)
 	assert.NotNil(t, e.defers)
@@ -42,6 +44,125 @@ func TestEngineDispatch(t *testing.T) {
 	require.NotNil(t, d.Function)
 }
 
+func TestEngineDispatchPriority(t *testing.T) {
+	t.Run("dispatches are consumed by priority", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		var calls []string
+		record := func(name string) func(Context) {
+			return func(Context) {
+				calls = append(calls, name)
+			}
+		}
+
+		e.Dispatch(Dispatch{Priority: LowPriority, Function: record("low-1")})
+		e.Dispatch(Dispatch{Function: record("normal-1")})
+		e.Dispatch(Dispatch{Priority: LowPriority, Function: record("low-2")})
+		e.Dispatch(Dispatch{Priority: HighPriority, Function: record("high-1")})
+		e.Dispatch(Dispatch{Function: record("normal-2")})
+		e.Dispatch(Dispatch{Priority: HighPriority, Function: record("high-2")})
+		require.Equal(t, 6, e.pendingDispatches())
+
+		e.Consume()
+		require.Zero(t, e.pendingDispatches())
+		require.Equal(t, []string{
+			"high-1",
+			"high-2",
+			"normal-1",
+			"normal-2",
+			"low-1",
+			"low-2",
+		}, calls)
+	})
+
+	t.Run("higher dispatches are handled first", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		var calls []string
+		record := func(name string) func(Context) {
+			return func(Context) {
+				calls = append(calls, name)
+			}
+		}
+
+		e.Dispatch(Dispatch{Function: record("normal")})
+		e.Dispatch(Dispatch{Priority: HighPriority, Function: record("high")})
+		e.Dispatch(Dispatch{Priority: LowPriority, Function: record("low")})
+
+		e.handleHigherDispatches(HighPriority)
+		require.Empty(t, calls)
+
+		e.handleHigherDispatches(NormalPriority)
+		require.Equal(t, []string{"high"}, calls)
+
+		e.Dispatch(Dispatch{Priority: HighPriority, Function: record("high")})
+		e.handleHigherDispatches(LowPriority)
+		require.Equal(t, []string{"high", "high", "normal"}, calls)
+		require.Len(t, e.lowDispatches, 1)
+	})
+
+	t.Run("deferred dispatches are executed by priority", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		bar := &bar{}
+		e.Mount(bar)
+		e.Consume()
+
+		var calls []string
+		deferred := func(p DispatchPriority, name string) {
+			e.handleDispatch(Dispatch{
+				Mode:     Defer,
+				Priority: p,
+				Source:   bar,
+				Function: func(Context) {
+					calls = append(calls, name)
+				},
+			})
+		}
+
+		deferred(LowPriority, "low-1")
+		deferred(NormalPriority, "normal-1")
+		deferred(HighPriority, "high-1")
+		deferred(LowPriority, "low-2")
+		deferred(HighPriority, "high-2")
+		deferred(NormalPriority, "normal-2")
+
+		e.execDeferableEvents()
+		require.Equal(t, []string{
+			"high-1",
+			"high-2",
+			"normal-1",
+			"normal-2",
+			"low-1",
+			"low-2",
+		}, calls)
+	})
+
+	t.Run("context dispatches with priority", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		ctx := e.Context()
+		ctx.PriorityDispatch(HighPriority, func(Context) {})
+		ctx.PriorityDefer(LowPriority, func(Context) {})
+
+		d := <-e.highDispatches
+		require.Equal(t, Update, d.Mode)
+		require.Equal(t, HighPriority, d.Priority)
+
+		d = <-e.lowDispatches
+		require.Equal(t, Defer, d.Mode)
+		require.Equal(t, LowPriority, d.Priority)
+	})
+}
+
 func TestEngineEmit(t *testing.T) {
 	e := engine{}
 	e.init()
@@ -210,6 +331,29 @@ func TestEngineUpdateCoponents(t *testing.T) {
 	require.Empty(t, e.updateQueue)
 }
 
+func TestEngineUpdateComponentsWithBudget(t *testing.T) {
+	e := engine{UpdateBudget: time.Nanosecond}
+	e.init()
+	defer e.Close()
+
+	foo := &foo{Bar: "bar"}
+	e.Mount(foo)
+	e.Consume()
+	bar := foo.root.(*bar)
+
+	e.scheduleComponentUpdate(foo)
+	e.scheduleComponentUpdate(bar)
+	require.Len(t, e.updateQueue, 2)
+
+	e.updateComponents()
+	require.Len(t, e.updates, 1)
+	require.Len(t, e.updateQueue, 1)
+
+	e.updateComponents()
+	require.Empty(t, e.updates)
+	require.Empty(t, e.updateQueue)
+}
+
 func TestEngineExecDeferableEvents(t *testing.T) {
 	e := engine{}
 	e.init()
@@ -239,6 +383,43 @@ func TestEngineExecDeferableEvents(t *testing.T) {
 	require.Empty(t, e.defers)
 }
 
+func TestEngineExecDeferableEventsOrder(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	foo := &foo{Bar: "bar"}
+	e.Mount(foo)
+	e.Consume()
+	bar := foo.root.(*bar)
+
+	var calls []string
+	deferCall := func(mode DispatchMode, src UI, name string) {
+		e.handleDispatch(Dispatch{
+			Mode:     mode,
+			Source:   src,
+			Function: func(Context) { calls = append(calls, name) },
+		})
+	}
+
+	deferCall(AfterPaint, foo, "foo-paint")
+	deferCall(Defer, bar, "bar-1")
+	deferCall(Defer, foo, "foo-1")
+	deferCall(Defer, bar, "bar-2")
+	deferCall(Defer, foo, "foo-2")
+	require.Len(t, e.defers, 5)
+
+	e.execDeferableEvents()
+	require.Equal(t, []string{
+		"foo-1",
+		"foo-2",
+		"bar-1",
+		"bar-2",
+		"foo-paint",
+	}, calls)
+	require.Empty(t, e.defers)
+}
+
 func TestEngineHandlePost(t *testing.T) {
 	isAppHandleCalled := false
 	isHandleACalled := false
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// renderPageEnv returns the script that carries the given environment
+// variables in the page. Reserved variables are excluded since they describe
+// the build and are already set by app.js.
+func renderPageEnv(env Environment) string {
+	vars := make(map[string]string, len(env))
+	for k, v := range env {
+		if !strings.HasPrefix(k, "GOAPP_") {
+			vars[k] = v
+		}
+	}
+	if len(vars) == 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(vars)
+	if err != nil {
+		Log(errors.New("encoding page env failed").Wrap(err))
+		return ""
+	}
+	return `<script id="app-env" type="application/json">` + string(b) + `</script>`
+}
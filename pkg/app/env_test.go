@@ -0,0 +1,66 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/env-test", &envTestCompo{})
+}
+
+type envTestCompo struct {
+	Compo
+
+	apiURL string
+}
+
+func (c *envTestCompo) OnPreRender(ctx Context) {
+	c.apiURL = ctx.Env("API_URL")
+}
+
+func (c *envTestCompo) Render() UI {
+	return Div().ID("api-url").Text(c.apiURL)
+}
+
+func TestRenderPageEnv(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, renderPageEnv(nil))
+		require.Empty(t, renderPageEnv(Environment{"GOAPP_VERSION": "v1"}))
+	})
+
+	t.Run("vars", func(t *testing.T) {
+		script := renderPageEnv(Environment{
+			"API_URL":       "https://api.go-app.dev",
+			"GOAPP_VERSION": "v1",
+			"HTML":          "</script>",
+		})
+		require.Equal(t, `<script id="app-env" type="application/json">{"API_URL":"https://api.go-app.dev","HTML":"\u003c/script\u003e"}</script>`, script)
+	})
+}
+
+func TestHandlerEnv(t *testing.T) {
+	h := Handler{
+		Env: Environment{"API_URL": "https://staging.go-app.dev"},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/env-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, body, `<script id="app-env" type="application/json">{"API_URL":"https://staging.go-app.dev"}</script>`)
+	require.Contains(t, body, "<div id=\"api-url\">\nhttps://staging.go-app.dev\n</div>")
+}
+
+func TestContextEnv(t *testing.T) {
+	e := engine{Env: Environment{"API_URL": "https://go-app.dev"}}
+	require.Equal(t, "https://go-app.dev", e.getenv("API_URL"))
+	require.Empty(t, e.getenv("UNDEFINED_ENV_TEST"))
+}
@@ -0,0 +1,260 @@
+package app
+
+// Modifiers describes the modifier keys that are pressed when an event is
+// emitted.
+type Modifiers struct {
+	Alt   bool
+	Ctrl  bool
+	Meta  bool
+	Shift bool
+}
+
+// MouseEvent describes a mouse or a pointer event, such as a click.
+type MouseEvent struct {
+	Modifiers
+
+	// The coordinates of the pointer relative to the viewport.
+	ClientX float64
+	ClientY float64
+
+	// The coordinates of the pointer relative to the document.
+	PageX float64
+	PageY float64
+
+	// The coordinates of the pointer relative to the screen.
+	ScreenX float64
+	ScreenY float64
+
+	// The coordinates of the pointer relative to the target element.
+	OffsetX float64
+	OffsetY float64
+
+	// The coordinates of the pointer relative to the previous mouse move
+	// event.
+	MovementX float64
+	MovementY float64
+
+	// The button that changed the state of the event: 0 for the main button,
+	// 1 for the auxiliary button and 2 for the secondary button.
+	Button int
+
+	// The buttons that are pressed, as a bit mask: 1 for the main button, 2
+	// for the secondary button and 4 for the auxiliary button.
+	Buttons int
+}
+
+// KeyboardEvent describes a keyboard event, such as a key down.
+type KeyboardEvent struct {
+	Modifiers
+
+	// The value of the pressed key, which depends on the keyboard layout and
+	// the modifiers. Eg: "a", "A", "Enter" or "ArrowUp".
+	Key string
+
+	// The physical key that is pressed, regardless of the keyboard layout.
+	// Eg: "KeyA" or "Enter".
+	Code string
+
+	// Reports whether the key is held down and automatically repeated.
+	Repeat bool
+
+	// Reports whether the event is emitted while a text is composed with an
+	// input method editor.
+	IsComposing bool
+}
+
+// InputEvent describes an edition of the content of an element, such as a
+// character typed in an input.
+type InputEvent struct {
+	// The inserted characters. It is empty when content is deleted.
+	Data string
+
+	// The type of change. Eg: "insertText", "deleteContentBackward" or
+	// "insertFromPaste".
+	InputType string
+
+	// Reports whether the event is emitted while a text is composed with an
+	// input method editor.
+	IsComposing bool
+}
+
+// WheelEvent describes a wheel event.
+type WheelEvent struct {
+	MouseEvent
+
+	// The scroll amounts.
+	DeltaX float64
+	DeltaY float64
+	DeltaZ float64
+
+	// The unit of the scroll amounts: 0 for pixels, 1 for lines and 2 for
+	// pages.
+	DeltaMode int
+}
+
+// TouchEvent describes a touch event.
+type TouchEvent struct {
+	Modifiers
+
+	// The points that are currently in contact with the touch surface.
+	Touches []Touch
+
+	// The points that are in contact with the touch surface and started on
+	// the target element.
+	TargetTouches []Touch
+
+	// The points that changed between this event and the previous one.
+	ChangedTouches []Touch
+}
+
+// Touch describes a point of contact with a touch surface.
+type Touch struct {
+	// The identifier of the point, which is the same for the whole duration
+	// of the contact.
+	Identifier int
+
+	// The coordinates of the point relative to the viewport.
+	ClientX float64
+	ClientY float64
+
+	// The coordinates of the point relative to the document.
+	PageX float64
+	PageY float64
+
+	// The coordinates of the point relative to the screen.
+	ScreenX float64
+	ScreenY float64
+
+	// The radii of the ellipse that describes the contact area.
+	RadiusX float64
+	RadiusY float64
+
+	// The pressure of the contact, from 0 to 1.
+	Force float64
+}
+
+// Modifiers returns the modifier keys that are pressed when the event is
+// emitted.
+func (e Event) Modifiers() Modifiers {
+	return Modifiers{
+		Alt:   eventBool(e, "altKey"),
+		Ctrl:  eventBool(e, "ctrlKey"),
+		Meta:  eventBool(e, "metaKey"),
+		Shift: eventBool(e, "shiftKey"),
+	}
+}
+
+// Mouse returns the properties of a mouse or a pointer event. Properties that
+// are not defined by the event are zero.
+func (e Event) Mouse() MouseEvent {
+	return MouseEvent{
+		Modifiers: e.Modifiers(),
+		ClientX:   eventFloat(e, "clientX"),
+		ClientY:   eventFloat(e, "clientY"),
+		PageX:     eventFloat(e, "pageX"),
+		PageY:     eventFloat(e, "pageY"),
+		ScreenX:   eventFloat(e, "screenX"),
+		ScreenY:   eventFloat(e, "screenY"),
+		OffsetX:   eventFloat(e, "offsetX"),
+		OffsetY:   eventFloat(e, "offsetY"),
+		MovementX: eventFloat(e, "movementX"),
+		MovementY: eventFloat(e, "movementY"),
+		Button:    int(eventFloat(e, "button")),
+		Buttons:   int(eventFloat(e, "buttons")),
+	}
+}
+
+// Keyboard returns the properties of a keyboard event. Properties that are
+// not defined by the event are zero.
+func (e Event) Keyboard() KeyboardEvent {
+	return KeyboardEvent{
+		Modifiers:   e.Modifiers(),
+		Key:         eventString(e, "key"),
+		Code:        eventString(e, "code"),
+		Repeat:      eventBool(e, "repeat"),
+		IsComposing: eventBool(e, "isComposing"),
+	}
+}
+
+// Input returns the properties of an input event. Properties that are not
+// defined by the event are zero.
+func (e Event) Input() InputEvent {
+	return InputEvent{
+		Data:        eventString(e, "data"),
+		InputType:   eventString(e, "inputType"),
+		IsComposing: eventBool(e, "isComposing"),
+	}
+}
+
+// Wheel returns the properties of a wheel event. Properties that are not
+// defined by the event are zero.
+func (e Event) Wheel() WheelEvent {
+	return WheelEvent{
+		MouseEvent: e.Mouse(),
+		DeltaX:     eventFloat(e, "deltaX"),
+		DeltaY:     eventFloat(e, "deltaY"),
+		DeltaZ:     eventFloat(e, "deltaZ"),
+		DeltaMode:  int(eventFloat(e, "deltaMode")),
+	}
+}
+
+// Touch returns the properties of a touch event. Properties that are not
+// defined by the event are zero.
+func (e Event) Touch() TouchEvent {
+	return TouchEvent{
+		Modifiers:      e.Modifiers(),
+		Touches:        eventTouches(e, "touches"),
+		TargetTouches:  eventTouches(e, "targetTouches"),
+		ChangedTouches: eventTouches(e, "changedTouches"),
+	}
+}
+
+func eventTouches(v Value, k string) []Touch {
+	list := v.Get(k)
+	if !list.Truthy() {
+		return nil
+	}
+
+	touches := make([]Touch, list.Length())
+	for i := range touches {
+		t := list.Index(i)
+		touches[i] = Touch{
+			Identifier: int(eventFloat(t, "identifier")),
+			ClientX:    eventFloat(t, "clientX"),
+			ClientY:    eventFloat(t, "clientY"),
+			PageX:      eventFloat(t, "pageX"),
+			PageY:      eventFloat(t, "pageY"),
+			ScreenX:    eventFloat(t, "screenX"),
+			ScreenY:    eventFloat(t, "screenY"),
+			RadiusX:    eventFloat(t, "radiusX"),
+			RadiusY:    eventFloat(t, "radiusY"),
+			Force:      eventFloat(t, "force"),
+		}
+	}
+	return touches
+}
+
+// eventFloat returns the given number property, or 0 when it is not defined.
+func eventFloat(v Value, k string) float64 {
+	p := v.Get(k)
+	if p.IsUndefined() || p.IsNull() {
+		return 0
+	}
+	return p.Float()
+}
+
+// eventString returns the given string property, or "" when it is not
+// defined.
+func eventString(v Value, k string) string {
+	p := v.Get(k)
+	if p.IsUndefined() || p.IsNull() {
+		return ""
+	}
+	return p.String()
+}
+
+// eventBool returns the given boolean property, or false when it is not
+// defined.
+func eventBool(v Value, k string) bool {
+	return v.Get(k).Truthy()
+}
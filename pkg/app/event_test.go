@@ -0,0 +1,95 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventAccessors(t *testing.T) {
+	t.Run("mouse", func(t *testing.T) {
+		e := Event{Value: newSimulatedEvent("click", map[string]interface{}{
+			"clientX":  10.5,
+			"clientY":  20,
+			"pageX":    30,
+			"button":   2,
+			"buttons":  3,
+			"shiftKey": true,
+		})}
+
+		m := e.Mouse()
+		require.Equal(t, 10.5, m.ClientX)
+		require.Equal(t, float64(20), m.ClientY)
+		require.Equal(t, float64(30), m.PageX)
+		require.Zero(t, m.OffsetX)
+		require.Equal(t, 2, m.Button)
+		require.Equal(t, 3, m.Buttons)
+		require.Equal(t, Modifiers{Shift: true}, m.Modifiers)
+	})
+
+	t.Run("keyboard", func(t *testing.T) {
+		e := Event{Value: newSimulatedEvent("keydown", map[string]interface{}{
+			"key":     "A",
+			"code":    "KeyA",
+			"repeat":  true,
+			"ctrlKey": true,
+			"metaKey": true,
+		})}
+
+		require.Equal(t, KeyboardEvent{
+			Modifiers: Modifiers{Ctrl: true, Meta: true},
+			Key:       "A",
+			Code:      "KeyA",
+			Repeat:    true,
+		}, e.Keyboard())
+	})
+
+	t.Run("input", func(t *testing.T) {
+		e := Event{Value: newSimulatedEvent("input", map[string]interface{}{
+			"inputType": "deleteContentBackward",
+			"data":      nil,
+		})}
+
+		require.Equal(t, InputEvent{InputType: "deleteContentBackward"}, e.Input())
+	})
+
+	t.Run("wheel", func(t *testing.T) {
+		e := Event{Value: newSimulatedEvent("wheel", map[string]interface{}{
+			"clientX":   1,
+			"deltaY":    -120,
+			"deltaMode": 1,
+		})}
+
+		w := e.Wheel()
+		require.Equal(t, float64(1), w.ClientX)
+		require.Equal(t, float64(-120), w.DeltaY)
+		require.Zero(t, w.DeltaX)
+		require.Equal(t, 1, w.DeltaMode)
+	})
+
+	t.Run("touch", func(t *testing.T) {
+		e := Event{Value: newSimulatedEvent("touchstart", map[string]interface{}{
+			"altKey": true,
+			"touches": []interface{}{
+				map[string]interface{}{"identifier": 1, "clientX": 5, "force": 0.5},
+				map[string]interface{}{"identifier": 2, "clientY": 7},
+			},
+		})}
+
+		touch := e.Touch()
+		require.Equal(t, Modifiers{Alt: true}, touch.Modifiers)
+		require.Equal(t, []Touch{
+			{Identifier: 1, ClientX: 5, Force: 0.5},
+			{Identifier: 2, ClientY: 7},
+		}, touch.Touches)
+		require.Empty(t, touch.TargetTouches)
+		require.Empty(t, touch.ChangedTouches)
+	})
+
+	t.Run("undefined properties are zero", func(t *testing.T) {
+		e := Event{Value: Null()}
+		require.Zero(t, e.Mouse())
+		require.Zero(t, e.Keyboard())
+		require.Zero(t, e.Touch())
+	})
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// EventModifier is an option of an event handler, passed with its scope, that
+// is applied when the event is emitted, before the handler runs. Eg:
+//  app.A().
+//      Href("/settings").
+//      OnClick(c.onOpenSettings, app.PreventDefault, app.StopPropagation)
+//
+// Modifiers are applied synchronously in the JavaScript listener, which is
+// required for PreventDefault since the handler is called after the event is
+// dispatched.
+type EventModifier int
+
+const (
+	// PreventDefault cancels the default action of the event, such as
+	// following a link or submitting a form.
+	PreventDefault EventModifier = 1 << iota
+
+	// StopPropagation prevents the event from reaching the handlers of the
+	// parent elements.
+	StopPropagation
+
+	// StopImmediatePropagation prevents the event from reaching the other
+	// handlers of the element and the ones of the parent elements.
+	StopImmediatePropagation
+
+	// Passive registers the listener as passive, which tells the browser
+	// that the default action is never canceled and lets it scroll without
+	// waiting for the handler. It can't be combined with PreventDefault.
+	Passive
+)
+
+func (m EventModifier) is(o EventModifier) bool {
+	return m&o != 0
+}
+
+// splitEventModifiers returns the modifiers that are in the given scope, and
+// the scope without them. It panics when the modifiers can't be combined.
+func splitEventModifiers(event string, scope []interface{}) (EventModifier, []interface{}) {
+	var modifiers EventModifier
+	var s []interface{}
+
+	for _, v := range scope {
+		if m, ok := v.(EventModifier); ok {
+			modifiers |= m
+			continue
+		}
+		if s == nil {
+			s = make([]interface{}, 0, len(scope))
+		}
+		s = append(s, v)
+	}
+
+	if modifiers.is(Passive) && modifiers.is(PreventDefault) {
+		panic(errors.New("setting event handler failed").
+			Tag("reason", "passive listeners can't prevent default").
+			Tag("event", event))
+	}
+	return modifiers, s
+}
+
+// applyEventModifiers applies the given modifiers to the given JavaScript
+// event.
+func applyEventModifiers(event Value, m EventModifier) {
+	if m.is(PreventDefault) {
+		event.Call("preventDefault")
+	}
+	if m.is(StopPropagation) {
+		event.Call("stopPropagation")
+	}
+	if m.is(StopImmediatePropagation) {
+		event.Call("stopImmediatePropagation")
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitEventModifiers(t *testing.T) {
+	modifiers, scope := splitEventModifiers("click", []interface{}{
+		"row",
+		PreventDefault,
+		42,
+		StopPropagation,
+	})
+	require.True(t, modifiers.is(PreventDefault))
+	require.True(t, modifiers.is(StopPropagation))
+	require.False(t, modifiers.is(Passive))
+	require.Equal(t, []interface{}{"row", 42}, scope)
+
+	modifiers, scope = splitEventModifiers("click", nil)
+	require.Zero(t, modifiers)
+	require.Nil(t, scope)
+
+	require.Panics(t, func() {
+		splitEventModifiers("touchmove", []interface{}{Passive, PreventDefault})
+	})
+}
+
+func TestElemSetEventHandlerWithModifiers(t *testing.T) {
+	h := func(Context, Event) {}
+
+	a := Div().OnClick(h, "row", PreventDefault)
+	b := Div().OnClick(h, "row")
+	require.Equal(t, "/row", a.eventHandlers()["click"].scope)
+	require.Equal(t, PreventDefault, a.eventHandlers()["click"].modifiers)
+	require.False(t, a.eventHandlers()["click"].equal(b.eventHandlers()["click"]))
+
+	require.Panics(t, func() {
+		Div().OnWheel(h, Passive, PreventDefault)
+	})
+}
+
+func TestApplyEventModifiers(t *testing.T) {
+	var calls []string
+	call := func(name string) func(args ...interface{}) Value {
+		return func(args ...interface{}) Value {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	event := newSimulatedEvent("click", map[string]interface{}{
+		"preventDefault":           call("preventDefault"),
+		"stopPropagation":          call("stopPropagation"),
+		"stopImmediatePropagation": call("stopImmediatePropagation"),
+	})
+
+	applyEventModifiers(event, PreventDefault|StopImmediatePropagation|Passive)
+	require.Equal(t, []string{"preventDefault", "stopImmediatePropagation"}, calls)
+}
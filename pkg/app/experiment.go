@@ -0,0 +1,285 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	experimentsCookieName = "goapp_experiments"
+	flagUserCookieName    = "goapp_flags_user"
+	experimentCookieTTL   = time.Hour * 24 * 365
+)
+
+// Experiment describes an A/B experiment whose variants are assigned to users.
+//
+// A variant is assigned once per user and kept in a cookie, which makes it
+// sticky across sessions and configuration changes. Pages prerendered for an
+// experiment are assigned their variant on the server and the app picks the
+// same one in the browser: the displayed variant never changes once the app is
+// loaded.
+type Experiment struct {
+	// The variants users are assigned to.
+	Variants []ExperimentVariant
+}
+
+// ExperimentVariant describes a variant of an experiment.
+type ExperimentVariant struct {
+	// The variant name. eg: "control".
+	Name string
+
+	// The weight of the variant relative to the other variants. Variants have
+	// the same weight when all the weights are zero.
+	Weight float64
+}
+
+// VariantFor returns the name of the variant assigned to the given user key for
+// the experiment with the given name. The assignment is deterministic and is
+// done the same way on the server and in the browser.
+func (e Experiment) VariantFor(name, userKey string) string {
+	if len(e.Variants) == 0 {
+		return ""
+	}
+
+	var total float64
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+
+	bucket := flagBucket(name, userKey)
+	var cumulated float64
+	for i, v := range e.Variants {
+		if total > 0 {
+			cumulated += v.Weight * 100 / total
+		} else {
+			cumulated = float64(i+1) * 100 / float64(len(e.Variants))
+		}
+		if bucket < cumulated {
+			return v.Name
+		}
+	}
+	return e.Variants[len(e.Variants)-1].Name
+}
+
+func (e Experiment) hasVariant(name string) bool {
+	for _, v := range e.Variants {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// experimentAssignments contains the experiment variants assigned to a user.
+type experimentAssignments struct {
+	mutex       sync.Mutex
+	userKey     string
+	variants    map[string]string
+	userKeyNew  bool
+	modified    bool
+	used        bool
+	persist     func(*experimentAssignments)
+	experiments func(string) (Experiment, bool)
+}
+
+func newExperimentAssignments(userKey, variants string) *experimentAssignments {
+	return &experimentAssignments{
+		userKey:     userKey,
+		variants:    parseExperimentVariants(variants),
+		experiments: featureFlags.experiment,
+	}
+}
+
+// newServerExperimentAssignments returns the assignments carried by the
+// cookies of the given request.
+func newServerExperimentAssignments(r *http.Request) *experimentAssignments {
+	var userKey, variants string
+	if c, err := r.Cookie(flagUserCookieName); err == nil {
+		userKey = c.Value
+	}
+	if c, err := r.Cookie(experimentsCookieName); err == nil {
+		variants = c.Value
+	}
+	return newExperimentAssignments(userKey, variants)
+}
+
+// newClientExperimentAssignments returns the assignments carried by the
+// document cookies. The cookies are updated when a variant is assigned.
+func newClientExperimentAssignments(userKey string) *experimentAssignments {
+	a := newExperimentAssignments(userKey, documentCookie(experimentsCookieName))
+	a.persist = func(a *experimentAssignments) {
+		for _, c := range a.cookies(Window().URL().Scheme == "https") {
+			Window().Get("document").Set("cookie", c.String())
+		}
+	}
+	return a
+}
+
+func (a *experimentAssignments) variant(name string) string {
+	if a == nil {
+		exp, _ := featureFlags.experiment(name)
+		return exp.VariantFor(name, "")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	exp, ok := a.experiments(name)
+	if !ok {
+		return ""
+	}
+	a.used = true
+
+	if v, ok := a.variants[name]; ok && exp.hasVariant(v) {
+		return v
+	}
+
+	if a.userKey == "" {
+		a.userKey = uuid.NewString()
+		a.userKeyNew = true
+	}
+	v := exp.VariantFor(name, a.userKey)
+	a.variants[name] = v
+	a.modified = true
+
+	if a.persist != nil {
+		a.persist(a)
+	}
+	return v
+}
+
+// isUsed reports whether a variant was requested.
+func (a *experimentAssignments) isUsed() bool {
+	if a == nil {
+		return false
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.used
+}
+
+// cookies returns the cookies that store the assignments when they have been
+// modified. It must be called with the mutex held.
+func (a *experimentAssignments) cookies(secure bool) []*http.Cookie {
+	if !a.modified {
+		return nil
+	}
+
+	cookies := make([]*http.Cookie, 0, 2)
+	if a.userKeyNew {
+		cookies = append(cookies, experimentCookie(flagUserCookieName, a.userKey, secure))
+	}
+	cookies = append(cookies, experimentCookie(experimentsCookieName, encodeExperimentVariants(a.variants), secure))
+
+	a.userKeyNew = false
+	a.modified = false
+	return cookies
+}
+
+// save sets the cookies that store the assignments in the given response.
+func (a *experimentAssignments) save(w http.ResponseWriter, r *http.Request) {
+	if a == nil {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+	for _, c := range a.cookies(secure) {
+		http.SetCookie(w, c)
+	}
+}
+
+// script returns a script that sets the cookies that store the assignments.
+// It is used when the assignments are modified after the response headers
+// are written. It returns an empty string when the assignments are not
+// modified.
+func (a *experimentAssignments) script(r *http.Request) string {
+	if a == nil {
+		return ""
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	secure := r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+	cookies := a.cookies(secure)
+	if len(cookies) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<script>")
+	for _, c := range cookies {
+		v, _ := json.Marshal(c.String())
+		b.WriteString("document.cookie=")
+		b.Write(v)
+		b.WriteString(";")
+	}
+	b.WriteString("</script>")
+	return b.String()
+}
+
+func experimentCookie(name, value string, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(experimentCookieTTL / time.Second),
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+func encodeExperimentVariants(variants map[string]string) string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(name))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(variants[name]))
+	}
+	return b.String()
+}
+
+func parseExperimentVariants(s string) map[string]string {
+	variants := make(map[string]string)
+	values, _ := url.ParseQuery(s)
+	for name := range values {
+		variants[name] = values.Get(name)
+	}
+	return variants
+}
+
+// documentCookie returns the value of the document cookie with the given name.
+func documentCookie(name string) string {
+	cookies := Window().Get("document").Get("cookie")
+	if !cookies.Truthy() {
+		return ""
+	}
+
+	for _, c := range strings.Split(cookies.String(), ";") {
+		c = strings.TrimSpace(c)
+		if strings.HasPrefix(c, name+"=") {
+			return strings.TrimPrefix(c, name+"=")
+		}
+	}
+	return ""
+}
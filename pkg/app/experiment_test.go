@@ -0,0 +1,184 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/experiment-test", &experimentTestCompo{})
+}
+
+type experimentTestCompo struct {
+	Compo
+
+	variant string
+}
+
+func (c *experimentTestCompo) OnPreRender(ctx Context) {
+	c.variant = ctx.Experiment("experiment-test")
+}
+
+func (c *experimentTestCompo) Render() UI {
+	return Div().ID("variant").Text(c.variant)
+}
+
+func TestHandlerExperiment(t *testing.T) {
+	defer SetFeatureFlags(FeatureFlags{})
+	SetFeatureFlags(FeatureFlags{
+		Experiments: map[string]Experiment{
+			"experiment-test": {Variants: []ExperimentVariant{{Name: "blue"}, {Name: "green"}}},
+		},
+	})
+
+	h := Handler{}
+
+	serve := func(cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/experiment-test", nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	w := serve()
+	cookies := responseCookies(w)
+	require.Len(t, cookies, 2)
+	require.Equal(t, flagUserCookieName, cookies[0].Name)
+	require.Equal(t, experimentsCookieName, cookies[1].Name)
+
+	variant := parseExperimentVariants(cookies[1].Value)["experiment-test"]
+	require.Contains(t, w.Body.String(), "<div id=\"variant\">\n"+variant+"\n</div>")
+	require.Equal(t, Experiment{
+		Variants: []ExperimentVariant{{Name: "blue"}, {Name: "green"}},
+	}.VariantFor("experiment-test", cookies[0].Value), variant)
+
+	_, cached := h.PreRenderCache.Get(context.Background(), "/experiment-test")
+	require.False(t, cached)
+
+	for _, v := range []string{"blue", "green"} {
+		w = serve(&http.Cookie{Name: experimentsCookieName, Value: "experiment-test=" + v})
+		require.Contains(t, w.Body.String(), "<div id=\"variant\">\n"+v+"\n</div>")
+		require.Len(t, responseCookies(w), 0)
+	}
+}
+
+func TestExperimentVariantFor(t *testing.T) {
+	t.Run("no variants", func(t *testing.T) {
+		require.Empty(t, Experiment{}.VariantFor("foo", "max"))
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		e := Experiment{Variants: []ExperimentVariant{{Name: "a"}, {Name: "b"}}}
+		require.Equal(t, e.VariantFor("foo", "max"), e.VariantFor("foo", "max"))
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		e := Experiment{Variants: []ExperimentVariant{
+			{Name: "control", Weight: 3},
+			{Name: "treatment", Weight: 1},
+		}}
+
+		counts := make(map[string]int)
+		for i := 0; i < 10000; i++ {
+			counts[e.VariantFor("foo", "user-"+strconv.Itoa(i))]++
+		}
+		require.InDelta(t, 7500, counts["control"], 250)
+		require.InDelta(t, 2500, counts["treatment"], 250)
+	})
+
+	t.Run("equal weights", func(t *testing.T) {
+		e := Experiment{Variants: []ExperimentVariant{{Name: "a"}, {Name: "b"}}}
+
+		counts := make(map[string]int)
+		for i := 0; i < 10000; i++ {
+			counts[e.VariantFor("foo", "user-"+strconv.Itoa(i))]++
+		}
+		require.InDelta(t, 5000, counts["a"], 250)
+		require.InDelta(t, 5000, counts["b"], 250)
+	})
+}
+
+func TestExperimentAssignments(t *testing.T) {
+	experiments := map[string]Experiment{
+		"button": {Variants: []ExperimentVariant{{Name: "blue"}, {Name: "green"}}},
+	}
+	lookup := func(name string) (Experiment, bool) {
+		e, ok := experiments[name]
+		return e, ok
+	}
+
+	t.Run("unknown experiment", func(t *testing.T) {
+		a := newExperimentAssignments("", "")
+		a.experiments = lookup
+		require.Empty(t, a.variant("unknown"))
+		require.False(t, a.isUsed())
+		require.Empty(t, a.cookies(false))
+	})
+
+	t.Run("new assignment", func(t *testing.T) {
+		a := newExperimentAssignments("", "")
+		a.experiments = lookup
+
+		v := a.variant("button")
+		require.Equal(t, experiments["button"].VariantFor("button", a.userKey), v)
+		require.True(t, a.isUsed())
+
+		cookies := a.cookies(true)
+		require.Len(t, cookies, 2)
+		require.Equal(t, flagUserCookieName, cookies[0].Name)
+		require.Equal(t, a.userKey, cookies[0].Value)
+		require.Equal(t, experimentsCookieName, cookies[1].Name)
+		require.Equal(t, "button="+v, cookies[1].Value)
+		require.True(t, cookies[1].Secure)
+		require.False(t, cookies[1].HttpOnly)
+		require.Empty(t, a.cookies(true))
+	})
+
+	t.Run("sticky assignment", func(t *testing.T) {
+		for _, v := range []string{"blue", "green"} {
+			a := newExperimentAssignments("max", "button="+v)
+			a.experiments = lookup
+			require.Equal(t, v, a.variant("button"))
+			require.Empty(t, a.cookies(false))
+		}
+	})
+
+	t.Run("removed variant is reassigned", func(t *testing.T) {
+		a := newExperimentAssignments("max", "button=red")
+		a.experiments = lookup
+		require.Equal(t, experiments["button"].VariantFor("button", "max"), a.variant("button"))
+
+		cookies := a.cookies(false)
+		require.Len(t, cookies, 1)
+		require.Equal(t, experimentsCookieName, cookies[0].Name)
+	})
+
+	t.Run("nil assignments", func(t *testing.T) {
+		var a *experimentAssignments
+		require.Empty(t, a.variant("button"))
+		require.False(t, a.isUsed())
+	})
+}
+
+func TestExperimentVariantsEncoding(t *testing.T) {
+	variants := map[string]string{
+		"b":     "x y",
+		"a&b":   "1",
+		"hello": "world",
+	}
+	encoded := encodeExperimentVariants(variants)
+	require.Equal(t, "a%26b=1&b=x+y&hello=world", encoded)
+	require.Equal(t, variants, parseExperimentVariants(encoded))
+	require.Empty(t, parseExperimentVariants(""))
+}
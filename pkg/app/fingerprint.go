@@ -0,0 +1,123 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	fingerprintParam       = "v"
+	fingerprintLen         = 16
+	fingerprintCacheHeader = "public, max-age=31536000, immutable"
+)
+
+func (h *Handler) initFingerprints() {
+	if !h.FingerprintResources {
+		return
+	}
+
+	dir, ok := h.Resources.(localDir)
+	if !ok {
+		return
+	}
+
+	fingerprints, err := fingerprintDir(dir.dir)
+	if err != nil {
+		Log(errors.New("fingerprinting static resources failed").
+			Tag("dir", dir.dir).
+			Wrap(err))
+		return
+	}
+	h.fingerprints = fingerprints
+
+	if len(fingerprints) != 0 {
+		b, _ := json.Marshal(fingerprints)
+		if h.Env == nil {
+			h.Env = make(map[string]string)
+		}
+		h.Env["GOAPP_RESOURCE_FINGERPRINTS"] = string(b)
+	}
+}
+
+func (h *Handler) fingerprintsVersion() string {
+	if len(h.fingerprints) == 0 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(h.fingerprints))
+	for p := range h.fingerprints {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, p := range paths {
+		hash.Write(stob(p))
+		hash.Write(stob(h.fingerprints[p]))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:fingerprintLen]
+}
+
+func (h *Handler) isFingerprinted(path, fingerprint string) bool {
+	return fingerprint != "" && h.fingerprints[path] == fingerprint
+}
+
+// fingerprintDir returns the fingerprints of the files located in the web
+// directory of the given root directory, indexed by their "/web/" path.
+func fingerprintDir(root string) (map[string]string, error) {
+	webDir := filepath.Join(root, "web")
+	if root == "" {
+		webDir = "web"
+	}
+
+	fingerprints := make(map[string]string)
+	if _, err := os.Stat(webDir); os.IsNotExist(err) {
+		return fingerprints, nil
+	}
+
+	err := filepath.Walk(webDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(webDir, path)
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.Sum256(b)
+		fingerprints["/web/"+filepath.ToSlash(rel)] = hex.EncodeToString(hash[:])[:fingerprintLen]
+		return nil
+	})
+	return fingerprints, err
+}
+
+// fingerprintPath appends the fingerprint associated with the given static
+// resource path, if any.
+func fingerprintPath(fingerprints map[string]string, path, resolved string) string {
+	if len(fingerprints) == 0 || strings.Contains(resolved, "?") {
+		return resolved
+	}
+
+	fingerprint, ok := fingerprints["/"+strings.TrimPrefix(path, "/")]
+	if !ok {
+		return resolved
+	}
+	return resolved + "?" + fingerprintParam + "=" + fingerprint
+}
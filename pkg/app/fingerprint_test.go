@@ -0,0 +1,86 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintDir(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateDir(t, filepath.Join("web", "css"))
+	testCreateFile(t, filepath.Join("web", "css", "main.css"), "body {}")
+	testCreateFile(t, filepath.Join("web", "app.wasm"), "wasm!")
+
+	fingerprints, err := fingerprintDir("")
+	require.NoError(t, err)
+	require.Len(t, fingerprints, 2)
+	require.Len(t, fingerprints["/web/css/main.css"], fingerprintLen)
+	require.Len(t, fingerprints["/web/app.wasm"], fingerprintLen)
+}
+
+func TestHandlerFingerprintResources(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateFile(t, filepath.Join("web", "main.css"), "body {}")
+	testCreateFile(t, filepath.Join("web", "app.wasm"), "wasm!")
+
+	h := Handler{
+		FingerprintResources: true,
+		Styles:               []string{"/web/main.css"},
+	}
+	h.once.Do(h.init)
+
+	fingerprint := h.fingerprints["/web/main.css"]
+	require.NotEmpty(t, fingerprint)
+	require.Equal(t, h.fingerprintsVersion(), h.Version)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("page references fingerprinted urls", func(t *testing.T) {
+		body := serve("/").Body.String()
+		require.Contains(t, body, `href="/web/main.css?v=`+fingerprint+`"`)
+	})
+
+	t.Run("app js references fingerprinted wasm", func(t *testing.T) {
+		body := serve("/app.js").Body.String()
+		require.Contains(t, body, `fetch("/web/app.wasm?v=`+h.fingerprints["/web/app.wasm"]+`"`)
+		require.Contains(t, body, "GOAPP_RESOURCE_FINGERPRINTS")
+	})
+
+	t.Run("service worker caches fingerprinted urls", func(t *testing.T) {
+		body := serve("/app-worker.js").Body.String()
+		require.Contains(t, body, `"/web/main.css?v=`+fingerprint+`"`)
+	})
+
+	t.Run("fingerprinted resource has far-future cache headers", func(t *testing.T) {
+		w := serve("/web/main.css?v=" + fingerprint)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, fingerprintCacheHeader, w.Header().Get("Cache-Control"))
+		require.Empty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("resource with outdated fingerprint is not cached", func(t *testing.T) {
+		w := serve("/web/main.css?v=outdated")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestHandlerFingerprintResourcesDisabled(t *testing.T) {
+	h := Handler{Version: "test"}
+	h.once.Do(h.init)
+	require.Empty(t, h.fingerprints)
+	require.Equal(t, "/web/main.css", h.resolveStaticPath("/web/main.css"))
+}
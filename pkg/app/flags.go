@@ -0,0 +1,342 @@
+package app
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The name of the action posted when feature flags change. The action
+	// value is the list of the names of the flags that changed.
+	FeatureFlagsAction = "/app/flags"
+
+	flagStatePrefix    = "/app/flags/"
+	flagUserStorageKey = "/app/flags/user"
+)
+
+// FeatureFlag describes the state of a feature flag.
+type FeatureFlag struct {
+	// Reports whether the flag is on.
+	Enabled bool `json:"enabled"`
+
+	// The percentage of users, from 0 to 100, for which an enabled flag is
+	// on. Users are assigned to a bucket derived from the flag name and their
+	// user key, which keeps a user in the same bucket across sessions.
+	//
+	// Default: 100.
+	Rollout float64 `json:"rollout,omitempty"`
+}
+
+// EnabledFor reports whether the flag with the given name is on for the given
+// user key. Flags partially rolled out are off when the user key is empty.
+//
+// It is meant to be used by servers to evaluate flags the same way as the
+// app.
+func (f FeatureFlag) EnabledFor(name, userKey string) bool {
+	switch {
+	case !f.Enabled:
+		return false
+
+	case f.Rollout <= 0 || f.Rollout >= 100:
+		return true
+
+	case userKey == "":
+		return false
+
+	default:
+		return flagBucket(name, userKey) < f.Rollout
+	}
+}
+
+// FeatureFlags describes how feature flags are evaluated and refreshed.
+//
+// eg:
+//  app.SetFeatureFlags(app.FeatureFlags{
+//      Defaults: map[string]app.FeatureFlag{
+//          "new-checkout": {Enabled: true, Rollout: 10},
+//      },
+//      URL:             "/api/flags",
+//      RefreshInterval: time.Minute,
+//  })
+type FeatureFlags struct {
+	// The flags used before remote flags are fetched, or when they can't be.
+	Defaults map[string]FeatureFlag
+
+	// The URL where remote flags are fetched from in the browser. It must
+	// return a JSON object that maps flag names to their FeatureFlag value.
+	// eg:
+	//  {"new-checkout": {"enabled": true, "rollout": 25}}
+	//
+	// Remote flags take precedence over the defaults.
+	URL string
+
+	// The interval at which remote flags are fetched again. They are fetched
+	// only once when zero.
+	RefreshInterval time.Duration
+
+	// Reports whether URL is a server-sent events stream that sends the
+	// remote flags each time they change. RefreshInterval is ignored when
+	// set.
+	Stream bool
+
+	// The A/B experiments whose variants are returned by
+	// Context.Experiment.
+	Experiments map[string]Experiment
+}
+
+// SetFeatureFlags sets how feature flags are evaluated and refreshed. It must
+// be called before RunWhenOnBrowser and before the Handler serves requests.
+//
+// On the server, flags are evaluated from their defaults, without user key.
+func SetFeatureFlags(f FeatureFlags) {
+	featureFlags.setConfig(f)
+}
+
+var (
+	featureFlags = &flagManager{}
+)
+
+type flagManager struct {
+	mutex     sync.RWMutex
+	config    FeatureFlags
+	remote    map[string]FeatureFlag
+	anonymous string
+	user      string
+	disp      Dispatcher
+	published map[string]bool
+}
+
+func (m *flagManager) setConfig(f FeatureFlags) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.config = f
+}
+
+func (m *flagManager) experiment(name string) (Experiment, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	exp, ok := m.config.Experiments[name]
+	return exp, ok
+}
+
+func (m *flagManager) anonymousKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.anonymous
+}
+
+func (m *flagManager) enabled(name string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.evaluate(name)
+}
+
+// evaluate must be called with the mutex held.
+func (m *flagManager) evaluate(name string) bool {
+	f, ok := m.remote[name]
+	if !ok {
+		f = m.config.Defaults[name]
+	}
+
+	user := m.user
+	if user == "" {
+		user = m.anonymous
+	}
+	return f.EnabledFor(name, user)
+}
+
+// publish sets the states of the flags with their current value in the given
+// dispatcher.
+func (m *flagManager) publish(d Dispatcher) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for name := range m.names() {
+		d.SetState(flagStatePrefix+name, m.evaluate(name))
+	}
+}
+
+// names must be called with the mutex held.
+func (m *flagManager) names() map[string]struct{} {
+	names := make(map[string]struct{}, len(m.config.Defaults)+len(m.remote))
+	for name := range m.config.Defaults {
+		names[name] = struct{}{}
+	}
+	for name := range m.remote {
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// start publishes the flags in the browser dispatcher and launches the
+// refresh of the remote flags.
+func (m *flagManager) start(d Dispatcher) {
+	// The anonymous key is shared with the server with a cookie in order to
+	// assign experiment variants the same way during prerendering.
+	anonymous := documentCookie(flagUserCookieName)
+	if anonymous == "" {
+		d.localStorage().Get(flagUserStorageKey, &anonymous)
+	}
+	if anonymous == "" {
+		anonymous = uuid.NewString()
+	}
+	if err := d.localStorage().Set(flagUserStorageKey, anonymous); err != nil {
+		Log(errors.New("storing feature flags user key failed").Wrap(err))
+	}
+	if documentCookie(flagUserCookieName) != anonymous {
+		c := experimentCookie(flagUserCookieName, anonymous, Window().URL().Scheme == "https")
+		Window().Get("document").Set("cookie", c.String())
+	}
+
+	m.mutex.Lock()
+	m.disp = d
+	m.anonymous = anonymous
+	m.published = make(map[string]bool)
+	for name := range m.names() {
+		m.published[name] = m.evaluate(name)
+	}
+	config := m.config
+	m.mutex.Unlock()
+
+	m.publish(d)
+
+	switch {
+	case config.URL == "":
+
+	case config.Stream:
+		m.stream(config.URL)
+
+	default:
+		m.fetch(config.URL)
+		if config.RefreshInterval > 0 {
+			go func() {
+				ticker := time.NewTicker(config.RefreshInterval)
+				defer ticker.Stop()
+
+				for range ticker.C {
+					if !d.suspended() {
+						m.fetch(config.URL)
+					}
+				}
+			}()
+		}
+	}
+}
+
+func (m *flagManager) setUser(key string) {
+	m.mutex.Lock()
+	m.user = key
+	m.mutex.Unlock()
+	m.update()
+}
+
+func (m *flagManager) setRemote(data []byte) error {
+	var remote map[string]FeatureFlag
+	if err := json.Unmarshal(data, &remote); err != nil {
+		return errors.New("decoding remote feature flags failed").Wrap(err)
+	}
+
+	m.mutex.Lock()
+	m.remote = remote
+	m.mutex.Unlock()
+	m.update()
+	return nil
+}
+
+// update publishes the flags whose value changed and posts a
+// FeatureFlagsAction with their names.
+func (m *flagManager) update() {
+	m.mutex.Lock()
+	d := m.disp
+	if d == nil {
+		m.mutex.Unlock()
+		return
+	}
+
+	var changed []string
+	values := make(map[string]bool)
+	for name := range m.names() {
+		v := m.evaluate(name)
+		if published, ok := m.published[name]; !ok || published != v {
+			changed = append(changed, name)
+			values[name] = v
+			m.published[name] = v
+		}
+	}
+	m.mutex.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	for name, v := range values {
+		d.SetState(flagStatePrefix+name, v)
+	}
+	d.Post(Action{
+		Name:  FeatureFlagsAction,
+		Value: changed,
+	})
+}
+
+func (m *flagManager) fetch(url string) {
+	fail := func(err error) {
+		Log(errors.New("fetching feature flags failed").
+			Tag("url", url).
+			Wrap(err))
+	}
+
+	awaitPromise(Window().Call("fetch", url, map[string]interface{}{
+		"credentials": "same-origin",
+	}), func(res Value) {
+		if !res.Get("ok").Bool() {
+			fail(errors.New("unexpected status").Tag("status", res.Get("status").Int()))
+			return
+		}
+
+		awaitPromise(res.Call("text"), func(text Value) {
+			if err := m.setRemote([]byte(text.String())); err != nil {
+				fail(err)
+			}
+		}, func(reason Value) {
+			fail(jsReasonError(reason))
+		})
+	}, func(reason Value) {
+		fail(jsReasonError(reason))
+	})
+}
+
+func (m *flagManager) stream(url string) {
+	if !Window().Get("EventSource").Truthy() {
+		Log(errors.New("streaming feature flags failed").
+			Tag("url", url).
+			Tag("reason", "server-sent events are not supported"))
+		return
+	}
+
+	source := Window().Get("EventSource").New(url, map[string]interface{}{
+		"withCredentials": true,
+	})
+	source.Call("addEventListener", "message", FuncOf(func(this Value, args []Value) interface{} {
+		if err := m.setRemote([]byte(promiseArg(args).Get("data").String())); err != nil {
+			Log(errors.New("streaming feature flags failed").
+				Tag("url", url).
+				Wrap(err))
+		}
+		return nil
+	}))
+}
+
+// flagBucket returns the bucket of the given user for the given flag, ranging
+// from 0 to 100.
+func flagBucket(name, userKey string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(userKey))
+	return float64(h.Sum32()%10000) / 100
+}
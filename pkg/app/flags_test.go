@@ -0,0 +1,128 @@
+package app
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagEnabledFor(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		require.False(t, FeatureFlag{}.EnabledFor("foo", "max"))
+		require.False(t, FeatureFlag{Rollout: 100}.EnabledFor("foo", "max"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		require.True(t, FeatureFlag{Enabled: true}.EnabledFor("foo", ""))
+		require.True(t, FeatureFlag{Enabled: true, Rollout: 100}.EnabledFor("foo", "max"))
+	})
+
+	t.Run("partial rollout without user key", func(t *testing.T) {
+		require.False(t, FeatureFlag{Enabled: true, Rollout: 99}.EnabledFor("foo", ""))
+	})
+
+	t.Run("partial rollout", func(t *testing.T) {
+		f := FeatureFlag{Enabled: true, Rollout: 25}
+		require.Equal(t, f.EnabledFor("foo", "max"), f.EnabledFor("foo", "max"))
+
+		enabled := 0
+		for i := 0; i < 10000; i++ {
+			if f.EnabledFor("foo", "user-"+strconv.Itoa(i)) {
+				enabled++
+			}
+		}
+		require.InDelta(t, 2500, enabled, 250)
+	})
+}
+
+func TestFlagBucket(t *testing.T) {
+	b := flagBucket("foo", "max")
+	require.GreaterOrEqual(t, b, float64(0))
+	require.Less(t, b, float64(100))
+	require.Equal(t, b, flagBucket("foo", "max"))
+	require.NotEqual(t, b, flagBucket("bar", "max"))
+}
+
+func TestFlagManager(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var changed []string
+	e.Handle(FeatureFlagsAction, h, func(ctx Context, a Action) {
+		changed = a.Value.([]string)
+	})
+
+	m := &flagManager{}
+	m.setConfig(FeatureFlags{
+		Defaults: map[string]FeatureFlag{
+			"foo": {Enabled: true},
+			"bar": {},
+		},
+	})
+	m.start(&e)
+
+	var anonymous string
+	e.localStorage().Get(flagUserStorageKey, &anonymous)
+	require.NotEmpty(t, anonymous)
+
+	var foo, bar bool
+	ctx := makeContext(h)
+	ctx.ObserveFlag("foo").Value(&foo)
+	ctx.ObserveFlag("bar").Value(&bar)
+	require.True(t, foo)
+	require.False(t, bar)
+
+	t.Run("remote flags", func(t *testing.T) {
+		err := m.setRemote([]byte(`{"bar": {"enabled": true}}`))
+		require.NoError(t, err)
+		e.Wait()
+		e.Consume()
+		require.True(t, bar)
+		require.True(t, foo)
+		require.Equal(t, []string{"bar"}, changed)
+		require.True(t, m.enabled("bar"))
+	})
+
+	t.Run("invalid remote flags", func(t *testing.T) {
+		require.Error(t, m.setRemote([]byte(`[]`)))
+	})
+
+	t.Run("user", func(t *testing.T) {
+		err := m.setRemote([]byte(`{"bar": {"enabled": true, "rollout": 50}}`))
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			key := "user-" + strconv.Itoa(i)
+			m.setUser(key)
+			e.Wait()
+			e.Consume()
+			require.Equal(t, FeatureFlag{Enabled: true, Rollout: 50}.EnabledFor("bar", key), bar)
+		}
+	})
+}
+
+func TestContextFlag(t *testing.T) {
+	defer SetFeatureFlags(FeatureFlags{})
+	SetFeatureFlags(FeatureFlags{
+		Defaults: map[string]FeatureFlag{
+			"new-checkout": {Enabled: true},
+		},
+	})
+
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	ctx := makeContext(h)
+	require.True(t, ctx.Flag("new-checkout"))
+	require.False(t, ctx.Flag("unknown"))
+
+	ctx.SetFlagUser("max")
+	require.Empty(t, featureFlags.user)
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"path"
+	"strings"
+)
+
+const (
+	defaultFontDisplay = "swap"
+)
+
+// Font describes a font face used by the pages of a Handler.
+type Font struct {
+	// The font family name. eg: "Inter".
+	Family string
+
+	// The path or URL of the font file. eg: "/web/fonts/inter-400.woff2".
+	//
+	// Font files served from the static resources directory are fingerprinted
+	// when the Handler FingerprintResources field is set.
+	Src string
+
+	// The weight of the font face, or a range of weights for variable fonts.
+	// eg: "400" or "100 900".
+	Weight string
+
+	// The style of the font face. eg: "normal" or "italic".
+	Style string
+
+	// The font-display policy that tells how text is displayed while the font
+	// face is loading. eg: "swap", "fallback", "optional" or "block".
+	//
+	// Default: "swap".
+	Display string
+
+	// The range of unicode code points supported by the font face.
+	// eg: "U+0000-00FF".
+	UnicodeRange string
+
+	// Reports whether the font face is critical to display pages. Critical
+	// font faces are preloaded.
+	Preload bool
+}
+
+func (f Font) format() string {
+	switch strings.ToLower(path.Ext(strings.SplitN(f.Src, "?", 2)[0])) {
+	case ".woff2":
+		return "woff2"
+
+	case ".woff":
+		return "woff"
+
+	case ".ttf":
+		return "truetype"
+
+	case ".otf":
+		return "opentype"
+
+	default:
+		return ""
+	}
+}
+
+func (f Font) writeFontFace(b *strings.Builder) {
+	b.WriteString("@font-face{")
+	b.WriteString(`font-family:"` + strings.ReplaceAll(f.Family, `"`, `\"`) + `";`)
+	b.WriteString(`src:url("` + f.Src + `")`)
+	if format := f.format(); format != "" {
+		b.WriteString(` format("` + format + `")`)
+	}
+	b.WriteByte(';')
+	if f.Weight != "" {
+		b.WriteString("font-weight:" + f.Weight + ";")
+	}
+	if f.Style != "" {
+		b.WriteString("font-style:" + f.Style + ";")
+	}
+	b.WriteString("font-display:" + f.Display + ";")
+	if f.UnicodeRange != "" {
+		b.WriteString("unicode-range:" + f.UnicodeRange + ";")
+	}
+	b.WriteByte('}')
+}
+
+func (h *Handler) initFonts() {
+	for i, f := range h.Fonts {
+		f.Src = h.resolveStaticPath(f.Src)
+		if f.Display == "" {
+			f.Display = defaultFontDisplay
+		}
+		h.Fonts[i] = f
+	}
+}
+
+// fontSources returns the resolved URLs of the Handler font files.
+func (h *Handler) fontSources() []string {
+	sources := make([]string, 0, len(h.Fonts))
+	for _, f := range h.Fonts {
+		sources = append(sources, f.Src)
+	}
+	return sources
+}
+
+// fontPreloads returns the preloads of the critical Handler font faces.
+func (h *Handler) fontPreloads() []Preload {
+	var preloads []Preload
+	for _, f := range h.Fonts {
+		if !f.Preload {
+			continue
+		}
+
+		p := Preload{
+			Href:        f.Src,
+			As:          "font",
+			CrossOrigin: "anonymous",
+		}
+		if format := f.format(); format != "" && format != "truetype" && format != "opentype" {
+			p.Type = "font/" + format
+		}
+		preloads = append(preloads, p)
+	}
+	return preloads
+}
+
+func renderFontFaces(fonts []Font) string {
+	if len(fonts) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<style>")
+	for _, f := range fonts {
+		f.writeFontFace(&b)
+	}
+	b.WriteString("</style>")
+	return b.String()
+}
@@ -0,0 +1,105 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFontFormat(t *testing.T) {
+	utests := []struct {
+		src    string
+		format string
+	}{
+		{src: "/web/inter.woff2", format: "woff2"},
+		{src: "/web/inter.WOFF", format: "woff"},
+		{src: "/web/inter.ttf?v=abc", format: "truetype"},
+		{src: "https://fonts.murlok.io/inter.otf", format: "opentype"},
+		{src: "/web/inter", format: ""},
+	}
+
+	for _, u := range utests {
+		t.Run(u.src, func(t *testing.T) {
+			require.Equal(t, u.format, Font{Src: u.src}.format())
+		})
+	}
+}
+
+func TestRenderFontFaces(t *testing.T) {
+	require.Empty(t, renderFontFaces(nil))
+
+	css := renderFontFaces([]Font{
+		{
+			Family:       `Inter "Var"`,
+			Src:          "/web/inter.woff2",
+			Weight:       "100 900",
+			Style:        "normal",
+			Display:      "swap",
+			UnicodeRange: "U+0000-00FF",
+		},
+		{
+			Family:  "Mono",
+			Src:     "/web/mono",
+			Display: "optional",
+		},
+	})
+	require.Equal(t, "<style>"+
+		`@font-face{font-family:"Inter \"Var\"";src:url("/web/inter.woff2") format("woff2");font-weight:100 900;font-style:normal;font-display:swap;unicode-range:U+0000-00FF;}`+
+		`@font-face{font-family:"Mono";src:url("/web/mono");font-display:optional;}`+
+		"</style>", css)
+}
+
+func TestHandlerFonts(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateFile(t, filepath.Join("web", "inter-400.woff2"), "regular")
+	testCreateFile(t, filepath.Join("web", "inter-700.woff2"), "bold")
+
+	h := Handler{
+		FingerprintResources: true,
+		Fonts: []Font{
+			{Family: "Inter", Src: "/web/inter-400.woff2", Weight: "400", Preload: true},
+			{Family: "Inter", Src: "/web/inter-700.woff2", Weight: "700", Display: "fallback"},
+		},
+	}
+
+	serve := func(path string) string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	body := serve("/")
+	regular := "/web/inter-400.woff2?v=" + h.fingerprints["/web/inter-400.woff2"]
+	bold := "/web/inter-700.woff2?v=" + h.fingerprints["/web/inter-700.woff2"]
+
+	require.Contains(t, body, `src:url("`+regular+`") format("woff2");font-weight:400;font-display:swap;`)
+	require.Contains(t, body, `src:url("`+bold+`") format("woff2");font-weight:700;font-display:fallback;`)
+	require.Regexp(t, `<link [^>]*href="`+strings.ReplaceAll(regular, "?", `\?`)+`"`, body)
+	require.NotRegexp(t, `<link [^>]*href="`+strings.ReplaceAll(bold, "?", `\?`)+`"`, body)
+	require.Regexp(t, `<link [^>]*as="font"`, body)
+	require.Regexp(t, `<link [^>]*type="font/woff2"`, body)
+
+	worker := serve("/app-worker.js")
+	require.Contains(t, worker, regular)
+	require.Contains(t, worker, bold)
+}
+
+func TestContextFontsReadyOnServer(t *testing.T) {
+	d := NewServerTester(Div())
+	defer d.Close()
+
+	called := false
+	d.Context().FontsReady(func(Context) {
+		called = true
+	})
+	d.Consume()
+	require.False(t, called)
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	defaultFormFieldPrefix = "field"
+)
+
+// FormField is the interface that describes a form field made of a label, a
+// form control, and an optional hint and error message.
+type FormField interface {
+	UI
+
+	// Sets a text that describes the expected value, such as a format.
+	Hint(v string) FormField
+
+	// Sets the error message of the field. The control is marked as invalid
+	// when it is not empty.
+	Error(v string) FormField
+
+	// Sets the prefix of the generated IDs.
+	//
+	// Default: "field".
+	IDPrefix(v string) FormField
+}
+
+// Field returns a form field that connects the given label and form control,
+// which is an input, a select or a textarea, with its hint and error message.
+//
+// IDs are generated with Context.StableID: the label points to the control
+// with its for attribute, and the control is described by the hint and the
+// error with aria-describedby. The IDs are the same when the field is
+// pre-rendered and when it is mounted in the browser. Eg:
+//  app.Form().Body(
+//      app.Field("Email", app.Input().Type("email").OnChange(f.onEmailChange)).
+//          Hint("We never share your email.").
+//          Error(f.emailErr),
+//  )
+//
+// It panics when control is not an HTML element.
+func Field(label string, control UI) FormField {
+	return &formField{
+		LabelText: label,
+		Control:   control,
+	}
+}
+
+type formField struct {
+	Compo
+
+	LabelText string
+	HintText  string
+	ErrorText string
+	Prefix    string
+	Control   UI
+
+	id string
+}
+
+func (f *formField) Hint(v string) FormField {
+	f.HintText = v
+	return f
+}
+
+func (f *formField) Error(v string) FormField {
+	f.ErrorText = v
+	return f
+}
+
+func (f *formField) IDPrefix(v string) FormField {
+	f.Prefix = v
+	return f
+}
+
+func (f *formField) OnPreRender(ctx Context) {
+	f.setID(ctx)
+}
+
+func (f *formField) OnMount(ctx Context) {
+	f.setID(ctx)
+}
+
+func (f *formField) OnUpdate(ctx Context) {
+	f.setID(ctx)
+}
+
+func (f *formField) setID(ctx Context) {
+	prefix := f.Prefix
+	if prefix == "" {
+		prefix = defaultFormFieldPrefix
+	}
+	if id := ctx.StableID(prefix); id != f.id {
+		f.id = id
+		f.Update()
+	}
+}
+
+func (f *formField) Render() UI {
+	ids := wireFormControl(f.Control, f.id, f.HintText != "", f.ErrorText != "")
+
+	return Div().
+		Class("goapp-field").
+		Body(
+			Label().
+				ID(ids.label).
+				For(ids.control).
+				Text(f.LabelText),
+			f.Control,
+			If(f.HintText != "",
+				Div().
+					ID(ids.hint).
+					Class("goapp-field-hint").
+					Text(f.HintText),
+			),
+			If(f.ErrorText != "",
+				Div().
+					ID(ids.error).
+					Class("goapp-field-error").
+					Attr("role", "alert").
+					Text(f.ErrorText),
+			),
+		)
+}
+
+// formFieldID contains the IDs of the elements of a form field. They are
+// derived from the ID of the control, which is the generated one unless it is
+// set by the caller. IDs are empty until the field is in a mounted tree.
+type formFieldID struct {
+	control     string
+	label       string
+	hint        string
+	error       string
+	describedBy string
+}
+
+func formFieldIDs(id string, hasHint, hasError bool) formFieldID {
+	if id == "" {
+		return formFieldID{}
+	}
+
+	ids := formFieldID{
+		control: id,
+		label:   id + "-label",
+		hint:    id + "-hint",
+		error:   id + "-error",
+	}
+
+	var describedBy []string
+	if hasHint {
+		describedBy = append(describedBy, ids.hint)
+	}
+	if hasError {
+		describedBy = append(describedBy, ids.error)
+	}
+	ids.describedBy = strings.Join(describedBy, " ")
+	return ids
+}
+
+// wireFormControl sets the aria-describedby and aria-invalid attributes of
+// the given control, and its id when it does not have one, then returns the
+// IDs of the field. The attributes of a control that is already mounted are
+// also updated in the DOM since it is not diffed against itself.
+func wireFormControl(control UI, id string, hasHint, hasError bool) formFieldID {
+	e, ok := control.(interface {
+		setAttr(k string, v interface{})
+		updateAttrs(attrs map[string]string)
+	})
+	if !ok {
+		panic(errors.New("wiring form control failed").
+			Tag("reason", "not an html element").
+			Tag("type", fmt.Sprintf("%T", control)),
+		)
+	}
+
+	if controlID := control.attributes()["id"]; controlID != "" {
+		id = controlID
+	}
+	ids := formFieldIDs(id, hasHint, hasError)
+
+	wired := map[string]string{
+		"id":               ids.control,
+		"aria-describedby": ids.describedBy,
+	}
+	if hasError {
+		wired["aria-invalid"] = "true"
+	}
+
+	if !control.Mounted() {
+		for k, v := range wired {
+			if v != "" {
+				e.setAttr(k, v)
+			}
+		}
+		return ids
+	}
+
+	attrs := make(map[string]string, len(control.attributes())+len(wired))
+	for k, v := range control.attributes() {
+		attrs[k] = v
+	}
+	delete(attrs, "aria-invalid")
+	for k, v := range wired {
+		if v != "" {
+			attrs[k] = v
+		} else {
+			delete(attrs, k)
+		}
+	}
+	e.updateAttrs(attrs)
+	return ids
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type formFieldCompo struct {
+	Compo
+
+	err     string
+	control UI
+	field   FormField
+}
+
+func (c *formFieldCompo) Render() UI {
+	control := c.control
+	if control == nil {
+		control = Input().Type("email")
+	}
+	c.field = Field("Email", control).
+		Hint("We never share it.").
+		Error(c.err)
+	return Form().Body(c.field)
+}
+
+func TestField(t *testing.T) {
+	t.Run("ids are wired", func(t *testing.T) {
+		compo := &formFieldCompo{}
+		d := NewClientTester(compo)
+		defer d.Close()
+		d.Consume()
+
+		field := compo.root.children()[0].(*formField)
+		require.Equal(t, "field-0-0", field.id)
+
+		label := field.root.children()[0]
+		control := field.root.children()[1]
+		hint := field.root.children()[2]
+
+		require.Equal(t, "field-0-0-label", label.attributes()["id"])
+		require.Equal(t, "field-0-0", label.attributes()["for"])
+		require.Equal(t, "field-0-0", control.attributes()["id"])
+		require.Equal(t, "email", control.attributes()["type"])
+		require.Equal(t, "field-0-0-hint", control.attributes()["aria-describedby"])
+		require.Equal(t, "field-0-0-hint", hint.attributes()["id"])
+		require.NotContains(t, control.attributes(), "aria-invalid")
+	})
+
+	t.Run("error is wired", func(t *testing.T) {
+		compo := &formFieldCompo{}
+		d := NewClientTester(compo)
+		defer d.Close()
+		d.Consume()
+
+		compo.err = "Invalid email."
+		compo.Update()
+		d.Consume()
+
+		field := compo.root.children()[0].(*formField)
+		control := field.root.children()[1]
+		require.Equal(t, "true", control.attributes()["aria-invalid"])
+		require.Equal(t, "field-0-0-hint field-0-0-error", control.attributes()["aria-describedby"])
+
+		err := field.root.children()[3]
+		require.Equal(t, "field-0-0-error", err.attributes()["id"])
+		require.Equal(t, "alert", err.attributes()["role"])
+
+		compo.err = ""
+		compo.Update()
+		d.Consume()
+		control = field.root.children()[1]
+		require.NotContains(t, control.attributes(), "aria-invalid")
+		require.Equal(t, "field-0-0-hint", control.attributes()["aria-describedby"])
+	})
+
+	t.Run("control id is kept", func(t *testing.T) {
+		compo := &formFieldCompo{control: Input().ID("email")}
+		d := NewClientTester(compo)
+		defer d.Close()
+		d.Consume()
+
+		field := compo.root.children()[0].(*formField)
+		label := field.root.children()[0]
+		control := field.root.children()[1]
+		require.Equal(t, "email", label.attributes()["for"])
+		require.Equal(t, "email", control.attributes()["id"])
+		require.Equal(t, "email-hint", control.attributes()["aria-describedby"])
+	})
+
+	t.Run("ids match pre-rendering", func(t *testing.T) {
+		serverCompo := &formFieldCompo{}
+		server := NewServerTester(Div().Body(serverCompo)).(*engine)
+		defer server.Close()
+		server.PreRender()
+		server.Consume()
+
+		clientCompo := &formFieldCompo{}
+		client := NewClientTester(clientCompo)
+		defer client.Close()
+		client.Consume()
+
+		serverField := serverCompo.root.children()[0].(*formField)
+		clientField := clientCompo.root.children()[0].(*formField)
+		require.Equal(t, "field-0-0", serverField.id)
+		require.Equal(t, clientField.id, serverField.id)
+	})
+
+	t.Run("non html control panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			wireFormControl(&formFieldCompo{}, "field", false, false)
+		})
+	})
+}
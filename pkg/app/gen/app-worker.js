@@ -1,4 +1,10 @@
 const cacheName = "app-" + "{{.Version}}";
+const backgroundFetchCacheName = "goapp-background-fetch";
+const contentIndexCacheName = "goapp-content-index";
+
+// The caches that store the files downloaded by the app. They are kept when
+// the app is updated.
+const persistentCacheNames = [backgroundFetchCacheName, contentIndexCacheName];
 
 self.addEventListener("install", event => {
   console.log("installing app worker {{.Version}}");
@@ -22,7 +28,7 @@ self.addEventListener("activate", event => {
     caches.keys().then(keyList => {
       return Promise.all(
         keyList.map(key => {
-          if (key !== cacheName) {
+          if (key !== cacheName && !persistentCacheNames.includes(key)) {
             return caches.delete(key);
           }
         })
@@ -32,6 +38,99 @@ self.addEventListener("activate", event => {
   console.log("app worker {{.Version}} is activated");
 });
 
+self.addEventListener("message", event => {
+  if (!event.data || event.data.type !== "goapp-purge-caches") {
+    return;
+  }
+
+  event.waitUntil(
+    caches.keys()
+      .then(keyList => Promise.all(keyList
+        .filter(key => !persistentCacheNames.includes(key))
+        .map(key => caches.delete(key))))
+      .then(() => {
+        console.log("app worker {{.Version}} caches are purged");
+        if (event.ports[0]) {
+          event.ports[0].postMessage(true);
+        }
+      })
+  );
+});
+
+self.addEventListener("backgroundfetchsuccess", event => {
+  event.waitUntil(settleBackgroundFetch(event.registration, true));
+});
+
+self.addEventListener("backgroundfetchfail", event => {
+  event.waitUntil(settleBackgroundFetch(event.registration, false));
+});
+
+self.addEventListener("backgroundfetchabort", event => {
+  event.waitUntil(settleBackgroundFetch(event.registration, false));
+});
+
+self.addEventListener("backgroundfetchclick", event => {
+  event.waitUntil(self.clients.openWindow(self.registration.scope));
+});
+
+function settleBackgroundFetch(reg, succeeded) {
+  // The result is recorded in the cache to be reported to the app when it is
+  // opened again.
+  const record = new URL(
+    "app-background-fetch/" + encodeURIComponent(reg.id),
+    self.registration.scope
+  ).href;
+
+  const progress = {
+    type: "goapp-background-fetch",
+    id: reg.id,
+    downloaded: reg.downloaded,
+    downloadTotal: reg.downloadTotal,
+    result: reg.result || (succeeded ? "success" : "failure"),
+    failureReason: reg.failureReason,
+    record: record,
+  };
+
+  return caches.open(backgroundFetchCacheName)
+    .then(cache => {
+      const stored = !succeeded ? Promise.resolve() : reg.matchAll()
+        .then(records => Promise.all(records.map(r => {
+          return r.responseReady.then(res => cache.put(r.request, res));
+        })));
+
+      return stored.then(() => cache.put(record, new Response(JSON.stringify(progress), {
+        headers: { "Content-Type": "application/json" },
+      })));
+    })
+    .then(() => self.clients.matchAll({ type: "window" }))
+    .then(clients => clients.forEach(client => client.postMessage(progress)))
+    .catch(err => {
+      console.error("settling background fetch " + reg.id + " failed:", err);
+    });
+}
+
+self.addEventListener("contentdelete", event => {
+  // The page of an item removed from the browser offline content UI is
+  // deleted from the cache.
+  const record = new URL(
+    "app-content-index/" + encodeURIComponent(event.id),
+    self.registration.scope
+  ).href;
+
+  event.waitUntil(
+    caches.open(contentIndexCacheName)
+      .then(cache => cache.match(record)
+        .then(res => res ? res.json() : null)
+        .then(data => Promise.all([
+          data ? cache.delete(data.url) : false,
+          cache.delete(record),
+        ])))
+      .catch(err => {
+        console.error("deleting indexed content " + event.id + " failed:", err);
+      })
+  );
+});
+
 self.addEventListener("fetch", event => {
   event.respondWith(
     caches.match(event.request).then(response => {
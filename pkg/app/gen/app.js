@@ -29,8 +29,22 @@ if ("serviceWorker" in navigator) {
 // Env
 // -----------------------------------------------------------------------------
 const goappEnv = {{.Env }};
+let goappPageEnv = null;
 
 function goappGetenv(k) {
+  if (goappPageEnv === null) {
+    const script = document.getElementById("app-env");
+    try {
+      goappPageEnv = script ? JSON.parse(script.textContent) : {};
+    } catch (err) {
+      console.error("parsing page env failed:", err);
+      goappPageEnv = {};
+    }
+  }
+
+  if (k in goappPageEnv) {
+    return goappPageEnv[k];
+  }
   return goappEnv[k];
 }
 
@@ -66,6 +80,122 @@ async function goappShowInstallPrompt() {
   deferredPrompt = null;
 }
 
+// -----------------------------------------------------------------------------
+// Client reports
+// -----------------------------------------------------------------------------
+var goappReport = function (report) { };
+
+function goappInitReports() {
+  const url = goappGetenv("GOAPP_REPORT_URL");
+  if (!url) {
+    return;
+  }
+
+  const sampled = (rate) => Math.random() < parseFloat(rate);
+  const reportsErrors = sampled(goappGetenv("GOAPP_REPORT_ERROR_SAMPLE_RATE"));
+  const reportsVitals = sampled(goappGetenv("GOAPP_REPORT_SAMPLE_RATE"));
+  const maxBatchSize = 20;
+  let queue = [];
+
+  const flush = () => {
+    if (!queue.length) {
+      return;
+    }
+
+    const body = JSON.stringify(queue);
+    queue = [];
+
+    if (navigator.sendBeacon && navigator.sendBeacon(url, new Blob([body], { type: "application/json" }))) {
+      return;
+    }
+    fetch(url, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: body,
+      keepalive: true,
+    }).catch(() => { });
+  };
+
+  const push = (report) => {
+    report.url = location.pathname;
+    report.time = new Date().toISOString();
+    report.version = goappGetenv("GOAPP_VERSION");
+    queue.push(report);
+
+    if (queue.length >= maxBatchSize) {
+      flush();
+    }
+  };
+
+  if (reportsErrors) {
+    goappReport = push;
+
+    window.addEventListener("error", (e) => {
+      push({
+        type: "error",
+        message: e.message,
+        source: e.filename,
+        line: e.lineno,
+        column: e.colno,
+        stack: e.error && e.error.stack ? String(e.error.stack) : undefined,
+      });
+    });
+
+    window.addEventListener("unhandledrejection", (e) => {
+      const reason = e.reason || {};
+      push({
+        type: "unhandledrejection",
+        message: String(reason.message || e.reason),
+        stack: reason.stack ? String(reason.stack) : undefined,
+      });
+    });
+  }
+
+  let lcp = 0;
+  let cls = 0;
+  let vitalsReported = false;
+
+  if (reportsVitals && "PerformanceObserver" in window) {
+    const observe = (type, fn) => {
+      try {
+        new PerformanceObserver((list) => list.getEntries().forEach(fn))
+          .observe({ type: type, buffered: true });
+      } catch (err) {
+        // Entry type not supported by the browser.
+      }
+    };
+
+    observe("longtask", (e) => push({ type: "longtask", value: e.duration }));
+    observe("measure", (e) => push({ type: "measure", message: e.name, value: e.duration }));
+    observe("largest-contentful-paint", (e) => lcp = e.startTime);
+    observe("first-input", (e) => push({ type: "fid", value: e.processingStart - e.startTime }));
+    observe("layout-shift", (e) => {
+      if (!e.hadRecentInput) {
+        cls += e.value;
+      }
+    });
+  }
+
+  document.addEventListener("visibilitychange", () => {
+    if (document.visibilityState != "hidden") {
+      return;
+    }
+
+    if (reportsVitals && !vitalsReported) {
+      vitalsReported = true;
+      if (lcp) {
+        push({ type: "lcp", value: lcp });
+      }
+      push({ type: "cls", value: cls });
+    }
+    flush();
+  });
+
+  setInterval(flush, 10000);
+}
+
+goappInitReports();
+
 // -----------------------------------------------------------------------------
 // Keep body clean
 // -----------------------------------------------------------------------------
@@ -92,9 +222,117 @@ function goappKeepBodyClean() {
   return () => mutationObserver.disconnect();
 }
 
+// -----------------------------------------------------------------------------
+// Crash detection
+// -----------------------------------------------------------------------------
+function goappMarkCrashed() {
+  try {
+    sessionStorage.setItem("/app/crash/detected", "true");
+  } catch (err) {
+    // Session storage not available.
+  }
+}
+
+function goappWatchCrashes(go) {
+  const exit = go.exit;
+  go.exit = (code) => {
+    if (code !== 0) {
+      goappMarkCrashed();
+    }
+    exit(code);
+  };
+
+  window.addEventListener("error", (e) => {
+    if (e.error instanceof WebAssembly.RuntimeError) {
+      goappMarkCrashed();
+    }
+  });
+}
+
+// -----------------------------------------------------------------------------
+// Loading failures
+// -----------------------------------------------------------------------------
+const goappLoadAttemptsKey = "goapp-load-attempts";
+
+function goappPurgeCaches() {
+  const purge = new Promise((resolve) => {
+    const controller = navigator.serviceWorker && navigator.serviceWorker.controller;
+    if (!controller) {
+      resolve();
+      return;
+    }
+
+    const channel = new MessageChannel();
+    channel.port1.onmessage = () => resolve();
+    controller.postMessage({ type: "goapp-purge-caches" }, [channel.port2]);
+    setTimeout(resolve, 3000);
+  });
+
+  return purge
+    .then(() => {
+      if ("caches" in window) {
+        return caches.keys().then(keys => Promise.all(keys
+          .filter(key => key !== "goapp-background-fetch" && key !== "goapp-content-index")
+          .map(key => caches.delete(key))));
+      }
+    })
+    .then(() => {
+      if ("serviceWorker" in navigator) {
+        return navigator.serviceWorker.getRegistration().then(reg => reg && reg.update());
+      }
+    })
+    .catch(err => {
+      console.error("purging caches failed:", err);
+    });
+}
+
+function goappOnLoadFailure(err) {
+  let attempts = 0;
+  try {
+    attempts = parseInt(sessionStorage.getItem(goappLoadAttemptsKey) || "0", 10);
+  } catch (e) {
+    // Session storage not available.
+  }
+
+  const maxRetries = parseInt(goappGetenv("GOAPP_LOADING_RETRIES") || "0", 10);
+  if (attempts < maxRetries) {
+    // A failure is usually caused by a stale wasm file served from the cache
+    // that does not match the current app.js. Caches are purged before
+    // reloading the page with an exponential backoff.
+    console.warn("loading wasm failed, retrying:", err);
+    try {
+      sessionStorage.setItem(goappLoadAttemptsKey, String(attempts + 1));
+    } catch (e) {
+      // Session storage not available.
+    }
+
+    goappPurgeCaches().then(() => {
+      setTimeout(() => location.reload(), 1000 * Math.pow(2, attempts));
+    });
+    return;
+  }
+
+  try {
+    sessionStorage.removeItem(goappLoadAttemptsKey);
+  } catch (e) {
+    // Session storage not available.
+  }
+
+  const loaderIcon = document.getElementById("app-wasm-loader-icon");
+  loaderIcon.className = "goapp-logo";
+
+  const loaderLabel = document.getElementById("app-wasm-loader-label");
+  loaderLabel.innerText = goappGetenv("GOAPP_LOADING_ERROR_LABEL") || err;
+
+  console.error("loading wasm failed: " + err);
+  goappReport({ type: "error", message: "loading wasm failed: " + err });
+}
+
 // -----------------------------------------------------------------------------
 // Init Web Assembly
 // -----------------------------------------------------------------------------
+var goappWasmInstance = null;
+
 if (!/bot|googlebot|crawler|spider|robot|crawling/i.test(navigator.userAgent)) {
   if (!WebAssembly.instantiateStreaming) {
     WebAssembly.instantiateStreaming = async (resp, importObject) => {
@@ -104,23 +342,23 @@ if (!/bot|googlebot|crawler|spider|robot|crawling/i.test(navigator.userAgent)) {
   }
 
   const go = new Go();
+  goappWatchCrashes(go);
 
   WebAssembly.instantiateStreaming(fetch("{{.Wasm}}"), go.importObject)
     .then(result => {
       const loaderIcon = document.getElementById("app-wasm-loader-icon");
       loaderIcon.className = "goapp-logo";
 
+      try {
+        sessionStorage.removeItem(goappLoadAttemptsKey);
+      } catch (e) {
+        // Session storage not available.
+      }
+
+      goappWasmInstance = result.instance;
       go.run(result.instance);
     })
-    .catch(err => {
-      const loaderIcon = document.getElementById("app-wasm-loader-icon");
-      loaderIcon.className = "goapp-logo";
-
-      const loaderLabel = document.getElementById("app-wasm-loader-label");
-      loaderLabel.innerText = err;
-
-      console.error("loading wasm failed: " + err);
-    });
+    .catch(goappOnLoadFailure);
 } else {
   document.getElementById('app-wasm-loader').style.display = "none";
 }
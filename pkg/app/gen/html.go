@@ -437,6 +437,7 @@ var tags = []tag{
 			"allowfullscreen",
 			"allowpaymentrequest",
 			"height",
+			"loading",
 			"name",
 			"referrerpolicy",
 			"sandbox",
@@ -456,8 +457,11 @@ var tags = []tag{
 		Attrs: withGlobalAttrs(attrsByNames(
 			"alt",
 			"crossorigin",
+			"decoding",
 			"height",
 			"ismap",
+			"loading",
+			"responsive",
 			"sizes",
 			"src",
 			"srcset",
@@ -478,6 +482,7 @@ var tags = []tag{
 			"accept",
 			"alt",
 			"autocomplete",
+			"autocomplete-tokens",
 			"autofocus",
 			"checked",
 			"dirname",
@@ -555,6 +560,7 @@ var tags = []tag{
 		SelfClosing: true,
 		Doc:         "defines the relationship between a document and an external resource (most used to link to style sheets).",
 		Attrs: withGlobalAttrs(attrsByNames(
+			"as",
 			"crossorigin",
 			"href",
 			"hreflang",
@@ -790,6 +796,7 @@ var tags = []tag{
 		Name: "Select",
 		Doc:  "defines a drop-down list.",
 		Attrs: withGlobalAttrs(attrsByNames(
+			"autocomplete-tokens",
 			"autofocus",
 			"disabled",
 			"form",
@@ -893,6 +900,7 @@ var tags = []tag{
 		Name: "Textarea",
 		Doc:  "defines a multiline input control (text area).",
 		Attrs: withGlobalAttrs(attrsByNames(
+			"autocomplete-tokens",
 			"autofocus",
 			"cols",
 			"dirname",
@@ -1064,6 +1072,11 @@ var attrs = map[string]attr{
 		Type: "string",
 		Doc:  "specifies an alternate text when the original element fails to display.",
 	},
+	"as": {
+		Name: "As",
+		Type: "string",
+		Doc:  "specifies the type of content being loaded by a preload or prefetch link.",
+	},
 	"async": {
 		Name: "Async",
 		Type: "bool",
@@ -1074,6 +1087,12 @@ var attrs = map[string]attr{
 		Type: "on/off",
 		Doc:  "specifies whether the element should have autocomplete enabled.",
 	},
+	"autocomplete-tokens": {
+		Name:         "AutoCompleteTokens",
+		NameOverride: "autocomplete",
+		Type:         "string|class",
+		Doc:          `specifies the autofill tokens that tell the browser which value to suggest. eg: "username", "current-password", "new-password" or "one-time-code".`,
+	},
 	"autofocus": {
 		Name: "AutoFocus",
 		Type: "bool",
@@ -1158,6 +1177,11 @@ var attrs = map[string]attr{
 		Type: "string",
 		Doc:  "specifies the date and time.",
 	},
+	"decoding": {
+		Name: "Decoding",
+		Type: "string",
+		Doc:  "specifies whether the image is decoded synchronously (sync), asynchronously (async) or automatically (auto).",
+	},
 	"default": {
 		Name: "Default",
 		Type: "bool",
@@ -1311,6 +1335,11 @@ var attrs = map[string]attr{
 		Type: "string",
 		Doc:  "refers to a datalist element that contains pre-defined options for an input element.",
 	},
+	"loading": {
+		Name: "Loading",
+		Type: "string",
+		Doc:  "specifies whether the element is loaded immediately (eager) or deferred until it is near the viewport (lazy).",
+	},
 	"loop": {
 		Name: "Loop",
 		Type: "bool",
@@ -1436,6 +1465,11 @@ var attrs = map[string]attr{
 		Type: "bool",
 		Doc:  "specifies that the element must be filled out before submitting the form.",
 	},
+	"responsive": {
+		Name: "Responsive",
+		Type: "int|responsive",
+		Doc:  "sets the srcset with the URLs of the image resized by the Handler image endpoint to the given widths. It can be called before or after Src.",
+	},
 	"reversed": {
 		Name: "Reversed",
 		Type: "bool",
@@ -2243,7 +2277,7 @@ func writeAttrFunction(w io.Writer, a attr, t tag, isInterface bool) {
 	
 				e.setAttr("%s", s)
 				return e
-			}`, strings.ToLower(a.Name))
+			}`, attrName(a))
 		}
 
 	case "bool|force":
@@ -2257,7 +2291,7 @@ func writeAttrFunction(w io.Writer, a attr, t tag, isInterface bool) {
 	
 				e.setAttr("%s", s)
 				return e
-			}`, strings.ToLower(a.Name))
+			}`, attrName(a))
 		}
 
 	case "url":
@@ -2266,7 +2300,16 @@ func writeAttrFunction(w io.Writer, a attr, t tag, isInterface bool) {
 			fmt.Fprintf(w, `{
 				e.setAttr("%s", v)
 				return e
-			}`, strings.ToLower(a.Name))
+			}`, attrName(a))
+		}
+
+	case "int|responsive":
+		fmt.Fprintf(w, `%s(widths ...int) HTML%s`, a.Name, t.Name)
+		if !isInterface {
+			fmt.Fprint(w, `{
+				e.setResponsiveSrcSet(widths)
+				return e
+			}`)
 		}
 
 	case "string|class":
@@ -2275,7 +2318,7 @@ func writeAttrFunction(w io.Writer, a attr, t tag, isInterface bool) {
 			fmt.Fprintf(w, `{
 				e.setAttr("%s", strings.Join(v, " "))
 				return e
-			}`, strings.ToLower(a.Name))
+			}`, attrName(a))
 		}
 
 	default:
@@ -2284,11 +2327,18 @@ func writeAttrFunction(w io.Writer, a attr, t tag, isInterface bool) {
 			fmt.Fprintf(w, `{
 				e.setAttr("%s", v)
 				return e
-			}`, strings.ToLower(a.Name))
+			}`, attrName(a))
 		}
 	}
 }
 
+func attrName(a attr) string {
+	if a.NameOverride != "" {
+		return a.NameOverride
+	}
+	return strings.ToLower(a.Name)
+}
+
 func writeEventFunction(w io.Writer, e eventHandler, t tag, isInterface bool) {
 	if !isInterface {
 		fmt.Fprintf(w, `func (e *html%s)`, t.Name)
@@ -2359,6 +2409,9 @@ import (
 			case "string|class":
 				fmt.Fprintln(f, `"foo bar")`)
 
+			case "int|responsive":
+				fmt.Fprintln(f, `320, 640)`)
+
 			default:
 				fmt.Fprintln(f, `42)`)
 			}
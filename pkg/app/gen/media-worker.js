@@ -0,0 +1,109 @@
+// -----------------------------------------------------------------------------
+// Media worker
+// -----------------------------------------------------------------------------
+// Processes images and PDF pages off the UI thread. Messages have the following
+// shape:
+//   { id, op: "image" | "pdf-page", file, options }
+// Results are posted back as:
+//   { id, file, width, height } or { id, error }
+
+let pdfjsLoaded = "";
+
+self.onmessage = async (event) => {
+  const { id, op, file, options } = event.data;
+
+  try {
+    let result;
+    switch (op) {
+      case "image":
+        result = await processImage(file, options);
+        break;
+
+      case "pdf-page":
+        result = await renderPDFPage(file, options);
+        break;
+
+      default:
+        throw new Error("unknown operation: " + op);
+    }
+    self.postMessage({ id, ...result });
+  } catch (err) {
+    self.postMessage({ id, error: String((err && err.message) || err) });
+  }
+};
+
+async function processImage(file, options) {
+  const bitmap = await createImageBitmap(file, {
+    imageOrientation: "from-image",
+  });
+
+  const rotate = (((options.rotate || 0) % 360) + 360) % 360;
+  const swap = rotate === 90 || rotate === 270;
+
+  let width = bitmap.width;
+  let height = bitmap.height;
+  const scale = Math.min(
+    1,
+    options.maxWidth > 0 ? options.maxWidth / (swap ? height : width) : 1,
+    options.maxHeight > 0 ? options.maxHeight / (swap ? width : height) : 1
+  );
+  width = Math.max(1, Math.round(width * scale));
+  height = Math.max(1, Math.round(height * scale));
+
+  const canvas = new OffscreenCanvas(swap ? height : width, swap ? width : height);
+  const ctx = canvas.getContext("2d");
+  ctx.translate(canvas.width / 2, canvas.height / 2);
+  ctx.rotate((rotate * Math.PI) / 180);
+  ctx.drawImage(bitmap, -width / 2, -height / 2, width, height);
+  bitmap.close();
+
+  return encode(canvas, file.name, options);
+}
+
+async function renderPDFPage(file, options) {
+  if (pdfjsLoaded !== options.pdfjs) {
+    importScripts(options.pdfjs);
+    pdfjsLoaded = options.pdfjs;
+  }
+
+  const data = new Uint8Array(await file.arrayBuffer());
+  const doc = await pdfjsLib.getDocument({
+    data: data,
+    isOffscreenCanvasSupported: true,
+  }).promise;
+
+  try {
+    const page = await doc.getPage(options.page);
+    const viewport = page.getViewport({ scale: options.scale });
+    const canvas = new OffscreenCanvas(
+      Math.ceil(viewport.width),
+      Math.ceil(viewport.height)
+    );
+
+    await page.render({
+      canvasContext: canvas.getContext("2d"),
+      viewport: viewport,
+    }).promise;
+
+    const name = file.name.replace(/\.pdf$/i, "") + "-" + options.page;
+    return encode(canvas, name, options);
+  } finally {
+    doc.destroy();
+  }
+}
+
+async function encode(canvas, name, options) {
+  const blob = await canvas.convertToBlob({
+    type: options.type,
+    quality: options.quality,
+  });
+
+  const ext = blob.type.split("/")[1] || "img";
+  const filename = (name || "image").replace(/\.[^./]+$/, "") + "." + ext;
+
+  return {
+    file: new File([blob], filename, { type: blob.type }),
+    width: canvas.width,
+    height: canvas.height,
+  };
+}
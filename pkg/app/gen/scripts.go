@@ -38,6 +38,7 @@ func main() {
 		{Var: "appWorkerJS", Filename: "gen/app-worker.js"},
 		{Var: "manifestJSON", Filename: "gen/manifest.webmanifest"},
 		{Var: "appCSS", Filename: "gen/app.css"},
+		{Var: "mediaWorkerJS", Filename: "gen/media-worker.js"},
 	}
 
 	fmt.Fprintln(f, "const(")
@@ -0,0 +1,485 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// GestureType represents the type of a recognized gesture.
+type GestureType int
+
+const (
+	// A short press and release without significant movement.
+	Tap GestureType = iota
+
+	// A press held without significant movement.
+	LongPress
+
+	// A fast movement released in a given direction.
+	Swipe
+
+	// A single pointer movement.
+	Pan
+
+	// A two pointers movement that changes the distance between them.
+	Pinch
+)
+
+// GesturePhase represents the phase of a continuous gesture such as a pan or
+// a pinch.
+type GesturePhase int
+
+const (
+	// The gesture started.
+	GestureStart GesturePhase = iota
+
+	// The gesture is in progress.
+	GestureMove
+
+	// The gesture ended.
+	GestureEnd
+)
+
+// Gesture describes a recognized gesture.
+type Gesture struct {
+	// The type of the gesture.
+	Type GestureType
+
+	// The phase of the gesture. Taps, long presses and swipes always end.
+	Phase GesturePhase
+
+	// The direction of a swipe or a pan: "left", "right", "up" or "down". It
+	// is "in" or "out" for a pinch.
+	Direction string
+
+	// The position of the pointer, in px relative to the viewport. It is the
+	// center of the two pointers for a pinch.
+	X, Y float64
+
+	// The distance traveled since the gesture started, in px.
+	DeltaX, DeltaY float64
+
+	// The speed of the movement, in px per second.
+	VelocityX, VelocityY float64
+
+	// The ratio between the current and the starting distance of the two
+	// pointers of a pinch.
+	Scale float64
+
+	// The time elapsed since the gesture started.
+	Duration time.Duration
+
+	// The pointer event that triggered the gesture.
+	Event Event
+}
+
+// Gestures recognizes taps, long presses, swipes, pans and pinches from the
+// pointer events of the elements it is attached to.
+//
+// A Gestures keeps track of the pointers between events. It should be stored
+// in a component field and attached to an element when rendering:
+//  type photo struct {
+//      app.Compo
+//      gestures app.Gestures
+//  }
+//
+//  func (p *photo) Render() app.UI {
+//      p.gestures.OnSwipe = p.onSwipe
+//      return p.gestures.Attach(app.Img().Src("/web/photo.jpg"))
+//  }
+//
+// Zero options are replaced by their default value.
+type Gestures struct {
+	// The maximum duration of a tap.
+	//
+	// Default: 250ms.
+	TapMaxDuration time.Duration
+
+	// The distance in px a pointer can move before a press is no longer a tap
+	// or a long press, and becomes a pan.
+	//
+	// Default: 10.
+	MoveThreshold float64
+
+	// The duration a pointer must be held to trigger a long press.
+	//
+	// Default: 500ms.
+	LongPressDuration time.Duration
+
+	// The minimum distance in px of a swipe.
+	//
+	// Default: 30.
+	SwipeMinDistance float64
+
+	// The minimum velocity in px per second of a swipe.
+	//
+	// Default: 300.
+	SwipeMinVelocity float64
+
+	// The CSS touch-action property set on the elements. eg: "pan-y" lets the
+	// browser scroll vertically while horizontal pans are recognized.
+	//
+	// Default: "none" when pans or pinches are handled.
+	TouchAction string
+
+	// The function called when a tap is recognized.
+	OnTap func(Context, Gesture)
+
+	// The function called when a long press is recognized.
+	OnLongPress func(Context, Gesture)
+
+	// The function called when a swipe is recognized.
+	OnSwipe func(Context, Gesture)
+
+	// The function called when a pan starts, moves and ends.
+	OnPan func(Context, Gesture)
+
+	// The function called when a pinch starts, moves and ends.
+	OnPinch func(Context, Gesture)
+
+	pointers  map[int]gesturePointer
+	startedAt time.Time
+	press     int
+	moved     bool
+	longPress bool
+	panning   bool
+	pinching  bool
+	pinchDist float64
+}
+
+type gesturePointer struct {
+	startX, startY float64
+	x, y           float64
+	at             time.Time
+	velocityX      float64
+	velocityY      float64
+}
+
+// Attach sets the pointer event handlers that recognize gestures on the given
+// HTML element and returns it. Touch actions are disabled on the element when
+// pans or pinches are handled so the browser does not scroll or zoom the page,
+// unless TouchAction is set.
+//
+// It panics when the element is not an HTML element.
+func (g *Gestures) Attach(elem UI) UI {
+	e, ok := elem.(interface {
+		setEventHandler(k string, h EventHandler, scope ...interface{})
+		setAttr(k string, v interface{})
+	})
+	if !ok {
+		panic(errors.New("attaching gestures failed").
+			Tag("reason", "not an html element").
+			Tag("type", fmt.Sprintf("%T", elem)),
+		)
+	}
+
+	switch {
+	case g.TouchAction != "":
+		e.setAttr("style", "touch-action:"+g.TouchAction)
+
+	case g.OnPan != nil || g.OnPinch != nil:
+		e.setAttr("style", "touch-action:none")
+	}
+	e.setEventHandler("pointerdown", g.onPointerDown)
+	e.setEventHandler("pointermove", g.onPointerMove)
+	e.setEventHandler("pointerup", g.onPointerUp)
+	e.setEventHandler("pointercancel", g.onPointerCancel)
+	return elem
+}
+
+func (g *Gestures) onPointerDown(ctx Context, e Event) {
+	if target := e.Get("currentTarget"); target.Get("setPointerCapture").Truthy() {
+		target.Call("setPointerCapture", e.Get("pointerId"))
+	}
+	g.down(ctx, e, e.Get("pointerId").Int(), e.Get("clientX").Float(), e.Get("clientY").Float(), time.Now())
+}
+
+func (g *Gestures) onPointerMove(ctx Context, e Event) {
+	g.move(ctx, e, e.Get("pointerId").Int(), e.Get("clientX").Float(), e.Get("clientY").Float(), time.Now())
+}
+
+func (g *Gestures) onPointerUp(ctx Context, e Event) {
+	g.up(ctx, e, e.Get("pointerId").Int(), time.Now(), false)
+}
+
+func (g *Gestures) onPointerCancel(ctx Context, e Event) {
+	g.up(ctx, e, e.Get("pointerId").Int(), time.Now(), true)
+}
+
+func (g *Gestures) down(ctx Context, e Event, id int, x, y float64, now time.Time) {
+	if g.pointers == nil {
+		g.pointers = make(map[int]gesturePointer, 2)
+	}
+	g.pointers[id] = gesturePointer{
+		startX: x,
+		startY: y,
+		x:      x,
+		y:      y,
+		at:     now,
+	}
+
+	switch len(g.pointers) {
+	case 1:
+		g.startedAt = now
+		g.press++
+		g.moved = false
+		g.longPress = false
+		g.panning = false
+
+		if g.OnLongPress != nil {
+			press := g.press
+			ctx.After(g.longPressDuration(), func(ctx Context) {
+				if g.press != press || g.moved || len(g.pointers) != 1 {
+					return
+				}
+				g.longPress = true
+				p := g.pointers[id]
+				g.OnLongPress(ctx, Gesture{
+					Type:     LongPress,
+					Phase:    GestureEnd,
+					X:        p.x,
+					Y:        p.y,
+					Duration: time.Since(g.startedAt),
+					Event:    e,
+				})
+			})
+		}
+
+	case 2:
+		g.moved = true
+		g.endPan(ctx, e, id, now)
+		g.pinching = true
+		g.pinchDist = g.pointersDistance()
+		g.pinch(ctx, e, GestureStart, now)
+	}
+}
+
+func (g *Gestures) move(ctx Context, e Event, id int, x, y float64, now time.Time) {
+	p, ok := g.pointers[id]
+	if !ok {
+		return
+	}
+
+	if dt := now.Sub(p.at).Seconds(); dt > 0 {
+		p.velocityX = (x - p.x) / dt
+		p.velocityY = (y - p.y) / dt
+	}
+	p.x = x
+	p.y = y
+	p.at = now
+	g.pointers[id] = p
+
+	if g.pinching {
+		g.pinch(ctx, e, GestureMove, now)
+		return
+	}
+
+	if !g.moved && math.Hypot(x-p.startX, y-p.startY) > g.moveThreshold() {
+		g.moved = true
+		g.panning = true
+		g.pan(ctx, e, p, GestureStart, now)
+		return
+	}
+
+	if g.panning {
+		g.pan(ctx, e, p, GestureMove, now)
+	}
+}
+
+func (g *Gestures) up(ctx Context, e Event, id int, now time.Time, canceled bool) {
+	p, ok := g.pointers[id]
+	if !ok {
+		return
+	}
+
+	if g.pinching {
+		g.pinch(ctx, e, GestureEnd, now)
+		g.pinching = false
+		delete(g.pointers, id)
+		return
+	}
+	delete(g.pointers, id)
+
+	if len(g.pointers) != 0 {
+		return
+	}
+	g.press++
+
+	if g.panning {
+		g.panning = false
+		g.pan(ctx, e, p, GestureEnd, now)
+	}
+	if canceled || g.longPress {
+		return
+	}
+
+	gesture := Gesture{
+		Phase:    GestureEnd,
+		X:        p.x,
+		Y:        p.y,
+		DeltaX:   p.x - p.startX,
+		DeltaY:   p.y - p.startY,
+		Duration: now.Sub(g.startedAt),
+		Event:    e,
+	}
+
+	if !g.moved {
+		if g.OnTap != nil && gesture.Duration <= g.tapMaxDuration() {
+			gesture.Type = Tap
+			g.OnTap(ctx, gesture)
+		}
+		return
+	}
+
+	if g.OnSwipe == nil {
+		return
+	}
+	if s := gesture.Duration.Seconds(); s > 0 {
+		gesture.VelocityX = gesture.DeltaX / s
+		gesture.VelocityY = gesture.DeltaY / s
+	}
+	if math.Hypot(gesture.DeltaX, gesture.DeltaY) >= g.swipeMinDistance() &&
+		math.Hypot(gesture.VelocityX, gesture.VelocityY) >= g.swipeMinVelocity() {
+		gesture.Type = Swipe
+		gesture.Direction = gestureDirection(gesture.DeltaX, gesture.DeltaY)
+		g.OnSwipe(ctx, gesture)
+	}
+}
+
+func (g *Gestures) pan(ctx Context, e Event, p gesturePointer, phase GesturePhase, now time.Time) {
+	if g.OnPan == nil {
+		return
+	}
+
+	dx := p.x - p.startX
+	dy := p.y - p.startY
+	g.OnPan(ctx, Gesture{
+		Type:      Pan,
+		Phase:     phase,
+		Direction: gestureDirection(dx, dy),
+		X:         p.x,
+		Y:         p.y,
+		DeltaX:    dx,
+		DeltaY:    dy,
+		VelocityX: p.velocityX,
+		VelocityY: p.velocityY,
+		Duration:  now.Sub(g.startedAt),
+		Event:     e,
+	})
+}
+
+func (g *Gestures) endPan(ctx Context, e Event, newPointer int, now time.Time) {
+	if !g.panning {
+		return
+	}
+	g.panning = false
+
+	for id, p := range g.pointers {
+		if id != newPointer {
+			g.pan(ctx, e, p, GestureEnd, now)
+			return
+		}
+	}
+}
+
+func (g *Gestures) pinch(ctx Context, e Event, phase GesturePhase, now time.Time) {
+	if g.OnPinch == nil || len(g.pointers) < 2 {
+		return
+	}
+
+	var x, y, startX, startY float64
+	for _, p := range g.pointers {
+		x += p.x / float64(len(g.pointers))
+		y += p.y / float64(len(g.pointers))
+		startX += p.startX / float64(len(g.pointers))
+		startY += p.startY / float64(len(g.pointers))
+	}
+
+	scale := 1.0
+	if g.pinchDist > 0 {
+		scale = g.pointersDistance() / g.pinchDist
+	}
+
+	direction := "out"
+	if scale < 1 {
+		direction = "in"
+	}
+
+	g.OnPinch(ctx, Gesture{
+		Type:      Pinch,
+		Phase:     phase,
+		Direction: direction,
+		X:         x,
+		Y:         y,
+		DeltaX:    x - startX,
+		DeltaY:    y - startY,
+		Scale:     scale,
+		Duration:  now.Sub(g.startedAt),
+		Event:     e,
+	})
+}
+
+func (g *Gestures) pointersDistance() float64 {
+	var points []gesturePointer
+	for _, p := range g.pointers {
+		points = append(points, p)
+		if len(points) == 2 {
+			break
+		}
+	}
+	if len(points) != 2 {
+		return 0
+	}
+	return math.Hypot(points[0].x-points[1].x, points[0].y-points[1].y)
+}
+
+func (g *Gestures) tapMaxDuration() time.Duration {
+	if g.TapMaxDuration == 0 {
+		return time.Millisecond * 250
+	}
+	return g.TapMaxDuration
+}
+
+func (g *Gestures) moveThreshold() float64 {
+	if g.MoveThreshold == 0 {
+		return 10
+	}
+	return g.MoveThreshold
+}
+
+func (g *Gestures) longPressDuration() time.Duration {
+	if g.LongPressDuration == 0 {
+		return time.Millisecond * 500
+	}
+	return g.LongPressDuration
+}
+
+func (g *Gestures) swipeMinDistance() float64 {
+	if g.SwipeMinDistance == 0 {
+		return 30
+	}
+	return g.SwipeMinDistance
+}
+
+func (g *Gestures) swipeMinVelocity() float64 {
+	if g.SwipeMinVelocity == 0 {
+		return 300
+	}
+	return g.SwipeMinVelocity
+}
+
+func gestureDirection(dx, dy float64) string {
+	if math.Abs(dx) >= math.Abs(dy) {
+		if dx < 0 {
+			return "left"
+		}
+		return "right"
+	}
+	if dy < 0 {
+		return "up"
+	}
+	return "down"
+}
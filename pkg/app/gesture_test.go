@@ -0,0 +1,162 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGesturesAttach(t *testing.T) {
+	t.Run("handlers are set on html element", func(t *testing.T) {
+		g := Gestures{OnPan: func(Context, Gesture) {}}
+		div := g.Attach(Div()).(*htmlDiv)
+		require.Contains(t, div.events, "pointerdown")
+		require.Contains(t, div.events, "pointermove")
+		require.Contains(t, div.events, "pointerup")
+		require.Contains(t, div.events, "pointercancel")
+		require.Equal(t, "touch-action:none;", div.attrs["style"])
+	})
+
+	t.Run("touch actions are kept when not panning", func(t *testing.T) {
+		g := Gestures{OnTap: func(Context, Gesture) {}}
+		div := g.Attach(Div()).(*htmlDiv)
+		require.Empty(t, div.attrs["style"])
+	})
+
+	t.Run("touch action is set", func(t *testing.T) {
+		g := Gestures{
+			TouchAction: "pan-y",
+			OnPan:       func(Context, Gesture) {},
+		}
+		div := g.Attach(Div()).(*htmlDiv)
+		require.Equal(t, "touch-action:pan-y;", div.attrs["style"])
+	})
+
+	t.Run("attaching to non html element panics", func(t *testing.T) {
+		g := Gestures{}
+		require.Panics(t, func() { g.Attach(Text("hello")) })
+	})
+}
+
+func TestGesturesRecognize(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	div := Div()
+	e.Mount(div)
+	e.Consume()
+	ctx := makeContext(div)
+	now := time.Now()
+
+	t.Run("tap", func(t *testing.T) {
+		var gestures []Gesture
+		g := Gestures{OnTap: func(ctx Context, g Gesture) { gestures = append(gestures, g) }}
+
+		g.down(ctx, Event{}, 1, 10, 10, now)
+		g.move(ctx, Event{}, 1, 12, 11, now.Add(time.Millisecond*50))
+		g.up(ctx, Event{}, 1, now.Add(time.Millisecond*100), false)
+		require.Len(t, gestures, 1)
+		require.Equal(t, Tap, gestures[0].Type)
+		require.Equal(t, float64(12), gestures[0].X)
+
+		g.down(ctx, Event{}, 1, 10, 10, now)
+		g.up(ctx, Event{}, 1, now.Add(time.Second), false)
+		require.Len(t, gestures, 1)
+	})
+
+	t.Run("swipe", func(t *testing.T) {
+		var swipe Gesture
+		g := Gestures{OnSwipe: func(ctx Context, g Gesture) { swipe = g }}
+
+		g.down(ctx, Event{}, 1, 100, 10, now)
+		g.move(ctx, Event{}, 1, 60, 12, now.Add(time.Millisecond*50))
+		g.up(ctx, Event{}, 1, now.Add(time.Millisecond*100), false)
+		require.Equal(t, Swipe, swipe.Type)
+		require.Equal(t, "left", swipe.Direction)
+		require.Equal(t, float64(-40), swipe.DeltaX)
+		require.Equal(t, float64(-400), swipe.VelocityX)
+	})
+
+	t.Run("slow movement is not a swipe", func(t *testing.T) {
+		swiped := false
+		g := Gestures{OnSwipe: func(ctx Context, g Gesture) { swiped = true }}
+
+		g.down(ctx, Event{}, 1, 100, 10, now)
+		g.move(ctx, Event{}, 1, 60, 10, now.Add(time.Second))
+		g.up(ctx, Event{}, 1, now.Add(time.Second*2), false)
+		require.False(t, swiped)
+	})
+
+	t.Run("pan", func(t *testing.T) {
+		var phases []GesturePhase
+		var last Gesture
+		g := Gestures{OnPan: func(ctx Context, g Gesture) {
+			phases = append(phases, g.Phase)
+			last = g
+		}}
+
+		g.down(ctx, Event{}, 1, 10, 10, now)
+		g.move(ctx, Event{}, 1, 10, 15, now.Add(time.Millisecond*10))
+		require.Empty(t, phases)
+
+		g.move(ctx, Event{}, 1, 10, 40, now.Add(time.Millisecond*20))
+		g.move(ctx, Event{}, 1, 10, 50, now.Add(time.Millisecond*30))
+		g.up(ctx, Event{}, 1, now.Add(time.Millisecond*40), false)
+		require.Equal(t, []GesturePhase{GestureStart, GestureMove, GestureEnd}, phases)
+		require.Equal(t, "down", last.Direction)
+		require.Equal(t, float64(40), last.DeltaY)
+	})
+
+	t.Run("pinch", func(t *testing.T) {
+		var pinches []Gesture
+		g := Gestures{OnPinch: func(ctx Context, g Gesture) { pinches = append(pinches, g) }}
+
+		g.down(ctx, Event{}, 1, 100, 100, now)
+		g.down(ctx, Event{}, 2, 200, 100, now.Add(time.Millisecond*10))
+		g.move(ctx, Event{}, 2, 300, 100, now.Add(time.Millisecond*20))
+		g.up(ctx, Event{}, 2, now.Add(time.Millisecond*30), false)
+		g.up(ctx, Event{}, 1, now.Add(time.Millisecond*40), false)
+
+		require.Len(t, pinches, 3)
+		require.Equal(t, GestureStart, pinches[0].Phase)
+		require.Equal(t, float64(1), pinches[0].Scale)
+		require.Equal(t, GestureMove, pinches[1].Phase)
+		require.Equal(t, float64(2), pinches[1].Scale)
+		require.Equal(t, "out", pinches[1].Direction)
+		require.Equal(t, float64(200), pinches[1].X)
+		require.Equal(t, GestureEnd, pinches[2].Phase)
+	})
+
+	t.Run("long press", func(t *testing.T) {
+		pressed := make(chan Gesture, 1)
+		tapped := false
+		g := Gestures{
+			LongPressDuration: time.Millisecond,
+			OnLongPress:       func(ctx Context, g Gesture) { pressed <- g },
+			OnTap:             func(ctx Context, g Gesture) { tapped = true },
+		}
+
+		g.down(ctx, Event{}, 1, 10, 10, now)
+		e.Wait()
+		e.Consume()
+
+		select {
+		case g := <-pressed:
+			require.Equal(t, LongPress, g.Type)
+		default:
+			t.Fatal("long press not recognized")
+		}
+
+		g.up(ctx, Event{}, 1, time.Now(), false)
+		require.False(t, tapped)
+	})
+}
+
+func TestGestureDirection(t *testing.T) {
+	require.Equal(t, "left", gestureDirection(-10, 2))
+	require.Equal(t, "right", gestureDirection(10, -2))
+	require.Equal(t, "up", gestureDirection(1, -10))
+	require.Equal(t, "down", gestureDirection(-1, 10))
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"reflect"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+var (
+	globalErrorHandler func(Context, GlobalError)
+)
+
+// GlobalError describes an error that is not caught by the JavaScript code of
+// the page, or a rejected promise that is not handled.
+type GlobalError struct {
+	// The type of the error: ClientReportError for uncaught errors and
+	// ClientReportRejection for unhandled rejections.
+	Type string
+
+	// The error message, or the reason of the rejection.
+	Message string
+
+	// The URL of the script that threw the error.
+	Source string
+
+	// The position of the error in the script.
+	Line   int
+	Column int
+
+	// The stack trace of the error, when available.
+	Stack string
+
+	// The type of the component that was handling a dispatched function when
+	// the error occurred, such as "*main.hello". It is empty when the error
+	// did not originate in a dispatched function.
+	Component string
+}
+
+// OnGlobalError sets the function called on the UI goroutine when an error is
+// not caught by the JavaScript code of the page, or when a rejected promise is
+// not handled. Eg:
+//  app.OnGlobalError(func(ctx app.Context, err app.GlobalError) {
+//      ctx.SetState("/error", err.Message)
+//  })
+//
+// Errors are also logged with the component they originate from when the app
+// runs on localhost.
+func OnGlobalError(h func(Context, GlobalError)) {
+	globalErrorHandler = h
+}
+
+// startGlobalErrorCapture listens to the error and unhandledrejection events
+// of the window.
+func (e *engine) startGlobalErrorCapture() {
+	if IsServer || e.RunsInServer {
+		return
+	}
+
+	listen := func(event, typ string) {
+		Window().Call("addEventListener", event, FuncOf(func(this Value, args []Value) interface{} {
+			e.handleGlobalError(newGlobalError(typ, args[0], e.dispatchSource))
+			return nil
+		}))
+	}
+	listen("error", ClientReportError)
+	listen("unhandledrejection", ClientReportRejection)
+}
+
+// newGlobalError returns the description of the given error or
+// unhandledrejection event, attributed to the component of the given
+// dispatch source.
+func newGlobalError(typ string, event Value, source UI) GlobalError {
+	err := GlobalError{Type: typ}
+
+	switch typ {
+	case ClientReportRejection:
+		reason := event.Get("reason")
+		err.Message = eventString(reason, "message")
+		if err.Message == "" && !reason.IsUndefined() && !reason.IsNull() {
+			err.Message = reason.String()
+		}
+		err.Stack = eventString(reason, "stack")
+
+	default:
+		err.Message = eventString(event, "message")
+		err.Source = eventString(event, "filename")
+		err.Line = int(eventFloat(event, "lineno"))
+		err.Column = int(eventFloat(event, "colno"))
+		if jsErr := event.Get("error"); jsErr.Truthy() {
+			err.Stack = eventString(jsErr, "stack")
+		}
+	}
+
+	if source != nil {
+		if c := nearestCompo(source); c != nil {
+			err.Component = reflect.TypeOf(c).String()
+		}
+	}
+	return err
+}
+
+// handleGlobalError logs the given error when the app runs on localhost and
+// dispatches it to the function set with OnGlobalError.
+func (e *engine) handleGlobalError(err GlobalError) {
+	if !IsServer && isLocalHost(Window().URL()) {
+		Log(errors.New("global error").
+			Tag("type", err.Type).
+			Tag("message", err.Message).
+			Tag("source", err.Source).
+			Tag("line", err.Line).
+			Tag("column", err.Column).
+			Tag("component", err.Component))
+	}
+
+	h := globalErrorHandler
+	if h == nil || e.Body == nil {
+		return
+	}
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			h(ctx, err)
+		},
+	})
+}
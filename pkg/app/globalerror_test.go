@@ -0,0 +1,126 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGlobalError(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	utests := []struct {
+		scenario string
+		typ      string
+		event    map[string]interface{}
+		source   UI
+		expected GlobalError
+	}{
+		{
+			scenario: "uncaught error",
+			typ:      ClientReportError,
+			event: map[string]interface{}{
+				"message":  "Uncaught TypeError: x is undefined",
+				"filename": "/web/widget.js",
+				"lineno":   12,
+				"colno":    4,
+				"error": map[string]interface{}{
+					"stack": "TypeError: x is undefined\n    at widget.js:12:4",
+				},
+			},
+			expected: GlobalError{
+				Type:    ClientReportError,
+				Message: "Uncaught TypeError: x is undefined",
+				Source:  "/web/widget.js",
+				Line:    12,
+				Column:  4,
+				Stack:   "TypeError: x is undefined\n    at widget.js:12:4",
+			},
+		},
+		{
+			scenario: "unhandled rejection with an error",
+			typ:      ClientReportRejection,
+			event: map[string]interface{}{
+				"reason": map[string]interface{}{
+					"message": "fetch failed",
+					"stack":   "Error: fetch failed",
+				},
+			},
+			expected: GlobalError{
+				Type:    ClientReportRejection,
+				Message: "fetch failed",
+				Stack:   "Error: fetch failed",
+			},
+		},
+		{
+			scenario: "unhandled rejection with a string",
+			typ:      ClientReportRejection,
+			event: map[string]interface{}{
+				"reason": "timeout",
+			},
+			expected: GlobalError{
+				Type:    ClientReportRejection,
+				Message: "timeout",
+			},
+		},
+		{
+			scenario: "error attributed to a component",
+			typ:      ClientReportError,
+			event: map[string]interface{}{
+				"message": "boom",
+			},
+			source: compo.root,
+			expected: GlobalError{
+				Type:      ClientReportError,
+				Message:   "boom",
+				Component: "*app.hello",
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			err := newGlobalError(u.typ, newSimulatedEvent(u.typ, u.event), u.source)
+			require.Equal(t, u.expected, err)
+		})
+	}
+}
+
+func TestEngineDispatchSource(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo).(*engine)
+	defer d.Close()
+
+	var source UI
+	d.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: compo,
+		Function: func(Context) {
+			source = d.dispatchSource
+		},
+	})
+	d.Consume()
+	require.Equal(t, compo, source)
+	require.Nil(t, d.dispatchSource)
+}
+
+func TestOnGlobalError(t *testing.T) {
+	defer OnGlobalError(nil)
+
+	var handled GlobalError
+	OnGlobalError(func(ctx Context, err GlobalError) {
+		handled = err
+	})
+
+	d := NewClientTester(&hello{}).(*engine)
+	defer d.Close()
+
+	d.handleGlobalError(GlobalError{
+		Type:    ClientReportError,
+		Message: "boom",
+	})
+	d.Consume()
+	require.Equal(t, "boom", handled.Message)
+}
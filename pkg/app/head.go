@@ -0,0 +1,168 @@
+package app
+
+import (
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HeadProvider is the interface that describes a component that contributes
+// elements to the page head while it is mounted, such as preload links, metas
+// or alternate icons.
+//
+// Head is called when the component is mounted and updated. Contributed
+// elements are removed from the head when the component is dismounted.
+// Identical elements contributed by multiple components are added only once.
+//
+// Contributed elements are also rendered in the head of pre-rendered pages.
+// Scripts should be loaded with Context.LoadScript since scripts added to the
+// head after the page is loaded are not executed.
+type HeadProvider interface {
+	Composer
+
+	// The elements to add to the page head.
+	Head() []UI
+}
+
+type headEntry struct {
+	count int
+	html  string
+	elem  Value
+}
+
+type headManager struct {
+	browser      bool
+	contributors map[Composer][]string
+	entries      map[string]*headEntry
+	order        []string
+}
+
+func (m *headManager) set(c Composer, elems []UI) {
+	if m.contributors == nil {
+		m.contributors = make(map[Composer][]string)
+		m.entries = make(map[string]*headEntry)
+	}
+
+	keys := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		key, html := headHTML(elem)
+		keys = append(keys, key)
+
+		entry, isAdded := m.entries[key]
+		if !isAdded {
+			entry = &headEntry{html: html}
+			m.entries[key] = entry
+			m.order = append(m.order, key)
+			if m.browser {
+				entry.elem = createHeadElement(key, html)
+			}
+		}
+		entry.count++
+	}
+
+	for _, key := range m.contributors[c] {
+		m.release(key)
+	}
+
+	if len(keys) == 0 {
+		delete(m.contributors, c)
+		return
+	}
+	m.contributors[c] = keys
+}
+
+func (m *headManager) release(key string) {
+	entry, isAdded := m.entries[key]
+	if !isAdded {
+		return
+	}
+
+	entry.count--
+	if entry.count > 0 {
+		return
+	}
+
+	if entry.elem != nil && entry.elem.Truthy() {
+		entry.elem.Call("remove")
+	}
+	delete(m.entries, key)
+
+	for i, k := range m.order {
+		if k == key {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// html returns the HTML representation of the contributed elements, in the
+// order they were added.
+func (m *headManager) html() []string {
+	html := make([]string, 0, len(m.order))
+	for _, key := range m.order {
+		html = append(html, m.entries[key].html)
+	}
+	return html
+}
+
+// headHTML returns a key that identifies the given element and its HTML
+// representation, marked with the key so that pre-rendered elements can be
+// adopted by the client.
+func headHTML(n UI) (key, html string) {
+	html = strings.TrimSpace(HTMLString(n))
+
+	h := fnv.New32a()
+	writeHeadKey(h, n)
+	key = strconv.FormatUint(uint64(h.Sum32()), 36)
+
+	if i := strings.IndexAny(html, " />"); strings.HasPrefix(html, "<") && i > 0 {
+		html = html[:i] + ` data-goapp-head="` + key + `"` + html[i:]
+	}
+	return key, html
+}
+
+// writeHeadKey writes a representation of the given element that does not
+// depend on the attributes order.
+func writeHeadKey(w io.Writer, n UI) {
+	attrs := n.attributes()
+	children := n.children()
+	if len(attrs) == 0 && len(children) == 0 {
+		PrintHTML(w, n)
+		return
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w.Write(stob("<" + n.name()))
+	for _, k := range keys {
+		w.Write(stob(" " + k + "=" + attrs[k]))
+	}
+	w.Write(stob(">"))
+	for _, c := range children {
+		writeHeadKey(w, c)
+	}
+}
+
+func createHeadElement(key, html string) Value {
+	doc := Window().Get("document")
+	head := doc.Get("head")
+
+	if elem := head.Call("querySelector", `[data-goapp-head="`+key+`"]`); elem.Truthy() {
+		return elem
+	}
+
+	tmpl := doc.Call("createElement", "template")
+	tmpl.Set("innerHTML", html)
+	elem := tmpl.Get("content").Get("firstElementChild")
+	if !elem.Truthy() {
+		return nil
+	}
+	head.Call("appendChild", elem)
+	return elem
+}
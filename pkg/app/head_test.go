@@ -0,0 +1,99 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type headCompo struct {
+	Compo
+
+	Preload string
+}
+
+func (c *headCompo) Head() []UI {
+	return []UI{
+		Link().Rel("preload").Href(c.Preload),
+		Meta().Name("theme-color").Content("#000"),
+	}
+}
+
+type headRouteCompo struct {
+	Compo
+}
+
+func (c *headRouteCompo) Head() []UI {
+	return []UI{
+		Link().Rel("preload").Href("/web/hero.jpg"),
+	}
+}
+
+func init() {
+	Route("/head-test", &headRouteCompo{})
+}
+
+func TestHeadManager(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	a := &headCompo{Preload: "/web/a.jpg"}
+	b := &headCompo{Preload: "/web/b.jpg"}
+	e.Mount(Div().Body(a, b))
+	e.Consume()
+
+	t.Run("contributions are reconciled", func(t *testing.T) {
+		html := e.heads.html()
+		require.Len(t, html, 3)
+		require.Contains(t, html[0], `href="/web/a.jpg"`)
+		require.Contains(t, html[1], `name="theme-color"`)
+		require.Contains(t, html[2], `href="/web/b.jpg"`)
+		require.Equal(t, 2, e.heads.entries[e.heads.order[1]].count)
+	})
+
+	t.Run("contributions are updated", func(t *testing.T) {
+		a.Preload = "/web/c.jpg"
+		e.scheduleComponentUpdate(a)
+		e.Consume()
+
+		html := e.heads.html()
+		require.Len(t, html, 3)
+		require.Contains(t, html[0], `name="theme-color"`)
+		require.Contains(t, html[1], `href="/web/b.jpg"`)
+		require.Contains(t, html[2], `href="/web/c.jpg"`)
+	})
+
+	t.Run("contributions are removed on dismount", func(t *testing.T) {
+		e.Mount(Div())
+		e.Consume()
+		require.Empty(t, e.heads.html())
+		require.Empty(t, e.heads.contributors)
+	})
+}
+
+func TestHeadHTML(t *testing.T) {
+	key, html := headHTML(Link().Rel("preload").Href("/web/a.jpg"))
+	require.NotEmpty(t, key)
+	require.Contains(t, html, `<link data-goapp-head="`+key+`"`)
+
+	key2, _ := headHTML(Link().Rel("preload").Href("/web/a.jpg"))
+	require.Equal(t, key, key2)
+}
+
+func TestHandlerServePageHead(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/head-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Contains(t, body, `href="/web/hero.jpg"`)
+	require.Contains(t, body, `data-goapp-head=`)
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// HealthEndpoints describes the endpoints that report the server health,
+// readiness and version. They are designed to be used as Kubernetes probes.
+//
+// An endpoint is disabled when its path is empty.
+//
+// eg:
+//  app.Handler{
+//      HealthEndpoints: app.HealthEndpoints{
+//          HealthPath:  "/healthz",
+//          ReadyPath:   "/readyz",
+//          VersionPath: "/version",
+//      },
+//  },
+type HealthEndpoints struct {
+	// The path of the endpoint that reports whether the server is alive.
+	HealthPath string
+
+	// The function called to check whether the server is alive. The server is
+	// reported as healthy when the function is nil or returns no error.
+	HealthCheck func(context.Context) error
+
+	// The path of the endpoint that reports whether the server is ready to
+	// serve requests.
+	ReadyPath string
+
+	// The function called to check whether the server is ready to serve
+	// requests. The server is reported as ready when the function is nil or
+	// returns no error.
+	ReadyCheck func(context.Context) error
+
+	// The path of the endpoint that reports the app version and the hash of
+	// the app.wasm file.
+	VersionPath string
+}
+
+// AppVersion describes the version of an app served by a Handler.
+type AppVersion struct {
+	// The Handler version.
+	Version string `json:"version"`
+
+	// The SHA-256 hash of the app.wasm file. Empty when the file is not
+	// available.
+	WasmHash string `json:"wasmHash,omitempty"`
+}
+
+func (h *Handler) serveHealthEndpoint(w http.ResponseWriter, r *http.Request) bool {
+	switch path := r.URL.Path; {
+	case path == "":
+		return false
+
+	case path == h.HealthEndpoints.HealthPath:
+		h.serveProbe(w, r, h.HealthEndpoints.HealthCheck)
+		return true
+
+	case path == h.HealthEndpoints.ReadyPath:
+		h.serveProbe(w, r, h.readyCheck)
+		return true
+
+	case path == h.HealthEndpoints.VersionPath:
+		h.serveVersion(w, r)
+		return true
+
+	default:
+		return false
+	}
+}
+
+func (h *Handler) serveProbe(w http.ResponseWriter, r *http.Request, check func(context.Context) error) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if check != nil {
+		if err := check(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write(stob(err.Error()))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(stob("ok"))
+}
+
+func (h *Handler) readyCheck(ctx context.Context) error {
+	if h.IsShuttingDown() {
+		return errors.New("server is shutting down")
+	}
+	if h.HealthEndpoints.ReadyCheck != nil {
+		return h.HealthEndpoints.ReadyCheck(ctx)
+	}
+	return nil
+}
+
+func (h *Handler) serveVersion(w http.ResponseWriter, r *http.Request) {
+	h.wasmHashOnce.Do(func() {
+		h.wasmHash = h.computeWasmHash()
+	})
+
+	b, _ := json.Marshal(AppVersion{
+		Version:  h.Version,
+		WasmHash: h.wasmHash,
+	})
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (h *Handler) computeWasmHash() string {
+	hash := sha256.New()
+
+	if fileHandler, ok := h.Resources.(http.Handler); ok {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = h.Resources.AppWASM()
+		rec := httptest.NewRecorder()
+		fileHandler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			return ""
+		}
+		hash.Write(rec.Body.Bytes())
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+
+	res, err := http.Get(h.Resources.AppWASM())
+	if err != nil {
+		Log(errors.New("getting app.wasm failed").
+			Tag("url", h.Resources.AppWASM()).
+			Wrap(err))
+		return ""
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ""
+	}
+	if _, err := io.Copy(hash, res.Body); err != nil {
+		Log(errors.New("reading app.wasm failed").
+			Tag("url", h.Resources.AppWASM()).
+			Wrap(err))
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
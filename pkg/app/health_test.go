@@ -0,0 +1,87 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerHealthEndpoints(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateFile(t, filepath.Join("web", "app.wasm"), "wasm!")
+
+	isReady := false
+	h := Handler{
+		Version: "v1.0.0",
+		HealthEndpoints: HealthEndpoints{
+			HealthPath:  "/healthz",
+			ReadyPath:   "/readyz",
+			VersionPath: "/version",
+			ReadyCheck: func(context.Context) error {
+				if !isReady {
+					return errors.New("not ready")
+				}
+				return nil
+			},
+		},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("health", func(t *testing.T) {
+		w := serve("/healthz")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "ok", w.Body.String())
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		w := serve("/readyz")
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+		require.Equal(t, "not ready", w.Body.String())
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		isReady = true
+		w := serve("/readyz")
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		w := serve("/version")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var v AppVersion
+		err := json.Unmarshal(w.Body.Bytes(), &v)
+		require.NoError(t, err)
+
+		hash := sha256.Sum256([]byte("wasm!"))
+		require.Equal(t, "v1.0.0", v.Version)
+		require.Equal(t, hex.EncodeToString(hash[:]), v.WasmHash)
+	})
+}
+
+func TestHandlerHealthEndpointsDisabled(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	h := Handler{}
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
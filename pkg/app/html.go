@@ -16616,7 +16616,7 @@ func (e *htmlForm) Text(v interface{}) HTMLForm {
 }
 
 func (e *htmlForm) AcceptCharset(v string) HTMLForm {
-	e.setAttr("acceptcharset", v)
+	e.setAttr("accept-charset", v)
 	return e
 }
 
@@ -21132,6 +21132,9 @@ type HTMLIFrame interface {
 	// Lang specifies the language of the element's content.
 	Lang(v string) HTMLIFrame
 
+	// Loading specifies whether the element is loaded immediately (eager) or deferred until it is near the viewport (lazy).
+	Loading(v string) HTMLIFrame
+
 	// Name specifies the name of the element.
 	Name(v string) HTMLIFrame
 
@@ -21375,6 +21378,11 @@ func (e *htmlIFrame) Lang(v string) HTMLIFrame {
 	return e
 }
 
+func (e *htmlIFrame) Loading(v string) HTMLIFrame {
+	e.setAttr("loading", v)
+	return e
+}
+
 func (e *htmlIFrame) Name(v string) HTMLIFrame {
 	e.setAttr("name", v)
 	return e
@@ -21630,6 +21638,9 @@ type HTMLImg interface {
 	// DataSet stores custom data private to the page or application.
 	DataSet(k string, v interface{}) HTMLImg
 
+	// Decoding specifies whether the image is decoded synchronously (sync), asynchronously (async) or automatically (auto).
+	Decoding(v string) HTMLImg
+
 	// Dir specifies the text direction for the content in an element.
 	Dir(v string) HTMLImg
 
@@ -21651,6 +21662,12 @@ type HTMLImg interface {
 	// Lang specifies the language of the element's content.
 	Lang(v string) HTMLImg
 
+	// Loading specifies whether the element is loaded immediately (eager) or deferred until it is near the viewport (lazy).
+	Loading(v string) HTMLImg
+
+	// Responsive sets the srcset with the URLs of the image resized by the Handler image endpoint to the given widths. It can be called before or after Src.
+	Responsive(widths ...int) HTMLImg
+
 	// Sizes specifies the size of the linked resource.
 	Sizes(v string) HTMLImg
 
@@ -21906,6 +21923,11 @@ func (e *htmlImg) DataSet(k string, v interface{}) HTMLImg {
 	return e
 }
 
+func (e *htmlImg) Decoding(v string) HTMLImg {
+	e.setAttr("decoding", v)
+	return e
+}
+
 func (e *htmlImg) Dir(v string) HTMLImg {
 	e.setAttr("dir", v)
 	return e
@@ -21941,6 +21963,16 @@ func (e *htmlImg) Lang(v string) HTMLImg {
 	return e
 }
 
+func (e *htmlImg) Loading(v string) HTMLImg {
+	e.setAttr("loading", v)
+	return e
+}
+
+func (e *htmlImg) Responsive(widths ...int) HTMLImg {
+	e.setResponsiveSrcSet(widths)
+	return e
+}
+
 func (e *htmlImg) Sizes(v string) HTMLImg {
 	e.setAttr("sizes", v)
 	return e
@@ -22300,6 +22332,9 @@ type HTMLInput interface {
 	// AutoComplete specifies whether the element should have autocomplete enabled.
 	AutoComplete(v bool) HTMLInput
 
+	// AutoCompleteTokens specifies the autofill tokens that tell the browser which value to suggest. eg: "username", "current-password", "new-password" or "one-time-code".
+	AutoCompleteTokens(v ...string) HTMLInput
+
 	// AutoFocus specifies that the element should automatically get focus when the page loads.
 	AutoFocus(v bool) HTMLInput
 
@@ -22571,6 +22606,11 @@ func (e *htmlInput) AutoComplete(v bool) HTMLInput {
 	return e
 }
 
+func (e *htmlInput) AutoCompleteTokens(v ...string) HTMLInput {
+	e.setAttr("autocomplete", strings.Join(v, " "))
+	return e
+}
+
 func (e *htmlInput) AutoFocus(v bool) HTMLInput {
 	e.setAttr("autofocus", v)
 	return e
@@ -25112,6 +25152,9 @@ type HTMLLink interface {
 	// Aria stores accessible rich internet applications (ARIA) data.
 	Aria(k string, v interface{}) HTMLLink
 
+	// As specifies the type of content being loaded by a preload or prefetch link.
+	As(v string) HTMLLink
+
 	// Attr sets the named attribute with the given value.
 	Attr(n string, v interface{}) HTMLLink
 
@@ -25301,6 +25344,11 @@ func (e *htmlLink) Aria(k string, v interface{}) HTMLLink {
 	return e
 }
 
+func (e *htmlLink) As(v string) HTMLLink {
+	e.setAttr("as", v)
+	return e
+}
+
 func (e *htmlLink) Attr(n string, v interface{}) HTMLLink {
 	e.setAttr(n, v)
 	return e
@@ -27002,7 +27050,7 @@ func (e *htmlMeta) Draggable(v bool) HTMLMeta {
 }
 
 func (e *htmlMeta) HTTPEquiv(v string) HTMLMeta {
-	e.setAttr("httpequiv", v)
+	e.setAttr("http-equiv", v)
 	return e
 }
 
@@ -36010,6 +36058,9 @@ type HTMLSelect interface {
 	// Attr sets the named attribute with the given value.
 	Attr(n string, v interface{}) HTMLSelect
 
+	// AutoCompleteTokens specifies the autofill tokens that tell the browser which value to suggest. eg: "username", "current-password", "new-password" or "one-time-code".
+	AutoCompleteTokens(v ...string) HTMLSelect
+
 	// AutoFocus specifies that the element should automatically get focus when the page loads.
 	AutoFocus(v bool) HTMLSelect
 
@@ -36207,6 +36258,11 @@ func (e *htmlSelect) Attr(n string, v interface{}) HTMLSelect {
 	return e
 }
 
+func (e *htmlSelect) AutoCompleteTokens(v ...string) HTMLSelect {
+	e.setAttr("autocomplete", strings.Join(v, " "))
+	return e
+}
+
 func (e *htmlSelect) AutoFocus(v bool) HTMLSelect {
 	e.setAttr("autofocus", v)
 	return e
@@ -41434,6 +41490,9 @@ type HTMLTextarea interface {
 	// Attr sets the named attribute with the given value.
 	Attr(n string, v interface{}) HTMLTextarea
 
+	// AutoCompleteTokens specifies the autofill tokens that tell the browser which value to suggest. eg: "username", "current-password", "new-password" or "one-time-code".
+	AutoCompleteTokens(v ...string) HTMLTextarea
+
 	// AutoFocus specifies that the element should automatically get focus when the page loads.
 	AutoFocus(v bool) HTMLTextarea
 
@@ -41647,6 +41706,11 @@ func (e *htmlTextarea) Attr(n string, v interface{}) HTMLTextarea {
 	return e
 }
 
+func (e *htmlTextarea) AutoCompleteTokens(v ...string) HTMLTextarea {
+	e.setAttr("autocomplete", strings.Join(v, " "))
+	return e
+}
+
 func (e *htmlTextarea) AutoFocus(v bool) HTMLTextarea {
 	e.setAttr("autofocus", v)
 	return e
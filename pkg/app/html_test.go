@@ -2948,6 +2948,7 @@ func TestIFrame(t *testing.T) {
 	elem.Hidden(false)
 	elem.ID("foo")
 	elem.Lang("foo")
+	elem.Loading("foo")
 	elem.Name("foo")
 	elem.ReferrerPolicy("foo")
 	elem.Sandbox(42)
@@ -3009,6 +3010,7 @@ func TestImg(t *testing.T) {
 	elem.ContentEditable(false)
 	elem.CrossOrigin("foo")
 	elem.DataSet("foo", "bar")
+	elem.Decoding("foo")
 	elem.Dir("foo")
 	elem.Draggable(true)
 	elem.Draggable(false)
@@ -3019,6 +3021,8 @@ func TestImg(t *testing.T) {
 	elem.IsMap(true)
 	elem.IsMap(false)
 	elem.Lang("foo")
+	elem.Loading("foo")
+	elem.Responsive(320, 640)
 	elem.Sizes("foo")
 	elem.Spellcheck(true)
 	elem.Spellcheck(false)
@@ -3099,6 +3103,7 @@ func TestInput(t *testing.T) {
 	elem.Attr("foo", "bar")
 	elem.AutoComplete(true)
 	elem.AutoComplete(false)
+	elem.AutoCompleteTokens("foo bar")
 	elem.AutoFocus(true)
 	elem.AutoFocus(false)
 	elem.Checked(true)
@@ -3489,6 +3494,7 @@ func TestLink(t *testing.T) {
 	elem := Link()
 	elem.AccessKey("foo")
 	elem.Aria("foo", "bar")
+	elem.As("foo")
 	elem.Attr("foo", "bar")
 	elem.Class("foo bar")
 	elem.ContentEditable(true)
@@ -5002,6 +5008,7 @@ func TestSelect(t *testing.T) {
 	elem.AccessKey("foo")
 	elem.Aria("foo", "bar")
 	elem.Attr("foo", "bar")
+	elem.AutoCompleteTokens("foo bar")
 	elem.AutoFocus(true)
 	elem.AutoFocus(false)
 	elem.Class("foo bar")
@@ -5756,6 +5763,7 @@ func TestTextarea(t *testing.T) {
 	elem.AccessKey("foo")
 	elem.Aria("foo", "bar")
 	elem.Attr("foo", "bar")
+	elem.AutoCompleteTokens("foo bar")
 	elem.AutoFocus(true)
 	elem.AutoFocus(false)
 	elem.Class("foo bar")
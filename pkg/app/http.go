@@ -24,6 +24,7 @@ const (
 	defaultThemeColor         = "#2d2c2c"
 	defaultPreRenderCacheSize = 8000000
 	defaultPreRenderCacheTTL  = time.Hour * 24
+	defaultLoadingRetries     = 3
 )
 
 // Handler is an HTTP handler that serves an HTML page that loads a Go wasm app
@@ -46,16 +47,100 @@ type Handler struct {
 	// Paths are relative to the root directory.
 	CacheableResources []string
 
+	// The function that returns the claims of the user that emits a request.
+	// Claims are used to prerender the elements created with IfAuthorized and
+	// are passed to the app. Pages prerendered with claims are not cached.
+	//
+	// Elements created with IfAuthorized are never displayed when nil.
+	Claims func(*http.Request) Claims
+
+	// The endpoint that receives the errors and the web vitals collected in
+	// the browser. It is disabled by default.
+	ClientReports ClientReports
+
+	// Reports whether the CSS rules required to display pre-rendered pages
+	// are inlined in their head.
+	//
+	// When enabled, the rules of app.css and of the Styles served from a
+	// LocalDir that match the elements of a pre-rendered page are inlined in
+	// a style element, and the stylesheets are loaded without blocking the
+	// first paint.
+	CriticalCSS bool
+
+	// Reports whether crawlers are served pages that are fully rendered on the
+	// server, without the loading screen and the scripts that load the app.
+	// Crawlers are detected with the User-Agent header of requests, and the
+	// pages served to them are cached apart, at their path prefixed with
+	// "crawler:".
+	CrawlerRendering bool
+
+	// Reports whether pages are prerendered in the time zone of the user. The
+	// time zone is detected by the app with Intl.DateTimeFormat and is echoed
+	// in a cookie, which makes server-rendered times match the ones displayed
+	// once the app is loaded. It is returned by Context.TimeZone. Pages
+	// prerendered in a time zone other than UTC are not cached.
+	//
+	// Time zones are UTC during prerendering when false.
+	ClientTimeZone bool
+
 	// The page description.
 	Description string
 
 	// The environment variables that are passed to the progressive web app.
+	// They are rendered into the pages and read with Context.Env or Getenv,
+	// which lets a same wasm build be configured differently in each
+	// deployment.
 	//
 	// Reserved keys:
 	// - GOAPP_VERSION
 	// - GOAPP_GOAPP_STATIC_RESOURCES_URL
+	// - GOAPP_RESOURCE_FINGERPRINTS
+	// - GOAPP_REPORT_URL
+	// - GOAPP_REPORT_SAMPLE_RATE
+	// - GOAPP_REPORT_ERROR_SAMPLE_RATE
+	// - GOAPP_LOADING_RETRIES
+	// - GOAPP_LOADING_ERROR_LABEL
+	// - GOAPP_MAINTENANCE_URL
+	// - GOAPP_PROTOCOL_HANDLERS
+	// - GOAPP_LOCALES
+	// - GOAPP_TIMEZONE_COOKIE
 	Env Environment
 
+	// The files that the installed app can open from the operating system.
+	// Files are available with Context.LaunchParams once the app is launched.
+	FileHandlers []FileHandler
+
+	// Reports whether static resources located in the /web directory are
+	// fingerprinted. Fingerprinted resources have their URL suffixed by a hash
+	// of their content and are served with far-future cache headers. When
+	// Version is not set, it is derived from the fingerprints.
+	//
+	// Fingerprinting is only performed when static resources are served from a
+	// local directory.
+	FingerprintResources bool
+
+	// The font faces used by the pages. They are declared in the head of
+	// pages, and critical font faces are preloaded. Font files are cached by
+	// the service worker in order to be available offline.
+	//
+	// eg:
+	//  app.Handler{
+	//      Fonts: []app.Font{
+	//          {Family: "Inter", Src: "/web/fonts/inter-400.woff2", Weight: "400", Preload: true},
+	//          {Family: "Inter", Src: "/web/fonts/inter-700.woff2", Weight: "700"},
+	//      },
+	//  },
+	Fonts []Font
+
+	// The endpoints that report the server health, readiness and version.
+	// Endpoints are disabled by default.
+	HealthEndpoints HealthEndpoints
+
+	// The minifier applied to the HTML documents of pre-rendered pages before
+	// they are cached and served. Pages are served as they are rendered when
+	// nil, or when the minification fails. See NewHTMLMinifier.
+	HTMLMinifier HTMLMinifier
+
 	// The icon that is used for the PWA, favicon, loading and default not
 	// found component.
 	Icon Icon
@@ -64,6 +149,23 @@ type Handler struct {
 	// linking the app.
 	Image string
 
+	// The encoder used by the image endpoint to reencode resized images.
+	//
+	// The image endpoint serves the static images located in the /web
+	// directory, resized to the smallest of ImageWidths that is greater or
+	// equal to the requested width. Resized images are kept in memory.
+	//
+	// eg:
+	//  "/app-image?src=/web/photo.jpg&w=640"
+	//
+	// Default: NewImageEncoder(85), which supports jpeg and png.
+	ImageEncoder ImageEncoder
+
+	// The widths that images served by the image endpoint can be resized to.
+	//
+	// Default: 320, 480, 640, 768, 960, 1280, 1600, 1920 and 2560.
+	ImageWidths []int
+
 	// The URLs that are launched in the app tab or window.
 	//
 	// By default, URLs with a different domain are launched in another tab.
@@ -74,9 +176,48 @@ type Handler struct {
 	// The page keywords.
 	Keywords []string
 
+	// The client mode of the installed app when it is launched while running,
+	// which is set in the launch_handler of the manifest: "auto",
+	// "navigate-new", "navigate-existing" or "focus-existing". With
+	// "focus-existing", launches are routed into the running app, which is
+	// notified with LaunchAction.
+	//
+	// Default: the browser default.
+	LaunchMode string
+
 	// The text displayed while loading a page.
 	LoadingLabel string
 
+	// The text displayed when the app fails to load after LoadingRetries
+	// attempts. The loading error is displayed when empty.
+	LoadingErrorLabel string
+
+	// The number of times the page is reloaded when loading the app fails,
+	// typically because a stale wasm file is served from the browser cache.
+	// Caches are purged before each reload and reloads are delayed with an
+	// exponential backoff. A negative value disables reloads.
+	//
+	// Default: 3.
+	LoadingRetries int
+
+	// The locales of the app, such as "en" or "de". The first locale is the
+	// default one.
+	//
+	// When set, routes are also served with a locale prefix, eg: "/de/about"
+	// displays the component routed at "/about" in the "de" locale, which is
+	// returned by Context.Locale and used by Context.T. The root path redirects
+	// to the locale persisted with Context.SetLocale, or to the one that best
+	// matches the Accept-Language header. A page is generated for each locale
+	// by GenerateStaticWebsite and listed in the sitemap.
+	Locales []string
+
+	// The maintenance mode of the handler. See Maintenance.
+	Maintenance Maintenance
+
+	// The maximum size in bytes of request bodies. Requests with a larger body
+	// are rejected with a 413 status code. No limit is enforced when zero.
+	MaxRequestBodySize int64
+
 	// The name of the web application as it is usually displayed to the user.
 	Name string
 
@@ -86,13 +227,96 @@ type Handler struct {
 	// of 8MB.
 	PreRenderCache PreRenderCache
 
+	// The maximum number of pages that are pre-rendered concurrently. Requests
+	// beyond the limit wait for a pre-rendering to complete, which caps the
+	// memory used during load spikes. The buffers of the pre-rendering
+	// engines are reused from one request to another.
+	//
+	// Default: 0, which does not limit the number of concurrent
+	// pre-renderings.
+	PreRenderConcurrency int
+
+	// Reports whether pre-rendered pages are streamed. When true, the
+	// beginning of the head, with the stylesheets and scripts, is flushed
+	// before the route loaders and the PreRender dispatches run so that
+	// browsers can fetch them while the page is pre-rendered. The rest of the
+	// page is written once the dispatches are done, and the body is flushed
+	// by chunks of 16KB.
+	//
+	// Since the response status is already written, redirections requested
+	// while a page is streamed are done by the browser and experiment cookies
+	// are set by a script. Streamed pages are not minified, only their cached
+	// copy is. Pages are not streamed when Sessions are enabled, in
+	// maintenance or when printed as PDF documents.
+	StreamPreRender bool
+
+	// The renderer that converts prerendered pages to PDF documents. When
+	// set, pages are served as PDF documents by the "/app-pdf" endpoint:
+	//  - GET /app-pdf?path=/reports/42 renders the given route.
+	//  - POST /app-pdf renders the route described by the PDFRequest JSON
+	//    body, with the given states.
+	//
+	// The endpoint is disabled when nil.
+	PDFRenderer PDFRenderer
+
+	// The URL the pages rendered as PDF documents are relative to, which the
+	// PDFRenderer uses to fetch their resources. eg: "https://murlok.io".
+	//
+	// Default: the address of the server that received the request. The
+	// request host is never used, since it is provided by the client.
+	PDFBaseURL string
+
+	// The custom protocols, such as "web+myapp", that launch the installed
+	// app. A launched URL is routed to the path that it describes, eg:
+	// "web+myapp://orders/42" is routed to "/orders/42". The original URL is
+	// available with Context.LaunchParams.
+	//
+	// Protocols must be lowercase and start with "web+".
+	ProtocolHandlers []string
+
 	// The static resources that are accessible from custom paths. Files that
 	// are proxied by default are /robots.txt, /sitemap.xml and /ads.txt.
+	//
+	// Note that the robots.txt and sitemap.xml files generated from Robots and
+	// Sitemap take precedence over proxied resources.
 	ProxyResources []ProxyResource
 
+	// The function that returns the key used to rate limit a request.
+	//
+	// Default: RemoteIP.
+	RateLimitKey func(*http.Request) string
+
+	// The rate limiter used to limit the number of requests served. Requests
+	// that are not allowed are rejected with a 429 status code. Health
+	// endpoints are not rate limited.
+	//
+	// eg:
+	//  app.Handler{
+	//      RateLimiter: app.NewTokenBucketRateLimiter(10, 50),
+	//  },
+	RateLimiter RateLimiter
+
 	// Additional headers to be added in head element.
 	RawHeaders []string
 
+	// The external origins that pages connect to, such as APIs, CDNs or font
+	// providers. Preconnect and dns-prefetch link tags are emitted in the head
+	// of pages in order to set up the connections before they are used.
+	//
+	// eg:
+	//  app.Handler{
+	//      ResourceHints: []app.ResourceHint{
+	//          {Origin: "https://api.murlok.io"},
+	//          {Origin: "https://fonts.gstatic.com", CrossOrigin: "anonymous"},
+	//          {Origin: "https://analytics.murlok.io", DNSPrefetchOnly: true},
+	//      },
+	//  },
+	ResourceHints []ResourceHint
+
+	// The robots.txt file served at /robots.txt. It is generated when rules are
+	// set.
+	Robots Robots
+
 	// The paths or urls of the JavaScript files to use with the page.
 	//
 	// eg:
@@ -108,6 +332,24 @@ type Handler struct {
 	// enough space to display Name.
 	ShortName string
 
+	// The shortcuts to key tasks of the app that are displayed in the context
+	// menu of the installed app icon.
+	//
+	// eg:
+	//  app.Handler{
+	//      Shortcuts: []app.Shortcut{
+	//          {Name: "New note", URL: "/notes/new"},
+	//      },
+	//  },
+	Shortcuts []Shortcut
+
+	// The server sessions. Sessions are disabled by default.
+	Sessions Sessions
+
+	// The sitemap served at /sitemap.xml. It is generated from the paths
+	// registered with Route when BaseURL is set.
+	Sitemap Sitemap
+
 	// The resource provider that provides static resources. Static resources
 	// are always accessed from a path that starts with "/web/".
 	//
@@ -128,6 +370,15 @@ type Handler struct {
 	//  },
 	Styles []string
 
+	// The apps served for specific host names, selected with the request Host
+	// header. Keys are host names without port, such as "shop.example.com",
+	// or wildcards that match subdomains, such as "*.example.com". Requests
+	// for other hosts are served with the Handler configuration.
+	//
+	// Health endpoints, rate limiting and uploads are handled by the Handler
+	// for every tenant.
+	Tenants map[string]Tenant
+
 	// The theme color for the application. This affects how the OS displays the
 	// app (e.g., PWA title bar or Android's task switcher).
 	//
@@ -137,34 +388,82 @@ type Handler struct {
 	// The page title.
 	Title string
 
+	// The tracer used to record spans for served requests and their
+	// prerendering phases.
+	//
+	// When set, responses have a Server-Timing header that contains the
+	// traceparent of the request span in order to let a client tracer set with
+	// SetClientTracer correlate its spans with the server ones.
+	Tracer Tracer
+
+	// The endpoint that receives the files uploaded with Context.Upload. It
+	// is disabled by default.
+	Uploads Uploads
+
 	// The version number. This is used in order to update the PWA application
 	// in the browser. It must be set when deployed on a live system in order to
 	// prevent recurring updates.
 	//
-	// Default: Auto-generated in order to trigger pwa update on a local
-	// development system.
+	// The version is sent in the Go-App-Version header of the responses and
+	// is served as an AppVersion at "/app-version", where the app fetches it
+	// to detect version skews.
+	//
+	// Default: BuildVersion, or auto-generated in order to trigger pwa update
+	// on a local development system.
 	Version string
 
 	once           sync.Once
 	etag           string
+	fingerprints   map[string]string
+	images         PreRenderCache
 	pwaResources   PreRenderCache
 	proxyResources map[string]ProxyResource
+	uploads        *uploadServer
+	sessions       *sessionManager
+	tenants        map[string]*Handler
+	wasmHashOnce   sync.Once
+	wasmHash       string
+	shutdownMutex  sync.Mutex
+	isShutdown     bool
+	activeRequests int
+	drained        chan struct{}
+	onShutdown     []func()
+	maintenance    *maintenanceState
+	prerenders     *prerenderPool
+	criticalStyles *criticalStyles
 }
 
 func (h *Handler) init() {
-	h.initVersion()
+	h.initUploads()
+	h.initTenants()
 	h.initStaticResources()
+	h.initFingerprints()
+	h.initVersion()
 	h.initImage()
+	h.initCriticalCSS()
+	h.initResourceHints()
 	h.initStyles()
+	h.initFonts()
 	h.initScripts()
 	h.initCacheableResources()
 	h.initIcon()
 	h.initPWA()
+	h.initClientReports()
+	h.initMaintenance()
 	h.initPreRenderedResources()
+	h.initSitemap()
 	h.initProxyResources()
+	h.initSessions()
+	h.initPrerenderPool()
 }
 
 func (h *Handler) initVersion() {
+	if h.Version == "" {
+		h.Version = BuildVersion
+	}
+	if h.Version == "" {
+		h.Version = h.fingerprintsVersion()
+	}
 	if h.Version == "" {
 		t := time.Now().UTC().String()
 		h.Version = fmt.Sprintf(`%x`, sha1.Sum([]byte(t)))
@@ -238,6 +537,12 @@ func (h *Handler) initPWA() {
 	if h.LoadingLabel == "" {
 		h.LoadingLabel = "Loading"
 	}
+	switch {
+	case h.LoadingRetries == 0:
+		h.LoadingRetries = defaultLoadingRetries
+	case h.LoadingRetries < 0:
+		h.LoadingRetries = 0
+	}
 }
 
 func (h *Handler) initPreRenderedResources() {
@@ -265,21 +570,40 @@ func (h *Handler) initPreRenderedResources() {
 	h.pwaResources.Set(ctx, PreRenderedItem{
 		Path:        "/manifest.webmanifest",
 		ContentType: "application/manifest+json",
-		Body:        h.makeManifestJSON(),
+		Body:        h.makeManifestJSON(""),
 	})
 
+	for _, l := range h.Locales {
+		h.pwaResources.Set(ctx, PreRenderedItem{
+			Path:        manifestPath(l),
+			ContentType: "application/manifest+json",
+			Body:        h.makeManifestJSON(l),
+		})
+	}
+
 	h.pwaResources.Set(ctx, PreRenderedItem{
 		Path:        "/app.css",
 		ContentType: "text/css",
 		Body:        []byte(appCSS),
 	})
 
+	h.pwaResources.Set(ctx, PreRenderedItem{
+		Path:        mediaWorkerEndpoint,
+		ContentType: "application/javascript",
+		Body:        []byte(mediaWorkerJS),
+	})
+
 	if h.PreRenderCache == nil {
 		h.PreRenderCache = NewPreRenderLRUCache(
 			defaultPreRenderCacheSize,
 			defaultPreRenderCacheTTL,
 		)
 	}
+
+	if h.ImageEncoder == nil {
+		h.ImageEncoder = NewImageEncoder(imageDefaultQuality)
+	}
+	h.images = NewPreRenderLRUCache(imageCacheSize, imageCacheTTL)
 }
 
 func (h *Handler) makeAppJS() []byte {
@@ -291,6 +615,18 @@ func (h *Handler) makeAppJS() []byte {
 	h.Env["GOAPP_VERSION"] = h.Version
 	h.Env["GOAPP_STATIC_RESOURCES_URL"] = h.Resources.Static()
 	h.Env["GOAPP_ROOT_PREFIX"] = h.Resources.Package()
+	h.Env["GOAPP_LOADING_RETRIES"] = strconv.Itoa(h.LoadingRetries)
+	h.Env["GOAPP_LOADING_ERROR_LABEL"] = h.LoadingErrorLabel
+	if len(h.ProtocolHandlers) != 0 {
+		protocols, _ := json.Marshal(h.ProtocolHandlers)
+		h.Env["GOAPP_PROTOCOL_HANDLERS"] = string(protocols)
+	}
+	if len(h.Locales) != 0 {
+		h.Env["GOAPP_LOCALES"] = strings.Join(h.Locales, ",")
+	}
+	if h.ClientTimeZone {
+		h.Env["GOAPP_TIMEZONE_COOKIE"] = "true"
+	}
 
 	for k, v := range h.Env {
 		if err := os.Setenv(k, v); err != nil {
@@ -318,7 +654,7 @@ func (h *Handler) makeAppJS() []byte {
 			WorkerJS string
 		}{
 			Env:      btos(env),
-			Wasm:     h.Resources.AppWASM(),
+			Wasm:     h.appWASMPath(),
 			WorkerJS: h.resolvePackagePath("/app-worker.js"),
 		}); err != nil {
 		panic(errors.New("initializing app.js failed").Wrap(err))
@@ -333,7 +669,7 @@ func (h *Handler) makeAppWorkerJS() []byte {
 		h.resolvePackagePath("/manifest.webmanifest"): {},
 		h.resolvePackagePath("/wasm_exec.js"):         {},
 		h.resolvePackagePath("/"):                     {},
-		h.Resources.AppWASM():                         {},
+		h.appWASMPath():                               {},
 	}
 
 	cacheResources := func(res ...string) {
@@ -346,8 +682,12 @@ func (h *Handler) makeAppWorkerJS() []byte {
 	}
 	cacheResources(h.Icon.Default, h.Icon.Large, h.Icon.AppleTouch)
 	cacheResources(h.Styles...)
+	cacheResources(h.fontSources()...)
 	cacheResources(h.Scripts...)
 	cacheResources(h.CacheableResources...)
+	for _, l := range h.Locales {
+		cacheResources(h.resolvePackagePath(manifestPath(l)))
+	}
 
 	var b bytes.Buffer
 	if err := template.
@@ -364,7 +704,7 @@ func (h *Handler) makeAppWorkerJS() []byte {
 	return b.Bytes()
 }
 
-func (h *Handler) makeManifestJSON() []byte {
+func (h *Handler) makeManifestJSON(locale string) []byte {
 	normalize := func(s string) string {
 		if !strings.HasPrefix(s, "/") {
 			s = "/" + s
@@ -375,29 +715,58 @@ func (h *Handler) makeManifestJSON() []byte {
 		return s
 	}
 
+	var protocolHandlers, fileHandlers, shortcuts string
+	if len(h.ProtocolHandlers) != 0 {
+		handlers, _ := json.Marshal(h.makeProtocolHandlers())
+		protocolHandlers = string(handlers)
+	}
+	if len(h.FileHandlers) != 0 {
+		handlers, _ := json.Marshal(h.makeFileHandlers())
+		fileHandlers = string(handlers)
+	}
+	if len(h.Shortcuts) != 0 {
+		s, _ := json.Marshal(h.makeManifestShortcuts(locale))
+		shortcuts = string(s)
+	}
+
+	startURL := normalize(h.Resources.Package())
+	if locale != "" {
+		startURL += locale + "/"
+	}
+
 	var b bytes.Buffer
 	if err := template.
 		Must(template.New("manifest.webmanifest").Parse(manifestJSON)).
 		Execute(&b, struct {
-			ShortName       string
-			Name            string
-			Description     string
-			DefaultIcon     string
-			LargeIcon       string
-			BackgroundColor string
-			ThemeColor      string
-			Scope           string
-			StartURL        string
+			ShortName        string
+			Name             string
+			Description      string
+			Lang             string
+			DefaultIcon      string
+			LargeIcon        string
+			BackgroundColor  string
+			ThemeColor       string
+			Scope            string
+			StartURL         string
+			ProtocolHandlers string
+			FileHandlers     string
+			Shortcuts        string
+			LaunchMode       string
 		}{
-			ShortName:       h.ShortName,
-			Name:            h.Name,
-			Description:     h.Description,
-			DefaultIcon:     h.Icon.Default,
-			LargeIcon:       h.Icon.Large,
-			BackgroundColor: h.BackgroundColor,
-			ThemeColor:      h.ThemeColor,
-			Scope:           normalize(h.Resources.Package()),
-			StartURL:        normalize(h.Resources.Package()),
+			ShortName:        manifestString(translate(locale, h.Locales, h.ShortName)),
+			Name:             manifestString(translate(locale, h.Locales, h.Name)),
+			Description:      manifestString(translate(locale, h.Locales, h.Description)),
+			Lang:             locale,
+			DefaultIcon:      h.Icon.Default,
+			LargeIcon:        h.Icon.Large,
+			BackgroundColor:  h.BackgroundColor,
+			ThemeColor:       h.ThemeColor,
+			Scope:            normalize(h.Resources.Package()),
+			StartURL:         startURL,
+			ProtocolHandlers: protocolHandlers,
+			FileHandlers:     fileHandlers,
+			Shortcuts:        shortcuts,
+			LaunchMode:       h.LaunchMode,
 		}); err != nil {
 		panic(errors.New("initializing manifest.webmanifest failed").Wrap(err))
 	}
@@ -452,30 +821,102 @@ func (h *Handler) initProxyResources() {
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.once.Do(h.init)
+	h.beginRequest()
+	defer h.endRequest()
+
+	if h.serveHealthEndpoint(w, r) {
+		return
+	}
+
+	if h.limitRequest(w, r) {
+		return
+	}
+
+	if t := h.tenant(r); t != nil {
+		t.ServeHTTP(w, r)
+		return
+	}
+
+	if h.redirectLocale(w, r) {
+		return
+	}
 
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("ETag", h.etag)
 
+	personalized := h.isPersonalized(r)
+	maintenance := h.maintenance.isEnabled()
+
 	etag := r.Header.Get("If-None-Match")
-	if etag == h.etag {
+	if etag == h.etag && !personalized && !maintenance {
 		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
+	ctx, span := startSpan(h.Tracer, r.Context(), "http.request")
+	defer span.End()
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.target", r.URL.Path)
+	if traceParent := span.TraceParent(); traceParent != "" {
+		w.Header().Set("Server-Timing", `traceparent;desc="`+traceParent+`"`)
+	}
+	r = r.WithContext(ctx)
+	w.Header().Set(versionHeader, h.Version)
+
 	path := r.URL.Path
 
+	if h.uploads != nil && (path == uploadEndpoint || strings.HasPrefix(path, uploadEndpoint+"/")) {
+		if !h.verifyCSRF(w, r) {
+			return
+		}
+		h.uploads.ServeHTTP(w, r)
+		return
+	}
+
+	if path == clientReportEndpoint && h.ClientReports.OnReport != nil {
+		h.serveClientReports(w, r)
+		return
+	}
+
+	if path == maintenanceEndpoint {
+		h.serveMaintenanceEvents(w, r)
+		return
+	}
+
+	if path == launchEndpoint && len(h.ProtocolHandlers) != 0 {
+		h.serveLaunch(w, r)
+		return
+	}
+
+	if path == pdfEndpoint && h.PDFRenderer != nil {
+		h.servePDF(w, r)
+		return
+	}
+
 	fileHandler, isServingStaticResources := h.Resources.(http.Handler)
 	if isServingStaticResources && strings.HasPrefix(path, "/web/") {
+		if h.isFingerprinted(path, r.URL.Query().Get(fingerprintParam)) {
+			w.Header().Set("Cache-Control", fingerprintCacheHeader)
+			w.Header().Del("ETag")
+		}
 		fileHandler.ServeHTTP(w, r)
 		return
 	}
 
 	switch path {
+	case imageEndpoint:
+		h.serveImage(w, r)
+		return
+
+	case versionEndpoint:
+		h.serveVersion(w, r)
+		return
+
 	case "/goapp.js":
 		path = "/app.js"
 
-	case "/manifest.json":
-		path = "/manifest.webmanifest"
+	case "/manifest.json", "/manifest.webmanifest":
+		path = h.requestManifestPath(r)
 
 	case "/app.wasm", "/goapp.wasm":
 		if isServingStaticResources {
@@ -495,7 +936,8 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if res, ok := h.PreRenderCache.Get(r.Context(), path); ok {
+	if res, ok := h.PreRenderCache.Get(r.Context(), h.preRenderCacheKey(r, path)); ok && !personalized && !maintenance {
+		h.setCrawlerHeaders(w)
 		h.servePreRenderedItem(w, res)
 		return
 	}
@@ -568,11 +1010,28 @@ func (h *Handler) serveProxyResource(resource ProxyResource, w http.ResponseWrit
 }
 
 func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
-	content, ok := routes.createComponent(r.URL.Path)
+	if h.IsShuttingDown() {
+		h.serveShuttingDown(w)
+		return
+	}
+
+	ctx, span := startSpan(h.Tracer, r.Context(), "prerender")
+	defer span.End()
+
+	maintenance := h.maintenance.isEnabled()
+	printing := isPDFRequest(r)
+	crawling := h.isCrawlerRequest(r)
+	routePath := h.routePath(r.URL.Path)
+	content, ok := routes.createComponent(routePath)
+	if maintenance {
+		content, ok = h.maintenanceComponent(), true
+	}
 	if !ok {
+		span.SetAttribute("http.status_code", http.StatusNotFound)
 		http.NotFound(w, r)
 		return
 	}
+	span.SetAttribute("component", reflect.TypeOf(content).String())
 
 	url := *r.URL
 	url.Host = r.Host
@@ -586,87 +1045,314 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 	page.SetLoadingLabel(h.LoadingLabel)
 	page.SetImage(h.Image)
 	page.url = &url
+	applyRouteMeta(&page, routePath)
+
+	var session *Session
+	if h.sessions != nil {
+		s, err := h.sessions.load(w, r)
+		if err != nil {
+			span.RecordError(err)
+			Log(err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		session = s
+	}
+
+	experiments := newServerExperimentAssignments(r)
+	claims := h.requestClaims(r)
+
+	_, routeParams := routes.meta(routePath)
+	buffers, err := h.prerenders.acquire(r.Context())
+	if err != nil {
+		span.RecordError(err)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	head := h.staticPageHead(r, routePath, maintenance, printing, crawling)
+	var stream *pageStream
+	if h.StreamPreRender && h.sessions == nil && !maintenance && !printing {
+		stream = newPageStream(w)
+	}
+	if stream != nil {
+		h.setCrawlerHeaders(w)
+		stream.start(h.requestLocale(r), head.static())
+	}
 
 	disp := engine{
 		Page:                   &page,
 		RunsInServer:           true,
 		ResolveStaticResources: h.resolveStaticPath,
 		ActionHandlers:         actionHandlers,
+		Tracer:                 h.Tracer,
+		Session:                session,
+		Request:                r,
+		Env:                    h.Env,
+		Experiments:            experiments,
+		Claims:                 claims,
+		Locales:                h.Locales,
+		TimeZone:               h.requestTimeZone(r),
+		buffers:                buffers,
+		routeParams:            routeParams,
+		routePath:              routePath,
 	}
+	defer h.prerenders.release(&disp)
 	body := Body().Body(
 		Div().Body(
+			If(!maintenance && !printing && !crawling,
+				Aside().
+					ID("app-wasm-loader").
+					Class("goapp-app-info").
+					Body(
+						Img().
+							ID("app-wasm-loader-icon").
+							Class("goapp-logo goapp-spin").
+							Src(h.Icon.Default),
+						P().
+							ID("app-wasm-loader-label").
+							Class("goapp-label").
+							Text(page.loadingLabel),
+					),
+			),
+			Div().ID(preRenderContainerID).Body(content),
+		),
+		If(!maintenance && !printing && !crawling && h.Maintenance.NotifyClients,
 			Aside().
-				ID("app-wasm-loader").
-				Class("goapp-app-info").
-				Body(
-					Img().
-						ID("app-wasm-loader-icon").
-						Class("goapp-logo goapp-spin").
-						Src(h.Icon.Default),
-					P().
-						ID("app-wasm-loader-label").
-						Class("goapp-label").
-						Text(page.loadingLabel),
-				),
-			Div().ID("app-pre-render").Body(content),
+				ID(maintenanceBannerID).
+				Class("goapp-maintenance-banner").
+				Hidden(true).
+				Text(h.Maintenance.Message),
 		),
 	)
+	mountCtx, mountSpan := startSpan(h.Tracer, ctx, "prerender.mount")
+	disp.Body = body
+	disp.TraceContext = mountCtx
+	disp.init()
+	defer disp.Close()
 	if err := mount(&disp, body); err != nil {
-		panic(errors.New("mounting pre-rendering container failed").
+		err = errors.New("mounting pre-rendering container failed").
 			Tag("server-side", disp.runsInServer()).
 			Tag("body-type", reflect.TypeOf(disp.Body)).
-			Wrap(err))
+			Wrap(err)
+		mountSpan.RecordError(err)
+		mountSpan.End()
+		panic(err)
 	}
-	disp.Body = body
-	disp.init()
-	defer disp.Close()
+	disp.refreshOutlets()
+	featureFlags.publish(&disp)
+	if states := pdfStates(r); len(states) != 0 {
+		disp.states.restore(states)
+	}
+	mountSpan.End()
 
+	dispatchCtx, dispatchSpan := startSpan(h.Tracer, ctx, "prerender.dispatch")
+	disp.TraceContext = dispatchCtx
+	if !maintenance {
+		disp.loadRoute(&url, routePath, func() {})
+	}
 	disp.PreRender()
 
-	for len(disp.dispatches) != 0 {
+	for disp.pendingDispatches() != 0 {
 		disp.Consume()
 		disp.Wait()
 	}
+	dispatchSpan.End()
+
+	launch := renderPageLaunch(r)
+	personalized := h.isPersonalized(r) || experiments.isUsed() || disp.requestUsed() || maintenance || printing || launch != ""
+	var experimentsScript string
+	if stream != nil {
+		experimentsScript = experiments.script(r)
+	} else {
+		experiments.save(w, r)
+	}
+
+	var csrfToken string
+	if session != nil {
+		session.mutex.Lock()
+		personalized = personalized || session.modified || session.destroyed
+		csrfToken = session.data.CSRF
+		session.mutex.Unlock()
+
+		if err := session.Save(); err != nil {
+			span.RecordError(err)
+			Log(err)
+		}
+	}
+
+	if redirect := disp.pageRedirect; redirect != nil {
+		span.SetAttribute("http.status_code", redirect.code)
+		if stream != nil {
+			stream.redirect(redirect.url)
+			return
+		}
+		http.Redirect(w, r, redirect.url, redirect.code)
+		return
+	}
+
+	_, htmlSpan := startSpan(h.Tracer, ctx, "prerender.html")
+	defer htmlSpan.End()
+
+	metas := renderMetaTags(page.metaTags(h.resolveStaticPath))
+	links := renderRouteLinks(routeLinks(page.URL(), routePath))
+	structuredData := renderStructuredData(page.structuredData)
+	env := renderPageEnv(h.Env)
+	claimsScript := renderPageClaims(claims)
+	routeDataScript := renderPageRouteData(disp.route)
+	criticalCSS := h.criticalStyles.render(body)
+	heads := disp.heads.html()
+
+	head.info = []UI{
+		Meta().
+			Name("author").
+			Content(page.Author()),
+		Meta().
+			Name("description").
+			Content(page.Description()),
+		Meta().
+			Name("keywords").
+			Content(page.Keywords()),
+	}
+	head.metadata = []UI{
+		If(csrfToken != "",
+			Meta().
+				Name("csrf-token").
+				Content(csrfToken),
+		),
+		Range(metas).Slice(func(i int) UI {
+			return metas[i]
+		}),
+		If(env != "",
+			Raw(env),
+		),
+		If(claimsScript != "",
+			Raw(claimsScript),
+		),
+		If(routeDataScript != "",
+			Raw(routeDataScript),
+		),
+		If(launch != "",
+			Raw(launch),
+		),
+		If(experimentsScript != "",
+			Raw(experimentsScript),
+		),
+		Title().Text(page.Title()),
+		Range(links).Slice(func(i int) UI {
+			return links[i]
+		}),
+	}
+	head.criticalCSS = []UI{
+		If(criticalCSS != "",
+			Raw("<style>"+criticalCSS+"</style>"),
+		),
+	}
+	head.extras = []UI{
+		Range(structuredData).Slice(func(i int) UI {
+			return structuredData[i]
+		}),
+		Range(heads).Slice(func(i int) UI {
+			return Raw(heads[i])
+		}),
+		Range(h.RawHeaders).Slice(func(i int) UI {
+			return Raw(h.RawHeaders[i])
+		}),
+	}
+
+	if stream != nil {
+		stream.end(head.dynamic(), body)
+		if !personalized {
+			h.PreRenderCache.Set(r.Context(), PreRenderedItem{
+				Path:        h.preRenderCacheKey(r, page.URL().Path),
+				Body:        h.minifyPage(page.URL().Path, stream.page.Bytes()),
+				ContentType: "text/html",
+			})
+		}
+		return
+	}
+
+	document := Html()
+	if locale := h.requestLocale(r); locale != "" {
+		document.Lang(locale)
+	}
 
 	var b bytes.Buffer
 	b.WriteString("<!DOCTYPE html>\n")
-	PrintHTML(&b, Html().Body(
-		Head().Body(
+	PrintHTML(&b, document.Body(
+		Head().Body(head.all()...),
+		body,
+	))
+
+	item := PreRenderedItem{
+		Path:        h.preRenderCacheKey(r, page.URL().Path),
+		Body:        h.minifyPage(page.URL().Path, b.Bytes()),
+		ContentType: "text/html",
+	}
+	if !personalized {
+		h.PreRenderCache.Set(r.Context(), item)
+	}
+	if maintenance {
+		h.setMaintenanceHeaders(w)
+		w.Header().Set("Content-Length", strconv.Itoa(item.Size()))
+		w.Header().Set("Content-Type", item.ContentType)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(item.Body)
+		return
+	}
+	h.setCrawlerHeaders(w)
+	h.servePreRenderedItem(w, item)
+}
+
+// pageHead contains the elements of the head of a pre-rendered page. The
+// static elements do not depend on the pre-rendering of the page, which lets
+// them be written before it completes when pages are streamed.
+type pageHead struct {
+	// Static elements.
+	charset   []UI
+	viewport  []UI
+	resources []UI
+	assets    []UI
+
+	// Elements set once the page is pre-rendered.
+	info        []UI
+	metadata    []UI
+	criticalCSS []UI
+	extras      []UI
+}
+
+// staticPageHead returns the head of a page with its static elements set.
+func (h *Handler) staticPageHead(r *http.Request, routePath string, maintenance, printing, crawling bool) pageHead {
+	resourceHints := renderResourceHints(h.ResourceHints)
+	preloads := renderPreloadLinks(append(h.fontPreloads(), routePreloads(routePath, h.resolveStaticPath)...))
+	fontFaces := renderFontFaces(h.Fonts)
+	stylesheets := renderStylesheets(
+		h.criticalStyles,
+		append([]string{h.resolvePackagePath("/app.css")}, h.Styles...)...,
+	)
+
+	return pageHead{
+		charset: []UI{
 			Meta().Charset("UTF-8"),
 			Meta().
 				HTTPEquiv("Content-Type").
 				Content("text/html; charset=utf-8"),
-			Meta().
-				Name("author").
-				Content(page.Author()),
-			Meta().
-				Name("description").
-				Content(page.Description()),
-			Meta().
-				Name("keywords").
-				Content(page.Keywords()),
+		},
+		viewport: []UI{
 			Meta().
 				Name("theme-color").
 				Content(h.ThemeColor),
 			Meta().
 				Name("viewport").
 				Content("width=device-width, initial-scale=1, maximum-scale=1, user-scalable=0, viewport-fit=cover"),
-			Meta().
-				Property("og:url").
-				Content(page.URL().String()),
-			Meta().
-				Property("og:title").
-				Content(page.Title()),
-			Meta().
-				Property("og:description").
-				Content(page.Description()),
-			Meta().
-				Property("og:type").
-				Content("website"),
-			Meta().
-				Property("og:image").
-				Content(page.Image()),
-			Title().Text(page.Title()),
+			Range(resourceHints).Slice(func(i int) UI {
+				return resourceHints[i]
+			}),
+		},
+		resources: []UI{
+			Range(preloads).Slice(func(i int) UI {
+				return preloads[i]
+			}),
 			Link().
 				Rel("icon").
 				Type("image/png").
@@ -676,42 +1362,62 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 				Href(h.Icon.AppleTouch),
 			Link().
 				Rel("manifest").
-				Href(h.resolvePackagePath("/manifest.webmanifest")),
-			Link().
-				Type("text/css").
-				Rel("stylesheet").
-				Href(h.resolvePackagePath("/app.css")),
-			Script().
-				Defer(true).
-				Src(h.resolvePackagePath("/wasm_exec.js")),
-			Script().
-				Defer(true).
-				Src(h.resolvePackagePath("/app.js")),
-			Range(h.Styles).Slice(func(i int) UI {
-				return Link().
-					Type("text/css").
-					Rel("stylesheet").
-					Href(h.Styles[i])
+				Href(h.resolvePackagePath(manifestPath(h.requestLocale(r)))),
+			If(fontFaces != "",
+				Raw(fontFaces),
+			),
+		},
+		assets: []UI{
+			Range(stylesheets).Slice(func(i int) UI {
+				return stylesheets[i]
 			}),
+			If(maintenance,
+				Raw(h.maintenanceScript()),
+			).ElseIf(!printing && !crawling,
+				Script().
+					Defer(true).
+					Src(h.resolvePackagePath("/wasm_exec.js")),
+				Script().
+					Defer(true).
+					Src(h.resolvePackagePath("/app.js")),
+			),
 			Range(h.Scripts).Slice(func(i int) UI {
 				return Script().
 					Defer(true).
 					Src(h.Scripts[i])
 			}),
-			Range(h.RawHeaders).Slice(func(i int) UI {
-				return Raw(h.RawHeaders[i])
-			}),
-		),
-		body,
-	))
+		},
+	}
+}
 
-	item := PreRenderedItem{
-		Path:        page.URL().Path,
-		Body:        b.Bytes(),
-		ContentType: "text/html",
+// all returns the elements of the head in the order of a non-streamed page.
+func (p pageHead) all() []UI {
+	return concatUIs(
+		p.charset,
+		p.info,
+		p.viewport,
+		p.metadata,
+		p.resources,
+		p.criticalCSS,
+		p.assets,
+		p.extras,
+	)
+}
+
+func (p pageHead) static() []UI {
+	return concatUIs(p.charset, p.viewport, p.resources, p.assets)
+}
+
+func (p pageHead) dynamic() []UI {
+	return concatUIs(p.info, p.metadata, p.criticalCSS, p.extras)
+}
+
+func concatUIs(uis ...[]UI) []UI {
+	var s []UI
+	for _, u := range uis {
+		s = append(s, u...)
 	}
-	h.PreRenderCache.Set(r.Context(), item)
-	h.servePreRenderedItem(w, item)
+	return s
 }
 
 func (h *Handler) resolvePackagePath(path string) string {
@@ -741,7 +1447,12 @@ func (h *Handler) resolveStaticPath(path string) string {
 	path = strings.Trim(path, "/")
 	b.WriteByte('/')
 	b.WriteString(path)
-	return b.String()
+	return fingerprintPath(h.fingerprints, path, b.String())
+}
+
+func (h *Handler) appWASMPath() string {
+	path := h.Resources.AppWASM()
+	return fingerprintPath(h.fingerprints, strings.TrimPrefix(path, h.Resources.Static()), path)
 }
 
 // Icon describes a square image that is used in various places such as
@@ -164,6 +164,18 @@ func TestHandlerServeWasmExecJS(t *testing.T) {
 	t.Log(w.Body.String())
 }
 
+func TestHandlerServeMediaWorkerJS(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, mediaWorkerEndpoint, nil)
+	w := httptest.NewRecorder()
+
+	h := Handler{}
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/javascript", w.Header().Get("Content-Type"))
+	require.Equal(t, mediaWorkerJS, w.Body.String())
+}
+
 func TestHandlerServeAppJSWithLocalDir(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
 	w := httptest.NewRecorder()
@@ -182,6 +194,46 @@ func TestHandlerServeAppJSWithLocalDir(t *testing.T) {
 	require.Contains(t, body, `"GOAPP_STATIC_RESOURCES_URL":""`)
 	require.Contains(t, body, `"GOAPP_ROOT_PREFIX":""`)
 	require.Contains(t, body, `"GOAPP_INTERNAL_URLS":"[\"https://redirect.me\"]"`)
+	require.Contains(t, body, `"GOAPP_LOADING_RETRIES":"3"`)
+	require.Contains(t, body, `"GOAPP_LOADING_ERROR_LABEL":""`)
+}
+
+func TestHandlerServeAppJSWithLoadingRetries(t *testing.T) {
+	utests := []struct {
+		scenario string
+		retries  int
+		expected string
+	}{
+		{
+			scenario: "retries are set",
+			retries:  5,
+			expected: `"GOAPP_LOADING_RETRIES":"5"`,
+		},
+		{
+			scenario: "retries are disabled",
+			retries:  -1,
+			expected: `"GOAPP_LOADING_RETRIES":"0"`,
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+			w := httptest.NewRecorder()
+
+			h := Handler{
+				LoadingRetries:    u.retries,
+				LoadingErrorLabel: "Please reload",
+			}
+			h.ServeHTTP(w, r)
+			body := w.Body.String()
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Contains(t, body, u.expected)
+			require.Contains(t, body, `"GOAPP_LOADING_ERROR_LABEL":"Please reload"`)
+			require.Contains(t, body, `goappOnLoadFailure`)
+		})
+	}
 }
 
 func TestHandlerServeAppJSWithRemoteBucket(t *testing.T) {
@@ -264,6 +316,9 @@ func TestHandlerServeAppWorkerJSWithLocalDir(t *testing.T) {
 	require.Contains(t, body, `self.addEventListener("install", event => {`)
 	require.Contains(t, body, `self.addEventListener("activate", event => {`)
 	require.Contains(t, body, `self.addEventListener("fetch", event => {`)
+	require.Contains(t, body, `self.addEventListener("message", event => {`)
+	require.Contains(t, body, `self.addEventListener("backgroundfetchsuccess", event => {`)
+	require.Contains(t, body, `self.addEventListener("contentdelete", event => {`)
 	require.Contains(t, body, `"/web/hello.css",`)
 	require.Contains(t, body, `"/web/hello.js",`)
 	require.Contains(t, body, `"/web/hello.png",`)
@@ -701,3 +756,26 @@ func TestIsStaticResourcePath(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerServePageWithChildRoute(t *testing.T) {
+	h := Handler{}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/outlet-test/b/42/details", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	require.Contains(t, body, `id="outlet-layout"`)
+	require.Contains(t, body, `id="outlet-b"`)
+	require.Contains(t, body, `id="outlet-a"`)
+
+	t.Run("outlet mounted while pre-rendering", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/outlet-late/b/42/details", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		require.Contains(t, body, `id="outlet-late-layout"`)
+		require.Contains(t, body, `id="outlet-b"`)
+		require.Contains(t, body, `id="outlet-a"`)
+	})
+}
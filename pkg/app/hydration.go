@@ -0,0 +1,116 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	preRenderContainerID = "app-pre-render"
+
+	elementNode = 1
+	textNode    = 3
+)
+
+// hydrate mounts the given element on the given DOM node rather than creating
+// a new one. The node and its children are patched to match the element, and
+// the nodes of a different type are replaced.
+func hydrate(d Dispatcher, n UI, v Value) error {
+	n.setSelf(n)
+
+	err := n.hydrate(d, v)
+	if !isErrReplace(err) {
+		return err
+	}
+
+	if err := mount(d, n); err != nil {
+		return err
+	}
+	v.Get("parentNode").replaceChild(n, v)
+	return nil
+}
+
+func errHydrationMismatch(n UI, v Value) error {
+	return errors.New("hydrating ui element failed").
+		Tag("replace", true).
+		Tag("reason", "node does not match").
+		Tag("kind", n.Kind()).
+		Tag("name", n.name()).
+		Tag("node-type", v.Get("nodeType").Int()).
+		Tag("node-name", v.Get("nodeName").String())
+}
+
+// hydratableChildNodes returns the child nodes of the given node that are
+// associated with UI elements. The whitespaces that separate pre-rendered
+// elements and the comments are removed.
+func hydratableChildNodes(v Value) []Value {
+	childNodes := v.Get("childNodes")
+	count := childNodes.Length()
+
+	nodes := make([]Value, 0, count)
+	var ignored []Value
+
+	for i := 0; i < count; i++ {
+		n := childNodes.Index(i)
+
+		switch n.Get("nodeType").Int() {
+		case elementNode:
+			nodes = append(nodes, n)
+
+		case textNode:
+			if strings.TrimSpace(n.Get("nodeValue").String()) == "" {
+				ignored = append(ignored, n)
+				continue
+			}
+			nodes = append(nodes, n)
+
+		default:
+			ignored = append(ignored, n)
+		}
+	}
+
+	for _, n := range ignored {
+		v.removeChild(n)
+	}
+	return nodes
+}
+
+func jsAttrNames(v Value) []string {
+	attrs := v.Get("attributes")
+	names := make([]string, attrs.Length())
+	for i := range names {
+		names[i] = attrs.Index(i).Get("name").String()
+	}
+	return names
+}
+
+// hydratePreRender mounts the given element on the DOM of the pre-rendered
+// page, which then takes the place of the pre-rendering container. It reports
+// whether the page is pre-rendered.
+func (e *engine) hydratePreRender(n UI) (bool, error) {
+	if e.runsInServer() {
+		return false, nil
+	}
+
+	container := Window().GetElementByID(preRenderContainerID)
+	if !container.Truthy() {
+		return false, nil
+	}
+
+	nodes := hydratableChildNodes(container)
+	if len(nodes) == 0 {
+		return false, nil
+	}
+
+	if err := hydrate(e, n, nodes[0]); err != nil {
+		return true, err
+	}
+
+	prerender, err := e.Body.(elemWithChildren).swapChildAt(0, n)
+	if err != nil {
+		return true, err
+	}
+	dismount(prerender)
+	return true, nil
+}
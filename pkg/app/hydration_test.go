@@ -0,0 +1,303 @@
+//go:build !wasm
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testDOMNode is a DOM node emulated in Go in order to test hydration.
+type testDOMNode struct {
+	value
+
+	nodeType  int
+	tag       string
+	nodeValue string
+	attrs     map[string]string
+	props     map[string]interface{}
+	parent    *testDOMNode
+	children  []Value
+	listeners map[string]int
+}
+
+func newTestDOMElem(tag string, attrs map[string]string, children ...Value) *testDOMNode {
+	n := &testDOMNode{
+		nodeType:  elementNode,
+		tag:       tag,
+		attrs:     attrs,
+		props:     make(map[string]interface{}),
+		listeners: make(map[string]int),
+	}
+	if n.attrs == nil {
+		n.attrs = make(map[string]string)
+	}
+	for _, c := range children {
+		n.appendChild(c)
+	}
+	return n
+}
+
+func newTestDOMText(v string) *testDOMNode {
+	return &testDOMNode{
+		nodeType:  textNode,
+		nodeValue: v,
+	}
+}
+
+func newTestDOMComment(v string) *testDOMNode {
+	return &testDOMNode{
+		nodeType:  8,
+		nodeValue: v,
+	}
+}
+
+func (n *testDOMNode) Get(p string) Value {
+	switch p {
+	case "nodeType":
+		return simulatedValue{v: n.nodeType}
+
+	case "tagName", "nodeName":
+		return simulatedValue{v: strings.ToUpper(n.tag)}
+
+	case "nodeValue":
+		return simulatedValue{v: n.nodeValue}
+
+	case "parentNode":
+		return n.parent
+
+	case "childNodes":
+		nodes := make([]interface{}, len(n.children))
+		for i, c := range n.children {
+			nodes[i] = c
+		}
+		return simulatedValue{v: nodes}
+
+	case "attributes":
+		var attrs []interface{}
+		for k := range n.attrs {
+			attrs = append(attrs, map[string]interface{}{"name": k})
+		}
+		return simulatedValue{v: attrs}
+
+	default:
+		return simulatedValue{v: n.props[p]}
+	}
+}
+
+func (n *testDOMNode) Set(p string, x interface{}) {
+	n.props[p] = x
+}
+
+func (n *testDOMNode) JSValue() Value {
+	return n
+}
+
+func (n *testDOMNode) Truthy() bool {
+	return true
+}
+
+func (n *testDOMNode) getAttr(k string) string {
+	if v, ok := n.attrs[k]; ok {
+		return v
+	}
+	return "<null>"
+}
+
+func (n *testDOMNode) setAttr(k, v string) {
+	n.attrs[k] = v
+}
+
+func (n *testDOMNode) delAttr(k string) {
+	delete(n.attrs, k)
+}
+
+func (n *testDOMNode) appendChild(c Wrapper) {
+	v := c.JSValue()
+	if node, ok := v.(*testDOMNode); ok {
+		node.detach()
+	}
+	setTestDOMParent(v, n)
+	n.children = append(n.children, v)
+}
+
+func (n *testDOMNode) replaceChild(new, old Wrapper) {
+	v := new.JSValue()
+	if node, ok := v.(*testDOMNode); ok {
+		node.detach()
+	}
+	setTestDOMParent(v, n)
+
+	for i, c := range n.children {
+		if c == old.JSValue() {
+			n.children[i] = v
+			setTestDOMParent(c, nil)
+			return
+		}
+	}
+}
+
+func (n *testDOMNode) removeChild(c Wrapper) {
+	for i, child := range n.children {
+		if child == c.JSValue() {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			setTestDOMParent(child, nil)
+			return
+		}
+	}
+}
+
+func setTestDOMParent(v Value, p *testDOMNode) {
+	if node, ok := v.(*testDOMNode); ok {
+		node.parent = p
+	}
+}
+
+func (n *testDOMNode) detach() {
+	if n.parent != nil {
+		n.parent.removeChild(n)
+	}
+}
+
+func (n *testDOMNode) setNodeValue(v string) {
+	n.nodeValue = v
+}
+
+func (n *testDOMNode) addEventListener(event string, fn Func) {
+	n.listeners[event]++
+}
+
+type hydrationCompo struct {
+	Compo
+
+	text string
+}
+
+func (c *hydrationCompo) Render() UI {
+	return Div().
+		Class("card").
+		OnClick(func(Context, Event) {}).
+		Body(
+			H1().Text("title"),
+			P().Text(c.text),
+			Span().Text("new"),
+		)
+}
+
+func TestHydrate(t *testing.T) {
+	d := NewClientTester(&hello{})
+	defer d.Close()
+
+	h1Text := newTestDOMText("\ntitle\n")
+	h1 := newTestDOMElem("h1", nil, newTestDOMText("\n"), h1Text, newTestDOMText("\n"))
+	pText := newTestDOMText("\nold\n")
+	p := newTestDOMElem("p", nil, pText)
+	section := newTestDOMElem("section", nil)
+	extra := newTestDOMElem("footer", nil)
+	root := newTestDOMElem("div", map[string]string{
+		"class":          "card",
+		"data-prerender": "true",
+	},
+		newTestDOMText("\n"),
+		h1,
+		newTestDOMComment("comment"),
+		newTestDOMText("\n"),
+		p,
+		section,
+		extra,
+	)
+	container := newTestDOMElem("div", nil, root)
+
+	compo := &hydrationCompo{text: "hello"}
+	err := hydrate(d, compo, root)
+	require.NoError(t, err)
+	require.True(t, compo.Mounted())
+
+	div := compo.root
+	require.Same(t, root, div.JSValue())
+	require.Equal(t, map[string]string{"class": "card"}, root.attrs)
+	require.Equal(t, 1, root.listeners["click"])
+	require.Len(t, root.children, 3)
+
+	require.Same(t, h1, div.children()[0].JSValue())
+	require.Len(t, h1.children, 1)
+	require.Same(t, h1Text, div.children()[0].children()[0].JSValue())
+	require.Equal(t, "title", h1Text.nodeValue)
+
+	require.Same(t, p, div.children()[1].JSValue())
+	require.Equal(t, "hello", pText.nodeValue)
+
+	require.NotSame(t, section, root.children[2])
+	require.Nil(t, section.parent)
+	require.Nil(t, extra.parent)
+
+	require.Same(t, container, root.parent)
+	require.Equal(t, div, compo.root.children()[0].parent())
+}
+
+func TestHydrateMismatchingRoot(t *testing.T) {
+	d := NewClientTester(&hello{})
+	defer d.Close()
+
+	root := newTestDOMElem("section", nil)
+	container := newTestDOMElem("div", nil, root)
+
+	div := Div().Text("hello")
+	err := hydrate(d, div, root)
+	require.NoError(t, err)
+	require.True(t, div.Mounted())
+	require.NotSame(t, root, div.JSValue())
+	require.Nil(t, root.parent)
+	require.Len(t, container.children, 1)
+}
+
+func TestHydrateText(t *testing.T) {
+	d := NewClientTester(&hello{})
+	defer d.Close()
+
+	node := newTestDOMText("hello")
+	text := Text("hello")
+	require.NoError(t, hydrate(d, text, node))
+	require.Same(t, node, text.JSValue())
+
+	err := text.hydrate(d, node)
+	require.Error(t, err)
+	require.False(t, isErrReplace(err))
+}
+
+func TestHydrateRaw(t *testing.T) {
+	d := NewClientTester(&hello{})
+	defer d.Close()
+
+	node := newTestDOMElem("svg", nil)
+	raw := Raw("<svg></svg>")
+	require.NoError(t, hydrate(d, raw, node))
+	require.Same(t, node, raw.JSValue())
+}
+
+func TestHydrateOutlet(t *testing.T) {
+	d := NewClientTester(&hello{})
+	defer d.Close()
+	d.(*engine).routePath = "/outlet-test/b/42/details"
+
+	a := newTestDOMElem("div", map[string]string{"id": "outlet-a"})
+	b := newTestDOMElem("div", map[string]string{"id": "outlet-b"}, a)
+	root := newTestDOMElem("div", map[string]string{"id": "outlet-layout"}, b)
+	newTestDOMElem("div", nil, root)
+
+	layout := &outletLayout{}
+	require.NoError(t, hydrate(d, layout, root))
+	require.Same(t, root, layout.root.JSValue())
+
+	o := layout.root.children()[0].(*outlet)
+	require.IsType(t, &outletChildB{}, o.child)
+	require.Same(t, b, o.child.JSValue())
+	require.Same(t, root, b.parent)
+
+	nested := o.child.(*outletChildB).root.children()[0].(*outlet)
+	require.IsType(t, &outletChildA{}, nested.child)
+	require.Same(t, a, nested.child.JSValue())
+	require.Same(t, b, a.parent)
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"time"
+)
+
+const (
+	idleFallbackDelay    = time.Millisecond
+	idleFallbackDuration = time.Millisecond * 50
+)
+
+// requestIdle calls the given function when the browser is idle, with the
+// time until which the idle period is estimated to last. It uses
+// requestIdleCallback when available and falls back to a timer otherwise.
+func requestIdle(fn func(deadline time.Time)) {
+	if !Window().Get("requestIdleCallback").Truthy() {
+		time.AfterFunc(idleFallbackDelay, func() {
+			fn(time.Now().Add(idleFallbackDuration))
+		})
+		return
+	}
+
+	var callback Func
+	callback = FuncOf(func(this Value, args []Value) interface{} {
+		defer callback.Release()
+
+		remaining := time.Duration(args[0].Call("timeRemaining").Float() * float64(time.Millisecond))
+		fn(time.Now().Add(remaining))
+		return nil
+	})
+	Window().Call("requestIdleCallback", callback)
+}
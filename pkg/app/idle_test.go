@@ -0,0 +1,44 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWhenIdle(t *testing.T) {
+	t.Run("function is called when idle", func(t *testing.T) {
+		foo := &foo{}
+		client := NewClientTester(foo)
+		defer client.Close()
+
+		called := make(chan time.Time, 1)
+		ctx := makeContext(foo)
+		ctx.WhenIdle(func(ctx Context, deadline time.Time) {
+			called <- deadline
+		})
+
+		require.Eventually(t, func() bool {
+			client.Consume()
+			return len(called) == 1
+		}, time.Second, time.Millisecond)
+		require.True(t, (<-called).After(time.Now()))
+	})
+
+	t.Run("function is not called when pre-rendering", func(t *testing.T) {
+		foo := &foo{}
+		server := NewServerTester(foo)
+		defer server.Close()
+
+		called := false
+		ctx := makeContext(foo)
+		ctx.WhenIdle(func(ctx Context, deadline time.Time) {
+			called = true
+		})
+
+		time.Sleep(idleFallbackDelay * 5)
+		server.Consume()
+		require.False(t, called)
+	})
+}
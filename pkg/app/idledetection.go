@@ -0,0 +1,218 @@
+package app
+
+import (
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// IdleState is the state where the idle status of the user is stored, as
+	// an IdleStatus. See IdleDetection.Observe.
+	IdleState = "/app/idle"
+
+	// The minimum threshold accepted by the Idle Detection API.
+	minIdleThreshold = time.Minute
+)
+
+var (
+	idles idleTracker
+)
+
+// IdleStatus describes whether the user is idle and whether the screen is
+// locked.
+type IdleStatus struct {
+	// The idle detection permission: "granted", "denied" or "prompt". The
+	// user idle state and the screen state are only detected when it is
+	// "granted".
+	Permission string
+
+	// Reports whether the user did not interact with the device for longer
+	// than the observed threshold.
+	UserIdle bool
+
+	// Reports whether the screen is locked.
+	ScreenLocked bool
+}
+
+// IdleDetection detects when the user is idle and when the screen is locked
+// with the Idle Detection API. It is useful for presence indicators and for
+// locking sensitive screens.
+//
+// The detection requires the user permission, that is requested with
+// RequestPermission. Operations do nothing on the server.
+type IdleDetection struct {
+	ctx Context
+}
+
+// IsSupported reports whether the browser can detect idle users.
+func (i IdleDetection) IsSupported() bool {
+	return !IsServer && Window().Get("IdleDetector").Truthy()
+}
+
+// RequestPermission asks the user for the permission to detect idle states.
+// It must be called from a user gesture, like a click handler. The given
+// function is called on the UI goroutine with the permission: "granted" or
+// "denied".
+//
+// Idle states are detected once the permission is granted when they are
+// observed.
+func (i IdleDetection) RequestPermission(onDone func(Context, string, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(permission string, err error) {
+		if err != nil {
+			err = errors.New("requesting idle detection permission failed").Wrap(err)
+		}
+		dispatchResult(i.ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, permission, err)
+		})
+	}
+
+	detector := Window().Get("IdleDetector")
+	if !detector.Truthy() {
+		done("denied", errors.New("idle detection is not supported"))
+		return
+	}
+
+	d := i.ctx.Dispatcher()
+	awaitPromise(detector.Call("requestPermission"), func(permission Value) {
+		idles.setPermission(d, permission.String())
+		done(permission.String(), nil)
+	}, func(reason Value) {
+		done("denied", jsReasonError(reason))
+	})
+}
+
+// Observe observes the idle status of the user, stored as an IdleStatus in
+// IdleState. The user is idle when they did not interact with the device for
+// longer than the given threshold, which can't be less than a minute. The
+// smallest threshold requested by observers is used. Eg:
+//  func (s *vault) OnMount(ctx app.Context) {
+//      ctx.Idle().Observe(5 * time.Minute).
+//          OnChange(func() {
+//              if s.idle.UserIdle || s.idle.ScreenLocked {
+//                  s.lock(ctx)
+//              }
+//          }).
+//          Value(&s.idle)
+//  }
+func (i IdleDetection) Observe(threshold time.Duration) Observer {
+	idles.start(i.ctx.Dispatcher(), threshold)
+	return i.ctx.ObserveState(IdleState)
+}
+
+// idleTracker stores the idle status of the user in IdleState. A single idle
+// detector is shared by the observers.
+type idleTracker struct {
+	started    bool
+	threshold  time.Duration
+	status     IdleStatus
+	controller Value
+}
+
+func (t *idleTracker) start(d Dispatcher, threshold time.Duration) {
+	if IsServer || !Window().Get("IdleDetector").Truthy() {
+		return
+	}
+
+	if threshold < minIdleThreshold {
+		threshold = minIdleThreshold
+	}
+	if t.started {
+		if threshold < t.threshold {
+			t.threshold = threshold
+			t.detect(d)
+		}
+		return
+	}
+	t.started = true
+	t.threshold = threshold
+
+	permissions := Window().Get("navigator").Get("permissions")
+	if !permissions.Truthy() {
+		t.setPermission(d, "prompt")
+		return
+	}
+
+	awaitPromise(permissions.Call("query", map[string]interface{}{
+		"name": "idle-detection",
+	}), func(status Value) {
+		status.Call("addEventListener", "change", FuncOf(func(this Value, args []Value) interface{} {
+			t.setPermission(d, status.Get("state").String())
+			return nil
+		}))
+		t.setPermission(d, status.Get("state").String())
+	}, func(reason Value) {
+		t.setPermission(d, "prompt")
+	})
+}
+
+// setPermission stores the given permission and starts or stops the detection
+// accordingly.
+func (t *idleTracker) setPermission(d Dispatcher, permission string) {
+	if permission == t.status.Permission {
+		return
+	}
+	t.status.Permission = permission
+
+	if permission == "granted" && t.started {
+		t.detect(d)
+	} else if permission != "granted" {
+		t.stop()
+		t.status.UserIdle = false
+		t.status.ScreenLocked = false
+	}
+	t.store(d)
+}
+
+// detect starts a new idle detector with the current threshold.
+func (t *idleTracker) detect(d Dispatcher) {
+	if t.status.Permission != "granted" {
+		return
+	}
+	t.stop()
+
+	controller := Window().Get("AbortController").New()
+	t.controller = controller
+
+	detector := Window().Get("IdleDetector").New()
+	detector.Call("addEventListener", "change", FuncOf(func(this Value, args []Value) interface{} {
+		t.status.UserIdle = detector.Get("userState").String() == "idle"
+		t.status.ScreenLocked = detector.Get("screenState").String() == "locked"
+		t.store(d)
+		return nil
+	}))
+
+	awaitPromise(detector.Call("start", map[string]interface{}{
+		"threshold": t.threshold.Milliseconds(),
+		"signal":    controller.Get("signal"),
+	}), func(Value) {}, func(reason Value) {
+		if jsOptionalString(reason.Get("name")) == "AbortError" {
+			return
+		}
+		Log(errors.New("starting idle detection failed").
+			Tag("threshold", t.threshold).
+			Wrap(jsReasonError(reason)))
+	})
+}
+
+func (t *idleTracker) stop() {
+	if t.controller != nil {
+		t.controller.Call("abort")
+		t.controller = nil
+	}
+}
+
+func (t *idleTracker) store(d Dispatcher) {
+	status := t.status
+
+	d.Dispatch(Dispatch{
+		Mode: Update,
+		Function: func(ctx Context) {
+			ctx.SetState(IdleState, status)
+		},
+	})
+}
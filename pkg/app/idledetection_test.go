@@ -0,0 +1,40 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleDetection(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	idle := makeContext(h).Idle()
+	require.False(t, idle.IsSupported())
+
+	called := false
+	idle.RequestPermission(func(Context, string, error) {
+		called = true
+	})
+
+	var status IdleStatus
+	idle.Observe(time.Second).Value(&status)
+	d.Consume()
+	require.False(t, called)
+	require.Zero(t, status)
+
+	d.SetState(IdleState, IdleStatus{
+		Permission:   "granted",
+		UserIdle:     true,
+		ScreenLocked: true,
+	})
+	d.Consume()
+	require.Equal(t, IdleStatus{
+		Permission:   "granted",
+		UserIdle:     true,
+		ScreenLocked: true,
+	}, status)
+}
@@ -0,0 +1,359 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	imageEndpoint       = "/app-image"
+	imageCacheSize      = 64000000
+	imageCacheTTL       = time.Hour * 24
+	imageDefaultQuality = 85
+	imageMaxSourceSize  = 32 << 20
+	imageMaxPixels      = 24000000
+	imageSourceParam    = "src"
+	imageWidthParam     = "w"
+	imageFormatParam    = "f"
+	imageThumbnailWidth = 32
+)
+
+var (
+	defaultImageWidths = []int{320, 480, 640, 768, 960, 1280, 1600, 1920, 2560}
+)
+
+// ImageEncoder is the interface that describes an encoder used by the Handler
+// image endpoint to reencode resized images.
+//
+// The default encoder supports the "jpeg" and "png" formats. Support for
+// formats such as "webp" or "avif" is provided by setting a custom encoder.
+// Decoding of additional source formats is enabled by registering their
+// decoder with image.RegisterFormat.
+type ImageEncoder interface {
+	// Returns the supported formats, by order of preference. Formats are
+	// image MIME subtypes, eg: "avif", "webp", "jpeg".
+	Formats() []string
+
+	// Encodes the given image with the given format.
+	Encode(w io.Writer, img image.Image, format string) error
+}
+
+// NewImageEncoder creates an image encoder that supports the "jpeg" and "png"
+// formats. JPEG images are encoded with the given quality, ranging from 1 to
+// 100.
+func NewImageEncoder(jpegQuality int) ImageEncoder {
+	if jpegQuality <= 0 || jpegQuality > 100 {
+		jpegQuality = imageDefaultQuality
+	}
+	return stdImageEncoder{quality: jpegQuality}
+}
+
+type stdImageEncoder struct {
+	quality int
+}
+
+func (e stdImageEncoder) Formats() []string {
+	return []string{"jpeg", "png"}
+}
+
+func (e stdImageEncoder) Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: e.quality})
+
+	case "png":
+		return png.Encode(w, img)
+
+	default:
+		return errors.New("unsupported image format").Tag("format", format)
+	}
+}
+
+// ImageSrcSet returns a srcset attribute value with the URLs of the given
+// static image resized by the Handler image endpoint to the given widths.
+func ImageSrcSet(src string, widths ...int) string {
+	var b strings.Builder
+	for i, w := range widths {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(ImageURL(src, w))
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(w))
+		b.WriteByte('w')
+	}
+	return b.String()
+}
+
+// ImageURL returns the URL of the given static image resized by the Handler
+// image endpoint to the given width.
+func ImageURL(src string, width int) string {
+	return imageEndpoint + "?" + url.Values{
+		imageSourceParam: {src},
+		imageWidthParam:  {strconv.Itoa(width)},
+	}.Encode()
+}
+
+func (h *Handler) serveImage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	src := path.Clean("/" + query.Get(imageSourceParam))
+	if !strings.HasPrefix(src, "/web/") {
+		http.Error(w, "image source must be a static resource", http.StatusBadRequest)
+		return
+	}
+
+	width, err := strconv.Atoi(query.Get(imageWidthParam))
+	if err != nil || width <= 0 {
+		http.Error(w, "invalid image width", http.StatusBadRequest)
+		return
+	}
+	width = h.imageWidth(width)
+
+	format := h.imageFormat(src, query.Get(imageFormatParam), r.Header.Get("Accept"))
+	if format == "" {
+		http.Error(w, "unsupported image format", http.StatusNotAcceptable)
+		return
+	}
+
+	key := src + "|" + strconv.Itoa(width) + "|" + format
+	if item, ok := h.images.Get(r.Context(), key); ok {
+		w.Header().Set("Vary", "Accept")
+		h.servePreRenderedItem(w, item)
+		return
+	}
+
+	_, span := startSpan(h.Tracer, r.Context(), "image.process")
+	defer span.End()
+	span.SetAttribute("image.src", src)
+	span.SetAttribute("image.width", width)
+	span.SetAttribute("image.format", format)
+
+	body, err := h.processImage(src, width, format)
+	if err != nil {
+		span.RecordError(err)
+		Log(errors.New("processing image failed").
+			Tag("src", src).
+			Tag("width", width).
+			Tag("format", format).
+			Wrap(err))
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	item := PreRenderedItem{
+		Path:        key,
+		ContentType: "image/" + format,
+		Body:        body,
+	}
+	h.images.Set(context.Background(), item)
+
+	w.Header().Set("Vary", "Accept")
+	h.servePreRenderedItem(w, item)
+}
+
+// ImageThumbnailURL returns the URL of a tiny version of the given static
+// image, suited to be used as a blurred placeholder while the image loads.
+func ImageThumbnailURL(src string) string {
+	return ImageURL(src, imageThumbnailWidth)
+}
+
+// imageWidth returns the smallest allowed width that is greater or equal to
+// the requested one. Thumbnail widths are always allowed.
+func (h *Handler) imageWidth(width int) int {
+	if width <= imageThumbnailWidth {
+		return imageThumbnailWidth
+	}
+
+	widths := h.ImageWidths
+	if len(widths) == 0 {
+		widths = defaultImageWidths
+	}
+
+	max := 0
+	best := 0
+	for _, w := range widths {
+		if w > max {
+			max = w
+		}
+		if w >= width && (best == 0 || w < best) {
+			best = w
+		}
+	}
+	if best == 0 {
+		return max
+	}
+	return best
+}
+
+// imageFormat returns the format used to encode an image. The requested
+// format is used when supported. Otherwise, the first encoder format that is
+// explicitly accepted by the client is used, falling back to the format that
+// matches the source image.
+func (h *Handler) imageFormat(src, requested, accept string) string {
+	formats := h.ImageEncoder.Formats()
+	if len(formats) == 0 {
+		return ""
+	}
+
+	isSupported := func(format string) bool {
+		for _, f := range formats {
+			if f == format {
+				return true
+			}
+		}
+		return false
+	}
+
+	if requested != "" {
+		if isSupported(requested) {
+			return requested
+		}
+		return ""
+	}
+
+	for _, f := range formats {
+		if strings.Contains(accept, "image/"+f) {
+			return f
+		}
+	}
+
+	fallback := "jpeg"
+	switch strings.ToLower(path.Ext(src)) {
+	case ".png", ".gif":
+		fallback = "png"
+	}
+	if isSupported(fallback) {
+		return fallback
+	}
+	return formats[0]
+}
+
+func (h *Handler) processImage(src string, width int, format string) ([]byte, error) {
+	b, err := h.getStaticResource(src)
+	if err != nil {
+		return nil, errors.New("getting image source failed").Wrap(err)
+	}
+
+	// Decoding and resizing allocate memory proportionally to the number of
+	// pixels, which the source size does not limit since images are
+	// compressed.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.New("decoding image config failed").Wrap(err)
+	}
+	if pixels := int64(cfg.Width) * int64(cfg.Height); pixels > imageMaxPixels {
+		return nil, errors.New("image is too large").
+			Tag("width", cfg.Width).
+			Tag("height", cfg.Height).
+			Tag("max-pixels", imageMaxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.New("decoding image failed").Wrap(err)
+	}
+
+	var buf bytes.Buffer
+	if err := h.ImageEncoder.Encode(&buf, resizeImage(img, width), format); err != nil {
+		return nil, errors.New("encoding image failed").Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (h *Handler) getStaticResource(path string) ([]byte, error) {
+	if fileHandler, ok := h.Resources.(http.Handler); ok {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = path
+		rec := httptest.NewRecorder()
+		fileHandler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			return nil, errors.New("static resource not found").
+				Tag("path", path).
+				Tag("status", rec.Code)
+		}
+		return rec.Body.Bytes(), nil
+	}
+
+	u := h.Resources.Static() + path
+	res, err := http.Get(u)
+	if err != nil {
+		return nil, errors.New("getting static resource failed").
+			Tag("url", u).
+			Wrap(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("static resource not found").
+			Tag("url", u).
+			Tag("status", res.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(res.Body, imageMaxSourceSize))
+}
+
+// resizeImage scales down the given image to the given width by averaging
+// source pixels. Images that are narrower than the given width are returned
+// unchanged.
+func resizeImage(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if width >= srcW || srcW == 0 || srcH == 0 {
+		return src
+	}
+
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(rgba.Pix[i])
+					g += uint32(rgba.Pix[i+1])
+					b += uint32(rgba.Pix[i+2])
+					a += uint32(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
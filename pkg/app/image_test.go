@@ -0,0 +1,204 @@
+//go:build !wasm
+
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testImageHeader returns the header of a PNG image with the given size,
+// without any pixel data.
+func testImageHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	copy(ihdr[12:], []byte{8, 6, 0, 0, 0})
+
+	b := make([]byte, 0, 33)
+	b = append(b, "\x89PNG\r\n\x1a\n"...)
+	b = append(b, 0, 0, 0, byte(len(ihdr)-4))
+	b = append(b, ihdr...)
+
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(ihdr))
+	return append(b, crc...)
+}
+
+func testImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 42, A: 255})
+		}
+	}
+
+	var b bytes.Buffer
+	err := png.Encode(&b, img)
+	require.NoError(t, err)
+	return b.Bytes()
+}
+
+type testImageEncoder struct {
+	calls int
+}
+
+func (e *testImageEncoder) Formats() []string {
+	return []string{"webp", "jpeg", "png"}
+}
+
+func (e *testImageEncoder) Encode(w io.Writer, img image.Image, format string) error {
+	e.calls++
+	if format == "webp" {
+		_, err := w.Write([]byte("webp!"))
+		return err
+	}
+	return NewImageEncoder(0).Encode(w, img, format)
+}
+
+func TestHandlerServeImage(t *testing.T) {
+	close := testCreateDir(t, "web")
+	defer close()
+	testCreateFile(t, filepath.Join("web", "photo.png"), string(testImage(t, 1000, 500)))
+	testCreateFile(t, filepath.Join("web", "huge.png"), string(testImageHeader(50000, 50000)))
+
+	encoder := &testImageEncoder{}
+	h := Handler{
+		ImageEncoder: encoder,
+		ImageWidths:  []int{320, 640},
+	}
+
+	serve := func(url, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("image is resized to the next allowed width", func(t *testing.T) {
+		w := serve("/app-image?src=/web/photo.png&w=500", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		require.Equal(t, "Accept", w.Header().Get("Vary"))
+
+		img, err := png.Decode(w.Body)
+		require.NoError(t, err)
+		require.Equal(t, 640, img.Bounds().Dx())
+		require.Equal(t, 320, img.Bounds().Dy())
+	})
+
+	t.Run("thumbnail width is always allowed", func(t *testing.T) {
+		w := serve(ImageThumbnailURL("/web/photo.png"), "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		img, err := png.Decode(w.Body)
+		require.NoError(t, err)
+		require.Equal(t, imageThumbnailWidth, img.Bounds().Dx())
+	})
+
+	t.Run("resized image is cached", func(t *testing.T) {
+		calls := encoder.calls
+		w := serve("/app-image?src=/web/photo.png&w=640", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, calls, encoder.calls)
+	})
+
+	t.Run("accepted format is negotiated", func(t *testing.T) {
+		w := serve("/app-image?src=/web/photo.png&w=320", "image/avif,image/webp,*/*")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "image/webp", w.Header().Get("Content-Type"))
+		require.Equal(t, "webp!", w.Body.String())
+	})
+
+	t.Run("requested format is used", func(t *testing.T) {
+		w := serve("/app-image?src=/web/photo.png&w=320&f=jpeg", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+
+		img, err := jpeg.Decode(w.Body)
+		require.NoError(t, err)
+		require.Equal(t, 320, img.Bounds().Dx())
+	})
+
+	t.Run("unsupported format is not acceptable", func(t *testing.T) {
+		w := serve("/app-image?src=/web/photo.png&w=320&f=avif", "")
+		require.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+
+	t.Run("source outside the web directory is rejected", func(t *testing.T) {
+		w := serve("/app-image?src=/web/../go.mod&w=320", "")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid width is rejected", func(t *testing.T) {
+		w := serve("/app-image?src=/web/photo.png&w=foo", "")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("image with too many pixels is rejected", func(t *testing.T) {
+		calls := encoder.calls
+		w := serve("/app-image?src=/web/huge.png&w=320", "")
+		require.Equal(t, http.StatusBadGateway, w.Code)
+		require.Equal(t, calls, encoder.calls)
+	})
+
+	t.Run("missing source is reported", func(t *testing.T) {
+		w := serve("/app-image?src=/web/missing.png&w=320", "")
+		require.Equal(t, http.StatusBadGateway, w.Code)
+	})
+}
+
+func TestResizeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x += 2 {
+		src.Set(x, 0, color.RGBA{R: 255, A: 255})
+		src.Set(x, 1, color.RGBA{R: 255, A: 255})
+		src.Set(x+1, 0, color.RGBA{B: 255, A: 255})
+		src.Set(x+1, 1, color.RGBA{B: 255, A: 255})
+	}
+
+	t.Run("image is scaled down", func(t *testing.T) {
+		img := resizeImage(src, 2)
+		require.Equal(t, image.Rect(0, 0, 2, 1), img.Bounds())
+		require.Equal(t, color.RGBA{R: 127, B: 127, A: 255}, img.At(0, 0))
+	})
+
+	t.Run("narrower image is not scaled up", func(t *testing.T) {
+		img := resizeImage(src, 8)
+		require.Equal(t, src, img)
+	})
+}
+
+func TestImgResponsive(t *testing.T) {
+	srcset := "/app-image?src=%2Fweb%2Fphoto.jpg&w=320 320w, /app-image?src=%2Fweb%2Fphoto.jpg&w=640 640w"
+
+	t.Run("responsive after src", func(t *testing.T) {
+		img := Img().
+			Src("/web/photo.jpg").
+			Responsive(320, 640)
+		require.Equal(t, srcset, img.(*htmlImg).attrs["srcset"])
+	})
+
+	t.Run("responsive before src", func(t *testing.T) {
+		img := Img().
+			Responsive(320, 640).
+			Src("/web/photo.jpg")
+		require.Equal(t, srcset, img.(*htmlImg).attrs["srcset"])
+	})
+}
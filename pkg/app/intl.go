@@ -0,0 +1,66 @@
+package app
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+var (
+	intlFormatsMutex sync.Mutex
+	intlFormats      = make(map[string]Value)
+)
+
+// formatIntl formats the given values with the Intl formatter created by the
+// given constructor, such as "NumberFormat", with the given locale and
+// options. Formatters are cached. It returns false when Intl is not available,
+// such as on the server, or when the options are not supported.
+func formatIntl(constructor, locale string, opts map[string]interface{}, args ...interface{}) (s string, ok bool) {
+	if IsServer {
+		return "", false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			Log(errors.New("formatting with intl failed").
+				Tag("constructor", constructor).
+				Tag("locale", locale).
+				Tag("options", opts).
+				Tag("error", r))
+			s, ok = "", false
+		}
+	}()
+
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var key strings.Builder
+	key.WriteString(constructor + "|" + locale)
+	for _, k := range keys {
+		key.WriteString("|" + k + "=" + toString(opts[k]))
+	}
+
+	intlFormatsMutex.Lock()
+	defer intlFormatsMutex.Unlock()
+
+	format, ok := intlFormats[key.String()]
+	if !ok {
+		intl := Window().Get("Intl")
+		if !intl.Truthy() || !intl.Get(constructor).Truthy() {
+			return "", false
+		}
+
+		locales := []interface{}{}
+		if locale != "" {
+			locales = append(locales, locale)
+		}
+		format = intl.Get(constructor).New(locales, opts)
+		intlFormats[key.String()] = format
+	}
+	return format.Call("format", args...).String(), true
+}
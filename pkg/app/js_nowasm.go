@@ -3,6 +3,7 @@
 package app
 
 import (
+	"fmt"
 	"net/url"
 	"runtime"
 
@@ -211,3 +212,142 @@ func copyBytesToGo(dst []byte, src Value) int {
 func copyBytesToJS(dst Value, src []byte) int {
 	return 0
 }
+
+// simulatedValue is a JavaScript value emulated in Go, which is used to
+// simulate events and element properties outside of a browser.
+type simulatedValue struct {
+	value
+	v interface{}
+}
+
+func (v simulatedValue) Bool() bool {
+	b, _ := v.v.(bool)
+	return b
+}
+
+func (v simulatedValue) Call(m string, args ...interface{}) Value {
+	if props, ok := v.v.(map[string]interface{}); ok {
+		if fn, ok := props[m].(func(args ...interface{}) Value); ok {
+			return fn(args...)
+		}
+	}
+	return simulatedValue{}
+}
+
+func (v simulatedValue) Float() float64 {
+	switch n := v.v.(type) {
+	case float64:
+		return n
+
+	case int:
+		return float64(n)
+
+	default:
+		return 0
+	}
+}
+
+func (v simulatedValue) Get(p string) Value {
+	if m, ok := v.v.(map[string]interface{}); ok {
+		if w, ok := m[p].(Value); ok {
+			return w
+		}
+		return simulatedValue{v: m[p]}
+	}
+	return simulatedValue{}
+}
+
+func (v simulatedValue) Index(i int) Value {
+	if s, ok := v.v.([]interface{}); ok && i >= 0 && i < len(s) {
+		if w, ok := s[i].(Value); ok {
+			return w
+		}
+		return simulatedValue{v: s[i]}
+	}
+	return simulatedValue{}
+}
+
+func (v simulatedValue) Int() int {
+	return int(v.Float())
+}
+
+func (v simulatedValue) Length() int {
+	if s, ok := v.v.([]interface{}); ok {
+		return len(s)
+	}
+	return 0
+}
+
+func (v simulatedValue) IsNull() bool {
+	return false
+}
+
+func (v simulatedValue) IsUndefined() bool {
+	return v.v == nil
+}
+
+func (v simulatedValue) JSValue() Value {
+	return v
+}
+
+func (v simulatedValue) Set(p string, x interface{}) {
+	if m, ok := v.v.(map[string]interface{}); ok {
+		m[p] = x
+	}
+}
+
+func (v simulatedValue) String() string {
+	switch s := v.v.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return s
+
+	case map[string]interface{}:
+		return "[object Object]"
+
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+func (v simulatedValue) Truthy() bool {
+	switch x := v.v.(type) {
+	case nil:
+		return false
+
+	case bool:
+		return x
+
+	case string:
+		return x != ""
+
+	case float64:
+		return x != 0
+
+	case int:
+		return x != 0
+
+	default:
+		return true
+	}
+}
+
+func newSimulatedEvent(typ string, props map[string]interface{}) Value {
+	m := map[string]interface{}{"type": typ}
+	for k, v := range props {
+		m[k] = v
+	}
+	return simulatedValue{v: m}
+}
+
+func setSimulatedProperty(n UI, k string, x interface{}) {
+	if v, ok := n.JSValue().(simulatedValue); ok {
+		v.Set(k, x)
+		return
+	}
+	if e, ok := n.(interface{ setJSValue(Value) }); ok {
+		e.setJSValue(simulatedValue{v: map[string]interface{}{k: x}})
+	}
+}
@@ -217,7 +217,7 @@ func (w *browserWindow) GetElementByID(id string) Value {
 
 func (w *browserWindow) ScrollToID(id string) {
 	if elem := w.GetElementByID(id); elem.Truthy() {
-		elem.Call("scrollIntoView")
+		elem.Call("scrollIntoView", scrollIntoViewOptions())
 	}
 }
 
@@ -237,7 +237,7 @@ func (w *browserWindow) AddEventListener(event string, h EventHandler) func() {
 			Mode:   Update,
 			Source: compo,
 		})
-	})
+	}, 0)
 	w.addEventListener(event, callback)
 
 	return func() {
@@ -345,3 +345,20 @@ func cleanArg(v interface{}) interface{} {
 	return v
 
 }
+
+func newSimulatedEvent(typ string, props map[string]interface{}) Value {
+	event := Window().Get("Event").New(typ, map[string]interface{}{
+		"bubbles":    true,
+		"cancelable": true,
+	})
+	for k, v := range props {
+		if _, isValue := v.(Value); !isValue {
+			event.Set(k, v)
+		}
+	}
+	return event
+}
+
+func setSimulatedProperty(n UI, k string, x interface{}) {
+	n.JSValue().Set(k, x)
+}
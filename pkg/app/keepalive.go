@@ -0,0 +1,200 @@
+package app
+
+import (
+	"net/url"
+)
+
+// SetKeepAlive sets the number of recently visited pages whose components are
+// kept in memory when another page is displayed.
+//
+// When the user goes back or forward to a kept page, its component is
+// displayed again as it was left, with its state and the scroll position
+// intact. It is not mounted again, which means that OnMount is not called and
+// that route loaders are not executed. OnNav is called as usual.
+//
+// Kept components stay mounted while they are not displayed, and the least
+// recently visited ones are dismounted when the limit is exceeded.
+//
+// Default: 0, which dismounts the component of a page when another page is
+// displayed.
+func SetKeepAlive(pages int) {
+	keepAlivePages = pages
+}
+
+var (
+	keepAlivePages int
+)
+
+// keptPage describes a page component that is kept mounted while another page
+// is displayed.
+type keptPage struct {
+	url         string
+	compo       UI
+	scroll      ScrollPosition
+	route       routeData
+	routeParams map[string]string
+	routePath   string
+}
+
+// keptPages is the list of the kept pages, from the least to the most
+// recently visited.
+type keptPages []keptPage
+
+func (p keptPages) has(u *url.URL) bool {
+	key := keepAliveKey(u)
+	for _, page := range p {
+		if page.url == key {
+			return true
+		}
+	}
+	return false
+}
+
+// take removes and returns the page kept for the given URL.
+func (p *keptPages) take(u *url.URL) (keptPage, bool) {
+	return p.takeKey(keepAliveKey(u))
+}
+
+func (p *keptPages) takeKey(key string) (keptPage, bool) {
+	pages := *p
+
+	for i, page := range pages {
+		if page.url == key {
+			copy(pages[i:], pages[i+1:])
+			pages[len(pages)-1] = keptPage{}
+			*p = pages[:len(pages)-1]
+			return page, true
+		}
+	}
+	return keptPage{}, false
+}
+
+// push adds the given page and returns the least recently visited pages that
+// exceed the given limit.
+func (p *keptPages) push(page keptPage, limit int) []keptPage {
+	if old, ok := p.takeKey(page.url); ok {
+		*p = append(*p, page)
+		return []keptPage{old}
+	}
+
+	pages := append(*p, page)
+	if len(pages) <= limit {
+		*p = pages
+		return nil
+	}
+
+	n := len(pages) - limit
+	evicted := make([]keptPage, n)
+	copy(evicted, pages[:n])
+	*p = append(pages[:0], pages[n:]...)
+	return evicted
+}
+
+// keepAliveKey returns the key of the page kept for the given URL, which does
+// not depend on the fragment.
+func keepAliveKey(u *url.URL) string {
+	return (&url.URL{
+		Path:     u.Path,
+		RawQuery: u.RawQuery,
+	}).String()
+}
+
+// mountPage mounts the given page component. When pages are kept alive, the
+// displayed page component is detached and kept with the scroll position, the
+// route data and the route parameters of the given URL.
+func (e *engine) mountPage(from *url.URL, n UI) {
+	if keepAlivePages <= 0 || from == nil {
+		e.Mount(n)
+		return
+	}
+
+	scroll := readScrollPosition()
+
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			defer e.perfSection(PerfMeasureMount)()
+
+			if !e.isMountedOnce {
+				e.mount(n)
+				e.displayRoute()
+				return
+			}
+
+			e.swapPage(from, scroll, n)
+			e.refreshOutlets()
+			e.displayRoute()
+		},
+	})
+}
+
+func (e *engine) isPageKept(u *url.URL) bool {
+	return keepAlivePages > 0 && e.keptPages.has(u)
+}
+
+// restorePage displays the page component kept for the given URL in place of
+// the displayed one, which is kept for the previous URL. The route data and
+// the route parameters of the kept page are restored since its route is not
+// loaded again. Pending route loads are canceled.
+func (e *engine) restorePage(from, u *url.URL) {
+	scroll := readScrollPosition()
+
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			defer e.perfSection(PerfMeasureMount)()
+
+			page, ok := e.keptPages.take(u)
+			if !ok {
+				return
+			}
+
+			e.routeLoads++
+			if e.cancelRouteLoad != nil {
+				e.cancelRouteLoad()
+				e.cancelRouteLoad = nil
+			}
+
+			e.swapPage(from, scroll, page.compo)
+			e.route = page.route
+			e.routeParams = page.routeParams
+			e.routePath = page.routePath
+			e.displayRoute()
+
+			ctx.Defer(func(Context) {
+				Window().Call("scrollTo", page.scroll.X, page.scroll.Y)
+			})
+		},
+	})
+}
+
+// swapPage displays the given page component and keeps the displayed one for
+// the given URL. Kept pages that exceed the limit are dismounted.
+func (e *engine) swapPage(from *url.URL, scroll ScrollPosition, n UI) {
+	old, err := e.Body.(elemWithChildren).swapChildAt(0, n)
+	if err != nil {
+		panic(e.mountError(err))
+	}
+
+	evicted := e.keptPages.push(keptPage{
+		url:         keepAliveKey(from),
+		compo:       old,
+		scroll:      scroll,
+		route:       e.displayedRoute,
+		routeParams: e.displayedRouteParams,
+		routePath:   e.displayedRoutePath,
+	}, keepAlivePages)
+	for _, p := range evicted {
+		dismount(p.compo)
+	}
+}
+
+// displayRoute records the loaded route as the route of the displayed page,
+// which is kept with its component when another page is displayed.
+func (e *engine) displayRoute() {
+	e.displayedRoute = e.route
+	e.displayedRouteParams = e.routeParams
+	e.displayedRoutePath = e.routePath
+}
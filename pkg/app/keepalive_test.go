@@ -0,0 +1,133 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/keepalive-users/:id", &keepAliveCompo{})
+}
+
+type keepAliveCompo struct {
+	Compo
+
+	label  string
+	clicks int
+}
+
+func (c *keepAliveCompo) Render() UI {
+	return Div().Text(c.label)
+}
+
+func TestKeptPages(t *testing.T) {
+	parse := func(rawURL string) *url.URL {
+		u, _ := url.Parse(rawURL)
+		return u
+	}
+
+	t.Run("pages are kept by path and query", func(t *testing.T) {
+		var pages keptPages
+		pages.push(keptPage{url: keepAliveKey(parse("/a?id=1#top"))}, 2)
+
+		require.True(t, pages.has(parse("/a?id=1")))
+		require.False(t, pages.has(parse("/a?id=2")))
+
+		page, ok := pages.take(parse("/a?id=1#bottom"))
+		require.True(t, ok)
+		require.Equal(t, "/a?id=1", page.url)
+		require.Empty(t, pages)
+	})
+
+	t.Run("least recently visited pages are evicted", func(t *testing.T) {
+		var pages keptPages
+		require.Empty(t, pages.push(keptPage{url: "/a"}, 2))
+		require.Empty(t, pages.push(keptPage{url: "/b"}, 2))
+
+		evicted := pages.push(keptPage{url: "/c"}, 2)
+		require.Len(t, evicted, 1)
+		require.Equal(t, "/a", evicted[0].url)
+		require.Equal(t, keptPages{{url: "/b"}, {url: "/c"}}, pages)
+	})
+
+	t.Run("page kept again replaces the previous one", func(t *testing.T) {
+		var pages keptPages
+		pages.push(keptPage{url: "/a"}, 2)
+		pages.push(keptPage{url: "/b"}, 2)
+
+		evicted := pages.push(keptPage{url: "/a"}, 2)
+		require.Len(t, evicted, 1)
+		require.Equal(t, keptPages{{url: "/b"}, {url: "/a"}}, pages)
+	})
+}
+
+func TestEngineKeepAlive(t *testing.T) {
+	SetKeepAlive(1)
+	defer SetKeepAlive(0)
+
+	a := &keepAliveCompo{label: "a"}
+	d := NewClientTester(a).(*engine)
+	defer d.Close()
+	a.clicks = 3
+
+	urlA, _ := url.Parse("/a")
+	urlB, _ := url.Parse("/b")
+	urlC, _ := url.Parse("/c")
+
+	b := &keepAliveCompo{label: "b"}
+	d.mountPage(urlA, b)
+	d.Consume()
+	require.Equal(t, b, d.Body.children()[0])
+	require.True(t, a.Mounted())
+	require.True(t, d.isPageKept(urlA))
+
+	d.restorePage(urlB, urlA)
+	d.Consume()
+	require.Equal(t, a, d.Body.children()[0])
+	require.Equal(t, 3, a.clicks)
+	require.True(t, b.Mounted())
+	require.True(t, d.isPageKept(urlB))
+	require.False(t, d.isPageKept(urlA))
+
+	c := &keepAliveCompo{label: "c"}
+	d.mountPage(urlA, c)
+	d.Consume()
+	require.Equal(t, c, d.Body.children()[0])
+	require.False(t, b.Mounted())
+	require.True(t, a.Mounted())
+	require.False(t, d.isPageKept(urlB))
+	require.False(t, d.isPageKept(urlC))
+}
+
+func TestEngineKeepAliveRouteParams(t *testing.T) {
+	SetKeepAlive(1)
+	defer SetKeepAlive(0)
+
+	d := NewClientTester(&keepAliveCompo{label: "home"}).(*engine)
+	defer d.Close()
+
+	urlHome, _ := url.Parse("/")
+	url42, _ := url.Parse("/keepalive-users/42")
+	url7, _ := url.Parse("/keepalive-users/7")
+
+	d.loadRoute(url42, url42.Path, func() {
+		d.mountPage(urlHome, &keepAliveCompo{label: "42"})
+	})
+	d.Consume()
+	require.Equal(t, "42", d.routeParam("id"))
+
+	d.loadRoute(url7, url7.Path, func() {
+		d.mountPage(url42, &keepAliveCompo{label: "7"})
+	})
+	d.Consume()
+	require.Equal(t, "7", d.routeParam("id"))
+	require.True(t, d.isPageKept(url42))
+
+	d.restorePage(url7, url42)
+	d.Consume()
+	require.Equal(t, "42", d.Body.children()[0].(*keepAliveCompo).label)
+	require.Equal(t, "42", d.routeParam("id"))
+	require.Equal(t, url42.Path, d.currentRoutePath())
+}
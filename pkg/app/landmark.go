@@ -0,0 +1,118 @@
+package app
+
+const (
+	// MainContentID is the ID of the element created with MainLandmark, which
+	// is the default target of SkipLink.
+	MainContentID = "goapp-main"
+)
+
+var (
+	routeFocusEnabled bool
+)
+
+// SetRouteFocus enables or disables moving the keyboard focus to the main
+// heading of the page after a navigation to another route, which makes
+// screen readers announce the new page.
+//
+// The focused element is the first h1 of the main landmark, the first h1 of
+// the page, or the main landmark itself. The initial load and navigations to
+// a fragment of the page keep the focus unchanged.
+//
+// Default: false.
+func SetRouteFocus(enabled bool) {
+	routeFocusEnabled = enabled
+}
+
+// SkipLink returns a link that lets keyboard users bypass the navigation and
+// move the focus to the element with the given ID. It is visually hidden
+// until it is focused and should be the first element of the page. When id
+// is empty, it targets the main landmark created with MainLandmark. Eg:
+//  app.Div().Body(
+//      app.SkipLink("", "Skip to content"),
+//      app.NavLandmark("Main menu").Body(...),
+//      app.MainLandmark().Body(...),
+//  )
+func SkipLink(id, label string) UI {
+	if id == "" {
+		id = MainContentID
+	}
+	return &skipLink{Target: id, Label: label}
+}
+
+type skipLink struct {
+	Compo
+
+	Target string
+	Label  string
+}
+
+func (l *skipLink) Render() UI {
+	return A().
+		Class("goapp-skip-link").
+		Href("#" + l.Target).
+		OnClick(l.onClick).
+		Text(l.Label)
+}
+
+func (l *skipLink) onClick(ctx Context, e Event) {
+	ctx.Defer(func(Context) {
+		focusElement(Window().GetElementByID(l.Target))
+	})
+}
+
+// MainLandmark returns the main landmark of the page, which wraps its primary
+// content. It has the MainContentID ID and can receive the focus moved by
+// SkipLink and SetRouteFocus. A page must have only one main landmark.
+func MainLandmark() HTMLMain {
+	return Main().
+		ID(MainContentID).
+		TabIndex(-1)
+}
+
+// NavLandmark returns a navigation landmark with the given accessible label,
+// which distinguishes it from the other navigation landmarks of the page.
+// Eg: "Main menu" or "Breadcrumb".
+func NavLandmark(label string) HTMLNav {
+	return Nav().Aria("label", label)
+}
+
+// AsideLandmark returns a complementary landmark with the given accessible
+// label, for content that is related to the main content but meaningful on
+// its own, such as a table of contents.
+func AsideLandmark(label string) HTMLAside {
+	return Aside().Aria("label", label)
+}
+
+// focusRouteHeading moves the focus to the main heading of the mounted page.
+func focusRouteHeading() {
+	if IsServer || !routeFocusEnabled {
+		return
+	}
+
+	doc := Window().Get("document")
+	for _, selector := range []string{
+		"main h1, [role=main] h1",
+		"h1",
+		"main, [role=main]",
+	} {
+		if elem := doc.Call("querySelector", selector); elem.Truthy() {
+			focusElement(elem)
+			return
+		}
+	}
+}
+
+// focusElement moves the focus to the given element without scrolling the
+// page. Elements that are not focusable are made focusable programmatically.
+func focusElement(elem Value) {
+	if IsServer || !elem.Truthy() {
+		return
+	}
+
+	if !elem.Call("hasAttribute", "tabindex").Bool() {
+		elem.Call("setAttribute", "tabindex", "-1")
+	}
+	elem.Call("focus", map[string]interface{}{
+		"preventScroll": true,
+	})
+}
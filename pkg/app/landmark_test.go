@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipLink(t *testing.T) {
+	t.Run("main content", func(t *testing.T) {
+		compo := SkipLink("", "Skip to content")
+		d := NewServerTester(compo)
+		defer d.Close()
+
+		a := compo.(*skipLink).root
+		require.Equal(t, "a", a.name())
+		require.Equal(t, "#"+MainContentID, a.attributes()["href"])
+		require.Equal(t, "goapp-skip-link", a.attributes()["class"])
+		require.NoError(t, TestMatch(compo, TestUIDescriptor{
+			Path:     TestPath(0, 0),
+			Expected: Text("Skip to content"),
+		}))
+	})
+
+	t.Run("custom target", func(t *testing.T) {
+		compo := SkipLink("results", "Skip to results")
+		d := NewServerTester(compo)
+		defer d.Close()
+
+		require.Equal(t, "#results", compo.(*skipLink).root.attributes()["href"])
+	})
+}
+
+func TestLandmarks(t *testing.T) {
+	main := MainLandmark().Body(H1().Text("Hello"))
+	require.Equal(t, MainContentID, main.attributes()["id"])
+	require.Equal(t, "-1", main.attributes()["tabindex"])
+
+	require.Equal(t, "Main menu", NavLandmark("Main menu").attributes()["aria-label"])
+	require.Equal(t, "Contents", AsideLandmark("Contents").attributes()["aria-label"])
+}
+
+func TestFocusRouteHeading(t *testing.T) {
+	SetRouteFocus(true)
+	defer SetRouteFocus(false)
+
+	focusRouteHeading()
+	focusElement(Window().GetElementByID(MainContentID))
+}
@@ -0,0 +1,278 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The name of the action posted when the installed app is launched with
+	// a URL or files while it is running. The action value is the
+	// LaunchParams of the launch.
+	LaunchAction = "/app/launch"
+
+	launchEndpoint = "/app-launch"
+	launchURLParam = "url"
+	launchScriptID = "app-launch"
+)
+
+var (
+	launchParams LaunchParams
+)
+
+// FileHandler describes the files that the installed app can open from the
+// operating system.
+//
+// eg:
+//  app.FileHandler{
+//      Path: "/open",
+//      Accept: map[string][]string{
+//          "text/csv": {".csv"},
+//      },
+//  }
+type FileHandler struct {
+	// The path of the route that opens the files.
+	Path string `json:"action"`
+
+	// The MIME types of the files and their extensions.
+	Accept map[string][]string `json:"accept"`
+}
+
+// LaunchParams describes how the app was launched from the operating system.
+type LaunchParams struct {
+	// The URL that launched the app. It is the original deep link, such as
+	// "web+myapp://orders/42", when the app is launched with a custom protocol.
+	URL string `json:"url"`
+
+	// The files that launched the app.
+	Files []LaunchFile `json:"-"`
+}
+
+// IsZero reports whether the app was not launched with a URL or files.
+func (p LaunchParams) IsZero() bool {
+	return p.URL == "" && len(p.Files) == 0
+}
+
+// LaunchFile is a file that launched the app.
+type LaunchFile struct {
+	// The name of the file.
+	Name string
+
+	handle Value
+}
+
+// Read reads the content of the file and passes it to the given function,
+// called on the UI goroutine.
+func (f LaunchFile) Read(ctx Context, onDone func(ctx Context, data []byte, err error)) {
+	done := func(data []byte, err error) {
+		if err != nil {
+			err = errors.New("reading launch file failed").
+				Tag("name", f.Name).
+				Wrap(err)
+		}
+		dispatchResult(ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, data, err)
+		})
+	}
+
+	if f.handle == nil || !f.handle.Truthy() {
+		done(nil, errors.New("file is not available"))
+		return
+	}
+
+	fail := func(reason Value) {
+		done(nil, jsReasonError(reason))
+	}
+
+	awaitPromise(f.handle.Call("getFile"), func(file Value) {
+		awaitPromise(file.Call("arrayBuffer"), func(buf Value) {
+			done(goBytes(buf), nil)
+		}, fail)
+	}, fail)
+}
+
+type launchRequestKey struct{}
+
+type pageLaunch struct {
+	URL  string `json:"url"`
+	Path string `json:"path"`
+}
+
+// launchPath returns the path that is associated with the given URL launched
+// with one of the given protocols. Eg: "web+myapp://orders/42" is associated
+// with "/orders/42".
+func launchPath(rawurl string, protocols []string) (string, bool) {
+	u, err := url.Parse(rawurl)
+	if err != nil || !stringsContains(protocols, strings.ToLower(u.Scheme)) {
+		return "", false
+	}
+
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.Path
+	}
+	path = "/" + strings.TrimLeft(path, "/")
+
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path, true
+}
+
+func (h *Handler) makeProtocolHandlers() []map[string]string {
+	handlers := make([]map[string]string, len(h.ProtocolHandlers))
+	for i, p := range h.ProtocolHandlers {
+		handlers[i] = map[string]string{
+			"protocol": p,
+			"url":      h.resolvePackagePath(launchEndpoint) + "?" + launchURLParam + "=%s",
+		}
+	}
+	return handlers
+}
+
+func (h *Handler) makeFileHandlers() []FileHandler {
+	handlers := make([]FileHandler, len(h.FileHandlers))
+	for i, fh := range h.FileHandlers {
+		handlers[i] = FileHandler{
+			Path:   h.resolvePackagePath(fh.Path),
+			Accept: fh.Accept,
+		}
+	}
+	return handlers
+}
+
+// serveLaunch prerenders the route associated with the URL that launched the
+// app with a custom protocol.
+func (h *Handler) serveLaunch(w http.ResponseWriter, r *http.Request) {
+	rawurl := r.URL.Query().Get(launchURLParam)
+	path, ok := launchPath(rawurl, h.ProtocolHandlers)
+	if !ok {
+		http.Error(w, "invalid launch url", http.StatusBadRequest)
+		return
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		http.Error(w, "invalid launch url", http.StatusBadRequest)
+		return
+	}
+
+	r2 := r.Clone(context.WithValue(r.Context(), launchRequestKey{}, pageLaunch{
+		URL:  rawurl,
+		Path: h.resolvePackagePath(u.Path),
+	}))
+	r2.URL.Path = u.Path
+	r2.URL.RawPath = ""
+	r2.URL.RawQuery = u.RawQuery
+	h.servePage(w, r2)
+}
+
+// renderPageLaunch returns the script that passes the URL that launched the app
+// to the page served for the given request.
+func renderPageLaunch(r *http.Request) string {
+	launch, ok := r.Context().Value(launchRequestKey{}).(pageLaunch)
+	if !ok {
+		return ""
+	}
+
+	if launch.Path != "" && r.URL.RawQuery != "" {
+		launch.Path += "?" + r.URL.RawQuery
+	}
+
+	b, err := json.Marshal(launch)
+	if err != nil {
+		Log(errors.New("encoding page launch failed").Wrap(err))
+		return ""
+	}
+	return `<script id="` + launchScriptID + `" type="application/json">` + string(b) + `</script>`
+}
+
+// loadPageLaunch sets the launch params passed by the server and replaces the
+// launch endpoint URL with the URL of the route that it is associated with.
+func loadPageLaunch() {
+	script := Window().GetElementByID(launchScriptID)
+	if !script.Truthy() {
+		return
+	}
+
+	var launch pageLaunch
+	if err := json.Unmarshal([]byte(script.Get("textContent").String()), &launch); err != nil {
+		Log(errors.New("decoding page launch failed").Wrap(err))
+		return
+	}
+	launchParams = LaunchParams{URL: launch.URL}
+
+	u := *Window().URL()
+	if path, err := url.Parse(launch.Path); err == nil {
+		u.Path = path.Path
+		u.RawPath = ""
+		u.RawQuery = path.RawQuery
+		Window().replaceHistory(&u)
+	}
+}
+
+// startLaunchQueue consumes the launches of the installed app that are
+// performed while it is running, or with files, and triggers OnAppLaunch.
+func startLaunchQueue(d ClientDispatcher) {
+	launchQueue := Window().Get("launchQueue")
+	if !launchQueue.Truthy() {
+		return
+	}
+
+	var protocols []string
+	json.Unmarshal([]byte(Getenv("GOAPP_PROTOCOL_HANDLERS")), &protocols)
+
+	launchQueue.Call("setConsumer", FuncOf(func(this Value, args []Value) interface{} {
+		params := promiseArg(args)
+		if !params.Truthy() {
+			return nil
+		}
+
+		launch := LaunchParams{
+			URL: jsOptionalString(params.Get("targetURL")),
+		}
+
+		var target *url.URL
+		if u, err := url.Parse(launch.URL); err == nil {
+			target = u
+			if strings.HasSuffix(u.Path, launchEndpoint) {
+				launch.URL = u.Query().Get(launchURLParam)
+				target = nil
+				if path, ok := launchPath(launch.URL, protocols); ok {
+					target, _ = url.Parse(rootPrefix + path)
+				}
+			}
+		}
+
+		if files := params.Get("files"); files.Truthy() {
+			for i := 0; i < files.Length(); i++ {
+				handle := files.Index(i)
+				launch.Files = append(launch.Files, LaunchFile{
+					Name:   handle.Get("name").String(),
+					handle: handle,
+				})
+			}
+		}
+
+		d.Dispatch(Dispatch{
+			Mode: Update,
+			Function: func(ctx Context) {
+				launchParams = launch
+				if target != nil && target.RequestURI() != Window().URL().RequestURI() {
+					ctx.NavigateTo(target)
+				}
+				d.AppLaunch(launch)
+				d.Post(Action{
+					Name:  LaunchAction,
+					Value: launch,
+				})
+			},
+		})
+		return nil
+	}))
+}
@@ -0,0 +1,134 @@
+//go:build !wasm
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/launch-orders/42", &launchTestCompo{})
+}
+
+type launchTestCompo struct {
+	Compo
+}
+
+func (c *launchTestCompo) Render() UI {
+	return Div().ID("launch-order")
+}
+
+func TestLaunchPath(t *testing.T) {
+	protocols := []string{"web+myapp"}
+
+	utests := []struct {
+		url   string
+		path  string
+		valid bool
+	}{
+		{url: "web+myapp://orders/42", path: "/orders/42", valid: true},
+		{url: "web+myapp:orders/42", path: "/orders/42", valid: true},
+		{url: "web+myapp://orders/42?tab=items", path: "/orders/42?tab=items", valid: true},
+		{url: "WEB+MYAPP://orders", path: "/orders", valid: true},
+		{url: "web+myapp://", path: "/", valid: true},
+		{url: "web+other://orders/42"},
+		{url: "https://murlok.io/orders/42"},
+		{url: "/orders/42"},
+	}
+
+	for _, u := range utests {
+		t.Run(u.url, func(t *testing.T) {
+			path, ok := launchPath(u.url, protocols)
+			require.Equal(t, u.valid, ok)
+			require.Equal(t, u.path, path)
+		})
+	}
+}
+
+func TestHandlerServeManifestWithLaunchHandlers(t *testing.T) {
+	h := Handler{
+		ProtocolHandlers: []string{"web+myapp"},
+		FileHandlers: []FileHandler{
+			{
+				Path:   "/open",
+				Accept: map[string][]string{"text/csv": {".csv"}},
+			},
+		},
+		LaunchMode: "focus-existing",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var manifest struct {
+		ProtocolHandlers []map[string]string `json:"protocol_handlers"`
+		FileHandlers     []FileHandler       `json:"file_handlers"`
+		LaunchHandler    map[string]string   `json:"launch_handler"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &manifest))
+	require.Equal(t, []map[string]string{{
+		"protocol": "web+myapp",
+		"url":      "/app-launch?url=%s",
+	}}, manifest.ProtocolHandlers)
+	require.Equal(t, h.FileHandlers, manifest.FileHandlers)
+	require.Equal(t, "focus-existing", manifest.LaunchHandler["client_mode"])
+}
+
+func TestHandlerServeManifestWithoutLaunchHandlers(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, json.Valid(w.Body.Bytes()))
+	require.NotContains(t, w.Body.String(), "protocol_handlers")
+	require.NotContains(t, w.Body.String(), "launch_handler")
+}
+
+func TestHandlerServeLaunch(t *testing.T) {
+	h := Handler{
+		ProtocolHandlers: []string{"web+myapp"},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("deep link is routed", func(t *testing.T) {
+		w := serve("/app-launch?url=web%2Bmyapp%3A%2F%2Flaunch-orders%2F42%3Ftab%3Ditems")
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		require.Contains(t, body, `id="launch-order"`)
+		require.Contains(t, body, `<script id="app-launch" type="application/json">{"url":"web+myapp://launch-orders/42?tab=items","path":"/launch-orders/42?tab=items"}</script>`)
+	})
+
+	t.Run("launched page is not cached", func(t *testing.T) {
+		w := serve("/launch-orders/42")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotContains(t, w.Body.String(), `id="app-launch"`)
+	})
+
+	t.Run("unregistered protocol is rejected", func(t *testing.T) {
+		w := serve("/app-launch?url=web%2Bother%3A%2F%2Flaunch-orders%2F42")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestContextLaunchParams(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	require.True(t, makeContext(compo).LaunchParams().IsZero())
+}
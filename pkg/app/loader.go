@@ -0,0 +1,495 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	routeDataScriptID    = "app-route-data"
+	routeDataPublicError = "loading route data failed"
+)
+
+// Loader describes how the data of the pages served by a route is loaded
+// before they are mounted.
+type Loader struct {
+	// The function that loads the data. It is called on the server before a
+	// page is pre-rendered, and in the browser before the page component is
+	// mounted after a navigation. The context page returns the URL of the
+	// page that is loaded.
+	Load func(ctx Context) (interface{}, error)
+
+	// The function that returns the UI that is displayed in the browser while
+	// the data is loading. The previous page stays displayed when nil.
+	Pending func() UI
+
+	// The routes whose loaders are executed before this one, with the same
+	// page URL. Loaders that do not depend on each other are executed
+	// concurrently, and their data is available with
+	// Context.ParentRouteData, both in Load and in the page components.
+	Parents []string
+
+	// The function that returns the error message passed to the pre-rendered
+	// page when Load returns an error. Errors are logged on the server, and
+	// the page receives "loading route data failed" when nil, which prevents
+	// server errors from being exposed to users.
+	PublicError func(error) string
+}
+
+// RouteLoader sets the loader of the pages served by the route registered with
+// the given path or regular expression pattern. Components get the loaded
+// data with Context.RouteData instead of fetching it when they are mounted,
+// which prevents empty states from being displayed.
+//
+// eg:
+//  app.RouteLoader("/article", app.Loader{
+//      Load: func(ctx app.Context) (interface{}, error) {
+//          return getArticle(ctx, ctx.Page().URL().Query().Get("id"))
+//      },
+//      Pending: func() app.UI {
+//          return &articleSkeleton{}
+//      },
+//  })
+//
+// Loaders can depend on the loaders of other routes, such as the loader of a
+// section that is shared by several pages:
+//  app.RouteLoader("/docs", app.Loader{
+//      Load: loadDocsMenu,
+//  })
+//
+//  app.RouteLoader("/docs/install", app.Loader{
+//      Parents: []string{"/docs"},
+//      Load: func(ctx app.Context) (interface{}, error) {
+//          var menu docsMenu
+//          if err := ctx.ParentRouteData("/docs", &menu); err != nil {
+//              return nil, err
+//          }
+//          return getDoc(ctx, menu.Version, "install")
+//      },
+//  })
+//
+// The context given to loaders is canceled when another page is loaded before
+// they return. Data loaded on the server is passed to the pre-rendered page as
+// JSON, which means that it is not loaded again when the app starts.
+func RouteLoader(route string, l Loader) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.loader = l
+	})
+}
+
+// routeData describes the data loaded for a page.
+type routeData struct {
+	path  string
+	value interface{}
+	raw   json.RawMessage
+	err   error
+
+	// The error message that is passed to the pre-rendered page.
+	publicErr string
+
+	// The data loaded by the parent loaders, by route.
+	parents map[string]routeData
+}
+
+// pageRouteData is the JSON representation of the data passed to a
+// pre-rendered page.
+type pageRouteData struct {
+	Path    string
+	Value   json.RawMessage          `json:",omitempty"`
+	Error   string                   `json:",omitempty"`
+	Parents map[string]pageRouteData `json:",omitempty"`
+}
+
+// load copies the loaded data into the given receiver, or returns the error
+// of the loader.
+func (d routeData) load(recv interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if d.raw != nil {
+		if err := json.Unmarshal(d.raw, recv); err != nil {
+			return errors.New("decoding route data failed").
+				Tag("path", d.path).
+				Wrap(err)
+		}
+		return nil
+	}
+
+	if err := storeValue(recv, d.value); err != nil {
+		return errors.New("getting route data failed").
+			Tag("path", d.path).
+			Wrap(err)
+	}
+	return nil
+}
+
+// parent returns the data loaded by the parent loader of the given route.
+func (d routeData) parent(route string) routeData {
+	if p, ok := d.parents[route]; ok {
+		return p
+	}
+
+	return routeData{
+		path: d.path,
+		err: errors.New("parent route data is not loaded").
+			Tag("path", d.path).
+			Tag("route", route),
+	}
+}
+
+// loadRoute loads the data of the page served by the given path, then calls
+// done on the UI goroutine. On the server, the data is loaded synchronously.
+// In the browser, the pending UI of the loader is mounted while the data is
+// loading, and done is not called when another route is loaded in the
+// meantime.
+func (e *engine) loadRoute(u *url.URL, path string, done func()) {
+	e.routeLoads++
+	id := e.routeLoads
+	if e.cancelRouteLoad != nil {
+		e.cancelRouteLoad()
+		e.cancelRouteLoad = nil
+	}
+
+	meta, params := routes.meta(path)
+	e.routeParams = params
+	e.routePath = path
+	l := meta.loader
+
+	if l.Load == nil {
+		e.route = routeData{path: path}
+		done()
+		return
+	}
+
+	if !e.RunsInServer {
+		if d, ok := loadPageRouteData(path); ok {
+			e.route = d
+			done()
+			return
+		}
+	}
+
+	ctx := makeContext(e.Body).(uiContext)
+	ctx.page = loaderPage{Page: ctx.page, url: u}
+	loadCtx, cancel := context.WithCancel(ctx.Context)
+	ctx.Context = loadCtx
+
+	if e.RunsInServer {
+		e.route = runLoaders(ctx, path, l)
+		cancel()
+		done()
+		return
+	}
+	e.cancelRouteLoad = cancel
+
+	if l.Pending != nil {
+		e.Mount(l.Pending())
+	}
+
+	e.Async(func() {
+		d := runLoaders(ctx, path, l)
+		cancel()
+
+		e.Dispatch(Dispatch{
+			Mode:   Update,
+			Source: e.Body,
+			Function: func(Context) {
+				if id != e.routeLoads {
+					return
+				}
+				e.route = d
+				e.cancelRouteLoad = nil
+				done()
+			},
+		})
+	})
+}
+
+func (e *engine) routeData(recv interface{}) error {
+	return e.route.load(recv)
+}
+
+func (e *engine) parentRouteData(route string, recv interface{}) error {
+	return e.route.parent(route).load(recv)
+}
+
+func (e *engine) routeParam(name string) string {
+	return e.routeParams[name]
+}
+
+func (e *engine) currentRoutePath() string {
+	return e.routePath
+}
+
+// runLoaders executes the given loader once its parent loaders are executed.
+func runLoaders(ctx uiContext, path string, l Loader) routeData {
+	if err := checkLoaderGraph(l, nil); err != nil {
+		return routeData{
+			path: path,
+			err: errors.New("loading route data failed").
+				Tag("path", path).
+				Wrap(err),
+		}
+	}
+
+	g := loaderGraph{
+		ctx:   ctx,
+		path:  path,
+		calls: make(map[string]*loaderCall),
+	}
+	return g.run(l)
+}
+
+// checkLoaderGraph returns an error when a parent route of the given loader
+// does not have a loader, or when loaders depend on each other.
+func checkLoaderGraph(l Loader, visiting []string) error {
+	for _, p := range l.Parents {
+		for _, v := range visiting {
+			if v == p {
+				return errors.New("loader dependency cycle").
+					Tag("route", p).
+					Tag("cycle", append(visiting, p))
+			}
+		}
+
+		parent := routes.loader(p)
+		if parent.Load == nil {
+			return errors.New("parent route does not have a loader").
+				Tag("route", p)
+		}
+		if err := checkLoaderGraph(parent, append(visiting, p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loaderGraph executes the loaders required to load a page. Each loader is
+// executed once, even when several loaders depend on it.
+type loaderGraph struct {
+	ctx   uiContext
+	path  string
+	mutex sync.Mutex
+	calls map[string]*loaderCall
+}
+
+type loaderCall struct {
+	done chan struct{}
+	data routeData
+}
+
+func (g *loaderGraph) run(l Loader) routeData {
+	d := routeData{
+		path:    g.path,
+		parents: g.loadParents(l.Parents),
+	}
+
+	for _, p := range l.Parents {
+		if err := d.parents[p].err; err != nil {
+			d.err = errors.New("loading parent route data failed").
+				Tag("path", g.path).
+				Tag("route", p).
+				Wrap(err)
+			d.publicErr = d.parents[p].publicErr
+			return d
+		}
+	}
+
+	if err := g.ctx.Err(); err != nil {
+		d.err = errors.New("loading route data failed").
+			Tag("path", g.path).
+			Wrap(err)
+		return d
+	}
+
+	ctx := g.ctx
+	ctx.disp = loaderDispatcher{
+		Dispatcher: ctx.disp,
+		data:       d,
+	}
+
+	v, err := l.Load(ctx)
+	if err != nil && l.PublicError != nil {
+		d.publicErr = l.PublicError(err)
+	}
+	if err != nil {
+		err = errors.New("loading route data failed").
+			Tag("path", g.path).
+			Wrap(err)
+	}
+	d.value = v
+	d.err = err
+	return d
+}
+
+// loadParents executes the loaders of the given routes concurrently and
+// returns their data with the data of their own parents.
+func (g *loaderGraph) loadParents(routes []string) map[string]routeData {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	calls := make([]*loaderCall, len(routes))
+	for i, r := range routes {
+		calls[i] = g.call(r)
+	}
+
+	parents := make(map[string]routeData)
+	for i, c := range calls {
+		<-c.done
+
+		for r, p := range c.data.parents {
+			parents[r] = p
+		}
+		d := c.data
+		d.parents = nil
+		parents[routes[i]] = d
+	}
+	return parents
+}
+
+func (g *loaderGraph) call(route string) *loaderCall {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if c, ok := g.calls[route]; ok {
+		return c
+	}
+
+	c := &loaderCall{done: make(chan struct{})}
+	g.calls[route] = c
+
+	go func() {
+		defer close(c.done)
+		c.data = g.run(routes.loader(route))
+	}()
+	return c
+}
+
+// loaderDispatcher is the dispatcher of the context given to loaders, which
+// returns the data loaded by their parents.
+type loaderDispatcher struct {
+	Dispatcher
+	data routeData
+}
+
+func (d loaderDispatcher) routeData(recv interface{}) error {
+	return d.data.load(recv)
+}
+
+func (d loaderDispatcher) parentRouteData(route string, recv interface{}) error {
+	return d.data.parent(route).load(recv)
+}
+
+// loaderPage is the page of the context given to loaders, which returns the
+// URL of the page that is loaded.
+type loaderPage struct {
+	Page
+	url *url.URL
+}
+
+func (p loaderPage) URL() *url.URL {
+	return p.url
+}
+
+// renderPageRouteData returns the script that passes the data loaded on the
+// server to the app.
+func renderPageRouteData(d routeData) string {
+	if d.value == nil && d.err == nil {
+		return ""
+	}
+	if d.err != nil {
+		Log(d.err)
+	}
+
+	data, err := newPageRouteData(d)
+	if err != nil {
+		Log(err)
+		return ""
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		Log(errors.New("encoding route data failed").
+			Tag("path", d.path).
+			Wrap(err))
+		return ""
+	}
+	return `<script id="` + routeDataScriptID + `" type="application/json">` + string(b) + `</script>`
+}
+
+// loadPageRouteData returns the data passed in the page by the server when it
+// was loaded for the given path. The script is removed, which ensures the data
+// is only used for the first navigation.
+func loadPageRouteData(path string) (routeData, bool) {
+	script := Window().GetElementByID(routeDataScriptID)
+	if !script.Truthy() {
+		return routeData{}, false
+	}
+	defer script.Call("remove")
+
+	var data pageRouteData
+	if err := json.Unmarshal([]byte(script.Get("textContent").String()), &data); err != nil {
+		Log(errors.New("decoding page route data failed").Wrap(err))
+		return routeData{}, false
+	}
+	if data.Path != path {
+		return routeData{}, false
+	}
+
+	return data.routeData(), true
+}
+
+func newPageRouteData(d routeData) (pageRouteData, error) {
+	data := pageRouteData{Path: d.path}
+
+	if d.err != nil {
+		data.Error = d.publicErr
+		if data.Error == "" {
+			data.Error = routeDataPublicError
+		}
+	} else {
+		v, err := json.Marshal(d.value)
+		if err != nil {
+			return pageRouteData{}, errors.New("encoding route data failed").
+				Tag("path", d.path).
+				Wrap(err)
+		}
+		data.Value = v
+	}
+
+	for r, p := range d.parents {
+		parent, err := newPageRouteData(p)
+		if err != nil {
+			return pageRouteData{}, err
+		}
+		if data.Parents == nil {
+			data.Parents = make(map[string]pageRouteData, len(d.parents))
+		}
+		data.Parents[r] = parent
+	}
+	return data, nil
+}
+
+func (p pageRouteData) routeData() routeData {
+	d := routeData{
+		path: p.Path,
+		raw:  p.Value,
+	}
+	if p.Error != "" {
+		d.err = errors.New(p.Error)
+	}
+
+	for r, parent := range p.Parents {
+		if d.parents == nil {
+			d.parents = make(map[string]routeData, len(p.Parents))
+		}
+		d.parents[r] = parent.routeData()
+	}
+	return d
+}
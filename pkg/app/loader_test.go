@@ -0,0 +1,350 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/loader-test", &loaderCompo{})
+	RouteLoader("/loader-test", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			return loaderArticle{
+				ID:    ctx.Page().URL().Query().Get("id"),
+				Title: "Hello",
+			}, nil
+		},
+		Pending: func() UI {
+			return Div().Text("loading")
+		},
+	})
+
+	aStarted := make(chan string, 1)
+	bStarted := make(chan string, 1)
+	RouteLoader("/loader-graph/a", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			aStarted <- "a"
+			return "a-" + waitLoaderGraph(bStarted), nil
+		},
+	})
+	RouteLoader("/loader-graph/b", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			bStarted <- "b"
+			return "b-" + waitLoaderGraph(aStarted), nil
+		},
+	})
+	RouteLoader("/loader-graph/c", Loader{
+		Parents: []string{"/loader-graph/a"},
+		Load: func(ctx Context) (interface{}, error) {
+			var a string
+			err := ctx.ParentRouteData("/loader-graph/a", &a)
+			return "c-" + a, err
+		},
+	})
+	RouteLoader("/loader-graph/page", Loader{
+		Parents: []string{"/loader-graph/c", "/loader-graph/b"},
+		Load: func(ctx Context) (interface{}, error) {
+			var a, b, c string
+			ctx.ParentRouteData("/loader-graph/a", &a)
+			ctx.ParentRouteData("/loader-graph/b", &b)
+			ctx.ParentRouteData("/loader-graph/c", &c)
+			return a + " " + b + " " + c, nil
+		},
+	})
+
+	RouteLoader("/loader-cycle/a", Loader{
+		Parents: []string{"/loader-cycle/b"},
+		Load: func(ctx Context) (interface{}, error) {
+			return nil, nil
+		},
+	})
+	RouteLoader("/loader-cycle/b", Loader{
+		Parents: []string{"/loader-cycle/a"},
+		Load: func(ctx Context) (interface{}, error) {
+			return nil, nil
+		},
+	})
+
+	Route("/loader-slow", &loaderCompo{})
+	RouteLoader("/loader-slow", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+
+			case <-time.After(time.Millisecond * 100):
+				return "slow", nil
+			}
+		},
+	})
+
+	Route("/loader-error-test", &loaderCompo{})
+	RouteLoader("/loader-error-test", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			return nil, errors.New("not found")
+		},
+	})
+
+	Route("/loader-public-error-test", &loaderCompo{})
+	RouteLoader("/loader-public-error-test", Loader{
+		Load: func(ctx Context) (interface{}, error) {
+			return nil, errors.New("sql: no rows in result set")
+		},
+		PublicError: func(err error) string {
+			return "article not found"
+		},
+	})
+}
+
+// waitLoaderGraph returns the name of the other loader once it is started,
+// which ensures that independent loaders are executed concurrently.
+func waitLoaderGraph(started chan string) string {
+	select {
+	case n := <-started:
+		return n
+
+	case <-time.After(time.Second):
+		return "timeout"
+	}
+}
+
+type loaderArticle struct {
+	ID    string
+	Title string
+}
+
+type loaderCompo struct {
+	Compo
+
+	article loaderArticle
+	err     error
+}
+
+func (c *loaderCompo) OnPreRender(ctx Context) {
+	c.err = ctx.RouteData(&c.article)
+}
+
+func (c *loaderCompo) Render() UI {
+	if c.err != nil {
+		return Div().Text("error: " + c.err.Error())
+	}
+	return Div().Text("article " + c.article.ID + ": " + c.article.Title)
+}
+
+func TestHandlerRouteLoader(t *testing.T) {
+	t.Run("data is loaded before pre-rendering", func(t *testing.T) {
+		h := Handler{}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/loader-test?id=42", nil))
+
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, body, "article 42: Hello")
+		require.Contains(t, body, `<script id="app-route-data" type="application/json">{"Path":"/loader-test","Value":{"ID":"42","Title":"Hello"}}</script>`)
+	})
+
+	t.Run("loader error is reported", func(t *testing.T) {
+		h := Handler{}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/loader-error-test", nil))
+
+		body := w.Body.String()
+		require.Contains(t, body, "error: loading route data failed")
+		require.Contains(t, body, `<script id="app-route-data" type="application/json">{"Path":"/loader-error-test","Error":"loading route data failed"}</script>`)
+	})
+
+	t.Run("loader public error is reported", func(t *testing.T) {
+		h := Handler{}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/loader-public-error-test", nil))
+
+		body := w.Body.String()
+		require.Contains(t, body, `<script id="app-route-data" type="application/json">{"Path":"/loader-public-error-test","Error":"article not found"}</script>`)
+		require.NotContains(t, body, `"Error":"sql`)
+	})
+
+	t.Run("route without loader does not pass data", func(t *testing.T) {
+		h := Handler{}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redirect-test", nil))
+		require.NotContains(t, w.Body.String(), "app-route-data")
+	})
+}
+
+func TestEngineLoadRoute(t *testing.T) {
+	t.Run("data is loaded before done is called", func(t *testing.T) {
+		d := NewClientTester(Div()).(*engine)
+		defer d.Close()
+
+		u, _ := url.Parse("/loader-test?id=21")
+		done := false
+		d.loadRoute(u, "/loader-test", func() {
+			done = true
+		})
+		require.False(t, done)
+
+		d.Consume()
+		require.Equal(t, "loading", d.Body.children()[0].children()[0].(*text).value)
+
+		d.Wait()
+		d.Consume()
+		require.True(t, done)
+
+		var article loaderArticle
+		err := d.Context().RouteData(&article)
+		require.NoError(t, err)
+		require.Equal(t, loaderArticle{ID: "21", Title: "Hello"}, article)
+	})
+
+	t.Run("previous load is dropped", func(t *testing.T) {
+		d := NewClientTester(Div()).(*engine)
+		defer d.Close()
+
+		u, _ := url.Parse("/loader-test")
+		first := false
+		second := false
+		d.loadRoute(u, "/loader-test", func() {
+			first = true
+		})
+		d.loadRoute(u, "/loader-error-test", func() {
+			second = true
+		})
+
+		d.Wait()
+		d.Consume()
+		require.False(t, first)
+		require.True(t, second)
+
+		var article loaderArticle
+		require.Error(t, d.Context().RouteData(&article))
+	})
+
+	t.Run("previous load is canceled", func(t *testing.T) {
+		d := NewClientTester(Div()).(*engine)
+		defer d.Close()
+
+		u, _ := url.Parse("/loader-slow")
+		canceled := false
+		d.loadRoute(u, "/loader-slow", func() {
+			canceled = true
+		})
+		d.loadRoute(u, "/no-loader", func() {})
+
+		d.Wait()
+		d.Consume()
+		require.False(t, canceled)
+		require.Nil(t, d.cancelRouteLoad)
+	})
+
+	t.Run("route without loader calls done immediately", func(t *testing.T) {
+		d := NewClientTester(Div()).(*engine)
+		defer d.Close()
+
+		u, _ := url.Parse("/")
+		done := false
+		d.loadRoute(u, "/no-loader", func() {
+			done = true
+		})
+		require.True(t, done)
+
+		article := loaderArticle{ID: "1"}
+		require.NoError(t, d.Context().RouteData(&article))
+		require.Zero(t, article)
+	})
+}
+
+func TestRunLoaders(t *testing.T) {
+	newLoaderContext := func() uiContext {
+		d := NewServerTester(Div())
+		return d.Context().(uiContext)
+	}
+
+	t.Run("parent loaders are executed before children", func(t *testing.T) {
+		path := "/loader-graph/page"
+		d := runLoaders(newLoaderContext(), path, routes.loader(path))
+		require.NoError(t, d.err)
+		require.Equal(t, "a-b b-a c-a-b", d.value)
+		require.Len(t, d.parents, 3)
+
+		var a string
+		require.NoError(t, d.parent("/loader-graph/a").load(&a))
+		require.Equal(t, "a-b", a)
+		require.Error(t, d.parent("/loader-test").load(&a))
+	})
+
+	t.Run("dependency cycle returns an error", func(t *testing.T) {
+		path := "/loader-cycle/a"
+		d := runLoaders(newLoaderContext(), path, routes.loader(path))
+		require.Error(t, d.err)
+	})
+
+	t.Run("parent without loader returns an error", func(t *testing.T) {
+		d := runLoaders(newLoaderContext(), "/", Loader{
+			Parents: []string{"/no-loader"},
+			Load: func(ctx Context) (interface{}, error) {
+				return nil, nil
+			},
+		})
+		require.Error(t, d.err)
+	})
+
+	t.Run("parent error is returned", func(t *testing.T) {
+		d := runLoaders(newLoaderContext(), "/", Loader{
+			Parents: []string{"/loader-error-test"},
+			Load: func(ctx Context) (interface{}, error) {
+				return "child", nil
+			},
+		})
+		require.Error(t, d.err)
+		require.Nil(t, d.value)
+	})
+}
+
+func TestPageRouteData(t *testing.T) {
+	d := routeData{
+		path:  "/loader-graph/page",
+		value: "page",
+		parents: map[string]routeData{
+			"/loader-graph/a": {path: "/loader-graph/page", value: "a"},
+			"/loader-graph/b": {path: "/loader-graph/page", err: errors.New("b failed")},
+			"/loader-graph/c": {path: "/loader-graph/page", err: errors.New("c failed"), publicErr: "c not found"},
+		},
+	}
+
+	data, err := newPageRouteData(d)
+	require.NoError(t, err)
+	d = data.routeData()
+
+	var v string
+	require.NoError(t, d.load(&v))
+	require.Equal(t, "page", v)
+	require.NoError(t, d.parent("/loader-graph/a").load(&v))
+	require.Equal(t, "a", v)
+	require.EqualError(t, d.parent("/loader-graph/b").load(&v), routeDataPublicError)
+	require.EqualError(t, d.parent("/loader-graph/c").load(&v), "c not found")
+}
+
+func TestRouteDataLoad(t *testing.T) {
+	t.Run("raw data is decoded", func(t *testing.T) {
+		d := routeData{raw: []byte(`{"ID":"7","Title":"Raw"}`)}
+
+		var article loaderArticle
+		require.NoError(t, d.load(&article))
+		require.Equal(t, loaderArticle{ID: "7", Title: "Raw"}, article)
+	})
+
+	t.Run("value of another type returns an error", func(t *testing.T) {
+		d := routeData{value: 42}
+
+		var article loaderArticle
+		require.Error(t, d.load(&article))
+	})
+}
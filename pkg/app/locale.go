@@ -0,0 +1,309 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	localeCookieName = "goapp_locale"
+	localeCookieTTL  = time.Hour * 24 * 365
+)
+
+var (
+	appLocales   []string
+	translations = translationCatalogs{
+		catalogs: make(map[string]map[string]string),
+	}
+)
+
+// AddTranslations registers the translations of the given locale, such as
+// "en" or "de". Translations are the texts returned by Context.T for the given
+// keys, and are merged with the translations previously registered for the
+// locale. Catalogs of translations can be extracted from the Go sources with
+// the i18n package.
+//
+// eg:
+//  app.AddTranslations("de", map[string]string{
+//      "Hello, %s!": "Hallo, %s!",
+//  })
+func AddTranslations(locale string, t map[string]string) {
+	translations.add(locale, t)
+}
+
+type translationCatalogs struct {
+	mutex    sync.RWMutex
+	catalogs map[string]map[string]string
+}
+
+func (c *translationCatalogs) add(locale string, t map[string]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	locale = strings.ToLower(locale)
+	catalog, ok := c.catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string, len(t))
+		c.catalogs[locale] = catalog
+	}
+	for k, v := range t {
+		catalog[k] = v
+	}
+}
+
+// translate returns the translation of the given key for the first of the
+// given locales that translates it, or the key when none does. Empty
+// translations, which are the untranslated entries of extracted catalogs, are
+// ignored.
+func (c *translationCatalogs) translate(key string, locales ...string) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, l := range locales {
+		if v := c.catalogs[strings.ToLower(l)][key]; v != "" {
+			return v
+		}
+	}
+	return key
+}
+
+func translate(locale string, locales []string, key string, v ...interface{}) string {
+	fallback := locale
+	if len(locales) != 0 {
+		fallback = locales[0]
+	}
+
+	s := translations.translate(key, locale, fallback)
+	if len(v) != 0 {
+		s = fmt.Sprintf(s, v...)
+	}
+	return s
+}
+
+// splitLocalePath returns the locale prefix of the given path and the path of
+// the route that it targets. Eg: "/de/about" is split into "de" and "/about".
+// The locale is empty when the path is not prefixed by one of the given
+// locales.
+func splitLocalePath(path string, locales []string) (string, string) {
+	for _, l := range locales {
+		prefix := "/" + l
+		if strings.EqualFold(path, prefix) {
+			return l, "/"
+		}
+		if len(path) > len(prefix) && path[len(prefix)] == '/' && strings.EqualFold(path[:len(prefix)], prefix) {
+			return l, path[len(prefix):]
+		}
+	}
+	return "", path
+}
+
+// localizePath returns the given route path prefixed by the given locale. Eg:
+// "/about" is localized as "/de/about" for "de", and "/" as "/de".
+func localizePath(path, locale string) string {
+	if locale == "" {
+		return path
+	}
+	if path == "" || path == "/" {
+		return "/" + locale
+	}
+	return "/" + locale + "/" + strings.TrimPrefix(path, "/")
+}
+
+// routePath returns the path of the route that is targeted by the given page
+// path, without its locale prefix.
+func (h *Handler) routePath(path string) string {
+	_, path = splitLocalePath(path, h.Locales)
+	return path
+}
+
+// requestLocale returns the locale that is displayed for the given request.
+func (h *Handler) requestLocale(r *http.Request) string {
+	if len(h.Locales) == 0 {
+		return ""
+	}
+	if locale, _ := splitLocalePath(r.URL.Path, h.Locales); locale != "" {
+		return locale
+	}
+	return h.Locales[0]
+}
+
+// preferredLocale returns the locale persisted with Context.SetLocale, or the
+// one that best matches the Accept-Language header of the given request.
+func (h *Handler) preferredLocale(r *http.Request) string {
+	if c, err := r.Cookie(localeCookieName); err == nil {
+		if locale := matchLocale(c.Value, h.Locales); locale != "" {
+			return locale
+		}
+	}
+	if locale := matchLocale(r.Header.Get("Accept-Language"), h.Locales); locale != "" {
+		return locale
+	}
+	return h.Locales[0]
+}
+
+// redirectLocale redirects requests to the root path to the preferred locale.
+// It reports whether the request has been redirected.
+func (h *Handler) redirectLocale(w http.ResponseWriter, r *http.Request) bool {
+	if len(h.Locales) == 0 || r.URL.Path != "/" {
+		return false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	u := url.URL{
+		Path:     h.resolvePackagePath(localizePath("/", h.preferredLocale(r))),
+		RawQuery: r.URL.RawQuery,
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Add("Vary", "Accept-Language, Cookie")
+	http.Redirect(w, r, u.String(), http.StatusFound)
+	return true
+}
+
+// localizedPaths returns the given route paths prefixed by each of the handler
+// locales.
+func (h *Handler) localizedPaths(paths []string) []string {
+	localized := make([]string, 0, len(paths)*len(h.Locales))
+	for _, l := range h.Locales {
+		for _, p := range paths {
+			localized = append(localized, localizePath(p, l))
+		}
+	}
+	return localized
+}
+
+// matchLocale returns the one of the given locales that best matches the
+// given Accept-Language header value, or an empty string when none matches.
+// Languages are matched exactly first, then by their base language. Eg:
+// "de-CH" matches "de".
+func matchLocale(acceptLanguage string, locales []string) string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if q, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			languages = append(languages, language{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(languages, func(a, b int) bool {
+		return languages[a].quality > languages[b].quality
+	})
+
+	for _, lang := range languages {
+		for _, l := range locales {
+			if strings.EqualFold(l, lang.tag) {
+				return l
+			}
+		}
+
+		base := strings.SplitN(lang.tag, "-", 2)[0]
+		for _, l := range locales {
+			if strings.EqualFold(strings.SplitN(l, "-", 2)[0], base) {
+				return l
+			}
+		}
+	}
+	return ""
+}
+
+// loadAppLocales reads the locales of the app and redirects the root path to
+// the preferred locale, which happens when the app is served as a static
+// website.
+func loadAppLocales() {
+	locales := strings.TrimSpace(Getenv("GOAPP_LOCALES"))
+	if locales == "" {
+		return
+	}
+	appLocales = strings.Split(locales, ",")
+
+	u := *Window().URL()
+	path := strings.TrimPrefix(u.Path, rootPrefix)
+	if path != "" && path != "/" {
+		return
+	}
+
+	locale := matchLocale(documentCookie(localeCookieName), appLocales)
+	if locale == "" {
+		locale = matchLocale(navigatorLanguages(), appLocales)
+	}
+	if locale == "" {
+		locale = appLocales[0]
+	}
+
+	u.Path = rootPrefix + localizePath("/", locale)
+	Window().replaceHistory(&u)
+}
+
+// navigatorLanguages returns the browser languages formatted as an
+// Accept-Language header value.
+func navigatorLanguages() string {
+	languages := Window().Get("navigator").Get("languages")
+	if !languages.Truthy() {
+		return ""
+	}
+
+	tags := make([]string, languages.Length())
+	for i := range tags {
+		tags[i] = languages.Index(i).String()
+	}
+	return strings.Join(tags, ",")
+}
+
+// pageLocale returns the locale of the page with the given URL.
+func pageLocale(u *url.URL, locales []string) string {
+	if len(locales) == 0 {
+		return ""
+	}
+	if locale, _ := splitLocalePath(strings.TrimPrefix(u.Path, rootPrefix), locales); locale != "" {
+		return locale
+	}
+	return locales[0]
+}
+
+// persistLocale stores the given locale in a cookie, which is used to choose
+// the locale when the root path is requested.
+func persistLocale(locale string) {
+	c := &http.Cookie{
+		Name:     localeCookieName,
+		Value:    locale,
+		Path:     "/",
+		MaxAge:   int(localeCookieTTL / time.Second),
+		Secure:   Window().URL().Scheme == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	Window().Get("document").Set("cookie", c.String())
+}
+
+// updateDocumentLocale sets the language of the document.
+func updateDocumentLocale(locale string) {
+	if locale == "" {
+		return
+	}
+	Window().Get("document").Get("documentElement").setAttr("lang", locale)
+}
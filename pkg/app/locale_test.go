@@ -0,0 +1,208 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/locale-about", &localeTestCompo{})
+	AddTranslations("de", map[string]string{
+		"Hello, %s!": "Hallo, %s!",
+	})
+	AddTranslations("en", map[string]string{
+		"Goodbye":   "Goodbye!",
+		"Untouched": "",
+	})
+}
+
+type localeTestCompo struct {
+	Compo
+
+	locale   string
+	greeting string
+	about    string
+}
+
+func (c *localeTestCompo) OnPreRender(ctx Context) {
+	c.locale = ctx.Locale()
+	c.greeting = ctx.T("Hello, %s!", "Max") + " " + ctx.T("Goodbye")
+	c.about = ctx.LocalizePath("/locale-about")
+}
+
+func (c *localeTestCompo) Render() UI {
+	return Div().
+		ID("locale-about").
+		DataSet("locale", c.locale).
+		Body(
+			Text(c.greeting),
+			A().Href(c.about),
+		)
+}
+
+func TestSplitLocalePath(t *testing.T) {
+	locales := []string{"en", "de", "pt-BR"}
+
+	utests := []struct {
+		path   string
+		locale string
+		route  string
+	}{
+		{path: "/de/about", locale: "de", route: "/about"},
+		{path: "/de", locale: "de", route: "/"},
+		{path: "/de/", locale: "de", route: "/"},
+		{path: "/pt-br/about", locale: "pt-BR", route: "/about"},
+		{path: "/about", route: "/about"},
+		{path: "/dev/about", route: "/dev/about"},
+		{path: "/", route: "/"},
+	}
+
+	for _, u := range utests {
+		t.Run(u.path, func(t *testing.T) {
+			locale, route := splitLocalePath(u.path, locales)
+			require.Equal(t, u.locale, locale)
+			require.Equal(t, u.route, route)
+		})
+	}
+}
+
+func TestLocalizePath(t *testing.T) {
+	require.Equal(t, "/de/about", localizePath("/about", "de"))
+	require.Equal(t, "/de", localizePath("/", "de"))
+	require.Equal(t, "/about", localizePath("/about", ""))
+}
+
+func TestMatchLocale(t *testing.T) {
+	locales := []string{"en", "de", "fr-CA"}
+
+	utests := []struct {
+		acceptLanguage string
+		locale         string
+	}{
+		{acceptLanguage: "de", locale: "de"},
+		{acceptLanguage: "de-CH, en;q=0.5", locale: "de"},
+		{acceptLanguage: "ja, en;q=0.4, de;q=0.8", locale: "de"},
+		{acceptLanguage: "fr-FR", locale: "fr-CA"},
+		{acceptLanguage: "FR-ca", locale: "fr-CA"},
+		{acceptLanguage: "de;q=0, ja"},
+		{acceptLanguage: "*"},
+		{acceptLanguage: ""},
+	}
+
+	for _, u := range utests {
+		t.Run(u.acceptLanguage, func(t *testing.T) {
+			require.Equal(t, u.locale, matchLocale(u.acceptLanguage, locales))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	locales := []string{"en", "de"}
+
+	require.Equal(t, "Hallo, Max!", translate("de", locales, "Hello, %s!", "Max"))
+	require.Equal(t, "Goodbye!", translate("de", locales, "Goodbye"))
+	require.Equal(t, "Hello, Max!", translate("en", locales, "Hello, %s!", "Max"))
+	require.Equal(t, "Unknown", translate("fr", nil, "Unknown"))
+	require.Equal(t, "Untouched", translate("en", locales, "Untouched"))
+}
+
+func TestHandlerServeLocalizedPage(t *testing.T) {
+	h := Handler{
+		Locales: []string{"en", "de"},
+	}
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("localized route is served", func(t *testing.T) {
+		w := serve("/de/locale-about", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		body := w.Body.String()
+		require.Contains(t, body, `<html lang="de">`)
+		require.Contains(t, body, `data-locale="de"`)
+		require.Contains(t, body, "Hallo, Max! Goodbye!")
+		require.Contains(t, body, `href="/de/locale-about"`)
+	})
+
+	t.Run("unprefixed route is served in the default locale", func(t *testing.T) {
+		w := serve("/locale-about", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		body := w.Body.String()
+		require.Contains(t, body, `<html lang="en">`)
+		require.Contains(t, body, "Hello, Max! Goodbye!")
+	})
+
+	t.Run("root redirects to the accepted language", func(t *testing.T) {
+		w := serve("/?ref=home", http.Header{
+			"Accept-Language": {"de-DE,de;q=0.9,en;q=0.8"},
+		})
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, "/de?ref=home", w.Header().Get("Location"))
+		require.Contains(t, w.Header().Get("Vary"), "Accept-Language")
+	})
+
+	t.Run("root redirects to the persisted locale", func(t *testing.T) {
+		w := serve("/", http.Header{
+			"Accept-Language": {"de"},
+			"Cookie":          {localeCookieName + "=en"},
+		})
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, "/en", w.Header().Get("Location"))
+	})
+
+	t.Run("root redirects to the default locale", func(t *testing.T) {
+		w := serve("/", http.Header{
+			"Accept-Language": {"ja"},
+		})
+		require.Equal(t, http.StatusFound, w.Code)
+		require.Equal(t, "/en", w.Header().Get("Location"))
+	})
+
+	t.Run("env contains locales", func(t *testing.T) {
+		require.Equal(t, "en,de", h.Env["GOAPP_LOCALES"])
+	})
+}
+
+func TestHandlerServeWithoutLocales(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "de")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.NotEqual(t, http.StatusFound, w.Code)
+	require.NotContains(t, w.Body.String(), "<html lang=")
+}
+
+func TestHandlerLocalizedPaths(t *testing.T) {
+	h := Handler{
+		Locales: []string{"en", "de"},
+	}
+
+	require.Equal(t, []string{"/en", "/en/about", "/de", "/de/about"}, h.localizedPaths([]string{"/", "/about"}))
+}
+
+func TestContextLocale(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	ctx := makeContext(compo)
+	require.Empty(t, ctx.Locale())
+	require.Equal(t, "/about", ctx.LocalizePath("/about"))
+	require.Equal(t, "Hello, Max!", ctx.T("Hello, %s!", "Max"))
+}
@@ -0,0 +1,78 @@
+package app
+
+// Mailbox describes how the actions that are posted while no handler is
+// mounted are buffered until a handler is registered.
+type Mailbox struct {
+	// The maximum number of buffered actions.
+	//
+	// Default: 16.
+	Size int
+
+	// The policy applied when an action is posted to a full mailbox.
+	//
+	// Default: DropOldest.
+	Policy MailboxPolicy
+}
+
+// MailboxPolicy represents the policy applied when an action is posted to a
+// full mailbox.
+type MailboxPolicy int
+
+const (
+	// A policy that drops the oldest buffered action to make room for the
+	// posted one.
+	DropOldest MailboxPolicy = iota
+
+	// A policy that drops the posted action.
+	DropNewest
+)
+
+const (
+	defaultMailboxSize = 16
+)
+
+// EnableMailbox enables buffering for the actions with the given name.
+//
+// Actions that are posted while no handler for the name is mounted are kept
+// in the mailbox and are delivered when a handler is registered, typically
+// from the OnMount method of a component that is mounted after a route
+// transition. Delivered actions are removed from the mailbox.
+//
+// It must be called before RunWhenOnBrowser.
+func EnableMailbox(actionName string, m Mailbox) {
+	actionMailboxes[actionName] = m
+}
+
+var actionMailboxes = make(map[string]Mailbox)
+
+type mailbox struct {
+	Mailbox
+
+	actions []Action
+}
+
+func newMailbox(m Mailbox) *mailbox {
+	if m.Size <= 0 {
+		m.Size = defaultMailboxSize
+	}
+	return &mailbox{Mailbox: m}
+}
+
+func (m *mailbox) push(a Action) {
+	if len(m.actions) < m.Size {
+		m.actions = append(m.actions, a)
+		return
+	}
+
+	if m.Policy == DropNewest {
+		return
+	}
+	copy(m.actions, m.actions[1:])
+	m.actions[len(m.actions)-1] = a
+}
+
+func (m *mailbox) pop() []Action {
+	actions := m.actions
+	m.actions = nil
+	return actions
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableMailbox(t *testing.T) {
+	EnableMailbox("/test-mailbox", Mailbox{Size: 2})
+	defer delete(actionMailboxes, "/test-mailbox")
+	require.Equal(t, Mailbox{Size: 2}, actionMailboxes["/test-mailbox"])
+}
+
+func TestMailboxPush(t *testing.T) {
+	t.Run("drop oldest", func(t *testing.T) {
+		m := newMailbox(Mailbox{Size: 2})
+		m.push(Action{Name: "a"})
+		m.push(Action{Name: "b"})
+		m.push(Action{Name: "c"})
+		require.Equal(t, []Action{{Name: "b"}, {Name: "c"}}, m.pop())
+		require.Empty(t, m.actions)
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		m := newMailbox(Mailbox{Size: 2, Policy: DropNewest})
+		m.push(Action{Name: "a"})
+		m.push(Action{Name: "b"})
+		m.push(Action{Name: "c"})
+		require.Equal(t, []Action{{Name: "a"}, {Name: "b"}}, m.pop())
+	})
+
+	t.Run("default size", func(t *testing.T) {
+		m := newMailbox(Mailbox{})
+		require.Equal(t, defaultMailboxSize, m.Size)
+	})
+}
+
+func TestActionManagerMailbox(t *testing.T) {
+	e := engine{
+		ActionMailboxes: map[string]Mailbox{
+			"cart.add": {Size: 2},
+		},
+	}
+	e.init()
+	defer e.Close()
+
+	e.Mount(Div())
+	e.Consume()
+
+	e.Post(Action{Name: "cart.add", Value: 1})
+	e.Post(Action{Name: "user.login"})
+	e.Wait()
+	require.Len(t, e.actions.mailboxes["cart.add"].actions, 1)
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var values []interface{}
+	makeContext(h).Handle("cart.*", func(ctx Context, a Action) {
+		values = append(values, a.Value)
+	})
+	e.Consume()
+	require.Equal(t, []interface{}{1}, values)
+	require.Empty(t, e.actions.mailboxes["cart.add"].actions)
+
+	e.Post(Action{Name: "cart.add", Value: 2})
+	e.Wait()
+	e.Consume()
+	require.Equal(t, []interface{}{1, 2}, values)
+	require.Empty(t, e.actions.mailboxes["cart.add"].actions)
+}
@@ -0,0 +1,296 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// The name of the action posted when the maintenance mode of the server
+	// changes. The action value is a bool that reports whether the server is
+	// under maintenance.
+	MaintenanceAction = "/app/maintenance"
+
+	maintenanceEndpoint          = "/app-maintenance"
+	maintenanceBannerID          = "app-maintenance-banner"
+	defaultMaintenanceMessage    = "Under maintenance"
+	defaultMaintenanceRetryAfter = 5 * time.Minute
+)
+
+// Maintenance describes the maintenance mode of a Handler.
+//
+// While under maintenance, page requests are answered with a 503 status code
+// and a prerendered page that displays the maintenance component, without
+// loading the app. The page is reloaded once the maintenance is over. Static
+// resources are still served.
+//
+// Running apps that are notified display a banner with the maintenance
+// message and block in-app navigation until the maintenance is over.
+//
+// eg:
+//  h := &app.Handler{
+//      Maintenance: app.Maintenance{
+//          Message:       "We are upgrading the shop, be right back!",
+//          NotifyClients: true,
+//      },
+//  }
+//  // ...
+//  h.SetMaintenance(true)
+type Maintenance struct {
+	// Reports whether the Handler starts under maintenance. Use
+	// Handler.SetMaintenance to change the maintenance mode while serving.
+	Enabled bool
+
+	// The component displayed in place of the pages. A new instance is
+	// created for each request.
+	//
+	// Default: a page that displays the app icon and Message.
+	Component Composer
+
+	// The message displayed by the default maintenance component and by the
+	// banner of running apps.
+	//
+	// Default: "Under maintenance".
+	Message string
+
+	// The duration sent in the Retry-After header of the pages served under
+	// maintenance.
+	//
+	// Default: 5 minutes.
+	RetryAfter time.Duration
+
+	// Reports whether running apps are notified when the maintenance mode
+	// changes. Each app then keeps a server-sent events connection open to
+	// the Handler.
+	NotifyClients bool
+}
+
+type maintenanceState struct {
+	mutex       sync.Mutex
+	enabled     bool
+	closed      bool
+	subscribers map[chan bool]struct{}
+}
+
+func (s *maintenanceState) isEnabled() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.enabled
+}
+
+func (s *maintenanceState) set(enabled bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.enabled == enabled {
+		return
+	}
+	s.enabled = enabled
+
+	for c := range s.subscribers {
+		select {
+		case c <- enabled:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel that receives the maintenance mode each time it
+// changes. The channel is closed when the state is closed.
+func (s *maintenanceState) subscribe() (c chan bool, unsubscribe func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c = make(chan bool, 1)
+	if s.closed {
+		close(c)
+		return c, func() {}
+	}
+
+	s.subscribers[c] = struct{}{}
+	return c, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if _, ok := s.subscribers[c]; ok {
+			delete(s.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+// close closes the subscriber channels in order to end the event streams.
+func (s *maintenanceState) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	for c := range s.subscribers {
+		delete(s.subscribers, c)
+		close(c)
+	}
+}
+
+func (h *Handler) initMaintenance() {
+	if h.Maintenance.Message == "" {
+		h.Maintenance.Message = defaultMaintenanceMessage
+	}
+	if h.Maintenance.RetryAfter <= 0 {
+		h.Maintenance.RetryAfter = defaultMaintenanceRetryAfter
+	}
+
+	h.maintenance = &maintenanceState{
+		enabled:     h.Maintenance.Enabled,
+		subscribers: make(map[chan bool]struct{}),
+	}
+
+	if h.Maintenance.NotifyClients {
+		if h.Env == nil {
+			h.Env = make(Environment)
+		}
+		h.Env["GOAPP_MAINTENANCE_URL"] = h.resolvePackagePath(maintenanceEndpoint)
+	}
+}
+
+// SetMaintenance sets whether the handler, and its tenants, are under
+// maintenance. Notified apps are informed of the change.
+func (h *Handler) SetMaintenance(enabled bool) {
+	h.once.Do(h.init)
+
+	h.maintenance.set(enabled)
+	for _, t := range h.tenants {
+		t.SetMaintenance(enabled)
+	}
+}
+
+// IsUnderMaintenance reports whether the handler is under maintenance.
+func (h *Handler) IsUnderMaintenance() bool {
+	h.once.Do(h.init)
+	return h.maintenance.isEnabled()
+}
+
+// maintenanceComponent returns a new instance of the component displayed
+// under maintenance.
+func (h *Handler) maintenanceComponent() Composer {
+	if h.Maintenance.Component == nil {
+		return &maintenancePage{
+			Icon:    h.Icon.Default,
+			Message: h.Maintenance.Message,
+		}
+	}
+	return reflect.New(reflect.TypeOf(h.Maintenance.Component).Elem()).Interface().(Composer)
+}
+
+// maintenanceScript returns the script that reloads the maintenance page once
+// the maintenance is over.
+func (h *Handler) maintenanceScript() string {
+	return fmt.Sprintf(`<script>if ("EventSource" in window) { new EventSource(%q).onmessage = (e) => { if (e.data === "false") { location.reload(); } }; }</script>`,
+		h.resolvePackagePath(maintenanceEndpoint),
+	)
+}
+
+func (h *Handler) setMaintenanceHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", strconv.Itoa(int(h.Maintenance.RetryAfter.Seconds())))
+}
+
+func (h *Handler) serveMaintenanceEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c, unsubscribe := h.maintenance.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(enabled bool) {
+		fmt.Fprintf(w, "data: %t\n\n", enabled)
+		flusher.Flush()
+	}
+	send(h.maintenance.isEnabled())
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case enabled, ok := <-c:
+			if !ok {
+				return
+			}
+			send(enabled)
+		}
+	}
+}
+
+// startMaintenanceEvents listens to the maintenance mode of the server when
+// notifications are enabled.
+func startMaintenanceEvents(d Dispatcher) {
+	url := Getenv("GOAPP_MAINTENANCE_URL")
+	if url == "" || !Window().Get("EventSource").Truthy() {
+		return
+	}
+
+	source := Window().Get("EventSource").New(url)
+	source.Call("addEventListener", "message", FuncOf(func(this Value, args []Value) interface{} {
+		enabled := promiseArg(args).Get("data").String() == "true"
+		d.Dispatch(Dispatch{
+			Mode: Update,
+			Function: func(ctx Context) {
+				setMaintenanceMode(d, enabled)
+			},
+		})
+		return nil
+	}))
+}
+
+func setMaintenanceMode(d Dispatcher, enabled bool) {
+	if maintenanceMode == enabled {
+		return
+	}
+	maintenanceMode = enabled
+
+	if banner := Window().GetElementByID(maintenanceBannerID); banner.Truthy() {
+		banner.Set("hidden", !enabled)
+	}
+	d.Post(Action{
+		Name:  MaintenanceAction,
+		Value: enabled,
+	})
+
+	if enabled {
+		Log("server is under maintenance, in-app navigation is blocked")
+	}
+}
+
+type maintenancePage struct {
+	Compo
+
+	Icon    string
+	Message string
+}
+
+func (p *maintenancePage) Render() UI {
+	return Div().
+		Class("goapp-app-info").
+		Body(
+			If(p.Icon != "",
+				Img().
+					Class("goapp-logo").
+					Alt("maintenance").
+					Src(p.Icon),
+			),
+			P().
+				Class("goapp-label").
+				Text(p.Message),
+		)
+}
@@ -0,0 +1,181 @@
+//go:build !wasm
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type maintenanceTestCompo struct {
+	Compo
+}
+
+func (c *maintenanceTestCompo) Render() UI {
+	return Div().ID("custom-maintenance")
+}
+
+func TestHandlerMaintenance(t *testing.T) {
+	h := Handler{
+		Maintenance: Maintenance{
+			Message: "Back soon",
+		},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `id="pre-render-ok"`)
+	require.False(t, h.IsUnderMaintenance())
+
+	h.SetMaintenance(true)
+	require.True(t, h.IsUnderMaintenance())
+
+	w = serve("/")
+	body := w.Body.String()
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Equal(t, "300", w.Header().Get("Retry-After"))
+	require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	require.Contains(t, body, "Back soon")
+	require.Contains(t, body, `new EventSource("/app-maintenance")`)
+	require.NotContains(t, body, `id="pre-render-ok"`)
+	require.NotContains(t, body, `/app.js"`)
+	require.NotContains(t, body, `id="app-wasm-loader"`)
+	require.NotContains(t, body, maintenanceBannerID)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", h.etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	require.Equal(t, http.StatusOK, serve("/app.css").Code)
+	require.Equal(t, http.StatusOK, serve("/app.js").Code)
+
+	h.SetMaintenance(false)
+	w = serve("/")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `id="pre-render-ok"`)
+}
+
+func TestHandlerMaintenanceComponent(t *testing.T) {
+	h := Handler{
+		Maintenance: Maintenance{
+			Enabled:   true,
+			Component: &maintenanceTestCompo{},
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Contains(t, w.Body.String(), `id="custom-maintenance"`)
+	require.NotContains(t, w.Body.String(), defaultMaintenanceMessage)
+}
+
+func TestHandlerMaintenanceNotifyClients(t *testing.T) {
+	h := Handler{
+		Maintenance: Maintenance{
+			NotifyClients: true,
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	body := w.Body.String()
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, body, `id="app-maintenance-banner"`)
+	require.Contains(t, body, defaultMaintenanceMessage)
+	require.Equal(t, "/app-maintenance", h.Env["GOAPP_MAINTENANCE_URL"])
+}
+
+func TestHandlerServeMaintenanceEvents(t *testing.T) {
+	h := &Handler{}
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	res, err := http.Get(s.URL + maintenanceEndpoint)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	events := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		line, err := events.ReadString('\n')
+		require.NoError(t, err)
+		_, err = events.ReadString('\n')
+		require.NoError(t, err)
+		return line
+	}
+
+	require.Equal(t, "data: false\n", readEvent())
+
+	h.SetMaintenance(true)
+	require.Equal(t, "data: true\n", readEvent())
+
+	h.SetMaintenance(false)
+	require.Equal(t, "data: false\n", readEvent())
+
+	err = h.Shutdown(context.Background())
+	require.NoError(t, err)
+	_, err = events.ReadString('\n')
+	require.Error(t, err)
+}
+
+func TestMaintenanceState(t *testing.T) {
+	s := &maintenanceState{subscribers: make(map[chan bool]struct{})}
+
+	c, unsubscribe := s.subscribe()
+	s.set(true)
+	require.True(t, s.isEnabled())
+	require.True(t, <-c)
+
+	s.set(true)
+	require.Len(t, c, 0)
+
+	unsubscribe()
+	_, ok := <-c
+	require.False(t, ok)
+	unsubscribe()
+
+	c, _ = s.subscribe()
+	s.close()
+	_, ok = <-c
+	require.False(t, ok)
+
+	c, unsubscribe = s.subscribe()
+	_, ok = <-c
+	require.False(t, ok)
+	unsubscribe()
+}
+
+func TestSetMaintenanceMode(t *testing.T) {
+	defer func() {
+		maintenanceMode = false
+	}()
+
+	d := NewClientTester(&hello{})
+	defer d.Close()
+
+	setMaintenanceMode(d, true)
+	require.True(t, maintenanceMode)
+
+	setMaintenanceMode(d, false)
+	require.False(t, maintenanceMode)
+}
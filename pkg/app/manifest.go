@@ -0,0 +1,80 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Shortcut describes a shortcut to a key task of the app, which is displayed
+// in the context menu of the installed app icon.
+//
+// When the Handler has locales, the names and the description are translated
+// with the translations registered with AddTranslations, and the URL is
+// prefixed by the locale of the manifest.
+type Shortcut struct {
+	// The name of the shortcut.
+	Name string
+
+	// The name of the shortcut displayed when there is not enough space to
+	// display Name.
+	ShortName string
+
+	// The description of the task performed by the shortcut.
+	Description string
+
+	// The path of the page opened by the shortcut. eg: "/notes/new".
+	URL string
+}
+
+type manifestShortcut struct {
+	Name        string `json:"name"`
+	ShortName   string `json:"short_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+func (h *Handler) makeManifestShortcuts(locale string) []manifestShortcut {
+	shortcuts := make([]manifestShortcut, 0, len(h.Shortcuts))
+	for _, s := range h.Shortcuts {
+		url := s.URL
+		if strings.HasPrefix(url, "/") {
+			url = localizePath(url, locale)
+		}
+
+		shortcuts = append(shortcuts, manifestShortcut{
+			Name:        translate(locale, h.Locales, s.Name),
+			ShortName:   translate(locale, h.Locales, s.ShortName),
+			Description: translate(locale, h.Locales, s.Description),
+			URL:         url,
+		})
+	}
+	return shortcuts
+}
+
+// manifestPath returns the path of the manifest of the given locale. Eg: the
+// manifest of "de" is served at "/manifest.de.webmanifest".
+func manifestPath(locale string) string {
+	if locale == "" {
+		return "/manifest.webmanifest"
+	}
+	return "/manifest." + locale + ".webmanifest"
+}
+
+// requestManifestPath returns the path of the manifest that is served for a
+// request to "/manifest.webmanifest". It is the manifest of the locale
+// persisted with Context.SetLocale, or of the one that best matches the
+// Accept-Language header, when the Handler has locales.
+func (h *Handler) requestManifestPath(r *http.Request) string {
+	if len(h.Locales) == 0 {
+		return manifestPath("")
+	}
+	return manifestPath(h.preferredLocale(r))
+}
+
+// manifestString returns the given string escaped to be written within a JSON
+// string of the manifest.
+func manifestString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
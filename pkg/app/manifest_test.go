@@ -0,0 +1,138 @@
+//go:build !wasm
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	AddTranslations("fr", map[string]string{
+		"Manifest test":               "Test du manifeste",
+		"Takes notes":                 "Prend des notes",
+		"Manifest test new note":      "Nouvelle note",
+		"Manifest test new note desc": "Crée une note",
+	})
+}
+
+func TestHandlerServeLocalizedManifest(t *testing.T) {
+	h := Handler{
+		Name:        "Manifest test",
+		ShortName:   `Manifest "test"`,
+		Description: "Takes notes",
+		Locales:     []string{"en", "fr"},
+		Shortcuts: []Shortcut{
+			{
+				Name:        "Manifest test new note",
+				Description: "Manifest test new note desc",
+				URL:         "/notes/new",
+			},
+			{
+				Name: "Manifest test docs",
+				URL:  "https://docs.murlok.io",
+			},
+		},
+	}
+
+	serve := func(path, acceptLanguage string) manifestTestJSON {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptLanguage != "" {
+			r.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/manifest+json", w.Header().Get("Content-Type"))
+
+		var m manifestTestJSON
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+		return m
+	}
+
+	t.Run("locale manifest is translated", func(t *testing.T) {
+		m := serve("/manifest.fr.webmanifest", "")
+		require.Equal(t, "Test du manifeste", m.Name)
+		require.Equal(t, `Manifest "test"`, m.ShortName)
+		require.Equal(t, "Prend des notes", m.Description)
+		require.Equal(t, "fr", m.Lang)
+		require.Equal(t, "/fr/", m.StartURL)
+		require.Equal(t, "/", m.Scope)
+		require.Equal(t, []manifestShortcut{
+			{
+				Name:        "Nouvelle note",
+				Description: "Crée une note",
+				URL:         "/fr/notes/new",
+			},
+			{
+				Name: "Manifest test docs",
+				URL:  "https://docs.murlok.io",
+			},
+		}, m.Shortcuts)
+	})
+
+	t.Run("default locale manifest is not translated", func(t *testing.T) {
+		m := serve("/manifest.en.webmanifest", "")
+		require.Equal(t, "Manifest test", m.Name)
+		require.Equal(t, "en", m.Lang)
+		require.Equal(t, "/en/", m.StartURL)
+		require.Equal(t, "/en/notes/new", m.Shortcuts[0].URL)
+	})
+
+	t.Run("manifest is selected with accept language", func(t *testing.T) {
+		m := serve("/manifest.webmanifest", "fr-FR,fr;q=0.9")
+		require.Equal(t, "fr", m.Lang)
+		require.Equal(t, "Test du manifeste", m.Name)
+
+		m = serve("/manifest.json", "")
+		require.Equal(t, "en", m.Lang)
+	})
+
+	t.Run("page links the manifest of its locale", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/fr", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `href="/manifest.fr.webmanifest"`)
+	})
+
+	t.Run("service worker caches locale manifests", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app-worker.js", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Contains(t, w.Body.String(), `"/manifest.fr.webmanifest"`)
+		require.Contains(t, w.Body.String(), `"/manifest.en.webmanifest"`)
+	})
+}
+
+func TestHandlerServeManifestWithoutLocales(t *testing.T) {
+	h := Handler{
+		Name: "Manifest test",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	r.Header.Set("Accept-Language", "fr")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var m manifestTestJSON
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	require.Equal(t, "Manifest test", m.Name)
+	require.Empty(t, m.Lang)
+	require.Empty(t, m.Shortcuts)
+}
+
+type manifestTestJSON struct {
+	Name        string             `json:"name"`
+	ShortName   string             `json:"short_name"`
+	Description string             `json:"description"`
+	Lang        string             `json:"lang"`
+	Scope       string             `json:"scope"`
+	StartURL    string             `json:"start_url"`
+	Shortcuts   []manifestShortcut `json:"shortcuts"`
+}
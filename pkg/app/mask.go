@@ -0,0 +1,241 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Mask is a pattern that formats the value of an input while it is typed. In
+// a pattern, '9' is a digit, 'a' is a letter and '*' is a letter or a digit.
+// Other characters are literals that are inserted as the value is typed. A
+// character preceded by a backslash is a literal. Eg: "(999) 999-9999".
+type Mask string
+
+const (
+	// PhoneMask formats a North American phone number.
+	PhoneMask Mask = "(999) 999-9999"
+
+	// CreditCardMask formats a 16 digits credit card number.
+	CreditCardMask Mask = "9999 9999 9999 9999"
+
+	// DateMask formats a date such as 12/31/2021.
+	DateMask Mask = "99/99/9999"
+
+	// TimeMask formats a time such as 23:59.
+	TimeMask Mask = "99:99"
+)
+
+// Format returns the given value formatted with the mask. Characters that do
+// not fit the pattern are dropped and the literals are only inserted before a
+// typed character, which lets users delete them with backspace.
+func (m Mask) Format(v string) string {
+	formatted, _ := m.apply(v)
+	return formatted
+}
+
+// Raw returns the characters of the given value that fill the pattern of the
+// mask, without the literals. Eg: PhoneMask.Raw("(555) 123-4567") returns
+// "5551234567".
+func (m Mask) Raw(v string) string {
+	_, raw := m.apply(v)
+	return raw
+}
+
+// Attach sets an input event handler that formats the value of the given
+// HTML element with the mask and returns the element. The input event handler
+// that is already set on the element is called after the value is formatted,
+// which means that Attach must be called after OnInput. Eg:
+//  app.PhoneMask.Attach(
+//      app.Input().
+//          Type("tel").
+//          Value(c.phone).
+//          OnInput(c.ValueTo(&c.phone)),
+//  )
+//
+// The caret of a focused input is kept after the characters typed before it,
+// both when the value is formatted and when an updated value is rendered.
+//
+// It panics when the element is not an HTML element.
+func (m Mask) Attach(elem UI) UI {
+	e, ok := elem.(interface {
+		setEventHandler(k string, h EventHandler, scope ...interface{})
+		setAttr(k string, v interface{})
+	})
+	if !ok {
+		panic(errors.New("attaching mask failed").
+			Tag("reason", "not an html element").
+			Tag("type", fmt.Sprintf("%T", elem)).
+			Tag("mask", m),
+		)
+	}
+
+	attrs := elem.attributes()
+	if v, ok := attrs["value"]; ok {
+		e.setAttr("value", m.Format(v))
+	}
+	if _, ok := attrs["inputmode"]; !ok && m.isNumeric() {
+		e.setAttr("inputmode", "numeric")
+	}
+
+	h := maskedInput{
+		mask: m,
+		next: elem.eventHandlers()["input"].value,
+	}
+	e.setEventHandler("input", h.onInput, string(m))
+	return elem
+}
+
+// apply returns the value formatted with the mask and the characters that
+// fill its pattern.
+func (m Mask) apply(v string) (string, string) {
+	var formatted, raw strings.Builder
+	var literals strings.Builder
+
+	input := []rune(v)
+	pattern := []rune(string(m))
+
+	for i := 0; i < len(pattern) && len(input) != 0; i++ {
+		p := pattern[i]
+
+		if p == '\\' && i+1 < len(pattern) {
+			i++
+			p = pattern[i]
+			literals.WriteRune(p)
+			if input[0] == p {
+				input = input[1:]
+			}
+			continue
+		}
+
+		if !isMaskSlot(p) {
+			literals.WriteRune(p)
+			if input[0] == p {
+				input = input[1:]
+			}
+			continue
+		}
+
+		for len(input) != 0 && !matchMaskSlot(p, input[0]) {
+			input = input[1:]
+		}
+		if len(input) == 0 {
+			break
+		}
+
+		formatted.WriteString(literals.String())
+		literals.Reset()
+		formatted.WriteRune(input[0])
+		raw.WriteRune(input[0])
+		input = input[1:]
+	}
+
+	return formatted.String(), raw.String()
+}
+
+func (m Mask) isNumeric() bool {
+	numeric := false
+	pattern := []rune(string(m))
+
+	for i := 0; i < len(pattern); i++ {
+		switch p := pattern[i]; {
+		case p == '\\':
+			i++
+
+		case p == '9':
+			numeric = true
+
+		case isMaskSlot(p):
+			return false
+		}
+	}
+	return numeric
+}
+
+func isMaskSlot(p rune) bool {
+	return p == '9' || p == 'a' || p == '*'
+}
+
+func matchMaskSlot(p, r rune) bool {
+	switch p {
+	case '9':
+		return unicode.IsDigit(r)
+
+	case 'a':
+		return unicode.IsLetter(r)
+
+	default:
+		return unicode.IsDigit(r) || unicode.IsLetter(r)
+	}
+}
+
+type maskedInput struct {
+	mask Mask
+	next EventHandler
+}
+
+func (m maskedInput) onInput(ctx Context, e Event) {
+	input := ctx.JSSrc()
+	setInputValue(input, m.mask.Format(input.Get("value").String()))
+
+	if m.next != nil {
+		m.next(ctx, e)
+	}
+}
+
+// setInputValue sets the value of the given form element. When the element is
+// focused, its caret is moved after the same number of letters and digits as
+// before the value is set, which prevents it from jumping to the end when the
+// value is reformatted.
+func setInputValue(elem Value, v string) {
+	current := elem.Get("value").String()
+	if current == v {
+		return
+	}
+
+	start := elem.Get("selectionStart")
+	if start.IsNull() || start.IsUndefined() || !elem.Call("matches", ":focus").Bool() {
+		elem.Set("value", v)
+		return
+	}
+
+	n := countCaretRunes(current, start.Int())
+	elem.Set("value", v)
+	caret := caretOffset(v, n)
+	elem.Call("setSelectionRange", caret, caret)
+}
+
+// countCaretRunes returns the number of letters and digits before the given
+// UTF-16 offset of s.
+func countCaretRunes(s string, offset int) int {
+	count := 0
+	for _, r := range []rune(s) {
+		if offset <= 0 {
+			break
+		}
+		offset -= len(utf16.Encode([]rune{r}))
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// caretOffset returns the UTF-16 offset of s that follows its nth letter or
+// digit.
+func caretOffset(s string, n int) int {
+	offset := 0
+	for _, r := range []rune(s) {
+		if n <= 0 {
+			break
+		}
+		offset += len(utf16.Encode([]rune{r}))
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n--
+		}
+	}
+	return offset
+}
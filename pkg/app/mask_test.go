@@ -0,0 +1,138 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskFormat(t *testing.T) {
+	utests := []struct {
+		scenario string
+		mask     Mask
+		value    string
+		expected string
+		raw      string
+	}{
+		{
+			scenario: "empty value",
+			mask:     PhoneMask,
+		},
+		{
+			scenario: "phone is formatted while typed",
+			mask:     PhoneMask,
+			value:    "5551",
+			expected: "(555) 1",
+			raw:      "5551",
+		},
+		{
+			scenario: "trailing literals are not inserted",
+			mask:     PhoneMask,
+			value:    "555",
+			expected: "(555",
+			raw:      "555",
+		},
+		{
+			scenario: "formatted phone is unchanged",
+			mask:     PhoneMask,
+			value:    "(555) 123-4567",
+			expected: "(555) 123-4567",
+			raw:      "5551234567",
+		},
+		{
+			scenario: "extra characters are dropped",
+			mask:     DateMask,
+			value:    "12/31/20211",
+			expected: "12/31/2021",
+			raw:      "12312021",
+		},
+		{
+			scenario: "invalid characters are dropped",
+			mask:     CreditCardMask,
+			value:    "4242-42x42 4",
+			expected: "4242 4242 4",
+			raw:      "424242424",
+		},
+		{
+			scenario: "pasted value is formatted",
+			mask:     CreditCardMask,
+			value:    "4242424242424242",
+			expected: "4242 4242 4242 4242",
+			raw:      "4242424242424242",
+		},
+		{
+			scenario: "letters and alphanumerics",
+			mask:     "aa-**",
+			value:    "ab1c2",
+			expected: "ab-1c",
+			raw:      "ab1c",
+		},
+		{
+			scenario: "literal digits are skipped",
+			mask:     `+\1 999`,
+			value:    "+1 23",
+			expected: "+1 23",
+			raw:      "23",
+		},
+		{
+			scenario: "literal digits are inserted",
+			mask:     `+\1 999`,
+			value:    "23",
+			expected: "+1 23",
+			raw:      "23",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			require.Equal(t, u.expected, u.mask.Format(u.value))
+			require.Equal(t, u.raw, u.mask.Raw(u.value))
+		})
+	}
+}
+
+func TestMaskIsNumeric(t *testing.T) {
+	require.True(t, PhoneMask.isNumeric())
+	require.True(t, Mask(`\a99`).isNumeric())
+	require.False(t, Mask("99-aa").isNumeric())
+	require.False(t, Mask("--").isNumeric())
+}
+
+func TestMaskAttach(t *testing.T) {
+	var typed string
+	input := PhoneMask.Attach(Input().
+		Value("5551234567").
+		OnInput(func(ctx Context, e Event) {
+			typed = ctx.JSSrc().Get("value").String()
+		}))
+	require.Equal(t, "(555) 123-4567", input.attributes()["value"])
+	require.Equal(t, "numeric", input.attributes()["inputmode"])
+
+	d := NewClientTester(input)
+	defer d.Close()
+
+	setSimulatedProperty(input, "value", "55512")
+	h := input.eventHandlers()["input"]
+	d.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: input,
+		Function: func(ctx Context) {
+			h.value(ctx, Event{Value: newSimulatedEvent("input", nil)})
+		},
+	})
+	d.Consume()
+	require.Equal(t, "(555) 12", typed)
+
+	require.Panics(t, func() {
+		PhoneMask.Attach(Text("hello"))
+	})
+}
+
+func TestCaretOffset(t *testing.T) {
+	require.Equal(t, 3, countCaretRunes("555 1", 4))
+	require.Equal(t, 4, caretOffset("(555) 1", 3))
+	require.Equal(t, 7, caretOffset("(555) 1", 4))
+	require.Equal(t, 0, caretOffset("(555) 1", 0))
+	require.Equal(t, 2, countCaretRunes("🙂ab", 4))
+	require.Equal(t, 4, caretOffset("🙂ab", 2))
+}
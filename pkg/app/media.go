@@ -0,0 +1,208 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	mediaWorkerEndpoint = "/app-media-worker.js"
+)
+
+// ImageOptions describes how an image is processed with Context.ProcessImage.
+//
+// The image is re-encoded, which strips its EXIF metadata such as the GPS
+// location. Its orientation metadata is applied before being stripped.
+type ImageOptions struct {
+	// The maximum width of the image in px. The image is downscaled to fit
+	// while keeping its aspect ratio. No limit is enforced when zero.
+	MaxWidth int
+
+	// The maximum height of the image in px. The image is downscaled to fit
+	// while keeping its aspect ratio. No limit is enforced when zero.
+	MaxHeight int
+
+	// The clockwise rotation of the image in degrees. It must be a multiple of
+	// 90.
+	Rotate int
+
+	// The content type of the processed image. eg: "image/webp".
+	//
+	// Default: "image/jpeg".
+	Type string
+
+	// The quality of the processed image, ranging from 0 to 1. It is only used
+	// by lossy formats.
+	//
+	// Default: 0.9.
+	Quality float64
+
+	// The function called on the UI goroutine when the image is processed.
+	OnDone func(Context, ProcessedFile, error)
+}
+
+// PDFPageOptions describes how a PDF page is rendered with
+// Context.RenderPDFPage.
+type PDFPageOptions struct {
+	// The URL of the pdf.js script that renders the page. It is loaded in the
+	// worker and must be a build that exposes the pdfjsLib global. eg:
+	// "/web/pdfjs/pdf.min.js".
+	PDFJS string
+
+	// The number of the page to render, starting at 1.
+	//
+	// Default: 1.
+	Page int
+
+	// The scale at which the page is rendered. A scale of 1 renders the page
+	// at 72 DPI.
+	//
+	// Default: 1.5.
+	Scale float64
+
+	// The content type of the rendered image.
+	//
+	// Default: "image/png".
+	Type string
+
+	// The quality of the rendered image, ranging from 0 to 1. It is only used
+	// by lossy formats.
+	//
+	// Default: 0.9.
+	Quality float64
+
+	// The function called on the UI goroutine when the page is rendered.
+	OnDone func(Context, ProcessedFile, error)
+}
+
+// ProcessedFile describes a file produced by the media worker.
+type ProcessedFile struct {
+	// The JavaScript File that contains the processed image. It can be
+	// uploaded with Context.Upload.
+	File Value
+
+	// The width of the image in px.
+	Width int
+
+	// The height of the image in px.
+	Height int
+}
+
+var (
+	mediaWorkers mediaWorker
+)
+
+// mediaWorker sends image and PDF processing jobs to a dedicated web worker in
+// order to not block the UI thread. The worker is started with the first job.
+type mediaWorker struct {
+	worker   Value
+	nextID   int
+	handlers map[int]func(Value)
+}
+
+func (w *mediaWorker) post(op string, file Value, options map[string]interface{}, onResult func(Value)) error {
+	if !Window().Get("Worker").Truthy() || !Window().Get("OffscreenCanvas").Truthy() {
+		return errors.New("media workers are not supported")
+	}
+
+	if w.worker == nil {
+		w.handlers = make(map[int]func(Value))
+		w.worker = Window().Get("Worker").New(rootPrefix + mediaWorkerEndpoint)
+		w.worker.Set("onmessage", FuncOf(func(this Value, args []Value) interface{} {
+			data := args[0].Get("data")
+			id := data.Get("id").Int()
+			if h, ok := w.handlers[id]; ok {
+				delete(w.handlers, id)
+				h(data)
+			}
+			return nil
+		}))
+	}
+
+	w.nextID++
+	w.handlers[w.nextID] = onResult
+	w.worker.Call("postMessage", map[string]interface{}{
+		"id":      w.nextID,
+		"op":      op,
+		"file":    file,
+		"options": options,
+	})
+	return nil
+}
+
+func processMedia(ctx Context, op string, file Value, options map[string]interface{}, onDone func(Context, ProcessedFile, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(f ProcessedFile, err error) {
+		if onDone == nil {
+			if err != nil {
+				Log(err)
+			}
+			return
+		}
+		ctx.Dispatch(func(ctx Context) {
+			onDone(ctx, f, err)
+		})
+	}
+
+	err := mediaWorkers.post(op, file, options, func(res Value) {
+		if msg := res.Get("error"); msg.Truthy() {
+			done(ProcessedFile{}, errors.New("processing media failed").
+				Tag("operation", op).
+				Tag("reason", msg.String()))
+			return
+		}
+
+		done(ProcessedFile{
+			File:   res.Get("file"),
+			Width:  res.Get("width").Int(),
+			Height: res.Get("height").Int(),
+		}, nil)
+	})
+	if err != nil {
+		done(ProcessedFile{}, errors.New("processing media failed").
+			Tag("operation", op).
+			Wrap(err))
+	}
+}
+
+func imageWorkerOptions(opts ImageOptions) map[string]interface{} {
+	if opts.Type == "" {
+		opts.Type = "image/jpeg"
+	}
+	if opts.Quality <= 0 {
+		opts.Quality = 0.9
+	}
+
+	return map[string]interface{}{
+		"maxWidth":  opts.MaxWidth,
+		"maxHeight": opts.MaxHeight,
+		"rotate":    opts.Rotate,
+		"type":      opts.Type,
+		"quality":   opts.Quality,
+	}
+}
+
+func pdfPageWorkerOptions(opts PDFPageOptions) map[string]interface{} {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	if opts.Scale <= 0 {
+		opts.Scale = 1.5
+	}
+	if opts.Type == "" {
+		opts.Type = "image/png"
+	}
+	if opts.Quality <= 0 {
+		opts.Quality = 0.9
+	}
+
+	return map[string]interface{}{
+		"pdfjs":   opts.PDFJS,
+		"page":    opts.Page,
+		"scale":   opts.Scale,
+		"type":    opts.Type,
+		"quality": opts.Quality,
+	}
+}
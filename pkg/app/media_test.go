@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageWorkerOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		require.Equal(t, map[string]interface{}{
+			"maxWidth":  0,
+			"maxHeight": 0,
+			"rotate":    0,
+			"type":      "image/jpeg",
+			"quality":   0.9,
+		}, imageWorkerOptions(ImageOptions{}))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		require.Equal(t, map[string]interface{}{
+			"maxWidth":  1920,
+			"maxHeight": 1080,
+			"rotate":    90,
+			"type":      "image/webp",
+			"quality":   0.5,
+		}, imageWorkerOptions(ImageOptions{
+			MaxWidth:  1920,
+			MaxHeight: 1080,
+			Rotate:    90,
+			Type:      "image/webp",
+			Quality:   0.5,
+		}))
+	})
+}
+
+func TestPDFPageWorkerOptions(t *testing.T) {
+	require.Equal(t, map[string]interface{}{
+		"pdfjs":   "/web/pdf.min.js",
+		"page":    1,
+		"scale":   1.5,
+		"type":    "image/png",
+		"quality": 0.9,
+	}, pdfPageWorkerOptions(PDFPageOptions{PDFJS: "/web/pdf.min.js"}))
+}
+
+func TestProcessImageOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	makeContext(h).ProcessImage(Null(), ImageOptions{
+		OnDone: func(Context, ProcessedFile, error) {
+			called = true
+		},
+	})
+	d.Consume()
+	require.False(t, called)
+	require.Nil(t, mediaWorkers.worker)
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"net"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The size of the WebAssembly memory above which a warning is logged when
+	// the app is served from a local host. Mobile browsers commonly crash
+	// instances that grow beyond 512MB.
+	memoryWarningThreshold = 400 << 20
+
+	memoryCheckInterval = 30 * time.Second
+)
+
+// MemoryStats describes the memory used by the app.
+type MemoryStats struct {
+	// The bytes of allocated heap objects.
+	HeapAlloc uint64
+
+	// The bytes of heap memory obtained from the system.
+	HeapSys uint64
+
+	// The number of allocated heap objects.
+	HeapObjects uint64
+
+	// The total bytes of memory obtained from the system by the Go runtime.
+	Sys uint64
+
+	// The number of completed garbage collection cycles.
+	NumGC uint32
+
+	// The size of the WebAssembly linear memory, in bytes. It never shrinks
+	// and is the memory the browser accounts for the app instance. It is 0 on
+	// the server.
+	WasmMemory uint64
+
+	// The bytes of JavaScript heap in use, as reported by the non-standard
+	// performance.memory API. It is 0 when the API is not available.
+	JSHeapUsed uint64
+
+	// The maximum size of the JavaScript heap, as reported by the
+	// non-standard performance.memory API. It is 0 when the API is not
+	// available.
+	JSHeapLimit uint64
+}
+
+func readMemoryStats() MemoryStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	stats := MemoryStats{
+		HeapAlloc:   m.HeapAlloc,
+		HeapSys:     m.HeapSys,
+		HeapObjects: m.HeapObjects,
+		Sys:         m.Sys,
+		NumGC:       m.NumGC,
+	}
+	if IsServer {
+		return stats
+	}
+
+	if inst := Window().Get("goappWasmInstance"); inst.Truthy() {
+		stats.WasmMemory = uint64(inst.Get("exports").Get("mem").Get("buffer").Get("byteLength").Float())
+	}
+	if mem := Window().Get("performance").Get("memory"); mem.Truthy() {
+		stats.JSHeapUsed = uint64(mem.Get("usedJSHeapSize").Float())
+		stats.JSHeapLimit = uint64(mem.Get("jsHeapSizeLimit").Float())
+	}
+	return stats
+}
+
+func measureUserAgentMemory(ctx Context, onDone func(Context, uint64, error)) {
+	if IsServer {
+		return
+	}
+
+	done := func(bytes uint64, err error) {
+		if err != nil {
+			err = errors.New("measuring memory failed").Wrap(err)
+		}
+		dispatchResult(ctx, onDone == nil, err, func(ctx Context) {
+			onDone(ctx, bytes, err)
+		})
+	}
+
+	perf := Window().Get("performance")
+	if !perf.Get("measureUserAgentSpecificMemory").Truthy() {
+		done(0, errors.New("measureUserAgentSpecificMemory is not supported").
+			Tag("cross-origin-isolated", Window().Get("crossOriginIsolated").Bool()))
+		return
+	}
+
+	awaitPromise(perf.Call("measureUserAgentSpecificMemory"), func(res Value) {
+		done(uint64(res.Get("bytes").Float()), nil)
+	}, func(reason Value) {
+		done(0, jsReasonError(reason))
+	})
+}
+
+// checkMemory logs a warning the first time the WebAssembly memory exceeds
+// memoryWarningThreshold.
+func (e *engine) checkMemory() {
+	if e.isMemoryWarned {
+		return
+	}
+
+	stats := readMemoryStats()
+	if stats.WasmMemory < memoryWarningThreshold {
+		return
+	}
+
+	e.isMemoryWarned = true
+	Log(errors.New("app is approaching mobile memory limits").
+		Tag("wasm-memory", stats.WasmMemory).
+		Tag("heap-alloc", stats.HeapAlloc).
+		Tag("heap-objects", stats.HeapObjects).
+		Tag("threshold", memoryWarningThreshold))
+}
+
+// isLocalHost reports whether the given URL targets the local machine, which
+// is how the app is served during development.
+func isLocalHost(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
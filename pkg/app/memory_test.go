@@ -0,0 +1,60 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMemoryStats(t *testing.T) {
+	stats := readMemoryStats()
+	require.NotZero(t, stats.HeapAlloc)
+	require.NotZero(t, stats.Sys)
+	require.Zero(t, stats.WasmMemory)
+}
+
+func TestContextMemoryStats(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	ctx := makeContext(h)
+	require.NotZero(t, ctx.MemoryStats().HeapSys)
+
+	called := false
+	ctx.MeasureMemory(func(ctx Context, bytes uint64, err error) {
+		called = true
+	})
+	d.Consume()
+	require.False(t, called)
+}
+
+func TestEngineCheckMemory(t *testing.T) {
+	e := engine{}
+	e.checkMemory()
+	require.False(t, e.isMemoryWarned)
+}
+
+func TestIsLocalHost(t *testing.T) {
+	utests := []struct {
+		url      string
+		expected bool
+	}{
+		{url: "http://localhost:7777/hello", expected: true},
+		{url: "http://app.localhost", expected: true},
+		{url: "http://127.0.0.1:8000", expected: true},
+		{url: "http://[::1]:8000", expected: true},
+		{url: "https://go-app.dev", expected: false},
+		{url: "http://192.168.1.10:8000", expected: false},
+		{url: "/hello", expected: false},
+	}
+
+	for _, u := range utests {
+		t.Run(u.url, func(t *testing.T) {
+			v, err := url.Parse(u.url)
+			require.NoError(t, err)
+			require.Equal(t, u.expected, isLocalHost(v))
+		})
+	}
+}
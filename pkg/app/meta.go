@@ -0,0 +1,172 @@
+package app
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// OpenGraph describes the Open Graph metadata of a page. It is used by social
+// networks to build link previews.
+//
+// Title, description, image and URL default to the page ones. Other empty
+// fields are omitted.
+type OpenGraph struct {
+	// The type of the page. eg: "article".
+	//
+	// Default: "website".
+	Type string
+
+	// The title of the page.
+	Title string
+
+	// The description of the page.
+	Description string
+
+	// The canonical URL of the page.
+	URL string
+
+	// The path or URL of the image displayed in previews.
+	Image string
+
+	// The description of the image.
+	ImageAlt string
+
+	// The width of the image in px.
+	ImageWidth int
+
+	// The height of the image in px.
+	ImageHeight int
+
+	// The name of the website.
+	SiteName string
+
+	// The locale of the page. eg: "en_US".
+	Locale string
+}
+
+// TwitterCard describes the Twitter card metadata of a page.
+//
+// Empty fields are omitted.
+type TwitterCard struct {
+	// The card type. eg: "summary_large_image".
+	//
+	// Default: "summary".
+	Card string
+
+	// The @username of the website.
+	Site string
+
+	// The @username of the content creator.
+	Creator string
+
+	// The title of the card.
+	Title string
+
+	// The description of the card.
+	Description string
+
+	// The path or URL of the image displayed in the card.
+	Image string
+
+	// The description of the image.
+	ImageAlt string
+}
+
+// StructuredData represents a JSON-LD structured data object that describes
+// the content of a page to search engines.
+//
+// eg:
+//  app.StructuredData{
+//      "@context": "https://schema.org",
+//      "@type":    "Organization",
+//      "url":      "https://go-app.dev",
+//  }
+type StructuredData map[string]interface{}
+
+type metaTag struct {
+	attr     string
+	key      string
+	content  string
+	required bool
+}
+
+func openGraphMetaTags(og OpenGraph, resolve func(string) string) []metaTag {
+	if og.Type == "" {
+		og.Type = "website"
+	}
+
+	tags := []metaTag{
+		{attr: "property", key: "og:type", required: true, content: og.Type},
+		{attr: "property", key: "og:url", required: true, content: og.URL},
+		{attr: "property", key: "og:title", required: true, content: og.Title},
+		{attr: "property", key: "og:description", required: true, content: og.Description},
+		{attr: "property", key: "og:image", required: true, content: resolve(og.Image)},
+		{attr: "property", key: "og:image:alt", content: og.ImageAlt},
+		{attr: "property", key: "og:site_name", content: og.SiteName},
+		{attr: "property", key: "og:locale", content: og.Locale},
+	}
+	if og.ImageWidth > 0 {
+		tags = append(tags, metaTag{attr: "property", key: "og:image:width", content: strconv.Itoa(og.ImageWidth)})
+	}
+	if og.ImageHeight > 0 {
+		tags = append(tags, metaTag{attr: "property", key: "og:image:height", content: strconv.Itoa(og.ImageHeight)})
+	}
+	return tags
+}
+
+func twitterCardMetaTags(c TwitterCard, resolve func(string) string) []metaTag {
+	if c.Card == "" {
+		c.Card = "summary"
+	}
+
+	return []metaTag{
+		{attr: "name", key: "twitter:card", content: c.Card},
+		{attr: "name", key: "twitter:site", content: c.Site},
+		{attr: "name", key: "twitter:creator", content: c.Creator},
+		{attr: "name", key: "twitter:title", content: c.Title},
+		{attr: "name", key: "twitter:description", content: c.Description},
+		{attr: "name", key: "twitter:image", content: resolve(c.Image)},
+		{attr: "name", key: "twitter:image:alt", content: c.ImageAlt},
+	}
+}
+
+func structuredDataScript(data StructuredData) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.New("encoding structured data failed").Wrap(err)
+	}
+	return `<script type="application/ld+json">` + string(b) + `</script>`, nil
+}
+
+func renderMetaTags(tags []metaTag) []UI {
+	metas := make([]UI, 0, len(tags))
+	for _, t := range tags {
+		if t.content == "" && !t.required {
+			continue
+		}
+
+		meta := Meta().Content(t.content)
+		if t.attr == "property" {
+			meta.Property(t.key)
+		} else {
+			meta.Name(t.key)
+		}
+		metas = append(metas, meta)
+	}
+	return metas
+}
+
+func renderStructuredData(data []StructuredData) []UI {
+	scripts := make([]UI, 0, len(data))
+	for _, d := range data {
+		s, err := structuredDataScript(d)
+		if err != nil {
+			Log(err)
+			continue
+		}
+		scripts = append(scripts, Raw(s))
+	}
+	return scripts
+}
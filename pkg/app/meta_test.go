@@ -0,0 +1,91 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/meta-test", &metaTestCompo{})
+}
+
+type metaTestCompo struct {
+	Compo
+}
+
+func (c *metaTestCompo) OnPreRender(ctx Context) {
+	ctx.Page().SetTitle("Hello")
+	ctx.Page().SetOpenGraph(OpenGraph{
+		Type:        "article",
+		ImageWidth:  1200,
+		ImageHeight: 630,
+		SiteName:    "go-app",
+	})
+	ctx.Page().SetTwitterCard(TwitterCard{
+		Card: "summary_large_image",
+		Site: "@jonhymaxoo",
+	})
+	ctx.Page().SetStructuredData(StructuredData{
+		"@context": "https://schema.org",
+		"@type":    "Article",
+		"headline": "</script>Hello",
+	})
+}
+
+func (c *metaTestCompo) Render() UI {
+	return Div()
+}
+
+func TestHandlerServePageMetadata(t *testing.T) {
+	h := Handler{
+		Image: "/web/preview.png",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/meta-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Equal(t, "article", testMetaContent(body, "og:type"))
+	require.Equal(t, "Hello", testMetaContent(body, "og:title"))
+	require.Equal(t, "/web/preview.png", testMetaContent(body, "og:image"))
+	require.Equal(t, "1200", testMetaContent(body, "og:image:width"))
+	require.Equal(t, "go-app", testMetaContent(body, "og:site_name"))
+	require.NotContains(t, body, `og:locale`)
+	require.Equal(t, "summary_large_image", testMetaContent(body, "twitter:card"))
+	require.Equal(t, "@jonhymaxoo", testMetaContent(body, "twitter:site"))
+	require.Contains(t, body, `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","headline":"\u003c/script\u003eHello"}</script>`)
+}
+
+func TestHandlerServePageDefaultMetadata(t *testing.T) {
+	h := Handler{
+		Title: "Default",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Equal(t, "website", testMetaContent(body, "og:type"))
+	require.Equal(t, "Default", testMetaContent(body, "og:title"))
+	require.NotContains(t, body, "twitter:card")
+	require.NotContains(t, body, "application/ld+json")
+}
+
+func testMetaContent(html, key string) string {
+	meta := regexp.MustCompile(`<meta[^>]*(?:property|name)="` + regexp.QuoteMeta(key) + `"[^>]*>`).FindString(html)
+	content := regexp.MustCompile(`content="([^"]*)"`).FindStringSubmatch(meta)
+	if len(content) != 2 {
+		return ""
+	}
+	return content[1]
+}
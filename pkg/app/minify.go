@@ -0,0 +1,340 @@
+package app
+
+import (
+	"bytes"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// HTMLMinifier is the interface that describes a minifier of the HTML
+// documents of pre-rendered pages.
+type HTMLMinifier interface {
+	// Returns the given HTML document minified.
+	MinifyHTML(page []byte) ([]byte, error)
+}
+
+// NewHTMLMinifier creates a minifier that:
+//  - Collapses consecutive whitespaces into a single space.
+//  - Removes the whitespaces around document metadata elements, such as meta
+//    or script, and between block elements, such as div or li.
+//  - Removes comments, except conditional comments.
+//  - Removes the quotes of attribute values that do not require them.
+//
+// The content of pre, textarea, script and style elements is kept as is,
+// which means that the minified document is displayed the same way as the
+// original one.
+func NewHTMLMinifier() HTMLMinifier {
+	return htmlMinifier{}
+}
+
+type htmlMinifier struct{}
+
+func (m htmlMinifier) MinifyHTML(page []byte) ([]byte, error) {
+	var b bytes.Buffer
+	b.Grow(len(page))
+
+	prevTag := ""
+	s := page
+
+	for len(s) != 0 {
+		text := s
+		if i := bytes.IndexByte(s, '<'); i >= 0 {
+			text = s[:i]
+		}
+		if len(text) != 0 {
+			s = s[len(text):]
+			writeMinifiedText(&b, text, prevTag, nextTagName(s))
+			continue
+		}
+
+		switch {
+		case bytes.HasPrefix(s, []byte("<!--")):
+			end := bytes.Index(s[4:], []byte("-->"))
+			if end < 0 {
+				return nil, errors.New("minifying html failed").
+					Tag("reason", "unterminated comment")
+			}
+			comment := s[:4+end+3]
+			if bytes.HasPrefix(comment, []byte("<!--[if")) {
+				b.Write(comment)
+			}
+			s = s[len(comment):]
+
+		case bytes.HasPrefix(s, []byte("<![CDATA[")):
+			end := bytes.Index(s, []byte("]]>"))
+			if end < 0 {
+				return nil, errors.New("minifying html failed").
+					Tag("reason", "unterminated cdata section")
+			}
+			b.Write(s[:end+3])
+			s = s[end+3:]
+
+		case bytes.HasPrefix(s, []byte("<!")) || bytes.HasPrefix(s, []byte("<?")):
+			end := bytes.IndexByte(s, '>')
+			if end < 0 {
+				return nil, errors.New("minifying html failed").
+					Tag("reason", "unterminated declaration")
+			}
+			b.Write(s[:end+1])
+			s = s[end+1:]
+			prevTag = "!doctype"
+
+		case bytes.HasPrefix(s, []byte("</")):
+			end := bytes.IndexByte(s, '>')
+			if end < 0 {
+				return nil, errors.New("minifying html failed").
+					Tag("reason", "unterminated end tag")
+			}
+			name := bytes.TrimSpace(s[2:end])
+			b.WriteString("</")
+			b.Write(name)
+			b.WriteByte('>')
+			s = s[end+1:]
+			prevTag = string(bytes.ToLower(name))
+
+		case len(s) > 1 && isASCIILetter(s[1]):
+			name, n, err := writeMinifiedStartTag(&b, s)
+			if err != nil {
+				return nil, err
+			}
+			s = s[n:]
+			prevTag = name
+
+			if isRawTextElement(name) {
+				end := indexEndTag(s, name)
+				if end < 0 {
+					return nil, errors.New("minifying html failed").
+						Tag("reason", "unterminated element").
+						Tag("element", name)
+				}
+				b.Write(s[:end])
+				s = s[end:]
+			}
+
+		default:
+			b.WriteByte('<')
+			s = s[1:]
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// writeMinifiedText writes the given text with its whitespaces collapsed. A
+// text that only contains whitespaces is removed when it is not displayed
+// between the given tags.
+func writeMinifiedText(b *bytes.Buffer, text []byte, prevTag, nextTag string) {
+	if len(bytes.TrimLeft(text, htmlSpaces)) == 0 {
+		if isMetadataElement(prevTag) || isMetadataElement(nextTag) ||
+			(isBlockElement(prevTag) && isBlockElement(nextTag)) {
+			return
+		}
+	}
+
+	prevSpace := b.Len() != 0 && b.Bytes()[b.Len()-1] == ' '
+	for _, c := range text {
+		if isHTMLSpace(c) {
+			if !prevSpace {
+				b.WriteByte(' ')
+			}
+			prevSpace = true
+			continue
+		}
+
+		b.WriteByte(c)
+		prevSpace = false
+	}
+}
+
+// writeMinifiedStartTag writes the start tag at the beginning of s with its
+// unnecessary whitespaces and attribute quotes removed. It returns the
+// lowercased tag name and the length of the tag in s.
+func writeMinifiedStartTag(b *bytes.Buffer, s []byte) (string, int, error) {
+	i := 1
+	for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	name := s[1:i]
+	b.WriteByte('<')
+	b.Write(name)
+
+	unquoted := false
+	for {
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+
+		switch {
+		case i >= len(s):
+			return "", 0, errors.New("minifying html failed").
+				Tag("reason", "unterminated start tag").
+				Tag("element", string(name))
+
+		case s[i] == '>':
+			b.WriteByte('>')
+			return string(bytes.ToLower(name)), i + 1, nil
+
+		case s[i] == '/' && i+1 < len(s) && s[i+1] == '>':
+			if unquoted {
+				b.WriteByte(' ')
+			}
+			b.WriteString("/>")
+			return string(bytes.ToLower(name)), i + 2, nil
+
+		case s[i] == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		b.WriteByte(' ')
+		b.Write(s[start:i])
+		unquoted = false
+
+		j := i
+		for j < len(s) && isHTMLSpace(s[j]) {
+			j++
+		}
+		if j >= len(s) || s[j] != '=' {
+			continue
+		}
+
+		i = j + 1
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			continue
+		}
+
+		var value []byte
+		quote := s[i]
+		if quote == '"' || quote == '\'' {
+			end := bytes.IndexByte(s[i+1:], quote)
+			if end < 0 {
+				return "", 0, errors.New("minifying html failed").
+					Tag("reason", "unterminated attribute value").
+					Tag("element", string(name))
+			}
+			value = s[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			quote = '"'
+			start := i
+			for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+				i++
+			}
+			value = s[start:i]
+		}
+
+		b.WriteByte('=')
+		if canUnquoteAttr(value) {
+			b.Write(value)
+			unquoted = true
+			continue
+		}
+		b.WriteByte(quote)
+		b.Write(value)
+		b.WriteByte(quote)
+	}
+}
+
+// canUnquoteAttr reports whether the given attribute value can be written
+// without quotes.
+func canUnquoteAttr(v []byte) bool {
+	if len(v) == 0 || v[len(v)-1] == '/' {
+		return false
+	}
+	return bytes.IndexAny(v, " \t\n\f\r\"'=<>`") < 0
+}
+
+// nextTagName returns the lowercased name of the tag at the beginning of s.
+func nextTagName(s []byte) string {
+	if len(s) == 0 || s[0] != '<' {
+		return ""
+	}
+	s = s[1:]
+	if len(s) != 0 && s[0] == '/' {
+		s = s[1:]
+	}
+
+	i := 0
+	for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	return string(bytes.ToLower(s[:i]))
+}
+
+// indexEndTag returns the index of the end tag of the given element in s, or
+// -1 when s does not contain it.
+func indexEndTag(s []byte, name string) int {
+	return bytes.Index(bytes.ToLower(s), []byte("</"+name))
+}
+
+const (
+	htmlSpaces = " \t\n\f\r"
+)
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isRawTextElement(name string) bool {
+	switch name {
+	case "pre", "script", "style", "textarea":
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isMetadataElement(name string) bool {
+	switch name {
+	case "!doctype", "html", "head", "body", "title", "base", "link", "meta",
+		"script", "style", "template", "noscript":
+		return true
+
+	default:
+		return false
+	}
+}
+
+func isBlockElement(name string) bool {
+	switch name {
+	case "address", "article", "aside", "blockquote", "dd", "details",
+		"dialog", "div", "dl", "dt", "fieldset", "figcaption", "figure",
+		"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr",
+		"li", "main", "nav", "ol", "p", "pre", "section", "summary", "table",
+		"tbody", "td", "tfoot", "th", "thead", "tr", "ul", "option", "optgroup",
+		"caption", "colgroup", "col", "legend":
+		return true
+
+	default:
+		return false
+	}
+}
+
+// minifyPage returns the given pre-rendered page minified with the handler
+// minifier. The page is returned as is when the minification fails.
+func (h *Handler) minifyPage(path string, page []byte) []byte {
+	if h.HTMLMinifier == nil {
+		return page
+	}
+
+	minified, err := h.HTMLMinifier.MinifyHTML(page)
+	if err != nil {
+		Log(errors.New("minifying pre-rendered page failed").
+			Tag("path", path).
+			Wrap(err))
+		return page
+	}
+	return minified
+}
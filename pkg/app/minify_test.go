@@ -0,0 +1,128 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLMinifier(t *testing.T) {
+	utests := []struct {
+		scenario string
+		in       string
+		out      string
+	}{
+		{
+			scenario: "whitespaces are collapsed",
+			in:       "<span>\n  hello   world\n</span>",
+			out:      "<span> hello world </span>",
+		},
+		{
+			scenario: "whitespaces between blocks are removed",
+			in:       "<div>\n  <p>hello</p>\n  <p>world</p>\n</div>",
+			out:      "<div><p>hello</p><p>world</p></div>",
+		},
+		{
+			scenario: "whitespaces between inline elements are kept",
+			in:       "<span>a</span>\n  <span>b</span>",
+			out:      "<span>a</span> <span>b</span>",
+		},
+		{
+			scenario: "whitespaces around metadata are removed",
+			in:       "<!DOCTYPE html>\n<html>\n<head>\n  <meta charset=\"UTF-8\">\n  <title>Hi</title>\n</head>\n</html>",
+			out:      "<!DOCTYPE html><html><head><meta charset=UTF-8><title>Hi</title></head></html>",
+		},
+		{
+			scenario: "comments are removed",
+			in:       "<span>a<!-- comment -->b</span> <!-- other --> <span>c</span>",
+			out:      "<span>ab</span> <span>c</span>",
+		},
+		{
+			scenario: "conditional comments are kept",
+			in:       "<!--[if IE]><p>IE</p><![endif]-->",
+			out:      "<!--[if IE]><p>IE</p><![endif]-->",
+		},
+		{
+			scenario: "attribute quotes are removed when possible",
+			in:       `<input  type="text"   value="a b" data-x='it"s' placeholder="" disabled>`,
+			out:      `<input type=text value="a b" data-x='it"s' placeholder="" disabled>`,
+		},
+		{
+			scenario: "unquoted value before self closing tag is separated",
+			in:       `<img src="a.png"/><img alt="path/"/>`,
+			out:      `<img src=a.png /><img alt="path/"/>`,
+		},
+		{
+			scenario: "pre content is kept",
+			in:       "<pre>\n  a   <b>b</b>\n    c</pre>",
+			out:      "<pre>\n  a   <b>b</b>\n    c</pre>",
+		},
+		{
+			scenario: "textarea content is kept",
+			in:       "<textarea>  a\n\n  b  </textarea>",
+			out:      "<textarea>  a\n\n  b  </textarea>",
+		},
+		{
+			scenario: "script content is kept",
+			in:       "<script>\n  if (a < b && c > d) {\n    // <!-- x -->\n  }\n</SCRIPT>",
+			out:      "<script>\n  if (a < b && c > d) {\n    // <!-- x -->\n  }\n</SCRIPT>",
+		},
+		{
+			scenario: "style content is kept",
+			in:       "<style>\n  a > b {  color: red; }\n</style>",
+			out:      "<style>\n  a > b {  color: red; }\n</style>",
+		},
+		{
+			scenario: "less than sign in text is kept",
+			in:       "<span>1 <  2</span>",
+			out:      "<span>1 < 2</span>",
+		},
+	}
+
+	m := NewHTMLMinifier()
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			out, err := m.MinifyHTML([]byte(u.in))
+			require.NoError(t, err)
+			require.Equal(t, u.out, string(out))
+		})
+	}
+}
+
+func TestHTMLMinifierErrors(t *testing.T) {
+	m := NewHTMLMinifier()
+
+	for _, in := range []string{
+		"<div><!-- comment",
+		"<div class=\"a",
+		"<div",
+		"<script>let a = 1;",
+		"</div",
+	} {
+		t.Run(in, func(t *testing.T) {
+			_, err := m.MinifyHTML([]byte(in))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHandlerHTMLMinifier(t *testing.T) {
+	serve := func(h *Handler) string {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	original := serve(&Handler{})
+	minified := serve(&Handler{HTMLMinifier: NewHTMLMinifier()})
+
+	require.Less(t, len(minified), len(original))
+	require.Contains(t, minified, "<!DOCTYPE html><html>")
+	require.NotContains(t, minified, "\n<div")
+}
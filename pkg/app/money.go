@@ -0,0 +1,254 @@
+package app
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+var (
+	// The number of minor unit digits of the ISO 4217 currencies that do not
+	// have 2 digits.
+	currencyDigits = map[string]int{
+		"BHD": 3,
+		"BIF": 0,
+		"CLF": 4,
+		"CLP": 0,
+		"DJF": 0,
+		"GNF": 0,
+		"IQD": 3,
+		"ISK": 0,
+		"JOD": 3,
+		"JPY": 0,
+		"KMF": 0,
+		"KRW": 0,
+		"KWD": 3,
+		"LYD": 3,
+		"OMR": 3,
+		"PYG": 0,
+		"RWF": 0,
+		"TND": 3,
+		"UGX": 0,
+		"UYI": 0,
+		"UYW": 4,
+		"VND": 0,
+		"VUV": 0,
+		"XAF": 0,
+		"XOF": 0,
+		"XPF": 0,
+	}
+)
+
+// CurrencyDigits returns the number of digits of the minor unit of the given
+// ISO 4217 currency code. Eg: 2 for "USD" which has cents, or 0 for "JPY".
+func CurrencyDigits(currency string) int {
+	if d, ok := currencyDigits[strings.ToUpper(currency)]; ok {
+		return d
+	}
+	return 2
+}
+
+// Money represents an amount of money in the minor unit of its currency, such
+// as cents. Amounts are integers, which avoids the rounding errors of floating
+// point arithmetic.
+type Money struct {
+	// The amount in the minor unit of the currency. Eg: 1234 for 12.34 USD.
+	Amount int64 `json:"amount"`
+
+	// The ISO 4217 currency code. Eg: "USD".
+	Currency string `json:"currency"`
+}
+
+// ParseMoney parses the given decimal amount, such as "-12.34", in the given
+// currency. It returns an error when the amount has more decimals than the
+// minor unit of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	digits := CurrencyDigits(currency)
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	units, decimals := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		units, decimals = s[:i], s[i+1:]
+	}
+	if units == "" && decimals == "" || len(decimals) > digits ||
+		!isDigits(units) || !isDigits(decimals) {
+		return Money{}, errors.New("parsing money failed").
+			Tag("amount", amount).
+			Tag("currency", currency)
+	}
+
+	minor := units + decimals + strings.Repeat("0", digits-len(decimals))
+	v, err := strconv.ParseInt(minor, 10, 64)
+	if err != nil {
+		return Money{}, errors.New("parsing money failed").
+			Tag("amount", amount).
+			Tag("currency", currency).
+			Wrap(err)
+	}
+	if negative {
+		v = -v
+	}
+	return Money{Amount: v, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns the sum of the amounts. It returns an error when the currencies
+// are different.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	m.Amount += o.Amount
+	return m, nil
+}
+
+// Sub returns the difference of the amounts. It returns an error when the
+// currencies are different.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	m.Amount -= o.Amount
+	return m, nil
+}
+
+// Mul returns the amount multiplied by the given factor, such as a tax rate,
+// rounded to the nearest minor unit. Halves are rounded to the nearest even
+// minor unit, as banks do.
+func (m Money) Mul(factor float64) Money {
+	m.Amount = int64(math.RoundToEven(float64(m.Amount) * factor))
+	return m
+}
+
+// Convert returns the amount converted to the given currency with the given
+// exchange rate, rounded to the nearest minor unit of the currency. Eg:
+// Money{Amount: 1000, Currency: "EUR"}.Convert("JPY", 130.5) returns 1305 JPY.
+func (m Money) Convert(currency string, rate float64) Money {
+	currency = strings.ToUpper(currency)
+	shift := math.Pow10(CurrencyDigits(currency) - CurrencyDigits(m.Currency))
+
+	return Money{
+		Amount:   int64(math.RoundToEven(float64(m.Amount) * rate * shift)),
+		Currency: currency,
+	}
+}
+
+// Allocate splits the amount according to the given ratios without losing any
+// minor unit: the minor units that can't be evenly split are given to the
+// first shares. Eg: 100 split with ratios 1, 1 and 1 gives 34, 33 and 33.
+func (m Money) Allocate(ratios ...int) []Money {
+	var total int64
+	for _, r := range ratios {
+		if r > 0 {
+			total += int64(r)
+		}
+	}
+
+	shares := make([]Money, len(ratios))
+	if total == 0 {
+		for i := range shares {
+			shares[i] = Money{Currency: m.Currency}
+		}
+		return shares
+	}
+
+	amount := m.Amount
+	sign := int64(1)
+	if amount < 0 {
+		amount, sign = -amount, -1
+	}
+
+	remainder := amount
+	for i, r := range ratios {
+		var share int64
+		if r > 0 {
+			share = amount * int64(r) / total
+		}
+		shares[i] = Money{Amount: share, Currency: m.Currency}
+		remainder -= share
+	}
+	for i := 0; remainder > 0; i = (i + 1) % len(shares) {
+		if ratios[i] > 0 {
+			shares[i].Amount++
+			remainder--
+		}
+	}
+
+	for i := range shares {
+		shares[i].Amount *= sign
+	}
+	return shares
+}
+
+// Decimal returns the amount as a decimal number in the major unit of the
+// currency. Eg: "12.34" for 1234 USD cents.
+func (m Money) Decimal() string {
+	digits := CurrencyDigits(m.Currency)
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	s := strconv.FormatUint(absInt64(amount), 10)
+	if digits == 0 {
+		return sign + s
+	}
+
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// String returns the amount followed by its currency code. Eg: "12.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if !strings.EqualFold(m.Currency, o.Currency) {
+		return errors.New("currencies are different").
+			Tag("currency", m.Currency).
+			Tag("other-currency", o.Currency)
+	}
+	return nil
+}
+
+func absInt64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
+
+// formatMoney formats the given amount of money with its currency symbol. It
+// uses Intl.NumberFormat in the browser and the currency code on the server.
+func formatMoney(locale string, m Money) string {
+	if s, ok := formatIntl("NumberFormat", locale, map[string]interface{}{
+		"style":    "currency",
+		"currency": m.Currency,
+	}, m.Decimal()); ok {
+		return s
+	}
+	return m.String()
+}
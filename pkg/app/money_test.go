@@ -0,0 +1,128 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyDigits(t *testing.T) {
+	require.Equal(t, 2, CurrencyDigits("USD"))
+	require.Equal(t, 0, CurrencyDigits("jpy"))
+	require.Equal(t, 3, CurrencyDigits("KWD"))
+}
+
+func TestParseMoney(t *testing.T) {
+	utests := []struct {
+		amount   string
+		currency string
+		expected Money
+		err      bool
+	}{
+		{amount: "12.34", currency: "usd", expected: Money{Amount: 1234, Currency: "USD"}},
+		{amount: "-12.3", currency: "EUR", expected: Money{Amount: -1230, Currency: "EUR"}},
+		{amount: "+7", currency: "EUR", expected: Money{Amount: 700, Currency: "EUR"}},
+		{amount: ".5", currency: "EUR", expected: Money{Amount: 50, Currency: "EUR"}},
+		{amount: "1305", currency: "JPY", expected: Money{Amount: 1305, Currency: "JPY"}},
+		{amount: "1.234", currency: "KWD", expected: Money{Amount: 1234, Currency: "KWD"}},
+		{amount: "0.001", currency: "USD", err: true},
+		{amount: "1.5", currency: "JPY", err: true},
+		{amount: "1,000.00", currency: "USD", err: true},
+		{amount: "", currency: "USD", err: true},
+		{amount: "99999999999999999999", currency: "USD", err: true},
+	}
+
+	for _, u := range utests {
+		t.Run(u.amount+" "+u.currency, func(t *testing.T) {
+			m, err := ParseMoney(u.amount, u.currency)
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, u.expected, m)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := Money{Amount: 1999, Currency: "EUR"}
+
+	t.Run("add", func(t *testing.T) {
+		m, err := price.Add(Money{Amount: 1, Currency: "EUR"})
+		require.NoError(t, err)
+		require.Equal(t, Money{Amount: 2000, Currency: "EUR"}, m)
+	})
+
+	t.Run("sub", func(t *testing.T) {
+		m, err := price.Sub(Money{Amount: 2000, Currency: "EUR"})
+		require.NoError(t, err)
+		require.Equal(t, Money{Amount: -1, Currency: "EUR"}, m)
+	})
+
+	t.Run("different currencies", func(t *testing.T) {
+		_, err := price.Add(Money{Amount: 1, Currency: "USD"})
+		require.Error(t, err)
+
+		_, err = price.Sub(Money{Amount: 1, Currency: "USD"})
+		require.Error(t, err)
+	})
+
+	t.Run("mul", func(t *testing.T) {
+		require.Equal(t, int64(380), price.Mul(0.19).Amount)
+		require.Equal(t, int64(2), Money{Amount: 5}.Mul(0.5).Amount)
+		require.Equal(t, int64(4), Money{Amount: 7}.Mul(0.5).Amount)
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		require.Equal(t, Money{Amount: 1305, Currency: "JPY"}, Money{Amount: 1000, Currency: "EUR"}.Convert("jpy", 130.5))
+		require.Equal(t, Money{Amount: 766, Currency: "EUR"}, Money{Amount: 1000, Currency: "JPY"}.Convert("EUR", 0.00766))
+		require.Equal(t, Money{Amount: 1085, Currency: "USD"}, Money{Amount: 1000, Currency: "EUR"}.Convert("USD", 1.085))
+	})
+
+	t.Run("allocate", func(t *testing.T) {
+		require.Equal(t, []Money{
+			{Amount: 34, Currency: "EUR"},
+			{Amount: 33, Currency: "EUR"},
+			{Amount: 33, Currency: "EUR"},
+		}, Money{Amount: 100, Currency: "EUR"}.Allocate(1, 1, 1))
+
+		require.Equal(t, []Money{
+			{Amount: -7, Currency: "EUR"},
+			{Amount: 0, Currency: "EUR"},
+			{Amount: -3, Currency: "EUR"},
+		}, Money{Amount: -10, Currency: "EUR"}.Allocate(2, 0, 1))
+
+		require.Equal(t, []Money{
+			{Currency: "EUR"},
+			{Currency: "EUR"},
+		}, Money{Amount: 10, Currency: "EUR"}.Allocate(0, 0))
+	})
+
+	t.Run("is zero", func(t *testing.T) {
+		require.True(t, Money{Currency: "EUR"}.IsZero())
+		require.False(t, price.IsZero())
+	})
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	require.Equal(t, "12.34", Money{Amount: 1234, Currency: "USD"}.Decimal())
+	require.Equal(t, "0.05", Money{Amount: 5, Currency: "USD"}.Decimal())
+	require.Equal(t, "-0.50", Money{Amount: -50, Currency: "USD"}.Decimal())
+	require.Equal(t, "1305", Money{Amount: 1305, Currency: "JPY"}.Decimal())
+	require.Equal(t, "0.001", Money{Amount: 1, Currency: "KWD"}.Decimal())
+	require.Equal(t, "12.34 USD", Money{Amount: 1234, Currency: "USD"}.String())
+}
+
+func TestContextFormatMoney(t *testing.T) {
+	if !IsServer {
+		t.Skip()
+	}
+
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	ctx := makeContext(compo)
+	require.Equal(t, "12.34 EUR", ctx.FormatMoney(Money{Amount: 1234, Currency: "EUR"}))
+}
@@ -33,12 +33,17 @@ type UI interface {
 	setParent(UI)
 	children() []UI
 	mount(Dispatcher) error
+	hydrate(Dispatcher, Value) error
 	dismount()
 	update(UI) error
 	onNav(*url.URL)
 	onAppUpdate()
 	onAppInstallChange()
 	onResize()
+	onVersionSkew()
+	onAppInstalled()
+	onDisplayModeChange()
+	onAppLaunch(LaunchParams)
 	preRender(Page)
 	html(w io.Writer)
 	htmlWithIndent(w io.Writer, indent int)
@@ -134,28 +139,34 @@ func FilterUIElems(uis ...UI) []UI {
 type EventHandler func(ctx Context, e Event)
 
 type eventHandler struct {
-	event   string
-	scope   string
-	jsvalue Func
-	value   EventHandler
+	event     string
+	scope     string
+	modifiers EventModifier
+	jsvalue   Func
+	value     EventHandler
 }
 
 func (h eventHandler) equal(o eventHandler) bool {
 	return h.event == o.event && h.scope == o.scope &&
+		h.modifiers == o.modifiers &&
 		reflect.ValueOf(h.value).Pointer() == reflect.ValueOf(o.value).Pointer()
 }
 
-func makeJsEventHandler(src UI, h EventHandler) Func {
+func makeJsEventHandler(src UI, h EventHandler, modifiers EventModifier) Func {
 	return FuncOf(func(this Value, args []Value) interface{} {
+		applyEventModifiers(args[0], modifiers)
+
 		src.dispatcher().Dispatch(Dispatch{
-			Mode:   Update,
-			Source: src,
+			Mode:     Update,
+			Priority: HighPriority,
+			Source:   src,
 			Function: func(ctx Context) {
 				ctx.Emit(func() {
 					event := Event{
 						Value: args[0],
 					}
 					trackMousePosition(event)
+					auditEvent(src, event)
 					h(ctx, event)
 				})
 			},
@@ -286,6 +286,19 @@ func TestEventHandlerEquality(t *testing.T) {
 			},
 			equals: false,
 		},
+		{
+			scenario: "same event with same func and different modifiers are not equal",
+			a: eventHandler{
+				event:     "test",
+				modifiers: PreventDefault,
+				value:     funcA,
+			},
+			b: eventHandler{
+				event: "test",
+				value: funcA,
+			},
+			equals: false,
+		},
 	}
 
 	for _, u := range utests {
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Outlet returns the element where the component of a parent route displays
+// the component of the matching child route. It is empty when the page does
+// not match a child route. See RouteChild.
+//
+// eg:
+//  func (l *settingsLayout) Render() app.UI {
+//      return app.Div().Body(
+//          app.Nav().Body(
+//              app.A().Href("/settings/profile").Text("Profile"),
+//              app.A().Href("/settings/users/42").Text("Users"),
+//          ),
+//          app.Main().Body(
+//              app.Outlet(),
+//          ),
+//      )
+//  }
+func Outlet() UI {
+	return &outlet{}
+}
+
+// outlet displays the component of a child route. Its child is set once it
+// is mounted or hydrated, and when the outlets of the page are refreshed
+// after a navigation.
+type outlet struct {
+	Compo
+
+	route string
+	child UI
+}
+
+func (o *outlet) mount(d Dispatcher) error {
+	if err := o.Compo.mount(d); err != nil {
+		return err
+	}
+
+	// The depth of the outlet is known once it is attached to its parents.
+	// Resolving the child afterward displays the child of outlets that are
+	// mounted after the page, such as the ones of conditional layouts.
+	d.Dispatch(Dispatch{
+		Mode:   Next,
+		Source: o,
+		Function: func(Context) {
+			if !o.Mounted() {
+				return
+			}
+
+			path := d.currentRoutePath()
+			if err := o.refresh(path, o.depth()); err != nil {
+				panic(errors.New("refreshing outlet failed").
+					Tag("path", path).
+					Tag("depth", o.depth()).
+					Wrap(err))
+			}
+		},
+	})
+	return nil
+}
+
+// hydrate resolves the child before the pre-rendered child is hydrated, which
+// lets the nodes of the child be reused. Parents are set before their
+// children are hydrated, so the depth of the outlet is already known.
+func (o *outlet) hydrate(d Dispatcher, v Value) error {
+	path := d.currentRoutePath()
+	if err := o.refresh(path, o.depth()); err != nil {
+		return errors.New("hydrating outlet failed").
+			Tag("path", path).
+			Tag("depth", o.depth()).
+			Wrap(err)
+	}
+	return o.Compo.hydrate(d, v)
+}
+
+// depth returns the number of outlets between the outlet and the page,
+// itself included.
+func (o *outlet) depth() int {
+	depth := 1
+	for p := o.parent(); p != nil; p = p.parent() {
+		if _, ok := p.(*outlet); ok {
+			depth++
+		}
+	}
+	return depth
+}
+
+func (o *outlet) Render() UI {
+	if o.child == nil {
+		return Text("")
+	}
+	return o.child
+}
+
+// refresh displays the component of the child route at the given depth of the
+// given path. The displayed component is kept when its route did not change.
+func (o *outlet) refresh(path string, depth int) error {
+	route, compo, ok := routes.createChildComponent(path, depth)
+	if o.child != nil && route == o.route {
+		return nil
+	}
+
+	o.route = route
+	o.child = Text("")
+	if ok {
+		o.child = compo
+	}
+
+	if !o.Mounted() {
+		return nil
+	}
+	return o.replaceRoot(o.child)
+}
+
+// refreshOutlets refreshes the outlets of the given element and its children
+// with the given route path.
+func refreshOutlets(n UI, path string) error {
+	return refreshOutletsAt(n, path, 0)
+}
+
+func refreshOutletsAt(n UI, path string, depth int) error {
+	if o, ok := n.(*outlet); ok {
+		depth++
+		if err := o.refresh(path, depth); err != nil {
+			return errors.New("refreshing outlet failed").
+				Tag("path", path).
+				Tag("depth", depth).
+				Wrap(err)
+		}
+	}
+
+	for _, c := range n.children() {
+		if err := refreshOutletsAt(c, path, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/outlet-test", &outletLayout{})
+	RouteChild("/outlet-test", "/a", &outletChildA{})
+	RouteChild("/outlet-test", "/b/:id", &outletChildB{})
+	RouteChild("/outlet-test/b/:id", "/details", &outletChildA{})
+
+	Route("/outlet-late", &outletLateLayout{})
+	RouteChild("/outlet-late", "/b/:id", &outletChildB{})
+	RouteChild("/outlet-late/b/:id", "/details", &outletChildA{})
+}
+
+type outletLayout struct {
+	Compo
+}
+
+func (l *outletLayout) Render() UI {
+	return Div().ID("outlet-layout").Body(
+		Outlet(),
+	)
+}
+
+// outletLateLayout displays its outlet once it is pre-rendered or updated.
+type outletLateLayout struct {
+	Compo
+
+	showOutlet bool
+}
+
+func (l *outletLateLayout) OnPreRender(ctx Context) {
+	l.showOutlet = true
+}
+
+func (l *outletLateLayout) Render() UI {
+	return Div().ID("outlet-late-layout").Body(
+		If(l.showOutlet,
+			Outlet(),
+		),
+	)
+}
+
+type outletChildA struct {
+	Compo
+}
+
+func (c *outletChildA) Render() UI {
+	return Div().ID("outlet-a")
+}
+
+type outletChildB struct {
+	Compo
+}
+
+func (c *outletChildB) Render() UI {
+	return Div().ID("outlet-b").Body(
+		Outlet(),
+	)
+}
+
+func TestRouterChildRoutes(t *testing.T) {
+	utests := []struct {
+		scenario string
+		path     string
+		chain    []Composer
+	}{
+		{
+			scenario: "parent route",
+			path:     "/outlet-test",
+			chain:    []Composer{&outletLayout{}},
+		},
+		{
+			scenario: "child route",
+			path:     "/outlet-test/a",
+			chain:    []Composer{&outletLayout{}, &outletChildA{}},
+		},
+		{
+			scenario: "child route with parameter",
+			path:     "/outlet-test/b/42",
+			chain:    []Composer{&outletLayout{}, &outletChildB{}},
+		},
+		{
+			scenario: "grandchild route",
+			path:     "/outlet-test/b/42/details",
+			chain:    []Composer{&outletLayout{}, &outletChildB{}, &outletChildA{}},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			compo, ok := routes.createComponent(u.path)
+			require.True(t, ok)
+			require.IsType(t, u.chain[0], compo)
+
+			for i := 1; i < len(u.chain); i++ {
+				_, compo, ok := routes.createChildComponent(u.path, i)
+				require.True(t, ok)
+				require.IsType(t, u.chain[i], compo)
+			}
+
+			_, _, ok = routes.createChildComponent(u.path, len(u.chain))
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestOutlet(t *testing.T) {
+	d := NewClientTester(Div())
+	defer d.Close()
+	e := d.(*engine)
+
+	navigate := func(path string) {
+		u, _ := url.Parse(path)
+		d.loadRoute(u, path, func() {})
+		compo, _ := routes.createComponent(path)
+		d.Mount(compo)
+		d.Consume()
+	}
+
+	outletChild := func(depth int) UI {
+		var outlets []*outlet
+		var find func(UI)
+		find = func(n UI) {
+			if o, ok := n.(*outlet); ok {
+				outlets = append(outlets, o)
+			}
+			for _, c := range n.children() {
+				find(c)
+			}
+		}
+		find(e.Body)
+		require.True(t, len(outlets) >= depth)
+		return outlets[depth-1].root
+	}
+
+	navigate("/outlet-test/a")
+	layout := e.Body.children()[0]
+	require.IsType(t, &outletLayout{}, layout)
+	require.IsType(t, &outletChildA{}, outletChild(1))
+
+	navigate("/outlet-test/b/42")
+	require.Same(t, layout, e.Body.children()[0])
+	childB := outletChild(1)
+	require.IsType(t, &outletChildB{}, childB)
+	require.Equal(t, "42", makeContext(childB).RouteParam("id"))
+
+	navigate("/outlet-test/b/21/details")
+	require.Same(t, layout, e.Body.children()[0])
+	require.Same(t, childB, outletChild(1))
+	require.IsType(t, &outletChildA{}, outletChild(2))
+	require.Equal(t, "21", makeContext(childB).RouteParam("id"))
+
+	navigate("/outlet-test")
+	require.Same(t, layout, e.Body.children()[0])
+	require.Equal(t, SimpleText, outletChild(1).Kind())
+}
+
+func TestOutletMountedAfterPage(t *testing.T) {
+	d := NewClientTester(Div())
+	defer d.Close()
+	e := d.(*engine)
+
+	path := "/outlet-late/b/42/details"
+	u, _ := url.Parse(path)
+	d.loadRoute(u, path, func() {})
+	compo, _ := routes.createComponent(path)
+	d.Mount(compo)
+	d.Consume()
+
+	layout := compo.(*outletLateLayout)
+	require.Empty(t, layout.root.children())
+
+	layout.showOutlet = true
+	layout.Update()
+	d.Consume()
+	d.Consume() // Resolves the child of the mounted outlet.
+
+	outlets := layout.root.children()
+	require.Len(t, outlets, 1)
+	childB := outlets[0].(*outlet).root
+	require.IsType(t, &outletChildB{}, childB)
+	require.Equal(t, "42", makeContext(childB).RouteParam("id"))
+
+	nested := childB.(*outletChildB).root.children()[0].(*outlet)
+	require.Equal(t, 2, nested.depth())
+	require.IsType(t, &outletChildA{}, nested.root)
+	require.Equal(t, path, e.routePath)
+}
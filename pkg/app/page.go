@@ -1,8 +1,11 @@
 package app
 
 import (
+	"encoding/json"
 	"net/url"
 	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
 )
 
 // Page is the interface that describes a web page.
@@ -50,18 +53,32 @@ type Page interface {
 
 	// Returns the page width and height in px.
 	Size() (w int, h int)
+
+	// Sets the Open Graph metadata used by social networks when linking the
+	// page.
+	SetOpenGraph(OpenGraph)
+
+	// Sets the Twitter card metadata used when linking the page on Twitter.
+	SetTwitterCard(TwitterCard)
+
+	// Sets the JSON-LD structured data that describes the page content to
+	// search engines.
+	SetStructuredData(...StructuredData)
 }
 
 type requestPage struct {
-	title        string
-	description  string
-	author       string
-	keywords     string
-	loadingLabel string
-	image        string
-	url          *url.URL
-	width        int
-	height       int
+	title          string
+	description    string
+	author         string
+	keywords       string
+	loadingLabel   string
+	image          string
+	url            *url.URL
+	width          int
+	height         int
+	openGraph      OpenGraph
+	twitterCard    *TwitterCard
+	structuredData []StructuredData
 }
 
 func (p *requestPage) Title() string {
@@ -120,6 +137,40 @@ func (p *requestPage) Size() (width int, height int) {
 	return p.width, p.height
 }
 
+func (p *requestPage) SetOpenGraph(v OpenGraph) {
+	p.openGraph = v
+}
+
+func (p *requestPage) SetTwitterCard(v TwitterCard) {
+	p.twitterCard = &v
+}
+
+func (p *requestPage) SetStructuredData(v ...StructuredData) {
+	p.structuredData = v
+}
+
+func (p *requestPage) metaTags(resolve func(string) string) []metaTag {
+	og := p.openGraph
+	if og.Title == "" {
+		og.Title = p.title
+	}
+	if og.Description == "" {
+		og.Description = p.description
+	}
+	if og.URL == "" && p.url != nil {
+		og.URL = p.url.String()
+	}
+	if og.Image == "" {
+		og.Image = p.image
+	}
+
+	tags := openGraphMetaTags(og, resolve)
+	if p.twitterCard != nil {
+		tags = append(tags, twitterCardMetaTags(*p.twitterCard, resolve)...)
+	}
+	return tags
+}
+
 type browserPage struct {
 	url        *url.URL
 	dispatcher Dispatcher
@@ -188,6 +239,71 @@ func (p browserPage) Size() (width int, height int) {
 	return Window().Size()
 }
 
+func (p browserPage) SetOpenGraph(v OpenGraph) {
+	if v.Title == "" {
+		v.Title = p.Title()
+	}
+	if v.Description == "" {
+		v.Description = p.Description()
+	}
+	if v.URL == "" {
+		v.URL = p.URL().String()
+	}
+	if v.Image == "" {
+		v.Image = p.Image()
+	}
+
+	p.setMetaTags(openGraphMetaTags(v, p.dispatcher.resolveStaticResource))
+}
+
+func (p browserPage) SetTwitterCard(v TwitterCard) {
+	p.setMetaTags(twitterCardMetaTags(v, p.dispatcher.resolveStaticResource))
+}
+
+func (p browserPage) SetStructuredData(v ...StructuredData) {
+	doc := Window().Get("document")
+	head := doc.Get("head")
+
+	scripts := doc.Call("querySelectorAll", "script[type='application/ld+json']")
+	for i := scripts.Length() - 1; i >= 0; i-- {
+		head.Call("removeChild", scripts.Index(i))
+	}
+
+	for _, d := range v {
+		b, err := json.Marshal(d)
+		if err != nil {
+			Log(errors.New("encoding structured data failed").Wrap(err))
+			continue
+		}
+
+		script := doc.Call("createElement", "script")
+		script.setAttr("type", "application/ld+json")
+		script.Set("text", string(b))
+		head.Call("appendChild", script)
+	}
+}
+
+func (p browserPage) setMetaTags(tags []metaTag) {
+	doc := Window().Get("document")
+
+	for _, t := range tags {
+		meta := doc.Call("querySelector", "meta["+t.attr+"='"+t.key+"']")
+		if t.content == "" && !t.required {
+			if meta.Truthy() {
+				meta.Get("parentNode").Call("removeChild", meta)
+			}
+			continue
+		}
+
+		if !meta.Truthy() {
+			meta = doc.Call("createElement", "meta")
+			meta.setAttr(t.attr, t.key)
+			doc.Get("head").Call("appendChild", meta)
+		}
+		meta.setAttr("content", t.content)
+	}
+}
+
 func (p browserPage) metaByName(v string) Value {
 	return Window().
 		Get("document").
@@ -44,4 +44,31 @@ func testPage(t *testing.T, p Page) {
 	w, h := p.Size()
 	require.NotZero(t, w)
 	require.NotZero(t, h)
+
+	p.SetOpenGraph(OpenGraph{Type: "article"})
+	p.SetTwitterCard(TwitterCard{Card: "summary"})
+	p.SetStructuredData(StructuredData{"@type": "Article"})
+}
+
+func TestRequestPageMetaTags(t *testing.T) {
+	u, _ := url.Parse("https://murlok.io/hello")
+	p := &requestPage{
+		title: "go-app",
+		image: "/web/image.png",
+		url:   u,
+	}
+	p.SetOpenGraph(OpenGraph{Description: "og description"})
+	p.SetTwitterCard(TwitterCard{})
+
+	contents := make(map[string]string)
+	for _, tag := range p.metaTags(func(v string) string { return "resolved" + v }) {
+		contents[tag.key] = tag.content
+	}
+
+	require.Equal(t, "website", contents["og:type"])
+	require.Equal(t, "go-app", contents["og:title"])
+	require.Equal(t, "og description", contents["og:description"])
+	require.Equal(t, "https://murlok.io/hello", contents["og:url"])
+	require.Equal(t, "resolved/web/image.png", contents["og:image"])
+	require.Equal(t, "summary", contents["twitter:card"])
 }
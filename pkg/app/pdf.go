@@ -0,0 +1,211 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	pdfEndpoint       = "/app-pdf"
+	pdfMaxRequestSize = 1 << 20
+)
+
+// PDFRenderer is the interface that describes a headless renderer that
+// converts prerendered pages to PDF documents. It is typically implemented
+// with a headless browser such as chromedp.
+type PDFRenderer interface {
+	// Returns the PDF document of the given HTML page. The base URL is the URL
+	// of the page, which is used to resolve the page resources such as styles
+	// and images.
+	RenderPDF(ctx context.Context, page []byte, baseURL string) ([]byte, error)
+}
+
+// PDFRequest describes a page to render as a PDF document. It is the JSON
+// body of the POST requests sent to the "/app-pdf" endpoint.
+//
+// eg:
+//  {
+//      "path": "/reports/42",
+//      "states": {
+//          "/report/period": "2021-Q4"
+//      }
+//  }
+type PDFRequest struct {
+	// The path of the route to render, with an optional query.
+	Path string `json:"path"`
+
+	// The JSON encoded values of the states that are set before prerendering
+	// the route. They can be read with Context.GetState or
+	// Context.ObserveState in OnPreRender.
+	States map[string]json.RawMessage `json:"states,omitempty"`
+}
+
+type pdfRequestKey struct{}
+
+// isPDFRequest reports whether the given request prerenders a page to convert
+// to a PDF document.
+func isPDFRequest(r *http.Request) bool {
+	_, ok := r.Context().Value(pdfRequestKey{}).(PDFRequest)
+	return ok
+}
+
+// pdfStates returns the states to set when prerendering a page for the given
+// request.
+func pdfStates(r *http.Request) map[string]json.RawMessage {
+	req, _ := r.Context().Value(pdfRequestKey{}).(PDFRequest)
+	return req.States
+}
+
+func (h *Handler) servePDF(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Del("ETag")
+
+	var req PDFRequest
+	switch r.Method {
+	case http.MethodGet:
+		req.Path = r.URL.Query().Get("path")
+
+	case http.MethodPost:
+		if !h.verifyCSRF(w, r) {
+			return
+		}
+
+		b, err := ioutil.ReadAll(io.LimitReader(r.Body, pdfMaxRequestSize+1))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if len(b) > pdfMaxRequestSize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := json.Unmarshal(b, &req); err != nil {
+			http.Error(w, "invalid pdf request", http.StatusBadRequest)
+			return
+		}
+
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	u, err := url.Parse(req.Path)
+	if err != nil || u.IsAbs() || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		http.Error(w, "invalid pdf path", http.StatusBadRequest)
+		return
+	}
+
+	r2 := r.Clone(context.WithValue(r.Context(), pdfRequestKey{}, req))
+	r2.Method = http.MethodGet
+	r2.Body = http.NoBody
+	r2.ContentLength = 0
+	r2.URL.Path = u.Path
+	r2.URL.RawPath = ""
+	r2.URL.RawQuery = u.RawQuery
+
+	var page pdfPageWriter
+	h.servePage(&page, r2)
+	if page.status != http.StatusOK {
+		for k, v := range page.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(page.status)
+		w.Write(page.body.Bytes())
+		return
+	}
+
+	baseURL, err := h.pdfBaseURL(r)
+	if err != nil {
+		Log(errors.New("getting pdf base url failed").
+			Tag("path", u.Path).
+			Wrap(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	doc, err := h.PDFRenderer.RenderPDF(r.Context(), page.body.Bytes(), baseURL+u.RequestURI())
+	if err != nil {
+		Log(errors.New("rendering pdf failed").
+			Tag("path", u.Path).
+			Wrap(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	filename := strings.ReplaceAll(path.Base(u.Path), `"`, "")
+	if filename == "/" {
+		filename = "index"
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`.pdf"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(doc)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(doc)
+}
+
+// pdfBaseURL returns the URL the pages rendered for the given request are
+// relative to. The request host is not used since a spoofed one would make the
+// renderer fetch resources from any host.
+func (h *Handler) pdfBaseURL(r *http.Request) (string, error) {
+	if h.PDFBaseURL != "" {
+		u, err := url.Parse(h.PDFBaseURL)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return "", errors.New("pdf base url is not an http url").
+				Tag("url", h.PDFBaseURL)
+		}
+		return strings.TrimSuffix(h.PDFBaseURL, "/"), nil
+	}
+
+	addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return "", errors.New("server address is unknown")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + addr.String(), nil
+}
+
+// pdfPageWriter is a response writer that buffers the page to convert to a
+// PDF document.
+type pdfPageWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *pdfPageWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *pdfPageWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *pdfPageWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
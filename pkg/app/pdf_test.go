@@ -0,0 +1,158 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/pdf-report", &pdfTestReport{})
+}
+
+type pdfTestReport struct {
+	Compo
+
+	period string
+}
+
+func (c *pdfTestReport) OnPreRender(ctx Context) {
+	ctx.GetState("/report/period", &c.period)
+}
+
+func (c *pdfTestReport) Render() UI {
+	return Div().
+		ID("pdf-report").
+		Text("period: " + c.period)
+}
+
+type pdfTestRenderer struct {
+	page    string
+	baseURL string
+	err     error
+}
+
+func (r *pdfTestRenderer) RenderPDF(ctx context.Context, page []byte, baseURL string) ([]byte, error) {
+	r.page = string(page)
+	r.baseURL = baseURL
+	return []byte("%PDF-1.4"), r.err
+}
+
+func TestHandlerServePDF(t *testing.T) {
+	renderer := &pdfTestRenderer{}
+	h := Handler{
+		PDFRenderer: renderer,
+		PDFBaseURL:  "http://example.com/",
+	}
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("route is rendered", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		require.Equal(t, `inline; filename="pdf-report.pdf"`, w.Header().Get("Content-Disposition"))
+		require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		require.Equal(t, "%PDF-1.4", w.Body.String())
+
+		require.Equal(t, "http://example.com/pdf-report", renderer.baseURL)
+		require.Contains(t, renderer.page, `id="pdf-report"`)
+		require.NotContains(t, renderer.page, "app.js")
+		require.NotContains(t, renderer.page, "app-wasm-loader")
+	})
+
+	t.Run("route is rendered with states", func(t *testing.T) {
+		body := strings.NewReader(`{"path":"/pdf-report?q=1","states":{"/report/period":"2021-Q4"}}`)
+		w := serve(httptest.NewRequest(http.MethodPost, "/app-pdf", body))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "http://example.com/pdf-report?q=1", renderer.baseURL)
+		require.Contains(t, renderer.page, "period: 2021-Q4")
+	})
+
+	t.Run("states are not cached", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/pdf-report", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotContains(t, w.Body.String(), "2021-Q4")
+		require.Contains(t, w.Body.String(), "app.js")
+	})
+
+	t.Run("spoofed host is not used", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil)
+		r.Host = "169.254.169.254"
+		w := serve(r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "http://example.com/pdf-report", renderer.baseURL)
+	})
+
+	t.Run("unknown route is not found", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-unknown", nil))
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("remote path is rejected", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/app-pdf?path=//evil.com/report", nil))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid request is rejected", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodPost, "/app-pdf", strings.NewReader("{")))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = serve(httptest.NewRequest(http.MethodDelete, "/app-pdf", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("renderer error is reported", func(t *testing.T) {
+		renderer.err = errors.New("test")
+		defer func() {
+			renderer.err = nil
+		}()
+
+		w := serve(httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestHandlerServePDFDefaultBaseURL(t *testing.T) {
+	renderer := &pdfTestRenderer{}
+	h := Handler{PDFRenderer: renderer}
+
+	t.Run("server address is used", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil)
+		r.Host = "169.254.169.254"
+		r = r.WithContext(context.WithValue(r.Context(), http.LocalAddrContextKey, &net.TCPAddr{
+			IP:   net.IPv4(127, 0, 0, 1),
+			Port: 8000,
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "http://127.0.0.1:8000/pdf-report", renderer.baseURL)
+	})
+
+	t.Run("unknown server address is reported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestHandlerServePDFDisabled(t *testing.T) {
+	h := Handler{}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app-pdf?path=/pdf-report", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The name of the performance measure that covers the mounting of the
+	// component of a page.
+	PerfMeasureMount = "goapp-mount"
+
+	// The name of the performance measure that covers the OnNav calls of a
+	// navigation.
+	PerfMeasureNav = "goapp-nav"
+
+	// The name of the performance measure that covers the first update of the
+	// components.
+	PerfMeasureFirstUpdate = "goapp-first-update"
+)
+
+// SpanRecorder is the interface that describes a Tracer that records spans
+// whose timing is already known, such as the performance measures made with
+// Context.PerfMeasure.
+//
+// Measures are recorded with tracers that don't implement it as spans that
+// have a "perf.duration_ms" attribute and that end as soon as they start.
+type SpanRecorder interface {
+	// Records a span with the given name, timing and attributes. The span is
+	// a child of the span that is carried by the given context, if any.
+	RecordSpan(ctx context.Context, name string, start, end time.Time, attrs map[string]interface{})
+}
+
+func (t *otlpTracer) RecordSpan(ctx context.Context, name string, start, end time.Time, attrs map[string]interface{}) {
+	_, span := t.StartSpan(ctx, name)
+	s := span.(*otlpSpan)
+	s.data.StartTimeUnixNano = strconv.FormatInt(start.UnixNano(), 10)
+	for k, v := range attrs {
+		s.data.Attributes = append(s.data.Attributes, makeOTLPAttribute(k, v))
+	}
+
+	s.once.Do(func() {
+		s.data.EndTimeUnixNano = strconv.FormatInt(end.UnixNano(), 10)
+		t.record(s.data)
+	})
+}
+
+// perfMark creates a mark with the given name in the browser performance
+// timeline.
+func perfMark(name string) {
+	if IsServer {
+		return
+	}
+
+	if perf := Window().Get("performance"); perf.Truthy() && perf.Get("mark").Truthy() {
+		perf.Call("mark", name)
+	}
+}
+
+// perfMeasure creates a measure between the given marks in the browser
+// performance timeline and records it with the given tracer. The measure ends
+// now when end is empty. It returns the measured duration.
+func perfMeasure(t Tracer, ctx context.Context, name, start, end string) time.Duration {
+	if IsServer {
+		return 0
+	}
+
+	perf := Window().Get("performance")
+	if !perf.Truthy() || !perf.Get("measure").Truthy() {
+		return 0
+	}
+
+	args := []interface{}{name, start}
+	if end != "" {
+		args = append(args, end)
+	}
+
+	var entry Value
+	func() {
+		defer func() {
+			if err := recover(); err != nil {
+				Log(errors.New("measuring performance failed").
+					Tag("name", name).
+					Tag("start", start).
+					Tag("end", end).
+					Tag("reason", err))
+			}
+		}()
+		entry = perf.Call("measure", args...)
+	}()
+
+	if entry == nil {
+		return 0
+	}
+	if !entry.Truthy() {
+		entries := perf.Call("getEntriesByName", name, "measure")
+		if !entries.Truthy() || entries.Length() == 0 {
+			return 0
+		}
+		entry = entries.Index(entries.Length() - 1)
+	}
+
+	startTime := entry.Get("startTime").Float()
+	duration := entry.Get("duration").Float()
+	if t != nil {
+		origin := perf.Get("timeOrigin").Float()
+		recordPerfMeasure(t, ctx, name, perfTime(origin+startTime), perfTime(origin+startTime+duration))
+	}
+	return time.Duration(duration * float64(time.Millisecond))
+}
+
+func recordPerfMeasure(t Tracer, ctx context.Context, name string, start, end time.Time) {
+	duration := float64(end.Sub(start)) / float64(time.Millisecond)
+	attrs := map[string]interface{}{
+		"perf.duration_ms": duration,
+	}
+
+	if r, ok := t.(SpanRecorder); ok {
+		r.RecordSpan(ctx, name, start, end, attrs)
+		return
+	}
+
+	_, span := t.StartSpan(ctx, name)
+	for k, v := range attrs {
+		span.SetAttribute(k, v)
+	}
+	span.End()
+}
+
+// perfTime converts the given number of milliseconds since the Unix epoch to
+// a time.
+func perfTime(ms float64) time.Time {
+	sec, frac := math.Modf(ms / 1000)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+func (e *engine) perfMeasure(name, start, end string) time.Duration {
+	if e.RunsInServer {
+		return 0
+	}
+	return perfMeasure(e.Tracer, e.TraceContext, name, start, end)
+}
+
+// perfSection creates a start mark for the measure with the given name and
+// returns a function that ends the measure. It does nothing on the server.
+func (e *engine) perfSection(name string) func() {
+	if e.RunsInServer || IsServer {
+		return func() {}
+	}
+
+	start := name + "-start"
+	perfMark(start)
+	return func() {
+		end := name + "-end"
+		perfMark(end)
+		e.perfMeasure(name, start, end)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfTime(t *testing.T) {
+	require.Equal(t, time.Unix(1614834367, 250000000), perfTime(1614834367250))
+}
+
+func TestRecordPerfMeasure(t *testing.T) {
+	start := time.Unix(1614834367, 0)
+	end := start.Add(1500 * time.Millisecond)
+
+	t.Run("span recorder", func(t *testing.T) {
+		tracer := NewOTLPTracer("http://localhost:4318/v1/traces", "test").(*otlpTracer)
+		recordPerfMeasure(tracer, context.Background(), "search", start, end)
+
+		tracer.mutex.Lock()
+		defer tracer.mutex.Unlock()
+		require.Len(t, tracer.spans, 1)
+
+		span := tracer.spans[0]
+		require.Equal(t, "search", span.Name)
+		require.Equal(t, "1614834367000000000", span.StartTimeUnixNano)
+		require.Equal(t, "1614834368500000000", span.EndTimeUnixNano)
+		require.Equal(t, []otlpAttribute{makeOTLPAttribute("perf.duration_ms", float64(1500))}, span.Attributes)
+	})
+
+	t.Run("tracer", func(t *testing.T) {
+		tracer := &testPerfTracer{}
+		recordPerfMeasure(tracer, context.Background(), "search", start, end)
+		require.Equal(t, []string{"search"}, tracer.spans)
+		require.Equal(t, float64(1500), tracer.attrs["perf.duration_ms"])
+	})
+}
+
+func TestContextPerf(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	ctx := makeContext(h)
+	ctx.PerfMark("foo-start")
+	require.Zero(t, ctx.PerfMeasure("foo", "foo-start", ""))
+}
+
+func TestEngineFirstUpdate(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+	require.True(t, e.isUpdatedOnce)
+}
+
+type testPerfTracer struct {
+	spans []string
+	attrs map[string]interface{}
+}
+
+func (t *testPerfTracer) StartSpan(ctx context.Context, name string) (context.Context, TraceSpan) {
+	t.spans = append(t.spans, name)
+	t.attrs = make(map[string]interface{})
+	return ctx, &testPerfSpan{attrs: t.attrs}
+}
+
+type testPerfSpan struct {
+	noopSpan
+	attrs map[string]interface{}
+}
+
+func (s *testPerfSpan) SetAttribute(k string, v interface{}) {
+	s.attrs[k] = v
+}
@@ -0,0 +1,103 @@
+package app
+
+const (
+	// ReducedMotionState is the state where whether the user prefers reduced
+	// motion is stored, as a bool. See Context.ObserveReducedMotion.
+	ReducedMotionState = "/app/prefers-reduced-motion"
+
+	// ContrastState is the state where the contrast preferred by the user is
+	// stored. See Context.ObserveContrast.
+	ContrastState = "/app/prefers-contrast"
+
+	reducedMotionQuery = "(prefers-reduced-motion: reduce)"
+)
+
+var (
+	// The values of the prefers-contrast media feature, other than
+	// "no-preference".
+	contrastPreferences = []string{
+		"more",
+		"less",
+		"custom",
+	}
+
+	preferences preferenceTracker
+)
+
+// preferenceTracker listens to the changes of the prefers-reduced-motion and
+// prefers-contrast media features and stores their values in
+// ReducedMotionState and ContrastState.
+type preferenceTracker struct {
+	started       bool
+	reducedMotion bool
+	contrast      string
+}
+
+func (t *preferenceTracker) start(d Dispatcher) {
+	if t.started || IsServer || !Window().Get("matchMedia").Truthy() {
+		return
+	}
+	t.started = true
+	t.reducedMotion = prefersReducedMotion()
+	t.contrast = prefersContrast()
+	t.store(d)
+
+	onChange := FuncOf(func(this Value, args []Value) interface{} {
+		reducedMotion := prefersReducedMotion()
+		contrast := prefersContrast()
+		if reducedMotion != t.reducedMotion || contrast != t.contrast {
+			t.reducedMotion = reducedMotion
+			t.contrast = contrast
+			t.store(d)
+		}
+		return nil
+	})
+
+	Window().Call("matchMedia", reducedMotionQuery).Call("addEventListener", "change", onChange)
+	for _, c := range contrastPreferences {
+		Window().Call("matchMedia", contrastQuery(c)).Call("addEventListener", "change", onChange)
+	}
+}
+
+func (t *preferenceTracker) store(d Dispatcher) {
+	reducedMotion := t.reducedMotion
+	contrast := t.contrast
+
+	d.Dispatch(Dispatch{
+		Mode: Update,
+		Function: func(ctx Context) {
+			ctx.SetState(ReducedMotionState, reducedMotion)
+			ctx.SetState(ContrastState, contrast)
+		},
+	})
+}
+
+// prefersReducedMotion reports whether the user asked the system to minimize
+// the amount of non-essential motion.
+func prefersReducedMotion() bool {
+	if !Window().Get("matchMedia").Truthy() {
+		return false
+	}
+	return Window().
+		Call("matchMedia", reducedMotionQuery).
+		Get("matches").
+		Bool()
+}
+
+// prefersContrast returns the contrast requested by the user: "more", "less",
+// "custom" or "no-preference".
+func prefersContrast() string {
+	if !Window().Get("matchMedia").Truthy() {
+		return "no-preference"
+	}
+	for _, c := range contrastPreferences {
+		if Window().Call("matchMedia", contrastQuery(c)).Get("matches").Bool() {
+			return c
+		}
+	}
+	return "no-preference"
+}
+
+func contrastQuery(contrast string) string {
+	return "(prefers-contrast: " + contrast + ")"
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferences(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	ctx := makeContext(h)
+	require.False(t, ctx.PrefersReducedMotion())
+	require.Equal(t, "no-preference", ctx.PrefersContrast())
+
+	var reducedMotion bool
+	var contrast string
+	ctx.ObserveReducedMotion().Value(&reducedMotion)
+	ctx.ObserveContrast().Value(&contrast)
+	d.Consume()
+	require.False(t, reducedMotion)
+	require.Empty(t, contrast)
+
+	d.SetState(ReducedMotionState, true)
+	d.SetState(ContrastState, "more")
+	d.Consume()
+	require.True(t, reducedMotion)
+	require.Equal(t, "more", contrast)
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Preload describes a resource that is critical to render the pages served by
+// a route.
+type Preload struct {
+	// The path or URL of the resource. See RouteCanonical for the template
+	// syntax.
+	Href string
+
+	// The type of content of the resource. eg: "image", "font", "style" or
+	// "script".
+	As string
+
+	// The MIME type of the resource. eg: "font/woff2".
+	Type string
+
+	// The CORS settings of the resource request. It is required for fonts.
+	// eg: "anonymous".
+	CrossOrigin string
+}
+
+// RoutePreload sets the critical resources of the pages served by the route
+// registered with the given path or regular expression pattern.
+//
+// eg:
+//  app.RoutePreload("/",
+//      app.Preload{Href: "/web/hero.jpg", As: "image"},
+//      app.Preload{Href: "/web/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+//  )
+//
+// Resources are emitted as preload link tags during prerender. They are
+// prefetched when PrefetchRoute is called with a path served by the route,
+// which happens when a link to the path is hovered.
+func RoutePreload(route string, resources ...Preload) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.preloads = resources
+	})
+}
+
+// PrefetchRoute prefetches the critical resources of the route that serves the
+// given path. It does nothing when called on a server or when the resources
+// were already prefetched.
+func PrefetchRoute(path string) {
+	if IsServer {
+		return
+	}
+
+	prefetchedMutex.Lock()
+	defer prefetchedMutex.Unlock()
+
+	doc := Window().Get("document")
+	for _, p := range routePreloads(path, clientStaticResourceResolver) {
+		if prefetched[p.Href] {
+			continue
+		}
+		prefetched[p.Href] = true
+
+		link := doc.Call("createElement", "link")
+		link.setAttr("rel", "prefetch")
+		link.setAttr("href", p.Href)
+		if p.As != "" {
+			link.setAttr("as", p.As)
+		}
+		if p.Type != "" {
+			link.setAttr("type", p.Type)
+		}
+		if p.CrossOrigin != "" {
+			link.setAttr("crossorigin", p.CrossOrigin)
+		}
+		doc.Get("head").Call("appendChild", link)
+	}
+}
+
+var (
+	prefetchedMutex sync.Mutex
+	prefetched      = make(map[string]bool)
+
+	clientStaticResourceResolver = func(path string) string { return path }
+)
+
+func routePreloads(path string, resolve func(string) string) []Preload {
+	meta, groups := routes.meta(path)
+
+	preloads := make([]Preload, 0, len(meta.preloads))
+	for _, p := range meta.preloads {
+		p.Href = resolve(expandRouteTemplate(p.Href, path, groups))
+		preloads = append(preloads, p)
+	}
+	return preloads
+}
+
+func renderPreloadLinks(preloads []Preload) []UI {
+	elems := make([]UI, 0, len(preloads))
+	for _, p := range preloads {
+		link := Link().
+			Rel("preload").
+			Href(p.Href)
+		if p.As != "" {
+			link.As(p.As)
+		}
+		if p.Type != "" {
+			link.Type(p.Type)
+		}
+		if p.CrossOrigin != "" {
+			link.CrossOrigin(p.CrossOrigin)
+		}
+		elems = append(elems, link)
+	}
+	return elems
+}
+
+func onAnchorHover(this Value, args []Value) interface{} {
+	elem := args[0].Get("target")
+
+	for elem.Truthy() {
+		switch elem.Get("tagName").String() {
+		case "A":
+			href := elem.Get("href")
+			if !href.Truthy() || !isInternalURL(href.String()) {
+				return nil
+			}
+
+			u, err := url.Parse(href.String())
+			if err != nil {
+				return nil
+			}
+
+			path := strings.TrimPrefix(u.Path, rootPrefix)
+			if path == "" {
+				path = "/"
+			}
+			_, path = splitLocalePath(path, appLocales)
+			PrefetchRoute(path)
+			return nil
+
+		case "BODY":
+			return nil
+
+		default:
+			elem = elem.Get("parentElement")
+		}
+	}
+	return nil
+}
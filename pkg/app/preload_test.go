@@ -0,0 +1,57 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/preload-test", &routeCompo{})
+	RoutePreload("/preload-test",
+		Preload{Href: "/web/hero.jpg", As: "image"},
+		Preload{Href: "https://fonts.example.com/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+	)
+
+	RouteWithRegexp("^/preload-test/user/(?P<name>[^/]+)$", &routeWithRegexpCompo{})
+	RoutePreload("^/preload-test/user/(?P<name>[^/]+)$", Preload{Href: "/web/avatars/{name}.jpg", As: "image"})
+}
+
+func TestRoutePreloads(t *testing.T) {
+	resolve := func(path string) string { return "/static" + path }
+
+	t.Run("route preloads are resolved", func(t *testing.T) {
+		preloads := routePreloads("/preload-test", resolve)
+		require.Len(t, preloads, 2)
+		require.Equal(t, "/static/web/hero.jpg", preloads[0].Href)
+		require.Equal(t, "font", preloads[1].As)
+	})
+
+	t.Run("route preloads are expanded", func(t *testing.T) {
+		preloads := routePreloads("/preload-test/user/maxence", resolve)
+		require.Len(t, preloads, 1)
+		require.Equal(t, "/static/web/avatars/maxence.jpg", preloads[0].Href)
+	})
+
+	t.Run("route without preloads", func(t *testing.T) {
+		require.Empty(t, routePreloads("/", resolve))
+	})
+}
+
+func TestHandlerServePagePreloads(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/preload-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Regexp(t, `<link [^>]*href="/web/hero.jpg"`, body)
+	require.Regexp(t, `<link [^>]*rel="preload"`, body)
+	require.Regexp(t, `<link [^>]*crossorigin="anonymous"`, body)
+}
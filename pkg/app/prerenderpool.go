@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"runtime"
+)
+
+// engineBuffers are the buffers of a pre-rendering engine that are reused by
+// the engines that pre-render the following requests.
+type engineBuffers struct {
+	dispatches     chan Dispatch
+	highDispatches chan Dispatch
+	lowDispatches  chan Dispatch
+	updates        map[Composer]struct{}
+	updateQueue    []updateDescriptor
+	defers         []Dispatch
+}
+
+func newEngineBuffers() *engineBuffers {
+	return &engineBuffers{
+		dispatches:     make(chan Dispatch, eventBufferSize),
+		highDispatches: make(chan Dispatch, eventBufferSize),
+		lowDispatches:  make(chan Dispatch, eventBufferSize),
+		updates:        make(map[Composer]struct{}),
+		updateQueue:    make([]updateDescriptor, 0, updateBufferSize),
+		defers:         make([]Dispatch, 0, deferBufferSize),
+	}
+}
+
+// reset clears the buffers of the given engine, which is closed, to make them
+// reusable by another engine.
+func (b *engineBuffers) reset(e *engine) {
+	if e.dispatches == nil {
+		return
+	}
+
+	for _, dispatches := range []chan Dispatch{e.highDispatches, e.dispatches, e.lowDispatches} {
+		for len(dispatches) != 0 {
+			<-dispatches
+		}
+	}
+
+	for c := range e.updates {
+		delete(e.updates, c)
+	}
+
+	queue := e.updateQueue[:cap(e.updateQueue)]
+	for i := range queue {
+		queue[i] = updateDescriptor{}
+	}
+
+	defers := e.defers[:cap(e.defers)]
+	for i := range defers {
+		defers[i] = Dispatch{}
+	}
+
+	b.dispatches = e.dispatches
+	b.highDispatches = e.highDispatches
+	b.lowDispatches = e.lowDispatches
+	b.updates = e.updates
+	b.updateQueue = queue[:0]
+	b.defers = defers[:0]
+}
+
+// prerenderPool bounds the number of pages that are pre-rendered concurrently
+// and keeps the buffers of idle pre-rendering engines warm.
+type prerenderPool struct {
+	slots chan struct{}
+	idle  chan *engineBuffers
+}
+
+// newPrerenderPool creates a pool that pre-renders at most the given number of
+// pages concurrently. There is no limit when concurrency is zero, and the
+// number of warm engines is the number of CPUs.
+func newPrerenderPool(concurrency int) *prerenderPool {
+	size := concurrency
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	p := &prerenderPool{
+		idle: make(chan *engineBuffers, size),
+	}
+	if concurrency > 0 {
+		p.slots = make(chan struct{}, concurrency)
+	}
+
+	for i := 0; i < size; i++ {
+		p.idle <- newEngineBuffers()
+	}
+	return p
+}
+
+// acquire waits for a pre-rendering slot and returns the buffers of an idle
+// engine. It returns an error when the given context is done before a slot is
+// available.
+func (p *prerenderPool) acquire(ctx context.Context) (*engineBuffers, error) {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	select {
+	case b := <-p.idle:
+		return b, nil
+
+	default:
+		return newEngineBuffers(), nil
+	}
+}
+
+// release makes the buffers of the given closed engine available to the next
+// pre-rendering and frees its slot.
+func (p *prerenderPool) release(e *engine) {
+	b := e.buffers
+	b.reset(e)
+
+	select {
+	case p.idle <- b:
+	default:
+	}
+
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+func (h *Handler) initPrerenderPool() {
+	h.prerenders = newPrerenderPool(h.PreRenderConcurrency)
+}
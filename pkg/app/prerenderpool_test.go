@@ -0,0 +1,127 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrerenderPool(t *testing.T) {
+	t.Run("concurrency is bounded", func(t *testing.T) {
+		p := newPrerenderPool(1)
+
+		b, err := p.acquire(context.Background())
+		require.NoError(t, err)
+		e := engine{buffers: b}
+		e.init()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		_, err = p.acquire(ctx)
+		require.Error(t, err)
+
+		p.release(&e)
+		_, err = p.acquire(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("buffers are reset and reused", func(t *testing.T) {
+		p := newPrerenderPool(1)
+
+		b, err := p.acquire(context.Background())
+		require.NoError(t, err)
+
+		e := engine{buffers: b}
+		e.init()
+		e.Dispatch(Dispatch{})
+		e.defers = append(e.defers, Dispatch{Mode: Defer})
+		e.updates[&hello{}] = struct{}{}
+		e.updateQueue = append(e.updateQueue, updateDescriptor{priority: 1})
+		p.release(&e)
+
+		reused, err := p.acquire(context.Background())
+		require.NoError(t, err)
+		require.True(t, b == reused)
+		require.Empty(t, reused.dispatches)
+		require.Empty(t, reused.updates)
+		require.Empty(t, reused.updateQueue)
+		require.Empty(t, reused.defers)
+		require.Equal(t, Dispatch{}, reused.defers[:1][0])
+	})
+
+	t.Run("released engine without init is ignored", func(t *testing.T) {
+		p := newPrerenderPool(1)
+
+		b, err := p.acquire(context.Background())
+		require.NoError(t, err)
+		p.release(&engine{buffers: b})
+
+		reused, err := p.acquire(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, reused.dispatches)
+	})
+}
+
+func TestEngineIgnoresDispatchesFromPreviousEngine(t *testing.T) {
+	p := newPrerenderPool(1)
+
+	b, err := p.acquire(context.Background())
+	require.NoError(t, err)
+	previous := engine{buffers: b}
+	previous.init()
+	previous.Close()
+	p.release(&previous)
+
+	b, err = p.acquire(context.Background())
+	require.NoError(t, err)
+	e := engine{buffers: b}
+	e.init()
+	defer e.Close()
+
+	called := false
+	previous.Dispatch(Dispatch{
+		Mode: Next,
+		Function: func(Context) {
+			called = true
+		},
+	})
+	e.Consume()
+	require.False(t, called)
+
+	e.Dispatch(Dispatch{
+		Mode: Next,
+		Function: func(Context) {
+			called = true
+		},
+	})
+	e.Consume()
+	require.True(t, called)
+}
+
+func TestHandlerPreRenderConcurrency(t *testing.T) {
+	h := Handler{PreRenderConcurrency: 2}
+
+	var wg sync.WaitGroup
+	codes := make([]int, 16)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			codes[i] = w.Code
+		}(i)
+	}
+	wg.Wait()
+
+	for _, c := range codes {
+		require.Equal(t, http.StatusOK, c)
+	}
+}
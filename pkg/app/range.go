@@ -143,6 +143,12 @@ func (r rangeLoop) mount(Dispatcher) error {
 		Tag("kind", r.Kind())
 }
 
+func (r rangeLoop) hydrate(Dispatcher, Value) error {
+	return errors.New("range loop is not hydratable").
+		Tag("name", r.name()).
+		Tag("kind", r.Kind())
+}
+
 func (r rangeLoop) dismount() {
 }
 
@@ -164,6 +170,18 @@ func (r rangeLoop) onAppInstallChange() {
 func (r rangeLoop) onResize() {
 }
 
+func (r rangeLoop) onVersionSkew() {
+}
+
+func (r rangeLoop) onAppInstalled() {
+}
+
+func (r rangeLoop) onDisplayModeChange() {
+}
+
+func (r rangeLoop) onAppLaunch(LaunchParams) {
+}
+
 func (r rangeLoop) preRender(Page) {
 }
 
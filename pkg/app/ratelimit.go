@@ -0,0 +1,139 @@
+package app
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimiterCleanupInterval = time.Minute
+)
+
+// RateLimiter is the interface that describes a rate limiter that decides
+// whether a request identified by a key is allowed to be served.
+type RateLimiter interface {
+	// Reports whether a request identified by the given key is allowed. When
+	// the request is not allowed, it returns the duration to wait before the
+	// next request is allowed.
+	Allow(key string) (allowed bool, retryAfter time.Duration)
+}
+
+// NewTokenBucketRateLimiter creates a rate limiter that uses a token bucket per
+// key. Buckets are refilled with the given number of tokens per second and can
+// hold at most burst tokens. Each allowed request consumes a token.
+func NewTokenBucketRateLimiter(tokensPerSecond float64, burst int) RateLimiter {
+	if tokensPerSecond <= 0 {
+		tokensPerSecond = 1
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &tokenBucketRateLimiter{
+		rate:    tokensPerSecond,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+type tokenBucketRateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mutex       sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func (l *tokenBucketRateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			tokens:    l.burst,
+			updatedAt: now,
+		}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		missing := 1 - b.tokens
+		return false, time.Duration(missing / l.rate * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+func (l *tokenBucketRateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < rateLimiterCleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+
+	fullAfter := time.Duration(l.burst / l.rate * float64(time.Second))
+	for k, b := range l.buckets {
+		if now.Sub(b.updatedAt) > fullAfter {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// RemoteIP returns the IP address of the client that sent the given request.
+// It is the default key used to rate limit requests.
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (h *Handler) limitRequest(w http.ResponseWriter, r *http.Request) bool {
+	if h.RateLimiter != nil {
+		key := h.RateLimitKey
+		if key == nil {
+			key = RemoteIP
+		}
+
+		if allowed, retryAfter := h.RateLimiter.Allow(key(r)); !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return true
+		}
+	}
+
+	if h.MaxRequestBodySize > 0 && r.Body != nil {
+		if r.ContentLength > h.MaxRequestBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return true
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, h.MaxRequestBodySize)
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+//go:build !wasm
+
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucketRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewTokenBucketRateLimiter(1, 2).(*tokenBucketRateLimiter)
+	l.now = func() time.Time { return now }
+
+	allowed, _ := l.Allow("foo")
+	require.True(t, allowed)
+	allowed, _ = l.Allow("foo")
+	require.True(t, allowed)
+
+	allowed, retryAfter := l.Allow("foo")
+	require.False(t, allowed)
+	require.Equal(t, time.Second, retryAfter)
+
+	allowed, _ = l.Allow("bar")
+	require.True(t, allowed)
+
+	now = now.Add(time.Second)
+	allowed, _ = l.Allow("foo")
+	require.True(t, allowed)
+
+	now = now.Add(time.Hour)
+	l.Allow("bar")
+	require.Len(t, l.buckets, 1)
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+	h := Handler{
+		RateLimiter: NewTokenBucketRateLimiter(0.001, 1),
+		HealthEndpoints: HealthEndpoints{
+			HealthPath: "/healthz",
+		},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/app.css")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve("/app.css")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = serve("/healthz")
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandlerRateLimitKey(t *testing.T) {
+	h := Handler{
+		RateLimiter: NewTokenBucketRateLimiter(0.001, 1),
+		RateLimitKey: func(r *http.Request) string {
+			return r.Header.Get("X-API-Key")
+		},
+	}
+
+	serve := func(key string) int {
+		r := httptest.NewRequest(http.MethodGet, "/app.css", nil)
+		r.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("foo"))
+	require.Equal(t, http.StatusOK, serve("bar"))
+	require.Equal(t, http.StatusTooManyRequests, serve("foo"))
+}
+
+func TestHandlerMaxRequestBodySize(t *testing.T) {
+	t.Run("content length exceeding limit", func(t *testing.T) {
+		h := Handler{MaxRequestBodySize: 4}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("body reader is limited", func(t *testing.T) {
+		h := Handler{MaxRequestBodySize: 4}
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+		r.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		limited := h.limitRequest(w, r)
+		require.False(t, limited)
+
+		_, err := ioutil.ReadAll(r.Body)
+		require.Error(t, err)
+	})
+}
+
+func TestRemoteIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	require.Equal(t, "192.0.2.1", RemoteIP(r))
+
+	r.RemoteAddr = "192.0.2.1"
+	require.Equal(t, "192.0.2.1", RemoteIP(r))
+}
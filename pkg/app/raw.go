@@ -121,6 +121,25 @@ func (r *raw) mount(d Dispatcher) error {
 	return nil
 }
 
+func (r *raw) hydrate(d Dispatcher, v Value) error {
+	if r.Mounted() {
+		return errors.New("hydrating raw html element failed").
+			Tag("reason", "already mounted").
+			Tag("name", r.name()).
+			Tag("kind", r.Kind())
+	}
+
+	if r.tag == "" ||
+		v.Get("nodeType").Int() != elementNode ||
+		!strings.EqualFold(v.Get("tagName").String(), r.tag) {
+		return errHydrationMismatch(r, v)
+	}
+
+	r.disp = d
+	r.jsvalue = v
+	return nil
+}
+
 func (r *raw) dismount() {
 	r.jsvalue = nil
 }
@@ -163,6 +182,18 @@ func (r *raw) onAppInstallChange() {
 func (r *raw) onResize() {
 }
 
+func (r *raw) onVersionSkew() {
+}
+
+func (r *raw) onAppInstalled() {
+}
+
+func (r *raw) onDisplayModeChange() {
+}
+
+func (r *raw) onAppLaunch(LaunchParams) {
+}
+
 func (r *raw) preRender(Page) {
 }
 
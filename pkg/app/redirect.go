@@ -0,0 +1,49 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// pageRedirect describes a redirection requested while a page is
+// pre-rendered.
+type pageRedirect struct {
+	url  string
+	code int
+}
+
+// redirect records the first redirection requested while a page is
+// pre-rendered. On the client, it navigates to the given URL and replaces the
+// current history entry.
+func (e *engine) redirect(rawURL string, code int) {
+	if code < 300 || code > 399 {
+		code = http.StatusFound
+	}
+
+	if e.RunsInServer {
+		if e.pageRedirect == nil {
+			e.pageRedirect = &pageRedirect{
+				url:  rawURL,
+				code: code,
+			}
+		}
+		return
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		Log(errors.New("redirecting failed").
+			Tag("url", rawURL).
+			Wrap(err))
+		return
+	}
+	if !u.IsAbs() {
+		u = Window().URL().ResolveReference(u)
+	}
+	navigateTo(e, u, false)
+	if !isExternalNavigation(u) && !IsServer {
+		Window().replaceHistory(u)
+	}
+}
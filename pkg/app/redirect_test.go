@@ -0,0 +1,70 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/redirect-test", &redirectCompo{})
+}
+
+type redirectCompo struct {
+	Compo
+}
+
+func (c *redirectCompo) OnPreRender(ctx Context) {
+	ctx.Redirect("/login", http.StatusSeeOther)
+	ctx.Redirect("/other", http.StatusMovedPermanently)
+}
+
+func (c *redirectCompo) Render() UI {
+	return Div().Text("account")
+}
+
+func TestHandlerRedirect(t *testing.T) {
+	h := Handler{}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redirect-test", nil))
+
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	require.Equal(t, "/login", w.Header().Get("Location"))
+	require.NotContains(t, w.Body.String(), "account")
+}
+
+func TestEngineRedirect(t *testing.T) {
+	utests := []struct {
+		scenario     string
+		code         int
+		expectedCode int
+	}{
+		{
+			scenario:     "redirect code is kept",
+			code:         http.StatusTemporaryRedirect,
+			expectedCode: http.StatusTemporaryRedirect,
+		},
+		{
+			scenario:     "non redirect code is replaced",
+			code:         http.StatusOK,
+			expectedCode: http.StatusFound,
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			d := NewServerTester(Div()).(*engine)
+			defer d.Close()
+
+			d.redirect("/login", u.code)
+			require.Equal(t, &pageRedirect{
+				url:  "/login",
+				code: u.expectedCode,
+			}, d.pageRedirect)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	relativeTimeMinDelay = time.Second
+	relativeTimeDay      = 24 * time.Hour
+)
+
+type relativeTimeUnit struct {
+	name  string
+	size  time.Duration
+	until time.Duration
+}
+
+// The units of the relative times, with the duration until which they are
+// used.
+var relativeTimeUnits = []relativeTimeUnit{
+	{name: "second", size: time.Minute, until: time.Minute},
+	{name: "minute", size: time.Minute, until: time.Hour},
+	{name: "hour", size: time.Hour, until: relativeTimeDay},
+	{name: "day", size: relativeTimeDay, until: 7 * relativeTimeDay},
+	{name: "week", size: 7 * relativeTimeDay, until: 30 * relativeTimeDay},
+	{name: "month", size: 30 * relativeTimeDay, until: 365 * relativeTimeDay},
+	{name: "year", size: 365 * relativeTimeDay, until: 1<<63 - 1},
+}
+
+// RelativeTime returns a time element that displays the given time relative to
+// now, such as "3 minutes ago", "yesterday" or "in 2 hours". The text is
+// formatted with Intl.RelativeTimeFormat for the locale of the current page.
+//
+// The element updates itself only when its text changes: every minute during
+// the first hour, then every hour during the first day, and so on. Times
+// under a minute from now are displayed as "now".
+//
+// eg:
+//  app.Span().Body(
+//      app.Text("Posted "),
+//      app.RelativeTime(post.CreatedAt),
+//  )
+func RelativeTime(t time.Time) UI {
+	return &relativeTime{Time: t}
+}
+
+type relativeTime struct {
+	Compo
+
+	Time time.Time
+
+	timer *time.Timer
+}
+
+func (r *relativeTime) OnMount(ctx Context) {
+	r.schedule(ctx)
+}
+
+func (r *relativeTime) OnUpdate(ctx Context) {
+	r.schedule(ctx)
+}
+
+func (r *relativeTime) OnDismount() {
+	r.stop()
+}
+
+// schedule schedules the update of the element for when its text changes.
+func (r *relativeTime) schedule(ctx Context) {
+	r.stop()
+
+	_, _, delay := relativeTimeValue(r.Time, time.Now())
+	r.timer = time.AfterFunc(delay, func() {
+		ctx.Dispatch(r.schedule)
+	})
+}
+
+func (r *relativeTime) stop() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+func (r *relativeTime) Render() UI {
+	value, unit, _ := relativeTimeValue(r.Time, time.Now())
+	locale := pageLocale(r.dispatcher().currentPage().URL(), r.dispatcher().locales())
+
+	return Time().
+		DateTime(r.Time.Format(time.RFC3339)).
+		Title(r.Time.In(r.dispatcher().timeZone()).Format("2006-01-02 15:04")).
+		Text(formatRelativeTime(locale, value, unit))
+}
+
+// relativeTimeValue returns the value and the unit that describe the given
+// time relative to now, as well as the duration after which they change. The
+// value is negative for times in the past.
+func relativeTimeValue(t, now time.Time) (int64, string, time.Duration) {
+	d := now.Sub(t)
+	past := d >= 0
+	if !past {
+		d = -d
+	}
+
+	u := relativeTimeUnits[len(relativeTimeUnits)-1]
+	for _, unit := range relativeTimeUnits {
+		if d < unit.until {
+			u = unit
+			break
+		}
+	}
+
+	value := int64(d / u.size)
+
+	var delay time.Duration
+	if past {
+		delay = time.Duration(value+1)*u.size - d
+		if next := u.until - d; next < delay {
+			delay = next
+		}
+		value = -value
+	} else {
+		delay = d - time.Duration(value)*u.size
+	}
+	if delay < relativeTimeMinDelay {
+		delay = relativeTimeMinDelay
+	}
+
+	return value, u.name, delay
+}
+
+// formatRelativeTime formats the given relative time with
+// Intl.RelativeTimeFormat in the browser, and in English on the server.
+func formatRelativeTime(locale string, value int64, unit string) string {
+	if s, ok := formatIntl("RelativeTimeFormat", locale, map[string]interface{}{
+		"numeric": "auto",
+	}, value, unit); ok {
+		return s
+	}
+
+	if value == 0 {
+		return "now"
+	}
+
+	n := value
+	if n < 0 {
+		n = -n
+	}
+	s := strconv.FormatInt(n, 10) + " " + unit
+	if n != 1 {
+		s += "s"
+	}
+
+	if value < 0 {
+		return s + " ago"
+	}
+	return "in " + s
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelativeTimeValue(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	utests := []struct {
+		scenario string
+		time     time.Time
+		value    int64
+		unit     string
+		delay    time.Duration
+	}{
+		{scenario: "now", time: now.Add(-20 * time.Second), value: 0, unit: "second", delay: 40 * time.Second},
+		{scenario: "minutes ago", time: now.Add(-3*time.Minute - 20*time.Second), value: -3, unit: "minute", delay: 40 * time.Second},
+		{scenario: "hours ago", time: now.Add(-2*time.Hour - 59*time.Minute), value: -2, unit: "hour", delay: time.Minute},
+		{scenario: "yesterday", time: now.Add(-day - time.Hour), value: -1, unit: "day", delay: 23 * time.Hour},
+		{scenario: "weeks ago switch to months", time: now.Add(-29 * day), value: -4, unit: "week", delay: day},
+		{scenario: "months ago", time: now.Add(-45 * day), value: -1, unit: "month", delay: 15 * day},
+		{scenario: "years ago", time: now.Add(-800 * day), value: -2, unit: "year", delay: 295 * day},
+		{scenario: "in minutes", time: now.Add(5*time.Minute + 10*time.Second), value: 5, unit: "minute", delay: 10 * time.Second},
+		{scenario: "in seconds", time: now.Add(10 * time.Second), value: 0, unit: "second", delay: 10 * time.Second},
+		{scenario: "minimum delay", time: now.Add(5 * time.Minute), value: 5, unit: "minute", delay: time.Second},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			value, unit, delay := relativeTimeValue(u.time, now)
+			require.Equal(t, u.value, value)
+			require.Equal(t, u.unit, unit)
+			require.Equal(t, u.delay, delay)
+		})
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	if !IsServer {
+		t.Skip()
+	}
+
+	require.Equal(t, "now", formatRelativeTime("", 0, "second"))
+	require.Equal(t, "3 minutes ago", formatRelativeTime("", -3, "minute"))
+	require.Equal(t, "1 day ago", formatRelativeTime("", -1, "day"))
+	require.Equal(t, "in 2 hours", formatRelativeTime("", 2, "hour"))
+}
+
+func TestRelativeTime(t *testing.T) {
+	rt := RelativeTime(time.Now().Add(-3 * time.Minute)).(*relativeTime)
+	d := NewClientTester(rt)
+	defer d.Close()
+	require.NotNil(t, rt.timer)
+
+	if IsServer {
+		require.NoError(t, TestMatch(rt, TestUIDescriptor{
+			Path:     TestPath(0, 0),
+			Expected: Text("3 minutes ago"),
+		}))
+	}
+
+	t.Run("time update is rendered", func(t *testing.T) {
+		d.Mount(RelativeTime(time.Now().Add(2*time.Hour + 30*time.Second)))
+		d.Consume()
+		require.NotNil(t, rt.timer)
+
+		if IsServer {
+			require.NoError(t, TestMatch(rt, TestUIDescriptor{
+				Path:     TestPath(0, 0),
+				Expected: Text("in 2 hours"),
+			}))
+		}
+	})
+
+	t.Run("timer is stopped when dismounted", func(t *testing.T) {
+		d.Mount(Div())
+		d.Consume()
+		require.Nil(t, rt.timer)
+	})
+}
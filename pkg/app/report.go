@@ -0,0 +1,185 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+const (
+	// The type of the reports that describe an unhandled error.
+	ClientReportError = "error"
+
+	// The type of the reports that describe a rejected promise that was not
+	// handled.
+	ClientReportRejection = "unhandledrejection"
+
+	// The type of the reports that describe a task that blocked the main
+	// thread for more than 50ms. The value is the task duration in
+	// milliseconds.
+	ClientReportLongTask = "longtask"
+
+	// The type of the reports that contain the Largest Contentful Paint of a
+	// page, in milliseconds.
+	ClientReportLCP = "lcp"
+
+	// The type of the reports that contain the First Input Delay of a page, in
+	// milliseconds.
+	ClientReportFID = "fid"
+
+	// The type of the reports that contain the Cumulative Layout Shift score
+	// of a page.
+	ClientReportCLS = "cls"
+
+	// The type of the reports that contain a performance measure, such as
+	// the ones made with Context.PerfMeasure. The message is the measure name
+	// and the value its duration in milliseconds.
+	ClientReportMeasure = "measure"
+
+	clientReportEndpoint     = "/app-report"
+	clientReportMaxBodySize  = 1 << 16
+	clientReportMaxBatchSize = 100
+)
+
+// ClientReports describes the endpoint that receives the errors and the web
+// vitals collected in the browser. The endpoint is served at "/app-report"
+// and is disabled when OnReport is nil.
+//
+// Reports are batched and sent when the page is hidden, every 10 seconds, or
+// once 20 reports are queued.
+//
+// eg:
+//  app.Handler{
+//      ClientReports: app.ClientReports{
+//          SampleRate: 0.1,
+//          OnReport: func(r *http.Request, reports []app.ClientReport) {
+//              for _, report := range reports {
+//                  log.Println(report.Type, report.URL, report.Message, report.Value)
+//              }
+//          },
+//      },
+//  },
+type ClientReports struct {
+	// The function called with the reports received from a client.
+	OnReport func(*http.Request, []ClientReport)
+
+	// The fraction of page loads, from 0 to 1, that report long tasks,
+	// performance measures and web vitals. A negative rate disables them.
+	//
+	// Default: 1.
+	SampleRate float64
+
+	// The fraction of page loads, from 0 to 1, that report errors and
+	// rejected promises. A negative rate disables them.
+	//
+	// Default: 1.
+	ErrorSampleRate float64
+}
+
+// ClientReport describes an event collected in the browser.
+type ClientReport struct {
+	// The report type. eg: ClientReportError.
+	Type string `json:"type"`
+
+	// The error message.
+	Message string `json:"message,omitempty"`
+
+	// The error stack trace.
+	Stack string `json:"stack,omitempty"`
+
+	// The script where the error occurred.
+	Source string `json:"source,omitempty"`
+
+	// The line where the error occurred.
+	Line int `json:"line,omitempty"`
+
+	// The column where the error occurred.
+	Column int `json:"column,omitempty"`
+
+	// The measured value of long tasks and web vitals.
+	Value float64 `json:"value,omitempty"`
+
+	// The path of the page where the report was collected.
+	URL string `json:"url"`
+
+	// The time when the report was collected.
+	Time time.Time `json:"time"`
+
+	// The version of the app that collected the report.
+	Version string `json:"version,omitempty"`
+}
+
+func (h *Handler) initClientReports() {
+	if h.ClientReports.OnReport == nil {
+		return
+	}
+
+	sampleRate := func(rate float64) float64 {
+		switch {
+		case rate == 0:
+			return 1
+		case rate < 0:
+			return 0
+		default:
+			return rate
+		}
+	}
+
+	if h.Env == nil {
+		h.Env = make(Environment)
+	}
+	h.Env["GOAPP_REPORT_URL"] = h.resolvePackagePath(clientReportEndpoint)
+	h.Env["GOAPP_REPORT_SAMPLE_RATE"] = strconv.FormatFloat(sampleRate(h.ClientReports.SampleRate), 'f', -1, 64)
+	h.Env["GOAPP_REPORT_ERROR_SAMPLE_RATE"] = strconv.FormatFloat(sampleRate(h.ClientReports.ErrorSampleRate), 'f', -1, 64)
+}
+
+func (h *Handler) serveClientReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, clientReportMaxBodySize+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(b) > clientReportMaxBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var reports []ClientReport
+	if err := json.Unmarshal(b, &reports); err != nil {
+		http.Error(w, "invalid reports", http.StatusBadRequest)
+		return
+	}
+	if len(reports) > clientReportMaxBatchSize {
+		reports = reports[:clientReportMaxBatchSize]
+	}
+
+	if len(reports) != 0 {
+		h.ClientReports.OnReport(r, reports)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reportPanic reports the given value recovered from a panic to the client
+// reports endpoint.
+func reportPanic(v interface{}) {
+	if IsServer || v == nil {
+		return
+	}
+
+	Window().Call("goappReport", map[string]interface{}{
+		"type":    ClientReportError,
+		"message": "panic: " + fmt.Sprint(v),
+		"stack":   string(debug.Stack()),
+	})
+}
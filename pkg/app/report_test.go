@@ -0,0 +1,100 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerClientReports(t *testing.T) {
+	var received []ClientReport
+	h := Handler{
+		ClientReports: ClientReports{
+			SampleRate: 0.25,
+			OnReport: func(r *http.Request, reports []ClientReport) {
+				received = append(received, reports...)
+			},
+		},
+	}
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, clientReportEndpoint, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("app js env", func(t *testing.T) {
+		w := serve(http.MethodGet, "")
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+		r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		body := w.Body.String()
+		require.Contains(t, body, `"GOAPP_REPORT_URL":"/app-report"`)
+		require.Contains(t, body, `"GOAPP_REPORT_SAMPLE_RATE":"0.25"`)
+		require.Contains(t, body, `"GOAPP_REPORT_ERROR_SAMPLE_RATE":"1"`)
+	})
+
+	t.Run("reports", func(t *testing.T) {
+		w := serve(http.MethodPost, `[
+			{"type": "error", "message": "boom", "url": "/", "time": "2021-03-04T05:06:07Z", "line": 42},
+			{"type": "lcp", "value": 1234.5, "url": "/foo", "time": "2021-03-04T05:06:08Z"}
+		]`)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, []ClientReport{
+			{
+				Type:    ClientReportError,
+				Message: "boom",
+				Line:    42,
+				URL:     "/",
+				Time:    time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+			},
+			{
+				Type:  ClientReportLCP,
+				Value: 1234.5,
+				URL:   "/foo",
+				Time:  time.Date(2021, 3, 4, 5, 6, 8, 0, time.UTC),
+			},
+		}, received)
+	})
+
+	t.Run("batch is truncated", func(t *testing.T) {
+		received = nil
+		batch := "[" + strings.Repeat(`{"type":"cls"},`, clientReportMaxBatchSize+10) + `{"type":"cls"}]`
+		w := serve(http.MethodPost, batch)
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Len(t, received, clientReportMaxBatchSize)
+	})
+
+	t.Run("invalid reports", func(t *testing.T) {
+		w := serve(http.MethodPost, `{"type": "error"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("too large body", func(t *testing.T) {
+		w := serve(http.MethodPost, strings.Repeat(" ", clientReportMaxBodySize+1))
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestHandlerClientReportsDisabled(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.NotContains(t, w.Body.String(), "GOAPP_REPORT_URL\":")
+
+	r = httptest.NewRequest(http.MethodPost, clientReportEndpoint, strings.NewReader("[]"))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
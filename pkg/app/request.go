@@ -0,0 +1,127 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// Request is a read-only view of the HTTP request of a page that is
+// pre-rendered on the server.
+//
+// Pages whose components read the request are not stored in the pre-render
+// cache, since their content may vary from one user to another.
+type Request struct {
+	r    *http.Request
+	used *int32
+}
+
+// Method returns the HTTP method of the request, eg: "GET".
+func (r Request) Method() string {
+	if !r.use() {
+		return ""
+	}
+	return r.r.Method
+}
+
+// URL returns a copy of the URL of the request.
+func (r Request) URL() *url.URL {
+	if !r.use() {
+		return nil
+	}
+
+	u := *r.r.URL
+	u.Host = r.r.Host
+	if u.User != nil {
+		user := *u.User
+		u.User = &user
+	}
+	return &u
+}
+
+// Header returns the first value of the given header, or "" when it is not
+// set. The key is case insensitive.
+func (r Request) Header(k string) string {
+	if !r.use() {
+		return ""
+	}
+	return r.r.Header.Get(k)
+}
+
+// HeaderValues returns a copy of the values of the given header. The key is
+// case insensitive.
+func (r Request) HeaderValues(k string) []string {
+	if !r.use() {
+		return nil
+	}
+
+	values := r.r.Header.Values(k)
+	if len(values) == 0 {
+		return nil
+	}
+	return append([]string(nil), values...)
+}
+
+// Cookie returns the value of the given cookie, and whether it is set.
+func (r Request) Cookie(name string) (string, bool) {
+	if !r.use() {
+		return "", false
+	}
+
+	c, err := r.r.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return c.Value, true
+}
+
+// UserAgent returns the user agent of the client that sent the request.
+func (r Request) UserAgent() string {
+	return r.Header("User-Agent")
+}
+
+// RemoteAddr returns the network address of the client that sent the request,
+// eg: "203.0.113.7:51234".
+func (r Request) RemoteAddr() string {
+	if !r.use() {
+		return ""
+	}
+	return r.r.RemoteAddr
+}
+
+// RemoteIP returns the IP address of the client that sent the request. See
+// RemoteIP.
+func (r Request) RemoteIP() string {
+	if !r.use() {
+		return ""
+	}
+	return RemoteIP(r.r)
+}
+
+// use reports whether the request is set and records that the page depends on
+// it.
+func (r Request) use() bool {
+	if r.r == nil {
+		return false
+	}
+
+	if r.used != nil {
+		atomic.StoreInt32(r.used, 1)
+	}
+	return true
+}
+
+func (e *engine) request() (Request, bool) {
+	if e.Request == nil {
+		return Request{}, false
+	}
+
+	return Request{
+		r:    e.Request,
+		used: &e.isRequestUsed,
+	}, true
+}
+
+func (e *engine) requestUsed() bool {
+	return atomic.LoadInt32(&e.isRequestUsed) != 0
+}
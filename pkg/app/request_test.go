@@ -0,0 +1,93 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/request-test", &requestTestCompo{})
+}
+
+type requestTestCompo struct {
+	Compo
+
+	userAgent string
+}
+
+func (c *requestTestCompo) OnPreRender(ctx Context) {
+	if r, ok := ctx.Request(); ok {
+		c.userAgent = r.UserAgent()
+	}
+}
+
+func (c *requestTestCompo) Render() UI {
+	return Div().Text("agent: " + c.userAgent)
+}
+
+func TestRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "http://example.com/a?b=c", nil)
+	r.RemoteAddr = "203.0.113.7:51234"
+	r.Header.Add("X-Test", "1")
+	r.Header.Add("X-Test", "2")
+	r.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+
+	e := engine{Request: r}
+	req, ok := e.request()
+	require.True(t, ok)
+	require.False(t, e.requestUsed())
+
+	require.Equal(t, http.MethodPost, req.Method())
+	require.True(t, e.requestUsed())
+	require.Equal(t, "http://example.com/a?b=c", req.URL().String())
+	require.Equal(t, "1", req.Header("x-test"))
+	require.Equal(t, "203.0.113.7:51234", req.RemoteAddr())
+	require.Equal(t, "203.0.113.7", req.RemoteIP())
+
+	theme, ok := req.Cookie("theme")
+	require.True(t, ok)
+	require.Equal(t, "dark", theme)
+	_, ok = req.Cookie("lang")
+	require.False(t, ok)
+
+	values := req.HeaderValues("X-Test")
+	require.Equal(t, []string{"1", "2"}, values)
+	values[0] = "changed"
+	require.Equal(t, "1", r.Header.Get("X-Test"))
+
+	u := req.URL()
+	u.Path = "/changed"
+	require.Equal(t, "/a", r.URL.Path)
+}
+
+func TestRequestNotSet(t *testing.T) {
+	d := NewClientTester(Div())
+	defer d.Close()
+
+	r, ok := d.Context().Request()
+	require.False(t, ok)
+	require.Empty(t, r.Method())
+	require.Nil(t, r.URL())
+	require.Empty(t, r.Header("User-Agent"))
+	require.Empty(t, r.RemoteIP())
+}
+
+func TestHandlerRequest(t *testing.T) {
+	h := Handler{}
+
+	serve := func(userAgent string) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/request-test", nil)
+		r.Header.Set("User-Agent", userAgent)
+		h.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+
+	require.Contains(t, serve("Mobile"), "agent: Mobile")
+	require.Contains(t, serve("Desktop"), "agent: Desktop")
+}
@@ -33,6 +33,7 @@ func LocalDir(root string) ResourceProvider {
 	return localDir{
 		Handler: http.FileServer(http.Dir(root)),
 		root:    root,
+		dir:     root,
 		appWASM: root + "/web/app.wasm",
 	}
 }
@@ -40,6 +41,7 @@ func LocalDir(root string) ResourceProvider {
 type localDir struct {
 	http.Handler
 	root    string
+	dir     string
 	appWASM string
 }
 
@@ -101,6 +103,7 @@ func CustomProvider(path, prefix string) ResourceProvider {
 	return localDir{
 		Handler: http.FileServer(http.Dir(root)),
 		root:    prefix,
+		dir:     root,
 		appWASM: prefix + "/web/app.wasm",
 	}
 }
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"net/url"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// ResourceHint describes an external origin that pages connect to, such as an
+// API, a CDN or a font provider.
+type ResourceHint struct {
+	// The origin. eg: "https://fonts.gstatic.com".
+	//
+	// URLs are reduced to their origin.
+	Origin string
+
+	// The CORS settings of the connections to the origin. It is required for
+	// origins that serve fonts or that are fetched in CORS mode.
+	// eg: "anonymous".
+	CrossOrigin string
+
+	// Reports whether only the DNS lookup of the origin is performed in
+	// advance. It is meant for origins that are not used by every page, since
+	// browsers close preconnected connections that remain unused.
+	DNSPrefetchOnly bool
+}
+
+func (h *Handler) initResourceHints() {
+	hints := make([]ResourceHint, 0, len(h.ResourceHints))
+	origins := make(map[string]bool, len(h.ResourceHints))
+
+	for _, hint := range h.ResourceHints {
+		origin, err := resourceHintOrigin(hint.Origin)
+		if err != nil {
+			Log(errors.New("adding resource hint failed").
+				Tag("origin", hint.Origin).
+				Wrap(err))
+			continue
+		}
+		if origins[origin] {
+			continue
+		}
+		origins[origin] = true
+
+		hint.Origin = origin
+		hints = append(hints, hint)
+	}
+
+	h.ResourceHints = hints
+}
+
+func resourceHintOrigin(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.New("origin is not an absolute url")
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func renderResourceHints(hints []ResourceHint) []UI {
+	elems := make([]UI, 0, len(hints)*2)
+	for _, h := range hints {
+		if !h.DNSPrefetchOnly {
+			link := Link().
+				Rel("preconnect").
+				Href(h.Origin)
+			if h.CrossOrigin != "" {
+				link.CrossOrigin(h.CrossOrigin)
+			}
+			elems = append(elems, link)
+		}
+
+		elems = append(elems, Link().
+			Rel("dns-prefetch").
+			Href(h.Origin))
+	}
+	return elems
+}
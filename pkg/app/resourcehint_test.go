@@ -0,0 +1,89 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceHintOrigin(t *testing.T) {
+	utests := []struct {
+		scenario string
+		url      string
+		origin   string
+		err      bool
+	}{
+		{
+			scenario: "origin",
+			url:      "https://api.murlok.io",
+			origin:   "https://api.murlok.io",
+		},
+		{
+			scenario: "url with path is reduced to its origin",
+			url:      "https://fonts.gstatic.com/s/inter/v12/font.woff2?x=y",
+			origin:   "https://fonts.gstatic.com",
+		},
+		{
+			scenario: "origin with port",
+			url:      "http://localhost:8080/",
+			origin:   "http://localhost:8080",
+		},
+		{
+			scenario: "relative url",
+			url:      "/api",
+			err:      true,
+		},
+		{
+			scenario: "host without scheme",
+			url:      "api.murlok.io",
+			err:      true,
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			origin, err := resourceHintOrigin(u.url)
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, u.origin, origin)
+		})
+	}
+}
+
+func TestHandlerResourceHints(t *testing.T) {
+	h := Handler{
+		ResourceHints: []ResourceHint{
+			{Origin: "https://api.murlok.io/v1"},
+			{Origin: "https://api.murlok.io"},
+			{Origin: "https://fonts.gstatic.com", CrossOrigin: "anonymous"},
+			{Origin: "https://analytics.murlok.io", DNSPrefetchOnly: true},
+			{Origin: "/relative"},
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, h.ResourceHints, 3)
+	require.Equal(t, "https://api.murlok.io", h.ResourceHints[0].Origin)
+
+	body := w.Body.String()
+	require.Regexp(t, `<link [^>]*rel="preconnect"[^>]*>`, body)
+	require.Regexp(t, `<link (?:[^>]*crossorigin="anonymous"[^>]*href="https://fonts.gstatic.com"|[^>]*href="https://fonts.gstatic.com"[^>]*crossorigin="anonymous")`, body)
+	require.Regexp(t, `<link [^>]*href="https://analytics.murlok.io"`, body)
+	require.NotRegexp(t, `<link [^>]*rel="preconnect"[^>]*href="https://analytics.murlok.io"`, body)
+	require.NotRegexp(t, `<link [^>]*href="https://analytics.murlok.io"[^>]*rel="preconnect"`, body)
+	require.NotContains(t, body, `href="/relative"`)
+	require.Equal(t, 3, strings.Count(body, `rel="dns-prefetch"`))
+	require.Equal(t, 2, strings.Count(body, `rel="preconnect"`))
+}
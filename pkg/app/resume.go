@@ -0,0 +1,176 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	updateSnapshotKey = "/app/update/snapshot"
+)
+
+// updateSnapshot describes the scroll positions and the form values of a page
+// that is reloaded to load an app update.
+type updateSnapshot struct {
+	URL     string
+	ScrollX float64
+	ScrollY float64
+
+	// The scroll positions of the scrolled elements, by element ID.
+	Scrolls map[string][2]float64 `json:",omitempty"`
+
+	// The values of the form controls, by element ID.
+	Values map[string]string `json:",omitempty"`
+
+	// The checked states of the checkboxes and the radio buttons, by element
+	// ID.
+	Checked map[string]bool `json:",omitempty"`
+}
+
+// newUpdateSnapshot returns the scroll positions and the form values of the
+// elements of the given tree that have an ID. Password and file inputs are
+// skipped.
+func newUpdateSnapshot(root UI, url string, scroll ScrollPosition) updateSnapshot {
+	s := updateSnapshot{
+		URL:     url,
+		ScrollX: scroll.X,
+		ScrollY: scroll.Y,
+		Scrolls: make(map[string][2]float64),
+		Values:  make(map[string]string),
+		Checked: make(map[string]bool),
+	}
+	s.collect(root)
+	return s
+}
+
+func (s *updateSnapshot) collect(n UI) {
+	if n.Kind() == HTML && n.Mounted() {
+		if id := n.attributes()["id"]; id != "" {
+			s.collectElem(id, n)
+		}
+	}
+
+	for _, c := range n.children() {
+		s.collect(c)
+	}
+}
+
+func (s *updateSnapshot) collectElem(id string, n UI) {
+	v := n.JSValue()
+
+	if left, top := eventFloat(v, "scrollLeft"), eventFloat(v, "scrollTop"); left != 0 || top != 0 {
+		s.Scrolls[id] = [2]float64{left, top}
+	}
+
+	switch n.name() {
+	case "input":
+		switch n.attributes()["type"] {
+		case "password", "file", "hidden", "submit", "reset", "button", "image":
+
+		case "checkbox", "radio":
+			s.Checked[id] = eventBool(v, "checked")
+
+		default:
+			s.Values[id] = eventString(v, "value")
+		}
+
+	case "textarea", "select":
+		s.Values[id] = eventString(v, "value")
+	}
+}
+
+// restoreValues sets the form values of the snapshot to the elements of the
+// given tree, and emits their input and change events so bound handlers
+// update the components.
+func (s updateSnapshot) restoreValues(n UI) {
+	if n.Kind() == HTML && n.Mounted() {
+		id := n.attributes()["id"]
+		v, hasValue := s.Values[id]
+		checked, hasChecked := s.Checked[id]
+
+		switch {
+		case id == "":
+
+		case hasValue:
+			setInputValue(n.JSValue(), v)
+			emitFormEvents(n.JSValue())
+
+		case hasChecked:
+			n.JSValue().Set("checked", checked)
+			emitFormEvents(n.JSValue())
+		}
+	}
+
+	for _, c := range n.children() {
+		s.restoreValues(c)
+	}
+}
+
+// restoreScrolls sets the scroll positions of the snapshot to the window and
+// to the elements of the given tree.
+func (s updateSnapshot) restoreScrolls(n UI) {
+	s.restoreElemScrolls(n)
+	if s.ScrollX != 0 || s.ScrollY != 0 {
+		Window().Call("scrollTo", s.ScrollX, s.ScrollY)
+	}
+}
+
+func (s updateSnapshot) restoreElemScrolls(n UI) {
+	if n.Kind() == HTML && n.Mounted() {
+		if scroll, ok := s.Scrolls[n.attributes()["id"]]; ok {
+			n.JSValue().Set("scrollLeft", scroll[0])
+			n.JSValue().Set("scrollTop", scroll[1])
+		}
+	}
+
+	for _, c := range n.children() {
+		s.restoreElemScrolls(c)
+	}
+}
+
+func emitFormEvents(elem Value) {
+	for _, event := range []string{"input", "change"} {
+		elem.Call("dispatchEvent", Window().Get("Event").New(event, map[string]interface{}{
+			"bubbles": true,
+		}))
+	}
+}
+
+// saveUpdateSnapshot saves the scroll positions and the form values of the
+// current page in session storage, to restore them once the page is reloaded
+// with the updated app.
+func (e *engine) saveUpdateSnapshot() {
+	if e.Body == nil {
+		return
+	}
+
+	s := newUpdateSnapshot(e.Body, Window().URL().String(), readScrollPosition())
+	if err := e.sessionStorage().Set(updateSnapshotKey, s); err != nil {
+		Log(errors.New("saving app update snapshot failed").Wrap(err))
+	}
+}
+
+// restoreUpdateSnapshot restores the snapshot saved before the page was
+// reloaded to load an app update, once the current page is mounted. The
+// snapshot is discarded when the URL changed.
+func (e *engine) restoreUpdateSnapshot() {
+	var s updateSnapshot
+	if err := e.sessionStorage().Get(updateSnapshotKey, &s); err != nil {
+		Log(errors.New("loading app update snapshot failed").Wrap(err))
+	}
+	e.sessionStorage().Del(updateSnapshotKey)
+	if s.URL == "" || s.URL != Window().URL().String() {
+		return
+	}
+
+	e.Dispatch(Dispatch{
+		Mode:   Defer,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			s.restoreValues(ctx.Src())
+
+			ctx.AfterPaint(func(ctx Context) {
+				s.restoreScrolls(ctx.Src())
+			})
+		},
+	})
+}
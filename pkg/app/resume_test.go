@@ -0,0 +1,89 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type resumeCompo struct {
+	Compo
+}
+
+func (c *resumeCompo) Render() UI {
+	return Div().
+		ID("list").
+		Body(
+			Input().ID("email"),
+			Input().ID("password").Type("password"),
+			Input().ID("terms").Type("checkbox"),
+			Textarea().ID("message"),
+			Input(),
+		)
+}
+
+func TestUpdateSnapshot(t *testing.T) {
+	compo := &resumeCompo{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	list := compo.root
+	email := list.children()[0]
+	password := list.children()[1]
+	terms := list.children()[2]
+	message := list.children()[3]
+
+	setSimulatedProperty(list, "scrollTop", 120)
+	setSimulatedProperty(email, "value", "max@go-app.dev")
+	setSimulatedProperty(password, "value", "secret")
+	setSimulatedProperty(terms, "checked", true)
+	setSimulatedProperty(message, "value", "hello")
+
+	s := newUpdateSnapshot(compo, "/contact", ScrollPosition{Y: 42})
+	require.Equal(t, updateSnapshot{
+		URL:     "/contact",
+		ScrollY: 42,
+		Scrolls: map[string][2]float64{"list": {0, 120}},
+		Values: map[string]string{
+			"email":   "max@go-app.dev",
+			"message": "hello",
+		},
+		Checked: map[string]bool{"terms": true},
+	}, s)
+
+	restored := &resumeCompo{}
+	d2 := NewClientTester(restored)
+	defer d2.Close()
+
+	for _, c := range restored.root.children() {
+		setSimulatedProperty(c, "value", "")
+	}
+	setSimulatedProperty(restored.root, "scrollTop", 0)
+
+	s.restoreValues(restored)
+	s.restoreScrolls(restored)
+
+	children := restored.root.children()
+	require.Equal(t, "max@go-app.dev", children[0].JSValue().Get("value").String())
+	require.Equal(t, "", children[1].JSValue().Get("value").String())
+	require.True(t, children[2].JSValue().Get("checked").Bool())
+	require.Equal(t, "hello", children[3].JSValue().Get("value").String())
+	require.Equal(t, float64(120), restored.root.JSValue().Get("scrollTop").Float())
+}
+
+func TestEngineRestoreUpdateSnapshot(t *testing.T) {
+	compo := &resumeCompo{}
+	d := NewClientTester(compo).(*engine)
+	defer d.Close()
+
+	setSimulatedProperty(compo.root.children()[0], "value", "max@go-app.dev")
+	d.saveUpdateSnapshot()
+
+	var s updateSnapshot
+	require.NoError(t, d.sessionStorage().Get(updateSnapshotKey, &s))
+	require.Equal(t, "max@go-app.dev", s.Values["email"])
+
+	d.restoreUpdateSnapshot()
+	d.Consume()
+	require.Equal(t, 0, d.sessionStorage().Len())
+}
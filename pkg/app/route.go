@@ -3,7 +3,11 @@ package app
 import (
 	"reflect"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
 )
 
 var (
@@ -12,12 +16,37 @@ var (
 
 // Route associates the type of the given component to the given path.
 //
+// The path can contain parameters: a ":name" segment matches any segment and a
+// trailing "*name" segment matches the rest of the path. Parameter values are
+// retrieved with Context.RouteParam. Exact paths take precedence over
+// patterns, and static segments over parameters.
+//
+// eg:
+//  app.Route("/users/:id/posts/*rest", &userPosts{})
+//
 // When a page is requested and matches the route, a new instance of the given
 // component is created before being displayed.
 func Route(path string, c Composer) {
 	routes.route(path, c)
 }
 
+// RouteChild associates the type of the given component to the given path,
+// relative to the route registered with the given parent path or pattern.
+//
+// When a page matches a child route, the component of the top parent route is
+// displayed and the components of the child routes are displayed in the
+// Outlet elements of their parent components. On navigation, only the
+// components of the routes that changed are mounted again, which keeps
+// layouts and their states.
+//
+// eg:
+//  app.Route("/settings", &settingsLayout{})
+//  app.RouteChild("/settings", "/profile", &profileSettings{})
+//  app.RouteChild("/settings", "/users/:id", &userSettings{})
+func RouteChild(parent, path string, c Composer) {
+	routes.routeChild(parent, path, c)
+}
+
 // RouteWithRegexp associates the type of the given component to the given
 // regular expression pattern.
 //
@@ -30,14 +59,19 @@ func RouteWithRegexp(pattern string, c Composer) {
 }
 
 type router struct {
-	mu               sync.RWMutex
-	routes           map[string]reflect.Type
-	routesWithRegexp []regexpRoute
+	mu                sync.RWMutex
+	routes            map[string]reflect.Type
+	routesWithPattern []patternRoute
+	routesWithRegexp  []regexpRoute
+	metas             map[string]routeMeta
+	parents           map[string]string
 }
 
 func makeRouter() router {
 	return router{
-		routes: make(map[string]reflect.Type),
+		routes:  make(map[string]reflect.Type),
+		metas:   make(map[string]routeMeta),
+		parents: make(map[string]string),
 	}
 }
 
@@ -45,7 +79,22 @@ func (r *router) route(path string, c Composer) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.routes[path] = reflect.TypeOf(c)
+	if !isRoutePattern(path) {
+		r.routes[path] = reflect.TypeOf(c)
+		return
+	}
+
+	route := newPatternRoute(path, reflect.TypeOf(c))
+	for i, pr := range r.routesWithPattern {
+		if pr.pattern == path {
+			r.routesWithPattern[i] = route
+			return
+		}
+	}
+	r.routesWithPattern = append(r.routesWithPattern, route)
+	sort.SliceStable(r.routesWithPattern, func(i, j int) bool {
+		return r.routesWithPattern[i].precedes(r.routesWithPattern[j])
+	})
 }
 
 func (r *router) routeWithRegexp(pattern string, c Composer) {
@@ -58,14 +107,112 @@ func (r *router) routeWithRegexp(pattern string, c Composer) {
 	})
 }
 
+// routeChild associates the type of the given component to the given path,
+// relative to the given parent route.
+func (r *router) routeChild(parent, path string, c Composer) {
+	route := childRoutePath(parent, path)
+	r.route(route, c)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parents[route] = parent
+}
+
+// createComponent creates the component of the route that matches the given
+// path. It is the component of the top parent route when the matching route is
+// a child route.
 func (r *router) createComponent(path string) (Composer, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	compoType, isRouted := r.routes[path]
+	route, _, isRouted := r.match(path)
+	if !isRouted {
+		return nil, false
+	}
+	return r.newComponent(r.chain(route)[0])
+}
+
+// createChildComponent creates the component displayed at the given depth of
+// the chain of routes that matches the given path, with the route it belongs
+// to. The depth of the top parent route is 0.
+func (r *router) createChildComponent(path string, depth int) (string, Composer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, _, isRouted := r.match(path)
+	if !isRouted {
+		return "", nil, false
+	}
+
+	chain := r.chain(route)
+	if depth >= len(chain) {
+		return "", nil, false
+	}
+
+	compo, ok := r.newComponent(chain[depth])
+	return chain[depth], compo, ok
+}
+
+// match returns the route that matches the given path, with the values of the
+// parameters of the matching pattern or of the named groups of the matching
+// regular expression.
+func (r *router) match(path string) (string, map[string]string, bool) {
+	if _, isRouted := r.routes[path]; isRouted {
+		return path, nil, true
+	}
+
+	for _, pr := range r.routesWithPattern {
+		if params, ok := pr.match(path); ok {
+			return pr.pattern, params, true
+		}
+	}
+
+	for _, rwr := range r.routesWithRegexp {
+		matches := rwr.regexp.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+
+		var groups map[string]string
+		for i, name := range rwr.regexp.SubexpNames() {
+			if name == "" {
+				continue
+			}
+			if groups == nil {
+				groups = make(map[string]string)
+			}
+			groups[name] = matches[i]
+		}
+		return rwr.regexp.String(), groups, true
+	}
+
+	return "", nil, false
+}
+
+// chain returns the parent routes of the given route, from the top parent to
+// the route itself.
+func (r *router) chain(route string) []string {
+	chain := []string{route}
+	for parent, ok := r.parents[route]; ok; parent, ok = r.parents[parent] {
+		chain = append([]string{parent}, chain...)
+	}
+	return chain
+}
+
+func (r *router) newComponent(route string) (Composer, bool) {
+	compoType, isRouted := r.routes[route]
+	if !isRouted {
+		for _, pr := range r.routesWithPattern {
+			if pr.pattern == route {
+				compoType = pr.compoType
+				isRouted = true
+				break
+			}
+		}
+	}
 	if !isRouted {
 		for _, rwr := range r.routesWithRegexp {
-			if rwr.regexp.MatchString(path) {
+			if rwr.regexp.String() == route {
 				compoType = rwr.compoType
 				isRouted = true
 				break
@@ -80,11 +227,187 @@ func (r *router) createComponent(path string) (Composer, bool) {
 	return compo, true
 }
 
+func (r *router) setMeta(route string, fn func(*routeMeta)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.metas[route]
+	fn(&m)
+	r.metas[route] = m
+}
+
+// meta returns the metadata of the route that matches the given path, with
+// the values of the parameters of the matching pattern or of the named groups
+// of the matching regular expression.
+func (r *router) meta(path string) (routeMeta, map[string]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, params, isRouted := r.match(path)
+	if !isRouted {
+		return routeMeta{}, nil
+	}
+	return r.metas[route], params
+}
+
+// loader returns the loader of the given route.
+func (r *router) loader(route string) Loader {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.metas[route].loader
+}
+
+func (r *router) paths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	paths := make([]string, 0, len(r.routes))
+	for p := range r.routes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 func (r *router) len() int {
-	return len(r.routes) + len(r.routesWithRegexp)
+	return len(r.routes) + len(r.routesWithPattern) + len(r.routesWithRegexp)
+}
+
+type routeMeta struct {
+	canonical     string
+	alternates    []Alternate
+	title         string
+	titleTemplate string
+	description   string
+	preloads      []Preload
+	loader        Loader
 }
 
 type regexpRoute struct {
 	regexp    *regexp.Regexp
 	compoType reflect.Type
 }
+
+// patternRoute is a route whose path contains parameters.
+type patternRoute struct {
+	pattern   string
+	segments  []string
+	compoType reflect.Type
+}
+
+func newPatternRoute(pattern string, compoType reflect.Type) patternRoute {
+	segments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	names := make(map[string]bool, len(segments))
+
+	for i, s := range segments {
+		if !isRouteParam(s) {
+			continue
+		}
+
+		name := s[1:]
+		switch {
+		case name == "":
+			panic(errors.New("routing pattern failed").
+				Tag("pattern", pattern).
+				Tag("reason", "parameter name is empty"))
+
+		case names[name]:
+			panic(errors.New("routing pattern failed").
+				Tag("pattern", pattern).
+				Tag("parameter", name).
+				Tag("reason", "parameter name is already used"))
+
+		case s[0] == '*' && i != len(segments)-1:
+			panic(errors.New("routing pattern failed").
+				Tag("pattern", pattern).
+				Tag("parameter", name).
+				Tag("reason", "wildcard is not the last segment"))
+		}
+		names[name] = true
+	}
+
+	return patternRoute{
+		pattern:   pattern,
+		segments:  segments,
+		compoType: compoType,
+	}
+}
+
+// match returns the values of the parameters of the route when the given path
+// matches its pattern.
+func (r patternRoute) match(path string) (map[string]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+
+	parts := strings.Split(path[1:], "/")
+	params := make(map[string]string, len(r.segments))
+	for i, s := range r.segments {
+		if s != "" && s[0] == '*' {
+			params[s[1:]] = strings.Join(parts[i:], "/")
+			return params, true
+		}
+
+		if i >= len(parts) {
+			return nil, false
+		}
+		if !isRouteParam(s) {
+			if s != parts[i] {
+				return nil, false
+			}
+			continue
+		}
+		if parts[i] == "" {
+			return nil, false
+		}
+		params[s[1:]] = parts[i]
+	}
+
+	if len(parts) != len(r.segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+// precedes reports whether the route is matched before the given one: static
+// segments take precedence over parameters that take precedence over
+// wildcards.
+func (r patternRoute) precedes(o patternRoute) bool {
+	for i := 0; i < len(r.segments) && i < len(o.segments); i++ {
+		if a, b := routeSegmentRank(r.segments[i]), routeSegmentRank(o.segments[i]); a != b {
+			return a > b
+		}
+	}
+	return len(r.segments) > len(o.segments)
+}
+
+// childRoutePath returns the path of the given child route of the given
+// parent route.
+func childRoutePath(parent, path string) string {
+	return strings.TrimSuffix(parent, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func isRoutePattern(path string) bool {
+	for _, s := range strings.Split(path, "/") {
+		if isRouteParam(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRouteParam(segment string) bool {
+	return segment != "" && (segment[0] == ':' || segment[0] == '*')
+}
+
+func routeSegmentRank(segment string) int {
+	switch {
+	case !isRouteParam(segment):
+		return 2
+	case segment[0] == ':':
+		return 1
+	default:
+		return 0
+	}
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -15,6 +16,10 @@ type routeWithRegexpCompo struct {
 	Compo
 }
 
+type routeWithPatternCompo struct {
+	Compo
+}
+
 func TestRoutes(t *testing.T) {
 	utests := []struct {
 		scenario     string
@@ -50,6 +55,40 @@ func TestRoutes(t *testing.T) {
 			},
 			expected: &routeCompo{},
 		},
+		{
+			scenario: "route with parameters is routed",
+			path:     "/users/42/posts/2023/hello",
+			createRoutes: func(r *router) {
+				r.route("/users/:id/posts/*rest", &routeWithPatternCompo{})
+				r.routeWithRegexp("^/users/.*$", &routeWithRegexpCompo{})
+			},
+			expected: &routeWithPatternCompo{},
+		},
+		{
+			scenario: "path take priority over route with parameters",
+			path:     "/users/me",
+			createRoutes: func(r *router) {
+				r.route("/users/:id", &routeWithPatternCompo{})
+				r.route("/users/me", &routeCompo{})
+			},
+			expected: &routeCompo{},
+		},
+		{
+			scenario: "route with empty parameter is not routed",
+			path:     "/users//posts",
+			createRoutes: func(r *router) {
+				r.route("/users/:id/posts", &routeWithPatternCompo{})
+			},
+			notFound: true,
+		},
+		{
+			scenario: "route with parameters and trailing slash is not routed",
+			path:     "/users/42/",
+			createRoutes: func(r *router) {
+				r.route("/users/:id", &routeWithPatternCompo{})
+			},
+			notFound: true,
+		},
 		{
 			scenario: "pattern is routed",
 			path:     "/ab",
@@ -128,3 +167,125 @@ func TestRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestRouterPaths(t *testing.T) {
+	r := makeRouter()
+	r.route("/b", &routeCompo{})
+	r.route("/a", &routeCompo{})
+	r.routeWithRegexp("^/color/.*$", &routeWithRegexpCompo{})
+
+	require.Equal(t, []string{"/a", "/b"}, r.paths())
+}
+
+func TestRouterParams(t *testing.T) {
+	utests := []struct {
+		scenario string
+		routes   []string
+		path     string
+		expected map[string]string
+		route    string
+	}{
+		{
+			scenario: "parameter",
+			routes:   []string{"/users/:id"},
+			path:     "/users/42",
+			expected: map[string]string{"id": "42"},
+			route:    "/users/:id",
+		},
+		{
+			scenario: "parameter and wildcard",
+			routes:   []string{"/users/:id/posts/*rest"},
+			path:     "/users/42/posts/2023/hello",
+			expected: map[string]string{"id": "42", "rest": "2023/hello"},
+			route:    "/users/:id/posts/*rest",
+		},
+		{
+			scenario: "empty wildcard",
+			routes:   []string{"/users/:id/posts/*rest"},
+			path:     "/users/42/posts",
+			expected: map[string]string{"id": "42", "rest": ""},
+			route:    "/users/:id/posts/*rest",
+		},
+		{
+			scenario: "static segment takes priority over parameter",
+			routes:   []string{"/users/:id/:tab", "/users/:id/posts"},
+			path:     "/users/42/posts",
+			expected: map[string]string{"id": "42"},
+			route:    "/users/:id/posts",
+		},
+		{
+			scenario: "parameter takes priority over wildcard",
+			routes:   []string{"/files/*path", "/files/:name"},
+			path:     "/files/a.png",
+			expected: map[string]string{"name": "a.png"},
+			route:    "/files/:name",
+		},
+		{
+			scenario: "exact path has no parameter",
+			routes:   []string{"/users/:id", "/users/me"},
+			path:     "/users/me",
+			route:    "/users/me",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			r := makeRouter()
+			for _, route := range u.routes {
+				r.route(route, &routeWithPatternCompo{})
+				r.setMeta(route, func(m *routeMeta) {
+					m.title = route
+				})
+			}
+
+			meta, params := r.meta(u.path)
+			require.Equal(t, u.expected, params)
+			require.Equal(t, u.route, meta.title)
+		})
+	}
+}
+
+func TestRouterPatternPanics(t *testing.T) {
+	utests := []struct {
+		scenario string
+		pattern  string
+	}{
+		{
+			scenario: "empty parameter name",
+			pattern:  "/users/:",
+		},
+		{
+			scenario: "duplicated parameter name",
+			pattern:  "/users/:id/:id",
+		},
+		{
+			scenario: "wildcard is not the last segment",
+			pattern:  "/files/*path/edit",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			r := makeRouter()
+			require.Panics(t, func() {
+				r.route(u.pattern, &routeWithPatternCompo{})
+			})
+		})
+	}
+}
+
+func TestContextRouteParam(t *testing.T) {
+	Route("/route-param-test/:id/*rest", &routeWithPatternCompo{})
+
+	compo := &routeWithPatternCompo{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	u, _ := url.Parse("/route-param-test/42/a/b")
+	d.loadRoute(u, u.Path, func() {})
+
+	ctx := makeContext(compo)
+	require.Equal(t, "42", ctx.RouteParam("id"))
+	require.Equal(t, "a/b", ctx.RouteParam("rest"))
+	require.Empty(t, ctx.RouteParam("missing"))
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// ScriptOptions describes how an external script is loaded with
+// Context.LoadScript.
+type ScriptOptions struct {
+	// Reports whether the script is executed as soon as it is downloaded.
+	Async bool
+
+	// Reports whether the script is executed after the document is parsed.
+	Defer bool
+
+	// The cryptographic nonce that allows the script with a Content Security
+	// Policy.
+	Nonce string
+
+	// The hash used to verify the script integrity. eg: "sha384-...".
+	Integrity string
+
+	// The CORS settings of the script request. eg: "anonymous".
+	CrossOrigin string
+
+	// The consent category that must be granted for the script to be loaded.
+	// The script is loaded regardless of the user consent when empty.
+	Consent string
+
+	// The function called on the UI goroutine when the script is loaded. It is
+	// called immediately when the script is already loaded.
+	OnLoad func(Context)
+
+	// The function called on the UI goroutine when the script failed to load.
+	OnError func(Context, error)
+}
+
+type scriptState int
+
+const (
+	scriptLoading scriptState = iota
+	scriptLoaded
+	scriptFailed
+)
+
+type scriptCallback struct {
+	dispatcher Dispatcher
+	source     UI
+	onLoad     func(Context)
+	onError    func(Context, error)
+}
+
+type script struct {
+	state     scriptState
+	err       error
+	elem      Value
+	funcs     []Func
+	callbacks []scriptCallback
+}
+
+type scriptManager struct {
+	mutex   sync.Mutex
+	scripts map[string]*script
+}
+
+var scripts scriptManager
+
+func (m *scriptManager) load(d Dispatcher, src UI, url string, opts ScriptOptions) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.scripts == nil {
+		m.scripts = make(map[string]*script)
+	}
+
+	cb := scriptCallback{
+		dispatcher: d,
+		source:     src,
+		onLoad:     opts.OnLoad,
+		onError:    opts.OnError,
+	}
+
+	if opts.Consent != "" && !ConsentGranted(opts.Consent) {
+		cb.call(errors.New("loading script failed").
+			Tag("url", url).
+			Tag("reason", "consent not granted").
+			Tag("consent", opts.Consent))
+		return
+	}
+
+	s, isRegistered := m.scripts[url]
+	if isRegistered && s.state != scriptFailed {
+		if s.state == scriptLoaded {
+			cb.call(nil)
+			return
+		}
+		s.callbacks = append(s.callbacks, cb)
+		return
+	}
+	if isRegistered {
+		s.release()
+	}
+
+	s = &script{callbacks: []scriptCallback{cb}}
+	m.scripts[url] = s
+
+	onLoad := FuncOf(func(this Value, args []Value) interface{} {
+		m.done(url, nil)
+		return nil
+	})
+	onError := FuncOf(func(this Value, args []Value) interface{} {
+		m.done(url, errors.New("loading script failed").Tag("url", url))
+		return nil
+	})
+	s.funcs = []Func{onLoad, onError}
+
+	doc := Window().Get("document")
+	elem := doc.Call("createElement", "script")
+	elem.Set("async", opts.Async)
+	elem.Set("defer", opts.Defer)
+	if opts.Nonce != "" {
+		elem.Set("nonce", opts.Nonce)
+	}
+	if opts.Integrity != "" {
+		elem.Set("integrity", opts.Integrity)
+	}
+	if opts.CrossOrigin != "" {
+		elem.Set("crossOrigin", opts.CrossOrigin)
+	}
+	elem.Call("addEventListener", "load", onLoad)
+	elem.Call("addEventListener", "error", onError)
+	elem.Set("src", url)
+	doc.Get("head").Call("appendChild", elem)
+	s.elem = elem
+}
+
+func (m *scriptManager) done(url string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, isRegistered := m.scripts[url]
+	if !isRegistered {
+		return
+	}
+
+	s.state = scriptLoaded
+	if err != nil {
+		s.state = scriptFailed
+		s.err = err
+	}
+
+	for _, cb := range s.callbacks {
+		cb.call(err)
+	}
+	s.callbacks = nil
+}
+
+func (m *scriptManager) remove(url string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	s, isRegistered := m.scripts[url]
+	if !isRegistered {
+		return
+	}
+	delete(m.scripts, url)
+	s.release()
+}
+
+func (s *script) release() {
+	if s.elem != nil && s.elem.Truthy() {
+		s.elem.Call("remove")
+	}
+	for _, f := range s.funcs {
+		f.Release()
+	}
+	s.funcs = nil
+}
+
+func (cb scriptCallback) call(err error) {
+	if (err == nil && cb.onLoad == nil) || (err != nil && cb.onError == nil) {
+		return
+	}
+
+	cb.dispatcher.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: cb.source,
+		Function: func(ctx Context) {
+			if err != nil {
+				cb.onError(ctx, err)
+				return
+			}
+			cb.onLoad(ctx)
+		},
+	})
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptManager(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	m := scriptManager{}
+	url := "https://example.com/sdk.js"
+
+	loads := 0
+	var loadErr error
+	opts := ScriptOptions{
+		Async:   true,
+		OnLoad:  func(Context) { loads++ },
+		OnError: func(ctx Context, err error) { loadErr = err },
+	}
+
+	t.Run("script is injected once", func(t *testing.T) {
+		m.load(&e, h, url, opts)
+		m.load(&e, h, url, opts)
+		require.Len(t, m.scripts, 1)
+		require.Len(t, m.scripts[url].callbacks, 2)
+	})
+
+	t.Run("load is reported to waiting callers", func(t *testing.T) {
+		m.done(url, nil)
+		e.Consume()
+		require.Equal(t, 2, loads)
+		require.Empty(t, m.scripts[url].callbacks)
+	})
+
+	t.Run("loaded script is reported immediately", func(t *testing.T) {
+		m.load(&e, h, url, opts)
+		e.Consume()
+		require.Equal(t, 3, loads)
+	})
+
+	t.Run("removed script is injected again", func(t *testing.T) {
+		m.remove(url)
+		require.Empty(t, m.scripts)
+
+		m.load(&e, h, url, opts)
+		require.Len(t, m.scripts, 1)
+	})
+
+	t.Run("error is reported", func(t *testing.T) {
+		m.done(url, errors.New("test"))
+		e.Consume()
+		require.Error(t, loadErr)
+		require.Equal(t, scriptFailed, m.scripts[url].state)
+	})
+
+	t.Run("script without consent is not injected", func(t *testing.T) {
+		loadErr = nil
+		opts := opts
+		opts.Consent = ConsentMarketing
+
+		m.load(&e, h, "https://example.com/ads.js", opts)
+		e.Consume()
+		require.Error(t, loadErr)
+		require.Len(t, m.scripts, 1)
+	})
+}
@@ -7,11 +7,13 @@ package app
 const (
 	wasmExecJS = "// Copyright 2018 The Go Authors. All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\n(() => {\n\t// Map multiple JavaScript environments to a single common API,\n\t// preferring web standards over Node.js API.\n\t//\n\t// Environments considered:\n\t// - Browsers\n\t// - Node.js\n\t// - Electron\n\t// - Parcel\n\t// - Webpack\n\n\tif (typeof global !== \"undefined\") {\n\t\t// global already exists\n\t} else if (typeof window !== \"undefined\") {\n\t\twindow.global = window;\n\t} else if (typeof self !== \"undefined\") {\n\t\tself.global = self;\n\t} else {\n\t\tthrow new Error(\"cannot export Go (neither global, window nor self is defined)\");\n\t}\n\n\tif (!global.require && typeof require !== \"undefined\") {\n\t\tglobal.require = require;\n\t}\n\n\tif (!global.fs && global.require) {\n\t\tconst fs = require(\"fs\");\n\t\tif (typeof fs === \"object\" && fs !== null && Object.keys(fs).length !== 0) {\n\t\t\tglobal.fs = fs;\n\t\t}\n\t}\n\n\tconst enosys = () => {\n\t\tconst err = new Error(\"not implemented\");\n\t\terr.code = \"ENOSYS\";\n\t\treturn err;\n\t};\n\n\tif (!global.fs) {\n\t\tlet outputBuf = \"\";\n\t\tglobal.fs = {\n\t\t\tconstants: { O_WRONLY: -1, O_RDWR: -1, O_CREAT: -1, O_TRUNC: -1, O_APPEND: -1, O_EXCL: -1 }, // unused\n\t\t\twriteSync(fd, buf) {\n\t\t\t\toutputBuf += decoder.decode(buf);\n\t\t\t\tconst nl = outputBuf.lastIndexOf(\"\\n\");\n\t\t\t\tif (nl != -1) {\n\t\t\t\t\tconsole.log(outputBuf.substr(0, nl));\n\t\t\t\t\toutputBuf = outputBuf.substr(nl + 1);\n\t\t\t\t}\n\t\t\t\treturn buf.length;\n\t\t\t},\n\t\t\twrite(fd, buf, offset, length, position, callback) {\n\t\t\t\tif (offset !== 0 || length !== buf.length || position !== null) {\n\t\t\t\t\tcallback(enosys());\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tconst n = this.writeSync(fd, buf);\n\t\t\t\tcallback(null, n);\n\t\t\t},\n\t\t\tchmod(path, mode, callback) { callback(enosys()); },\n\t\t\tchown(path, uid, gid, callback) { callback(enosys()); },\n\t\t\tclose(fd, callback) { callback(enosys()); },\n\t\t\tfchmod(fd, mode, callback) { callback(enosys()); },\n\t\t\tfchown(fd, uid, gid, callback) { callback(enosys()); },\n\t\t\tfstat(fd, callback) { callback(enosys()); },\n\t\t\tfsync(fd, callback) { callback(null); },\n\t\t\tftruncate(fd, length, callback) { callback(enosys()); },\n\t\t\tlchown(path, uid, gid, callback) { callback(enosys()); },\n\t\t\tlink(path, link, callback) { callback(enosys()); },\n\t\t\tlstat(path, callback) { callback(enosys()); },\n\t\t\tmkdir(path, perm, callback) { callback(enosys()); },\n\t\t\topen(path, flags, mode, callback) { callback(enosys()); },\n\t\t\tread(fd, buffer, offset, length, position, callback) { callback(enosys()); },\n\t\t\treaddir(path, callback) { callback(enosys()); },\n\t\t\treadlink(path, callback) { callback(enosys()); },\n\t\t\trename(from, to, callback) { callback(enosys()); },\n\t\t\trmdir(path, callback) { callback(enosys()); },\n\t\t\tstat(path, callback) { callback(enosys()); },\n\t\t\tsymlink(path, link, callback) { callback(enosys()); },\n\t\t\ttruncate(path, length, callback) { callback(enosys()); },\n\t\t\tunlink(path, callback) { callback(enosys()); },\n\t\t\tutimes(path, atime, mtime, callback) { callback(enosys()); },\n\t\t};\n\t}\n\n\tif (!global.process) {\n\t\tglobal.process = {\n\t\t\tgetuid() { return -1; },\n\t\t\tgetgid() { return -1; },\n\t\t\tgeteuid() { return -1; },\n\t\t\tgetegid() { return -1; },\n\t\t\tgetgroups() { throw enosys(); },\n\t\t\tpid: -1,\n\t\t\tppid: -1,\n\t\t\tumask() { throw enosys(); },\n\t\t\tcwd() { throw enosys(); },\n\t\t\tchdir() { throw enosys(); },\n\t\t}\n\t}\n\n\tif (!global.crypto && global.require) {\n\t\tconst nodeCrypto = require(\"crypto\");\n\t\tglobal.crypto = {\n\t\t\tgetRandomValues(b) {\n\t\t\t\tnodeCrypto.randomFillSync(b);\n\t\t\t},\n\t\t};\n\t}\n\tif (!global.crypto) {\n\t\tthrow new Error(\"global.crypto is not available, polyfill required (getRandomValues only)\");\n\t}\n\n\tif (!global.performance) {\n\t\tglobal.performance = {\n\t\t\tnow() {\n\t\t\t\tconst [sec, nsec] = process.hrtime();\n\t\t\t\treturn sec * 1000 + nsec / 1000000;\n\t\t\t},\n\t\t};\n\t}\n\n\tif (!global.TextEncoder && global.require) {\n\t\tglobal.TextEncoder = require(\"util\").TextEncoder;\n\t}\n\tif (!global.TextEncoder) {\n\t\tthrow new Error(\"global.TextEncoder is not available, polyfill required\");\n\t}\n\n\tif (!global.TextDecoder && global.require) {\n\t\tglobal.TextDecoder = require(\"util\").TextDecoder;\n\t}\n\tif (!global.TextDecoder) {\n\t\tthrow new Error(\"global.TextDecoder is not available, polyfill required\");\n\t}\n\n\t// End of polyfills for common API.\n\n\tconst encoder = new TextEncoder(\"utf-8\");\n\tconst decoder = new TextDecoder(\"utf-8\");\n\n\tglobal.Go = class {\n\t\tconstructor() {\n\t\t\tthis.argv = [\"js\"];\n\t\t\tthis.env = {};\n\t\t\tthis.exit = (code) => {\n\t\t\t\tif (code !== 0) {\n\t\t\t\t\tconsole.warn(\"exit code:\", code);\n\t\t\t\t}\n\t\t\t};\n\t\t\tthis._exitPromise = new Promise((resolve) => {\n\t\t\t\tthis._resolveExitPromise = resolve;\n\t\t\t});\n\t\t\tthis._pendingEvent = null;\n\t\t\tthis._scheduledTimeouts = new Map();\n\t\t\tthis._nextCallbackTimeoutID = 1;\n\n\t\t\tconst setInt64 = (addr, v) => {\n\t\t\t\tthis.mem.setUint32(addr + 0, v, true);\n\t\t\t\tthis.mem.setUint32(addr + 4, Math.floor(v / 4294967296), true);\n\t\t\t}\n\n\t\t\tconst getInt64 = (addr) => {\n\t\t\t\tconst low = this.mem.getUint32(addr + 0, true);\n\t\t\t\tconst high = this.mem.getInt32(addr + 4, true);\n\t\t\t\treturn low + high * 4294967296;\n\t\t\t}\n\n\t\t\tconst loadValue = (addr) => {\n\t\t\t\tconst f = this.mem.getFloat64(addr, true);\n\t\t\t\tif (f === 0) {\n\t\t\t\t\treturn undefined;\n\t\t\t\t}\n\t\t\t\tif (!isNaN(f)) {\n\t\t\t\t\treturn f;\n\t\t\t\t}\n\n\t\t\t\tconst id = this.mem.getUint32(addr, true);\n\t\t\t\treturn this._values[id];\n\t\t\t}\n\n\t\t\tconst storeValue = (addr, v) => {\n\t\t\t\tconst nanHead = 0x7FF80000;\n\n\t\t\t\tif (typeof v === \"number\" && v !== 0) {\n\t\t\t\t\tif (isNaN(v)) {\n\t\t\t\t\t\tthis.mem.setUint32(addr + 4, nanHead, true);\n\t\t\t\t\t\tthis.mem.setUint32(addr, 0, true);\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\tthis.mem.setFloat64(addr, v, true);\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tif (v === undefined) {\n\t\t\t\t\tthis.mem.setFloat64(addr, 0, true);\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tlet id = this._ids.get(v);\n\t\t\t\tif (id === undefined) {\n\t\t\t\t\tid = this._idPool.pop();\n\t\t\t\t\tif (id === undefined) {\n\t\t\t\t\t\tid = this._values.length;\n\t\t\t\t\t}\n\t\t\t\t\tthis._values[id] = v;\n\t\t\t\t\tthis._goRefCounts[id] = 0;\n\t\t\t\t\tthis._ids.set(v, id);\n\t\t\t\t}\n\t\t\t\tthis._goRefCounts[id]++;\n\t\t\t\tlet typeFlag = 0;\n\t\t\t\tswitch (typeof v) {\n\t\t\t\t\tcase \"object\":\n\t\t\t\t\t\tif (v !== null) {\n\t\t\t\t\t\t\ttypeFlag = 1;\n\t\t\t\t\t\t}\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase \"string\":\n\t\t\t\t\t\ttypeFlag = 2;\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase \"symbol\":\n\t\t\t\t\t\ttypeFlag = 3;\n\t\t\t\t\t\tbreak;\n\t\t\t\t\tcase \"function\":\n\t\t\t\t\t\ttypeFlag = 4;\n\t\t\t\t\t\tbreak;\n\t\t\t\t}\n\t\t\t\tthis.mem.setUint32(addr + 4, nanHead | typeFlag, true);\n\t\t\t\tthis.mem.setUint32(addr, id, true);\n\t\t\t}\n\n\t\t\tconst loadSlice = (addr) => {\n\t\t\t\tconst array = getInt64(addr + 0);\n\t\t\t\tconst len = getInt64(addr + 8);\n\t\t\t\treturn new Uint8Array(this._inst.exports.mem.buffer, array, len);\n\t\t\t}\n\n\t\t\tconst loadSliceOfValues = (addr) => {\n\t\t\t\tconst array = getInt64(addr + 0);\n\t\t\t\tconst len = getInt64(addr + 8);\n\t\t\t\tconst a = new Array(len);\n\t\t\t\tfor (let i = 0; i < len; i++) {\n\t\t\t\t\ta[i] = loadValue(array + i * 8);\n\t\t\t\t}\n\t\t\t\treturn a;\n\t\t\t}\n\n\t\t\tconst loadString = (addr) => {\n\t\t\t\tconst saddr = getInt64(addr + 0);\n\t\t\t\tconst len = getInt64(addr + 8);\n\t\t\t\treturn decoder.decode(new DataView(this._inst.exports.mem.buffer, saddr, len));\n\t\t\t}\n\n\t\t\tconst timeOrigin = Date.now() - performance.now();\n\t\t\tthis.importObject = {\n\t\t\t\tgo: {\n\t\t\t\t\t// Go's SP does not change as long as no Go code is running. Some operations (e.g. calls, getters and setters)\n\t\t\t\t\t// may synchronously trigger a Go event handler. This makes Go code get executed in the middle of the imported\n\t\t\t\t\t// function. A goroutine can switch to a new stack if the current stack is too small (see morestack function).\n\t\t\t\t\t// This changes the SP, thus we have to update the SP used by the imported function.\n\n\t\t\t\t\t// func wasmExit(code int32)\n\t\t\t\t\t\"runtime.wasmExit\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst code = this.mem.getInt32(sp + 8, true);\n\t\t\t\t\t\tthis.exited = true;\n\t\t\t\t\t\tdelete this._inst;\n\t\t\t\t\t\tdelete this._values;\n\t\t\t\t\t\tdelete this._goRefCounts;\n\t\t\t\t\t\tdelete this._ids;\n\t\t\t\t\t\tdelete this._idPool;\n\t\t\t\t\t\tthis.exit(code);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func wasmWrite(fd uintptr, p unsafe.Pointer, n int32)\n\t\t\t\t\t\"runtime.wasmWrite\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst fd = getInt64(sp + 8);\n\t\t\t\t\t\tconst p = getInt64(sp + 16);\n\t\t\t\t\t\tconst n = this.mem.getInt32(sp + 24, true);\n\t\t\t\t\t\tfs.writeSync(fd, new Uint8Array(this._inst.exports.mem.buffer, p, n));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func resetMemoryDataView()\n\t\t\t\t\t\"runtime.resetMemoryDataView\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tthis.mem = new DataView(this._inst.exports.mem.buffer);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func nanotime1() int64\n\t\t\t\t\t\"runtime.nanotime1\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tsetInt64(sp + 8, (timeOrigin + performance.now()) * 1000000);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func walltime() (sec int64, nsec int32)\n\t\t\t\t\t\"runtime.walltime\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst msec = (new Date).getTime();\n\t\t\t\t\t\tsetInt64(sp + 8, msec / 1000);\n\t\t\t\t\t\tthis.mem.setInt32(sp + 16, (msec % 1000) * 1000000, true);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func scheduleTimeoutEvent(delay int64) int32\n\t\t\t\t\t\"runtime.scheduleTimeoutEvent\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst id = this._nextCallbackTimeoutID;\n\t\t\t\t\t\tthis._nextCallbackTimeoutID++;\n\t\t\t\t\t\tthis._scheduledTimeouts.set(id, setTimeout(\n\t\t\t\t\t\t\t() => {\n\t\t\t\t\t\t\t\tthis._resume();\n\t\t\t\t\t\t\t\twhile (this._scheduledTimeouts.has(id)) {\n\t\t\t\t\t\t\t\t\t// for some reason Go failed to register the timeout event, log and try again\n\t\t\t\t\t\t\t\t\t// (temporary workaround for https://github.com/golang/go/issues/28975)\n\t\t\t\t\t\t\t\t\tconsole.warn(\"scheduleTimeoutEvent: missed timeout event\");\n\t\t\t\t\t\t\t\t\tthis._resume();\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tgetInt64(sp + 8) + 1, // setTimeout has been seen to fire up to 1 millisecond early\n\t\t\t\t\t\t));\n\t\t\t\t\t\tthis.mem.setInt32(sp + 16, id, true);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func clearTimeoutEvent(id int32)\n\t\t\t\t\t\"runtime.clearTimeoutEvent\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst id = this.mem.getInt32(sp + 8, true);\n\t\t\t\t\t\tclearTimeout(this._scheduledTimeouts.get(id));\n\t\t\t\t\t\tthis._scheduledTimeouts.delete(id);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func getRandomData(r []byte)\n\t\t\t\t\t\"runtime.getRandomData\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tcrypto.getRandomValues(loadSlice(sp + 8));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func finalizeRef(v ref)\n\t\t\t\t\t\"syscall/js.finalizeRef\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst id = this.mem.getUint32(sp + 8, true);\n\t\t\t\t\t\tthis._goRefCounts[id]--;\n\t\t\t\t\t\tif (this._goRefCounts[id] === 0) {\n\t\t\t\t\t\t\tconst v = this._values[id];\n\t\t\t\t\t\t\tthis._values[id] = null;\n\t\t\t\t\t\t\tthis._ids.delete(v);\n\t\t\t\t\t\t\tthis._idPool.push(id);\n\t\t\t\t\t\t}\n\t\t\t\t\t},\n\n\t\t\t\t\t// func stringVal(value string) ref\n\t\t\t\t\t\"syscall/js.stringVal\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tstoreValue(sp + 24, loadString(sp + 8));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueGet(v ref, p string) ref\n\t\t\t\t\t\"syscall/js.valueGet\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst result = Reflect.get(loadValue(sp + 8), loadString(sp + 16));\n\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\tstoreValue(sp + 32, result);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueSet(v ref, p string, x ref)\n\t\t\t\t\t\"syscall/js.valueSet\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tReflect.set(loadValue(sp + 8), loadString(sp + 16), loadValue(sp + 32));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueDelete(v ref, p string)\n\t\t\t\t\t\"syscall/js.valueDelete\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tReflect.deleteProperty(loadValue(sp + 8), loadString(sp + 16));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueIndex(v ref, i int) ref\n\t\t\t\t\t\"syscall/js.valueIndex\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tstoreValue(sp + 24, Reflect.get(loadValue(sp + 8), getInt64(sp + 16)));\n\t\t\t\t\t},\n\n\t\t\t\t\t// valueSetIndex(v ref, i int, x ref)\n\t\t\t\t\t\"syscall/js.valueSetIndex\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tReflect.set(loadValue(sp + 8), getInt64(sp + 16), loadValue(sp + 24));\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueCall(v ref, m string, args []ref) (ref, bool)\n\t\t\t\t\t\"syscall/js.valueCall\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\tconst v = loadValue(sp + 8);\n\t\t\t\t\t\t\tconst m = Reflect.get(v, loadString(sp + 16));\n\t\t\t\t\t\t\tconst args = loadSliceOfValues(sp + 32);\n\t\t\t\t\t\t\tconst result = Reflect.apply(m, v, args);\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 56, result);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 64, 1);\n\t\t\t\t\t\t} catch (err) {\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 56, err);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 64, 0);\n\t\t\t\t\t\t}\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueInvoke(v ref, args []ref) (ref, bool)\n\t\t\t\t\t\"syscall/js.valueInvoke\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\tconst v = loadValue(sp + 8);\n\t\t\t\t\t\t\tconst args = loadSliceOfValues(sp + 16);\n\t\t\t\t\t\t\tconst result = Reflect.apply(v, undefined, args);\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 40, result);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 1);\n\t\t\t\t\t\t} catch (err) {\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 40, err);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 0);\n\t\t\t\t\t\t}\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueNew(v ref, args []ref) (ref, bool)\n\t\t\t\t\t\"syscall/js.valueNew\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\tconst v = loadValue(sp + 8);\n\t\t\t\t\t\t\tconst args = loadSliceOfValues(sp + 16);\n\t\t\t\t\t\t\tconst result = Reflect.construct(v, args);\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 40, result);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 1);\n\t\t\t\t\t\t} catch (err) {\n\t\t\t\t\t\t\tsp = this._inst.exports.getsp() >>> 0; // see comment above\n\t\t\t\t\t\t\tstoreValue(sp + 40, err);\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 0);\n\t\t\t\t\t\t}\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueLength(v ref) int\n\t\t\t\t\t\"syscall/js.valueLength\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tsetInt64(sp + 16, parseInt(loadValue(sp + 8).length));\n\t\t\t\t\t},\n\n\t\t\t\t\t// valuePrepareString(v ref) (ref, int)\n\t\t\t\t\t\"syscall/js.valuePrepareString\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst str = encoder.encode(String(loadValue(sp + 8)));\n\t\t\t\t\t\tstoreValue(sp + 16, str);\n\t\t\t\t\t\tsetInt64(sp + 24, str.length);\n\t\t\t\t\t},\n\n\t\t\t\t\t// valueLoadString(v ref, b []byte)\n\t\t\t\t\t\"syscall/js.valueLoadString\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst str = loadValue(sp + 8);\n\t\t\t\t\t\tloadSlice(sp + 16).set(str);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func valueInstanceOf(v ref, t ref) bool\n\t\t\t\t\t\"syscall/js.valueInstanceOf\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tthis.mem.setUint8(sp + 24, (loadValue(sp + 8) instanceof loadValue(sp + 16)) ? 1 : 0);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func copyBytesToGo(dst []byte, src ref) (int, bool)\n\t\t\t\t\t\"syscall/js.copyBytesToGo\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst dst = loadSlice(sp + 8);\n\t\t\t\t\t\tconst src = loadValue(sp + 32);\n\t\t\t\t\t\tif (!(src instanceof Uint8Array || src instanceof Uint8ClampedArray)) {\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 0);\n\t\t\t\t\t\t\treturn;\n\t\t\t\t\t\t}\n\t\t\t\t\t\tconst toCopy = src.subarray(0, dst.length);\n\t\t\t\t\t\tdst.set(toCopy);\n\t\t\t\t\t\tsetInt64(sp + 40, toCopy.length);\n\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 1);\n\t\t\t\t\t},\n\n\t\t\t\t\t// func copyBytesToJS(dst ref, src []byte) (int, bool)\n\t\t\t\t\t\"syscall/js.copyBytesToJS\": (sp) => {\n\t\t\t\t\t\tsp >>>= 0;\n\t\t\t\t\t\tconst dst = loadValue(sp + 8);\n\t\t\t\t\t\tconst src = loadSlice(sp + 16);\n\t\t\t\t\t\tif (!(dst instanceof Uint8Array || dst instanceof Uint8ClampedArray)) {\n\t\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 0);\n\t\t\t\t\t\t\treturn;\n\t\t\t\t\t\t}\n\t\t\t\t\t\tconst toCopy = src.subarray(0, dst.length);\n\t\t\t\t\t\tdst.set(toCopy);\n\t\t\t\t\t\tsetInt64(sp + 40, toCopy.length);\n\t\t\t\t\t\tthis.mem.setUint8(sp + 48, 1);\n\t\t\t\t\t},\n\n\t\t\t\t\t\"debug\": (value) => {\n\t\t\t\t\t\tconsole.log(value);\n\t\t\t\t\t},\n\t\t\t\t}\n\t\t\t};\n\t\t}\n\n\t\tasync run(instance) {\n\t\t\tif (!(instance instanceof WebAssembly.Instance)) {\n\t\t\t\tthrow new Error(\"Go.run: WebAssembly.Instance expected\");\n\t\t\t}\n\t\t\tthis._inst = instance;\n\t\t\tthis.mem = new DataView(this._inst.exports.mem.buffer);\n\t\t\tthis._values = [ // JS values that Go currently has references to, indexed by reference id\n\t\t\t\tNaN,\n\t\t\t\t0,\n\t\t\t\tnull,\n\t\t\t\ttrue,\n\t\t\t\tfalse,\n\t\t\t\tglobal,\n\t\t\t\tthis,\n\t\t\t];\n\t\t\tthis._goRefCounts = new Array(this._values.length).fill(Infinity); // number of references that Go has to a JS value, indexed by reference id\n\t\t\tthis._ids = new Map([ // mapping from JS values to reference ids\n\t\t\t\t[0, 1],\n\t\t\t\t[null, 2],\n\t\t\t\t[true, 3],\n\t\t\t\t[false, 4],\n\t\t\t\t[global, 5],\n\t\t\t\t[this, 6],\n\t\t\t]);\n\t\t\tthis._idPool = [];   // unused ids that have been garbage collected\n\t\t\tthis.exited = false; // whether the Go program has exited\n\n\t\t\t// Pass command line arguments and environment variables to WebAssembly by writing them to the linear memory.\n\t\t\tlet offset = 4096;\n\n\t\t\tconst strPtr = (str) => {\n\t\t\t\tconst ptr = offset;\n\t\t\t\tconst bytes = encoder.encode(str + \"\\0\");\n\t\t\t\tnew Uint8Array(this.mem.buffer, offset, bytes.length).set(bytes);\n\t\t\t\toffset += bytes.length;\n\t\t\t\tif (offset % 8 !== 0) {\n\t\t\t\t\toffset += 8 - (offset % 8);\n\t\t\t\t}\n\t\t\t\treturn ptr;\n\t\t\t};\n\n\t\t\tconst argc = this.argv.length;\n\n\t\t\tconst argvPtrs = [];\n\t\t\tthis.argv.forEach((arg) => {\n\t\t\t\targvPtrs.push(strPtr(arg));\n\t\t\t});\n\t\t\targvPtrs.push(0);\n\n\t\t\tconst keys = Object.keys(this.env).sort();\n\t\t\tkeys.forEach((key) => {\n\t\t\t\targvPtrs.push(strPtr(`${key}=${this.env[key]}`));\n\t\t\t});\n\t\t\targvPtrs.push(0);\n\n\t\t\tconst argv = offset;\n\t\t\targvPtrs.forEach((ptr) => {\n\t\t\t\tthis.mem.setUint32(offset, ptr, true);\n\t\t\t\tthis.mem.setUint32(offset + 4, 0, true);\n\t\t\t\toffset += 8;\n\t\t\t});\n\n\t\t\tthis._inst.exports.run(argc, argv);\n\t\t\tif (this.exited) {\n\t\t\t\tthis._resolveExitPromise();\n\t\t\t}\n\t\t\tawait this._exitPromise;\n\t\t}\n\n\t\t_resume() {\n\t\t\tif (this.exited) {\n\t\t\t\tthrow new Error(\"Go program has already exited\");\n\t\t\t}\n\t\t\tthis._inst.exports.resume();\n\t\t\tif (this.exited) {\n\t\t\t\tthis._resolveExitPromise();\n\t\t\t}\n\t\t}\n\n\t\t_makeFuncWrapper(id) {\n\t\t\tconst go = this;\n\t\t\treturn function () {\n\t\t\t\tconst event = { id: id, this: this, args: arguments };\n\t\t\t\tgo._pendingEvent = event;\n\t\t\t\tgo._resume();\n\t\t\t\treturn event.result;\n\t\t\t};\n\t\t}\n\t}\n\n\tif (\n\t\ttypeof module !== \"undefined\" &&\n\t\tglobal.require &&\n\t\tglobal.require.main === module &&\n\t\tglobal.process &&\n\t\tglobal.process.versions &&\n\t\t!global.process.versions.electron\n\t) {\n\t\tif (process.argv.length < 3) {\n\t\t\tconsole.error(\"usage: go_js_wasm_exec [wasm binary] [arguments]\");\n\t\t\tprocess.exit(1);\n\t\t}\n\n\t\tconst go = new Go();\n\t\tgo.argv = process.argv.slice(2);\n\t\tgo.env = Object.assign({ TMPDIR: require(\"os\").tmpdir() }, process.env);\n\t\tgo.exit = process.exit;\n\t\tWebAssembly.instantiate(fs.readFileSync(process.argv[2]), go.importObject).then((result) => {\n\t\t\tprocess.on(\"exit\", (code) => { // Node.js exits if no event handler is pending\n\t\t\t\tif (code === 0 && !go.exited) {\n\t\t\t\t\t// deadlock, make Go print error and stack traces\n\t\t\t\t\tgo._pendingEvent = { id: 0 };\n\t\t\t\t\tgo._resume();\n\t\t\t\t}\n\t\t\t});\n\t\t\treturn go.run(result.instance);\n\t\t}).catch((err) => {\n\t\t\tconsole.error(err);\n\t\t\tprocess.exit(1);\n\t\t});\n\t}\n})();\n"
 
-	appJS = "// -----------------------------------------------------------------------------\n// Init service worker\n// -----------------------------------------------------------------------------\nvar goappOnUpdate = function () { };\n\nif (\"serviceWorker\" in navigator) {\n  navigator.serviceWorker\n    .register(\"{{.WorkerJS}}\")\n    .then(reg => {\n      console.log(\"registering app service worker\");\n\n      reg.onupdatefound = function () {\n        const installingWorker = reg.installing;\n        installingWorker.onstatechange = function () {\n          if (installingWorker.state == \"installed\") {\n            if (navigator.serviceWorker.controller) {\n              goappOnUpdate();\n            }\n          }\n        };\n      }\n    })\n    .catch(err => {\n      console.error(\"offline service worker registration failed\", err);\n    });\n}\n\n// -----------------------------------------------------------------------------\n// Env\n// -----------------------------------------------------------------------------\nconst goappEnv = {{.Env }};\n\nfunction goappGetenv(k) {\n  return goappEnv[k];\n}\n\n// -----------------------------------------------------------------------------\n// App install\n// -----------------------------------------------------------------------------\nlet deferredPrompt = null;\nvar goappOnAppInstallChange = function () { };\n\nwindow.addEventListener(\"beforeinstallprompt\", e => {\n  e.preventDefault();\n  deferredPrompt = e;\n  goappOnAppInstallChange();\n});\n\nwindow.addEventListener('appinstalled', () => {\n  deferredPrompt = null;\n  goappOnAppInstallChange();\n});\n\nfunction goappIsAppInstallable() {\n  return !goappIsAppInstalled() && deferredPrompt != null;\n}\n\nfunction goappIsAppInstalled() {\n  const isStandalone = window.matchMedia('(display-mode: standalone)').matches;\n  return isStandalone || navigator.standalone;\n}\n\nasync function goappShowInstallPrompt() {\n  deferredPrompt.prompt();\n  await deferredPrompt.userChoice;\n  deferredPrompt = null;\n}\n\n// -----------------------------------------------------------------------------\n// Keep body clean\n// -----------------------------------------------------------------------------\nfunction goappKeepBodyClean() {\n  const body = document.body;\n  const bodyChildrenCount = body.children.length;\n\n  const mutationObserver = new MutationObserver(function (mutationList) {\n    mutationList.forEach((mutation) => {\n      switch (mutation.type) {\n        case 'childList':\n          while (body.children.length > bodyChildrenCount) {\n            body.removeChild(body.lastChild);\n          }\n          break;\n      }\n    });\n  });\n\n  mutationObserver.observe(document.body, {\n    childList: true,\n  });\n\n  return () => mutationObserver.disconnect();\n}\n\n// -----------------------------------------------------------------------------\n// Init Web Assembly\n// -----------------------------------------------------------------------------\nif (!/bot|googlebot|crawler|spider|robot|crawling/i.test(navigator.userAgent)) {\n  if (!WebAssembly.instantiateStreaming) {\n    WebAssembly.instantiateStreaming = async (resp, importObject) => {\n      const source = await (await resp).arrayBuffer();\n      return await WebAssembly.instantiate(source, importObject);\n    };\n  }\n\n  const go = new Go();\n\n  WebAssembly.instantiateStreaming(fetch(\"{{.Wasm}}\"), go.importObject)\n    .then(result => {\n      const loaderIcon = document.getElementById(\"app-wasm-loader-icon\");\n      loaderIcon.className = \"goapp-logo\";\n\n      go.run(result.instance);\n    })\n    .catch(err => {\n      const loaderIcon = document.getElementById(\"app-wasm-loader-icon\");\n      loaderIcon.className = \"goapp-logo\";\n\n      const loaderLabel = document.getElementById(\"app-wasm-loader-label\");\n      loaderLabel.innerText = err;\n\n      console.error(\"loading wasm failed: \" + err);\n    });\n} else {\n  document.getElementById('app-wasm-loader').style.display = \"none\";\n}\n"
+	appJS = "// -----------------------------------------------------------------------------\n// Init service worker\n// -----------------------------------------------------------------------------\nvar goappOnUpdate = function () { };\n\nif (\"serviceWorker\" in navigator) {\n  navigator.serviceWorker\n    .register(\"{{.WorkerJS}}\")\n    .then(reg => {\n      console.log(\"registering app service worker\");\n\n      reg.onupdatefound = function () {\n        const installingWorker = reg.installing;\n        installingWorker.onstatechange = function () {\n          if (installingWorker.state == \"installed\") {\n            if (navigator.serviceWorker.controller) {\n              goappOnUpdate();\n            }\n          }\n        };\n      }\n    })\n    .catch(err => {\n      console.error(\"offline service worker registration failed\", err);\n    });\n}\n\n// -----------------------------------------------------------------------------\n// Env\n// -----------------------------------------------------------------------------\nconst goappEnv = {{.Env }};\nlet goappPageEnv = null;\n\nfunction goappGetenv(k) {\n  if (goappPageEnv === null) {\n    const script = document.getElementById(\"app-env\");\n    try {\n      goappPageEnv = script ? JSON.parse(script.textContent) : {};\n    } catch (err) {\n      console.error(\"parsing page env failed:\", err);\n      goappPageEnv = {};\n    }\n  }\n\n  if (k in goappPageEnv) {\n    return goappPageEnv[k];\n  }\n  return goappEnv[k];\n}\n\n// -----------------------------------------------------------------------------\n// App install\n// -----------------------------------------------------------------------------\nlet deferredPrompt = null;\nvar goappOnAppInstallChange = function () { };\n\nwindow.addEventListener(\"beforeinstallprompt\", e => {\n  e.preventDefault();\n  deferredPrompt = e;\n  goappOnAppInstallChange();\n});\n\nwindow.addEventListener('appinstalled', () => {\n  deferredPrompt = null;\n  goappOnAppInstallChange();\n});\n\nfunction goappIsAppInstallable() {\n  return !goappIsAppInstalled() && deferredPrompt != null;\n}\n\nfunction goappIsAppInstalled() {\n  const isStandalone = window.matchMedia('(display-mode: standalone)').matches;\n  return isStandalone || navigator.standalone;\n}\n\nasync function goappShowInstallPrompt() {\n  deferredPrompt.prompt();\n  await deferredPrompt.userChoice;\n  deferredPrompt = null;\n}\n\n// -----------------------------------------------------------------------------\n// Client reports\n// -----------------------------------------------------------------------------\nvar goappReport = function (report) { };\n\nfunction goappInitReports() {\n  const url = goappGetenv(\"GOAPP_REPORT_URL\");\n  if (!url) {\n    return;\n  }\n\n  const sampled = (rate) => Math.random() < parseFloat(rate);\n  const reportsErrors = sampled(goappGetenv(\"GOAPP_REPORT_ERROR_SAMPLE_RATE\"));\n  const reportsVitals = sampled(goappGetenv(\"GOAPP_REPORT_SAMPLE_RATE\"));\n  const maxBatchSize = 20;\n  let queue = [];\n\n  const flush = () => {\n    if (!queue.length) {\n      return;\n    }\n\n    const body = JSON.stringify(queue);\n    queue = [];\n\n    if (navigator.sendBeacon && navigator.sendBeacon(url, new Blob([body], { type: \"application/json\" }))) {\n      return;\n    }\n    fetch(url, {\n      method: \"POST\",\n      headers: { \"Content-Type\": \"application/json\" },\n      body: body,\n      keepalive: true,\n    }).catch(() => { });\n  };\n\n  const push = (report) => {\n    report.url = location.pathname;\n    report.time = new Date().toISOString();\n    report.version = goappGetenv(\"GOAPP_VERSION\");\n    queue.push(report);\n\n    if (queue.length >= maxBatchSize) {\n      flush();\n    }\n  };\n\n  if (reportsErrors) {\n    goappReport = push;\n\n    window.addEventListener(\"error\", (e) => {\n      push({\n        type: \"error\",\n        message: e.message,\n        source: e.filename,\n        line: e.lineno,\n        column: e.colno,\n        stack: e.error && e.error.stack ? String(e.error.stack) : undefined,\n      });\n    });\n\n    window.addEventListener(\"unhandledrejection\", (e) => {\n      const reason = e.reason || {};\n      push({\n        type: \"unhandledrejection\",\n        message: String(reason.message || e.reason),\n        stack: reason.stack ? String(reason.stack) : undefined,\n      });\n    });\n  }\n\n  let lcp = 0;\n  let cls = 0;\n  let vitalsReported = false;\n\n  if (reportsVitals && \"PerformanceObserver\" in window) {\n    const observe = (type, fn) => {\n      try {\n        new PerformanceObserver((list) => list.getEntries().forEach(fn))\n          .observe({ type: type, buffered: true });\n      } catch (err) {\n        // Entry type not supported by the browser.\n      }\n    };\n\n    observe(\"longtask\", (e) => push({ type: \"longtask\", value: e.duration }));\n    observe(\"measure\", (e) => push({ type: \"measure\", message: e.name, value: e.duration }));\n    observe(\"largest-contentful-paint\", (e) => lcp = e.startTime);\n    observe(\"first-input\", (e) => push({ type: \"fid\", value: e.processingStart - e.startTime }));\n    observe(\"layout-shift\", (e) => {\n      if (!e.hadRecentInput) {\n        cls += e.value;\n      }\n    });\n  }\n\n  document.addEventListener(\"visibilitychange\", () => {\n    if (document.visibilityState != \"hidden\") {\n      return;\n    }\n\n    if (reportsVitals && !vitalsReported) {\n      vitalsReported = true;\n      if (lcp) {\n        push({ type: \"lcp\", value: lcp });\n      }\n      push({ type: \"cls\", value: cls });\n    }\n    flush();\n  });\n\n  setInterval(flush, 10000);\n}\n\ngoappInitReports();\n\n// -----------------------------------------------------------------------------\n// Keep body clean\n// -----------------------------------------------------------------------------\nfunction goappKeepBodyClean() {\n  const body = document.body;\n  const bodyChildrenCount = body.children.length;\n\n  const mutationObserver = new MutationObserver(function (mutationList) {\n    mutationList.forEach((mutation) => {\n      switch (mutation.type) {\n        case 'childList':\n          while (body.children.length > bodyChildrenCount) {\n            body.removeChild(body.lastChild);\n          }\n          break;\n      }\n    });\n  });\n\n  mutationObserver.observe(document.body, {\n    childList: true,\n  });\n\n  return () => mutationObserver.disconnect();\n}\n\n// -----------------------------------------------------------------------------\n// Crash detection\n// -----------------------------------------------------------------------------\nfunction goappMarkCrashed() {\n  try {\n    sessionStorage.setItem(\"/app/crash/detected\", \"true\");\n  } catch (err) {\n    // Session storage not available.\n  }\n}\n\nfunction goappWatchCrashes(go) {\n  const exit = go.exit;\n  go.exit = (code) => {\n    if (code !== 0) {\n      goappMarkCrashed();\n    }\n    exit(code);\n  };\n\n  window.addEventListener(\"error\", (e) => {\n    if (e.error instanceof WebAssembly.RuntimeError) {\n      goappMarkCrashed();\n    }\n  });\n}\n\n// -----------------------------------------------------------------------------\n// Loading failures\n// -----------------------------------------------------------------------------\nconst goappLoadAttemptsKey = \"goapp-load-attempts\";\n\nfunction goappPurgeCaches() {\n  const purge = new Promise((resolve) => {\n    const controller = navigator.serviceWorker && navigator.serviceWorker.controller;\n    if (!controller) {\n      resolve();\n      return;\n    }\n\n    const channel = new MessageChannel();\n    channel.port1.onmessage = () => resolve();\n    controller.postMessage({ type: \"goapp-purge-caches\" }, [channel.port2]);\n    setTimeout(resolve, 3000);\n  });\n\n  return purge\n    .then(() => {\n      if (\"caches\" in window) {\n        return caches.keys().then(keys => Promise.all(keys\n          .filter(key => key !== \"goapp-background-fetch\" && key !== \"goapp-content-index\")\n          .map(key => caches.delete(key))));\n      }\n    })\n    .then(() => {\n      if (\"serviceWorker\" in navigator) {\n        return navigator.serviceWorker.getRegistration().then(reg => reg && reg.update());\n      }\n    })\n    .catch(err => {\n      console.error(\"purging caches failed:\", err);\n    });\n}\n\nfunction goappOnLoadFailure(err) {\n  let attempts = 0;\n  try {\n    attempts = parseInt(sessionStorage.getItem(goappLoadAttemptsKey) || \"0\", 10);\n  } catch (e) {\n    // Session storage not available.\n  }\n\n  const maxRetries = parseInt(goappGetenv(\"GOAPP_LOADING_RETRIES\") || \"0\", 10);\n  if (attempts < maxRetries) {\n    // A failure is usually caused by a stale wasm file served from the cache\n    // that does not match the current app.js. Caches are purged before\n    // reloading the page with an exponential backoff.\n    console.warn(\"loading wasm failed, retrying:\", err);\n    try {\n      sessionStorage.setItem(goappLoadAttemptsKey, String(attempts + 1));\n    } catch (e) {\n      // Session storage not available.\n    }\n\n    goappPurgeCaches().then(() => {\n      setTimeout(() => location.reload(), 1000 * Math.pow(2, attempts));\n    });\n    return;\n  }\n\n  try {\n    sessionStorage.removeItem(goappLoadAttemptsKey);\n  } catch (e) {\n    // Session storage not available.\n  }\n\n  const loaderIcon = document.getElementById(\"app-wasm-loader-icon\");\n  loaderIcon.className = \"goapp-logo\";\n\n  const loaderLabel = document.getElementById(\"app-wasm-loader-label\");\n  loaderLabel.innerText = goappGetenv(\"GOAPP_LOADING_ERROR_LABEL\") || err;\n\n  console.error(\"loading wasm failed: \" + err);\n  goappReport({ type: \"error\", message: \"loading wasm failed: \" + err });\n}\n\n// -----------------------------------------------------------------------------\n// Init Web Assembly\n// -----------------------------------------------------------------------------\nvar goappWasmInstance = null;\n\nif (!/bot|googlebot|crawler|spider|robot|crawling/i.test(navigator.userAgent)) {\n  if (!WebAssembly.instantiateStreaming) {\n    WebAssembly.instantiateStreaming = async (resp, importObject) => {\n      const source = await (await resp).arrayBuffer();\n      return await WebAssembly.instantiate(source, importObject);\n    };\n  }\n\n  const go = new Go();\n  goappWatchCrashes(go);\n\n  WebAssembly.instantiateStreaming(fetch(\"{{.Wasm}}\"), go.importObject)\n    .then(result => {\n      const loaderIcon = document.getElementById(\"app-wasm-loader-icon\");\n      loaderIcon.className = \"goapp-logo\";\n\n      try {\n        sessionStorage.removeItem(goappLoadAttemptsKey);\n      } catch (e) {\n        // Session storage not available.\n      }\n\n      goappWasmInstance = result.instance;\n      go.run(result.instance);\n    })\n    .catch(goappOnLoadFailure);\n} else {\n  document.getElementById('app-wasm-loader').style.display = \"none\";\n}\n"
 
-	appWorkerJS = "const cacheName = \"app-\" + \"{{.Version}}\";\n\nself.addEventListener(\"install\", event => {\n  console.log(\"installing app worker {{.Version}}\");\n\n  event.waitUntil(\n    caches.open(cacheName).\n      then(cache => {\n        return cache.addAll([\n          {{range $path, $element := .ResourcesToCache}}\"{{$path}}\",\n          {{end}}\n        ]);\n      }).\n      then(() => {\n        self.skipWaiting();\n      })\n  );\n});\n\nself.addEventListener(\"activate\", event => {\n  event.waitUntil(\n    caches.keys().then(keyList => {\n      return Promise.all(\n        keyList.map(key => {\n          if (key !== cacheName) {\n            return caches.delete(key);\n          }\n        })\n      );\n    })\n  );\n  console.log(\"app worker {{.Version}} is activated\");\n});\n\nself.addEventListener(\"fetch\", event => {\n  event.respondWith(\n    caches.match(event.request).then(response => {\n      return response || fetch(event.request);\n    })\n  );\n});\n"
+	appWorkerJS = "const cacheName = \"app-\" + \"{{.Version}}\";\nconst backgroundFetchCacheName = \"goapp-background-fetch\";\nconst contentIndexCacheName = \"goapp-content-index\";\n\n// The caches that store the files downloaded by the app. They are kept when\n// the app is updated.\nconst persistentCacheNames = [backgroundFetchCacheName, contentIndexCacheName];\n\nself.addEventListener(\"install\", event => {\n  console.log(\"installing app worker {{.Version}}\");\n\n  event.waitUntil(\n    caches.open(cacheName).\n      then(cache => {\n        return cache.addAll([\n          {{range $path, $element := .ResourcesToCache}}\"{{$path}}\",\n          {{end}}\n        ]);\n      }).\n      then(() => {\n        self.skipWaiting();\n      })\n  );\n});\n\nself.addEventListener(\"activate\", event => {\n  event.waitUntil(\n    caches.keys().then(keyList => {\n      return Promise.all(\n        keyList.map(key => {\n          if (key !== cacheName && !persistentCacheNames.includes(key)) {\n            return caches.delete(key);\n          }\n        })\n      );\n    })\n  );\n  console.log(\"app worker {{.Version}} is activated\");\n});\n\nself.addEventListener(\"message\", event => {\n  if (!event.data || event.data.type !== \"goapp-purge-caches\") {\n    return;\n  }\n\n  event.waitUntil(\n    caches.keys()\n      .then(keyList => Promise.all(keyList\n        .filter(key => !persistentCacheNames.includes(key))\n        .map(key => caches.delete(key))))\n      .then(() => {\n        console.log(\"app worker {{.Version}} caches are purged\");\n        if (event.ports[0]) {\n          event.ports[0].postMessage(true);\n        }\n      })\n  );\n});\n\nself.addEventListener(\"backgroundfetchsuccess\", event => {\n  event.waitUntil(settleBackgroundFetch(event.registration, true));\n});\n\nself.addEventListener(\"backgroundfetchfail\", event => {\n  event.waitUntil(settleBackgroundFetch(event.registration, false));\n});\n\nself.addEventListener(\"backgroundfetchabort\", event => {\n  event.waitUntil(settleBackgroundFetch(event.registration, false));\n});\n\nself.addEventListener(\"backgroundfetchclick\", event => {\n  event.waitUntil(self.clients.openWindow(self.registration.scope));\n});\n\nfunction settleBackgroundFetch(reg, succeeded) {\n  // The result is recorded in the cache to be reported to the app when it is\n  // opened again.\n  const record = new URL(\n    \"app-background-fetch/\" + encodeURIComponent(reg.id),\n    self.registration.scope\n  ).href;\n\n  const progress = {\n    type: \"goapp-background-fetch\",\n    id: reg.id,\n    downloaded: reg.downloaded,\n    downloadTotal: reg.downloadTotal,\n    result: reg.result || (succeeded ? \"success\" : \"failure\"),\n    failureReason: reg.failureReason,\n    record: record,\n  };\n\n  return caches.open(backgroundFetchCacheName)\n    .then(cache => {\n      const stored = !succeeded ? Promise.resolve() : reg.matchAll()\n        .then(records => Promise.all(records.map(r => {\n          return r.responseReady.then(res => cache.put(r.request, res));\n        })));\n\n      return stored.then(() => cache.put(record, new Response(JSON.stringify(progress), {\n        headers: { \"Content-Type\": \"application/json\" },\n      })));\n    })\n    .then(() => self.clients.matchAll({ type: \"window\" }))\n    .then(clients => clients.forEach(client => client.postMessage(progress)))\n    .catch(err => {\n      console.error(\"settling background fetch \" + reg.id + \" failed:\", err);\n    });\n}\n\nself.addEventListener(\"contentdelete\", event => {\n  // The page of an item removed from the browser offline content UI is\n  // deleted from the cache.\n  const record = new URL(\n    \"app-content-index/\" + encodeURIComponent(event.id),\n    self.registration.scope\n  ).href;\n\n  event.waitUntil(\n    caches.open(contentIndexCacheName)\n      .then(cache => cache.match(record)\n        .then(res => res ? res.json() : null)\n        .then(data => Promise.all([\n          data ? cache.delete(data.url) : false,\n          cache.delete(record),\n        ])))\n      .catch(err => {\n        console.error(\"deleting indexed content \" + event.id + \" failed:\", err);\n      })\n  );\n});\n\nself.addEventListener(\"fetch\", event => {\n  event.respondWith(\n    caches.match(event.request).then(response => {\n      return response || fetch(event.request);\n    })\n  );\n});\n"
 
-	manifestJSON = "{\n  \"short_name\": \"{{.ShortName}}\",\n  \"name\": \"{{.Name}}\",\n  \"description\": \"{{.Description}}\",\n  \"icons\": [\n    {\n      \"src\": \"{{.DefaultIcon}}\",\n      \"type\": \"image/png\",\n      \"sizes\": \"192x192\"\n    },\n    {\n      \"src\": \"{{.LargeIcon}}\",\n      \"type\": \"image/png\",\n      \"sizes\": \"512x512\"\n    }\n  ],\n  \"scope\": \"{{.Scope}}\",\n  \"start_url\": \"{{.StartURL}}\",\n  \"background_color\": \"{{.BackgroundColor}}\",\n  \"theme_color\": \"{{.ThemeColor}}\",\n  \"display\": \"standalone\"\n}\n"
+	manifestJSON = "{\n  \"short_name\": \"{{.ShortName}}\",\n  \"name\": \"{{.Name}}\",\n  \"description\": \"{{.Description}}\",{{if .Lang}}\n  \"lang\": \"{{.Lang}}\",{{end}}\n  \"icons\": [\n    {\n      \"src\": \"{{.DefaultIcon}}\",\n      \"type\": \"image/png\",\n      \"sizes\": \"192x192\"\n    },\n    {\n      \"src\": \"{{.LargeIcon}}\",\n      \"type\": \"image/png\",\n      \"sizes\": \"512x512\"\n    }\n  ],\n  \"scope\": \"{{.Scope}}\",\n  \"start_url\": \"{{.StartURL}}\",\n  \"background_color\": \"{{.BackgroundColor}}\",\n  \"theme_color\": \"{{.ThemeColor}}\",\n  \"display\": \"standalone\"{{if .ProtocolHandlers}},\n  \"protocol_handlers\": {{.ProtocolHandlers}}{{end}}{{if .FileHandlers}},\n  \"file_handlers\": {{.FileHandlers}}{{end}}{{if .Shortcuts}},\n  \"shortcuts\": {{.Shortcuts}}{{end}}{{if .LaunchMode}},\n  \"launch_handler\": {\n    \"client_mode\": \"{{.LaunchMode}}\"\n  }{{end}}\n}\n"
 
-	appCSS = "/*------------------------------------------------------------------------------\n  Loader\n------------------------------------------------------------------------------*/\n.goapp-app-info {\n  position: fixed;\n  top: 0;\n  left: 0;\n  z-index: 1000;\n  width: 100%;\n  height: 100%;\n  overflow: hidden;\n\n  display: flex;\n  flex-direction: column;\n  justify-content: center;\n  align-items: center;\n\n  font-family: -apple-system, BlinkMacSystemFont, \"Segoe UI\", Roboto, Oxygen,\n    Ubuntu, Cantarell, \"Open Sans\", \"Helvetica Neue\", sans-serif;\n  font-size: 13px;\n  font-weight: 400;\n  color: white;\n  background-color: #2d2c2c;\n}\n\n@media (prefers-color-scheme: light) {\n  .goapp-app-info {\n    color: black;\n    background-color: #f6f6f6;\n  }\n}\n\n.goapp-logo {\n  max-width: 100px;\n  max-height: 100px;\n  user-select: none;\n  -moz-user-select: none;\n  -webkit-user-drag: none;\n  -webkit-user-select: none;\n  -ms-user-select: none;\n}\n\n.goapp-label {\n  margin-top: 12px;\n  font-size: 21px;\n  font-weight: 100;\n  letter-spacing: 1px;\n  max-width: 480px;\n  text-align: center;\n  text-transform: lowercase;\n}\n\n.goapp-spin {\n  animation: goapp-spin-frames 1.21s infinite linear;\n}\n\n@keyframes goapp-spin-frames {\n  from {\n    transform: rotate(0deg);\n  }\n\n  to {\n    transform: rotate(360deg);\n  }\n}\n\n/*------------------------------------------------------------------------------\n  Not found\n------------------------------------------------------------------------------*/\n.goapp-notfound-title {\n  display: flex;\n  justify-content: center;\n  align-items: center;\n  font-size: 65pt;\n  font-weight: 100;\n}\n\n/*------------------------------------------------------------------------------\n  Widget Layout\n------------------------------------------------------------------------------*/\n.goapp-shell-hamburger-button-default {\n  font-size: 24px;\n  padding: 12px 18px;\n  color: currentColor;\n}\n\n.goapp-shell-hamburger-button-default:hover {\n  color: dodgerblue;\n  cursor: pointer;\n}\n"
+	appCSS = "/*------------------------------------------------------------------------------\n  Loader\n------------------------------------------------------------------------------*/\n.goapp-app-info {\n  position: fixed;\n  top: 0;\n  left: 0;\n  z-index: 1000;\n  width: 100%;\n  height: 100%;\n  overflow: hidden;\n\n  display: flex;\n  flex-direction: column;\n  justify-content: center;\n  align-items: center;\n\n  font-family: -apple-system, BlinkMacSystemFont, \"Segoe UI\", Roboto, Oxygen,\n    Ubuntu, Cantarell, \"Open Sans\", \"Helvetica Neue\", sans-serif;\n  font-size: 13px;\n  font-weight: 400;\n  color: white;\n  background-color: #2d2c2c;\n}\n\n@media (prefers-color-scheme: light) {\n  .goapp-app-info {\n    color: black;\n    background-color: #f6f6f6;\n  }\n}\n\n@media (prefers-contrast: more) {\n  .goapp-app-info {\n    color: white;\n    background-color: black;\n  }\n}\n\n@media (prefers-contrast: more) and (prefers-color-scheme: light) {\n  .goapp-app-info {\n    color: black;\n    background-color: white;\n  }\n}\n\n.goapp-logo {\n  max-width: 100px;\n  max-height: 100px;\n  user-select: none;\n  -moz-user-select: none;\n  -webkit-user-drag: none;\n  -webkit-user-select: none;\n  -ms-user-select: none;\n}\n\n.goapp-label {\n  margin-top: 12px;\n  font-size: 21px;\n  font-weight: 100;\n  letter-spacing: 1px;\n  max-width: 480px;\n  text-align: center;\n  text-transform: lowercase;\n}\n\n.goapp-spin {\n  animation: goapp-spin-frames 1.21s infinite linear;\n}\n\n@keyframes goapp-spin-frames {\n  from {\n    transform: rotate(0deg);\n  }\n\n  to {\n    transform: rotate(360deg);\n  }\n}\n\n@media (prefers-reduced-motion: reduce) {\n  .goapp-spin {\n    animation-duration: 3.63s;\n  }\n}\n\n/*------------------------------------------------------------------------------\n  Not found\n------------------------------------------------------------------------------*/\n.goapp-notfound-title {\n  display: flex;\n  justify-content: center;\n  align-items: center;\n  font-size: 65pt;\n  font-weight: 100;\n}\n\n/*------------------------------------------------------------------------------\n  Maintenance\n------------------------------------------------------------------------------*/\n.goapp-maintenance-banner {\n  position: fixed;\n  top: 0;\n  left: 0;\n  right: 0;\n  z-index: 1001;\n  padding: 12px;\n\n  font-family: -apple-system, BlinkMacSystemFont, \"Segoe UI\", Roboto, Oxygen,\n    Ubuntu, Cantarell, \"Open Sans\", \"Helvetica Neue\", sans-serif;\n  font-size: 15px;\n  text-align: center;\n  color: black;\n  background-color: #ffcc00;\n}\n\n.goapp-maintenance-banner[hidden] {\n  display: none;\n}\n\n/*------------------------------------------------------------------------------\n  Skip link\n------------------------------------------------------------------------------*/\n.goapp-skip-link {\n  position: fixed;\n  top: 8px;\n  left: 8px;\n  z-index: 1002;\n  padding: 8px 12px;\n  transform: translateY(calc(-100% - 16px));\n\n  font-family: -apple-system, BlinkMacSystemFont, \"Segoe UI\", Roboto, Oxygen,\n    Ubuntu, Cantarell, \"Open Sans\", \"Helvetica Neue\", sans-serif;\n  font-size: 15px;\n  color: white;\n  background-color: black;\n}\n\n.goapp-skip-link:focus {\n  transform: none;\n}\n\n#goapp-main:focus {\n  outline: none;\n}\n\n/*------------------------------------------------------------------------------\n  Widget Layout\n------------------------------------------------------------------------------*/\n.goapp-shell-hamburger-button-default {\n  font-size: 24px;\n  padding: 12px 18px;\n  color: currentColor;\n}\n\n.goapp-shell-hamburger-button-default:hover {\n  color: dodgerblue;\n  cursor: pointer;\n}\n"
+
+	mediaWorkerJS = "// -----------------------------------------------------------------------------\n// Media worker\n// -----------------------------------------------------------------------------\n// Processes images and PDF pages off the UI thread. Messages have the following\n// shape:\n//   { id, op: \"image\" | \"pdf-page\", file, options }\n// Results are posted back as:\n//   { id, file, width, height } or { id, error }\n\nlet pdfjsLoaded = \"\";\n\nself.onmessage = async (event) => {\n  const { id, op, file, options } = event.data;\n\n  try {\n    let result;\n    switch (op) {\n      case \"image\":\n        result = await processImage(file, options);\n        break;\n\n      case \"pdf-page\":\n        result = await renderPDFPage(file, options);\n        break;\n\n      default:\n        throw new Error(\"unknown operation: \" + op);\n    }\n    self.postMessage({ id, ...result });\n  } catch (err) {\n    self.postMessage({ id, error: String((err && err.message) || err) });\n  }\n};\n\nasync function processImage(file, options) {\n  const bitmap = await createImageBitmap(file, {\n    imageOrientation: \"from-image\",\n  });\n\n  const rotate = (((options.rotate || 0) % 360) + 360) % 360;\n  const swap = rotate === 90 || rotate === 270;\n\n  let width = bitmap.width;\n  let height = bitmap.height;\n  const scale = Math.min(\n    1,\n    options.maxWidth > 0 ? options.maxWidth / (swap ? height : width) : 1,\n    options.maxHeight > 0 ? options.maxHeight / (swap ? width : height) : 1\n  );\n  width = Math.max(1, Math.round(width * scale));\n  height = Math.max(1, Math.round(height * scale));\n\n  const canvas = new OffscreenCanvas(swap ? height : width, swap ? width : height);\n  const ctx = canvas.getContext(\"2d\");\n  ctx.translate(canvas.width / 2, canvas.height / 2);\n  ctx.rotate((rotate * Math.PI) / 180);\n  ctx.drawImage(bitmap, -width / 2, -height / 2, width, height);\n  bitmap.close();\n\n  return encode(canvas, file.name, options);\n}\n\nasync function renderPDFPage(file, options) {\n  if (pdfjsLoaded !== options.pdfjs) {\n    importScripts(options.pdfjs);\n    pdfjsLoaded = options.pdfjs;\n  }\n\n  const data = new Uint8Array(await file.arrayBuffer());\n  const doc = await pdfjsLib.getDocument({\n    data: data,\n    isOffscreenCanvasSupported: true,\n  }).promise;\n\n  try {\n    const page = await doc.getPage(options.page);\n    const viewport = page.getViewport({ scale: options.scale });\n    const canvas = new OffscreenCanvas(\n      Math.ceil(viewport.width),\n      Math.ceil(viewport.height)\n    );\n\n    await page.render({\n      canvasContext: canvas.getContext(\"2d\"),\n      viewport: viewport,\n    }).promise;\n\n    const name = file.name.replace(/\\.pdf$/i, \"\") + \"-\" + options.page;\n    return encode(canvas, name, options);\n  } finally {\n    doc.destroy();\n  }\n}\n\nasync function encode(canvas, name, options) {\n  const blob = await canvas.convertToBlob({\n    type: options.type,\n    quality: options.quality,\n  });\n\n  const ext = blob.type.split(\"/\")[1] || \"img\";\n  const filename = (name || \"image\").replace(/\\.[^./]+$/, \"\") + \".\" + ext;\n\n  return {\n    file: new File([blob], filename, { type: blob.type }),\n    width: canvas.width,\n    height: canvas.height,\n  };\n}\n"
 )
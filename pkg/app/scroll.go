@@ -0,0 +1,104 @@
+package app
+
+const (
+	// ScrollState is the state where the window scroll position is stored.
+	// It is updated once per animation frame when scrolling, whatever the
+	// number of observers. See Context.ObserveScroll.
+	ScrollState = "/app/scroll"
+)
+
+// ScrollPosition describes the window scroll position.
+type ScrollPosition struct {
+	// The number of px the page is scrolled horizontally.
+	X float64
+
+	// The number of px the page is scrolled vertically.
+	Y float64
+
+	// The maximum number of px the page can be scrolled vertically.
+	MaxY float64
+}
+
+// Progress returns how far the page is scrolled vertically, ranging from 0 to
+// 1.
+func (p ScrollPosition) Progress() float64 {
+	if p.MaxY <= 0 {
+		return 0
+	}
+	if p.Y >= p.MaxY {
+		return 1
+	}
+	return p.Y / p.MaxY
+}
+
+var (
+	scrolls scrollTracker
+)
+
+// scrollTracker reads the window scroll position when the page is scrolled or
+// resized, at most once per animation frame, and stores it in ScrollState.
+type scrollTracker struct {
+	started  bool
+	pending  bool
+	position ScrollPosition
+}
+
+func (t *scrollTracker) start(d Dispatcher) {
+	if t.started || IsServer {
+		return
+	}
+	t.started = true
+	t.update(d)
+
+	if !Window().Get("requestAnimationFrame").Truthy() {
+		return
+	}
+
+	var onFrame Func
+	onFrame = FuncOf(func(this Value, args []Value) interface{} {
+		t.pending = false
+		t.update(d)
+		return nil
+	})
+
+	onScroll := FuncOf(func(this Value, args []Value) interface{} {
+		if !t.pending {
+			t.pending = true
+			Window().Call("requestAnimationFrame", onFrame)
+		}
+		return nil
+	})
+
+	opts := map[string]interface{}{"passive": true}
+	Window().Call("addEventListener", "scroll", onScroll, opts)
+	Window().Call("addEventListener", "resize", onScroll, opts)
+}
+
+func (t *scrollTracker) update(d Dispatcher) {
+	position := readScrollPosition()
+	if position == t.position {
+		return
+	}
+	t.position = position
+
+	d.Dispatch(Dispatch{
+		Mode: Update,
+		Function: func(ctx Context) {
+			ctx.SetState(ScrollState, position)
+		},
+	})
+}
+
+func readScrollPosition() ScrollPosition {
+	win := Window()
+	doc := win.Get("document").Get("documentElement")
+	if !doc.Truthy() {
+		return ScrollPosition{}
+	}
+
+	return ScrollPosition{
+		X:    win.Get("scrollX").Float(),
+		Y:    win.Get("scrollY").Float(),
+		MaxY: doc.Get("scrollHeight").Float() - win.Get("innerHeight").Float(),
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrollPositionProgress(t *testing.T) {
+	require.Zero(t, ScrollPosition{}.Progress())
+	require.Zero(t, ScrollPosition{Y: 42}.Progress())
+	require.Equal(t, 0.25, ScrollPosition{Y: 25, MaxY: 100}.Progress())
+	require.Equal(t, float64(1), ScrollPosition{Y: 120, MaxY: 100}.Progress())
+}
+
+func TestObserveScroll(t *testing.T) {
+	h := &hello{}
+	d := NewClientTester(h)
+	defer d.Close()
+
+	var position ScrollPosition
+	makeContext(h).ObserveScroll().Value(&position)
+	d.Consume()
+	require.Zero(t, position)
+
+	d.SetState(ScrollState, ScrollPosition{Y: 42, MaxY: 100})
+	d.Consume()
+	require.Equal(t, float64(42), position.Y)
+}
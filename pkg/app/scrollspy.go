@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strconv"
+)
+
+// ScrollSpy describes the in-page sections tracked by Context.SpyScroll.
+type ScrollSpy struct {
+	// The state where the ID of the active section is stored.
+	State string
+
+	// The IDs of the HTML elements that represent the sections, in document
+	// order.
+	Sections []string
+
+	// The distance in px from the top of the viewport from where sections
+	// are considered visible. It is typically the height of a fixed header.
+	Offset int
+
+	// Reports whether the URL fragment is replaced by the ID of the active
+	// section. The browser history entry is replaced rather than added so
+	// that going back does not iterate over the sections.
+	UpdateURL bool
+}
+
+// SetSmoothScrolling enables or disables smooth scrolling when navigating to
+// an in-page anchor or calling Context.ScrollTo. Scrolling remains instant
+// when the user prefers reduced motion.
+//
+// Default: false.
+func SetSmoothScrolling(enabled bool) {
+	smoothScrollingEnabled = enabled
+}
+
+var (
+	smoothScrollingEnabled bool
+)
+
+func scrollIntoViewOptions() map[string]interface{} {
+	if !smoothScrollingEnabled || prefersReducedMotion() {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"behavior": "smooth",
+	}
+}
+
+func spyScroll(ctx Context, s ScrollSpy) {
+	if IsServer || len(s.Sections) == 0 ||
+		!Window().Get("IntersectionObserver").Truthy() {
+		return
+	}
+
+	visible := make(map[string]bool, len(s.Sections))
+	active := ""
+
+	callback := FuncOf(func(this Value, args []Value) interface{} {
+		entries := args[0]
+		for i := 0; i < entries.Length(); i++ {
+			entry := entries.Index(i)
+			id := entry.Get("target").Get("id").String()
+			visible[id] = entry.Get("isIntersecting").Bool()
+		}
+
+		current := activeSection(s.Sections, visible)
+		if current == "" || current == active {
+			return nil
+		}
+		active = current
+
+		ctx.Dispatch(func(ctx Context) {
+			ctx.SetState(s.State, current)
+			if s.UpdateURL {
+				updateURLFragment(current)
+			}
+		})
+		return nil
+	})
+
+	observer := Window().Get("IntersectionObserver").New(callback, map[string]interface{}{
+		"rootMargin": "-" + strconv.Itoa(s.Offset) + "px 0px -50% 0px",
+	})
+
+	ctx.Defer(func(Context) {
+		for _, id := range s.Sections {
+			if elem := Window().GetElementByID(id); elem.Truthy() {
+				observer.Call("observe", elem)
+			}
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+		ctx.Dispatch(func(Context) {
+			observer.Call("disconnect")
+			callback.Release()
+		})
+	}()
+}
+
+// activeSection returns the first visible section.
+func activeSection(sections []string, visible map[string]bool) string {
+	for _, id := range sections {
+		if visible[id] {
+			return id
+		}
+	}
+	return ""
+}
+
+func updateURLFragment(fragment string) {
+	u := Window().URL()
+	if u.Fragment == fragment {
+		return
+	}
+	u.Fragment = fragment
+	Window().replaceHistory(u)
+}
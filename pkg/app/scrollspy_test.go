@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveSection(t *testing.T) {
+	sections := []string{"intro", "install", "usage"}
+
+	require.Empty(t, activeSection(sections, nil))
+	require.Equal(t, "install", activeSection(sections, map[string]bool{
+		"install": true,
+		"usage":   true,
+	}))
+	require.Equal(t, "intro", activeSection(sections, map[string]bool{
+		"intro": true,
+		"usage": true,
+	}))
+	require.Empty(t, activeSection(sections, map[string]bool{
+		"intro": false,
+		"api":   true,
+	}))
+}
+
+func TestScrollIntoViewOptions(t *testing.T) {
+	require.Empty(t, scrollIntoViewOptions())
+
+	SetSmoothScrolling(true)
+	defer SetSmoothScrolling(false)
+	require.Equal(t, map[string]interface{}{"behavior": "smooth"}, scrollIntoViewOptions())
+}
+
+func TestSpyScrollOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	makeContext(h).SpyScroll(ScrollSpy{
+		State:    "/section",
+		Sections: []string{"intro"},
+	})
+	d.Consume()
+
+	var section string
+	d.GetState("/section", &section)
+	require.Empty(t, section)
+}
@@ -0,0 +1,439 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	defaultSessionCookieName = "goapp_session"
+	defaultSessionTTL        = time.Hour * 24
+
+	// The HTTP header that contains the CSRF token of the session.
+	CSRFHeader = "X-CSRF-Token"
+
+	// The form field that contains the CSRF token of the session.
+	CSRFFormField = "csrf_token"
+)
+
+// SessionStore is the interface that describes a store where server sessions
+// are persisted.
+type SessionStore interface {
+	// Loads the data of the session with the given ID. It returns false when
+	// the session does not exist or is expired.
+	Load(ctx context.Context, id string) ([]byte, bool, error)
+
+	// Saves the data of the session with the given ID, for the given
+	// duration.
+	Save(ctx context.Context, id string, data []byte, ttl time.Duration) error
+
+	// Deletes the session with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// Sessions describes how server sessions are issued by the Handler.
+//
+// A session is created when a value is set, or when its CSRF token is
+// requested. It is then identified by an HTTP only cookie. Pages requested
+// with a session cookie are prerendered for that session: they are neither
+// served from nor stored in the prerender cache.
+//
+// eg:
+//  app.Handler{
+//      Sessions: app.Sessions{
+//          Store: app.NewMemorySessionStore(),
+//          CSRF:  true,
+//      },
+//  },
+type Sessions struct {
+	// The store where sessions are persisted. Sessions are disabled when nil.
+	Store SessionStore
+
+	// The name of the session cookie.
+	//
+	// Default: "goapp_session".
+	CookieName string
+
+	// The duration a session is kept since its last modification.
+	//
+	// Default: 24h.
+	TTL time.Duration
+
+	// The SameSite attribute of the session cookie.
+	//
+	// Default: http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// Reports whether the requests made with a session cookie and an unsafe
+	// method to the endpoints served by the Handler, such as uploads, must
+	// carry the session CSRF token in the X-CSRF-Token header or in the
+	// csrf_token form field.
+	//
+	// The token is available with Session.CSRFToken and is emitted in
+	// prerendered pages as a meta tag named "csrf-token", which is used by
+	// Context.Upload.
+	CSRF bool
+}
+
+// Session is a server session. Values are encoded in JSON.
+//
+// The methods of a nil session are no-ops, which lets components use the
+// value returned by Context.Session without checking whether they are
+// prerendered.
+type Session struct {
+	mutex     sync.Mutex
+	manager   *sessionManager
+	w         http.ResponseWriter
+	r         *http.Request
+	id        string
+	data      sessionData
+	isNew     bool
+	modified  bool
+	destroyed bool
+	staleID   string
+}
+
+type sessionData struct {
+	Values map[string]json.RawMessage `json:"values,omitempty"`
+	CSRF   string                     `json:"csrf,omitempty"`
+}
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.id
+}
+
+// IsNew reports whether the session was created by the current request.
+func (s *Session) IsNew() bool {
+	if s == nil {
+		return true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.isNew
+}
+
+// Get stores the value of the given key into the given receiver. The receiver
+// is left untouched when there is no value for the key.
+func (s *Session) Get(key string, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, ok := s.data.Values[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return errors.New("getting session value failed").
+			Tag("key", key).
+			Wrap(err)
+	}
+	return nil
+}
+
+// Set sets the value of the given key.
+func (s *Session) Set(key string, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return errors.New("setting session value failed").
+			Tag("key", key).
+			Wrap(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.data.Values == nil {
+		s.data.Values = make(map[string]json.RawMessage)
+	}
+	s.data.Values[key] = value
+	s.modified = true
+	return nil
+}
+
+// Del deletes the value of the given key.
+func (s *Session) Del(key string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.modified = true
+	}
+}
+
+// CSRFToken returns the CSRF token of the session.
+func (s *Session) CSRFToken() string {
+	if s == nil {
+		return ""
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.data.CSRF == "" {
+		s.data.CSRF = newSessionToken()
+		s.modified = true
+	}
+	return s.data.CSRF
+}
+
+// VerifyCSRF reports whether the given request carries the session CSRF
+// token in the X-CSRF-Token header or in the csrf_token form field.
+func (s *Session) VerifyCSRF(r *http.Request) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mutex.Lock()
+	token := s.data.CSRF
+	s.mutex.Unlock()
+
+	received := r.Header.Get(CSRFHeader)
+	if received == "" {
+		received = r.PostFormValue(CSRFFormField)
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(received)) == 1
+}
+
+// Renew gives the session a new ID while keeping its values. It must be
+// called when the user logs in in order to prevent session fixation.
+func (s *Session) Renew() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.isNew && s.staleID == "" {
+		s.staleID = s.id
+	}
+	s.id = newSessionToken()
+	s.data.CSRF = newSessionToken()
+	s.modified = true
+}
+
+// Destroy deletes the session and its values. It is typically called when
+// the user logs out.
+func (s *Session) Destroy() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.data = sessionData{}
+	s.destroyed = true
+}
+
+// Save persists the session and sets the session cookie when the session has
+// been modified. It must be called before the response body is written.
+//
+// Sessions used by prerendered components are saved by the Handler.
+func (s *Session) Save() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.manager.save(s)
+}
+
+// Session returns the server session of the given request. A new session is
+// returned when the request has no valid session cookie. The session must be
+// saved with Session.Save when modified.
+//
+// It is meant to be used by HTTP handlers that share the Handler sessions,
+// such as API endpoints. It returns nil when sessions are disabled.
+func (h *Handler) Session(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	h.once.Do(h.init)
+	if h.sessions == nil {
+		return nil, nil
+	}
+	return h.sessions.load(w, r)
+}
+
+func (h *Handler) initSessions() {
+	if h.Sessions.Store == nil {
+		return
+	}
+
+	if h.Sessions.CookieName == "" {
+		h.Sessions.CookieName = defaultSessionCookieName
+	}
+	if h.Sessions.TTL <= 0 {
+		h.Sessions.TTL = defaultSessionTTL
+	}
+	if h.Sessions.SameSite == 0 {
+		h.Sessions.SameSite = http.SameSiteLaxMode
+	}
+	h.sessions = &sessionManager{Sessions: h.Sessions}
+}
+
+// isPersonalized reports whether the given request has a session cookie or
+// claims, in which case its response must not be shared with other users.
+func (h *Handler) isPersonalized(r *http.Request) bool {
+	return h.sessions != nil && h.sessions.hasCookie(r) ||
+		!h.requestClaims(r).isZero() ||
+		h.hasTimeZone(r)
+}
+
+// verifyCSRF reports whether the given request can be served. It writes a 403
+// status code when it can't.
+func (h *Handler) verifyCSRF(w http.ResponseWriter, r *http.Request) bool {
+	if h.sessions == nil || !h.Sessions.CSRF || !h.sessions.hasCookie(r) {
+		return true
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	s, err := h.sessions.load(w, r)
+	if err != nil {
+		Log(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return false
+	}
+	if !s.VerifyCSRF(r) {
+		http.Error(w, "invalid csrf token", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+type sessionManager struct {
+	Sessions
+}
+
+func (m *sessionManager) hasCookie(r *http.Request) bool {
+	c, err := r.Cookie(m.CookieName)
+	return err == nil && c.Value != ""
+}
+
+func (m *sessionManager) load(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	s := &Session{
+		manager: m,
+		w:       w,
+		r:       r,
+	}
+
+	if c, err := r.Cookie(m.CookieName); err == nil && c.Value != "" {
+		b, ok, err := m.Store.Load(r.Context(), c.Value)
+		if err != nil {
+			return nil, errors.New("loading session failed").Wrap(err)
+		}
+		if ok {
+			if err := json.Unmarshal(b, &s.data); err != nil {
+				return nil, errors.New("decoding session failed").Wrap(err)
+			}
+			s.id = c.Value
+			return s, nil
+		}
+	}
+
+	s.id = newSessionToken()
+	s.isNew = true
+	return s, nil
+}
+
+func (m *sessionManager) save(s *Session) error {
+	ctx := s.r.Context()
+
+	if s.staleID != "" {
+		if err := m.Store.Delete(ctx, s.staleID); err != nil {
+			return errors.New("deleting renewed session failed").Wrap(err)
+		}
+		s.staleID = ""
+	}
+
+	if s.destroyed {
+		if !s.isNew {
+			if err := m.Store.Delete(ctx, s.id); err != nil {
+				return errors.New("deleting session failed").Wrap(err)
+			}
+		}
+		http.SetCookie(s.w, m.cookie(s.r, "", -1))
+		s.destroyed = false
+		s.modified = false
+		s.isNew = true
+		s.id = newSessionToken()
+		return nil
+	}
+
+	if !s.modified {
+		return nil
+	}
+
+	b, err := json.Marshal(s.data)
+	if err != nil {
+		return errors.New("encoding session failed").Wrap(err)
+	}
+	if err := m.Store.Save(ctx, s.id, b, m.TTL); err != nil {
+		return errors.New("saving session failed").Wrap(err)
+	}
+
+	http.SetCookie(s.w, m.cookie(s.r, s.id, int(m.TTL/time.Second)))
+	s.modified = false
+	return nil
+}
+
+func (m *sessionManager) cookie(r *http.Request, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.CookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: m.SameSite,
+	}
+}
+
+// csrfToken returns the CSRF token emitted in the prerendered page.
+func csrfToken() string {
+	meta := Window().Get("document").Call("querySelector", `meta[name="csrf-token"]`)
+	if !meta.Truthy() {
+		return ""
+	}
+	return meta.Call("getAttribute", "content").String()
+}
+
+func newSessionToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New("generating session token failed").Wrap(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,208 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/session-test", &sessionTestCompo{})
+}
+
+type sessionTestCompo struct {
+	Compo
+
+	user string
+}
+
+func (c *sessionTestCompo) OnPreRender(ctx Context) {
+	s := ctx.Session()
+	if err := s.Get("user", &c.user); err != nil {
+		panic(err)
+	}
+	if c.user == "" {
+		s.Set("user", "max")
+		s.CSRFToken()
+	}
+}
+
+func (c *sessionTestCompo) Render() UI {
+	return Div().ID("session-user").Text(c.user)
+}
+
+func TestHandlerSessions(t *testing.T) {
+	h := Handler{
+		Sessions: Sessions{
+			Store: NewMemorySessionStore(),
+			CSRF:  true,
+		},
+		Uploads: Uploads{Dir: t.TempDir()},
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/session-test", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "\nmax\n")
+
+	cookies := responseCookies(w)
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	require.Equal(t, defaultSessionCookieName, cookie.Name)
+	require.True(t, cookie.HttpOnly)
+	require.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+
+	_, cached := h.PreRenderCache.Get(context.Background(), "/session-test")
+	require.False(t, cached)
+
+	t.Run("prerendering reads the session", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/session-test", nil)
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "<div id=\"session-user\">\nmax\n</div>")
+		require.Empty(t, responseCookies(w))
+
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookie)
+		s, err := h.Session(httptest.NewRecorder(), r)
+		require.NoError(t, err)
+		require.NotEmpty(t, s.CSRFToken())
+		require.Equal(t, s.CSRFToken(), testMetaContent(w.Body.String(), "csrf-token"))
+	})
+
+	t.Run("request without csrf token is rejected", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, uploadEndpoint, strings.NewReader(`{"name":"a.txt","size":1}`))
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("request with csrf token is served", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookie)
+		s, err := h.Session(httptest.NewRecorder(), r)
+		require.NoError(t, err)
+		require.False(t, s.IsNew())
+
+		r = httptest.NewRequest(http.MethodPost, uploadEndpoint, strings.NewReader(`{"name":"a.txt","size":1}`))
+		r.AddCookie(cookie)
+		r.Header.Set(CSRFHeader, s.CSRFToken())
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("request without session cookie is not checked", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, uploadEndpoint, strings.NewReader(`{"name":"a.txt","size":1}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestSession(t *testing.T) {
+	h := Handler{
+		Sessions: Sessions{Store: NewMemorySessionStore()},
+	}
+
+	newRequest := func(c *http.Cookie) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/api", nil)
+		if c != nil {
+			r.AddCookie(c)
+		}
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	s, err := h.Session(w, newRequest(nil))
+	require.NoError(t, err)
+	require.True(t, s.IsNew())
+
+	require.NoError(t, s.Save())
+	require.Empty(t, responseCookies(w))
+
+	require.NoError(t, s.Set("count", 42))
+	require.NoError(t, s.Save())
+	cookie := responseCookies(w)[0]
+	require.Equal(t, s.ID(), cookie.Value)
+
+	t.Run("get", func(t *testing.T) {
+		s, err := h.Session(httptest.NewRecorder(), newRequest(cookie))
+		require.NoError(t, err)
+
+		var count int
+		require.NoError(t, s.Get("count", &count))
+		require.Equal(t, 42, count)
+
+		var missing string
+		require.NoError(t, s.Get("missing", &missing))
+		require.Empty(t, missing)
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		s, err := h.Session(w, newRequest(cookie))
+		require.NoError(t, err)
+
+		s.Renew()
+		require.NotEqual(t, cookie.Value, s.ID())
+		require.NoError(t, s.Save())
+
+		old, err := h.Session(httptest.NewRecorder(), newRequest(cookie))
+		require.NoError(t, err)
+		require.True(t, old.IsNew())
+
+		cookie = responseCookies(w)[0]
+		renewed, err := h.Session(httptest.NewRecorder(), newRequest(cookie))
+		require.NoError(t, err)
+
+		var count int
+		renewed.Get("count", &count)
+		require.Equal(t, 42, count)
+	})
+
+	t.Run("destroy", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		s, err := h.Session(w, newRequest(cookie))
+		require.NoError(t, err)
+
+		s.Destroy()
+		require.NoError(t, s.Save())
+		require.Equal(t, -1, responseCookies(w)[0].MaxAge)
+
+		destroyed, err := h.Session(httptest.NewRecorder(), newRequest(cookie))
+		require.NoError(t, err)
+		require.True(t, destroyed.IsNew())
+	})
+
+	t.Run("nil session", func(t *testing.T) {
+		var s *Session
+		require.NoError(t, s.Set("foo", "bar"))
+		require.NoError(t, s.Get("foo", nil))
+		require.Empty(t, s.CSRFToken())
+		require.False(t, s.VerifyCSRF(newRequest(nil)))
+		require.NoError(t, s.Save())
+	})
+
+	t.Run("disabled sessions", func(t *testing.T) {
+		var h Handler
+		s, err := h.Session(httptest.NewRecorder(), newRequest(nil))
+		require.NoError(t, err)
+		require.Nil(t, s)
+	})
+}
+
+func responseCookies(w *httptest.ResponseRecorder) []*http.Cookie {
+	res := http.Response{Header: w.Header()}
+	return res.Cookies()
+}
@@ -0,0 +1,386 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// NewMemorySessionStore creates a session store that keeps sessions in
+// memory. Sessions are lost when the server restarts and are not shared
+// between server instances.
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{
+		sessions: make(map[string]memorySession),
+	}
+}
+
+type memorySessionStore struct {
+	mutex     sync.Mutex
+	sessions  map[string]memorySession
+	lastSweep time.Time
+}
+
+type memorySession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func (s *memorySessionStore) Load(ctx context.Context, id string) ([]byte, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || time.Now().After(session.expiresAt) {
+		return nil, false, nil
+	}
+	return session.data, true, nil
+}
+
+func (s *memorySessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.sessions[id] = memorySession{
+		data:      data,
+		expiresAt: now.Add(ttl),
+	}
+
+	if now.Sub(s.lastSweep) > time.Minute {
+		for id, session := range s.sessions {
+			if now.After(session.expiresAt) {
+				delete(s.sessions, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	return nil
+}
+
+func (s *memorySessionStore) Delete(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
+
+// SQLSessionStore is a session store that persists sessions in a SQL
+// database.
+//
+// The table must be created beforehand. eg:
+//  CREATE TABLE sessions (
+//      id         VARCHAR(64) PRIMARY KEY,
+//      data       BLOB NOT NULL,
+//      expires_at BIGINT NOT NULL
+//  );
+//
+// Expired sessions are not returned but are left in the table. They can be
+// removed periodically with DeleteExpired.
+type SQLSessionStore struct {
+	// The database.
+	DB *sql.DB
+
+	// The name of the table where sessions are stored.
+	//
+	// Default: "sessions".
+	Table string
+
+	// The function that returns the placeholder of the nth query argument,
+	// starting at 1. eg: "$1" for PostgreSQL.
+	//
+	// Default: "?".
+	Placeholder func(n int) string
+}
+
+func (s SQLSessionStore) Load(ctx context.Context, id string) ([]byte, bool, error) {
+	var data []byte
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT data FROM "+s.table()+" WHERE id = "+s.placeholder(1)+" AND expires_at > "+s.placeholder(2),
+		id,
+		time.Now().Unix(),
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.New("loading sql session failed").
+			Tag("table", s.table()).
+			Wrap(err)
+	}
+	return data, true, nil
+}
+
+func (s SQLSessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	err := s.save(ctx, id, data, ttl)
+	if err != nil {
+		return errors.New("saving sql session failed").
+			Tag("table", s.table()).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (s SQLSessionStore) save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Upserts are not portable across databases.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+s.table()+" WHERE id = "+s.placeholder(1), id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO "+s.table()+" (id, data, expires_at) VALUES ("+s.placeholder(1)+", "+s.placeholder(2)+", "+s.placeholder(3)+")",
+		id,
+		data,
+		time.Now().Add(ttl).Unix(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s SQLSessionStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM "+s.table()+" WHERE id = "+s.placeholder(1), id); err != nil {
+		return errors.New("deleting sql session failed").
+			Tag("table", s.table()).
+			Wrap(err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes the expired sessions.
+func (s SQLSessionStore) DeleteExpired(ctx context.Context) error {
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM "+s.table()+" WHERE expires_at <= "+s.placeholder(1), time.Now().Unix()); err != nil {
+		return errors.New("deleting expired sql sessions failed").
+			Tag("table", s.table()).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (s SQLSessionStore) table() string {
+	if s.Table == "" {
+		return "sessions"
+	}
+	return s.Table
+}
+
+func (s SQLSessionStore) placeholder(n int) string {
+	if s.Placeholder == nil {
+		return "?"
+	}
+	return s.Placeholder(n)
+}
+
+// RedisSessionStore is a session store that persists sessions in Redis. It
+// speaks the Redis protocol directly and does not require a client library.
+type RedisSessionStore struct {
+	// The address of the Redis server. eg: "localhost:6379".
+	Addr string
+
+	// The password used to authenticate. No authentication is performed when
+	// empty.
+	Password string
+
+	// The database index.
+	DB int
+
+	// The prefix of the session keys.
+	//
+	// Default: "goapp:session:".
+	Prefix string
+
+	// The maximum number of idle connections kept open.
+	//
+	// Default: 8.
+	MaxIdleConns int
+
+	once sync.Once
+	idle chan *redisConn
+}
+
+func (s *RedisSessionStore) Load(ctx context.Context, id string) ([]byte, bool, error) {
+	res, err := s.do(ctx, "GET", s.key(id))
+	if err != nil {
+		return nil, false, errors.New("loading redis session failed").
+			Tag("addr", s.Addr).
+			Wrap(err)
+	}
+	if res == nil {
+		return nil, false, nil
+	}
+	return res, true, nil
+}
+
+func (s *RedisSessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	ms := strconv.FormatInt(int64(ttl/time.Millisecond), 10)
+	if _, err := s.do(ctx, "SET", s.key(id), string(data), "PX", ms); err != nil {
+		return errors.New("saving redis session failed").
+			Tag("addr", s.Addr).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (s *RedisSessionStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.do(ctx, "DEL", s.key(id)); err != nil {
+		return errors.New("deleting redis session failed").
+			Tag("addr", s.Addr).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (s *RedisSessionStore) key(id string) string {
+	if s.Prefix == "" {
+		return "goapp:session:" + id
+	}
+	return s.Prefix + id
+}
+
+func (s *RedisSessionStore) do(ctx context.Context, args ...string) ([]byte, error) {
+	s.once.Do(func() {
+		size := s.MaxIdleConns
+		if size <= 0 {
+			size = 8
+		}
+		s.idle = make(chan *redisConn, size)
+	})
+
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Time{})
+	}
+
+	res, err := conn.do(args...)
+	if _, isReplyErr := err.(redisError); err != nil && !isReplyErr {
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case s.idle <- conn:
+	default:
+		conn.Close()
+	}
+	return res, err
+}
+
+func (s *RedisSessionStore) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &redisConn{
+		Conn:   c,
+		reader: bufio.NewReader(c),
+	}
+	if s.Password != "" {
+		if _, err := conn.do("AUTH", s.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if s.DB != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(s.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+func (c *redisConn) do(args ...string) ([]byte, error) {
+	cmd := make([]byte, 0, 64)
+	cmd = append(cmd, '*')
+	cmd = strconv.AppendInt(cmd, int64(len(args)), 10)
+	cmd = append(cmd, '\r', '\n')
+	for _, a := range args {
+		cmd = append(cmd, '$')
+		cmd = strconv.AppendInt(cmd, int64(len(a)), 10)
+		cmd = append(cmd, '\r', '\n')
+		cmd = append(cmd, a...)
+		cmd = append(cmd, '\r', '\n')
+	}
+
+	if _, err := c.Write(cmd); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads a Redis reply. Bulk strings are returned as is, nil bulk
+// strings as nil and other replies as their textual value.
+func (c *redisConn) readReply() ([]byte, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, errors.New("invalid redis reply").Tag("reply", line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+
+	case '-':
+		return nil, redisError(line[1:])
+
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.New("invalid redis bulk string length").Wrap(err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+
+	default:
+		return nil, errors.New("unsupported redis reply").Tag("reply", line)
+	}
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	testSessionStore(t, NewMemorySessionStore())
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	addr := newTestRedisServer(t)
+	testSessionStore(t, &RedisSessionStore{
+		Addr:     addr,
+		Password: "secret",
+		DB:       2,
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		s := &RedisSessionStore{Addr: addr, Password: "wrong"}
+		_, _, err := s.Load(context.Background(), "foo")
+		require.Error(t, err)
+	})
+}
+
+func testSessionStore(t *testing.T, s SessionStore) {
+	ctx := context.Background()
+
+	_, ok, err := s.Load(ctx, "foo")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, s.Save(ctx, "foo", []byte(`{"values":{"user":"max"}}`), time.Minute))
+	data, ok, err := s.Load(ctx, "foo")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, `{"values":{"user":"max"}}`, string(data))
+
+	require.NoError(t, s.Save(ctx, "expired", []byte("bye"), -time.Second))
+	_, ok, err = s.Load(ctx, "expired")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, s.Delete(ctx, "foo"))
+	_, ok, err = s.Load(ctx, "foo")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+// newTestRedisServer starts a server that implements the subset of the Redis
+// protocol used by RedisSessionStore.
+func newTestRedisServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	var mutex sync.Mutex
+	values := make(map[string]string)
+	expirations := make(map[string]time.Time)
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		authenticated := false
+
+		for {
+			args, err := readTestRedisCommand(r)
+			if err != nil {
+				return
+			}
+
+			mutex.Lock()
+			var reply string
+			switch cmd := strings.ToUpper(args[0]); {
+			case cmd == "AUTH":
+				authenticated = args[1] == "secret"
+				reply = "+OK\r\n"
+				if !authenticated {
+					reply = "-WRONGPASS invalid password\r\n"
+				}
+
+			case !authenticated:
+				reply = "-NOAUTH authentication required\r\n"
+
+			case cmd == "SELECT":
+				reply = "+OK\r\n"
+
+			case cmd == "SET":
+				ms, _ := strconv.Atoi(args[4])
+				values[args[1]] = args[2]
+				expirations[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+				reply = "+OK\r\n"
+
+			case cmd == "GET":
+				v, ok := values[args[1]]
+				if !ok || time.Now().After(expirations[args[1]]) {
+					reply = "$-1\r\n"
+				} else {
+					reply = "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+				}
+
+			case cmd == "DEL":
+				delete(values, args[1])
+				reply = ":1\r\n"
+			}
+			mutex.Unlock()
+
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func readTestRedisCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"net/http"
+)
+
+// Shutdown gracefully shuts down the handler without interrupting requests
+// that are being served. It is meant to be called before the http.Server that
+// uses the handler is shut down, during rolling deploys.
+//
+// Once called, the readiness endpoint reports the server as unavailable, page
+// requests that require a prerender are rejected with a 503 status code and the
+// functions registered with RegisterOnShutdown are launched on their own
+// goroutine. Shutdown then waits for in-flight requests and their prerendering
+// engine work to complete.
+//
+// If the given context expires before in-flight requests are completed,
+// Shutdown returns the context's error.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	h.once.Do(h.init)
+
+	h.shutdownMutex.Lock()
+	if !h.isShutdown {
+		h.isShutdown = true
+		h.drained = make(chan struct{})
+		if h.activeRequests == 0 {
+			close(h.drained)
+		}
+
+		for _, fn := range h.onShutdown {
+			go fn()
+		}
+		h.maintenance.close()
+	}
+	drained := h.drained
+	h.shutdownMutex.Unlock()
+
+	for _, t := range h.tenants {
+		if err := t.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-drained:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterOnShutdown registers a function to call when Shutdown is called. It
+// is used to close long-lived connections such as websockets, with a going
+// away status that tells clients to reconnect.
+func (h *Handler) RegisterOnShutdown(fn func()) {
+	h.shutdownMutex.Lock()
+	defer h.shutdownMutex.Unlock()
+
+	h.onShutdown = append(h.onShutdown, fn)
+}
+
+// IsShuttingDown reports whether Shutdown has been called.
+func (h *Handler) IsShuttingDown() bool {
+	h.shutdownMutex.Lock()
+	defer h.shutdownMutex.Unlock()
+
+	return h.isShutdown
+}
+
+func (h *Handler) beginRequest() {
+	h.shutdownMutex.Lock()
+	h.activeRequests++
+	h.shutdownMutex.Unlock()
+}
+
+func (h *Handler) endRequest() {
+	h.shutdownMutex.Lock()
+	defer h.shutdownMutex.Unlock()
+
+	h.activeRequests--
+	if h.isShutdown && h.activeRequests == 0 {
+		select {
+		case <-h.drained:
+		default:
+			close(h.drained)
+		}
+	}
+}
+
+func (h *Handler) serveShuttingDown(w http.ResponseWriter) {
+	w.Header().Set("Connection", "close")
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+}
@@ -0,0 +1,59 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerShutdown(t *testing.T) {
+	h := Handler{
+		HealthEndpoints: HealthEndpoints{
+			ReadyPath: "/readyz",
+		},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, serve("/readyz").Code)
+
+	hookCalled := make(chan struct{})
+	h.RegisterOnShutdown(func() {
+		close(hookCalled)
+	})
+
+	err := h.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.True(t, h.IsShuttingDown())
+	<-hookCalled
+
+	require.Equal(t, http.StatusServiceUnavailable, serve("/readyz").Code)
+	require.Equal(t, http.StatusServiceUnavailable, serve("/").Code)
+	require.Equal(t, http.StatusOK, serve("/app.css").Code)
+}
+
+func TestHandlerShutdownWaitsForInFlightRequests(t *testing.T) {
+	h := Handler{}
+	h.once.Do(h.init)
+	h.beginRequest()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	err := h.Shutdown(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	h.endRequest()
+	err = h.Shutdown(context.Background())
+	require.NoError(t, err)
+}
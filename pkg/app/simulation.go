@@ -0,0 +1,343 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	defaultSimulationSteps = 100
+)
+
+var (
+	// The values typed in inputs when Simulation.Inputs is empty.
+	defaultSimulationInputs = []string{
+		"",
+		"a",
+		"0",
+		"-1",
+		"42",
+		"3.14",
+		"1e300",
+		"hello world",
+		"  padded  ",
+		"<script>alert(1)</script>",
+		"ünïcødé 🙂",
+		strings.Repeat("x", 1024),
+	}
+
+	// The keys pressed by keyboard events.
+	simulationKeys = []string{
+		"Enter",
+		"Escape",
+		"Tab",
+		"Backspace",
+		"ArrowUp",
+		"ArrowDown",
+		" ",
+		"a",
+	}
+
+	// The events that are simulated when an element has a handler for them.
+	simulatedEvents = map[string]bool{
+		"blur":     true,
+		"change":   true,
+		"click":    true,
+		"dblclick": true,
+		"focus":    true,
+		"input":    true,
+		"keydown":  true,
+		"keyup":    true,
+		"submit":   true,
+	}
+)
+
+// Simulation generates random but valid sequences of events, such as clicks,
+// inputs and navigations, and applies them to a component mounted with
+// NewClientTester. Invariants are checked after each event is consumed,
+// which catches the state machine bugs of a component automatically.
+//
+// Sequences are deterministic: a simulation with the same seed applies the
+// same events to the same component, which makes failures reproducible and
+// plays well with fuzzing. Eg:
+//  func FuzzCart(f *testing.F) {
+//      f.Fuzz(func(t *testing.T, seed int64) {
+//          cart := &cart{}
+//          d := app.NewClientTester(cart)
+//          defer d.Close()
+//
+//          _, err := app.Simulation{
+//              Seed: seed,
+//              URLs: []string{"/cart", "/checkout"},
+//              Invariant: func() error {
+//                  if cart.total() < 0 {
+//                      return errors.New("negative total")
+//                  }
+//                  return nil
+//              },
+//          }.Run(d, cart)
+//          require.NoError(t, err)
+//      })
+//  }
+type Simulation struct {
+	// The seed of the random sequence.
+	Seed int64
+
+	// The number of events to apply.
+	//
+	// Default: 100.
+	Steps int
+
+	// The URLs that can be navigated to, in addition to the ones of the
+	// mounted links.
+	URLs []string
+
+	// The values typed in text inputs, text areas and unknown selects.
+	//
+	// Default: a set of edge case strings, such as "", "-1", "1e300" or
+	// HTML.
+	Inputs []string
+
+	// The function called after each event is consumed. The simulation stops
+	// when it returns an error.
+	Invariant func() error
+}
+
+// SimulationStep describes an event applied by a simulation.
+type SimulationStep struct {
+	// The event type, such as "click" or "input", or "nav" for navigations.
+	Event string
+
+	// The path of the element that received the event, made of the
+	// component types and element tags that lead to it. It is empty for
+	// navigations. Eg: "*main.cart > div > button#checkout".
+	Path string
+
+	// The value typed in an input, the key pressed or the URL navigated to.
+	Value string
+}
+
+func (s SimulationStep) String() string {
+	str := s.Event
+	if s.Path != "" {
+		str += " " + s.Path
+	}
+	if s.Value != "" {
+		str += fmt.Sprintf(" %q", s.Value)
+	}
+	return str
+}
+
+// Run applies the simulation to the given component, which must be mounted
+// in the given dispatcher. It returns the applied steps, and an error that
+// describes the failing step when an invariant is violated or when an event
+// handler panics.
+func (s Simulation) Run(d ClientDispatcher, root UI) ([]SimulationStep, error) {
+	if s.Steps <= 0 {
+		s.Steps = defaultSimulationSteps
+	}
+	if len(s.Inputs) == 0 {
+		s.Inputs = defaultSimulationInputs
+	}
+	rnd := rand.New(rand.NewSource(s.Seed))
+
+	d.Consume()
+	if err := s.check(); err != nil {
+		return nil, errors.New("simulation invariant is violated before the first step").
+			Tag("seed", s.Seed).
+			Wrap(err)
+	}
+
+	steps := make([]SimulationStep, 0, s.Steps)
+	for i := 0; i < s.Steps; i++ {
+		targets := simulationTargets(root, "")
+		if len(targets) == 0 && len(s.URLs) == 0 {
+			break
+		}
+
+		step, err := s.apply(d, rnd, targets)
+		steps = append(steps, step)
+		if err == nil {
+			err = s.check()
+		}
+		if err != nil {
+			return steps, errors.New("simulation failed").
+				Tag("seed", s.Seed).
+				Tag("step", i).
+				Tag("event", step).
+				Tag("steps", steps).
+				Wrap(err)
+		}
+	}
+	return steps, nil
+}
+
+func (s Simulation) apply(d ClientDispatcher, rnd *rand.Rand, targets []simulationTarget) (step SimulationStep, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("event handler panicked").Tag("panic", r)
+		}
+	}()
+
+	if n := rnd.Intn(len(targets) + len(s.URLs)); n >= len(targets) {
+		step = SimulationStep{Event: "nav", Value: s.URLs[n-len(targets)]}
+		simulateNav(d, step.Value)
+		d.Consume()
+		return step, nil
+	}
+
+	t := targets[rnd.Intn(len(targets))]
+	step = SimulationStep{Event: t.event, Path: t.path}
+	props := map[string]interface{}{"target": t.node.JSValue()}
+
+	switch {
+	case t.event == "keydown" || t.event == "keyup":
+		step.Value = simulationKeys[rnd.Intn(len(simulationKeys))]
+		props["key"] = step.Value
+
+	case t.event == "input" || t.event == "change":
+		step.Value = s.simulateValue(rnd, t.node)
+	}
+
+	if h, ok := t.node.eventHandlers()[t.event]; ok {
+		event := Event{Value: newSimulatedEvent(t.event, props)}
+		node := t.node
+		d.Dispatch(Dispatch{
+			Mode:   Update,
+			Source: node,
+			Function: func(ctx Context) {
+				ctx.Emit(func() {
+					h.value(ctx, event)
+				})
+			},
+		})
+	}
+	d.Consume()
+
+	if t.event == "click" && isSimulatedLink(t.node) {
+		step.Value = t.node.attributes()["href"]
+		simulateNav(d, step.Value)
+		d.Consume()
+	}
+	return step, nil
+}
+
+// simulateValue sets a random valid value to the given form element and
+// returns it.
+func (s Simulation) simulateValue(rnd *rand.Rand, n UI) string {
+	attrs := n.attributes()
+
+	switch {
+	case attrs["type"] == "checkbox" || attrs["type"] == "radio":
+		checked := rnd.Intn(2) == 0
+		setSimulatedProperty(n, "checked", checked)
+		return fmt.Sprint(checked)
+
+	case n.name() == "select":
+		var options []string
+		collectSimulationOptions(n, &options)
+		if len(options) != 0 {
+			v := options[rnd.Intn(len(options))]
+			setSimulatedProperty(n, "value", v)
+			return v
+		}
+	}
+
+	v := s.Inputs[rnd.Intn(len(s.Inputs))]
+	setSimulatedProperty(n, "value", v)
+	return v
+}
+
+func (s Simulation) check() error {
+	if s.Invariant == nil {
+		return nil
+	}
+	return s.Invariant()
+}
+
+func simulateNav(d ClientDispatcher, rawURL string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(errors.New("parsing simulated url failed").
+			Tag("url", rawURL).
+			Wrap(err))
+	}
+	d.Nav(u)
+}
+
+type simulationTarget struct {
+	node  UI
+	path  string
+	event string
+}
+
+// simulationTargets returns the elements of the given tree that handle a
+// simulated event, and the links.
+func simulationTargets(n UI, path string) []simulationTarget {
+	var targets []simulationTarget
+
+	switch n.Kind() {
+	case Component:
+		path = joinNodePath(path, reflect.TypeOf(n).String())
+
+	case HTML:
+		path = joinNodePath(path, nodePathName(n))
+		if _, disabled := n.attributes()["disabled"]; disabled {
+			return nil
+		}
+
+		events := make([]string, 0, len(n.eventHandlers()))
+		for event := range n.eventHandlers() {
+			if simulatedEvents[event] {
+				events = append(events, event)
+			}
+		}
+		if _, ok := n.eventHandlers()["click"]; !ok && isSimulatedLink(n) {
+			events = append(events, "click")
+		}
+		sort.Strings(events)
+
+		for _, event := range events {
+			targets = append(targets, simulationTarget{
+				node:  n,
+				path:  path,
+				event: event,
+			})
+		}
+
+	default:
+		return nil
+	}
+
+	for _, c := range n.children() {
+		targets = append(targets, simulationTargets(c, path)...)
+	}
+	return targets
+}
+
+// isSimulatedLink reports whether the given element is a link to a page of
+// the app.
+func isSimulatedLink(n UI) bool {
+	if n.name() != "a" {
+		return false
+	}
+	href := n.attributes()["href"]
+	return strings.HasPrefix(href, "/") && !strings.HasPrefix(href, "//")
+}
+
+func collectSimulationOptions(n UI, options *[]string) {
+	if n.Kind() == HTML && n.name() == "option" {
+		if v, ok := n.attributes()["value"]; ok {
+			*options = append(*options, v)
+		}
+	}
+	for _, c := range n.children() {
+		collectSimulationOptions(c, options)
+	}
+}
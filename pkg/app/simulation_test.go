@@ -0,0 +1,170 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type simulationTestCompo struct {
+	Compo
+
+	guarded  bool
+	count    int
+	username string
+	agreed   bool
+	size     string
+	lastKey  string
+	navCount int
+}
+
+func (c *simulationTestCompo) OnNav(ctx Context) {
+	c.navCount++
+}
+
+func (c *simulationTestCompo) Render() UI {
+	return Div().Body(
+		Button().ID("inc").OnClick(c.increment).Text("+"),
+		Button().ID("dec").OnClick(c.decrement).Text("-"),
+		Button().ID("disabled").Disabled(true).OnClick(func(Context, Event) {
+			panic("disabled button clicked")
+		}),
+		Input().
+			Type("text").
+			Value(c.username).
+			OnInput(c.ValueTo(&c.username)).
+			OnKeyDown(c.onKeyDown),
+		Input().
+			Type("checkbox").
+			OnChange(c.onAgree),
+		Select().
+			OnChange(c.ValueTo(&c.size)).
+			Body(
+				Option().Value("s").Text("S"),
+				Option().Value("m").Text("M"),
+			),
+		A().Href("/about").Text("About"),
+		A().Href("https://go-app.dev").Text("External"),
+		P().Text(c.count),
+	)
+}
+
+func (c *simulationTestCompo) increment(ctx Context, e Event) {
+	c.count++
+}
+
+func (c *simulationTestCompo) decrement(ctx Context, e Event) {
+	if c.guarded && c.count == 0 {
+		return
+	}
+	c.count--
+}
+
+func (c *simulationTestCompo) onKeyDown(ctx Context, e Event) {
+	c.lastKey = e.Get("key").String()
+}
+
+func (c *simulationTestCompo) onAgree(ctx Context, e Event) {
+	c.agreed = ctx.JSSrc().Get("checked").Bool()
+}
+
+func (c *simulationTestCompo) invariant() error {
+	if c.count < 0 {
+		return errors.New("negative count").Tag("count", c.count)
+	}
+	if c.size != "" && c.size != "s" && c.size != "m" {
+		return errors.New("invalid size").Tag("size", c.size)
+	}
+	return nil
+}
+
+func TestSimulation(t *testing.T) {
+	t.Run("invariants are respected", func(t *testing.T) {
+		compo := &simulationTestCompo{guarded: true}
+		d := NewClientTester(compo)
+		defer d.Close()
+
+		steps, err := Simulation{
+			Seed:      42,
+			Steps:     300,
+			URLs:      []string{"/settings"},
+			Invariant: compo.invariant,
+		}.Run(d, compo)
+		require.NoError(t, err)
+		require.Len(t, steps, 300)
+
+		events := make(map[string]bool)
+		for _, s := range steps {
+			events[s.Event] = true
+			require.NotContains(t, s.Path, "disabled")
+			require.NotEqual(t, "https://go-app.dev", s.Value)
+		}
+		require.Equal(t, map[string]bool{
+			"click":   true,
+			"input":   true,
+			"change":  true,
+			"keydown": true,
+			"nav":     true,
+		}, events)
+		require.NotZero(t, compo.navCount)
+		require.NotEmpty(t, compo.lastKey)
+		require.NotEmpty(t, compo.size)
+	})
+
+	t.Run("invariant violation is reported", func(t *testing.T) {
+		compo := &simulationTestCompo{}
+		d := NewClientTester(compo)
+		defer d.Close()
+
+		steps, err := Simulation{
+			Seed:      42,
+			Steps:     1000,
+			Invariant: compo.invariant,
+		}.Run(d, compo)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "negative count")
+		require.Equal(t, "click", steps[len(steps)-1].Event)
+		require.True(t, strings.HasSuffix(steps[len(steps)-1].Path, "button#dec"))
+	})
+
+	t.Run("sequences are deterministic", func(t *testing.T) {
+		run := func() []SimulationStep {
+			compo := &simulationTestCompo{guarded: true}
+			d := NewClientTester(compo)
+			defer d.Close()
+
+			steps, err := Simulation{Seed: 7, Steps: 50}.Run(d, compo)
+			require.NoError(t, err)
+			return steps
+		}
+		require.Equal(t, run(), run())
+	})
+
+	t.Run("panic is reported", func(t *testing.T) {
+		root := Div().Body(
+			Button().OnClick(func(Context, Event) {
+				panic("boom")
+			}),
+		)
+		d := NewClientTester(root)
+		defer d.Close()
+
+		_, err := Simulation{}.Run(d, root)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "boom")
+	})
+}
+
+func TestSimulationStepString(t *testing.T) {
+	require.Equal(t, `input *app.hello > input "hi"`, SimulationStep{
+		Event: "input",
+		Path:  "*app.hello > input",
+		Value: "hi",
+	}.String())
+	require.Equal(t, "nav \"/about\"", SimulationStep{
+		Event: "nav",
+		Value: "/about",
+	}.String())
+}
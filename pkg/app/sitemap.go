@@ -0,0 +1,210 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sitemap describes the sitemap.xml file generated from the registered routes.
+//
+// The sitemap is generated when BaseURL is set. It takes precedence over the
+// /web/sitemap.xml static resource.
+//
+// eg:
+//  app.Handler{
+//      Sitemap: app.Sitemap{
+//          BaseURL: "https://go-app.dev",
+//          Paths:   []string{"/reference/app"},
+//          Priority: func(path string) float64 {
+//              if path == "/" {
+//                  return 1
+//              }
+//              return 0.5
+//          },
+//      },
+//  },
+type Sitemap struct {
+	// The URL the paths are relative to. eg: "https://go-app.dev".
+	BaseURL string
+
+	// Additional paths to list in the sitemap. This is typically used to list
+	// pages that are routed with RouteWithRegexp, which can't be listed
+	// automatically.
+	Paths []string
+
+	// The paths to exclude from the sitemap.
+	Exclude []string
+
+	// The function that returns the last modification date of the page at the
+	// given path. The date is omitted when the function is nil or returns a
+	// zero time.
+	LastMod func(path string) time.Time
+
+	// The function that returns how frequently the page at the given path is
+	// likely to change. eg: "daily". The frequency is omitted when the function
+	// is nil or returns an empty string.
+	ChangeFreq func(path string) string
+
+	// The function that returns the priority of the page at the given path,
+	// ranging from 0.0 to 1.0. The priority is omitted when the function is nil
+	// or returns 0.
+	Priority func(path string) float64
+}
+
+// Robots describes the robots.txt file generated by the Handler.
+//
+// The robots.txt file is generated when rules are set. It takes precedence over
+// the /web/robots.txt static resource and references the generated sitemap
+// when there is one.
+//
+// eg:
+//  app.Handler{
+//      Robots: app.Robots{
+//          Rules: []app.RobotsRule{
+//              {
+//                  UserAgent: "*",
+//                  Disallow:  []string{"/admin"},
+//              },
+//          },
+//      },
+//  },
+type Robots struct {
+	// The rules that tell crawlers which paths they can access.
+	Rules []RobotsRule
+
+	// The URLs of additional sitemaps.
+	Sitemaps []string
+}
+
+// RobotsRule describes the paths a crawler is allowed to access.
+type RobotsRule struct {
+	// The crawler the rule applies to. Default is "*".
+	UserAgent string
+
+	// The paths that are allowed to be crawled.
+	Allow []string
+
+	// The paths that are not allowed to be crawled.
+	Disallow []string
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+func (h *Handler) initSitemap() {
+	ctx := context.TODO()
+
+	if h.Sitemap.BaseURL != "" {
+		h.pwaResources.Set(ctx, PreRenderedItem{
+			Path:        "/sitemap.xml",
+			ContentType: "application/xml",
+			Body:        h.makeSitemapXML(),
+		})
+	}
+
+	if len(h.Robots.Rules) != 0 {
+		h.pwaResources.Set(ctx, PreRenderedItem{
+			Path:        "/robots.txt",
+			ContentType: "text/plain; charset=utf-8",
+			Body:        h.makeRobotsTxt(),
+		})
+	}
+}
+
+func (h *Handler) makeSitemapXML() []byte {
+	excluded := make(map[string]bool, len(h.Sitemap.Exclude))
+	for _, p := range h.Sitemap.Exclude {
+		excluded[p] = true
+	}
+
+	paths := append(routes.paths(), h.Sitemap.Paths...)
+	paths = append(paths, h.localizedPaths(paths)...)
+	sort.Strings(paths)
+
+	baseURL := strings.TrimSuffix(h.Sitemap.BaseURL, "/")
+	urlset := sitemapURLSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+	}
+
+	for i, p := range paths {
+		if excluded[p] || (i > 0 && paths[i-1] == p) {
+			continue
+		}
+
+		u := sitemapURL{
+			Loc: baseURL + p,
+		}
+		if h.Sitemap.LastMod != nil {
+			if t := h.Sitemap.LastMod(p); !t.IsZero() {
+				u.LastMod = t.UTC().Format("2006-01-02")
+			}
+		}
+		if h.Sitemap.ChangeFreq != nil {
+			u.ChangeFreq = h.Sitemap.ChangeFreq(p)
+		}
+		if h.Sitemap.Priority != nil {
+			if priority := h.Sitemap.Priority(p); priority > 0 {
+				u.Priority = strconv.FormatFloat(priority, 'f', 1, 64)
+			}
+		}
+		urlset.URLs = append(urlset.URLs, u)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "  ")
+	enc.Encode(urlset)
+	return b.Bytes()
+}
+
+func (h *Handler) makeRobotsTxt() []byte {
+	var b bytes.Buffer
+
+	for i, r := range h.Robots.Rules {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		userAgent := r.UserAgent
+		if userAgent == "" {
+			userAgent = "*"
+		}
+		b.WriteString("User-agent: " + userAgent + "\n")
+
+		for _, p := range r.Allow {
+			b.WriteString("Allow: " + p + "\n")
+		}
+		for _, p := range r.Disallow {
+			b.WriteString("Disallow: " + p + "\n")
+		}
+	}
+
+	sitemaps := h.Robots.Sitemaps
+	if h.Sitemap.BaseURL != "" {
+		sitemaps = append([]string{strings.TrimSuffix(h.Sitemap.BaseURL, "/") + "/sitemap.xml"}, sitemaps...)
+	}
+	if len(sitemaps) != 0 {
+		b.WriteByte('\n')
+	}
+	for _, s := range sitemaps {
+		b.WriteString("Sitemap: " + s + "\n")
+	}
+
+	return b.Bytes()
+}
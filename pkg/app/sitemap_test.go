@@ -0,0 +1,91 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerSitemap(t *testing.T) {
+	Route("/sitemap-test", &routeCompo{})
+	Route("/sitemap-test/excluded", &routeCompo{})
+
+	h := Handler{
+		Sitemap: Sitemap{
+			BaseURL: "https://go-app.dev/",
+			Paths:   []string{"/sitemap-test/extra"},
+			Exclude: []string{"/sitemap-test/excluded"},
+			LastMod: func(path string) time.Time {
+				return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+			},
+			ChangeFreq: func(path string) string {
+				return "weekly"
+			},
+			Priority: func(path string) float64 {
+				if path == "/sitemap-test" {
+					return 0.8
+				}
+				return 0
+			},
+		},
+		Robots: Robots{
+			Rules: []RobotsRule{
+				{Disallow: []string{"/admin"}},
+				{UserAgent: "Googlebot", Allow: []string{"/"}},
+			},
+		},
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("sitemap is generated from routes", func(t *testing.T) {
+		w := serve("/sitemap.xml")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+
+		body := w.Body.String()
+		require.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		require.Contains(t, body, "<loc>https://go-app.dev/sitemap-test</loc>")
+		require.Contains(t, body, "<loc>https://go-app.dev/sitemap-test/extra</loc>")
+		require.NotContains(t, body, "/sitemap-test/excluded")
+		require.Contains(t, body, "<lastmod>2021-06-01</lastmod>")
+		require.Contains(t, body, "<changefreq>weekly</changefreq>")
+		require.Contains(t, body, "<priority>0.8</priority>")
+	})
+
+	t.Run("robots txt is generated", func(t *testing.T) {
+		w := serve("/robots.txt")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "User-agent: *\n"+
+			"Disallow: /admin\n"+
+			"\n"+
+			"User-agent: Googlebot\n"+
+			"Allow: /\n"+
+			"\n"+
+			"Sitemap: https://go-app.dev/sitemap.xml\n",
+			w.Body.String(),
+		)
+	})
+}
+
+func TestHandlerSitemapDisabled(t *testing.T) {
+	h := Handler{}
+	h.once.Do(h.init)
+
+	_, ok := h.pwaResources.Get(context.Background(), "/sitemap.xml")
+	require.False(t, ok)
+
+	_, ok = h.pwaResources.Get(context.Background(), "/robots.txt")
+	require.False(t, ok)
+}
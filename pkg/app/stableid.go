@@ -0,0 +1,40 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+)
+
+// stableID returns an ID made of the given prefix and of the position of the
+// given node relative to its topmost component ancestor. The topmost
+// component is the page component, which is mounted in different containers
+// on the server and on the client, so positions above it are ignored.
+func stableID(n UI, prefix string) string {
+	var ancestors []UI
+	top := 0
+	for p := n; p != nil; p = p.parent() {
+		ancestors = append(ancestors, p)
+		if p.Kind() == Component {
+			top = len(ancestors) - 1
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i := top - 1; i >= 0; i-- {
+		b.WriteByte('-')
+		b.WriteString(strconv.Itoa(childIndex(ancestors[i+1], ancestors[i])))
+	}
+	return b.String()
+}
+
+// childIndex returns the index of the given child in the children of the
+// given parent, or -1 when it is not found.
+func childIndex(parent, child UI) int {
+	for i, c := range parent.children() {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
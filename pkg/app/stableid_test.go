@@ -0,0 +1,77 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stableIDField struct {
+	Compo
+
+	id string
+}
+
+func (f *stableIDField) OnPreRender(ctx Context) {
+	f.id = ctx.StableID("field")
+}
+
+func (f *stableIDField) OnMount(ctx Context) {
+	f.id = ctx.StableID("field")
+}
+
+func (f *stableIDField) Render() UI {
+	return Div().Body(
+		Label().For(f.id).Text("Field"),
+		Input().ID(f.id),
+	)
+}
+
+type stableIDForm struct {
+	Compo
+
+	fields [2]*stableIDField
+}
+
+func (f *stableIDForm) Render() UI {
+	if f.fields[0] == nil {
+		f.fields = [2]*stableIDField{{}, {}}
+	}
+	return Form().Body(
+		f.fields[0],
+		Div().Body(f.fields[1]),
+	)
+}
+
+func TestStableID(t *testing.T) {
+	serverForm := &stableIDForm{}
+	server := &engine{RunsInServer: true}
+	body := Body().Body(
+		Div().Body(
+			Aside().ID("app-wasm-loader"),
+			Div().ID("app-pre-render").Body(serverForm),
+		),
+	)
+	err := mount(server, body)
+	require.NoError(t, err)
+	server.Body = body
+	server.init()
+	defer server.Close()
+	server.PreRender()
+	server.Consume()
+
+	clientForm := &stableIDForm{}
+	client := NewClientTester(clientForm)
+	defer client.Close()
+	client.Consume()
+
+	for i := range serverForm.fields {
+		require.NotEmpty(t, serverForm.fields[i].id)
+		require.Equal(t, serverForm.fields[i].id, clientForm.fields[i].id)
+	}
+	require.Equal(t, "field-0-0", clientForm.fields[0].id)
+	require.Equal(t, "field-0-1-0", clientForm.fields[1].id)
+
+	ctx := makeContext(clientForm)
+	require.Equal(t, "form", ctx.StableID("form"))
+}
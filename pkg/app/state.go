@@ -36,6 +36,9 @@ type State struct {
 	// Reports whether the state is persisted in local storage.
 	IsPersistent bool
 
+	// Reports whether the state is persisted in session storage.
+	IsSessionPersistent bool
+
 	// Reports whether the state is encrypted before being persisted in local
 	// storage.
 	IsEncrypted bool
@@ -46,7 +49,11 @@ type State struct {
 	// Reports whether a state is broadcasted to other browser tabs and windows.
 	IsBroadcasted bool
 
+	// Reports whether a state is saved in the crash snapshots.
+	IsSnapshotted bool
+
 	value     interface{}
+	raw       []byte
 	observers map[*observer]struct{}
 }
 
@@ -65,8 +72,16 @@ func Persist(s *State) {
 	s.IsPersistent = true
 }
 
-// Encrypt is a state option that encrypts a state before persisting it in local
-// storage. Encryption is performed only when the Persist option is also set.
+// PersistInSession is a state option that persists a state in session storage,
+// which keeps it for the lifetime of the browser tab. It is ignored when the
+// Persist option is also set.
+func PersistInSession(s *State) {
+	s.IsSessionPersistent = true
+}
+
+// Encrypt is a state option that encrypts a state before persisting it.
+// Encryption is performed only when the Persist or PersistInSession option is
+// also set.
 func Encrypt(s *State) {
 	s.IsEncrypted = true
 }
@@ -97,6 +112,13 @@ func Broadcast(s *State) {
 	s.IsBroadcasted = true
 }
 
+// Snapshot is a state option that periodically saves a state in session
+// storage, in order to be restored with Context.RestoreCrashState after the
+// app crashed.
+func Snapshot(s *State) {
+	s.IsSnapshotted = true
+}
+
 type observer struct {
 	element    UI
 	subscribe  func(*observer)
@@ -171,13 +193,14 @@ func (s *store) Set(key string, v interface{}, opts ...StateOption) {
 
 	state := s.states[key]
 	state.value = v
+	state.raw = nil
 	for _, o := range opts {
 		o(&state)
 	}
 	s.states[key] = state
 
-	if state.IsPersistent {
-		if err := s.setPersistent(key, state.IsEncrypted, state.ExpiresAt, v); err != nil {
+	if storage, other, ok := s.persistentStorage(state); ok {
+		if err := s.setPersistent(storage, other, key, state.IsEncrypted, state.ExpiresAt, v); err != nil {
 			Log(errors.New("persisting state failed").
 				Tag("state", key).
 				Wrap(err))
@@ -246,9 +269,14 @@ func (s *store) Get(key string, recv interface{}) {
 		s.states[key] = state
 	}
 
-	if state.value != nil {
+	switch {
+	case state.value != nil:
 		err = storeValue(recv, state.value)
-	} else {
+
+	case state.raw != nil:
+		err = json.Unmarshal(state.raw, recv)
+
+	default:
 		err = s.getPersistent(key, recv)
 	}
 	if err != nil {
@@ -263,7 +291,7 @@ func (s *store) Del(key string) {
 	defer s.mutex.Unlock()
 
 	delete(s.states, key)
-	s.disp.localStorage().Del(key)
+	s.delPersistent(key)
 }
 
 func (s *store) Observe(key string, elem UI) Observer {
@@ -308,10 +336,16 @@ func (s *store) subscribe(key string, o *observer) error {
 	}
 	s.states[key] = state
 
-	if state.value != nil {
+	switch {
+	case state.value != nil:
 		return storeValue(o.receiver, state.value)
+
+	case state.raw != nil:
+		return json.Unmarshal(state.raw, o.receiver)
+
+	default:
+		return s.getPersistent(key, o.receiver)
 	}
-	return s.getPersistent(key, o.receiver)
 }
 
 func (s *store) removeUnusedObservers() {
@@ -324,16 +358,34 @@ func (s *store) removeUnusedObservers() {
 	}
 }
 
+// persistentStorage returns the storage where the given state is persisted
+// and the other storage, where it must not remain.
+func (s *store) persistentStorage(state State) (storage, other BrowserStorage, ok bool) {
+	switch {
+	case state.IsPersistent:
+		return s.disp.localStorage(), s.disp.sessionStorage(), true
+
+	case state.IsSessionPersistent:
+		return s.disp.sessionStorage(), s.disp.localStorage(), true
+
+	default:
+		return nil, nil, false
+	}
+}
+
 func (s *store) getPersistent(key string, recv interface{}) error {
 	var state persistentState
 	s.disp.localStorage().Get(key, &state)
+	if state.isEmpty() {
+		s.disp.sessionStorage().Get(key, &state)
+	}
 
-	if state.EncryptedValue == nil && state.Value == nil && state.ExpiresAt == (time.Time{}) {
+	if state.isEmpty() {
 		return nil
 	}
 
 	if state.isExpired(time.Now()) {
-		s.disp.localStorage().Del(key)
+		s.delPersistent(key)
 		return nil
 	}
 
@@ -343,7 +395,7 @@ func (s *store) getPersistent(key string, recv interface{}) error {
 	return s.disp.Context().Decrypt(state.EncryptedValue, recv)
 }
 
-func (s *store) setPersistent(key string, encrypt bool, expiresAt time.Time, v interface{}) error {
+func (s *store) setPersistent(storage, other BrowserStorage, key string, encrypt bool, expiresAt time.Time, v interface{}) error {
 	var err error
 
 	state := persistentState{
@@ -358,7 +410,16 @@ func (s *store) setPersistent(key string, encrypt bool, expiresAt time.Time, v i
 		return err
 	}
 
-	return s.disp.localStorage().Set(key, state)
+	if err := storage.Set(key, state); err != nil {
+		return err
+	}
+	other.Del(key)
+	return nil
+}
+
+func (s *store) delPersistent(key string) {
+	s.disp.localStorage().Del(key)
+	s.disp.sessionStorage().Del(key)
 }
 
 func (s *store) expireExpiredValues() {
@@ -372,8 +433,9 @@ func (s *store) expireExpiredValues() {
 }
 
 func (s *store) expire(key string, state State) State {
-	s.disp.localStorage().Del(key)
+	s.delPersistent(key)
 	state.value = nil
+	state.raw = nil
 	return state
 }
 
@@ -421,8 +483,12 @@ func (s *store) onBroadcast(event Value) {
 
 	key := event.Get("State").String()
 	v := []byte(event.Get("Value").String())
-	state := s.states[key]
+	s.notifyJSON(key, s.states[key], v)
+}
 
+// notifyJSON updates the observers of the given state with the given JSON
+// encoded value. It must be called with the mutex held.
+func (s *store) notifyJSON(key string, state State, v []byte) {
 	for obs := range state.observers {
 		o := obs
 
@@ -491,6 +557,10 @@ type persistentState struct {
 	ExpiresAt      time.Time       `json:",omitempty"`
 }
 
+func (s *persistentState) isEmpty() bool {
+	return s.EncryptedValue == nil && s.Value == nil && s.ExpiresAt == (time.Time{})
+}
+
 func (s *persistentState) isExpired(now time.Time) bool {
 	return s.ExpiresAt != time.Time{} && now.After(s.ExpiresAt)
 }
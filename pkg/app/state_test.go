@@ -167,6 +167,117 @@ func TestStorePersist(t *testing.T) {
 	})
 }
 
+func TestStorePersistInSession(t *testing.T) {
+	d := NewClientTester(Div())
+	defer d.Close()
+
+	s := newStore(d)
+	defer s.Close()
+	key := "/test/store/persist-in-session"
+
+	t.Run("value is persisted in session storage", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 42, PersistInSession)
+		s.Get(key, &v)
+		require.Equal(t, 42, v)
+		require.Equal(t, 1, d.sessionStorage().Len())
+		require.Equal(t, 0, d.localStorage().Len())
+	})
+
+	t.Run("value is obtained from session storage", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 21, PersistInSession)
+		delete(s.states, key)
+		require.Empty(t, s.states)
+
+		s.Get(key, &v)
+		require.Equal(t, 21, v)
+	})
+
+	t.Run("value is observed from session storage", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 84, PersistInSession)
+		delete(s.states, key)
+
+		s.Observe(key, Div()).Value(&v)
+		require.Equal(t, 84, v)
+	})
+
+	t.Run("encrypted value is obtained from session storage", func(t *testing.T) {
+		var v string
+
+		s.Set(key, "hello", PersistInSession, Encrypt)
+		delete(s.states, key)
+
+		s.Get(key, &v)
+		require.Equal(t, "hello", v)
+	})
+
+	t.Run("value is deleted", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 1977, PersistInSession)
+		s.Del(key)
+
+		s.Get(key, &v)
+		require.Equal(t, 0, v)
+		require.Equal(t, 0, d.sessionStorage().Len())
+	})
+
+	t.Run("value is moved from local storage", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 7, Persist)
+		delete(s.states, key)
+		require.True(t, testStorageContains(d.localStorage(), key))
+
+		s.Set(key, 8, PersistInSession)
+		delete(s.states, key)
+		require.False(t, testStorageContains(d.localStorage(), key))
+		require.True(t, testStorageContains(d.sessionStorage(), key))
+
+		s.Get(key, &v)
+		require.Equal(t, 8, v)
+	})
+
+	t.Run("value is moved to local storage", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 9, PersistInSession)
+		delete(s.states, key)
+
+		s.Set(key, 10, Persist)
+		delete(s.states, key)
+		require.True(t, testStorageContains(d.localStorage(), key))
+		require.False(t, testStorageContains(d.sessionStorage(), key))
+
+		s.Get(key, &v)
+		require.Equal(t, 10, v)
+		s.Del(key)
+	})
+
+	t.Run("expired value is removed", func(t *testing.T) {
+		var v int
+
+		s.Set(key, 1986, PersistInSession, ExpiresIn(time.Millisecond))
+		time.Sleep(time.Millisecond * 2)
+		delete(s.states, key)
+
+		s.Get(key, &v)
+		require.Equal(t, 0, v)
+		require.Equal(t, 0, d.sessionStorage().Len())
+	})
+}
+
+func testStorageContains(s BrowserStorage, key string) bool {
+	var state persistentState
+	s.Get(key, &state)
+	return !state.isEmpty()
+}
+
 func TestStoreEncrypt(t *testing.T) {
 	d := NewClientTester(Div())
 	defer d.Close()
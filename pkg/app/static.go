@@ -34,10 +34,18 @@ func GenerateStaticWebsite(dir string, h *Handler, pages ...string) error {
 		"/web":                  {},
 	}
 
+	for _, l := range h.Locales {
+		resources[manifestPath(l)] = struct{}{}
+	}
+
 	for path := range routes.routes {
 		resources[path] = struct{}{}
 	}
 
+	for _, path := range h.localizedPaths(routes.paths()) {
+		resources[path] = struct{}{}
+	}
+
 	for _, p := range pages {
 		if p == "" {
 			continue
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"net/http"
+)
+
+const (
+	pageStreamChunkSize = 16 << 10
+)
+
+// pageStream writes a pre-rendered page while it is pre-rendered. The static
+// elements of the head are flushed before the pre-rendering and the body is
+// flushed by chunks once it is printed. The written bytes are kept in order to
+// cache the page once it is complete.
+type pageStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	page    bytes.Buffer
+	pending int
+}
+
+// newPageStream returns a stream that writes to the given response. It returns
+// nil when the response can't be flushed.
+func newPageStream(w http.ResponseWriter) *pageStream {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+
+	return &pageStream{
+		w:       w,
+		flusher: flusher,
+	}
+}
+
+func (s *pageStream) Write(b []byte) (int, error) {
+	s.page.Write(b)
+	n, err := s.w.Write(b)
+	s.pending += n
+	if s.pending >= pageStreamChunkSize {
+		s.flush()
+	}
+	return n, err
+}
+
+// start writes the beginning of the document and the elements of the head
+// that are known before the pre-rendering, then flushes them.
+func (s *pageStream) start(locale string, head []UI) {
+	s.w.Header().Set("Content-Type", "text/html")
+	s.w.WriteHeader(http.StatusOK)
+
+	s.Write([]byte("<!DOCTYPE html>\n"))
+	if locale != "" {
+		s.Write([]byte(`<html lang="` + html.EscapeString(locale) + `">`))
+	} else {
+		s.Write([]byte("<html>"))
+	}
+	s.Write([]byte("<head>"))
+	s.print(head...)
+	s.flush()
+}
+
+// end writes the remaining elements of the head and the pre-rendered body,
+// which is flushed by chunks.
+func (s *pageStream) end(head []UI, body UI) {
+	s.print(head...)
+	s.Write([]byte("</head>"))
+	s.flush()
+
+	s.print(body)
+	s.Write([]byte("</html>"))
+	s.flush()
+}
+
+// redirect ends the document with a redirection to the given URL. It is used
+// when a redirection is requested after the response headers are written.
+func (s *pageStream) redirect(url string) {
+	target, _ := json.Marshal(url)
+	s.end([]UI{
+		Meta().
+			HTTPEquiv("refresh").
+			Content("0;url=" + url),
+		Raw("<script>window.location.replace(" + string(target) + ");</script>"),
+	}, Body())
+}
+
+func (s *pageStream) print(uis ...UI) {
+	for _, ui := range FilterUIElems(uis...) {
+		PrintHTML(s, ui)
+		s.Write([]byte("\n"))
+	}
+}
+
+func (s *pageStream) flush() {
+	s.flusher.Flush()
+	s.pending = 0
+}
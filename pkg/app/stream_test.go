@@ -0,0 +1,190 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/stream-test", &streamTestCompo{})
+	Route("/stream-order-test", &streamOrderTestCompo{})
+}
+
+// streamTestRecorder is the response written when streamOrderTestCompo is
+// pre-rendered.
+var streamTestRecorder *flushRecorder
+
+type streamOrderTestCompo struct {
+	Compo
+
+	flushedHead string
+}
+
+func (c *streamOrderTestCompo) OnPreRender(ctx Context) {
+	if streamTestRecorder != nil && len(streamTestRecorder.flushes) != 0 {
+		c.flushedHead = streamTestRecorder.flushes[0]
+	}
+}
+
+func (c *streamOrderTestCompo) Render() UI {
+	return Div().
+		ID("stream-order").
+		DataSet("flushed-head", c.flushedHead != "")
+}
+
+type streamTestCompo struct {
+	Compo
+}
+
+func (c *streamTestCompo) Render() UI {
+	return Ul().Body(
+		Range(make([]struct{}, 2000)).Slice(func(i int) UI {
+			return Li().
+				ID("stream-item-" + strconv.Itoa(i)).
+				Text("streamed item")
+		}),
+	)
+}
+
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+func TestHandlerStreamPreRender(t *testing.T) {
+	serve := func(h *Handler, path string) *flushRecorder {
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("head is flushed before the page is pre-rendered", func(t *testing.T) {
+		h := &Handler{
+			Title:           "Streamed",
+			StreamPreRender: true,
+		}
+
+		w := serve(h, "/")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Header().Get("Content-Length"))
+		require.Equal(t, "text/html", w.Header().Get("Content-Type"))
+		require.Len(t, w.flushes, 3)
+
+		head := w.flushes[0]
+		require.True(t, strings.HasPrefix(head, "<!DOCTYPE html>\n<html><head>"))
+		require.Contains(t, head, `src="/app.js"`)
+		require.Contains(t, head, `href="/app.css"`)
+		require.NotContains(t, head, "</head>")
+		require.NotContains(t, head, "<title>")
+		require.NotContains(t, head, `id="pre-render-ok"`)
+
+		require.True(t, strings.HasSuffix(w.flushes[1], "</head>"))
+		require.Contains(t, w.flushes[1], "<title>\nStreamed\n</title>")
+
+		page := w.Body.String()
+		require.True(t, strings.HasPrefix(page, head))
+		require.Contains(t, page, `id="pre-render-ok"`)
+		require.True(t, strings.HasSuffix(page, "</body>\n</html>"))
+	})
+
+	t.Run("head is flushed before the prerender runs", func(t *testing.T) {
+		h := &Handler{StreamPreRender: true}
+
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		streamTestRecorder = w
+		defer func() {
+			streamTestRecorder = nil
+		}()
+
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream-order-test", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `data-flushed-head="true"`)
+	})
+
+	t.Run("body is flushed by chunks", func(t *testing.T) {
+		h := &Handler{StreamPreRender: true}
+
+		w := serve(h, "/stream-test")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Greater(t, len(w.flushes), 3)
+		for i := 1; i < len(w.flushes)-1; i++ {
+			if i == 1 {
+				continue
+			}
+			require.GreaterOrEqual(t, len(w.flushes[i])-len(w.flushes[i-1]), pageStreamChunkSize)
+		}
+		require.Equal(t, w.Body.String(), w.flushes[len(w.flushes)-1])
+		require.Contains(t, w.Body.String(), `id="stream-item-1999"`)
+	})
+
+	t.Run("streamed page is cached", func(t *testing.T) {
+		h := &Handler{StreamPreRender: true}
+
+		serve(h, "/")
+		w := serve(h, "/")
+		require.Empty(t, w.flushes)
+		require.NotEmpty(t, w.Header().Get("Content-Length"))
+		require.Contains(t, w.Body.String(), `id="pre-render-ok"`)
+	})
+
+	t.Run("redirect is done by the browser", func(t *testing.T) {
+		h := &Handler{StreamPreRender: true}
+
+		w := serve(h, "/redirect-test")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `content="0;url=/login"`)
+		require.Contains(t, w.Body.String(), `window.location.replace("/login")`)
+		require.NotContains(t, w.Body.String(), "account")
+
+		_, cached := h.PreRenderCache.Get(context.Background(), "/redirect-test")
+		require.False(t, cached)
+	})
+
+	t.Run("experiment cookies are set by a script", func(t *testing.T) {
+		defer SetFeatureFlags(FeatureFlags{})
+		SetFeatureFlags(FeatureFlags{
+			Experiments: map[string]Experiment{
+				"experiment-test": {Variants: []ExperimentVariant{{Name: "blue"}}},
+			},
+		})
+		h := &Handler{StreamPreRender: true}
+
+		w := serve(h, "/experiment-test")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, responseCookies(w.ResponseRecorder))
+		require.Contains(t, w.Body.String(), `document.cookie="`+experimentsCookieName+`=experiment-test=blue;`)
+	})
+
+	t.Run("page with session is not streamed", func(t *testing.T) {
+		h := &Handler{
+			StreamPreRender: true,
+			Sessions: Sessions{
+				Store: NewMemorySessionStore(),
+			},
+		}
+
+		w := serve(h, "/session-test")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.flushes)
+		require.Len(t, responseCookies(w.ResponseRecorder), 1)
+	})
+
+	t.Run("page is not streamed by default", func(t *testing.T) {
+		w := serve(&Handler{}, "/")
+		require.Empty(t, w.flushes)
+		require.NotEmpty(t, w.Header().Get("Content-Length"))
+	})
+}
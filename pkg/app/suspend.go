@@ -0,0 +1,60 @@
+package app
+
+import (
+	"sync/atomic"
+)
+
+// SuspendPolicy represents a policy that decides whether the engine suspends
+// its work while the page is hidden.
+type SuspendPolicy int
+
+const (
+	// SuspendWhenHidden suspends component updates and the functions
+	// scheduled with Context.Every while the page is hidden, which is the case
+	// when its tab is in background or the browser is minimized. Pending
+	// updates are performed when the page becomes visible again.
+	//
+	// Dispatched functions and actions are still executed while the page is
+	// hidden.
+	SuspendWhenHidden SuspendPolicy = iota
+
+	// NeverSuspend keeps performing component updates at full rate while the
+	// page is hidden.
+	NeverSuspend
+)
+
+var (
+	engineSuspendPolicy = SuspendWhenHidden
+)
+
+// SetSuspendPolicy sets the policy that decides whether the engine suspends
+// its work while the page is hidden. It must be called before
+// RunWhenOnBrowser.
+//
+// Default: SuspendWhenHidden.
+func SetSuspendPolicy(p SuspendPolicy) {
+	engineSuspendPolicy = p
+}
+
+func onVisibilityChange(e *engine) EventHandler {
+	return func(ctx Context, ev Event) {
+		hidden := Window().Get("document").Get("hidden").Bool()
+		e.setSuspended(hidden)
+	}
+}
+
+func (e *engine) setSuspended(v bool) {
+	if e.SuspendPolicy == NeverSuspend {
+		return
+	}
+
+	var suspended int32
+	if v {
+		suspended = 1
+	}
+	atomic.StoreInt32(&e.isSuspended, suspended)
+}
+
+func (e *engine) suspended() bool {
+	return atomic.LoadInt32(&e.isSuspended) == 1
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type everyCompo struct {
+	Compo
+
+	count int32
+}
+
+func (c *everyCompo) OnMount(ctx Context) {
+	ctx.Every(time.Millisecond, func(Context) {
+		atomic.AddInt32(&c.count, 1)
+	})
+}
+
+func (c *everyCompo) Render() UI {
+	return Div()
+}
+
+func TestEngineSuspend(t *testing.T) {
+	t.Run("suspend when hidden", func(t *testing.T) {
+		e := engine{}
+		e.init()
+		defer e.Close()
+
+		e.setSuspended(true)
+		require.True(t, e.suspended())
+
+		e.setSuspended(false)
+		require.False(t, e.suspended())
+	})
+
+	t.Run("never suspend", func(t *testing.T) {
+		e := engine{SuspendPolicy: NeverSuspend}
+		e.init()
+		defer e.Close()
+
+		e.setSuspended(true)
+		require.False(t, e.suspended())
+	})
+}
+
+func TestContextEvery(t *testing.T) {
+	compo := &everyCompo{}
+	e := engine{}
+	e.init()
+	defer e.Close()
+	e.Mount(compo)
+	e.Consume()
+
+	time.Sleep(time.Millisecond * 20)
+	e.Consume()
+	require.NotZero(t, atomic.LoadInt32(&compo.count))
+
+	e.setSuspended(true)
+	time.Sleep(time.Millisecond * 5)
+	e.Consume()
+	atomic.StoreInt32(&compo.count, 0)
+
+	time.Sleep(time.Millisecond * 20)
+	e.Consume()
+	require.Zero(t, atomic.LoadInt32(&compo.count))
+}
+
+func TestContextEveryServer(t *testing.T) {
+	compo := &everyCompo{}
+	d := NewServerTester(compo)
+	defer d.Close()
+
+	time.Sleep(time.Millisecond * 10)
+	d.Consume()
+	require.Zero(t, atomic.LoadInt32(&compo.count))
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Tenant describes an app served by a Handler for a set of host names. It
+// lets a single Handler serve multiple brands with their own manifest, icons,
+// colors and static resources.
+//
+// Fields left empty are inherited from the Handler. Styles, Scripts and
+// RawHeaders are added to the Handler ones and Env is merged with the Handler
+// environment.
+//
+// eg:
+//  app.Handler{
+//      Name: "Acme",
+//      Tenants: map[string]app.Tenant{
+//          "shop.example.com": {
+//              Name:       "Example Shop",
+//              ThemeColor: "#ff6600",
+//              Icon:       app.Icon{Default: "/web/logo.png"},
+//              Resources:  app.LocalDir("tenants/example"),
+//          },
+//          "*.acme.com": {
+//              Name:      "Acme",
+//              Resources: app.RemoteBucket("https://storage.acme.com/web"),
+//          },
+//      },
+//  },
+type Tenant struct {
+	// The page authors.
+	Author string
+
+	// A placeholder background color for the application page to display
+	// before its stylesheets are loaded.
+	BackgroundColor string
+
+	// The URL the sitemap paths and the pages rendered as PDF documents are
+	// relative to. The sitemap is not generated for the tenant when empty.
+	BaseURL string
+
+	// The page description.
+	Description string
+
+	// The environment variables that are passed to the progressive web app.
+	Env Environment
+
+	// The icon that is used for the PWA, favicon, loading and default not
+	// found component.
+	Icon Icon
+
+	// The path of the default image that is used by social networks when
+	// linking the app.
+	Image string
+
+	// The page keywords.
+	Keywords []string
+
+	// The text displayed while loading a page.
+	LoadingLabel string
+
+	// The text displayed when the app fails to load.
+	LoadingErrorLabel string
+
+	// The name of the web application as it is usually displayed to the user.
+	Name string
+
+	// The cache that stores the tenant pre-rendered pages.
+	//
+	// Default is a LRU cache that keeps pages up to 24h and have a maximum
+	// size of 8MB.
+	PreRenderCache PreRenderCache
+
+	// Additional headers to be added in head element.
+	RawHeaders []string
+
+	// The resource provider that provides the tenant static resources.
+	Resources ResourceProvider
+
+	// The paths or urls of the JavaScript files to use with the page.
+	Scripts []string
+
+	// The name of the web application displayed to the user when there is
+	// not enough space to display Name.
+	ShortName string
+
+	// The paths or urls of the CSS files to use with the page.
+	Styles []string
+
+	// The theme color for the application.
+	ThemeColor string
+
+	// The page title.
+	Title string
+}
+
+func (h *Handler) initTenants() {
+	if len(h.Tenants) == 0 {
+		return
+	}
+
+	h.tenants = make(map[string]*Handler, len(h.Tenants))
+	for host, t := range h.Tenants {
+		h.tenants[strings.ToLower(host)] = h.newTenantHandler(t)
+	}
+}
+
+// newTenantHandler creates the handler that serves the given tenant. It must be
+// called before the Handler fields are resolved. Exported Handler fields must
+// be copied here, which is enforced by TestNewTenantHandlerInheritsFields.
+func (h *Handler) newTenantHandler(t Tenant) *Handler {
+	th := &Handler{
+		Author:               h.Author,
+		BackgroundColor:      h.BackgroundColor,
+		CacheableResources:   copyStrings(h.CacheableResources),
+		Claims:               h.Claims,
+		ClientReports:        h.ClientReports,
+		ClientTimeZone:       h.ClientTimeZone,
+		CrawlerRendering:     h.CrawlerRendering,
+		CriticalCSS:          h.CriticalCSS,
+		Description:          h.Description,
+		Env:                  make(Environment, len(h.Env)+len(t.Env)),
+		FileHandlers:         h.FileHandlers,
+		FingerprintResources: h.FingerprintResources,
+		Fonts:                append([]Font(nil), h.Fonts...),
+		HTMLMinifier:         h.HTMLMinifier,
+		Icon:                 h.Icon,
+		Image:                h.Image,
+		ImageEncoder:         h.ImageEncoder,
+		ImageWidths:          h.ImageWidths,
+		InternalURLs:         h.InternalURLs,
+		Keywords:             h.Keywords,
+		LaunchMode:           h.LaunchMode,
+		LoadingLabel:         h.LoadingLabel,
+		LoadingErrorLabel:    h.LoadingErrorLabel,
+		LoadingRetries:       h.LoadingRetries,
+		Locales:              h.Locales,
+		Maintenance:          h.Maintenance,
+		Name:                 h.Name,
+		PDFBaseURL:           h.PDFBaseURL,
+		PDFRenderer:          h.PDFRenderer,
+		ProtocolHandlers:     h.ProtocolHandlers,
+		PreRenderCache:       t.PreRenderCache,
+		PreRenderConcurrency: h.PreRenderConcurrency,
+		StreamPreRender:      h.StreamPreRender,
+		ProxyResources:       h.ProxyResources,
+		RawHeaders:           append(copyStrings(h.RawHeaders), t.RawHeaders...),
+		ResourceHints:        append([]ResourceHint(nil), h.ResourceHints...),
+		Robots:               h.Robots,
+		Scripts:              append(copyStrings(h.Scripts), t.Scripts...),
+		ShortName:            h.ShortName,
+		Shortcuts:            append([]Shortcut(nil), h.Shortcuts...),
+		Sessions:             h.Sessions,
+		Sitemap:              h.Sitemap,
+		Resources:            h.Resources,
+		Styles:               append(copyStrings(h.Styles), t.Styles...),
+		ThemeColor:           h.ThemeColor,
+		Title:                h.Title,
+		Tracer:               h.Tracer,
+		Version:              h.Version,
+		uploads:              h.uploads,
+	}
+
+	for k, v := range h.Env {
+		th.Env[k] = v
+	}
+	for k, v := range t.Env {
+		th.Env[k] = v
+	}
+
+	overrideString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	overrideString(&th.Author, t.Author)
+	overrideString(&th.BackgroundColor, t.BackgroundColor)
+	overrideString(&th.Description, t.Description)
+	overrideString(&th.Image, t.Image)
+	overrideString(&th.LoadingLabel, t.LoadingLabel)
+	overrideString(&th.LoadingErrorLabel, t.LoadingErrorLabel)
+	overrideString(&th.Name, t.Name)
+	overrideString(&th.ShortName, t.ShortName)
+	overrideString(&th.ThemeColor, t.ThemeColor)
+	overrideString(&th.Title, t.Title)
+
+	th.Sitemap.BaseURL = t.BaseURL
+	overrideString(&th.PDFBaseURL, t.BaseURL)
+	if t.Icon.Default != "" {
+		th.Icon = t.Icon
+	}
+	if len(t.Keywords) != 0 {
+		th.Keywords = t.Keywords
+	}
+	if t.Resources != nil {
+		th.Resources = t.Resources
+	}
+	return th
+}
+
+// tenant returns the handler that serves the tenant of the given request. It
+// returns nil when the request host does not belong to a tenant.
+func (h *Handler) tenant(r *http.Request) *Handler {
+	if len(h.tenants) == 0 {
+		return nil
+	}
+
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if t, ok := h.tenants[host]; ok {
+		return t
+	}
+	for i := strings.IndexByte(host, '.'); i >= 0; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if t, ok := h.tenants["*."+host]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
@@ -0,0 +1,218 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerTenants(t *testing.T) {
+	h := Handler{
+		Name:       "Base",
+		Title:      "Base title",
+		ThemeColor: "#000000",
+		Styles:     []string{"/web/app.css"},
+		Env:        Environment{"PLAN": "free"},
+		Sitemap:    Sitemap{BaseURL: "https://base.dev"},
+		Tenants: map[string]Tenant{
+			"shop.example.com": {
+				Name:       "Shop",
+				Title:      "Shop title",
+				ThemeColor: "#ff6600",
+				Icon:       Icon{Default: "/web/shop.png"},
+				Styles:     []string{"/web/shop.css"},
+				Env:        Environment{"TENANT": "shop"},
+				Resources:  RemoteBucket("https://storage.example.com/shop"),
+			},
+			"*.acme.com": {
+				Name:    "Acme",
+				Title:   "Acme title",
+				BaseURL: "https://acme.com",
+			},
+		},
+	}
+
+	serve := func(host, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Host = host
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	t.Run("manifest", func(t *testing.T) {
+		body := serve("shop.example.com", "/manifest.webmanifest").Body.String()
+		require.Contains(t, body, `"name": "Shop"`)
+		require.Contains(t, body, `"theme_color": "#ff6600"`)
+		require.Contains(t, body, `https://storage.example.com/shop/web/shop.png`)
+
+		body = serve("go-app.dev", "/manifest.webmanifest").Body.String()
+		require.Contains(t, body, `"name": "Base"`)
+		require.Contains(t, body, `"theme_color": "#000000"`)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		body := serve("SHOP.example.com:8000", "/").Body.String()
+		require.Contains(t, body, "<title>\nShop title\n</title>")
+		require.Contains(t, body, `href="https://storage.example.com/shop/web/app.css"`)
+		require.Contains(t, body, `href="https://storage.example.com/shop/web/shop.css"`)
+
+		body = serve("go-app.dev", "/").Body.String()
+		require.Contains(t, body, "<title>\nBase title\n</title>")
+		require.Contains(t, body, `href="/web/app.css"`)
+		require.NotContains(t, body, "shop.css")
+
+		body = serve("eu.acme.com", "/").Body.String()
+		require.Contains(t, body, "<title>\nAcme title\n</title>")
+	})
+
+	t.Run("app js env", func(t *testing.T) {
+		body := serve("shop.example.com", "/app.js").Body.String()
+		require.Contains(t, body, `"TENANT":"shop"`)
+		require.Contains(t, body, `"PLAN":"free"`)
+	})
+
+	t.Run("sitemap", func(t *testing.T) {
+		body := serve("eu.acme.com", "/sitemap.xml").Body.String()
+		require.Contains(t, body, "https://acme.com/")
+		require.NotContains(t, body, "https://base.dev")
+	})
+
+	t.Run("pdf base url", func(t *testing.T) {
+		require.Equal(t, "https://acme.com", h.tenants["*.acme.com"].PDFBaseURL)
+		require.Empty(t, h.tenants["shop.example.com"].PDFBaseURL)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		lookup := func(host string) *Handler {
+			return h.tenant(&http.Request{Host: host})
+		}
+
+		require.Equal(t, h.tenants["shop.example.com"], lookup("shop.example.com."))
+		require.Equal(t, h.tenants["*.acme.com"], lookup("a.b.acme.com"))
+		require.Nil(t, lookup("acme.com"))
+		require.Nil(t, lookup("example.com"))
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		require.NoError(t, h.Shutdown(context.Background()))
+		require.True(t, h.tenants["shop.example.com"].IsShuttingDown())
+	})
+}
+
+func TestNewTenantHandlerInheritsFields(t *testing.T) {
+	// The fields that tenant handlers don't inherit. Health endpoints, rate
+	// limits and request sizes are handled by the parent handler before a
+	// request is passed to a tenant, and uploads are served by the parent
+	// upload server. Tenants have their own pre-render cache and no tenants.
+	notInherited := map[string]bool{
+		"HealthEndpoints":    true,
+		"MaxRequestBodySize": true,
+		"PreRenderCache":     true,
+		"RateLimitKey":       true,
+		"RateLimiter":        true,
+		"Tenants":            true,
+		"Uploads":            true,
+	}
+
+	interfaces := map[reflect.Type]interface{}{
+		reflect.TypeOf((*HTMLMinifier)(nil)).Elem():     NewHTMLMinifier(),
+		reflect.TypeOf((*ImageEncoder)(nil)).Elem():     NewImageEncoder(85),
+		reflect.TypeOf((*PDFRenderer)(nil)).Elem():      &pdfTestRenderer{},
+		reflect.TypeOf((*PreRenderCache)(nil)).Elem():   NewPreRenderLRUCache(1<<10, time.Minute),
+		reflect.TypeOf((*RateLimiter)(nil)).Elem():      NewTokenBucketRateLimiter(1, 1),
+		reflect.TypeOf((*ResourceProvider)(nil)).Elem(): LocalDir("web"),
+		reflect.TypeOf((*Tracer)(nil)).Elem():           NewOTLPTracer("http://localhost/v1/traces", "test"),
+	}
+
+	var h Handler
+	hv := reflect.ValueOf(&h).Elem()
+	for i := 0; i < hv.NumField(); i++ {
+		f := hv.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Interface {
+			v, ok := interfaces[f.Type]
+			require.True(t, ok, "no test value for field %s of type %s", f.Name, f.Type)
+			hv.Field(i).Set(reflect.ValueOf(v))
+			continue
+		}
+		setTestNonZeroValue(hv.Field(i))
+		require.False(t, hv.Field(i).IsZero(), "no test value for field %s of type %s", f.Name, f.Type)
+	}
+
+	th := reflect.ValueOf(h.newTenantHandler(Tenant{})).Elem()
+	for i := 0; i < th.NumField(); i++ {
+		f := th.Type().Field(i)
+		if f.PkgPath != "" || notInherited[f.Name] {
+			continue
+		}
+		require.False(t, th.Field(i).IsZero(), "field %s is not inherited by tenant handlers", f.Name)
+	}
+}
+
+// setTestNonZeroValue sets the given value to a value that is not zero.
+// Interfaces and channels are left unset.
+func setTestNonZeroValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("test")
+
+	case reflect.Bool:
+		v.SetBool(true)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		setTestNonZeroValue(s.Index(0))
+		v.Set(s)
+
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		setTestNonZeroValue(key)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		setTestNonZeroValue(elem)
+		m := reflect.MakeMap(v.Type())
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+
+	case reflect.Func:
+		v.Set(reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+			out := make([]reflect.Value, v.Type().NumOut())
+			for i := range out {
+				out[i] = reflect.Zero(v.Type().Out(i))
+			}
+			return out
+		}))
+
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		setTestNonZeroValue(p.Elem())
+		v.Set(p)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				setTestNonZeroValue(v.Field(i))
+			}
+		}
+	}
+}
@@ -86,6 +86,27 @@ func (t *text) mount(d Dispatcher) error {
 	return nil
 }
 
+func (t *text) hydrate(d Dispatcher, v Value) error {
+	if t.Mounted() {
+		return errors.New("hydrating ui element failed").
+			Tag("reason", "already mounted").
+			Tag("kind", t.Kind()).
+			Tag("name", t.name()).
+			Tag("value", t.value)
+	}
+
+	if v.Get("nodeType").Int() != textNode {
+		return errHydrationMismatch(t, v)
+	}
+
+	t.disp = d
+	t.jsvalue = v
+	if v.Get("nodeValue").String() != t.value {
+		v.setNodeValue(t.value)
+	}
+	return nil
+}
+
 func (t *text) dismount() {
 	t.jsvalue = nil
 }
@@ -126,6 +147,18 @@ func (t *text) onAppInstallChange() {
 func (t *text) onResize() {
 }
 
+func (t *text) onVersionSkew() {
+}
+
+func (t *text) onAppInstalled() {
+}
+
+func (t *text) onDisplayModeChange() {
+}
+
+func (t *text) onAppLaunch(LaunchParams) {
+}
+
 func (t *text) preRender(Page) {
 }
 
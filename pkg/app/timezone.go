@@ -0,0 +1,93 @@
+package app
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	timeZoneCookieName = "goapp_timezone"
+	timeZoneCookieTTL  = time.Hour * 24 * 365
+)
+
+var (
+	timeZones sync.Map
+)
+
+// loadTimeZone returns the location with the given IANA time zone name, such
+// as "Europe/Paris". Locations are cached. It returns false when the name is
+// not a valid time zone or when the time zone database is not available.
+func loadTimeZone(name string) (*time.Location, bool) {
+	if name == "" || name == "Local" {
+		return nil, false
+	}
+	if loc, ok := timeZones.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	timeZones.Store(name, loc)
+	return loc, true
+}
+
+// requestTimeZone returns the time zone of the user that emits the given
+// request, which is echoed by the app in a cookie when ClientTimeZone is
+// enabled. It returns UTC when the time zone is unknown.
+func (h *Handler) requestTimeZone(r *http.Request) *time.Location {
+	if !h.ClientTimeZone {
+		return time.UTC
+	}
+
+	c, err := r.Cookie(timeZoneCookieName)
+	if err != nil {
+		return time.UTC
+	}
+	if loc, ok := loadTimeZone(c.Value); ok {
+		return loc
+	}
+	return time.UTC
+}
+
+// hasTimeZone reports whether the given request is emitted by a user whose
+// time zone is known.
+func (h *Handler) hasTimeZone(r *http.Request) bool {
+	return h.requestTimeZone(r) != time.UTC
+}
+
+// clientTimeZone returns the time zone of the browser, detected with
+// Intl.DateTimeFormat, and echoes it in a cookie for the next requests when
+// GOAPP_TIMEZONE_COOKIE is set.
+//
+// The returned location follows the daylight saving time rules of the time
+// zone when the time zone database is embedded in the app with the
+// time/tzdata package. Otherwise, it is the fixed offset of the browser.
+func clientTimeZone() *time.Location {
+	var name string
+	if intl := Window().Get("Intl"); intl.Truthy() {
+		name = jsOptionalString(intl.Get("DateTimeFormat").
+			New().
+			Call("resolvedOptions").
+			Get("timeZone"))
+	}
+
+	if name != "" && Getenv("GOAPP_TIMEZONE_COOKIE") != "" && documentCookie(timeZoneCookieName) != name {
+		c := &http.Cookie{
+			Name:     timeZoneCookieName,
+			Value:    name,
+			Path:     "/",
+			MaxAge:   int(timeZoneCookieTTL / time.Second),
+			Secure:   Window().URL().Scheme == "https",
+			SameSite: http.SameSiteLaxMode,
+		}
+		Window().Get("document").Set("cookie", c.String())
+	}
+
+	if loc, ok := loadTimeZone(name); ok {
+		return loc
+	}
+	return time.Local
+}
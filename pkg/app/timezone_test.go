@@ -0,0 +1,109 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/timezone-test", &timeZoneTestCompo{})
+}
+
+type timeZoneTestCompo struct {
+	Compo
+
+	date string
+}
+
+func (c *timeZoneTestCompo) OnPreRender(ctx Context) {
+	t := time.Date(2021, 7, 14, 20, 0, 0, 0, time.UTC)
+	c.date = ctx.TimeZone().String() + " " + t.In(ctx.TimeZone()).Format("15:04")
+}
+
+func (c *timeZoneTestCompo) Render() UI {
+	return Div().
+		ID("timezone-test").
+		Text(c.date)
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	loc, ok := loadTimeZone("Europe/Paris")
+	require.True(t, ok)
+	require.Equal(t, "Europe/Paris", loc.String())
+
+	cached, ok := loadTimeZone("Europe/Paris")
+	require.True(t, ok)
+	require.True(t, loc == cached)
+
+	_, ok = loadTimeZone("Mars/Olympus")
+	require.False(t, ok)
+
+	_, ok = loadTimeZone("Local")
+	require.False(t, ok)
+
+	_, ok = loadTimeZone("")
+	require.False(t, ok)
+}
+
+func TestHandlerServeWithClientTimeZone(t *testing.T) {
+	serve := func(h *Handler, timeZone string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/timezone-test", nil)
+		if timeZone != "" {
+			r.AddCookie(&http.Cookie{Name: timeZoneCookieName, Value: timeZone})
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("page is prerendered in the user time zone", func(t *testing.T) {
+		h := &Handler{ClientTimeZone: true}
+		w := serve(h, "Europe/Paris")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "Europe/Paris 22:00")
+		require.Equal(t, "true", h.Env["GOAPP_TIMEZONE_COOKIE"])
+	})
+
+	t.Run("invalid time zone is ignored", func(t *testing.T) {
+		h := &Handler{ClientTimeZone: true}
+		w := serve(h, "Mars/Olympus")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "UTC 20:00")
+	})
+
+	t.Run("time zone is ignored when disabled", func(t *testing.T) {
+		h := &Handler{}
+		w := serve(h, "Europe/Paris")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "UTC 20:00")
+		require.Empty(t, h.Env["GOAPP_TIMEZONE_COOKIE"])
+	})
+}
+
+func TestHandlerIsPersonalizedWithClientTimeZone(t *testing.T) {
+	h := &Handler{ClientTimeZone: true}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.False(t, h.isPersonalized(r))
+
+	r.AddCookie(&http.Cookie{Name: timeZoneCookieName, Value: "UTC"})
+	require.False(t, h.isPersonalized(r))
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: timeZoneCookieName, Value: "Asia/Tokyo"})
+	require.True(t, h.isPersonalized(r))
+}
+
+func TestContextTimeZone(t *testing.T) {
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	require.Equal(t, time.UTC, makeContext(compo).TimeZone())
+}
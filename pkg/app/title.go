@@ -0,0 +1,83 @@
+package app
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	titleTemplateMutex sync.RWMutex
+	titleTemplate      string
+)
+
+// SetTitleTemplate sets the template used to format the titles set with
+// RouteTitle. The "%s" verb is replaced by the route title.
+//
+// eg:
+//  app.SetTitleTemplate("%s — MyApp")
+func SetTitleTemplate(template string) {
+	titleTemplateMutex.Lock()
+	defer titleTemplateMutex.Unlock()
+
+	titleTemplate = template
+}
+
+// RouteTitle sets the title of the pages served by the route registered with
+// the given path or regular expression pattern.
+//
+// The title is formatted with the route title template or the one set with
+// SetTitleTemplate. In the title, "{path}" is replaced by the page path and
+// "{name}" by the value of the parameter "name" of the route path or of the
+// named group "name" of the route regular expression.
+//
+// The title is applied before the routed component is mounted, during
+// prerender and on navigation. Components can still override it with
+// Page().SetTitle.
+func RouteTitle(route, title string) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.title = title
+	})
+}
+
+// RouteTitleTemplate sets the template used to format the title of the pages
+// served by the route registered with the given path or regular expression
+// pattern. It overrides the template set with SetTitleTemplate.
+func RouteTitleTemplate(route, template string) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.titleTemplate = template
+	})
+}
+
+// RouteDescription sets the default description of the pages served by the
+// route registered with the given path or regular expression pattern.
+//
+// The description is applied before the routed component is mounted, during
+// prerender and on navigation.
+func RouteDescription(route, description string) {
+	routes.setMeta(route, func(m *routeMeta) {
+		m.description = description
+	})
+}
+
+func applyRouteMeta(p Page, path string) {
+	meta, groups := routes.meta(path)
+
+	if meta.title != "" {
+		template := meta.titleTemplate
+		if template == "" {
+			titleTemplateMutex.RLock()
+			template = titleTemplate
+			titleTemplateMutex.RUnlock()
+		}
+
+		title := expandRouteTemplate(meta.title, path, groups)
+		if template != "" {
+			title = strings.Replace(template, "%s", title, 1)
+		}
+		p.SetTitle(title)
+	}
+
+	if meta.description != "" {
+		p.SetDescription(expandRouteTemplate(meta.description, path, groups))
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	Route("/title-test", &routeCompo{})
+	RouteTitle("/title-test", "Hello")
+	RouteDescription("/title-test", "Hello page")
+
+	RouteWithRegexp("^/title-test/user/(?P<name>[^/]+)$", &routeWithRegexpCompo{})
+	RouteTitle("^/title-test/user/(?P<name>[^/]+)$", "User {name}")
+	RouteTitleTemplate("^/title-test/user/(?P<name>[^/]+)$", "%s | Users")
+}
+
+func TestApplyRouteMeta(t *testing.T) {
+	SetTitleTemplate("%s — go-app")
+	defer SetTitleTemplate("")
+
+	utests := []struct {
+		scenario    string
+		path        string
+		title       string
+		description string
+	}{
+		{
+			scenario:    "route without meta keeps page values",
+			path:        "/",
+			title:       "default",
+			description: "default description",
+		},
+		{
+			scenario:    "route title is formatted with the global template",
+			path:        "/title-test",
+			title:       "Hello — go-app",
+			description: "Hello page",
+		},
+		{
+			scenario:    "route title is formatted with the route template",
+			path:        "/title-test/user/maxence",
+			title:       "User maxence | Users",
+			description: "default description",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			page := requestPage{
+				title:       "default",
+				description: "default description",
+			}
+			applyRouteMeta(&page, u.path)
+			require.Equal(t, u.title, page.Title())
+			require.Equal(t, u.description, page.Description())
+		})
+	}
+}
+
+func TestHandlerServePageRouteTitle(t *testing.T) {
+	h := Handler{Title: "default"}
+
+	r := httptest.NewRequest(http.MethodGet, "/title-test", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Contains(t, body, "<title>\nHello\n</title>")
+	require.Equal(t, "Hello page", testMetaContent(body, "description"))
+}
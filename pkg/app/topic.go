@@ -0,0 +1,78 @@
+package app
+
+import (
+	"reflect"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Topic is a typed channel of messages propagated across the app. It is built
+// on top of actions: messages are published as actions named after the topic
+// and carrying the message as value.
+//
+// eg:
+//  var cartUpdates = app.NewTopic[Cart]("cart.updated")
+//
+//  func (c *cartBadge) OnMount(ctx app.Context) {
+//      cartUpdates.Subscribe(ctx, func(ctx app.Context, cart Cart) {
+//          c.count = len(cart.Items)
+//      })
+//  }
+//
+//  func (c *cartPage) onAdd(ctx app.Context, e app.Event) {
+//      c.cart.Items = append(c.cart.Items, c.item)
+//      cartUpdates.Publish(ctx, c.cart)
+//  }
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic creates a topic with the given name. It panics when the name is an
+// action pattern, which would subscribe to the actions of other topics.
+func NewTopic[T any](name string) Topic[T] {
+	if isActionPattern(name) {
+		panic(errors.New("creating topic failed").
+			Tag("name", name).
+			Tag("reason", "name is an action pattern"))
+	}
+	return Topic[T]{name: name}
+}
+
+// Name returns the name of the actions that carry the topic messages.
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Subscribe registers the given handler to be executed on the UI goroutine
+// when a message is published on the topic. The handler is bound to the
+// context's UI element and is unsubscribed when it is dismounted.
+func (t Topic[T]) Subscribe(ctx Context, h func(Context, T)) {
+	ctx.Handle(t.name, func(ctx Context, a Action) {
+		v, ok := t.message(a)
+		if !ok {
+			return
+		}
+		h(ctx, v)
+	})
+}
+
+// Publish sends the given message to the topic subscribers.
+func (t Topic[T]) Publish(ctx Context, v T) {
+	ctx.NewActionWithValue(t.name, v)
+}
+
+func (t Topic[T]) message(a Action) (T, bool) {
+	var v T
+	if a.Value == nil {
+		return v, true
+	}
+
+	v, ok := a.Value.(T)
+	if !ok {
+		Log(errors.New("receiving topic message failed").
+			Tag("topic", t.name).
+			Tag("expected-type", reflect.TypeOf(&v).Elem()).
+			Tag("type", reflect.TypeOf(a.Value)))
+	}
+	return v, ok
+}
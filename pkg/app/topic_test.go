@@ -0,0 +1,88 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type topicTestMsg struct {
+	Count int
+}
+
+func TestTopic(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	topic := NewTopic[topicTestMsg]("/topic-test")
+	require.Equal(t, "/topic-test", topic.Name())
+
+	var received []topicTestMsg
+	topic.Subscribe(makeContext(h), func(ctx Context, msg topicTestMsg) {
+		received = append(received, msg)
+	})
+
+	publish := func(fn func(ctx Context)) {
+		fn(makeContext(h))
+		e.Wait()
+		e.Consume()
+	}
+
+	t.Run("published messages are received", func(t *testing.T) {
+		publish(func(ctx Context) {
+			topic.Publish(ctx, topicTestMsg{Count: 42})
+		})
+		require.Equal(t, []topicTestMsg{{Count: 42}}, received)
+	})
+
+	t.Run("nil value is received as zero value", func(t *testing.T) {
+		publish(func(ctx Context) {
+			ctx.NewAction("/topic-test")
+		})
+		require.Equal(t, topicTestMsg{}, received[1])
+	})
+
+	t.Run("value with another type is ignored", func(t *testing.T) {
+		publish(func(ctx Context) {
+			ctx.NewActionWithValue("/topic-test", "hello")
+		})
+		require.Len(t, received, 2)
+	})
+
+	t.Run("subscription is removed on dismount", func(t *testing.T) {
+		e.Mount(Div())
+		e.Consume()
+
+		publish(func(ctx Context) {
+			topic.Publish(ctx, topicTestMsg{Count: 21})
+		})
+		require.Len(t, received, 2)
+		require.Empty(t, e.actions.handlers["/topic-test"])
+	})
+}
+
+func TestNewTopicWithPattern(t *testing.T) {
+	utests := []string{
+		"cart.*",
+		"cart.#",
+		"*",
+		"#.updated",
+	}
+
+	for _, u := range utests {
+		t.Run(u, func(t *testing.T) {
+			require.Panics(t, func() {
+				NewTopic[topicTestMsg](u)
+			})
+		})
+	}
+
+	require.NotPanics(t, func() {
+		NewTopic[topicTestMsg]("cart.updated*")
+	})
+}
@@ -0,0 +1,372 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	otlpBatchSize     = 64
+	otlpFlushInterval = time.Second * 5
+)
+
+var (
+	clientTracer Tracer
+)
+
+// Tracer is the interface that describes a tracer that records timed
+// operations (spans). It is used to instrument the Handler prerendering phases
+// and, optionally, the client engine dispatches and updates.
+//
+// It can be satisfied by an OpenTelemetry adapter or by the tracer returned by
+// NewOTLPTracer.
+type Tracer interface {
+	// Starts a span with the given name. The span is a child of the span that
+	// is carried by the given context, if any. The returned context carries the
+	// created span.
+	StartSpan(ctx context.Context, name string) (context.Context, TraceSpan)
+}
+
+// TraceSpan is the interface that describes a timed operation recorded by a
+// Tracer.
+type TraceSpan interface {
+	// Sets an attribute that describes the span.
+	SetAttribute(k string, v interface{})
+
+	// Records the given error and marks the span as failed.
+	RecordError(err error)
+
+	// Returns the W3C trace context header value (traceparent) that identifies
+	// the span. Returns an empty string when not supported.
+	TraceParent() string
+
+	// Ends the span.
+	End()
+}
+
+// SetClientTracer sets the tracer used to record client engine dispatches and
+// component updates. It must be called before RunWhenOnBrowser.
+//
+// When the page has been served by a Handler with a Tracer, client spans are
+// children of the span of the request that served the page.
+func SetClientTracer(t Tracer) {
+	clientTracer = t
+}
+
+// ContextWithTraceParent returns a copy of the given context that carries the
+// given W3C traceparent value. It is used to make spans created by the tracer
+// returned by NewOTLPTracer children of a remote span.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if _, _, ok := parseTraceParent(traceParent); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// TraceParentFromContext returns the W3C traceparent value carried by the given
+// context. It returns an empty string when there is none.
+func TraceParentFromContext(ctx context.Context) string {
+	if s, ok := ctx.Value(spanKey{}).(TraceSpan); ok {
+		return s.TraceParent()
+	}
+	tp, _ := ctx.Value(traceParentKey{}).(string)
+	return tp
+}
+
+func startSpan(t Tracer, ctx context.Context, name string) (context.Context, TraceSpan) {
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	return t.StartSpan(ctx, name)
+}
+
+type traceParentKey struct{}
+
+type spanKey struct{}
+
+type noopSpan struct{}
+
+func (s noopSpan) SetAttribute(k string, v interface{}) {
+}
+
+func (s noopSpan) RecordError(err error) {
+}
+
+func (s noopSpan) TraceParent() string {
+	return ""
+}
+
+func (s noopSpan) End() {
+}
+
+// NewOTLPTracer creates a tracer that exports spans to the given OpenTelemetry
+// collector endpoint by using the OTLP/HTTP JSON protocol. Spans are exported
+// by batches.
+//
+// The endpoint is the full URL of the traces receiver, usually ending with
+// "/v1/traces". It can be used both on server and client side.
+func NewOTLPTracer(endpoint, serviceName string) Tracer {
+	return &otlpTracer{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		client:      http.DefaultClient,
+	}
+}
+
+type otlpTracer struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+
+	mutex      sync.Mutex
+	spans      []otlpSpanData
+	flushTimer *time.Timer
+}
+
+func (t *otlpTracer) StartSpan(ctx context.Context, name string) (context.Context, TraceSpan) {
+	s := &otlpSpan{
+		tracer: t,
+		data: otlpSpanData{
+			Name:              name,
+			Kind:              1,
+			SpanID:            newTraceID(8),
+			StartTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		},
+	}
+
+	if traceID, parentID, ok := parseTraceParent(TraceParentFromContext(ctx)); ok {
+		s.data.TraceID = traceID
+		s.data.ParentSpanID = parentID
+	} else {
+		s.data.TraceID = newTraceID(16)
+	}
+
+	return context.WithValue(ctx, spanKey{}, TraceSpan(s)), s
+}
+
+func (t *otlpTracer) record(s otlpSpanData) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.spans = append(t.spans, s)
+	if len(t.spans) >= otlpBatchSize {
+		t.flush()
+		return
+	}
+
+	if t.flushTimer == nil {
+		t.flushTimer = time.AfterFunc(otlpFlushInterval, func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.flush()
+		})
+	}
+}
+
+func (t *otlpTracer) flush() {
+	if t.flushTimer != nil {
+		t.flushTimer.Stop()
+		t.flushTimer = nil
+	}
+	if len(t.spans) == 0 {
+		return
+	}
+
+	spans := t.spans
+	t.spans = nil
+	go t.export(spans)
+}
+
+func (t *otlpTracer) export(spans []otlpSpanData) {
+	if err := t.send(spans); err != nil {
+		Log(errors.New("exporting spans failed").
+			Tag("endpoint", t.endpoint).
+			Tag("spans-count", len(spans)).
+			Wrap(err))
+	}
+}
+
+func (t *otlpTracer) send(spans []otlpSpanData) error {
+	body, err := json.Marshal(t.payload(spans))
+	if err != nil {
+		return errors.New("encoding spans failed").Wrap(err)
+	}
+
+	res, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.New("posting spans failed").Wrap(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("posting spans failed").
+			Tag("status-code", res.StatusCode)
+	}
+	return nil
+}
+
+func (t *otlpTracer) payload(spans []otlpSpanData) otlpPayload {
+	return otlpPayload{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{
+						makeOTLPAttribute("service.name", t.serviceName),
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "go-app"},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
+
+type otlpSpan struct {
+	tracer *otlpTracer
+	once   sync.Once
+	mutex  sync.Mutex
+	data   otlpSpanData
+}
+
+func (s *otlpSpan) SetAttribute(k string, v interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Attributes = append(s.data.Attributes, makeOTLPAttribute(k, v))
+}
+
+func (s *otlpSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Status = &otlpStatus{
+		Code:    2,
+		Message: err.Error(),
+	}
+}
+
+func (s *otlpSpan) TraceParent() string {
+	return "00-" + s.data.TraceID + "-" + s.data.SpanID + "-01"
+}
+
+func (s *otlpSpan) End() {
+	s.once.Do(func() {
+		s.mutex.Lock()
+		s.data.EndTimeUnixNano = strconv.FormatInt(time.Now().UnixNano(), 10)
+		data := s.data
+		s.mutex.Unlock()
+
+		s.tracer.record(data)
+	})
+}
+
+type otlpPayload struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope      `json:"scope"`
+	Spans []otlpSpanData `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpanData struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string             `json:"key"`
+	Value otlpAttributeValue `json:"value"`
+}
+
+type otlpAttributeValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func makeOTLPAttribute(k string, v interface{}) otlpAttribute {
+	var value otlpAttributeValue
+
+	switch v := v.(type) {
+	case bool:
+		value.BoolValue = &v
+
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprint(v)
+		value.IntValue = &s
+
+	case float32:
+		f := float64(v)
+		value.DoubleValue = &f
+
+	case float64:
+		value.DoubleValue = &v
+
+	default:
+		s := toString(v)
+		value.StringValue = &s
+	}
+
+	return otlpAttribute{
+		Key:   k,
+		Value: value,
+	}
+}
+
+func newTraceID(size int) string {
+	b := make([]byte, size)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func parseTraceParent(v string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(v, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
@@ -0,0 +1,142 @@
+//go:build !wasm
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testTracer struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string) (context.Context, TraceSpan) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.names = append(t.names, name)
+	return ctx, noopSpan{}
+}
+
+func (t *testTracer) spanNames() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]string(nil), t.names...)
+}
+
+func TestHandlerTracing(t *testing.T) {
+	tracer := &testTracer{}
+	h := Handler{Tracer: tracer}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	names := tracer.spanNames()
+	require.Contains(t, names, "http.request")
+	require.Contains(t, names, "prerender")
+	require.Contains(t, names, "prerender.mount")
+	require.Contains(t, names, "prerender.dispatch")
+	require.Contains(t, names, "prerender.html")
+	require.Contains(t, names, "engine.dispatch")
+}
+
+func TestHandlerTracingServerTiming(t *testing.T) {
+	h := Handler{Tracer: NewOTLPTracer("http://localhost:0/v1/traces", "test")}
+
+	r := httptest.NewRequest(http.MethodGet, "/app.css", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Regexp(t, `^traceparent;desc="00-[0-9a-f]{32}-[0-9a-f]{16}-01"$`, w.Header().Get("Server-Timing"))
+}
+
+func TestEngineTracing(t *testing.T) {
+	tracer := &testTracer{}
+	e := engine{Tracer: tracer}
+	e.init()
+	defer e.Close()
+
+	e.Mount(&foo{})
+	e.Consume()
+
+	names := tracer.spanNames()
+	require.Contains(t, names, "engine.dispatch")
+	require.Contains(t, names, "engine.update")
+}
+
+func TestOTLPTracer(t *testing.T) {
+	payloads := make(chan otlpPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p otlpPayload
+		err := json.NewDecoder(r.Body).Decode(&p)
+		require.NoError(t, err)
+		payloads <- p
+	}))
+	defer server.Close()
+
+	tracer := NewOTLPTracer(server.URL, "test-service").(*otlpTracer)
+
+	parentTraceID := "0af7651916cd43dd8448eb211c80319c"
+	parentSpanID := "b7ad6b7169203331"
+	ctx := ContextWithTraceParent(context.Background(), "00-"+parentTraceID+"-"+parentSpanID+"-01")
+
+	ctx, parent := tracer.StartSpan(ctx, "parent")
+	parent.SetAttribute("string", "hello")
+	parent.SetAttribute("int", 42)
+
+	_, child := tracer.StartSpan(ctx, "child")
+	child.RecordError(errors.New("test"))
+	child.End()
+	parent.End()
+
+	tracer.mutex.Lock()
+	tracer.flush()
+	tracer.mutex.Unlock()
+
+	p := <-payloads
+	require.Len(t, p.ResourceSpans, 1)
+	require.Equal(t, "service.name", p.ResourceSpans[0].Resource.Attributes[0].Key)
+	require.Equal(t, "test-service", *p.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+
+	spans := p.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	childData := spans[0]
+	parentData := spans[1]
+	require.Equal(t, "parent", parentData.Name)
+	require.Equal(t, parentTraceID, parentData.TraceID)
+	require.Equal(t, parentSpanID, parentData.ParentSpanID)
+	require.Len(t, parentData.Attributes, 2)
+	require.Equal(t, "hello", *parentData.Attributes[0].Value.StringValue)
+	require.Equal(t, "42", *parentData.Attributes[1].Value.IntValue)
+
+	require.Equal(t, "child", childData.Name)
+	require.Equal(t, parentTraceID, childData.TraceID)
+	require.Equal(t, parentData.SpanID, childData.ParentSpanID)
+	require.Equal(t, 2, childData.Status.Code)
+}
+
+func TestContextWithTraceParent(t *testing.T) {
+	t.Run("valid traceparent", func(t *testing.T) {
+		tp := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+		ctx := ContextWithTraceParent(context.Background(), tp)
+		require.Equal(t, tp, TraceParentFromContext(ctx))
+	})
+
+	t.Run("invalid traceparent", func(t *testing.T) {
+		ctx := ContextWithTraceParent(context.Background(), "hello")
+		require.Empty(t, TraceParentFromContext(ctx))
+	})
+}
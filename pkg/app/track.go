@@ -0,0 +1,36 @@
+package app
+
+import (
+	"net/url"
+)
+
+const (
+	// The name of the action posted after each navigation, once the navigated
+	// page is updated. The action value is the navigated *url.URL.
+	NavAction = "/app/nav"
+
+	// The name of the action posted with Context.Track. The action value is a
+	// TrackEvent.
+	TrackAction = "/app/track"
+)
+
+// TrackEvent represents an event recorded with Context.Track.
+type TrackEvent struct {
+	// The event name.
+	Name string
+
+	// The properties that describe the event. Can be nil.
+	Properties map[string]interface{}
+}
+
+func postNavAction(d Dispatcher, u *url.URL) {
+	d.Dispatch(Dispatch{
+		Mode: Defer,
+		Function: func(ctx Context) {
+			ctx.Dispatcher().Post(Action{
+				Name:  NavAction,
+				Value: u,
+			})
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextTrack(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var event TrackEvent
+	e.Handle(TrackAction, h, func(ctx Context, a Action) {
+		event = a.Value.(TrackEvent)
+	})
+
+	makeContext(h).Track("signup", map[string]interface{}{"plan": "pro"})
+	e.Wait()
+	e.Consume()
+	require.Equal(t, TrackEvent{
+		Name:       "signup",
+		Properties: map[string]interface{}{"plan": "pro"},
+	}, event)
+}
+
+func TestPostNavAction(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	h := &hello{}
+	e.Mount(h)
+	e.Consume()
+
+	var navigated *url.URL
+	e.Handle(NavAction, h, func(ctx Context, a Action) {
+		navigated = a.Value.(*url.URL)
+	})
+
+	u, _ := url.Parse("/hello")
+	postNavAction(&e, u)
+	e.Consume()
+	e.Wait()
+	e.Consume()
+	require.Equal(t, u, navigated)
+}
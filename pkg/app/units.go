@@ -0,0 +1,105 @@
+package app
+
+import (
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+type measurementUnit struct {
+	dimension string
+	factor    float64
+	symbol    string
+}
+
+// The measurement units supported by ConvertUnit, named as the sanctioned
+// units of Intl.NumberFormat. Factors convert a value to the base unit of the
+// dimension.
+var measurementUnits = map[string]measurementUnit{
+	"millimeter": {dimension: "length", factor: 0.001, symbol: "mm"},
+	"centimeter": {dimension: "length", factor: 0.01, symbol: "cm"},
+	"meter":      {dimension: "length", factor: 1, symbol: "m"},
+	"kilometer":  {dimension: "length", factor: 1000, symbol: "km"},
+	"inch":       {dimension: "length", factor: 0.0254, symbol: "in"},
+	"foot":       {dimension: "length", factor: 0.3048, symbol: "ft"},
+	"yard":       {dimension: "length", factor: 0.9144, symbol: "yd"},
+	"mile":       {dimension: "length", factor: 1609.344, symbol: "mi"},
+
+	"gram":     {dimension: "mass", factor: 0.001, symbol: "g"},
+	"kilogram": {dimension: "mass", factor: 1, symbol: "kg"},
+	"ounce":    {dimension: "mass", factor: 0.028349523125, symbol: "oz"},
+	"pound":    {dimension: "mass", factor: 0.45359237, symbol: "lb"},
+	"stone":    {dimension: "mass", factor: 6.35029318, symbol: "st"},
+
+	"milliliter":  {dimension: "volume", factor: 0.001, symbol: "mL"},
+	"liter":       {dimension: "volume", factor: 1, symbol: "L"},
+	"fluid-ounce": {dimension: "volume", factor: 0.0295735295625, symbol: "fl oz"},
+	"gallon":      {dimension: "volume", factor: 3.785411784, symbol: "gal"},
+
+	"meter-per-second":   {dimension: "speed", factor: 1, symbol: "m/s"},
+	"kilometer-per-hour": {dimension: "speed", factor: 1 / 3.6, symbol: "km/h"},
+	"mile-per-hour":      {dimension: "speed", factor: 0.44704, symbol: "mph"},
+
+	"celsius":    {dimension: "temperature", symbol: "°C"},
+	"fahrenheit": {dimension: "temperature", symbol: "°F"},
+
+	"bit":      {dimension: "digital", factor: 0.125, symbol: "bit"},
+	"byte":     {dimension: "digital", factor: 1, symbol: "byte"},
+	"kilobyte": {dimension: "digital", factor: 1e3, symbol: "kB"},
+	"megabyte": {dimension: "digital", factor: 1e6, symbol: "MB"},
+	"gigabyte": {dimension: "digital", factor: 1e9, symbol: "GB"},
+	"terabyte": {dimension: "digital", factor: 1e12, symbol: "TB"},
+
+	"millisecond": {dimension: "duration", factor: 0.001, symbol: "ms"},
+	"second":      {dimension: "duration", factor: 1, symbol: "sec"},
+	"minute":      {dimension: "duration", factor: 60, symbol: "min"},
+	"hour":        {dimension: "duration", factor: 3600, symbol: "hr"},
+	"day":         {dimension: "duration", factor: 86400, symbol: "day"},
+	"week":        {dimension: "duration", factor: 604800, symbol: "wk"},
+}
+
+// ConvertUnit converts the given value from a measurement unit to another one
+// of the same dimension. Units are named as the Intl.NumberFormat units, such
+// as "kilometer", "mile-per-hour", "celsius" or "gigabyte". Eg:
+//  miles, err := app.ConvertUnit(42.195, "kilometer", "mile")
+func ConvertUnit(v float64, from, to string) (float64, error) {
+	f, fromOK := measurementUnits[from]
+	t, toOK := measurementUnits[to]
+	if !fromOK || !toOK || f.dimension != t.dimension {
+		return 0, errors.New("converting unit failed").
+			Tag("from", from).
+			Tag("to", to).
+			Tag("reason", "units are not convertible")
+	}
+
+	if f.dimension == "temperature" {
+		switch {
+		case from == to:
+			return v, nil
+
+		case from == "celsius":
+			return v*9/5 + 32, nil
+
+		default:
+			return (v - 32) * 5 / 9, nil
+		}
+	}
+	return v * f.factor / t.factor, nil
+}
+
+// formatUnit formats the given value with the given measurement unit. It uses
+// Intl.NumberFormat in the browser and the unit symbol on the server.
+func formatUnit(locale string, v float64, unit string) string {
+	if s, ok := formatIntl("NumberFormat", locale, map[string]interface{}{
+		"style": "unit",
+		"unit":  unit,
+	}, v); ok {
+		return s
+	}
+
+	symbol := unit
+	if u, ok := measurementUnits[unit]; ok {
+		symbol = u.symbol
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + symbol
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertUnit(t *testing.T) {
+	utests := []struct {
+		value    float64
+		from     string
+		to       string
+		expected float64
+	}{
+		{value: 42.195, from: "kilometer", to: "mile", expected: 26.218757},
+		{value: 1, from: "foot", to: "centimeter", expected: 30.48},
+		{value: 1, from: "pound", to: "gram", expected: 453.59237},
+		{value: 1, from: "gallon", to: "liter", expected: 3.785411784},
+		{value: 100, from: "kilometer-per-hour", to: "mile-per-hour", expected: 62.137119},
+		{value: 100, from: "celsius", to: "fahrenheit", expected: 212},
+		{value: 212, from: "fahrenheit", to: "celsius", expected: 100},
+		{value: 20, from: "celsius", to: "celsius", expected: 20},
+		{value: 1, from: "gigabyte", to: "megabyte", expected: 1000},
+		{value: 1.5, from: "hour", to: "minute", expected: 90},
+	}
+
+	for _, u := range utests {
+		t.Run(u.from+" to "+u.to, func(t *testing.T) {
+			v, err := ConvertUnit(u.value, u.from, u.to)
+			require.NoError(t, err)
+			require.InDelta(t, u.expected, v, 0.000001)
+		})
+	}
+
+	t.Run("different dimensions", func(t *testing.T) {
+		_, err := ConvertUnit(1, "kilometer", "kilogram")
+		require.Error(t, err)
+	})
+
+	t.Run("unknown unit", func(t *testing.T) {
+		_, err := ConvertUnit(1, "parsec", "meter")
+		require.Error(t, err)
+	})
+}
+
+func TestContextFormatUnit(t *testing.T) {
+	if !IsServer {
+		t.Skip()
+	}
+
+	compo := &hello{}
+	d := NewClientTester(compo)
+	defer d.Close()
+
+	ctx := makeContext(compo)
+	require.Equal(t, "12.5 km/h", ctx.FormatUnit(12.5, "kilometer-per-hour"))
+	require.Equal(t, "3 acre", ctx.FormatUnit(3, "acre"))
+}
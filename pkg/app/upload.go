@@ -0,0 +1,409 @@
+package app
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	uploadDefaultParallel   = 3
+	uploadDefaultMaxRetries = 5
+	uploadRetryDelay        = time.Second
+	uploadStorageKey        = "/go-app/uploads/"
+)
+
+// UploadOptions describes how a file is uploaded with Context.Upload.
+type UploadOptions struct {
+	// The URL of the endpoint that receives the file.
+	//
+	// Default: "/app-upload", the endpoint enabled with Handler.Uploads.
+	URL string
+
+	// The size in bytes of the chunks the file is split into. The upload
+	// endpoint rejects chunks larger than 64MB.
+	//
+	// Default: 4MB.
+	ChunkSize int64
+
+	// The number of chunks that are uploaded in parallel.
+	//
+	// Default: 3.
+	Parallel int
+
+	// The HTTP headers sent with the requests.
+	Header map[string]string
+
+	// The number of times the upload of a chunk is retried when it fails.
+	// Failures that happen while the browser is offline are not counted: the
+	// upload is resumed when the connection comes back.
+	//
+	// Default: 5.
+	MaxRetries int
+
+	// The function called on the UI goroutine when a chunk is uploaded.
+	OnProgress func(Context, UploadProgress)
+
+	// The function called on the UI goroutine when the upload is completed,
+	// with the ID of the upload on the server. The error is not nil when the
+	// upload failed or was canceled.
+	OnDone func(ctx Context, id string, err error)
+}
+
+// UploadProgress describes the progress of an upload.
+type UploadProgress struct {
+	// The name of the uploaded file.
+	Name string
+
+	// The number of bytes uploaded.
+	Loaded int64
+
+	// The size of the file in bytes.
+	Total int64
+}
+
+// Progress returns the progress of the upload, ranging from 0 to 1.
+func (p UploadProgress) Progress() float64 {
+	if p.Total <= 0 {
+		return 1
+	}
+	return float64(p.Loaded) / float64(p.Total)
+}
+
+// fileUpload uploads a JavaScript File chunk by chunk. The ID of the upload is
+// remembered in local storage so that uploading the same file later resumes
+// where it stopped.
+type fileUpload struct {
+	ctx  Context
+	file Value
+	opts UploadOptions
+
+	id         string
+	storageKey string
+	controller Value
+	chunkSize  int64
+	pending    []int
+	inflight   int
+	failures   map[int]int
+	progress   UploadProgress
+	done       bool
+	finished   chan struct{}
+}
+
+func upload(ctx Context, file Value, opts UploadOptions) {
+	if IsServer {
+		return
+	}
+
+	if opts.URL == "" {
+		opts.URL = uploadEndpoint
+	}
+	opts.URL = strings.TrimSuffix(opts.URL, "/")
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = uploadDefaultChunkSize
+	}
+	if opts.Parallel <= 0 {
+		opts.Parallel = uploadDefaultParallel
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = uploadDefaultMaxRetries
+	}
+
+	u := &fileUpload{
+		ctx:      ctx,
+		file:     file,
+		opts:     opts,
+		failures: make(map[int]int),
+		progress: UploadProgress{
+			Name:  file.Get("name").String(),
+			Total: int64(file.Get("size").Float()),
+		},
+		finished: make(chan struct{}),
+	}
+	u.storageKey = uploadStorageKey + opts.URL + "/" + u.progress.Name + "/" +
+		strconv.FormatInt(u.progress.Total, 10) + "/" +
+		strconv.FormatInt(int64(file.Get("lastModified").Float()), 10)
+
+	if !Window().Get("fetch").Truthy() || !Window().Get("AbortController").Truthy() {
+		u.finish(errors.New("uploading file failed").
+			Tag("name", u.progress.Name).
+			Tag("reason", "fetch is not supported"))
+		return
+	}
+	u.controller = Window().Get("AbortController").New()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			u.controller.Call("abort")
+
+		case <-u.finished:
+		}
+	}()
+
+	ctx.LocalStorage().Get(u.storageKey, &u.id)
+	if u.id != "" {
+		u.resume()
+		return
+	}
+	u.create()
+}
+
+func (u *fileUpload) create() {
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":      u.progress.Name,
+		"type":      u.file.Get("type").String(),
+		"size":      u.progress.Total,
+		"chunkSize": u.opts.ChunkSize,
+	})
+
+	u.fetch("POST", u.opts.URL, string(body), func(res Value) {
+		if !res.Get("ok").Bool() {
+			u.failStatus(res)
+			return
+		}
+
+		awaitPromise(res.Call("json"), func(status Value) {
+			u.id = status.Get("id").String()
+			u.ctx.LocalStorage().Set(u.storageKey, u.id)
+			u.start(status)
+		}, u.fail)
+	}, u.fail)
+}
+
+func (u *fileUpload) resume() {
+	u.fetch("GET", u.opts.URL+"/"+u.id, nil, func(res Value) {
+		if res.Get("status").Int() == 404 {
+			u.ctx.LocalStorage().Del(u.storageKey)
+			u.create()
+			return
+		}
+		if !res.Get("ok").Bool() {
+			u.failStatus(res)
+			return
+		}
+
+		awaitPromise(res.Call("json"), u.start, u.fail)
+	}, u.fail)
+}
+
+func (u *fileUpload) start(status Value) {
+	u.chunkSize = int64(status.Get("chunkSize").Float())
+	if u.chunkSize <= 0 {
+		u.finish(errors.New("uploading file failed").
+			Tag("name", u.progress.Name).
+			Tag("reason", "invalid chunk size"))
+		return
+	}
+
+	received := make(map[int]bool)
+	if r := status.Get("received"); r.Truthy() {
+		for i := 0; i < r.Length(); i++ {
+			idx := r.Index(i).Int()
+			received[idx] = true
+			u.progress.Loaded += u.chunkLen(idx)
+		}
+	}
+
+	chunks := int((u.progress.Total + u.chunkSize - 1) / u.chunkSize)
+	if chunks == 0 {
+		chunks = 1
+	}
+	for i := 0; i < chunks; i++ {
+		if !received[i] {
+			u.pending = append(u.pending, i)
+		}
+	}
+
+	u.notifyProgress()
+	if len(u.pending) == 0 {
+		u.complete()
+		return
+	}
+	for i := 0; i < u.opts.Parallel && len(u.pending) != 0; i++ {
+		u.next()
+	}
+}
+
+func (u *fileUpload) next() {
+	if u.done {
+		return
+	}
+	if len(u.pending) == 0 {
+		if u.inflight == 0 {
+			u.complete()
+		}
+		return
+	}
+
+	chunk := u.pending[0]
+	u.pending = u.pending[1:]
+	u.inflight++
+
+	start := int64(chunk) * u.chunkSize
+	end := start + u.chunkLen(chunk)
+	body := u.file.Call("slice", start, end)
+	url := u.opts.URL + "/" + u.id + "?chunk=" + strconv.Itoa(chunk)
+
+	u.fetch("PUT", url, body, func(res Value) {
+		u.inflight--
+
+		switch status := res.Get("status").Int(); {
+		case res.Get("ok").Bool():
+			u.progress.Loaded += end - start
+			u.notifyProgress()
+			u.next()
+
+		case status >= 500 || status == 429:
+			u.retry(chunk, res)
+
+		default:
+			u.failStatus(res)
+		}
+	}, func(reason Value) {
+		u.inflight--
+		u.retry(chunk, reason)
+	})
+}
+
+// retry puts the given chunk back to the pending ones and uploads it after a
+// delay that increases with the number of failures. When the browser is
+// offline, the upload is resumed when it comes back online.
+func (u *fileUpload) retry(chunk int, reason Value) {
+	if u.done {
+		return
+	}
+	if u.controller.Get("signal").Get("aborted").Bool() {
+		u.fail(reason)
+		return
+	}
+
+	u.pending = append([]int{chunk}, u.pending...)
+
+	if !Window().Get("navigator").Get("onLine").Bool() {
+		var online Func
+		online = FuncOf(func(this Value, args []Value) interface{} {
+			Window().Call("removeEventListener", "online", online)
+			online.Release()
+			u.next()
+			return nil
+		})
+		Window().Call("addEventListener", "online", online)
+		return
+	}
+
+	u.failures[chunk]++
+	if u.failures[chunk] > u.opts.MaxRetries {
+		if reason != nil && reason.Get("status").Truthy() {
+			u.failStatus(reason)
+			return
+		}
+		u.fail(reason)
+		return
+	}
+
+	delay := uploadRetryDelay << uint(u.failures[chunk]-1)
+	Window().Call("setTimeout", funcOnce(u.next), delay.Milliseconds())
+}
+
+func (u *fileUpload) complete() {
+	if u.done {
+		return
+	}
+	u.ctx.LocalStorage().Del(u.storageKey)
+	u.finish(nil)
+}
+
+func (u *fileUpload) failStatus(res Value) {
+	u.finish(errors.New("uploading file failed").
+		Tag("name", u.progress.Name).
+		Tag("status", res.Get("status").Int()))
+}
+
+func (u *fileUpload) fail(reason Value) {
+	err := errors.New("uploading file failed").Tag("name", u.progress.Name)
+	if reason != nil && reason.Truthy() {
+		err = err.
+			Tag("error", reason.Get("name").String()).
+			Tag("reason", reason.Get("message").String())
+	}
+	u.finish(err)
+}
+
+func (u *fileUpload) finish(err error) {
+	if u.done {
+		return
+	}
+	u.done = true
+	close(u.finished)
+
+	id := u.id
+	if u.opts.OnDone == nil {
+		if err != nil {
+			Log(err)
+		}
+		return
+	}
+	u.ctx.Dispatch(func(ctx Context) {
+		u.opts.OnDone(ctx, id, err)
+	})
+}
+
+func (u *fileUpload) notifyProgress() {
+	if u.opts.OnProgress == nil {
+		return
+	}
+
+	progress := u.progress
+	u.ctx.Dispatch(func(ctx Context) {
+		u.opts.OnProgress(ctx, progress)
+	})
+}
+
+func (u *fileUpload) chunkLen(chunk int) int64 {
+	start := int64(chunk) * u.chunkSize
+	if end := start + u.chunkSize; end < u.progress.Total {
+		return u.chunkSize
+	}
+	if start > u.progress.Total {
+		return 0
+	}
+	return u.progress.Total - start
+}
+
+func (u *fileUpload) fetch(method, url string, body interface{}, onResponse, onError func(Value)) {
+	header := make(map[string]interface{}, len(u.opts.Header)+2)
+	if token := csrfToken(); token != "" {
+		header[CSRFHeader] = token
+	}
+	for k, v := range u.opts.Header {
+		header[k] = v
+	}
+	if method == "POST" {
+		header["Content-Type"] = "application/json"
+	}
+
+	init := map[string]interface{}{
+		"method":  method,
+		"headers": header,
+		"signal":  u.controller.Get("signal"),
+	}
+	if body != nil {
+		init["body"] = body
+	}
+	awaitPromise(Window().Call("fetch", url, init), onResponse, onError)
+}
+
+// funcOnce returns a JavaScript function that calls fn and releases itself.
+func funcOnce(fn func()) Func {
+	var f Func
+	f = FuncOf(func(this Value, args []Value) interface{} {
+		f.Release()
+		fn()
+		return nil
+	})
+	return f
+}
@@ -0,0 +1,214 @@
+//go:build !wasm
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploads(t *testing.T) {
+	dir := t.TempDir()
+
+	var completed UploadedFile
+	h := Handler{
+		Uploads: Uploads{
+			Dir:         dir,
+			MaxFileSize: 64,
+			OnComplete: func(r *http.Request, f UploadedFile) error {
+				completed = f
+				return nil
+			},
+		},
+	}
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, uploadStatus) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+		var status uploadStatus
+		json.Unmarshal(w.Body.Bytes(), &status)
+		return w, status
+	}
+
+	t.Run("too large file is rejected", func(t *testing.T) {
+		w, _ := do(http.MethodPost, uploadEndpoint, `{"name":"big.txt","size":65}`)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("too large chunk is rejected", func(t *testing.T) {
+		body := fmt.Sprintf(`{"name":"big.txt","size":64,"chunkSize":%d}`, uploadMaxChunkSize+1)
+		w, _ := do(http.MethodPost, uploadEndpoint, body)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("chunk longer than its size is rejected", func(t *testing.T) {
+		_, status := do(http.MethodPost, uploadEndpoint, `{"name":"a.txt","size":4,"chunkSize":2}`)
+
+		w, _ := do(http.MethodPut, uploadEndpoint+"/"+status.ID+"?chunk=0", "abc")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, uploadEndpoint+"/"+status.ID+"?chunk=1", ioutil.NopCloser(strings.NewReader("abc")))
+		r.ContentLength = -1
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		_, status = do(http.MethodGet, uploadEndpoint+"/"+status.ID, "")
+		require.Empty(t, status.Received)
+	})
+
+	t.Run("unknown upload is not found", func(t *testing.T) {
+		w, _ := do(http.MethodGet, uploadEndpoint+"/"+strings.Repeat("a", 32), "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+
+		w, _ = do(http.MethodGet, uploadEndpoint+"/../secret", "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("file is uploaded in chunks", func(t *testing.T) {
+		w, status := do(http.MethodPost, uploadEndpoint, `{"name":"../hello.txt","type":"text/plain","size":11,"chunkSize":4}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Len(t, status.ID, 32)
+		require.Equal(t, int64(4), status.ChunkSize)
+		require.Empty(t, status.Received)
+		id := status.ID
+
+		w, _ = do(http.MethodPut, uploadEndpoint+"/"+id+"?chunk=2", "rl")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w, status = do(http.MethodPut, uploadEndpoint+"/"+id+"?chunk=2", "rld")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, []int{2}, status.Received)
+
+		w, status = do(http.MethodPut, uploadEndpoint+"/"+id+"?chunk=0", "hell")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.False(t, status.Complete)
+
+		_, status = do(http.MethodGet, uploadEndpoint+"/"+id, "")
+		require.Equal(t, []int{0, 2}, status.Received)
+
+		w, status = do(http.MethodPut, uploadEndpoint+"/"+id+"?chunk=1", "o wo")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, status.Complete)
+
+		require.Equal(t, id, completed.ID)
+		require.Equal(t, "hello.txt", completed.Name)
+		require.Equal(t, "text/plain", completed.ContentType)
+		require.Equal(t, int64(11), completed.Size)
+		require.Equal(t, filepath.Join(dir, id), completed.Path)
+
+		content, err := ioutil.ReadFile(completed.Path)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+
+		w, _ = do(http.MethodGet, uploadEndpoint+"/"+id, "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("upload is deleted", func(t *testing.T) {
+		_, status := do(http.MethodPost, uploadEndpoint, `{"name":"bye.txt","size":3}`)
+
+		w, _ := do(http.MethodDelete, uploadEndpoint+"/"+status.ID, "")
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		_, err := os.Stat(filepath.Join(dir, status.ID))
+		require.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestUploadsLimits(t *testing.T) {
+	h := Handler{
+		Uploads: Uploads{
+			Dir: t.TempDir(),
+		},
+	}
+
+	create := func(body string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, uploadEndpoint, strings.NewReader(body)))
+		return w.Code
+	}
+
+	utests := []struct {
+		scenario string
+		body     string
+		status   int
+	}{
+		{
+			scenario: "file within the default size is accepted",
+			body:     `{"name":"a.txt","size":1024}`,
+			status:   http.StatusCreated,
+		},
+		{
+			scenario: "file larger than the default size is rejected",
+			body:     fmt.Sprintf(`{"name":"a.txt","size":%d}`, uploadDefaultMaxFileSize+1),
+			status:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "file with too many chunks is rejected",
+			body:     fmt.Sprintf(`{"name":"a.txt","size":%d,"chunkSize":1}`, uploadMaxChunks+1),
+			status:   http.StatusBadRequest,
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			require.Equal(t, u.status, create(u.body))
+		})
+	}
+}
+
+func TestUploadsAuthorize(t *testing.T) {
+	h := Handler{
+		Uploads: Uploads{
+			Dir: t.TempDir(),
+			Authorize: func(r *http.Request) error {
+				return errors.New("not signed in")
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, uploadEndpoint, strings.NewReader(`{"size":1}`)))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUploadOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	done := false
+	makeContext(h).Upload(Null(), UploadOptions{
+		OnDone: func(Context, string, error) { done = true },
+	})
+	d.Consume()
+	require.False(t, done)
+}
+
+func TestUploadProgress(t *testing.T) {
+	require.Equal(t, float64(1), UploadProgress{}.Progress())
+	require.Equal(t, 0.5, UploadProgress{Loaded: 2, Total: 4}.Progress())
+}
+
+func TestFileUploadChunkLen(t *testing.T) {
+	u := fileUpload{
+		chunkSize: 4,
+		progress:  UploadProgress{Total: 11},
+	}
+	require.Equal(t, int64(4), u.chunkLen(0))
+	require.Equal(t, int64(4), u.chunkLen(1))
+	require.Equal(t, int64(3), u.chunkLen(2))
+	require.Equal(t, int64(0), u.chunkLen(3))
+}
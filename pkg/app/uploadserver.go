@@ -0,0 +1,437 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	uploadEndpoint           = "/app-upload"
+	uploadDefaultChunkSize   = 4 << 20
+	uploadDefaultMaxFileSize = 1 << 30
+	uploadMaxChunkSize       = 64 << 20
+	uploadMaxChunks          = 1 << 16
+	uploadMaxMetaSize        = 1 << 16
+)
+
+var (
+	uploadIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+// Uploads describes the endpoint that receives the files uploaded with
+// Context.Upload. The endpoint is served at "/app-upload" and is disabled when
+// Dir is empty.
+//
+// eg:
+//  app.Handler{
+//      Uploads: app.Uploads{
+//          Dir:         "/var/lib/myapp/uploads",
+//          MaxFileSize: 4 << 30,
+//          OnComplete: func(r *http.Request, f app.UploadedFile) error {
+//              return os.Rename(f.Path, filepath.Join("/var/lib/myapp/files", f.Name))
+//          },
+//      },
+//  },
+type Uploads struct {
+	// The directory where uploaded files are written.
+	Dir string
+
+	// The maximum size of an uploaded file in bytes. Larger files are rejected
+	// with a 413 status code.
+	//
+	// Default: 1GB.
+	MaxFileSize int64
+
+	// The function called before an upload is started or resumed. Requests are
+	// rejected with a 403 status code when it returns an error.
+	Authorize func(*http.Request) error
+
+	// The function called when all the chunks of a file are received. The
+	// file is kept at the given path until it is moved or deleted.
+	OnComplete func(*http.Request, UploadedFile) error
+}
+
+// UploadedFile describes a file received by the upload endpoint.
+type UploadedFile struct {
+	// The ID of the upload.
+	ID string `json:"id"`
+
+	// The file name provided by the client.
+	Name string `json:"name"`
+
+	// The content type provided by the client.
+	ContentType string `json:"type,omitempty"`
+
+	// The size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// The path where the file is written.
+	Path string `json:"-"`
+}
+
+type uploadSession struct {
+	UploadedFile
+	ChunkSize int64  `json:"chunkSize"`
+	Received  []bool `json:"received"`
+}
+
+func (s uploadSession) chunks() int {
+	return len(s.Received)
+}
+
+func (s uploadSession) chunkLen(i int) int64 {
+	if i == s.chunks()-1 {
+		return s.Size - int64(i)*s.ChunkSize
+	}
+	return s.ChunkSize
+}
+
+func (s uploadSession) receivedChunks() []int {
+	received := make([]int, 0, len(s.Received))
+	for i, ok := range s.Received {
+		if ok {
+			received = append(received, i)
+		}
+	}
+	return received
+}
+
+func (s uploadSession) isComplete() bool {
+	for _, ok := range s.Received {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type uploadStatus struct {
+	ID        string `json:"id"`
+	ChunkSize int64  `json:"chunkSize"`
+	Received  []int  `json:"received"`
+	Complete  bool   `json:"complete"`
+}
+
+func (h *Handler) initUploads() {
+	if h.uploads == nil && h.Uploads.Dir != "" {
+		uploads := h.Uploads
+		if uploads.MaxFileSize <= 0 {
+			uploads.MaxFileSize = uploadDefaultMaxFileSize
+		}
+		h.uploads = &uploadServer{Uploads: uploads}
+	}
+}
+
+type uploadServer struct {
+	Uploads
+
+	mutex sync.Mutex
+}
+
+func (s *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Authorize != nil {
+		if err := s.Authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, uploadEndpoint), "/")
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, r)
+
+	case id == "":
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+	case !uploadIDPattern.MatchString(id):
+		w.WriteHeader(http.StatusNotFound)
+
+	case r.Method == http.MethodGet:
+		s.status(w, id)
+
+	case r.Method == http.MethodPut:
+		s.writeChunk(w, r, id)
+
+	case r.Method == http.MethodDelete:
+		s.delete(w, id)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *uploadServer) create(w http.ResponseWriter, r *http.Request) {
+	var session uploadSession
+	if err := json.NewDecoder(io.LimitReader(r.Body, uploadMaxMetaSize)).Decode(&session); err != nil {
+		http.Error(w, "invalid upload", http.StatusBadRequest)
+		return
+	}
+
+	if session.ChunkSize <= 0 {
+		session.ChunkSize = uploadDefaultChunkSize
+	}
+
+	switch {
+	case session.Size < 0:
+		http.Error(w, "invalid upload size", http.StatusBadRequest)
+		return
+
+	case session.Size > s.MaxFileSize:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+
+	case session.ChunkSize > uploadMaxChunkSize:
+		http.Error(w, "chunk size too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	chunks := (session.Size + session.ChunkSize - 1) / session.ChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+	if chunks > uploadMaxChunks {
+		http.Error(w, "too many chunks", http.StatusBadRequest)
+		return
+	}
+
+	id, err := newUploadID()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	session.ID = id
+	session.Name = filepath.Base(session.Name)
+	session.Received = make([]bool, chunks)
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		s.fail(w, errors.New("creating upload directory failed").
+			Tag("dir", s.Dir).
+			Wrap(err))
+		return
+	}
+
+	f, err := os.Create(s.dataPath(id))
+	if err != nil {
+		s.fail(w, errors.New("creating upload file failed").Wrap(err))
+		return
+	}
+	err = f.Truncate(session.Size)
+	f.Close()
+	if err != nil {
+		s.fail(w, errors.New("allocating upload file failed").Wrap(err))
+		return
+	}
+
+	s.mutex.Lock()
+	err = s.saveSession(session)
+	s.mutex.Unlock()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	s.writeStatus(w, session)
+}
+
+func (s *uploadServer) status(w http.ResponseWriter, id string) {
+	s.mutex.Lock()
+	session, err := s.loadSession(id)
+	s.mutex.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.writeStatus(w, session)
+}
+
+func (s *uploadServer) writeChunk(w http.ResponseWriter, r *http.Request, id string) {
+	s.mutex.Lock()
+	session, err := s.loadSession(id)
+	s.mutex.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	chunk, err := strconv.Atoi(r.URL.Query().Get("chunk"))
+	if err != nil || chunk < 0 || chunk >= session.chunks() {
+		http.Error(w, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	size := session.chunkLen(chunk)
+	if r.ContentLength > size {
+		http.Error(w, "invalid chunk size", http.StatusBadRequest)
+		return
+	}
+
+	f, err := os.OpenFile(s.dataPath(id), os.O_WRONLY, 0)
+	if err != nil {
+		s.fail(w, errors.New("opening upload file failed").Wrap(err))
+		return
+	}
+	n, err := s.copyChunk(f, r.Body, int64(chunk)*session.ChunkSize, size)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		s.fail(w, errors.New("writing upload chunk failed").Wrap(err))
+		return
+	}
+	if n != size {
+		http.Error(w, "invalid chunk size", http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	session, err = s.loadSession(id)
+	completed := false
+	if err == nil {
+		wasComplete := session.isComplete()
+		session.Received[chunk] = true
+		completed = !wasComplete && session.isComplete()
+		err = s.saveSession(session)
+	}
+	s.mutex.Unlock()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	if completed {
+		file := session.UploadedFile
+		file.Path = s.dataPath(id)
+		if s.OnComplete != nil {
+			if err := s.OnComplete(r, file); err != nil {
+				s.fail(w, errors.New("completing upload failed").
+					Tag("id", id).
+					Wrap(err))
+				return
+			}
+		}
+		os.Remove(s.sessionPath(id))
+	}
+	s.writeStatus(w, session)
+}
+
+// copyChunk copies at most size bytes from the given reader to the given file
+// at the given offset. It returns size + 1 when the reader has more than size
+// bytes, and less than size when it is shorter.
+func (s *uploadServer) copyChunk(f *os.File, r io.Reader, offset, size int64) (int64, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err := io.CopyN(f, r, size)
+	if err == io.EOF {
+		return n, nil
+	}
+	if err != nil {
+		return n, err
+	}
+
+	if extra, _ := io.ReadFull(r, make([]byte, 1)); extra > 0 {
+		return n + 1, nil
+	}
+	return n, nil
+}
+
+func (s *uploadServer) delete(w http.ResponseWriter, id string) {
+	s.mutex.Lock()
+	os.Remove(s.sessionPath(id))
+	os.Remove(s.dataPath(id))
+	s.mutex.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *uploadServer) writeStatus(w http.ResponseWriter, session uploadSession) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(uploadStatus{
+		ID:        session.ID,
+		ChunkSize: session.ChunkSize,
+		Received:  session.receivedChunks(),
+		Complete:  session.isComplete(),
+	})
+}
+
+func (s *uploadServer) fail(w http.ResponseWriter, err error) {
+	Log(err)
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func (s *uploadServer) loadSession(id string) (uploadSession, error) {
+	var session uploadSession
+
+	b, err := ioutil.ReadFile(s.sessionPath(id))
+	if err != nil {
+		return session, errors.New("reading upload session failed").
+			Tag("id", id).
+			Wrap(err)
+	}
+	if err := json.Unmarshal(b, &session); err != nil {
+		return session, errors.New("decoding upload session failed").
+			Tag("id", id).
+			Wrap(err)
+	}
+	return session, nil
+}
+
+func (s *uploadServer) saveSession(session uploadSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		return errors.New("encoding upload session failed").
+			Tag("id", session.ID).
+			Wrap(err)
+	}
+
+	tmp := s.sessionPath(session.ID) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return errors.New("writing upload session failed").
+			Tag("id", session.ID).
+			Wrap(err)
+	}
+	if err := os.Rename(tmp, s.sessionPath(session.ID)); err != nil {
+		return errors.New("writing upload session failed").
+			Tag("id", session.ID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (s *uploadServer) dataPath(id string) string {
+	return filepath.Join(s.Dir, id)
+}
+
+func (s *uploadServer) sessionPath(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+func newUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New("generating upload id failed").Wrap(err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	versionEndpoint      = "/app-version"
+	versionHeader        = "Go-App-Version"
+	versionCheckInterval = time.Minute
+)
+
+// BuildVersion is the version of the build. It is meant to be set at link time
+// with the same value for the server and the wasm binaries:
+//  go build -ldflags "-X github.com/maxence-charriere/go-app/v9/pkg/app.BuildVersion=v1.2.0"
+//
+// It is used as Handler.Version when the latter is not set. In the browser, it
+// is compared to the version of the server to detect version skews. The
+// version of the app.js file that loaded the app is used when empty.
+var BuildVersion string
+
+var (
+	serverVersion string
+)
+
+// VersionSkewHandler is the interface that describes a component that is
+// notified when the server serves a different version of the app than the one
+// that is running, which typically happens after a deployment when the app has
+// been open for a while.
+type VersionSkewHandler interface {
+	// The function called when a version skew is detected. Use
+	// Context.ServerVersion to get the version of the server. It is always
+	// called on the UI goroutine.
+	OnVersionSkew(Context)
+}
+
+// clientVersion returns the version of the running app.
+func clientVersion() string {
+	if BuildVersion != "" {
+		return BuildVersion
+	}
+	return Getenv("GOAPP_VERSION")
+}
+
+// checkVersion fetches the version of the server and triggers OnVersionSkew
+// when it differs from the client version. Checks are performed at most once
+// per versionCheckInterval.
+func (e *engine) checkVersion() {
+	if IsServer || e.RunsInServer {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(e.lastVersionCheck) < versionCheckInterval {
+		return
+	}
+	e.lastVersionCheck = now
+
+	url := rootPrefix + versionEndpoint
+	fail := func(err error) {
+		Log(errors.New("checking server version failed").
+			Tag("url", url).
+			Wrap(err))
+	}
+
+	awaitPromise(Window().Call("fetch", url, map[string]interface{}{
+		"cache":       "no-store",
+		"credentials": "same-origin",
+	}), func(res Value) {
+		if !res.Get("ok").Bool() {
+			fail(errors.New("unexpected status").Tag("status", res.Get("status").Int()))
+			return
+		}
+
+		awaitPromise(res.Call("json"), func(version Value) {
+			e.onServerVersion(version.Get("version").String())
+		}, func(reason Value) {
+			fail(jsReasonError(reason))
+		})
+	}, func(reason Value) {
+		fail(jsReasonError(reason))
+	})
+}
+
+// onServerVersion triggers OnVersionSkew from the root component when the given
+// server version differs from the client version and was not reported yet.
+func (e *engine) onServerVersion(v string) {
+	e.Dispatch(Dispatch{
+		Mode:   Update,
+		Source: e.Body,
+		Function: func(ctx Context) {
+			if v == "" || v == clientVersion() || v == serverVersion {
+				return
+			}
+
+			serverVersion = v
+			e.VersionSkew()
+		},
+	})
+}
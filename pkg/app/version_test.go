@@ -0,0 +1,100 @@
+//go:build !wasm
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type versionSkewCompo struct {
+	Compo
+
+	serverVersion string
+	skews         int
+}
+
+func (c *versionSkewCompo) OnVersionSkew(ctx Context) {
+	c.serverVersion = ctx.ServerVersion()
+	c.skews++
+}
+
+func TestHandlerServeAppVersion(t *testing.T) {
+	h := Handler{Version: "v2"}
+
+	r := httptest.NewRequest(http.MethodGet, versionEndpoint, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	require.Equal(t, "v2", w.Header().Get(versionHeader))
+
+	var v AppVersion
+	err := json.Unmarshal(w.Body.Bytes(), &v)
+	require.NoError(t, err)
+	require.Equal(t, "v2", v.Version)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, "v2", w.Header().Get(versionHeader))
+}
+
+func TestHandlerBuildVersion(t *testing.T) {
+	BuildVersion = "v1.2.0"
+	defer func() {
+		BuildVersion = ""
+	}()
+
+	h := Handler{}
+	h.initVersion()
+	require.Equal(t, "v1.2.0", h.Version)
+
+	h = Handler{Version: "v2"}
+	h.initVersion()
+	require.Equal(t, "v2", h.Version)
+}
+
+func TestEngineVersionSkew(t *testing.T) {
+	BuildVersion = "v1"
+	defer func() {
+		BuildVersion = ""
+		serverVersion = ""
+	}()
+
+	c := &versionSkewCompo{}
+	d := NewClientTester(c)
+	defer d.Close()
+	e := d.(*engine)
+
+	e.onServerVersion("v1")
+	e.Consume()
+	require.Zero(t, c.skews)
+
+	e.onServerVersion("")
+	e.Consume()
+	require.Zero(t, c.skews)
+
+	e.onServerVersion("v2")
+	e.Consume()
+	require.Equal(t, 1, c.skews)
+	require.Equal(t, "v2", c.serverVersion)
+
+	e.onServerVersion("v2")
+	e.Consume()
+	require.Equal(t, 1, c.skews)
+}
+
+func TestEngineCheckVersionOnServer(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	e.checkVersion()
+	require.True(t, e.lastVersionCheck.IsZero())
+}
@@ -0,0 +1,59 @@
+package app
+
+// SetViewTransitions enables or disables the animation of route changes with
+// the View Transition API.
+//
+// When enabled, navigating to another page captures the current page, mounts
+// the new page and animates between both with a cross-fade. Elements that
+// have the same "view-transition-name" CSS property on both pages are animated
+// from one to the other. eg:
+//  app.Img().
+//      Src("/web/cover.jpg").
+//      Style("view-transition-name", "cover")
+//
+// Pages are changed without animation when the browser does not support view
+// transitions or when the user prefers reduced motion.
+//
+// Default: false.
+func SetViewTransitions(enabled bool) {
+	viewTransitionsEnabled = enabled
+}
+
+var (
+	viewTransitionsEnabled bool
+)
+
+// startViewTransition executes the given function that changes the page
+// within a view transition. The transition completes once the updates that
+// result from the function are performed.
+func startViewTransition(d Dispatcher, update func()) {
+	doc := Window().Get("document")
+	if !viewTransitionsEnabled ||
+		!doc.Get("startViewTransition").Truthy() ||
+		prefersReducedMotion() {
+		update()
+		return
+	}
+
+	var callback Func
+	callback = FuncOf(func(this Value, args []Value) interface{} {
+		defer callback.Release()
+
+		var executor Func
+		executor = FuncOf(func(this Value, args []Value) interface{} {
+			defer executor.Release()
+
+			resolve := args[0]
+			update()
+			d.Dispatch(Dispatch{
+				Mode: Defer,
+				Function: func(Context) {
+					resolve.Invoke()
+				},
+			})
+			return nil
+		})
+		return Window().Get("Promise").New(executor)
+	})
+	doc.Call("startViewTransition", callback)
+}
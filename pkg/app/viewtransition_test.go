@@ -0,0 +1,28 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartViewTransition(t *testing.T) {
+	e := engine{}
+	e.init()
+	defer e.Close()
+
+	t.Run("page is changed directly when disabled", func(t *testing.T) {
+		called := false
+		startViewTransition(&e, func() { called = true })
+		require.True(t, called)
+	})
+
+	t.Run("page is changed directly when unsupported", func(t *testing.T) {
+		SetViewTransitions(true)
+		defer SetViewTransitions(false)
+
+		called := false
+		startViewTransition(&e, func() { called = true })
+		require.True(t, called)
+	})
+}
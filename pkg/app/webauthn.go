@@ -0,0 +1,318 @@
+package app
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// WebAuthnRegisterOptions describes how a passkey is created with
+// WebAuthn.Register.
+type WebAuthnRegisterOptions struct {
+	// The base64url challenge issued by the server with NewWebAuthnChallenge.
+	Challenge string
+
+	// The relying party ID, which is the domain of the app. eg: "go-app.dev".
+	//
+	// Default: the page host name.
+	RPID string
+
+	// The name of the relying party displayed by the browser.
+	RPName string
+
+	// The opaque ID of the user, which must not contain personal
+	// information.
+	UserID string
+
+	// The name of the user account. eg: "max@go-app.dev".
+	UserName string
+
+	// The name of the user displayed by the browser.
+	//
+	// Default: UserName.
+	UserDisplayName string
+
+	// The base64url IDs of the credentials already registered for the user,
+	// which prevents registering the same authenticator twice.
+	ExcludeCredentials []string
+
+	// The time the user has to complete the registration.
+	//
+	// Default: 5 minutes.
+	Timeout time.Duration
+
+	// Whether the credential is stored on the authenticator in order to be
+	// discoverable without giving a user name: "required", "preferred" or
+	// "discouraged".
+	//
+	// Default: "preferred".
+	ResidentKey string
+
+	// Whether the user must be verified with a PIN or biometrics:
+	// "required", "preferred" or "discouraged".
+	//
+	// Default: "preferred".
+	UserVerification string
+
+	// The function called on the UI goroutine when the registration is
+	// completed. The registration must be sent to the server to be verified
+	// with WebAuthnRP.VerifyRegistration.
+	OnDone func(Context, WebAuthnRegistration, error)
+}
+
+// WebAuthnRegistration is the result of a passkey registration. All the
+// binary fields are base64url encoded.
+type WebAuthnRegistration struct {
+	ID                string   `json:"id"`
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports,omitempty"`
+}
+
+// WebAuthnAuthenticateOptions describes how a user is authenticated with
+// WebAuthn.Authenticate.
+type WebAuthnAuthenticateOptions struct {
+	// The base64url challenge issued by the server with NewWebAuthnChallenge.
+	Challenge string
+
+	// The relying party ID used when the credentials were registered.
+	//
+	// Default: the page host name.
+	RPID string
+
+	// The base64url IDs of the credentials allowed to authenticate. Any
+	// discoverable credential is allowed when empty.
+	AllowCredentials []string
+
+	// The time the user has to complete the authentication.
+	//
+	// Default: 5 minutes.
+	Timeout time.Duration
+
+	// Whether the user must be verified with a PIN or biometrics:
+	// "required", "preferred" or "discouraged".
+	//
+	// Default: "preferred".
+	UserVerification string
+
+	// How the browser asks the user for a credential. "conditional" shows
+	// passkeys in the autofill suggestions of inputs with an autocomplete
+	// attribute that contains "webauthn".
+	//
+	// Default: modal.
+	Mediation string
+
+	// The function called on the UI goroutine when the authentication is
+	// completed. The assertion must be sent to the server to be verified with
+	// WebAuthnRP.VerifyAssertion.
+	OnDone func(Context, WebAuthnAssertion, error)
+}
+
+// WebAuthnAssertion is the result of a passkey authentication. All the binary
+// fields are base64url encoded.
+type WebAuthnAssertion struct {
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// WebAuthn creates and uses passkeys with the browser credentials API.
+//
+// Operations are asynchronous. Their result is passed to the given function,
+// called on the UI goroutine. Operations do nothing on the server.
+type WebAuthn struct {
+	ctx Context
+}
+
+// Register creates a passkey for a user.
+func (w WebAuthn) Register(opts WebAuthnRegisterOptions) {
+	if IsServer {
+		return
+	}
+
+	done := func(r WebAuthnRegistration, err error) {
+		if err != nil {
+			err = errors.New("registering passkey failed").
+				Tag("user", opts.UserName).
+				Wrap(err)
+		}
+		dispatchResult(w.ctx, opts.OnDone == nil, err, func(ctx Context) {
+			opts.OnDone(ctx, r, err)
+		})
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(opts.Challenge)
+	if err != nil {
+		done(WebAuthnRegistration{}, errors.New("decoding challenge failed").Wrap(err))
+		return
+	}
+
+	credentials := Window().Get("navigator").Get("credentials")
+	if !credentials.Truthy() || !Window().Get("PublicKeyCredential").Truthy() {
+		done(WebAuthnRegistration{}, errors.New("webauthn is not supported"))
+		return
+	}
+
+	if opts.UserDisplayName == "" {
+		opts.UserDisplayName = opts.UserName
+	}
+	if opts.ResidentKey == "" {
+		opts.ResidentKey = "preferred"
+	}
+
+	rp := map[string]interface{}{"name": opts.RPName}
+	if opts.RPID != "" {
+		rp["id"] = opts.RPID
+	}
+
+	publicKey := map[string]interface{}{
+		"challenge": jsBytes(challenge),
+		"rp":        rp,
+		"user": map[string]interface{}{
+			"id":          jsBytes([]byte(opts.UserID)),
+			"name":        opts.UserName,
+			"displayName": opts.UserDisplayName,
+		},
+		"pubKeyCredParams": []interface{}{
+			map[string]interface{}{"type": "public-key", "alg": coseAlgES256},
+			map[string]interface{}{"type": "public-key", "alg": coseAlgEdDSA},
+			map[string]interface{}{"type": "public-key", "alg": coseAlgRS256},
+		},
+		"timeout":            webAuthnTimeout(opts.Timeout),
+		"excludeCredentials": webAuthnCredentialDescriptors(opts.ExcludeCredentials),
+		"authenticatorSelection": map[string]interface{}{
+			"residentKey":        opts.ResidentKey,
+			"requireResidentKey": opts.ResidentKey == "required",
+			"userVerification":   webAuthnUserVerification(opts.UserVerification),
+		},
+		"attestation": "none",
+	}
+
+	awaitPromise(credentials.Call("create", map[string]interface{}{
+		"publicKey": publicKey,
+	}), func(cred Value) {
+		res := cred.Get("response")
+
+		var transports []string
+		if res.Get("getTransports").Truthy() {
+			t := res.Call("getTransports")
+			for i, l := 0, t.Length(); i < l; i++ {
+				transports = append(transports, t.Index(i).String())
+			}
+		}
+
+		done(WebAuthnRegistration{
+			ID:                base64URL(cred.Get("rawId")),
+			ClientDataJSON:    base64URL(res.Get("clientDataJSON")),
+			AttestationObject: base64URL(res.Get("attestationObject")),
+			Transports:        transports,
+		}, nil)
+	}, func(reason Value) {
+		done(WebAuthnRegistration{}, jsReasonError(reason))
+	})
+}
+
+// Authenticate asks the user for a passkey and signs the server challenge
+// with it.
+func (w WebAuthn) Authenticate(opts WebAuthnAuthenticateOptions) {
+	if IsServer {
+		return
+	}
+
+	done := func(a WebAuthnAssertion, err error) {
+		if err != nil {
+			err = errors.New("authenticating with passkey failed").Wrap(err)
+		}
+		dispatchResult(w.ctx, opts.OnDone == nil, err, func(ctx Context) {
+			opts.OnDone(ctx, a, err)
+		})
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(opts.Challenge)
+	if err != nil {
+		done(WebAuthnAssertion{}, errors.New("decoding challenge failed").Wrap(err))
+		return
+	}
+
+	credentials := Window().Get("navigator").Get("credentials")
+	if !credentials.Truthy() || !Window().Get("PublicKeyCredential").Truthy() {
+		done(WebAuthnAssertion{}, errors.New("webauthn is not supported"))
+		return
+	}
+
+	publicKey := map[string]interface{}{
+		"challenge":        jsBytes(challenge),
+		"timeout":          webAuthnTimeout(opts.Timeout),
+		"allowCredentials": webAuthnCredentialDescriptors(opts.AllowCredentials),
+		"userVerification": webAuthnUserVerification(opts.UserVerification),
+	}
+	if opts.RPID != "" {
+		publicKey["rpId"] = opts.RPID
+	}
+
+	options := map[string]interface{}{
+		"publicKey": publicKey,
+	}
+	if opts.Mediation != "" {
+		options["mediation"] = opts.Mediation
+	}
+
+	awaitPromise(credentials.Call("get", options), func(cred Value) {
+		res := cred.Get("response")
+
+		var userHandle string
+		if h := res.Get("userHandle"); h.Truthy() {
+			userHandle = base64URL(h)
+		}
+
+		done(WebAuthnAssertion{
+			ID:                base64URL(cred.Get("rawId")),
+			ClientDataJSON:    base64URL(res.Get("clientDataJSON")),
+			AuthenticatorData: base64URL(res.Get("authenticatorData")),
+			Signature:         base64URL(res.Get("signature")),
+			UserHandle:        userHandle,
+		}, nil)
+	}, func(reason Value) {
+		done(WebAuthnAssertion{}, jsReasonError(reason))
+	})
+}
+
+func webAuthnTimeout(d time.Duration) int64 {
+	if d <= 0 {
+		d = 5 * time.Minute
+	}
+	return d.Milliseconds()
+}
+
+func webAuthnUserVerification(v string) string {
+	if v == "" {
+		return "preferred"
+	}
+	return v
+}
+
+func webAuthnCredentialDescriptors(ids []string) []interface{} {
+	descriptors := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		b, err := base64.RawURLEncoding.DecodeString(id)
+		if err != nil {
+			Log(errors.New("decoding credential id failed").
+				Tag("id", id).
+				Wrap(err))
+			continue
+		}
+
+		descriptors = append(descriptors, map[string]interface{}{
+			"type": "public-key",
+			"id":   jsBytes(b),
+		})
+	}
+	return descriptors
+}
+
+func base64URL(buf Value) string {
+	return base64.RawURLEncoding.EncodeToString(goBytes(buf))
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnDefaults(t *testing.T) {
+	require.Equal(t, int64(300000), webAuthnTimeout(0))
+	require.Equal(t, int64(1000), webAuthnTimeout(time.Second))
+	require.Equal(t, "preferred", webAuthnUserVerification(""))
+	require.Equal(t, "required", webAuthnUserVerification("required"))
+}
+
+func TestWebAuthnOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	makeContext(h).WebAuthn().Authenticate(WebAuthnAuthenticateOptions{
+		Challenge: NewWebAuthnChallenge(),
+		OnDone: func(Context, WebAuthnAssertion, error) {
+			called = true
+		},
+	})
+	d.Consume()
+	require.False(t, called)
+}
@@ -0,0 +1,494 @@
+package app
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	coseAlgES256 = -7
+	coseAlgEdDSA = -8
+	coseAlgRS256 = -257
+
+	webAuthnFlagUserPresent      = 0x01
+	webAuthnFlagUserVerified     = 0x04
+	webAuthnFlagAttestedCredData = 0x40
+
+	cborMaxSize  = 64 << 10
+	cborMaxDepth = 16
+	cborMaxItems = 1024
+)
+
+// NewWebAuthnChallenge returns a random base64url challenge to pass to
+// WebAuthn.Register or WebAuthn.Authenticate.
+//
+// The challenge must be kept on the server, typically in the user session,
+// in order to be compared with the one signed by the authenticator. A
+// challenge must be used only once.
+func NewWebAuthnChallenge() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New("generating webauthn challenge failed").Wrap(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// WebAuthnRP describes the relying party that verifies passkey registrations
+// and authentications on the server.
+//
+// eg:
+//  rp := app.WebAuthnRP{
+//      ID:      "go-app.dev",
+//      Origins: []string{"https://go-app.dev"},
+//  }
+//
+//  cred, err := rp.VerifyRegistration(session.Challenge, registration)
+type WebAuthnRP struct {
+	// The relying party ID, which is the domain of the app. eg: "go-app.dev".
+	ID string
+
+	// The origins the app is served from. eg: "https://go-app.dev".
+	Origins []string
+
+	// Reports whether the user must have been verified with a PIN or
+	// biometrics.
+	RequireUserVerification bool
+}
+
+// WebAuthnCredential describes a registered passkey. It is meant to be stored
+// with the user it belongs to.
+type WebAuthnCredential struct {
+	// The base64url ID of the credential.
+	ID string `json:"id"`
+
+	// The COSE encoded public key.
+	PublicKey []byte `json:"publicKey"`
+
+	// The signature counter of the authenticator. It is used to detect cloned
+	// authenticators.
+	SignCount uint32 `json:"signCount"`
+
+	// The transports the authenticator supports. eg: "internal" or "usb".
+	Transports []string `json:"transports,omitempty"`
+}
+
+// VerifyRegistration verifies a passkey registration that signed the given
+// challenge and returns the credential to store.
+//
+// Attestation statements are not verified: the authenticator model is not
+// trusted, only the generated key pair.
+func (rp WebAuthnRP) VerifyRegistration(challenge string, r WebAuthnRegistration) (WebAuthnCredential, error) {
+	cred, err := rp.verifyRegistration(challenge, r)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("verifying passkey registration failed").
+			Tag("rp", rp.ID).
+			Tag("credential", r.ID).
+			Wrap(err)
+	}
+	return cred, nil
+}
+
+func (rp WebAuthnRP) verifyRegistration(challenge string, r WebAuthnRegistration) (WebAuthnCredential, error) {
+	if _, err := rp.verifyClientData(r.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return WebAuthnCredential{}, err
+	}
+
+	attestation, err := base64.RawURLEncoding.DecodeString(r.AttestationObject)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("decoding attestation object failed").Wrap(err)
+	}
+	v, _, err := cborDecode(attestation)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("decoding attestation object failed").Wrap(err)
+	}
+	obj, _ := v.(map[interface{}]interface{})
+	authData, ok := obj["authData"].([]byte)
+	if !ok {
+		return WebAuthnCredential{}, errors.New("attestation object has no authenticator data")
+	}
+
+	data, err := rp.parseAuthenticatorData(authData)
+	if err != nil {
+		return WebAuthnCredential{}, err
+	}
+	if data.flags&webAuthnFlagAttestedCredData == 0 {
+		return WebAuthnCredential{}, errors.New("authenticator data has no credential")
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(data.credentialID)
+	if id != r.ID {
+		return WebAuthnCredential{}, errors.New("credential id mismatch").
+			Tag("authenticator-data-id", id)
+	}
+	if _, _, err := coseKey(data.publicKey); err != nil {
+		return WebAuthnCredential{}, err
+	}
+
+	return WebAuthnCredential{
+		ID:         id,
+		PublicKey:  data.publicKey,
+		SignCount:  data.signCount,
+		Transports: r.Transports,
+	}, nil
+}
+
+// VerifyAssertion verifies that the given passkey authentication signed the
+// given challenge with the given registered credential. It returns the
+// credential with an updated signature counter, which must be stored.
+func (rp WebAuthnRP) VerifyAssertion(challenge string, c WebAuthnCredential, a WebAuthnAssertion) (WebAuthnCredential, error) {
+	cred, err := rp.verifyAssertion(challenge, c, a)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("verifying passkey assertion failed").
+			Tag("rp", rp.ID).
+			Tag("credential", a.ID).
+			Wrap(err)
+	}
+	return cred, nil
+}
+
+func (rp WebAuthnRP) verifyAssertion(challenge string, c WebAuthnCredential, a WebAuthnAssertion) (WebAuthnCredential, error) {
+	if a.ID != c.ID {
+		return WebAuthnCredential{}, errors.New("credential id mismatch").
+			Tag("expected", c.ID)
+	}
+
+	clientData, err := rp.verifyClientData(a.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return WebAuthnCredential{}, err
+	}
+
+	authData, err := base64.RawURLEncoding.DecodeString(a.AuthenticatorData)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("decoding authenticator data failed").Wrap(err)
+	}
+	data, err := rp.parseAuthenticatorData(authData)
+	if err != nil {
+		return WebAuthnCredential{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(a.Signature)
+	if err != nil {
+		return WebAuthnCredential{}, errors.New("decoding signature failed").Wrap(err)
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+	if err := verifyCOSESignature(c.PublicKey, signed, signature); err != nil {
+		return WebAuthnCredential{}, err
+	}
+
+	if data.signCount != 0 || c.SignCount != 0 {
+		if data.signCount <= c.SignCount {
+			return WebAuthnCredential{}, errors.New("signature counter did not increase").
+				Tag("stored", c.SignCount).
+				Tag("received", data.signCount)
+		}
+	}
+
+	c.SignCount = data.signCount
+	return c, nil
+}
+
+func (rp WebAuthnRP) verifyClientData(clientDataJSON, typ, challenge string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(clientDataJSON)
+	if err != nil {
+		return nil, errors.New("decoding client data failed").Wrap(err)
+	}
+
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(b, &clientData); err != nil {
+		return nil, errors.New("decoding client data failed").Wrap(err)
+	}
+
+	if clientData.Type != typ {
+		return nil, errors.New("unexpected client data type").
+			Tag("expected", typ).
+			Tag("type", clientData.Type)
+	}
+	if challenge == "" || clientData.Challenge != challenge {
+		return nil, errors.New("challenge mismatch")
+	}
+	if !stringsContains(rp.Origins, clientData.Origin) {
+		return nil, errors.New("origin is not allowed").
+			Tag("origin", clientData.Origin)
+	}
+	return b, nil
+}
+
+type webAuthnAuthenticatorData struct {
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+func (rp WebAuthnRP) parseAuthenticatorData(b []byte) (webAuthnAuthenticatorData, error) {
+	if len(b) < 37 {
+		return webAuthnAuthenticatorData{}, errors.New("authenticator data too short")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(b[:32], rpIDHash[:]) {
+		return webAuthnAuthenticatorData{}, errors.New("relying party id mismatch")
+	}
+
+	data := webAuthnAuthenticatorData{
+		flags:     b[32],
+		signCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	if data.flags&webAuthnFlagUserPresent == 0 {
+		return webAuthnAuthenticatorData{}, errors.New("user is not present")
+	}
+	if rp.RequireUserVerification && data.flags&webAuthnFlagUserVerified == 0 {
+		return webAuthnAuthenticatorData{}, errors.New("user is not verified")
+	}
+	if data.flags&webAuthnFlagAttestedCredData == 0 {
+		return data, nil
+	}
+
+	// AAGUID (16 bytes), credential ID length (2 bytes), credential ID and
+	// COSE public key.
+	b = b[37:]
+	if len(b) < 18 {
+		return webAuthnAuthenticatorData{}, errors.New("attested credential data too short")
+	}
+	idLen := int(binary.BigEndian.Uint16(b[16:18]))
+	b = b[18:]
+	if len(b) < idLen {
+		return webAuthnAuthenticatorData{}, errors.New("credential id too short")
+	}
+	data.credentialID = b[:idLen]
+
+	b = b[idLen:]
+	_, rest, err := cborDecode(b)
+	if err != nil {
+		return webAuthnAuthenticatorData{}, errors.New("decoding credential public key failed").Wrap(err)
+	}
+	data.publicKey = b[:len(b)-len(rest)]
+	return data, nil
+}
+
+// coseKey decodes a COSE encoded public key and returns its algorithm.
+func coseKey(b []byte) (crypto.PublicKey, int64, error) {
+	v, _, err := cborDecode(b)
+	if err != nil {
+		return nil, 0, errors.New("decoding cose key failed").Wrap(err)
+	}
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, errors.New("cose key is not a map")
+	}
+
+	kty, _ := m[int64(1)].(int64)
+	alg, _ := m[int64(3)].(int64)
+	x, _ := m[int64(-2)].([]byte)
+
+	switch {
+	case kty == 2 && alg == coseAlgES256:
+		y, _ := m[int64(-3)].([]byte)
+		if crv, _ := m[int64(-1)].(int64); crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, errors.New("invalid p-256 cose key")
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, alg, nil
+
+	case kty == 1 && alg == coseAlgEdDSA:
+		if crv, _ := m[int64(-1)].(int64); crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errors.New("invalid ed25519 cose key")
+		}
+		return ed25519.PublicKey(x), alg, nil
+
+	case kty == 3 && alg == coseAlgRS256:
+		n, _ := m[int64(-1)].([]byte)
+		e := new(big.Int).SetBytes(x)
+		if len(n) == 0 || !e.IsInt64() {
+			return nil, 0, errors.New("invalid rsa cose key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(e.Int64()),
+		}, alg, nil
+
+	default:
+		return nil, 0, errors.New("unsupported cose key").
+			Tag("kty", kty).
+			Tag("alg", alg)
+	}
+}
+
+func verifyCOSESignature(publicKey, data, signature []byte) error {
+	key, _, err := coseKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		h := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(k, h[:], signature) {
+			return errors.New("invalid signature")
+		}
+
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, signature) {
+			return errors.New("invalid signature")
+		}
+
+	case *rsa.PublicKey:
+		h := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], signature); err != nil {
+			return errors.New("invalid signature").Wrap(err)
+		}
+	}
+	return nil
+}
+
+// cborDecode decodes the CBOR data item at the beginning of the given bytes
+// and returns the remaining bytes. It only supports the subset used by
+// WebAuthn: integers, byte and text strings, arrays, maps and simple values.
+//
+// The decoded data comes from clients, which is why its size, its nesting
+// depth and its number of items are limited.
+func cborDecode(b []byte) (interface{}, []byte, error) {
+	if len(b) > cborMaxSize {
+		return nil, nil, errors.New("cbor data is too large").
+			Tag("size", len(b)).
+			Tag("max", cborMaxSize)
+	}
+	return cborDecodeItem(b, 0)
+}
+
+func cborDecodeItem(b []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, errors.New("cbor data is too deeply nested").Tag("max", cborMaxDepth)
+	}
+	if len(b) == 0 {
+		return nil, nil, errors.New("unexpected end of cbor data")
+	}
+
+	major := b[0] >> 5
+	info := b[0] & 0x1f
+	b = b[1:]
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, b, nil
+		case 21:
+			return true, b, nil
+		case 22, 23:
+			return nil, b, nil
+		default:
+			return nil, nil, errors.New("unsupported cbor simple value").Tag("info", info)
+		}
+	}
+
+	var n uint64
+	switch {
+	case info < 24:
+		n = uint64(info)
+
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(b) < size {
+			return nil, nil, errors.New("unexpected end of cbor data")
+		}
+		for _, c := range b[:size] {
+			n = n<<8 | uint64(c)
+		}
+		b = b[size:]
+
+	default:
+		return nil, nil, errors.New("unsupported cbor length").Tag("info", info)
+	}
+
+	if (major == 4 || major == 5) && (n > cborMaxItems || n > uint64(len(b))) {
+		return nil, nil, errors.New("too many cbor items").
+			Tag("items", n).
+			Tag("max", cborMaxItems)
+	}
+
+	switch major {
+	case 0:
+		return int64(n), b, nil
+
+	case 1:
+		return -1 - int64(n), b, nil
+
+	case 2, 3:
+		if uint64(len(b)) < n {
+			return nil, nil, errors.New("unexpected end of cbor data")
+		}
+		if major == 3 {
+			return string(b[:n]), b[n:], nil
+		}
+		return b[:n], b[n:], nil
+
+	case 4:
+		var items []interface{}
+		for i := uint64(0); i < n; i++ {
+			item, rest, err := cborDecodeItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+			b = rest
+		}
+		return items, b, nil
+
+	case 5:
+		m := make(map[interface{}]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			k, rest, err := cborDecodeItem(b, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key := k.(type) {
+			case int64, string:
+			case []byte:
+				k = string(key)
+			default:
+				return nil, nil, errors.New("unsupported cbor map key").Tag("type", fmt.Sprintf("%T", k))
+			}
+
+			v, rest, err := cborDecodeItem(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[k] = v
+			b = rest
+		}
+		return m, b, nil
+
+	default:
+		return nil, nil, errors.New("unsupported cbor type").Tag("major", major)
+	}
+}
+
+func stringsContains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebAuthnChallenge(t *testing.T) {
+	c := NewWebAuthnChallenge()
+	b, err := base64.RawURLEncoding.DecodeString(c)
+	require.NoError(t, err)
+	require.Len(t, b, 32)
+	require.NotEqual(t, c, NewWebAuthnChallenge())
+}
+
+func TestWebAuthnRP(t *testing.T) {
+	rp := WebAuthnRP{
+		ID:      "go-app.dev",
+		Origins: []string{"https://go-app.dev"},
+	}
+	auth := newTestAuthenticator(t, rp.ID)
+
+	challenge := NewWebAuthnChallenge()
+	cred, err := rp.VerifyRegistration(challenge, auth.register(t, challenge, "https://go-app.dev"))
+	require.NoError(t, err)
+	require.Equal(t, auth.credentialID(), cred.ID)
+	require.NotEmpty(t, cred.PublicKey)
+
+	t.Run("registration with wrong challenge is rejected", func(t *testing.T) {
+		_, err := rp.VerifyRegistration(NewWebAuthnChallenge(), auth.register(t, challenge, "https://go-app.dev"))
+		require.Error(t, err)
+	})
+
+	t.Run("registration from other origin is rejected", func(t *testing.T) {
+		_, err := rp.VerifyRegistration(challenge, auth.register(t, challenge, "https://evil.dev"))
+		require.Error(t, err)
+	})
+
+	t.Run("registration for other relying party is rejected", func(t *testing.T) {
+		other := WebAuthnRP{ID: "evil.dev", Origins: rp.Origins}
+		_, err := other.VerifyRegistration(challenge, auth.register(t, challenge, "https://go-app.dev"))
+		require.Error(t, err)
+	})
+
+	t.Run("registration without user verification is rejected", func(t *testing.T) {
+		strict := rp
+		strict.RequireUserVerification = true
+		_, err := strict.VerifyRegistration(challenge, auth.register(t, challenge, "https://go-app.dev"))
+		require.Error(t, err)
+	})
+
+	t.Run("assertion is verified", func(t *testing.T) {
+		challenge := NewWebAuthnChallenge()
+		updated, err := rp.VerifyAssertion(challenge, cred, auth.assert(t, challenge, "https://go-app.dev", 1))
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), updated.SignCount)
+
+		_, err = rp.VerifyAssertion(challenge, updated, auth.assert(t, challenge, "https://go-app.dev", 1))
+		require.Error(t, err)
+	})
+
+	t.Run("assertion with wrong challenge is rejected", func(t *testing.T) {
+		_, err := rp.VerifyAssertion(NewWebAuthnChallenge(), cred, auth.assert(t, challenge, "https://go-app.dev", 2))
+		require.Error(t, err)
+	})
+
+	t.Run("assertion with invalid signature is rejected", func(t *testing.T) {
+		challenge := NewWebAuthnChallenge()
+		a := auth.assert(t, challenge, "https://go-app.dev", 3)
+		a.Signature = auth.assert(t, NewWebAuthnChallenge(), "https://go-app.dev", 3).Signature
+
+		_, err := rp.VerifyAssertion(challenge, cred, a)
+		require.Error(t, err)
+	})
+
+	t.Run("assertion with other credential is rejected", func(t *testing.T) {
+		challenge := NewWebAuthnChallenge()
+		a := newTestAuthenticator(t, rp.ID).assert(t, challenge, "https://go-app.dev", 4)
+		_, err := rp.VerifyAssertion(challenge, cred, a)
+		require.Error(t, err)
+	})
+}
+
+func TestCBORDecode(t *testing.T) {
+	v, rest, err := cborDecode([]byte{
+		0xa3,       // map(3)
+		0x01, 0x02, // 1: 2
+		0x20, 0x43, 1, 2, 3, // -1: h'010203'
+		0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', // "fmt": "none"
+		0xf5, // trailing true
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[interface{}]interface{}{
+		int64(1):  int64(2),
+		int64(-1): []byte{1, 2, 3},
+		"fmt":     "none",
+	}, v)
+	require.Equal(t, []byte{0xf5}, rest)
+
+	_, _, err = cborDecode([]byte{0x43, 1})
+	require.Error(t, err)
+}
+
+func TestCBORDecodeHostileData(t *testing.T) {
+	utests := []struct {
+		scenario string
+		data     []byte
+	}{
+		{
+			scenario: "array map key",
+			data:     []byte{0xa1, 0x80, 0x00},
+		},
+		{
+			scenario: "map map key",
+			data:     []byte{0xa1, 0xa0, 0x00},
+		},
+		{
+			scenario: "deeply nested arrays",
+			data:     bytes.Repeat([]byte{0x81}, cborMaxSize),
+		},
+		{
+			scenario: "too large data",
+			data:     bytes.Repeat([]byte{0x81}, 20<<20),
+		},
+		{
+			scenario: "too many items",
+			data:     []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			scenario: "more items than bytes",
+			data:     []byte{0x98, 0x10, 0x00},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			_, _, err := cborDecode(u.data)
+			require.Error(t, err)
+		})
+	}
+}
+
+type testAuthenticator struct {
+	rpID string
+	id   []byte
+	key  *ecdsa.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T, rpID string) testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return testAuthenticator{
+		rpID: rpID,
+		id:   id,
+		key:  key,
+	}
+}
+
+func (a testAuthenticator) credentialID() string {
+	return base64.RawURLEncoding.EncodeToString(a.id)
+}
+
+func (a testAuthenticator) register(t *testing.T, challenge, origin string) WebAuthnRegistration {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	publicKey := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	publicKey = append(publicKey, x...)
+	publicKey = append(publicKey, 0x22, 0x58, 0x20)
+	publicKey = append(publicKey, y...)
+
+	authData := a.authData(webAuthnFlagUserPresent|webAuthnFlagAttestedCredData, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, byte(len(a.id)>>8), byte(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, publicKey...)
+
+	attestation := []byte{0xa3}
+	attestation = append(attestation, cborTestText("fmt")...)
+	attestation = append(attestation, cborTestText("none")...)
+	attestation = append(attestation, cborTestText("attStmt")...)
+	attestation = append(attestation, 0xa0)
+	attestation = append(attestation, cborTestText("authData")...)
+	attestation = append(attestation, 0x59, byte(len(authData)>>8), byte(len(authData)))
+	attestation = append(attestation, authData...)
+
+	return WebAuthnRegistration{
+		ID:                a.credentialID(),
+		ClientDataJSON:    testClientData(t, "webauthn.create", challenge, origin),
+		AttestationObject: base64.RawURLEncoding.EncodeToString(attestation),
+	}
+}
+
+func (a testAuthenticator) assert(t *testing.T, challenge, origin string, signCount uint32) WebAuthnAssertion {
+	clientData := testClientData(t, "webauthn.get", challenge, origin)
+	clientDataJSON, _ := base64.RawURLEncoding.DecodeString(clientData)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	authData := a.authData(webAuthnFlagUserPresent, signCount)
+	h := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, h[:])
+	require.NoError(t, err)
+
+	return WebAuthnAssertion{
+		ID:                a.credentialID(),
+		ClientDataJSON:    clientData,
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+	}
+}
+
+func (a testAuthenticator) authData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	b := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[33:], signCount)
+	return b
+}
+
+func testClientData(t *testing.T, typ, challenge, origin string) string {
+	b, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func cborTestText(s string) []byte {
+	return append([]byte{0x60 | byte(len(s))}, s...)
+}
@@ -0,0 +1,499 @@
+package app
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	cryptoKeysDB    = "go-app-crypto"
+	cryptoKeysStore = "keys"
+	aesGCMNonceSize = 12
+)
+
+// CryptoAlgorithm represents a key algorithm supported by WebCrypto.
+type CryptoAlgorithm string
+
+const (
+	// AES-GCM 256 bits secret keys, used to encrypt and decrypt data.
+	AESGCM CryptoAlgorithm = "AES-GCM"
+
+	// HMAC SHA-256 secret keys, used to sign and verify data.
+	HMACSHA256 CryptoAlgorithm = "HMAC-SHA-256"
+
+	// ECDH P-256 key pairs, used to derive AES-GCM keys shared between two
+	// parties.
+	ECDHP256 CryptoAlgorithm = "ECDH-P-256"
+
+	// ECDSA P-256 key pairs, used to sign and verify data with SHA-256.
+	ECDSAP256 CryptoAlgorithm = "ECDSA-P-256"
+
+	// Ed25519 key pairs, used to sign and verify data. Ed25519 is not supported
+	// by all browsers.
+	Ed25519 CryptoAlgorithm = "Ed25519"
+)
+
+// CryptoKey is a WebCrypto key.
+//
+// Secret and private keys are created as non-extractable: their raw bytes
+// can't be read from the page, even by the app. They can only be used through
+// WebCrypto or stored with WebCrypto.StoreKey.
+type CryptoKey struct {
+	value Value
+}
+
+// Type returns the key type: "secret", "private" or "public".
+func (k CryptoKey) Type() string {
+	if k.IsZero() {
+		return ""
+	}
+	return k.value.Get("type").String()
+}
+
+// IsZero reports whether the key is empty.
+func (k CryptoKey) IsZero() bool {
+	return k.value == nil || !k.value.Truthy()
+}
+
+// JSValue returns the underlying JavaScript CryptoKey.
+func (k CryptoKey) JSValue() Value {
+	return k.value
+}
+
+// CryptoKeyPair is a WebCrypto private and public key pair.
+type CryptoKeyPair struct {
+	Private CryptoKey
+	Public  CryptoKey
+}
+
+// WebCrypto performs cryptographic operations with the browser native
+// implementation, which avoids shipping one inside the wasm binary.
+//
+// Operations are asynchronous. Their result is passed to the given function,
+// called on the UI goroutine. Operations do nothing on the server.
+type WebCrypto struct {
+	ctx Context
+}
+
+// Hash computes the digest of the given data. The algorithm is one of
+// "SHA-1", "SHA-256", "SHA-384" or "SHA-512".
+func (c WebCrypto) Hash(algorithm string, data []byte, onDone func(Context, []byte, error)) {
+	c.run("hashing data failed", onDone, func(subtle Value, done func(Value, error)) {
+		awaitCrypto(subtle.Call("digest", algorithm, jsBytes(data)), done)
+	}, func(v Value) interface{} {
+		return goBytes(v)
+	})
+}
+
+// GenerateKey generates a non-extractable secret key. The algorithm is either
+// AESGCM or HMACSHA256.
+func (c WebCrypto) GenerateKey(alg CryptoAlgorithm, onDone func(Context, CryptoKey, error)) {
+	c.run("generating crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		params, err := cryptoKeyParams(alg)
+		if err != nil {
+			done(nil, err)
+			return
+		}
+		if params.pair {
+			done(nil, errors.New("algorithm generates key pairs").Tag("algorithm", alg))
+			return
+		}
+		awaitCrypto(subtle.Call("generateKey", params.algorithm, false, params.secretUsages), done)
+	}, func(v Value) interface{} {
+		return CryptoKey{value: v}
+	})
+}
+
+// GenerateKeyPair generates a key pair with a non-extractable private key. The
+// algorithm is either ECDHP256, ECDSAP256 or Ed25519.
+func (c WebCrypto) GenerateKeyPair(alg CryptoAlgorithm, onDone func(Context, CryptoKeyPair, error)) {
+	c.run("generating crypto key pair failed", onDone, func(subtle Value, done func(Value, error)) {
+		params, err := cryptoKeyParams(alg)
+		if err != nil {
+			done(nil, err)
+			return
+		}
+		if !params.pair {
+			done(nil, errors.New("algorithm generates secret keys").Tag("algorithm", alg))
+			return
+		}
+
+		usages := append(params.privateUsages, params.publicUsages...)
+		awaitCrypto(subtle.Call("generateKey", params.algorithm, false, usages), done)
+	}, func(v Value) interface{} {
+		return CryptoKeyPair{
+			Private: CryptoKey{value: v.Get("privateKey")},
+			Public:  CryptoKey{value: v.Get("publicKey")},
+		}
+	})
+}
+
+// ImportKey imports the given raw key. Keys of AESGCM and HMACSHA256
+// algorithms are imported as non-extractable secret keys, others as public
+// keys, which is typically used to import the public key of another party.
+func (c WebCrypto) ImportKey(alg CryptoAlgorithm, raw []byte, onDone func(Context, CryptoKey, error)) {
+	c.run("importing crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		params, err := cryptoKeyParams(alg)
+		if err != nil {
+			done(nil, err)
+			return
+		}
+
+		usages := params.secretUsages
+		if params.pair {
+			usages = params.publicUsages
+		}
+		awaitCrypto(subtle.Call("importKey", "raw", jsBytes(raw), params.algorithm, params.pair, usages), done)
+	}, func(v Value) interface{} {
+		return CryptoKey{value: v}
+	})
+}
+
+// ExportPublicKey returns the raw bytes of the given public key, which can be
+// sent to another party.
+func (c WebCrypto) ExportPublicKey(key CryptoKey, onDone func(Context, []byte, error)) {
+	c.run("exporting public key failed", onDone, func(subtle Value, done func(Value, error)) {
+		if key.Type() != "public" {
+			done(nil, errors.New("key is not a public key").Tag("type", key.Type()))
+			return
+		}
+		awaitCrypto(subtle.Call("exportKey", "raw", key.value), done)
+	}, func(v Value) interface{} {
+		return goBytes(v)
+	})
+}
+
+// Encrypt encrypts the given data with an AES-GCM key. The returned
+// ciphertext is prefixed by the random nonce that is used to decrypt it.
+func (c WebCrypto) Encrypt(key CryptoKey, data []byte, onDone func(Context, []byte, error)) {
+	var nonce Value
+
+	c.run("encrypting data failed", onDone, func(subtle Value, done func(Value, error)) {
+		nonce = Window().Get("crypto").Call("getRandomValues", Window().Get("Uint8Array").New(aesGCMNonceSize))
+		awaitCrypto(subtle.Call("encrypt", map[string]interface{}{
+			"name": "AES-GCM",
+			"iv":   nonce,
+		}, key.value, jsBytes(data)), done)
+	}, func(v Value) interface{} {
+		b := make([]byte, aesGCMNonceSize)
+		copyBytesToGo(b, nonce)
+		return append(b, goBytes(v)...)
+	})
+}
+
+// Decrypt decrypts the given ciphertext produced by Encrypt with the same
+// AES-GCM key.
+func (c WebCrypto) Decrypt(key CryptoKey, ciphertext []byte, onDone func(Context, []byte, error)) {
+	c.run("decrypting data failed", onDone, func(subtle Value, done func(Value, error)) {
+		if len(ciphertext) < aesGCMNonceSize {
+			done(nil, errors.New("ciphertext too short"))
+			return
+		}
+		awaitCrypto(subtle.Call("decrypt", map[string]interface{}{
+			"name": "AES-GCM",
+			"iv":   jsBytes(ciphertext[:aesGCMNonceSize]),
+		}, key.value, jsBytes(ciphertext[aesGCMNonceSize:])), done)
+	}, func(v Value) interface{} {
+		return goBytes(v)
+	})
+}
+
+// Sign signs the given data with an HMAC secret key or with an ECDSA or
+// Ed25519 private key.
+func (c WebCrypto) Sign(key CryptoKey, data []byte, onDone func(Context, []byte, error)) {
+	c.run("signing data failed", onDone, func(subtle Value, done func(Value, error)) {
+		awaitCrypto(subtle.Call("sign", cryptoSignParams(key), key.value, jsBytes(data)), done)
+	}, func(v Value) interface{} {
+		return goBytes(v)
+	})
+}
+
+// Verify reports whether the given signature of the given data is valid for an
+// HMAC secret key or for an ECDSA or Ed25519 public key.
+func (c WebCrypto) Verify(key CryptoKey, signature, data []byte, onDone func(Context, bool, error)) {
+	c.run("verifying signature failed", onDone, func(subtle Value, done func(Value, error)) {
+		awaitCrypto(subtle.Call("verify", cryptoSignParams(key), key.value, jsBytes(signature), jsBytes(data)), done)
+	}, func(v Value) interface{} {
+		return v.Bool()
+	})
+}
+
+// DeriveKey derives a non-extractable AES-GCM key from an ECDH private key and
+// the ECDH public key of another party. Both parties derive the same key.
+func (c WebCrypto) DeriveKey(private, public CryptoKey, onDone func(Context, CryptoKey, error)) {
+	c.run("deriving crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		awaitCrypto(subtle.Call("deriveKey",
+			map[string]interface{}{
+				"name":   "ECDH",
+				"public": public.value,
+			},
+			private.value,
+			map[string]interface{}{
+				"name":   "AES-GCM",
+				"length": 256,
+			},
+			false,
+			[]interface{}{"encrypt", "decrypt"},
+		), done)
+	}, func(v Value) interface{} {
+		return CryptoKey{value: v}
+	})
+}
+
+// StoreKey stores the given key in IndexedDB under the given name. Stored
+// keys keep being non-extractable.
+func (c WebCrypto) StoreKey(name string, key CryptoKey, onDone func(Context, error)) {
+	c.run("storing crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		cryptoKeysRequest("readwrite", func(store Value) Value {
+			return store.Call("put", key.value, name)
+		}, done)
+	}, nil)
+}
+
+// LoadKey loads the key stored under the given name. The returned key is zero
+// when there is no key for the given name.
+func (c WebCrypto) LoadKey(name string, onDone func(Context, CryptoKey, error)) {
+	c.run("loading crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		cryptoKeysRequest("readonly", func(store Value) Value {
+			return store.Call("get", name)
+		}, done)
+	}, func(v Value) interface{} {
+		return CryptoKey{value: v}
+	})
+}
+
+// DeleteKey deletes the key stored under the given name.
+func (c WebCrypto) DeleteKey(name string, onDone func(Context, error)) {
+	c.run("deleting crypto key failed", onDone, func(subtle Value, done func(Value, error)) {
+		cryptoKeysRequest("readwrite", func(store Value) Value {
+			return store.Call("delete", name)
+		}, done)
+	}, nil)
+}
+
+// run executes the given operation and passes its result, converted by the
+// given function, to onDone on the UI goroutine. onDone is a func(Context,
+// error) when convert is nil, a func(Context, T, error) otherwise.
+func (c WebCrypto) run(msg string, onDone interface{}, op func(subtle Value, done func(Value, error)), convert func(Value) interface{}) {
+	if IsServer {
+		return
+	}
+
+	done := func(v Value, err error) {
+		if err != nil {
+			err = errors.New(msg).Wrap(err)
+		}
+
+		var res interface{}
+		if err == nil && convert != nil {
+			res = convert(v)
+		}
+
+		c.ctx.Dispatch(func(ctx Context) {
+			callCryptoDone(ctx, onDone, res, err)
+		})
+	}
+
+	subtle := Window().Get("crypto").Get("subtle")
+	if !subtle.Truthy() {
+		done(nil, errors.New("webcrypto is not supported"))
+		return
+	}
+	op(subtle, done)
+}
+
+func callCryptoDone(ctx Context, onDone interface{}, res interface{}, err error) {
+	switch f := onDone.(type) {
+	case func(Context, error):
+		if f != nil {
+			f(ctx, err)
+			return
+		}
+
+	case func(Context, []byte, error):
+		if f != nil {
+			b, _ := res.([]byte)
+			f(ctx, b, err)
+			return
+		}
+
+	case func(Context, bool, error):
+		if f != nil {
+			ok, _ := res.(bool)
+			f(ctx, ok, err)
+			return
+		}
+
+	case func(Context, CryptoKey, error):
+		if f != nil {
+			k, _ := res.(CryptoKey)
+			f(ctx, k, err)
+			return
+		}
+
+	case func(Context, CryptoKeyPair, error):
+		if f != nil {
+			p, _ := res.(CryptoKeyPair)
+			f(ctx, p, err)
+			return
+		}
+	}
+
+	if err != nil {
+		Log(err)
+	}
+}
+
+type cryptoParams struct {
+	algorithm     map[string]interface{}
+	pair          bool
+	secretUsages  []interface{}
+	privateUsages []interface{}
+	publicUsages  []interface{}
+}
+
+func cryptoKeyParams(alg CryptoAlgorithm) (cryptoParams, error) {
+	switch alg {
+	case AESGCM:
+		return cryptoParams{
+			algorithm:    map[string]interface{}{"name": "AES-GCM", "length": 256},
+			secretUsages: []interface{}{"encrypt", "decrypt"},
+		}, nil
+
+	case HMACSHA256:
+		return cryptoParams{
+			algorithm:    map[string]interface{}{"name": "HMAC", "hash": "SHA-256"},
+			secretUsages: []interface{}{"sign", "verify"},
+		}, nil
+
+	case ECDHP256:
+		return cryptoParams{
+			algorithm:     map[string]interface{}{"name": "ECDH", "namedCurve": "P-256"},
+			pair:          true,
+			privateUsages: []interface{}{"deriveKey", "deriveBits"},
+			publicUsages:  []interface{}{},
+		}, nil
+
+	case ECDSAP256:
+		return cryptoParams{
+			algorithm:     map[string]interface{}{"name": "ECDSA", "namedCurve": "P-256"},
+			pair:          true,
+			privateUsages: []interface{}{"sign"},
+			publicUsages:  []interface{}{"verify"},
+		}, nil
+
+	case Ed25519:
+		return cryptoParams{
+			algorithm:     map[string]interface{}{"name": "Ed25519"},
+			pair:          true,
+			privateUsages: []interface{}{"sign"},
+			publicUsages:  []interface{}{"verify"},
+		}, nil
+
+	default:
+		return cryptoParams{}, errors.New("unsupported crypto algorithm").
+			Tag("algorithm", alg)
+	}
+}
+
+func cryptoSignParams(key CryptoKey) interface{} {
+	if key.IsZero() {
+		return nil
+	}
+
+	name := key.value.Get("algorithm").Get("name").String()
+	if name == "ECDSA" {
+		return map[string]interface{}{
+			"name": name,
+			"hash": "SHA-256",
+		}
+	}
+	return name
+}
+
+func awaitCrypto(p Value, done func(Value, error)) {
+	awaitPromise(p, func(v Value) {
+		done(v, nil)
+	}, func(reason Value) {
+		done(nil, jsReasonError(reason))
+	})
+}
+
+// cryptoKeysRequest executes the request returned by the given function on the
+// IndexedDB store where crypto keys are saved.
+func cryptoKeysRequest(mode string, request func(store Value) Value, done func(Value, error)) {
+	idb := Window().Get("indexedDB")
+	if !idb.Truthy() {
+		done(nil, errors.New("indexeddb is not supported"))
+		return
+	}
+
+	open := idb.Call("open", cryptoKeysDB, 1)
+	upgrade := FuncOf(func(this Value, args []Value) interface{} {
+		open.Get("result").Call("createObjectStore", cryptoKeysStore)
+		return nil
+	})
+	open.Set("onupgradeneeded", upgrade)
+
+	onIDBRequest(open, func(db Value) {
+		upgrade.Release()
+		store := db.
+			Call("transaction", cryptoKeysStore, mode).
+			Call("objectStore", cryptoKeysStore)
+
+		onIDBRequest(request(store), func(v Value) {
+			db.Call("close")
+			done(v, nil)
+		}, func(err error) {
+			db.Call("close")
+			done(nil, err)
+		})
+	}, func(err error) {
+		upgrade.Release()
+		done(nil, err)
+	})
+}
+
+func onIDBRequest(req Value, onSuccess func(Value), onError func(error)) {
+	var success, failure Func
+	release := func() {
+		success.Release()
+		failure.Release()
+	}
+
+	success = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		onSuccess(req.Get("result"))
+		return nil
+	})
+	failure = FuncOf(func(this Value, args []Value) interface{} {
+		release()
+		onError(jsReasonError(req.Get("error")))
+		return nil
+	})
+
+	req.Set("onsuccess", success)
+	req.Set("onerror", failure)
+}
+
+func jsReasonError(reason Value) error {
+	err := errors.New("javascript operation failed")
+	if reason != nil && reason.Truthy() {
+		err = err.
+			Tag("name", reason.Get("name").String()).
+			Tag("reason", reason.Get("message").String())
+	}
+	return err
+}
+
+func jsBytes(b []byte) Value {
+	v := Window().Get("Uint8Array").New(len(b))
+	copyBytesToJS(v, b)
+	return v
+}
+
+func goBytes(buf Value) []byte {
+	v := Window().Get("Uint8Array").New(buf)
+	b := make([]byte, v.Length())
+	copyBytesToGo(b, v)
+	return b
+}
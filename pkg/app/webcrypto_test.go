@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoKeyParams(t *testing.T) {
+	utests := []struct {
+		scenario string
+		alg      CryptoAlgorithm
+		pair     bool
+	}{
+		{scenario: "aes-gcm", alg: AESGCM},
+		{scenario: "hmac", alg: HMACSHA256},
+		{scenario: "ecdh", alg: ECDHP256, pair: true},
+		{scenario: "ecdsa", alg: ECDSAP256, pair: true},
+		{scenario: "ed25519", alg: Ed25519, pair: true},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			params, err := cryptoKeyParams(u.alg)
+			require.NoError(t, err)
+			require.NotEmpty(t, params.algorithm["name"])
+			require.Equal(t, u.pair, params.pair)
+
+			if u.pair {
+				require.NotEmpty(t, params.privateUsages)
+				require.Empty(t, params.secretUsages)
+			} else {
+				require.NotEmpty(t, params.secretUsages)
+			}
+		})
+	}
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := cryptoKeyParams("RSA")
+		require.Error(t, err)
+	})
+}
+
+func TestCryptoKeyIsZero(t *testing.T) {
+	require.True(t, CryptoKey{}.IsZero())
+	require.Empty(t, CryptoKey{}.Type())
+	require.True(t, CryptoKey{value: Null()}.IsZero())
+}
+
+func TestCallCryptoDone(t *testing.T) {
+	div := Div()
+	d := NewServerTester(div)
+	defer d.Close()
+	ctx := makeContext(div)
+	errTest := errors.New("test")
+
+	t.Run("bytes", func(t *testing.T) {
+		var res []byte
+		callCryptoDone(ctx, func(ctx Context, b []byte, err error) {
+			res = b
+		}, []byte("hello"), nil)
+		require.Equal(t, []byte("hello"), res)
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		var res bool
+		callCryptoDone(ctx, func(ctx Context, ok bool, err error) {
+			res = ok
+		}, true, nil)
+		require.True(t, res)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var res error
+		callCryptoDone(ctx, func(ctx Context, err error) {
+			res = err
+		}, nil, errTest)
+		require.Equal(t, errTest, res)
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		var onDone func(Context, CryptoKey, error)
+		callCryptoDone(ctx, onDone, nil, errTest)
+	})
+}
+
+func TestWebCryptoOnServer(t *testing.T) {
+	h := &hello{}
+	d := NewServerTester(h)
+	defer d.Close()
+
+	called := false
+	makeContext(h).Crypto().Hash("SHA-256", []byte("hello"), func(Context, []byte, error) {
+		called = true
+	})
+	d.Consume()
+	require.False(t, called)
+}
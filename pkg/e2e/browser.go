@@ -0,0 +1,181 @@
+package e2e
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The environment variable that sets the path of the browser executable.
+	browserEnv = "GOAPP_E2E_BROWSER"
+
+	devToolsPrefix = "DevTools listening on "
+)
+
+var (
+	// The executables of the Chromium based browsers, looked up in the PATH.
+	browserNames = []string{
+		"chromium",
+		"chromium-browser",
+		"google-chrome",
+		"google-chrome-stable",
+		"chrome",
+		"msedge",
+	}
+
+	// The locations of the browsers that are usually not in the PATH.
+	browserPaths = []string{
+		"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+		"/Applications/Chromium.app/Contents/MacOS/Chromium",
+		`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+		`C:\Program Files (x86)\Google\Chrome\Application\chrome.exe`,
+	}
+)
+
+// FindBrowser returns the path of the headless browser used to run the tests:
+// the one set by the GOAPP_E2E_BROWSER environment variable, or the first
+// Chromium based browser installed. Tests are generally skipped when it
+// returns an error:
+//  if _, err := e2e.FindBrowser(); err != nil {
+//      t.Skip(err)
+//  }
+func FindBrowser() (string, error) {
+	if path := os.Getenv(browserEnv); path != "" {
+		return path, nil
+	}
+
+	for _, name := range browserNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	for _, path := range browserPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", errors.New("no browser found").
+		Tag("env", browserEnv).
+		Tag("browsers", browserNames)
+}
+
+// launchBrowser starts the given browser in headless mode and returns the
+// URL of the DevTools WebSocket of its blank page.
+func launchBrowser(path, profileDir string, timeout time.Duration) (*exec.Cmd, string, error) {
+	cmd := exec.Command(path,
+		"--headless=new",
+		"--disable-gpu",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--remote-debugging-port=0",
+		"--user-data-dir="+profileDir,
+		"about:blank",
+	)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, "", errors.New("launching browser failed").
+			Tag("browser", path).
+			Wrap(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, "", errors.New("launching browser failed").
+			Tag("browser", path).
+			Wrap(err)
+	}
+
+	kill := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+
+	browserURL := make(chan string, 1)
+	go func() {
+		u, _ := readDevToolsURL(stderr)
+		browserURL <- u
+		io.Copy(ioutil.Discard, stderr)
+	}()
+
+	var wsURL string
+	select {
+	case wsURL = <-browserURL:
+	case <-time.After(timeout):
+	}
+	if wsURL == "" {
+		kill()
+		return nil, "", errors.New("launching browser failed").
+			Tag("browser", path).
+			Tag("reason", "devtools url not found")
+	}
+
+	pageURL, err := devToolsPageURL(wsURL)
+	if err != nil {
+		kill()
+		return nil, "", err
+	}
+	return cmd, pageURL, nil
+}
+
+// readDevToolsURL reads the URL of the DevTools WebSocket of the browser from
+// its output.
+func readDevToolsURL(r io.Reader) (string, error) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, devToolsPrefix) {
+			return strings.TrimPrefix(line, devToolsPrefix), nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// devToolsPageURL returns the URL of the DevTools WebSocket of the first page
+// of the browser that listens on the given URL.
+func devToolsPageURL(browserURL string) (string, error) {
+	u, err := url.Parse(browserURL)
+	if err != nil {
+		return "", errors.New("parsing devtools url failed").
+			Tag("url", browserURL).
+			Wrap(err)
+	}
+
+	res, err := http.Get("http://" + u.Host + "/json/list")
+	if err != nil {
+		return "", errors.New("listing browser pages failed").
+			Tag("url", browserURL).
+			Wrap(err)
+	}
+	defer res.Body.Close()
+
+	var targets []struct {
+		Type                 string `json:"type"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&targets); err != nil {
+		return "", errors.New("listing browser pages failed").
+			Tag("url", browserURL).
+			Wrap(err)
+	}
+
+	for _, t := range targets {
+		if t.Type == "page" && t.WebSocketDebuggerURL != "" {
+			return t.WebSocketDebuggerURL, nil
+		}
+	}
+	return "", errors.New("listing browser pages failed").
+		Tag("url", browserURL).
+		Tag("reason", "no page found")
+}
@@ -0,0 +1,206 @@
+package e2e
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// cdpConn is a connection to a browser page that speaks the Chrome DevTools
+// Protocol.
+type cdpConn struct {
+	ws      *websocket.Conn
+	timeout time.Duration
+
+	mutex   sync.Mutex
+	lastID  int64
+	pending map[int64]chan cdpMessage
+	logs    []string
+	err     error
+}
+
+type cdpMessage struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *cdpError       `json:"error,omitempty"`
+}
+
+type cdpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func dialCDP(url string, timeout time.Duration) (*cdpConn, error) {
+	ws, err := websocket.Dial(url, "", "http://localhost")
+	if err != nil {
+		return nil, errors.New("connecting to browser failed").
+			Tag("url", url).
+			Wrap(err)
+	}
+
+	c := &cdpConn{
+		ws:      ws,
+		timeout: timeout,
+		pending: make(map[int64]chan cdpMessage),
+	}
+	go c.read()
+	return c, nil
+}
+
+func (c *cdpConn) read() {
+	for {
+		var msg cdpMessage
+		if err := websocket.JSON.Receive(c.ws, &msg); err != nil {
+			c.close(errors.New("reading browser message failed").Wrap(err))
+			return
+		}
+
+		c.mutex.Lock()
+		if msg.ID != 0 {
+			if res, ok := c.pending[msg.ID]; ok {
+				delete(c.pending, msg.ID)
+				res <- msg
+			}
+		} else if log, ok := cdpLog(msg); ok {
+			c.logs = append(c.logs, log)
+		}
+		c.mutex.Unlock()
+	}
+}
+
+// call calls the given protocol method and decodes its result in the given
+// value.
+func (c *cdpConn) call(method string, params, result interface{}) error {
+	c.mutex.Lock()
+	if c.err != nil {
+		c.mutex.Unlock()
+		return c.err
+	}
+	c.lastID++
+	id := c.lastID
+	res := make(chan cdpMessage, 1)
+	c.pending[id] = res
+	c.mutex.Unlock()
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return errors.New("encoding browser call failed").
+			Tag("method", method).
+			Wrap(err)
+	}
+	if err := websocket.JSON.Send(c.ws, cdpMessage{
+		ID:     id,
+		Method: method,
+		Params: b,
+	}); err != nil {
+		return errors.New("sending browser call failed").
+			Tag("method", method).
+			Wrap(err)
+	}
+
+	select {
+	case msg, ok := <-res:
+		if !ok {
+			return c.err
+		}
+		if msg.Error != nil {
+			return errors.New("browser call failed").
+				Tag("method", method).
+				Tag("code", msg.Error.Code).
+				Tag("message", msg.Error.Message)
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+
+	case <-time.After(c.timeout):
+		c.mutex.Lock()
+		delete(c.pending, id)
+		c.mutex.Unlock()
+		return errors.New("browser call timed out").
+			Tag("method", method).
+			Tag("timeout", c.timeout)
+	}
+}
+
+func (c *cdpConn) consoleLogs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	logs := make([]string, len(c.logs))
+	copy(logs, c.logs)
+	return logs
+}
+
+func (c *cdpConn) close(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	for id, res := range c.pending {
+		delete(c.pending, id)
+		close(res)
+	}
+	c.ws.Close()
+}
+
+// cdpLog returns the text of the console messages and the uncaught exceptions
+// of the page.
+func cdpLog(msg cdpMessage) (string, bool) {
+	switch msg.Method {
+	case "Runtime.consoleAPICalled":
+		var params struct {
+			Type string `json:"type"`
+			Args []struct {
+				Value       interface{} `json:"value"`
+				Description string      `json:"description"`
+			} `json:"args"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return "", false
+		}
+
+		log := params.Type + ":"
+		for _, a := range params.Args {
+			switch v := a.Value.(type) {
+			case nil:
+				log += " " + a.Description
+
+			case string:
+				log += " " + v
+
+			default:
+				b, _ := json.Marshal(v)
+				log += " " + string(b)
+			}
+		}
+		return log, true
+
+	case "Runtime.exceptionThrown":
+		var params struct {
+			ExceptionDetails struct {
+				Text      string `json:"text"`
+				Exception struct {
+					Description string `json:"description"`
+				} `json:"exception"`
+			} `json:"exceptionDetails"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return "", false
+		}
+		return "exception: " + params.ExceptionDetails.Text + " " +
+			params.ExceptionDetails.Exception.Description, true
+
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,400 @@
+// Package e2e provides a harness to test go-app apps end-to-end in a real
+// headless browser.
+//
+// The harness builds the app as WebAssembly, serves it with the app Handler
+// and drives a Chromium based browser with the Chrome DevTools Protocol.
+// Assertions are made on the page DOM and on the state of the mounted
+// components, which the app exposes through a debug bridge that is only
+// enabled by the harness.
+//
+// eg:
+//  func TestHello(t *testing.T) {
+//      if _, err := e2e.FindBrowser(); err != nil {
+//          t.Skip(err)
+//      }
+//
+//      b, err := e2e.Start(e2e.Config{
+//          Package: "./cmd/hello",
+//          Handler: &app.Handler{Name: "Hello"},
+//      })
+//      require.NoError(t, err)
+//      defer b.Close()
+//
+//      require.NoError(t, b.Navigate("/"))
+//      require.NoError(t, b.Type("input", "Max"))
+//
+//      var hello struct{ Name string }
+//      require.NoError(t, b.Component("hello", &hello))
+//      require.Equal(t, "Max", hello.Name)
+//  }
+package e2e
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	// The environment variable that enables the debug bridge of the app.
+	bridgeEnv = "GOAPP_E2E"
+
+	defaultTimeout  = 10 * time.Second
+	defaultWebDir   = "web"
+	pollingInterval = 50 * time.Millisecond
+)
+
+// Config describes how an app is built, served and browsed.
+type Config struct {
+	// The Go package of the app, built as app.wasm. Eg: "./cmd/hello".
+	Package string
+
+	// The handler that serves the app. Its Resources are replaced to serve
+	// the built app.wasm and the static resources of the Web directory.
+	Handler *app.Handler
+
+	// The directory of the static resources, served at /web/.
+	//
+	// Default: "web".
+	Web string
+
+	// The path of the browser executable.
+	//
+	// Default: the one returned by FindBrowser.
+	Browser string
+
+	// The maximum duration of the browser operations, such as waiting for an
+	// element or the loading of a page.
+	//
+	// Default: 10s.
+	Timeout time.Duration
+}
+
+// Build builds the given Go package as a WebAssembly app at the given path.
+func Build(pkg, out string) error {
+	cmd := exec.Command("go", "build", "-o", out, pkg)
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.New("building app failed").
+			Tag("package", pkg).
+			Tag("output", strings.TrimSpace(string(output))).
+			Wrap(err)
+	}
+	return nil
+}
+
+// Browser is a headless browser that browses an app served by the harness.
+type Browser struct {
+	timeout   time.Duration
+	dir       string
+	server    *httptest.Server
+	cmd       *exec.Cmd
+	conn      *cdpConn
+	appLoaded bool
+}
+
+// Start builds the app, serves it on a local address and launches a headless
+// browser. The returned browser must be closed.
+func Start(c Config) (*Browser, error) {
+	if c.Handler == nil {
+		return nil, errors.New("starting e2e test failed").
+			Tag("reason", "handler is nil")
+	}
+	if c.Web == "" {
+		c.Web = defaultWebDir
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Browser == "" {
+		path, err := FindBrowser()
+		if err != nil {
+			return nil, errors.New("starting e2e test failed").Wrap(err)
+		}
+		c.Browser = path
+	}
+
+	dir, err := ioutil.TempDir("", "goapp-e2e-")
+	if err != nil {
+		return nil, errors.New("starting e2e test failed").Wrap(err)
+	}
+	b := &Browser{
+		timeout: c.Timeout,
+		dir:     dir,
+	}
+
+	wasm := filepath.Join(dir, "app.wasm")
+	if err := Build(c.Package, wasm); err != nil {
+		b.Close()
+		return nil, err
+	}
+
+	if c.Handler.Env == nil {
+		c.Handler.Env = app.Environment{}
+	}
+	c.Handler.Env[bridgeEnv] = "true"
+	c.Handler.Resources = resources{
+		wasm: wasm,
+		web:  http.FileServer(http.Dir(c.Web)),
+	}
+	b.server = httptest.NewServer(c.Handler)
+
+	cmd, pageURL, err := launchBrowser(c.Browser, filepath.Join(dir, "profile"), c.Timeout)
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	b.cmd = cmd
+
+	conn, err := dialCDP(pageURL, c.Timeout)
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	b.conn = conn
+
+	for _, domain := range []string{"Page.enable", "Runtime.enable"} {
+		if err := conn.call(domain, struct{}{}, nil); err != nil {
+			b.Close()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// URL returns the absolute URL of the given path on the server of the app.
+func (b *Browser) URL(path string) string {
+	return b.server.URL + path
+}
+
+// Navigate loads the page at the given path and waits until the app is
+// started and has performed its pending updates.
+func (b *Browser) Navigate(path string) error {
+	var res struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := b.conn.call("Page.navigate", map[string]interface{}{
+		"url": b.URL(path),
+	}, &res); err != nil {
+		return err
+	}
+	if res.ErrorText != "" {
+		return errors.New("navigating failed").
+			Tag("path", path).
+			Tag("error", res.ErrorText)
+	}
+
+	if err := b.poll("window.goappE2E !== undefined"); err != nil {
+		return errors.New("navigating failed").
+			Tag("path", path).
+			Tag("logs", b.Logs()).
+			Wrap(err)
+	}
+	return b.Settle()
+}
+
+// Settle waits until the app has performed its pending updates.
+func (b *Browser) Settle() error {
+	return b.Eval("goappE2E.settle().then(() => null)", nil)
+}
+
+// Wait waits until an element matches the given CSS selector.
+func (b *Browser) Wait(selector string) error {
+	if err := b.poll("document.querySelector(" + jsString(selector) + ") !== null"); err != nil {
+		return errors.New("waiting for element failed").
+			Tag("selector", selector).
+			Wrap(err)
+	}
+	return nil
+}
+
+// Click clicks on the element that matches the given CSS selector and waits
+// until the resulting updates are performed.
+func (b *Browser) Click(selector string) error {
+	if err := b.Wait(selector); err != nil {
+		return err
+	}
+	if err := b.Eval("document.querySelector("+jsString(selector)+").click()", nil); err != nil {
+		return err
+	}
+	return b.Settle()
+}
+
+// Type sets the value of the input that matches the given CSS selector, emits
+// its input and change events, and waits until the resulting updates are
+// performed.
+func (b *Browser) Type(selector, value string) error {
+	if err := b.Wait(selector); err != nil {
+		return err
+	}
+	if err := b.Eval(`(() => {
+		const input = document.querySelector(`+jsString(selector)+`);
+		input.focus();
+		input.value = `+jsString(value)+`;
+		input.dispatchEvent(new Event("input", {bubbles: true}));
+		input.dispatchEvent(new Event("change", {bubbles: true}));
+	})()`, nil); err != nil {
+		return err
+	}
+	return b.Settle()
+}
+
+// Text returns the text content of the element that matches the given CSS
+// selector.
+func (b *Browser) Text(selector string) (string, error) {
+	if err := b.Wait(selector); err != nil {
+		return "", err
+	}
+
+	var text string
+	err := b.Eval("document.querySelector("+jsString(selector)+").textContent", &text)
+	return text, err
+}
+
+// Component decodes the exported fields of the first mounted component with
+// the given type name in the given value. The name is either qualified, such
+// as "*main.hello", or not, such as "hello". Fields are encoded as JSON.
+func (b *Browser) Component(name string, v interface{}) error {
+	var compos []json.RawMessage
+	if err := b.bridge("components", name, &compos); err != nil {
+		return err
+	}
+	if len(compos) == 0 {
+		return errors.New("component not found").Tag("name", name)
+	}
+	return json.Unmarshal(compos[0], v)
+}
+
+// State decodes the value of the given state in the given value.
+func (b *Browser) State(key string, v interface{}) error {
+	return b.bridge("state", key, v)
+}
+
+// Eval evaluates the given JavaScript expression in the page and decodes its
+// result in the given value. Promises are awaited.
+func (b *Browser) Eval(expr string, v interface{}) error {
+	var res struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	if err := b.conn.call("Runtime.evaluate", map[string]interface{}{
+		"expression":    expr,
+		"awaitPromise":  true,
+		"returnByValue": true,
+	}, &res); err != nil {
+		return err
+	}
+
+	if e := res.ExceptionDetails; e != nil {
+		return errors.New("evaluating javascript failed").
+			Tag("expression", expr).
+			Tag("error", e.Exception.Description).
+			Tag("text", e.Text)
+	}
+	if v == nil || len(res.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Result.Value, v)
+}
+
+// Logs returns the console messages and the uncaught exceptions of the page.
+func (b *Browser) Logs() []string {
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.consoleLogs()
+}
+
+// Close stops the browser and the server, and removes the built app.
+func (b *Browser) Close() error {
+	if b.conn != nil {
+		b.conn.close(errors.New("browser is closed"))
+	}
+	if b.cmd != nil {
+		b.cmd.Process.Kill()
+		b.cmd.Wait()
+	}
+	if b.server != nil {
+		b.server.Close()
+	}
+	return os.RemoveAll(b.dir)
+}
+
+// bridge calls the given function of the debug bridge of the app and decodes
+// its JSON result in the given value.
+func (b *Browser) bridge(fn, arg string, v interface{}) error {
+	var res string
+	if err := b.Eval("goappE2E."+fn+"("+jsString(arg)+")", &res); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(res), v)
+}
+
+// poll evaluates the given JavaScript condition until it is true.
+func (b *Browser) poll(condition string) error {
+	timeout := time.Now().Add(b.timeout)
+	for {
+		var ok bool
+		err := b.Eval(condition, &ok)
+		if err == nil && ok {
+			return nil
+		}
+		if time.Now().After(timeout) {
+			return errors.New("condition is not met").
+				Tag("condition", condition).
+				Tag("timeout", b.timeout).
+				Wrap(err)
+		}
+		time.Sleep(pollingInterval)
+	}
+}
+
+// jsString returns the given string as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// resources serves the built app.wasm and the static resources of the Web
+// directory.
+type resources struct {
+	wasm string
+	web  http.Handler
+}
+
+func (r resources) Package() string {
+	return ""
+}
+
+func (r resources) Static() string {
+	return ""
+}
+
+func (r resources) AppWASM() string {
+	return "/web/app.wasm"
+}
+
+func (r resources) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == r.AppWASM() {
+		w.Header().Set("Content-Type", "application/wasm")
+		http.ServeFile(w, req, r.wasm)
+		return
+	}
+	http.StripPrefix("/web", r.web).ServeHTTP(w, req)
+}
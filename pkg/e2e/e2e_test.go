@@ -0,0 +1,223 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestBrowser(t *testing.T) {
+	if _, err := FindBrowser(); err != nil {
+		t.Skip(err)
+	}
+
+	b, err := Start(Config{
+		Package: "./testdata/hello",
+		Handler: &app.Handler{Name: "Hello"},
+	})
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, b.Navigate("/"))
+	require.NoError(t, b.Type("input", "Max"))
+
+	text, err := b.Text("h1")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Max", text)
+
+	var hello struct{ Name string }
+	require.NoError(t, b.Component("hello", &hello))
+	require.Equal(t, "Max", hello.Name)
+
+	require.Error(t, b.Component("bye", &hello))
+}
+
+func TestStartWithoutHandler(t *testing.T) {
+	_, err := Start(Config{Package: "./testdata/hello"})
+	require.Error(t, err)
+}
+
+func TestFindBrowser(t *testing.T) {
+	os.Setenv(browserEnv, "/usr/bin/firefly")
+	defer os.Unsetenv(browserEnv)
+
+	path, err := FindBrowser()
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/firefly", path)
+}
+
+func TestReadDevToolsURL(t *testing.T) {
+	u, err := readDevToolsURL(strings.NewReader(`
+[0101/000000.000:WARNING:bluez_dbus_manager.cc(248)] Floss manager not present
+DevTools listening on ws://127.0.0.1:41241/devtools/browser/5f1d
+`))
+	require.NoError(t, err)
+	require.Equal(t, "ws://127.0.0.1:41241/devtools/browser/5f1d", u)
+
+	_, err = readDevToolsURL(strings.NewReader("crashed\n"))
+	require.Error(t, err)
+}
+
+func TestDevToolsPageURL(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/json/list", r.URL.Path)
+		w.Write([]byte(`[
+			{"type": "service_worker", "webSocketDebuggerUrl": "ws://localhost/devtools/worker/1"},
+			{"type": "page", "webSocketDebuggerUrl": "ws://localhost/devtools/page/2"}
+		]`))
+	}))
+	defer s.Close()
+
+	u, err := devToolsPageURL(strings.Replace(s.URL, "http", "ws", 1) + "/devtools/browser/1")
+	require.NoError(t, err)
+	require.Equal(t, "ws://localhost/devtools/page/2", u)
+}
+
+func TestResources(t *testing.T) {
+	dir := t.TempDir()
+	wasm := filepath.Join(dir, "app.wasm")
+	require.NoError(t, ioutil.WriteFile(wasm, []byte("wasm"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web", "hello.css"), []byte("css"), 0644))
+
+	r := resources{
+		wasm: wasm,
+		web:  http.FileServer(http.Dir(filepath.Join(dir, "web"))),
+	}
+
+	utests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/web/app.wasm", expected: "wasm"},
+		{path: "/web/hello.css", expected: "css"},
+	}
+
+	for _, u := range utests {
+		t.Run(u.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, u.path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, u.expected, w.Body.String())
+		})
+	}
+}
+
+func TestBrowserEval(t *testing.T) {
+	s := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		for {
+			var msg cdpMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				return
+			}
+
+			var params struct {
+				Expression string `json:"expression"`
+			}
+			json.Unmarshal(msg.Params, &params)
+
+			res := cdpMessage{ID: msg.ID}
+			switch params.Expression {
+			case `goappE2E.components("hello")`:
+				res.Result = json.RawMessage(`{"result": {"type": "string", "value": "[{\"Name\": \"Max\"}]"}}`)
+
+			case `goappE2E.components("bye")`:
+				res.Result = json.RawMessage(`{"result": {"type": "string", "value": "[]"}}`)
+
+			case `goappE2E.state("/count")`:
+				res.Result = json.RawMessage(`{"result": {"type": "string", "value": "42"}}`)
+
+			case "throw":
+				res.Result = json.RawMessage(`{"result": {}, "exceptionDetails": {"text": "Uncaught", "exception": {"description": "Error: throw"}}}`)
+
+			case "slow":
+				continue
+
+			default:
+				res.Error = &cdpError{Code: -32000, Message: "unsupported"}
+			}
+
+			websocket.JSON.Send(ws, cdpMessage{
+				Method: "Runtime.consoleAPICalled",
+				Params: json.RawMessage(`{"type": "log", "args": [{"type": "string", "value": "evaluated"}, {"type": "number", "value": 1}]}`),
+			})
+			websocket.JSON.Send(ws, res)
+		}
+	}))
+	defer s.Close()
+
+	conn, err := dialCDP(strings.Replace(s.URL, "http", "ws", 1), time.Millisecond*100)
+	require.NoError(t, err)
+	b := &Browser{conn: conn, timeout: time.Millisecond * 100}
+	defer b.Close()
+
+	var hello struct{ Name string }
+	require.NoError(t, b.Component("hello", &hello))
+	require.Equal(t, "Max", hello.Name)
+	require.Error(t, b.Component("bye", &hello))
+
+	var count int
+	require.NoError(t, b.State("/count", &count))
+	require.Equal(t, 42, count)
+
+	require.Error(t, b.Eval("throw", nil))
+	require.Error(t, b.Eval("unknown", nil))
+	require.Error(t, b.Eval("slow", nil))
+	require.Error(t, b.poll("unknown"))
+
+	require.Contains(t, b.Logs(), "log: evaluated 1")
+}
+
+func TestCDPLog(t *testing.T) {
+	utests := []struct {
+		scenario string
+		msg      cdpMessage
+		expected string
+		ok       bool
+	}{
+		{
+			scenario: "console message",
+			msg: cdpMessage{
+				Method: "Runtime.consoleAPICalled",
+				Params: json.RawMessage(`{"type": "error", "args": [{"type": "object", "description": "Error: boom"}]}`),
+			},
+			expected: "error: Error: boom",
+			ok:       true,
+		},
+		{
+			scenario: "exception",
+			msg: cdpMessage{
+				Method: "Runtime.exceptionThrown",
+				Params: json.RawMessage(`{"exceptionDetails": {"text": "Uncaught", "exception": {"description": "panic"}}}`),
+			},
+			expected: "exception: Uncaught panic",
+			ok:       true,
+		},
+		{
+			scenario: "other event",
+			msg:      cdpMessage{Method: "Page.loadEventFired"},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			log, ok := cdpLog(u.msg)
+			require.Equal(t, u.ok, ok)
+			require.Equal(t, u.expected, log)
+		})
+	}
+}
+
+func TestJSString(t *testing.T) {
+	require.Equal(t, `"a \"b\" \u003cc\u003e"`, jsString(`a "b" <c>`))
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+type hello struct {
+	app.Compo
+
+	Name string
+}
+
+func (h *hello) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Text("Hello "+h.Name),
+		app.Input().
+			Value(h.Name).
+			OnInput(h.ValueTo(&h.Name)),
+	)
+}
+
+func main() {
+	app.Route("/", &hello{})
+	app.RunWhenOnBrowser()
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// CSV writes the given table to w as comma-separated values.
+func CSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+
+	if len(t.Header) != 0 {
+		if err := cw.Write(t.Header); err != nil {
+			return errors.New("writing csv header failed").Wrap(err)
+		}
+	}
+
+	record := make([]string, 0, len(t.Header))
+	for i, row := range t.Rows {
+		record = record[:0]
+		for _, cell := range row {
+			record = append(record, formatCell(cell))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return errors.New("writing csv row failed").
+				Tag("row", i).
+				Wrap(err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.New("writing csv failed").Wrap(err)
+	}
+	return nil
+}
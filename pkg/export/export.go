@@ -0,0 +1,76 @@
+// Package export provides functions to write tabular data as files that can
+// be opened by spreadsheet applications, such as CSV and XLSX files.
+//
+// Exported files are typically downloaded with app.Context.Download:
+//  var b bytes.Buffer
+//  if err := export.XLSX(&b, table); err != nil {
+//      app.Log(err)
+//      return
+//  }
+//  ctx.Download("orders.xlsx", export.XLSXContentType, &b)
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// The content type of CSV files.
+	CSVContentType = "text/csv; charset=utf-8"
+
+	// The content type of XLSX files.
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Table represents tabular data.
+type Table struct {
+	// The name of the table. It is used as the sheet name of XLSX files.
+	Name string
+
+	// The column names. The header row is omitted when empty.
+	Header []string
+
+	// The rows of the table. Cells can be strings, numbers, booleans,
+	// time.Time values or any value that is formatted with fmt.Sprint. Nil
+	// cells are empty.
+	Rows [][]interface{}
+}
+
+func formatCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return v
+
+	case bool:
+		return strconv.FormatBool(v)
+
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+
+	case time.Time:
+		return v.Format(time.RFC3339)
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSV(t *testing.T) {
+	var b bytes.Buffer
+	err := CSV(&b, Table{
+		Header: []string{"name", "price", "available", "date", "notes"},
+		Rows: [][]interface{}{
+			{"Hat", 9.5, true, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), nil},
+			{"Shoes, red", 42, false, nil, `say "hi"`},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "name,price,available,date,notes\n"+
+		"Hat,9.5,true,2021-03-04T00:00:00Z,\n"+
+		"\"Shoes, red\",42,false,,\"say \"\"hi\"\"\"\n", b.String())
+}
+
+func TestXLSX(t *testing.T) {
+	var b bytes.Buffer
+	err := XLSX(&b,
+		Table{
+			Name:   "Orders",
+			Header: []string{"name", "price"},
+			Rows: [][]interface{}{
+				{"Hat & <scarf>", 9.5},
+				{true, nil, 3},
+			},
+		},
+		Table{Name: "orders"},
+	)
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "_rels/.rels")
+	require.Contains(t, files, "xl/_rels/workbook.xml.rels")
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="Orders" sheetId="1" r:id="rId1"/>`)
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="orders (2)" sheetId="2" r:id="rId2"/>`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`)
+	require.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">Hat &amp; &lt;scarf&gt;</t></is></c>`)
+	require.Contains(t, sheet, `<c r="B2"><v>9.5</v></c>`)
+	require.Contains(t, sheet, `<c r="A3" t="b"><v>1</v></c>`)
+	require.NotContains(t, sheet, `r="B3"`)
+	require.Contains(t, sheet, `<c r="C3"><v>3</v></c>`)
+	require.Contains(t, files, "xl/worksheets/sheet2.xml")
+}
+
+func TestXLSXColumn(t *testing.T) {
+	require.Equal(t, "A", xlsxColumn(0))
+	require.Equal(t, "Z", xlsxColumn(25))
+	require.Equal(t, "AA", xlsxColumn(26))
+	require.Equal(t, "AZ", xlsxColumn(51))
+	require.Equal(t, "BA", xlsxColumn(52))
+	require.Equal(t, "ZZ", xlsxColumn(701))
+	require.Equal(t, "AAA", xlsxColumn(702))
+}
+
+func TestXLSXSheetNames(t *testing.T) {
+	require.Equal(t, []string{
+		"Sheet1",
+		"a_b_c",
+		"abcdefghijklmnopqrstuvwxyzabcde",
+		"abcdefghijklmnopqrstuvwxyza (2)",
+	}, xlsxSheetNames([]Table{
+		{},
+		{Name: "a/b?c"},
+		{Name: "abcdefghijklmnopqrstuvwxyzabcdefgh"},
+		{Name: "abcdefghijklmnopqrstuvwxyzabcdefgh"},
+	}))
+}
@@ -0,0 +1,206 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const (
+	xlsxMaxSheetName = 31
+)
+
+// XLSX writes the given tables to w as an Excel workbook. Each table is
+// written in its own sheet.
+func XLSX(w io.Writer, tables ...Table) error {
+	if len(tables) == 0 {
+		tables = []Table{{}}
+	}
+
+	zw := zip.NewWriter(w)
+	names := xlsxSheetNames(tables)
+
+	files := []xlsxFile{
+		{name: "[Content_Types].xml", content: xlsxContentTypes(len(tables))},
+		{name: "_rels/.rels", content: []byte(xlsxRels)},
+		{name: "xl/workbook.xml", content: xlsxWorkbook(names)},
+		{name: "xl/_rels/workbook.xml.rels", content: xlsxWorkbookRels(len(tables))},
+	}
+	for i, t := range tables {
+		files = append(files, xlsxFile{
+			name:    "xl/worksheets/sheet" + strconv.Itoa(i+1) + ".xml",
+			content: xlsxSheet(t),
+		})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return errors.New("writing xlsx failed").
+				Tag("file", f.name).
+				Wrap(err)
+		}
+		if _, err := fw.Write(f.content); err != nil {
+			return errors.New("writing xlsx failed").
+				Tag("file", f.name).
+				Wrap(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return errors.New("writing xlsx failed").Wrap(err)
+	}
+	return nil
+}
+
+type xlsxFile struct {
+	name    string
+	content []byte
+}
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func xlsxContentTypes(sheets int) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		b.WriteString(`<Override PartName="/xl/worksheets/sheet` + strconv.Itoa(i) + `.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`)
+	}
+	b.WriteString(`</Types>`)
+	return b.Bytes()
+}
+
+func xlsxWorkbook(names []string) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		id := strconv.Itoa(i + 1)
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(name))
+		b.WriteString(`" sheetId="` + id + `" r:id="rId` + id + `"/>`)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.Bytes()
+}
+
+func xlsxWorkbookRels(sheets int) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		id := strconv.Itoa(i)
+		b.WriteString(`<Relationship Id="rId` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet` + id + `.xml"/>`)
+	}
+	b.WriteString(`</Relationships>`)
+	return b.Bytes()
+}
+
+func xlsxSheet(t Table) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	row := 1
+	if len(t.Header) != 0 {
+		cells := make([]interface{}, len(t.Header))
+		for i, h := range t.Header {
+			cells[i] = h
+		}
+		xlsxRow(&b, row, cells)
+		row++
+	}
+	for _, r := range t.Rows {
+		xlsxRow(&b, row, r)
+		row++
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.Bytes()
+}
+
+func xlsxRow(b *bytes.Buffer, row int, cells []interface{}) {
+	r := strconv.Itoa(row)
+	b.WriteString(`<row r="` + r + `">`)
+
+	for i, cell := range cells {
+		if cell == nil {
+			continue
+		}
+
+		ref := xlsxColumn(i) + r
+		if isNumber(cell) {
+			b.WriteString(`<c r="` + ref + `"><v>` + formatCell(cell) + `</v></c>`)
+			continue
+		}
+
+		if v, ok := cell.(bool); ok {
+			value := "0"
+			if v {
+				value = "1"
+			}
+			b.WriteString(`<c r="` + ref + `" t="b"><v>` + value + `</v></c>`)
+			continue
+		}
+
+		b.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(b, []byte(formatCell(cell)))
+		b.WriteString(`</t></is></c>`)
+	}
+
+	b.WriteString(`</row>`)
+}
+
+// xlsxColumn returns the letters of the column at the given zero-based index.
+// eg: 0 is "A", 26 is "AA".
+func xlsxColumn(i int) string {
+	var name []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = append([]byte{byte('A' + (i-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+func xlsxSheetNames(tables []Table) []string {
+	names := make([]string, len(tables))
+	used := make(map[string]bool, len(tables))
+
+	for i, t := range tables {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, t.Name)
+		if name == "" {
+			name = "Sheet" + strconv.Itoa(i+1)
+		}
+		if r := []rune(name); len(r) > xlsxMaxSheetName {
+			name = string(r[:xlsxMaxSheetName])
+		}
+
+		base := []rune(name)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			suffix := " (" + strconv.Itoa(n) + ")"
+			if len(base)+len(suffix) > xlsxMaxSheetName {
+				base = base[:xlsxMaxSheetName-len(suffix)]
+			}
+			name = string(base) + suffix
+		}
+
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Extract walks the Go source files of the given directories and their
+// subdirectories and returns the messages of the Context.T calls, sorted by
+// key. Only calls whose key is a string literal are extracted.
+//
+// Test files, as well as vendor, testdata and hidden directories, are skipped.
+func Extract(dirs ...string) ([]Message, error) {
+	e := newExtractor()
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name := info.Name()
+			if info.IsDir() {
+				if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+				return nil
+			}
+			return e.parseFile(path, nil)
+		})
+		if err != nil {
+			return nil, errors.New("extracting messages failed").
+				Tag("dir", dir).
+				Wrap(err)
+		}
+	}
+
+	return e.messages(), nil
+}
+
+// ExtractSource returns the messages of the Context.T calls of the given Go
+// source, sorted by key. The filename is used to report the message locations.
+func ExtractSource(filename string, src []byte) ([]Message, error) {
+	e := newExtractor()
+	if err := e.parseFile(filename, src); err != nil {
+		return nil, errors.New("extracting messages failed").
+			Tag("filename", filename).
+			Wrap(err)
+	}
+	return e.messages(), nil
+}
+
+type extractor struct {
+	fset      *token.FileSet
+	locations map[string][]string
+}
+
+func newExtractor() *extractor {
+	return &extractor{
+		fset:      token.NewFileSet(),
+		locations: make(map[string][]string),
+	}
+}
+
+func (e *extractor) parseFile(filename string, src interface{}) error {
+	f, err := parser.ParseFile(e.fset, filename, src, 0)
+	if err != nil {
+		return errors.New("parsing go file failed").
+			Tag("filename", filename).
+			Wrap(err)
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "T" {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		id, err := strconv.Unquote(lit.Value)
+		if err != nil || id == "" {
+			return true
+		}
+
+		pos := e.fset.Position(lit.Pos())
+		location := filepath.ToSlash(pos.Filename) + ":" + strconv.Itoa(pos.Line)
+		e.locations[id] = append(e.locations[id], location)
+		return true
+	})
+	return nil
+}
+
+func (e *extractor) messages() []Message {
+	messages := make([]Message, 0, len(e.locations))
+	for id, locations := range e.locations {
+		messages = append(messages, Message{
+			ID:        id,
+			Locations: locations,
+		})
+	}
+
+	sort.Slice(messages, func(a, b int) bool {
+		return messages[a].ID < messages[b].ID
+	})
+	return messages
+}
@@ -0,0 +1,127 @@
+// Package i18n provides functions to extract the translatable texts of an app
+// and to maintain the translation catalogs that are registered with
+// app.AddTranslations.
+//
+// Texts are extracted from the Go sources by looking for Context.T calls with a
+// string literal key. Catalogs can then be created or updated in CI:
+//  messages, err := i18n.Extract("./pkg/ui")
+//  if err != nil {
+//      log.Fatal(err)
+//  }
+//
+//  for _, locale := range []string{"en", "de"} {
+//      if err := i18n.MergeFile("web/locales/"+locale+".po", messages); err != nil {
+//          log.Fatal(err)
+//      }
+//  }
+package i18n
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// Message describes a translatable text found in the Go sources.
+type Message struct {
+	// The key passed to Context.T.
+	ID string
+
+	// The positions of the calls that use the key, formatted as
+	// "filename:line".
+	Locations []string
+}
+
+// Catalog represents the translations of a locale, indexed by key. Empty
+// translations are the keys that are not translated yet.
+type Catalog map[string]string
+
+// Merge returns a catalog that contains the keys of the given messages, with
+// the translations of the given catalog. Keys that are not in the catalog have
+// an empty translation and keys that are no longer used are removed.
+func Merge(c Catalog, messages []Message) Catalog {
+	merged := make(Catalog, len(messages))
+	for _, m := range messages {
+		merged[m.ID] = c[m.ID]
+	}
+	return merged
+}
+
+// MergeFile merges the given messages into the catalog file at the given path,
+// which is created when it does not exist. The file format is given by its
+// extension: ".json" or ".po".
+func MergeFile(path string, messages []Message) error {
+	var (
+		read  func(io.Reader) (Catalog, error)
+		write func(io.Writer, Catalog, []Message) error
+	)
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		read = ReadJSON
+		write = func(w io.Writer, c Catalog, _ []Message) error {
+			return WriteJSON(w, c)
+		}
+
+	case ".po":
+		read = ReadPO
+		write = WritePO
+
+	default:
+		return errors.New("unsupported catalog format").
+			Tag("path", path).
+			Tag("extension", ext)
+	}
+
+	catalog := make(Catalog)
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+
+	case err != nil:
+		return errors.New("reading catalog file failed").
+			Tag("path", path).
+			Wrap(err)
+
+	default:
+		if catalog, err = read(bytes.NewReader(b)); err != nil {
+			return errors.New("decoding catalog file failed").
+				Tag("path", path).
+				Wrap(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, Merge(catalog, messages), messages); err != nil {
+		return errors.New("encoding catalog file failed").
+			Tag("path", path).
+			Wrap(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.New("creating catalog directory failed").
+			Tag("path", path).
+			Wrap(err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return errors.New("writing catalog file failed").
+			Tag("path", path).
+			Wrap(err)
+	}
+	return nil
+}
+
+func sortedKeys(c Catalog) []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
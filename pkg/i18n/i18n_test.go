@@ -0,0 +1,233 @@
+package i18n
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = `package ui
+
+import "github.com/maxence-charriere/go-app/v9/pkg/app"
+
+type hello struct {
+	app.Compo
+	greeting string
+}
+
+func (h *hello) OnPreRender(ctx app.Context) {
+	h.greeting = ctx.T("Hello, %s!", "Max")
+	key := "dynamic"
+	ctx.T(key)
+	ctx.T("")
+	ctx.T(` + "`Say \"hi\"`" + `)
+}
+
+func (h *hello) OnNav(ctx app.Context) {
+	h.greeting = ctx.T("Hello, %s!", "Max")
+}
+`
+
+func TestExtractSource(t *testing.T) {
+	messages, err := ExtractSource("ui/hello.go", []byte(testSource))
+	require.NoError(t, err)
+	require.Equal(t, []Message{
+		{ID: "Hello, %s!", Locations: []string{"ui/hello.go:11", "ui/hello.go:19"}},
+		{ID: `Say "hi"`, Locations: []string{"ui/hello.go:15"}},
+	}, messages)
+}
+
+func TestExtractSourceError(t *testing.T) {
+	_, err := ExtractSource("ui/hello.go", []byte("package"))
+	require.Error(t, err)
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"ui/hello.go":          testSource,
+		"ui/hello_test.go":     `package ui; func f(ctx Context) { ctx.T("test") }`,
+		"ui/vendor/v.go":       `package v; func f(ctx Context) { ctx.T("vendor") }`,
+		"ui/testdata/t.go":     `package t; func f(ctx Context) { ctx.T("testdata") }`,
+		"ui/footer/footer.go":  `package footer; func f(ctx Context) { ctx.T("Goodbye") }`,
+		"ui/footer/README.txt": `ctx.T("readme")`,
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(src), 0644))
+	}
+
+	messages, err := Extract(filepath.Join(dir, "ui"))
+	require.NoError(t, err)
+
+	ids := make([]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+	require.Equal(t, []string{"Goodbye", "Hello, %s!", `Say "hi"`}, ids)
+
+	_, err = Extract(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	c := Merge(Catalog{
+		"Hello, %s!": "Hallo, %s!",
+		"Obsolete":   "Veraltet",
+	}, []Message{
+		{ID: "Hello, %s!"},
+		{ID: "Goodbye"},
+	})
+	require.Equal(t, Catalog{
+		"Hello, %s!": "Hallo, %s!",
+		"Goodbye":    "",
+	}, c)
+}
+
+func TestJSON(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteJSON(&b, Catalog{
+		"b": "<b>",
+		"a": "A",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"a\": \"A\",\n  \"b\": \"<b>\"\n}\n", b.String())
+
+	c, err := ReadJSON(&b)
+	require.NoError(t, err)
+	require.Equal(t, Catalog{"a": "A", "b": "<b>"}, c)
+
+	_, err = ReadJSON(bytes.NewBufferString("["))
+	require.Error(t, err)
+}
+
+func TestPO(t *testing.T) {
+	var b bytes.Buffer
+	err := WritePO(&b, Catalog{
+		"Hello, %s!": "Hallo, %s!",
+		"Say \"hi\"": "",
+		"Zebra":      "Zebra\nline",
+	}, []Message{
+		{ID: "Say \"hi\"", Locations: []string{"ui/hello.go:15"}},
+		{ID: "Hello, %s!", Locations: []string{"ui/hello.go:11", "ui/hello.go:19"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: ui/hello.go:15
+msgid "Say \"hi\""
+msgstr ""
+
+#: ui/hello.go:11
+#: ui/hello.go:19
+#, c-format
+msgid "Hello, %s!"
+msgstr "Hallo, %s!"
+
+msgid "Zebra"
+msgstr "Zebra\nline"
+`, b.String())
+
+	c, err := ReadPO(&b)
+	require.NoError(t, err)
+	require.Equal(t, Catalog{
+		"Hello, %s!": "Hallo, %s!",
+		"Say \"hi\"": "",
+		"Zebra":      "Zebra\nline",
+	}, c)
+}
+
+func TestReadPO(t *testing.T) {
+	c, err := ReadPO(bytes.NewBufferString(`# Translator comment
+msgid ""
+msgstr ""
+"Language: de\n"
+
+#, fuzzy
+msgid "Hello"
+msgstr "Hallo"
+
+msgid ""
+"Multi "
+"line"
+msgstr ""
+"Mehrere "
+"Zeilen"
+
+msgctxt "menu"
+msgid "File"
+msgstr "Datei"
+
+msgid "Apple"
+msgid_plural "Apples"
+msgstr[0] "Apfel"
+msgstr[1] "Äpfel"
+
+#~ msgid "Obsolete"
+#~ msgstr "Veraltet"
+`))
+	require.NoError(t, err)
+	require.Equal(t, Catalog{
+		"Hello":      "Hallo",
+		"Multi line": "Mehrere Zeilen",
+	}, c)
+
+	_, err = ReadPO(bytes.NewBufferString(`msgid "unterminated`))
+	require.Error(t, err)
+}
+
+func TestMergeFile(t *testing.T) {
+	dir := t.TempDir()
+	messages := []Message{
+		{ID: "Hello", Locations: []string{"ui/hello.go:11"}},
+		{ID: "Goodbye", Locations: []string{"ui/footer.go:3"}},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(dir, "locales", "de.json")
+		require.NoError(t, MergeFile(path, messages[:1]))
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(`{"Hello": "Hallo", "Obsolete": "Veraltet"}`), 0644))
+		require.NoError(t, MergeFile(path, messages))
+
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		c, err := ReadJSON(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, Catalog{"Hello": "Hallo", "Goodbye": ""}, c)
+	})
+
+	t.Run("po", func(t *testing.T) {
+		path := filepath.Join(dir, "locales", "de.po")
+		require.NoError(t, MergeFile(path, messages[:1]))
+
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Contains(t, string(b), "#: ui/hello.go:11\nmsgid \"Hello\"\nmsgstr \"\"\n")
+
+		require.NoError(t, MergeFile(path, messages))
+		b, err = ioutil.ReadFile(path)
+		require.NoError(t, err)
+		c, err := ReadPO(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Equal(t, Catalog{"Hello": "", "Goodbye": ""}, c)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		require.Error(t, MergeFile(filepath.Join(dir, "de.yaml"), messages))
+	})
+
+	t.Run("invalid catalog", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte("["), 0644))
+		require.Error(t, MergeFile(path, messages))
+	})
+}
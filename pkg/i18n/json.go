@@ -0,0 +1,31 @@
+package i18n
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+// ReadJSON reads a catalog encoded as a JSON object whose fields are the keys
+// and values are the translations.
+func ReadJSON(r io.Reader) (Catalog, error) {
+	c := make(Catalog)
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, errors.New("decoding json catalog failed").Wrap(err)
+	}
+	return c, nil
+}
+
+// WriteJSON writes the given catalog as a JSON object sorted by key, which
+// keeps the diffs of catalog files minimal.
+func WriteJSON(w io.Writer, c Catalog) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(c); err != nil {
+		return errors.New("encoding json catalog failed").Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package i18n
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/errors"
+)
+
+const poHeader = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+`
+
+// ReadPO reads a catalog encoded in the gettext PO format. Entries with a
+// context or plural forms, as well as obsolete entries, are ignored.
+func ReadPO(r io.Reader) (Catalog, error) {
+	c := make(Catalog)
+
+	var (
+		id, str  string
+		field    *string
+		hasID    bool
+		skipping bool
+	)
+
+	flush := func() {
+		if hasID && id != "" && !skipping {
+			c[id] = str
+		}
+		id, str = "", ""
+		field = nil
+		hasID, skipping = false, false
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "#"):
+			if hasID {
+				flush()
+			}
+
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				continue
+			}
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, errors.New("decoding po string failed").
+					Tag("line", n).
+					Wrap(err)
+			}
+			*field += s
+
+		default:
+			keyword := line
+			value := ""
+			if i := strings.IndexByte(line, ' '); i >= 0 {
+				keyword = line[:i]
+				value = strings.TrimSpace(line[i+1:])
+			}
+
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, errors.New("decoding po string failed").
+					Tag("line", n).
+					Wrap(err)
+			}
+
+			switch keyword {
+			case "msgctxt", "msgid_plural":
+				skipping = true
+				field = nil
+
+			case "msgid":
+				if hasID {
+					flush()
+				}
+				hasID = true
+				id = s
+				field = &id
+
+			case "msgstr":
+				str = s
+				field = &str
+
+			default:
+				skipping = true
+				field = nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("reading po catalog failed").Wrap(err)
+	}
+
+	flush()
+	return c, nil
+}
+
+// WritePO writes the given catalog in the gettext PO format. Entries follow the
+// order of the given messages, with their locations as references, and the
+// catalog keys that are not in the messages are written last, sorted by key.
+func WritePO(w io.Writer, c Catalog, messages []Message) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(poHeader)
+
+	written := make(map[string]bool, len(c))
+	writeEntry := func(id string, locations []string) {
+		bw.WriteByte('\n')
+		for _, l := range locations {
+			fmt.Fprintf(bw, "#: %s\n", l)
+		}
+		if strings.Contains(id, "%") {
+			bw.WriteString("#, c-format\n")
+		}
+		fmt.Fprintf(bw, "msgid %s\n", quotePO(id))
+		fmt.Fprintf(bw, "msgstr %s\n", quotePO(c[id]))
+		written[id] = true
+	}
+
+	for _, m := range messages {
+		if _, ok := c[m.ID]; ok && !written[m.ID] {
+			writeEntry(m.ID, m.Locations)
+		}
+	}
+	for _, id := range sortedKeys(c) {
+		if !written[id] {
+			writeEntry(id, nil)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return errors.New("writing po catalog failed").Wrap(err)
+	}
+	return nil
+}
+
+func quotePO(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\t", `\t`,
+		"\r", `\r`,
+	)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,418 @@
+package ui
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// IAppInstallBanner is the interface that describes a banner that invites the
+// user to install the app. The banner is only displayed when the app can be
+// installed.
+type IAppInstallBanner interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IAppInstallBanner
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IAppInstallBanner
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IAppInstallBanner
+
+	// Sets the label. Default is "Install this app on your device.".
+	Label(v string) IAppInstallBanner
+
+	// Sets the install button label. Default is "Install".
+	InstallLabel(v string) IAppInstallBanner
+
+	// Sets the dismiss button label. Default is "Not now". The dismiss button
+	// is hidden when the label is empty.
+	DismissLabel(v string) IAppInstallBanner
+}
+
+// AppInstallBanner creates a banner that shows the app install prompt when its
+// install button is clicked.
+func AppInstallBanner() IAppInstallBanner {
+	return &appInstallBanner{
+		Ilabel:        "Install this app on your device.",
+		IinstallLabel: "Install",
+		IdismissLabel: "Not now",
+	}
+}
+
+type appInstallBanner struct {
+	app.Compo
+
+	Iid           string
+	Iclass        string
+	Istyles       []style
+	Ilabel        string
+	IinstallLabel string
+	IdismissLabel string
+
+	installable bool
+	dismissed   bool
+}
+
+func (b *appInstallBanner) ID(v string) IAppInstallBanner {
+	b.Iid = v
+	return b
+}
+
+func (b *appInstallBanner) Class(v string) IAppInstallBanner {
+	b.Iclass = app.AppendClass(b.Iclass, v)
+	return b
+}
+
+func (b *appInstallBanner) Style(k, v string) IAppInstallBanner {
+	if v == "" {
+		return b
+	}
+	b.Istyles = append(b.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return b
+}
+
+func (b *appInstallBanner) Label(v string) IAppInstallBanner {
+	b.Ilabel = v
+	return b
+}
+
+func (b *appInstallBanner) InstallLabel(v string) IAppInstallBanner {
+	b.IinstallLabel = v
+	return b
+}
+
+func (b *appInstallBanner) DismissLabel(v string) IAppInstallBanner {
+	b.IdismissLabel = v
+	return b
+}
+
+func (b *appInstallBanner) OnMount(ctx app.Context) {
+	b.installable = ctx.IsAppInstallable()
+}
+
+func (b *appInstallBanner) OnAppInstallChange(ctx app.Context) {
+	b.installable = ctx.IsAppInstallable()
+}
+
+func (b *appInstallBanner) Render() app.UI {
+	return renderBanner(bannerProps{
+		id:           b.Iid,
+		class:        b.Iclass,
+		styles:       b.Istyles,
+		visible:      b.installable && !b.dismissed,
+		label:        b.Ilabel,
+		actionLabel:  b.IinstallLabel,
+		onAction:     b.onInstall,
+		dismissLabel: b.IdismissLabel,
+		onDismiss:    b.onDismiss,
+	})
+}
+
+func (b *appInstallBanner) onInstall(ctx app.Context, e app.Event) {
+	ctx.ShowAppInstallPrompt()
+}
+
+func (b *appInstallBanner) onDismiss(ctx app.Context, e app.Event) {
+	b.dismissed = true
+}
+
+// IAppUpdateBanner is the interface that describes a banner that notifies the
+// user that an app update is available. The banner is only displayed when an
+// update has been downloaded in background.
+type IAppUpdateBanner interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IAppUpdateBanner
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IAppUpdateBanner
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IAppUpdateBanner
+
+	// Sets the label. Default is "A new version is available.".
+	Label(v string) IAppUpdateBanner
+
+	// Sets the reload button label. Default is "Reload".
+	ReloadLabel(v string) IAppUpdateBanner
+
+	// Sets the dismiss button label. The dismiss button is hidden when the
+	// label is empty, which is the default.
+	DismissLabel(v string) IAppUpdateBanner
+}
+
+// AppUpdateBanner creates a banner that reloads the app when its reload button
+// is clicked.
+func AppUpdateBanner() IAppUpdateBanner {
+	return &appUpdateBanner{
+		Ilabel:       "A new version is available.",
+		IreloadLabel: "Reload",
+	}
+}
+
+type appUpdateBanner struct {
+	app.Compo
+
+	Iid           string
+	Iclass        string
+	Istyles       []style
+	Ilabel        string
+	IreloadLabel  string
+	IdismissLabel string
+
+	updateAvailable bool
+	dismissed       bool
+}
+
+func (b *appUpdateBanner) ID(v string) IAppUpdateBanner {
+	b.Iid = v
+	return b
+}
+
+func (b *appUpdateBanner) Class(v string) IAppUpdateBanner {
+	b.Iclass = app.AppendClass(b.Iclass, v)
+	return b
+}
+
+func (b *appUpdateBanner) Style(k, v string) IAppUpdateBanner {
+	if v == "" {
+		return b
+	}
+	b.Istyles = append(b.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return b
+}
+
+func (b *appUpdateBanner) Label(v string) IAppUpdateBanner {
+	b.Ilabel = v
+	return b
+}
+
+func (b *appUpdateBanner) ReloadLabel(v string) IAppUpdateBanner {
+	b.IreloadLabel = v
+	return b
+}
+
+func (b *appUpdateBanner) DismissLabel(v string) IAppUpdateBanner {
+	b.IdismissLabel = v
+	return b
+}
+
+func (b *appUpdateBanner) OnMount(ctx app.Context) {
+	b.updateAvailable = ctx.AppUpdateAvailable()
+}
+
+func (b *appUpdateBanner) OnAppUpdate(ctx app.Context) {
+	b.updateAvailable = ctx.AppUpdateAvailable()
+}
+
+func (b *appUpdateBanner) Render() app.UI {
+	return renderBanner(bannerProps{
+		id:           b.Iid,
+		class:        b.Iclass,
+		styles:       b.Istyles,
+		visible:      b.updateAvailable && !b.dismissed,
+		label:        b.Ilabel,
+		actionLabel:  b.IreloadLabel,
+		onAction:     b.onReload,
+		dismissLabel: b.IdismissLabel,
+		onDismiss:    b.onDismiss,
+	})
+}
+
+func (b *appUpdateBanner) onReload(ctx app.Context, e app.Event) {
+	ctx.Reload()
+}
+
+func (b *appUpdateBanner) onDismiss(ctx app.Context, e app.Event) {
+	b.dismissed = true
+}
+
+// IConsentBanner is the interface that describes a banner that asks the user
+// for consent. The banner is only displayed until the user makes a choice.
+type IConsentBanner interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IConsentBanner
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IConsentBanner
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IConsentBanner
+
+	// Sets the label. Default is "This website uses cookies to improve your
+	// experience.".
+	Label(v string) IConsentBanner
+
+	// Sets the consent categories granted when the accept button is clicked.
+	// Default is analytics, marketing and preferences.
+	Categories(v ...string) IConsentBanner
+
+	// Sets the accept button label. Default is "Accept".
+	AcceptLabel(v string) IConsentBanner
+
+	// Sets the reject button label. Default is "Reject". Rejecting grants
+	// only the necessary category.
+	RejectLabel(v string) IConsentBanner
+}
+
+// ConsentBanner creates a banner that records the user consent with
+// Context.SetConsent.
+func ConsentBanner() IConsentBanner {
+	return &consentBanner{
+		Ilabel: "This website uses cookies to improve your experience.",
+		Icategories: []string{
+			app.ConsentAnalytics,
+			app.ConsentMarketing,
+			app.ConsentPreferences,
+		},
+		IacceptLabel: "Accept",
+		IrejectLabel: "Reject",
+	}
+}
+
+type consentBanner struct {
+	app.Compo
+
+	Iid          string
+	Iclass       string
+	Istyles      []style
+	Ilabel       string
+	Icategories  []string
+	IacceptLabel string
+	IrejectLabel string
+
+	mounted bool
+	decided bool
+}
+
+func (b *consentBanner) ID(v string) IConsentBanner {
+	b.Iid = v
+	return b
+}
+
+func (b *consentBanner) Class(v string) IConsentBanner {
+	b.Iclass = app.AppendClass(b.Iclass, v)
+	return b
+}
+
+func (b *consentBanner) Style(k, v string) IConsentBanner {
+	if v == "" {
+		return b
+	}
+	b.Istyles = append(b.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return b
+}
+
+func (b *consentBanner) Label(v string) IConsentBanner {
+	b.Ilabel = v
+	return b
+}
+
+func (b *consentBanner) Categories(v ...string) IConsentBanner {
+	b.Icategories = v
+	return b
+}
+
+func (b *consentBanner) AcceptLabel(v string) IConsentBanner {
+	b.IacceptLabel = v
+	return b
+}
+
+func (b *consentBanner) RejectLabel(v string) IConsentBanner {
+	b.IrejectLabel = v
+	return b
+}
+
+func (b *consentBanner) OnMount(ctx app.Context) {
+	b.mounted = true
+	b.decided = ctx.Consent().Decided()
+	ctx.Handle(app.ConsentAction, b.onConsentChange)
+}
+
+func (b *consentBanner) Render() app.UI {
+	return renderBanner(bannerProps{
+		id:           b.Iid,
+		class:        app.AppendClass("goapp-consent-banner", b.Iclass),
+		styles:       b.Istyles,
+		visible:      b.mounted && !b.decided,
+		label:        b.Ilabel,
+		actionLabel:  b.IacceptLabel,
+		onAction:     b.onAccept,
+		dismissLabel: b.IrejectLabel,
+		onDismiss:    b.onReject,
+	})
+}
+
+func (b *consentBanner) onAccept(ctx app.Context, e app.Event) {
+	ctx.SetConsent(b.Icategories...)
+	b.decided = true
+}
+
+func (b *consentBanner) onReject(ctx app.Context, e app.Event) {
+	ctx.SetConsent()
+	b.decided = true
+}
+
+func (b *consentBanner) onConsentChange(ctx app.Context, a app.Action) {
+	b.decided = true
+}
+
+type bannerProps struct {
+	id           string
+	class        string
+	styles       []style
+	visible      bool
+	label        string
+	actionLabel  string
+	onAction     app.EventHandler
+	dismissLabel string
+	onDismiss    app.EventHandler
+}
+
+func renderBanner(p bannerProps) app.UI {
+	banner := app.Aside().
+		ID(p.id).
+		Class(app.AppendClass("goapp-banner", p.class)).
+		Style("display", "flex").
+		Style("align-items", "center").
+		Body(
+			app.Div().
+				Class("goapp-banner-label").
+				Style("flex-grow", "1").
+				Text(p.label),
+			app.Button().
+				Class("goapp-banner-action").
+				Style("margin-left", pxToString(DefaultIconSpace*2)).
+				OnClick(p.onAction).
+				Text(p.actionLabel),
+			app.If(p.dismissLabel != "",
+				app.Button().
+					Class("goapp-banner-dismiss").
+					Style("margin-left", pxToString(DefaultIconSpace)).
+					OnClick(p.onDismiss).
+					Text(p.dismissLabel),
+			),
+		)
+
+	for _, s := range p.styles {
+		banner.Style(s.key, s.value)
+	}
+
+	if !p.visible {
+		banner.Style("display", "none")
+	}
+	return banner
+}
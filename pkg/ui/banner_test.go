@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBannerPreRender(t *testing.T) {
+	utests := []struct {
+		scenario string
+		banner   app.UI
+		label    string
+	}{
+		{
+			scenario: "app install banner",
+			banner:   AppInstallBanner().Label("Install me"),
+			label:    "Install me",
+		},
+		{
+			scenario: "app update banner",
+			banner:   AppUpdateBanner().DismissLabel("Later"),
+			label:    "A new version is available.",
+		},
+		{
+			scenario: "consent banner",
+			banner:   ConsentBanner().Label("We use cookies"),
+			label:    "We use cookies",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			d := app.NewServerTester(u.banner)
+			defer d.Close()
+			d.PreRender()
+
+			html := app.HTMLString(u.banner)
+			require.Contains(t, html, u.label)
+			require.Contains(t, html, "display:none")
+		})
+	}
+}
+
+func TestBannerClient(t *testing.T) {
+	banners := []app.UI{
+		AppInstallBanner(),
+		AppUpdateBanner(),
+	}
+
+	for _, b := range banners {
+		d := app.NewClientTester(b)
+		d.AppInstallChange()
+		d.AppUpdate()
+		d.Consume()
+		require.Contains(t, app.HTMLString(b), "display:none")
+		d.Close()
+	}
+}
+
+func TestConsentBanner(t *testing.T) {
+	b := ConsentBanner()
+	d := app.NewClientTester(b)
+	defer d.Close()
+	require.NotContains(t, app.HTMLString(b), "display:none")
+
+	b.(*consentBanner).onReject(d.Context(), app.Event{})
+	d.Consume()
+	require.Contains(t, app.HTMLString(b), "display:none")
+	require.True(t, d.Context().Consent().Decided())
+	require.False(t, app.ConsentGranted(app.ConsentAnalytics))
+	require.True(t, app.ConsentGranted(app.ConsentNecessary))
+
+	b.(*consentBanner).onAccept(d.Context(), app.Event{})
+	require.True(t, app.ConsentGranted(app.ConsentAnalytics))
+	d.Context().SetConsent()
+}
@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// IPullToRefresh is the interface that describes a scrollable container that
+// triggers a refresh when it is pulled down from its top.
+type IPullToRefresh interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IPullToRefresh
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IPullToRefresh
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IPullToRefresh
+
+	// Sets the distance in px the container must be pulled to trigger a
+	// refresh. Default is 64px.
+	Threshold(px int) IPullToRefresh
+
+	// Sets the label displayed while pulling. Default is "Pull to refresh".
+	Label(v string) IPullToRefresh
+
+	// Sets the label displayed when the threshold is reached. Default is
+	// "Release to refresh".
+	ReleaseLabel(v string) IPullToRefresh
+
+	// Reports whether a refresh is in progress. A loader is displayed above
+	// the content while refreshing.
+	Refreshing(v bool) IPullToRefresh
+
+	// Sets the function called when the container is pulled beyond the
+	// threshold and released.
+	OnRefresh(h func(app.Context)) IPullToRefresh
+
+	// Sets the content.
+	Content(elems ...app.UI) IPullToRefresh
+}
+
+// PullToRefresh creates a scrollable container that triggers a refresh when
+// it is pulled down from its top.
+func PullToRefresh() IPullToRefresh {
+	return &pullToRefresh{
+		Ithreshold:    64,
+		Ilabel:        "Pull to refresh",
+		IreleaseLabel: "Release to refresh",
+	}
+}
+
+type pullToRefresh struct {
+	app.Compo
+
+	Iid           string
+	Iclass        string
+	Istyles       []style
+	Ithreshold    int
+	Ilabel        string
+	IreleaseLabel string
+	Irefreshing   bool
+	IonRefresh    func(app.Context)
+	Icontent      []app.UI
+
+	gestures app.Gestures
+	scrolled bool
+	pulling  bool
+	pull     float64
+}
+
+func (r *pullToRefresh) ID(v string) IPullToRefresh {
+	r.Iid = v
+	return r
+}
+
+func (r *pullToRefresh) Class(v string) IPullToRefresh {
+	r.Iclass = app.AppendClass(r.Iclass, v)
+	return r
+}
+
+func (r *pullToRefresh) Style(k, v string) IPullToRefresh {
+	if v == "" {
+		return r
+	}
+	r.Istyles = append(r.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return r
+}
+
+func (r *pullToRefresh) Threshold(px int) IPullToRefresh {
+	if px > 0 {
+		r.Ithreshold = px
+	}
+	return r
+}
+
+func (r *pullToRefresh) Label(v string) IPullToRefresh {
+	r.Ilabel = v
+	return r
+}
+
+func (r *pullToRefresh) ReleaseLabel(v string) IPullToRefresh {
+	r.IreleaseLabel = v
+	return r
+}
+
+func (r *pullToRefresh) Refreshing(v bool) IPullToRefresh {
+	r.Irefreshing = v
+	return r
+}
+
+func (r *pullToRefresh) OnRefresh(h func(app.Context)) IPullToRefresh {
+	r.IonRefresh = h
+	return r
+}
+
+func (r *pullToRefresh) Content(elems ...app.UI) IPullToRefresh {
+	r.Icontent = app.FilterUIElems(elems...)
+	return r
+}
+
+func (r *pullToRefresh) Render() app.UI {
+	// Pulling down is handled by the container only when it is scrolled to
+	// its top. The browser keeps handling the other scrolls.
+	r.gestures.TouchAction = "pan-x pan-down"
+	if r.scrolled {
+		r.gestures.TouchAction = "auto"
+	}
+	r.gestures.OnPan = r.onPan
+
+	height := 0
+	switch {
+	case r.Irefreshing:
+		height = r.Ithreshold
+
+	case r.pulling:
+		height = int(r.pull)
+	}
+
+	label := r.Ilabel
+	if r.pull >= float64(r.Ithreshold) {
+		label = r.IreleaseLabel
+	}
+
+	indicator := app.Div().
+		Class("goapp-pull-to-refresh-indicator").
+		Style("display", "flex").
+		Style("align-items", "center").
+		Style("justify-content", "center").
+		Style("overflow", "hidden").
+		Style("height", pxToString(height)).
+		Body(
+			app.If(r.Irefreshing,
+				Loader().
+					Loading(true).
+					Size(DefaultIconSize).
+					Label(""),
+			).Else(
+				app.Text(label),
+			),
+		)
+	if !r.pulling {
+		indicator.Style("transition", "height 0.2s")
+	}
+
+	container := app.Div().
+		ID(r.Iid).
+		Class(app.AppendClass("goapp-pull-to-refresh", r.Iclass)).
+		Style("overflow-y", "auto").
+		OnScroll(r.onScroll).
+		Body(
+			indicator,
+			app.Div().
+				Class("goapp-pull-to-refresh-content").
+				Body(r.Icontent...),
+		)
+
+	for _, s := range r.Istyles {
+		container.Style(s.key, s.value)
+	}
+
+	return r.gestures.Attach(container)
+}
+
+func (r *pullToRefresh) onScroll(ctx app.Context, e app.Event) {
+	r.scrolled = ctx.JSSrc().Get("scrollTop").Float() > 0
+}
+
+func (r *pullToRefresh) onPan(ctx app.Context, g app.Gesture) {
+	switch g.Phase {
+	case app.GestureStart:
+		r.pulling = !r.scrolled && !r.Irefreshing && g.Direction == "down"
+		r.pull = 0
+
+	case app.GestureMove:
+		if !r.pulling {
+			return
+		}
+		r.pull = g.DeltaY / 2
+		if r.pull < 0 {
+			r.pull = 0
+		}
+		if limit := float64(r.Ithreshold) * 1.5; r.pull > limit {
+			r.pull = limit
+		}
+
+	case app.GestureEnd:
+		refresh := r.pulling && r.pull >= float64(r.Ithreshold)
+		r.pulling = false
+		r.pull = 0
+		if refresh && r.IonRefresh != nil {
+			r.IonRefresh(ctx)
+		}
+	}
+}
+
+// ISwipeBack is the interface that describes a container that navigates to
+// the previous page when it is swiped from its left edge to the right.
+type ISwipeBack interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) ISwipeBack
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) ISwipeBack
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) ISwipeBack
+
+	// Sets the width in px of the left edge area where a swipe must start.
+	// Default is 32px.
+	EdgeWidth(px int) ISwipeBack
+
+	// Sets the distance in px the content must be dragged to navigate back.
+	// Default is 80px.
+	Threshold(px int) ISwipeBack
+
+	// Sets the content.
+	Content(elems ...app.UI) ISwipeBack
+}
+
+// SwipeBack creates a container that navigates to the previous page when it
+// is swiped from its left edge to the right. The content follows the swipe
+// and the navigation is animated when view transitions are enabled with
+// app.SetViewTransitions.
+func SwipeBack() ISwipeBack {
+	return &swipeBack{
+		IedgeWidth: 32,
+		Ithreshold: 80,
+	}
+}
+
+type swipeBack struct {
+	app.Compo
+
+	Iid        string
+	Iclass     string
+	Istyles    []style
+	IedgeWidth int
+	Ithreshold int
+	Icontent   []app.UI
+
+	gestures app.Gestures
+	dragging bool
+	offset   float64
+}
+
+func (b *swipeBack) ID(v string) ISwipeBack {
+	b.Iid = v
+	return b
+}
+
+func (b *swipeBack) Class(v string) ISwipeBack {
+	b.Iclass = app.AppendClass(b.Iclass, v)
+	return b
+}
+
+func (b *swipeBack) Style(k, v string) ISwipeBack {
+	if v == "" {
+		return b
+	}
+	b.Istyles = append(b.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return b
+}
+
+func (b *swipeBack) EdgeWidth(px int) ISwipeBack {
+	if px > 0 {
+		b.IedgeWidth = px
+	}
+	return b
+}
+
+func (b *swipeBack) Threshold(px int) ISwipeBack {
+	if px > 0 {
+		b.Ithreshold = px
+	}
+	return b
+}
+
+func (b *swipeBack) Content(elems ...app.UI) ISwipeBack {
+	b.Icontent = app.FilterUIElems(elems...)
+	return b
+}
+
+func (b *swipeBack) Render() app.UI {
+	b.gestures.TouchAction = "pan-y"
+	b.gestures.OnPan = b.onPan
+
+	content := app.Div().
+		Class("goapp-swipe-back-content").
+		Style("transform", "translateX("+strconv.Itoa(int(b.offset))+"px)").
+		Body(b.Icontent...)
+	if !b.dragging {
+		content.Style("transition", "transform 0.2s")
+	}
+
+	container := app.Div().
+		ID(b.Iid).
+		Class(app.AppendClass("goapp-swipe-back", b.Iclass)).
+		Style("overflow-x", "hidden").
+		Body(content)
+
+	for _, s := range b.Istyles {
+		container.Style(s.key, s.value)
+	}
+
+	return b.gestures.Attach(container)
+}
+
+func (b *swipeBack) onPan(ctx app.Context, g app.Gesture) {
+	switch g.Phase {
+	case app.GestureStart:
+		startX := g.X - g.DeltaX
+		b.dragging = startX <= float64(b.IedgeWidth) && g.Direction == "right"
+
+	case app.GestureMove:
+		if b.dragging && g.DeltaX > 0 {
+			b.offset = g.DeltaX
+		}
+
+	case app.GestureEnd:
+		back := b.dragging && g.DeltaX >= float64(b.Ithreshold)
+		b.dragging = false
+		b.offset = 0
+		if back {
+			ctx.NavigateBack()
+		}
+	}
+}
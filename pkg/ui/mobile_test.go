@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMobilePreRender(t *testing.T) {
+	utests := []struct {
+		scenario string
+		compo    app.UI
+		class    string
+	}{
+		{
+			scenario: "pull to refresh",
+			compo:    PullToRefresh().Content(app.Text("hello")),
+			class:    "goapp-pull-to-refresh",
+		},
+		{
+			scenario: "swipe back",
+			compo:    SwipeBack().Content(app.Text("hello")),
+			class:    "goapp-swipe-back",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			d := app.NewServerTester(u.compo)
+			defer d.Close()
+			d.PreRender()
+
+			html := app.HTMLString(u.compo)
+			require.Contains(t, html, u.class)
+			require.Contains(t, html, "hello")
+		})
+	}
+}
+
+func TestPullToRefreshPan(t *testing.T) {
+	refreshed := false
+	r := PullToRefresh().
+		Threshold(50).
+		OnRefresh(func(app.Context) { refreshed = true }).(*pullToRefresh)
+
+	r.onPan(nil, app.Gesture{Phase: app.GestureStart, Direction: "down", DeltaY: 12})
+	require.True(t, r.pulling)
+
+	r.onPan(nil, app.Gesture{Phase: app.GestureMove, Direction: "down", DeltaY: 60})
+	require.Equal(t, float64(30), r.pull)
+
+	r.onPan(nil, app.Gesture{Phase: app.GestureMove, Direction: "down", DeltaY: 400})
+	require.Equal(t, float64(75), r.pull)
+
+	r.onPan(nil, app.Gesture{Phase: app.GestureEnd, Direction: "down", DeltaY: 400})
+	require.True(t, refreshed)
+	require.False(t, r.pulling)
+	require.Zero(t, r.pull)
+
+	refreshed = false
+	r.scrolled = true
+	r.onPan(nil, app.Gesture{Phase: app.GestureStart, Direction: "down", DeltaY: 12})
+	r.onPan(nil, app.Gesture{Phase: app.GestureMove, Direction: "down", DeltaY: 400})
+	r.onPan(nil, app.Gesture{Phase: app.GestureEnd, Direction: "down", DeltaY: 400})
+	require.False(t, refreshed)
+}
+
+func TestSwipeBackPan(t *testing.T) {
+	b := SwipeBack().EdgeWidth(20).(*swipeBack)
+
+	b.onPan(nil, app.Gesture{Phase: app.GestureStart, Direction: "right", X: 52, DeltaX: 12})
+	require.False(t, b.dragging)
+
+	b.onPan(nil, app.Gesture{Phase: app.GestureStart, Direction: "right", X: 22, DeltaX: 12})
+	require.True(t, b.dragging)
+
+	b.onPan(nil, app.Gesture{Phase: app.GestureMove, Direction: "right", X: 50, DeltaX: 40})
+	require.Equal(t, float64(40), b.offset)
+
+	b.onPan(nil, app.Gesture{Phase: app.GestureEnd, Direction: "right", X: 50, DeltaX: 40})
+	require.False(t, b.dragging)
+	require.Zero(t, b.offset)
+}
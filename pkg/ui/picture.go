@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// IPicture is the interface that describes a responsive image that is loaded
+// from the Handler image endpoint.
+type IPicture interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IPicture
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IPicture
+
+	// Sets the path of the static image to display. eg: "/web/photo.jpg".
+	Src(v string) IPicture
+
+	// Sets the alternate text.
+	Alt(v string) IPicture
+
+	// Sets the widths in px that the image is resized to.
+	Widths(v ...int) IPicture
+
+	// Sets the image sizes for different page layouts. eg: "(max-width:
+	// 480px) 100vw, 50vw".
+	Sizes(v string) IPicture
+
+	// Adds an art-direction source that displays the given static image when
+	// the given media query matches. Sources are resized to the picture
+	// widths. Multiple sources can be defined by successive calls.
+	Source(media, src string) IPicture
+
+	// Sets the intrinsic aspect ratio of the image in order to reserve its
+	// space and prevent layout shifts while it loads.
+	AspectRatio(width, height int) IPicture
+
+	// Reports whether the image is loaded only when it is near the viewport.
+	// Default is true.
+	Lazy(v bool) IPicture
+
+	// Reports whether a blurred thumbnail is displayed while the image loads.
+	BlurUp(v bool) IPicture
+}
+
+// Picture creates a responsive image.
+func Picture() IPicture {
+	return &picture{
+		Ilazy: true,
+	}
+}
+
+type picture struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Isrc     string
+	Ialt     string
+	Iwidths  []int
+	Isizes   string
+	Isources []pictureSource
+	Iwidth   int
+	Iheight  int
+	Ilazy    bool
+	IblurUp  bool
+
+	loaded bool
+}
+
+type pictureSource struct {
+	media string
+	src   string
+}
+
+func (p *picture) ID(v string) IPicture {
+	p.Iid = v
+	return p
+}
+
+func (p *picture) Class(v string) IPicture {
+	p.Iclass = app.AppendClass(p.Iclass, v)
+	return p
+}
+
+func (p *picture) Src(v string) IPicture {
+	p.Isrc = v
+	return p
+}
+
+func (p *picture) Alt(v string) IPicture {
+	p.Ialt = v
+	return p
+}
+
+func (p *picture) Widths(v ...int) IPicture {
+	p.Iwidths = v
+	return p
+}
+
+func (p *picture) Sizes(v string) IPicture {
+	p.Isizes = v
+	return p
+}
+
+func (p *picture) Source(media, src string) IPicture {
+	p.Isources = append(p.Isources, pictureSource{
+		media: media,
+		src:   src,
+	})
+	return p
+}
+
+func (p *picture) AspectRatio(width, height int) IPicture {
+	p.Iwidth = width
+	p.Iheight = height
+	return p
+}
+
+func (p *picture) Lazy(v bool) IPicture {
+	p.Ilazy = v
+	return p
+}
+
+func (p *picture) BlurUp(v bool) IPicture {
+	p.IblurUp = v
+	return p
+}
+
+func (p *picture) Render() app.UI {
+	img := app.Img().
+		Src(p.Isrc).
+		Alt(p.Ialt).
+		Decoding("async").
+		OnLoad(p.onLoad)
+
+	if len(p.Iwidths) != 0 {
+		img.Responsive(p.Iwidths...)
+	}
+	if p.Isizes != "" {
+		img.Sizes(p.Isizes)
+	}
+	if p.Ilazy {
+		img.Loading("lazy")
+	}
+
+	if p.Iwidth > 0 && p.Iheight > 0 {
+		img.Width(p.Iwidth).
+			Height(p.Iheight).
+			Style("width", "100%").
+			Style("height", "auto").
+			Style("aspect-ratio", fmt.Sprintf("%v / %v", p.Iwidth, p.Iheight))
+	}
+
+	if p.IblurUp && !p.loaded {
+		img.Style("background-image", fmt.Sprintf("url('%s')", app.ImageThumbnailURL(p.Isrc))).
+			Style("background-size", "cover").
+			Style("background-position", "center").
+			Style("filter", "blur(12px)")
+	}
+	if p.IblurUp {
+		img.Style("transition", "filter 300ms")
+	}
+
+	return app.Picture().
+		ID(p.Iid).
+		Class(p.Iclass).
+		Body(
+			app.Range(p.Isources).Slice(func(i int) app.UI {
+				s := p.Isources[i]
+				srcset := s.src
+				if len(p.Iwidths) != 0 {
+					srcset = app.ImageSrcSet(s.src, p.Iwidths...)
+				}
+
+				source := app.Source().
+					Media(s.media).
+					SrcSet(srcset)
+				if p.Isizes != "" {
+					source.Sizes(p.Isizes)
+				}
+				return source
+			}),
+			img,
+		)
+}
+
+func (p *picture) onLoad(ctx app.Context, e app.Event) {
+	p.loaded = true
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPicturePreRender(t *testing.T) {
+	utests := []struct {
+		scenario string
+		picture  app.UI
+		contains []string
+	}{
+		{
+			scenario: "lazy picture",
+			picture:  Picture().Src("/web/photo.jpg"),
+			contains: []string{
+				`src="/web/photo.jpg"`,
+				`loading="lazy"`,
+			},
+		},
+		{
+			scenario: "responsive picture",
+			picture: Picture().
+				Src("/web/photo.jpg").
+				Widths(320, 640).
+				Sizes("50vw"),
+			contains: []string{
+				`srcset="` + app.ImageSrcSet("/web/photo.jpg", 320, 640) + `"`,
+				`sizes="50vw"`,
+			},
+		},
+		{
+			scenario: "picture with art-direction source",
+			picture: Picture().
+				Src("/web/photo.jpg").
+				Widths(320).
+				Source("(max-width: 480px)", "/web/photo-mobile.jpg"),
+			contains: []string{
+				`media="(max-width: 480px)"`,
+				`srcset="` + app.ImageSrcSet("/web/photo-mobile.jpg", 320) + `"`,
+			},
+		},
+		{
+			scenario: "picture with aspect ratio and blur-up",
+			picture: Picture().
+				Src("/web/photo.jpg").
+				AspectRatio(16, 9).
+				BlurUp(true),
+			contains: []string{
+				`width="16"`,
+				`height="9"`,
+				"aspect-ratio:16 / 9",
+				app.ImageThumbnailURL("/web/photo.jpg"),
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			d := app.NewServerTester(u.picture)
+			defer d.Close()
+			d.PreRender()
+
+			html := app.HTMLString(u.picture)
+			for _, s := range u.contains {
+				require.Contains(t, html, s)
+			}
+		})
+	}
+}
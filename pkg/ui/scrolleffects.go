@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// IShrinkingHeader is the interface that describes a sticky header that
+// shrinks as the page is scrolled.
+type IShrinkingHeader interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IShrinkingHeader
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IShrinkingHeader
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IShrinkingHeader
+
+	// Sets the height in px when the page is not scrolled. Default is 90px.
+	Height(px int) IShrinkingHeader
+
+	// Sets the height in px when the page is scrolled. Default is 56px.
+	ShrunkHeight(px int) IShrinkingHeader
+
+	// Sets the content.
+	Content(elems ...app.UI) IShrinkingHeader
+}
+
+// ShrinkingHeader creates a sticky header that shrinks as the page is
+// scrolled. The "goapp-shrinking-header-shrunk" class is set once the header
+// is fully shrunk.
+func ShrinkingHeader() IShrinkingHeader {
+	return &shrinkingHeader{
+		Iheight:       defaultHeaderHeight,
+		IshrunkHeight: 56,
+	}
+}
+
+type shrinkingHeader struct {
+	app.Compo
+
+	Iid           string
+	Iclass        string
+	Istyles       []style
+	Iheight       int
+	IshrunkHeight int
+	Icontent      []app.UI
+
+	scroll app.ScrollPosition
+}
+
+func (h *shrinkingHeader) ID(v string) IShrinkingHeader {
+	h.Iid = v
+	return h
+}
+
+func (h *shrinkingHeader) Class(v string) IShrinkingHeader {
+	h.Iclass = app.AppendClass(h.Iclass, v)
+	return h
+}
+
+func (h *shrinkingHeader) Style(k, v string) IShrinkingHeader {
+	if v == "" {
+		return h
+	}
+	h.Istyles = append(h.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return h
+}
+
+func (h *shrinkingHeader) Height(px int) IShrinkingHeader {
+	if px > 0 {
+		h.Iheight = px
+	}
+	return h
+}
+
+func (h *shrinkingHeader) ShrunkHeight(px int) IShrinkingHeader {
+	if px > 0 {
+		h.IshrunkHeight = px
+	}
+	return h
+}
+
+func (h *shrinkingHeader) Content(elems ...app.UI) IShrinkingHeader {
+	h.Icontent = app.FilterUIElems(elems...)
+	return h
+}
+
+func (h *shrinkingHeader) OnMount(ctx app.Context) {
+	ctx.ObserveScroll().Value(&h.scroll)
+}
+
+func (h *shrinkingHeader) Render() app.UI {
+	height := h.height()
+
+	class := app.AppendClass("goapp-shrinking-header", h.Iclass)
+	if height == h.IshrunkHeight {
+		class = app.AppendClass(class, "goapp-shrinking-header-shrunk")
+	}
+
+	header := app.Header().
+		ID(h.Iid).
+		Class(class).
+		Style("position", "sticky").
+		Style("top", "0").
+		Style("z-index", "1").
+		Style("box-sizing", "border-box").
+		Style("overflow", "hidden").
+		Style("height", pxToString(height)).
+		Body(h.Icontent...)
+
+	for _, s := range h.Istyles {
+		header.Style(s.key, s.value)
+	}
+	return header
+}
+
+func (h *shrinkingHeader) height() int {
+	shrink := h.Iheight - h.IshrunkHeight
+	if shrink <= 0 {
+		return h.Iheight
+	}
+
+	scrolled := int(h.scroll.Y)
+	if scrolled > shrink {
+		scrolled = shrink
+	}
+	if scrolled < 0 {
+		scrolled = 0
+	}
+	return h.Iheight - scrolled
+}
+
+// IScrollProgress is the interface that describes a bar that indicates how far
+// the page is scrolled.
+type IScrollProgress interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IScrollProgress
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IScrollProgress
+
+	// Sets the style. Multiple styles can be defined by successive calls.
+	Style(k, v string) IScrollProgress
+
+	// Sets the height in px. Default is 3px.
+	Height(px int) IScrollProgress
+
+	// Sets the color. Default is currentColor.
+	Color(v string) IScrollProgress
+}
+
+// ScrollProgress creates a bar fixed at the top of the page that indicates how
+// far the page is scrolled.
+func ScrollProgress() IScrollProgress {
+	return &scrollProgress{
+		Iheight: 3,
+		Icolor:  "currentColor",
+	}
+}
+
+type scrollProgress struct {
+	app.Compo
+
+	Iid     string
+	Iclass  string
+	Istyles []style
+	Iheight int
+	Icolor  string
+
+	scroll app.ScrollPosition
+}
+
+func (p *scrollProgress) ID(v string) IScrollProgress {
+	p.Iid = v
+	return p
+}
+
+func (p *scrollProgress) Class(v string) IScrollProgress {
+	p.Iclass = app.AppendClass(p.Iclass, v)
+	return p
+}
+
+func (p *scrollProgress) Style(k, v string) IScrollProgress {
+	if v == "" {
+		return p
+	}
+	p.Istyles = append(p.Istyles, style{
+		key:   k,
+		value: v,
+	})
+	return p
+}
+
+func (p *scrollProgress) Height(px int) IScrollProgress {
+	if px > 0 {
+		p.Iheight = px
+	}
+	return p
+}
+
+func (p *scrollProgress) Color(v string) IScrollProgress {
+	if v != "" {
+		p.Icolor = v
+	}
+	return p
+}
+
+func (p *scrollProgress) OnMount(ctx app.Context) {
+	ctx.ObserveScroll().Value(&p.scroll)
+}
+
+func (p *scrollProgress) Render() app.UI {
+	bar := app.Div().
+		ID(p.Iid).
+		Class(app.AppendClass("goapp-scroll-progress", p.Iclass)).
+		Attr("role", "progressbar").
+		Aria("valuemin", 0).
+		Aria("valuemax", 100).
+		Aria("valuenow", int(p.scroll.Progress()*100)).
+		Style("position", "fixed").
+		Style("top", "0").
+		Style("left", "0").
+		Style("z-index", "2").
+		Style("height", pxToString(p.Iheight)).
+		Style("background", p.Icolor).
+		Style("width", strconv.FormatFloat(p.scroll.Progress()*100, 'f', 2, 64)+"%")
+
+	for _, s := range p.Istyles {
+		bar.Style(s.key, s.value)
+	}
+	return bar
+}
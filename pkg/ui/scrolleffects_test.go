@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShrinkingHeaderHeight(t *testing.T) {
+	h := ShrinkingHeader().Height(100).ShrunkHeight(60).(*shrinkingHeader)
+	require.Equal(t, 100, h.height())
+
+	h.scroll.Y = 25
+	require.Equal(t, 75, h.height())
+
+	h.scroll.Y = 400
+	require.Equal(t, 60, h.height())
+}
+
+func TestScrollEffectsClient(t *testing.T) {
+	header := ShrinkingHeader().Content(app.Text("title"))
+	progress := ScrollProgress()
+
+	d := app.NewClientTester(app.Div().Body(header, progress))
+	defer d.Close()
+	d.Consume()
+
+	d.SetState(app.ScrollState, app.ScrollPosition{Y: 500, MaxY: 1000})
+	d.Consume()
+
+	html := app.HTMLString(header)
+	require.Contains(t, html, "goapp-shrinking-header-shrunk")
+	require.Contains(t, html, "height:56px")
+	require.Contains(t, app.HTMLString(progress), "width:50.00%")
+}